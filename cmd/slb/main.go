@@ -6,9 +6,13 @@ import (
 	"os"
 
 	"github.com/Dicklesworthstone/slb/internal/cli"
+	"github.com/Dicklesworthstone/slb/internal/sandbox"
 )
 
 func main() {
+	// Sandboxed commands run through a re-exec of this binary.
+	sandbox.MaybeRunHelper()
+
 	if err := cli.Execute(); err != nil {
 		os.Exit(1)
 	}
@@ -0,0 +1,82 @@
+// Package cli implements the abort command.
+package cli
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/Dicklesworthstone/slb/internal/core"
+	"github.com/Dicklesworthstone/slb/internal/daemon"
+	"github.com/Dicklesworthstone/slb/internal/db"
+	"github.com/Dicklesworthstone/slb/internal/output"
+	"github.com/spf13/cobra"
+)
+
+var (
+	flagAbortReason     string
+	flagAbortSessionKey string
+)
+
+func init() {
+	abortCmd.Flags().StringVarP(&flagAbortReason, "reason", "r", "", "why the execution is being aborted")
+	abortCmd.Flags().StringVarP(&flagAbortSessionKey, "session-key", "k", "", "session HMAC key (required)")
+	rootCmd.AddCommand(abortCmd)
+}
+
+var abortCmd = &cobra.Command{
+	Use:   "abort <request-id>",
+	Short: "Abort an executing request",
+	Long: `Stop an approved command while it is executing.
+
+Any reviewer who approved the request can abort it. The slb process running
+the command notices the abort within a second, terminates the command's whole
+process group (SIGTERM, then SIGKILL after a grace period) and marks the
+request execution_failed, recording who aborted it and why.
+
+Use --session-id/-s to specify your session if not using environment, and
+--session-key/-k for its key.
+
+Examples:
+  slb abort abc123 -s $SESSION_ID -k $SESSION_KEY
+  slb abort abc123 -s $SESSION_ID -k $SESSION_KEY --reason "targets the production cluster"`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		requestID := args[0]
+
+		if flagSessionID == "" {
+			return fmt.Errorf("--session-id is required to abort a request")
+		}
+		if flagAbortSessionKey == "" {
+			return fmt.Errorf("--session-key is required to abort a request")
+		}
+
+		dbConn, err := db.Open(GetDB())
+		if err != nil {
+			return fmt.Errorf("opening database: %w", err)
+		}
+		defer dbConn.Close()
+
+		request, abort, err := core.AbortExecution(dbConn, core.AbortOptions{
+			RequestID:  requestID,
+			SessionID:  flagSessionID,
+			SessionKey: flagAbortSessionKey,
+			Reason:     flagAbortReason,
+		})
+		if err != nil {
+			return fmt.Errorf("cannot abort request: %w", err)
+		}
+		_ = daemon.NewEventNotifier(daemon.DefaultSocketPath()).NotifyExecutionAborted(request, abort)
+
+		out := output.New(output.Format(GetOutput()))
+		resp := map[string]any{
+			"request_id":   requestID,
+			"status":       "abort_requested",
+			"aborted_by":   abort.Agent,
+			"requested_at": abort.CreatedAt.Format(time.RFC3339),
+		}
+		if abort.Reason != "" {
+			resp["reason"] = abort.Reason
+		}
+		return out.Write(resp)
+	},
+}
@@ -0,0 +1,106 @@
+package cli
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/Dicklesworthstone/slb/internal/db"
+	"github.com/Dicklesworthstone/slb/internal/testutil"
+	"github.com/spf13/cobra"
+)
+
+func newTestAbortCmd(dbPath string) *cobra.Command {
+	root := &cobra.Command{
+		Use:           "slb",
+		SilenceUsage:  true,
+		SilenceErrors: true,
+	}
+
+	root.PersistentFlags().StringVar(&flagDB, "db", dbPath, "database path")
+	root.PersistentFlags().StringVarP(&flagOutput, "output", "o", "text", "output format")
+	root.PersistentFlags().BoolVarP(&flagJSON, "json", "j", false, "json output")
+	root.PersistentFlags().StringVarP(&flagSessionID, "session-id", "s", "", "session ID")
+
+	root.AddCommand(abortCmd)
+
+	return root
+}
+
+func resetAbortFlags() {
+	flagDB = ""
+	flagOutput = "text"
+	flagJSON = false
+	flagSessionID = ""
+	flagAbortReason = ""
+	flagAbortSessionKey = ""
+}
+
+func TestAbortCommand_RequiresSessionID(t *testing.T) {
+	h := testutil.NewHarness(t)
+	resetAbortFlags()
+
+	_, _, err := executeCommand(newTestAbortCmd(h.DBPath), "abort", "some-request-id")
+	if err == nil || !strings.Contains(err.Error(), "--session-id is required") {
+		t.Fatalf("expected --session-id error, got %v", err)
+	}
+
+	resetAbortFlags()
+	_, _, err = executeCommand(newTestAbortCmd(h.DBPath), "abort", "some-request-id", "-s", "some-session")
+	if err == nil || !strings.Contains(err.Error(), "--session-key is required") {
+		t.Fatalf("expected --session-key error, got %v", err)
+	}
+}
+
+func TestAbortCommand_ApproverAbortsExecutingRequest(t *testing.T) {
+	h := testutil.NewHarness(t)
+	resetAbortFlags()
+
+	requestor := testutil.MakeSession(t, h.DB, testutil.WithProject(h.ProjectDir), testutil.WithAgent("Requestor"))
+	reviewer := testutil.MakeSession(t, h.DB, testutil.WithProject(h.ProjectDir), testutil.WithAgent("Reviewer"))
+	req := testutil.MakeRequest(t, h.DB, requestor,
+		testutil.WithCommand("make deploy", h.ProjectDir, true),
+		testutil.WithStatus(db.StatusExecuting),
+	)
+	if err := h.DB.CreateReview(&db.Review{
+		RequestID: req.ID, ReviewerSessionID: reviewer.ID, ReviewerAgent: reviewer.AgentName,
+		ReviewerModel: reviewer.Model, Decision: db.DecisionApprove, Signature: "sig",
+	}); err != nil {
+		t.Fatalf("CreateReview: %v", err)
+	}
+
+	// The requestor did not approve the request.
+	_, _, err := executeCommand(newTestAbortCmd(h.DBPath), "abort", req.ID, "-s", requestor.ID, "-k", requestor.SessionKey)
+	if err == nil || !strings.Contains(err.Error(), "only an approver") {
+		t.Fatalf("expected approver error, got %v", err)
+	}
+
+	// Knowing the approver's session ID is not enough.
+	resetAbortFlags()
+	_, _, err = executeCommand(newTestAbortCmd(h.DBPath), "abort", req.ID, "-s", reviewer.ID, "-k", requestor.SessionKey)
+	if err == nil || !strings.Contains(err.Error(), "session key does not match") {
+		t.Fatalf("expected key mismatch error, got %v", err)
+	}
+
+	resetAbortFlags()
+	stdout, err := executeCommandCapture(t, newTestAbortCmd(h.DBPath), "abort", req.ID,
+		"-s", reviewer.ID, "-k", reviewer.SessionKey, "--reason", "wrong cluster", "-j")
+	if err != nil {
+		t.Fatalf("abort: %v", err)
+	}
+	var result map[string]any
+	if err := json.Unmarshal([]byte(stdout), &result); err != nil {
+		t.Fatalf("failed to parse JSON: %v\nstdout: %s", err, stdout)
+	}
+	if result["status"] != "abort_requested" || result["aborted_by"] != "Reviewer" || result["reason"] != "wrong cluster" {
+		t.Errorf("unexpected result: %v", result)
+	}
+
+	abort, err := h.DB.GetExecutionAbort(req.ID)
+	if err != nil {
+		t.Fatalf("GetExecutionAbort: %v", err)
+	}
+	if abort.SessionID != reviewer.ID || abort.Reason != "wrong cluster" {
+		t.Errorf("abort = %+v", abort)
+	}
+}
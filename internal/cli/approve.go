@@ -7,6 +7,7 @@ import (
 
 	"github.com/Dicklesworthstone/slb/internal/config"
 	"github.com/Dicklesworthstone/slb/internal/core"
+	"github.com/Dicklesworthstone/slb/internal/daemon"
 	"github.com/Dicklesworthstone/slb/internal/db"
 	"github.com/Dicklesworthstone/slb/internal/integrations"
 	"github.com/Dicklesworthstone/slb/internal/output"
@@ -14,10 +15,16 @@ import (
 )
 
 var (
-	flagApproveSessionID     string
-	flagApproveSessionKey    string
-	flagApproveComments      string
-	flagApproveTargetProject string
+	flagApproveSessionID      string
+	flagApproveSessionKey     string
+	flagApproveComments       string
+	flagApproveTargetProject  string
+	flagApproveRequireSandbox bool
+
+	// Batch selection flags
+	flagApproveFilter  string
+	flagApproveAllFrom string
+	flagApproveYes     bool
 
 	// Structured response flags
 	flagApproveReasonResponse string
@@ -31,6 +38,10 @@ func init() {
 	approveCmd.Flags().StringVarP(&flagApproveSessionKey, "session-key", "k", "", "session HMAC key for signing (required)")
 	approveCmd.Flags().StringVarP(&flagApproveComments, "comments", "m", "", "additional comments")
 	approveCmd.Flags().StringVar(&flagApproveTargetProject, "target-project", "", "target project path for cross-project approvals")
+	approveCmd.Flags().StringVar(&flagApproveFilter, "filter", "", "approve every pending request matching key=value[,key=value] (tier, agent, session, model, command glob; * also matches / but not shell separators)")
+	approveCmd.Flags().StringVar(&flagApproveAllFrom, "all-from", "", "approve every pending request from this requestor session")
+	approveCmd.Flags().BoolVarP(&flagApproveYes, "yes", "y", false, "skip the batch confirmation")
+	approveCmd.Flags().BoolVar(&flagApproveRequireSandbox, "require-sandbox", false, "approve only for execution in a sandbox (Linux)")
 
 	// Structured response flags for justification fields
 	approveCmd.Flags().StringVar(&flagApproveReasonResponse, "reason-response", "", "response to the reason justification")
@@ -42,7 +53,7 @@ func init() {
 }
 
 var approveCmd = &cobra.Command{
-	Use:   "approve <request-id>",
+	Use:   "approve [request-id]",
 	Short: "Approve a pending request",
 	Long: `Approve a command request, allowing it to proceed.
 
@@ -53,14 +64,31 @@ requests (unless you are a trusted self-approve agent).
 For cross-project reviews, use --target-project to specify which project's
 database contains the request you want to approve.
 
+To approve several requests at once, select them with --filter and/or
+--all-from instead of a request ID. The matching commands are listed for
+confirmation (skip with --yes, required with JSON output), then each request
+gets its own signed review. Your own requests are never selected. A
+command glob matches the whole command, and its * also matches "/".
+
+With --require-sandbox the approval only holds for sandboxed execution: the
+command may only write to the paths it names, with the network and resource
+limits configured under general.sandbox_*. Where sandboxing is unavailable
+the command is not executed.
+
 	Examples:
 	  slb approve abc123 -s $SESSION_ID -k $SESSION_KEY
 	  slb approve abc123 -s $SESSION_ID -k $SESSION_KEY -m "Looks safe"
+	  slb approve abc123 -s $SESSION_ID -k $SESSION_KEY --require-sandbox
 	  slb approve abc123 -s $SESSION_ID -k $SESSION_KEY --reason-response "Valid use case"
-	  slb approve abc123 -s $SESSION_ID -k $SESSION_KEY --target-project /path/to/other/project`,
-	Args: cobra.ExactArgs(1),
+	  slb approve abc123 -s $SESSION_ID -k $SESSION_KEY --target-project /path/to/other/project
+	  slb approve -s $SESSION_ID -k $SESSION_KEY --filter 'tier=caution,agent=BlueLake'
+	  slb approve -s $SESSION_ID -k $SESSION_KEY --filter 'command=rm -rf ./dist-*' --yes`,
+	Args: batchReviewArgs(&flagApproveFilter, &flagApproveAllFrom),
 	RunE: func(cmd *cobra.Command, args []string) error {
-		requestID := args[0]
+		var requestID string
+		if len(args) > 0 {
+			requestID = args[0]
+		}
 
 		// Validate required flags
 		if flagApproveSessionID == "" {
@@ -102,12 +130,26 @@ database contains the request you want to approve.
 				GoalResponse:   flagApproveGoalResponse,
 				SafetyResponse: flagApproveSafetyResponse,
 			},
-			Comments: flagApproveComments,
+			Comments:       flagApproveComments,
+			RequireSandbox: flagApproveRequireSandbox,
 		}
 
 		// Create review service and submit
 		reviewSvc := core.NewReviewService(dbConn, core.DefaultReviewConfig())
-		reviewSvc.SetNotifier(buildAgentMailNotifier(project))
+		reviewSvc.SetNotifier(buildRequestNotifier(project))
+
+		if requestID == "" {
+			return runBatchReview(dbConn, batchReview{
+				Project: project,
+				Filter:  flagApproveFilter,
+				AllFrom: flagApproveAllFrom,
+				Yes:     flagApproveYes,
+				Opts:    opts,
+				Service: reviewSvc,
+				In:      cmd.InOrStdin(),
+			})
+		}
+
 		result, err := reviewSvc.SubmitReview(opts)
 		if err != nil {
 			return fmt.Errorf("submitting approval: %w", err)
@@ -160,6 +202,15 @@ database contains the request you want to approve.
 	},
 }
 
+// buildRequestNotifier publishes request events to the daemon (for live
+// subscribers) and to Agent Mail when enabled.
+func buildRequestNotifier(project string) integrations.RequestNotifier {
+	return integrations.MultiNotifier{
+		daemon.NewEventNotifier(daemon.DefaultSocketPath()),
+		buildAgentMailNotifier(project),
+	}
+}
+
 // buildAgentMailNotifier constructs a notifier from config; falls back to no-op on errors/disabled.
 func buildAgentMailNotifier(project string) integrations.RequestNotifier {
 	cfg, err := config.Load(config.LoadOptions{
@@ -61,6 +61,9 @@ func resetApproveFlags() {
 	flagApproveEffectResponse = ""
 	flagApproveGoalResponse = ""
 	flagApproveSafetyResponse = ""
+	flagApproveFilter = ""
+	flagApproveAllFrom = ""
+	flagApproveYes = false
 }
 
 func TestApproveCommand_RequiresRequestID(t *testing.T) {
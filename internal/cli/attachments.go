@@ -7,6 +7,7 @@ import (
 
 	"github.com/Dicklesworthstone/slb/internal/core"
 	"github.com/Dicklesworthstone/slb/internal/db"
+	"github.com/Dicklesworthstone/slb/internal/scrub"
 )
 
 // AttachmentFlags holds the attachment-related CLI flags.
@@ -14,12 +15,16 @@ type AttachmentFlags struct {
 	Files       []string
 	Contexts    []string
 	Screenshots []string
+	// Scrubber removes secrets from text attachments (default
+	// scrub.Default()).
+	Scrubber *scrub.Scrubber
 }
 
 // CollectAttachments loads and processes attachments from CLI flags.
 // It returns a slice of attachments ready to be included in a request.
 func CollectAttachments(ctx context.Context, flags AttachmentFlags) ([]db.Attachment, error) {
 	config := core.DefaultAttachmentConfig()
+	config.Scrubber = flags.Scrubber
 	var attachments []db.Attachment
 
 	// Process file attachments
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/Dicklesworthstone/slb/internal/core"
+	"github.com/Dicklesworthstone/slb/internal/daemon"
 	"github.com/Dicklesworthstone/slb/internal/db"
 	"github.com/Dicklesworthstone/slb/internal/output"
 	"github.com/spf13/cobra"
@@ -56,6 +57,8 @@ Use --session-id/-s to specify your session if not using environment.`,
 		if err := dbConn.UpdateRequestStatus(requestID, db.StatusCancelled); err != nil {
 			return fmt.Errorf("cancelling request: %w", err)
 		}
+		request.Status = db.StatusCancelled
+		_ = daemon.NewEventNotifier(daemon.DefaultSocketPath()).NotifyRequestCancelled(request)
 
 		out := output.New(output.Format(GetOutput()))
 		return out.Write(map[string]any{
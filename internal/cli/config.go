@@ -48,14 +48,14 @@ var configCmd = &cobra.Command{
 
 var configGetCmd = &cobra.Command{
 	Use:   "get <key>",
-	Short: "Get a specific configuration value",
+	Short: "Get a configuration value and the layer it came from",
 	Args:  cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		project, err := projectPath()
 		if err != nil {
 			return err
 		}
-		cfg, err := config.Load(config.LoadOptions{
+		cfg, prov, err := config.LoadWithProvenance(config.LoadOptions{
 			ProjectDir: project,
 			ConfigPath: flagConfig,
 		})
@@ -67,11 +67,20 @@ var configGetCmd = &cobra.Command{
 		if !ok {
 			return fmt.Errorf("unknown key %q", args[0])
 		}
+		source := prov.Source(args[0])
+		resp := map[string]any{
+			"key":    args[0],
+			"value":  val,
+			"source": source.Layer,
+		}
+		if source.Origin != "" {
+			resp["origin"] = source.Origin
+		}
+		if lock := prov.Lock(args[0]); lock != "" {
+			resp["lock"] = lock
+		}
 		out := output.New(output.Format(GetOutput()))
-		return out.Write(map[string]any{
-			"key":   args[0],
-			"value": val,
-		})
+		return out.Write(resp)
 	},
 }
 
@@ -94,9 +103,29 @@ var configSetCmd = &cobra.Command{
 		if err != nil {
 			return err
 		}
+		previous, readErr := os.ReadFile(target)
 		if err := config.WriteValue(target, args[0], value); err != nil {
 			return err
 		}
+		// Undo a write the system config's locks would ignore.
+		_, prov, err := config.LoadWithProvenance(config.LoadOptions{
+			ProjectDir: project,
+			ConfigPath: flagConfig,
+			Warn:       func(string) {},
+		})
+		if err == nil {
+			if src, problem := prov.Violation(args[0]); problem != "" && src.Origin == target {
+				err = fmt.Errorf("%w: %s", config.ErrLocked, problem)
+			}
+		}
+		if errors.Is(err, config.ErrLocked) {
+			if readErr == nil {
+				_ = os.WriteFile(target, previous, 0o600)
+			} else {
+				_ = os.Remove(target)
+			}
+			return err
+		}
 
 		out := output.New(output.Format(GetOutput()))
 		return out.Write(map[string]any{
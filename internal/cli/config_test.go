@@ -2,9 +2,13 @@ package cli
 
 import (
 	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
+	"github.com/Dicklesworthstone/slb/internal/config"
 	"github.com/Dicklesworthstone/slb/internal/testutil"
 	"github.com/spf13/cobra"
 )
@@ -183,6 +187,80 @@ func TestConfigSetCommand_SetsValue(t *testing.T) {
 	}
 }
 
+func TestConfigGetCommand_SystemLocks(t *testing.T) {
+	h := testutil.NewHarness(t)
+	resetConfigFlags()
+	t.Setenv("HOME", t.TempDir())
+	system := filepath.Join(t.TempDir(), "config.toml")
+	old := config.SystemConfigPath
+	config.SystemConfigPath = system
+	t.Cleanup(func() { config.SystemConfigPath = old })
+	if err := os.WriteFile(system, []byte("[general]\nmin_approvals = 3\n\n[locks]\nstricter = [\"general.min_approvals\"]\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	cmd := newTestConfigCmd(h.DBPath)
+	stdout, err := executeCommandCapture(t, cmd, "config", "get", "general.min_approvals", "-C", h.ProjectDir, "-j")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var result map[string]any
+	if err := json.Unmarshal([]byte(stdout), &result); err != nil {
+		t.Fatalf("failed to parse JSON: %v\nstdout: %s", err, stdout)
+	}
+	if result["source"] != "system" || result["origin"] != system || result["lock"] != "stricter" {
+		t.Errorf("unexpected provenance: %v", result)
+	}
+
+	// A weaker project value is refused and not left behind.
+	resetConfigFlags()
+	cmd = newTestConfigCmd(h.DBPath)
+	_, err = executeCommandCapture(t, cmd, "config", "set", "general.min_approvals", "1", "-C", h.ProjectDir, "-j")
+	if !errors.Is(err, config.ErrLocked) {
+		t.Fatalf("expected ErrLocked, got %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(h.ProjectDir, ".slb", "config.toml")); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("project config left behind: %v", err)
+	}
+}
+
+func TestCommands_RunWhenProjectConfigWeakensLockedKey(t *testing.T) {
+	h := testutil.NewHarness(t)
+	resetConfigFlags()
+	t.Setenv("HOME", t.TempDir())
+	system := filepath.Join(t.TempDir(), "config.toml")
+	old := config.SystemConfigPath
+	config.SystemConfigPath = system
+	t.Cleanup(func() { config.SystemConfigPath = old })
+	if err := os.WriteFile(system, []byte("[general]\nmin_approvals = 3\n\n[locks]\nstricter = [\"general.min_approvals\"]\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	// Written directly, as an agent editing the project config would.
+	if err := config.WriteValue(filepath.Join(h.ProjectDir, ".slb", "config.toml"), "general.min_approvals", 1); err != nil {
+		t.Fatal(err)
+	}
+
+	// The weaker value is ignored rather than failing the command.
+	stdout, err := executeCommandCapture(t, newTestConfigCmd(h.DBPath), "config", "get", "general.min_approvals", "-C", h.ProjectDir, "-j")
+	if err != nil {
+		t.Fatalf("config get: %v", err)
+	}
+	var result map[string]any
+	if err := json.Unmarshal([]byte(stdout), &result); err != nil {
+		t.Fatalf("failed to parse JSON: %v\nstdout: %s", err, stdout)
+	}
+	if result["value"] != float64(3) || result["source"] != "system" {
+		t.Errorf("unexpected value: %v", result)
+	}
+
+	// Other commands load the config and run as usual.
+	resetRequestFlags()
+	sess := testutil.MakeSession(t, h.DB, testutil.WithProject(h.ProjectDir))
+	if _, err := executeCommandCapture(t, newTestRequestCmd(h.DBPath), "request", "rm -rf ./build", "-s", sess.ID, "-C", h.ProjectDir, "-j"); err != nil {
+		t.Fatalf("request: %v", err)
+	}
+}
+
 func TestConfigCommand_Help(t *testing.T) {
 	h := testutil.NewHarness(t)
 	resetConfigFlags()
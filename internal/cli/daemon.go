@@ -3,11 +3,13 @@ package cli
 import (
 	"bufio"
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
 	"os"
 	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/Dicklesworthstone/slb/internal/daemon"
@@ -21,6 +23,11 @@ var (
 	flagDaemonStopTimeoutSecs int
 	flagDaemonLogsFollow      bool
 	flagDaemonLogsLines       int
+
+	flagDaemonCertDir   string
+	flagDaemonCertName  string
+	flagDaemonCertHosts []string
+	flagDaemonCertDays  int
 )
 
 func init() {
@@ -28,6 +35,16 @@ func init() {
 	daemonCmd.AddCommand(daemonStopCmd)
 	daemonCmd.AddCommand(daemonStatusCmd)
 	daemonCmd.AddCommand(daemonLogsCmd)
+	daemonCmd.AddCommand(daemonCertCmd)
+	daemonCmd.AddCommand(daemonOpenAPICmd)
+
+	daemonCertCmd.AddCommand(daemonCertCACmd)
+	daemonCertCmd.AddCommand(daemonCertServerCmd)
+	daemonCertCmd.AddCommand(daemonCertClientCmd)
+	daemonCertCmd.PersistentFlags().StringVar(&flagDaemonCertDir, "dir", "", "directory for certificates (default ~/.slb/tls)")
+	daemonCertCmd.PersistentFlags().IntVar(&flagDaemonCertDays, "days", 0, "certificate validity in days (default: CA 3650, others 365)")
+	daemonCertCmd.PersistentFlags().StringVar(&flagDaemonCertName, "name", "", "certificate common name (client: agent name or principal)")
+	daemonCertServerCmd.Flags().StringSliceVar(&flagDaemonCertHosts, "host", nil, "DNS name or IP the daemon is reached at (repeatable)")
 
 	daemonStartCmd.Flags().BoolVar(&flagDaemonStartForeground, "foreground", false, "run the daemon in the current process (do not fork)")
 
@@ -134,7 +151,7 @@ var daemonStatusCmd = &cobra.Command{
 		pendingCount, activeSessions := daemonProjectStats(project)
 
 		out := output.New(output.Format(GetOutput()))
-		return out.Write(map[string]any{
+		resp := map[string]any{
 			"running":         info.Status == daemon.DaemonRunning,
 			"status":          info.Status.String(),
 			"pid":             info.PID,
@@ -146,7 +163,11 @@ var daemonStatusCmd = &cobra.Command{
 			"socket_path":     info.SocketPath,
 			"socket_alive":    info.SocketAlive,
 			"message":         info.Message,
-		})
+		}
+		if freeze := daemonProjectFreeze(project); freeze != nil {
+			resp["freeze"] = freezeResponse("frozen", freeze)
+		}
+		return out.Write(resp)
 	},
 }
 
@@ -188,6 +209,156 @@ var daemonLogsCmd = &cobra.Command{
 	},
 }
 
+var daemonOpenAPICmd = &cobra.Command{
+	Use:   "openapi",
+	Short: "Print the OpenAPI document for the HTTP gateway",
+	Long: `Print the OpenAPI 3 document describing the daemon's HTTP/JSON and
+Server-Sent Events gateway (enabled with daemon.http_addr). A running
+gateway serves the same document at /v1/openapi.json.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		data, err := json.MarshalIndent(daemon.OpenAPIDocument(), "", "  ")
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintln(cmd.OutOrStdout(), string(data))
+		return err
+	},
+}
+
+var daemonCertCmd = &cobra.Command{
+	Use:   "cert",
+	Short: "Generate TLS certificates for the TCP listener",
+	Long: `Generate a local certificate authority and server/client certificates
+for the daemon's TCP listener.
+
+Typical setup:
+  slb daemon cert ca
+  slb daemon cert server --host slb.internal --host 10.0.0.5
+  slb daemon cert client --name BlueLake
+
+Then set daemon.tcp_tls_cert/tcp_tls_key to the server files and
+daemon.tcp_tls_client_ca to ca.pem. Remote agents connect with
+SLB_HOST=tls://host:port, SLB_TLS_CA, SLB_TLS_CERT and SLB_TLS_KEY.`,
+}
+
+var daemonCertCACmd = &cobra.Command{
+	Use:   "ca",
+	Short: "Create a local certificate authority",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		dir, err := daemonCertDir()
+		if err != nil {
+			return err
+		}
+		if _, err := os.Stat(filepath.Join(dir, "ca.pem")); err == nil {
+			return fmt.Errorf("CA already exists in %s", dir)
+		}
+
+		files, err := daemon.GenerateCA(dir, flagDaemonCertName, daemonCertValidity())
+		if err != nil {
+			return err
+		}
+		return writeDaemonCertOutput("ca", files)
+	},
+}
+
+var daemonCertServerCmd = &cobra.Command{
+	Use:   "server",
+	Short: "Issue the daemon's server certificate",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		dir, err := daemonCertDir()
+		if err != nil {
+			return err
+		}
+
+		hosts := flagDaemonCertHosts
+		if len(hosts) == 0 {
+			hosts = []string{"localhost", "127.0.0.1", "::1"}
+		}
+		name := strings.TrimSpace(flagDaemonCertName)
+		if name == "" {
+			name = hosts[0]
+		}
+
+		files, err := daemon.IssueCertificate(dir, dir, "server", daemon.CertOptions{
+			CommonName: name,
+			Hosts:      hosts,
+			ValidFor:   daemonCertValidity(),
+		})
+		if err != nil {
+			return err
+		}
+		return writeDaemonCertOutput("server", files)
+	},
+}
+
+var daemonCertClientCmd = &cobra.Command{
+	Use:   "client",
+	Short: "Issue a client certificate for a remote agent or principal",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		dir, err := daemonCertDir()
+		if err != nil {
+			return err
+		}
+
+		name := strings.TrimSpace(flagDaemonCertName)
+		if name == "" {
+			return fmt.Errorf("--name is required for client certificates")
+		}
+
+		files, err := daemon.IssueCertificate(dir, dir, "client-"+sanitizeCertFileName(name), daemon.CertOptions{
+			CommonName: name,
+			Client:     true,
+			ValidFor:   daemonCertValidity(),
+		})
+		if err != nil {
+			return err
+		}
+		return writeDaemonCertOutput("client", files)
+	},
+}
+
+func daemonCertDir() (string, error) {
+	if flagDaemonCertDir != "" {
+		return flagDaemonCertDir, nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("finding home dir: %w", err)
+	}
+	return filepath.Join(home, ".slb", "tls"), nil
+}
+
+func daemonCertValidity() time.Duration {
+	if flagDaemonCertDays <= 0 {
+		return 0
+	}
+	return time.Duration(flagDaemonCertDays) * 24 * time.Hour
+}
+
+func sanitizeCertFileName(name string) string {
+	var b strings.Builder
+	for _, r := range name {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-', r == '_', r == '.':
+			b.WriteRune(r)
+		default:
+			b.WriteRune('_')
+		}
+	}
+	return b.String()
+}
+
+func writeDaemonCertOutput(kind string, files daemon.CertFiles) error {
+	out := output.New(output.Format(GetOutput()))
+	return out.Write(map[string]any{
+		"kind":      kind,
+		"cert_path": files.CertPath,
+		"key_path":  files.KeyPath,
+		"subject":   files.Subject,
+		"not_after": files.NotAfter,
+	})
+}
+
 func daemonProjectPath() (string, error) {
 	if flagProject != "" {
 		return flagProject, nil
@@ -219,6 +390,26 @@ func daemonProjectStats(projectPath string) (pendingCount int, activeSessions in
 	return pendingCount, activeSessions
 }
 
+// daemonProjectFreeze returns the project's active change freeze, or nil.
+func daemonProjectFreeze(projectPath string) *db.Freeze {
+	dbPath := filepath.Join(projectPath, ".slb", "state.db")
+	dbConn, err := db.OpenWithOptions(dbPath, db.OpenOptions{
+		CreateIfNotExists: false,
+		InitSchema:        false,
+		ReadOnly:          true,
+	})
+	if err != nil {
+		return nil
+	}
+	defer dbConn.Close()
+
+	freeze, err := dbConn.GetActiveFreeze(projectPath, time.Now())
+	if err != nil {
+		return nil
+	}
+	return freeze
+}
+
 func daemonLogPath() (string, error) {
 	home, err := os.UserHomeDir()
 	if err != nil {
@@ -527,3 +527,50 @@ func TestDaemonLogPath_HomeUnset(t *testing.T) {
 		}
 	}
 }
+
+func TestDaemonCertCommands(t *testing.T) {
+	resetDaemonFlags()
+	flagOutput = "json"
+	dir := t.TempDir()
+	flagDaemonCertDir = dir
+	defer func() {
+		flagDaemonCertDir = ""
+		flagDaemonCertName = ""
+		flagDaemonCertHosts = nil
+		flagDaemonCertDays = 0
+	}()
+
+	if err := daemonCertCACmd.RunE(daemonCertCACmd, nil); err != nil {
+		t.Fatalf("cert ca: %v", err)
+	}
+	if err := daemonCertCACmd.RunE(daemonCertCACmd, nil); err == nil {
+		t.Fatalf("expected error when CA already exists")
+	}
+
+	flagDaemonCertHosts = []string{"127.0.0.1"}
+	if err := daemonCertServerCmd.RunE(daemonCertServerCmd, nil); err != nil {
+		t.Fatalf("cert server: %v", err)
+	}
+
+	if err := daemonCertClientCmd.RunE(daemonCertClientCmd, nil); err == nil {
+		t.Fatalf("expected error for client cert without --name")
+	}
+	flagDaemonCertName = "Blue Lake"
+	if err := daemonCertClientCmd.RunE(daemonCertClientCmd, nil); err != nil {
+		t.Fatalf("cert client: %v", err)
+	}
+
+	for _, name := range []string{"ca.pem", "ca-key.pem", "server.pem", "server-key.pem", "client-Blue_Lake.pem", "client-Blue_Lake-key.pem"} {
+		if _, err := os.Stat(filepath.Join(dir, name)); err != nil {
+			t.Errorf("expected %s to exist: %v", name, err)
+		}
+	}
+
+	info, err := os.Stat(filepath.Join(dir, "client-Blue_Lake-key.pem"))
+	if err != nil {
+		t.Fatalf("stat key: %v", err)
+	}
+	if info.Mode().Perm() != 0600 {
+		t.Errorf("expected key mode 0600, got %o", info.Mode().Perm())
+	}
+}
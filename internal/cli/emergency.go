@@ -6,6 +6,7 @@ import (
 	"crypto/sha256"
 	"encoding/hex"
 	"fmt"
+	"io"
 	"os"
 	"strings"
 	"time"
@@ -47,7 +48,9 @@ This is a HUMAN OVERRIDE for emergency situations. It requires:
 - A mandatory reason explaining why the bypass is necessary
 - Interactive confirmation OR --yes with --ack containing the command hash
 
-The command is extensively logged for audit purposes.
+The command is extensively logged for audit purposes. Freeze periods and
+change freezes (slb freeze) do not stop it, but any it bypasses are recorded
+in the log and the output.
 
 Examples:
   slb emergency-execute "rm -rf /tmp/broken" -r "System emergency"
@@ -128,6 +131,20 @@ To get the command hash for --ack, run:
 		if err != nil {
 			return fmt.Errorf("loading config: %w", err)
 		}
+		scrubber, err := newScrubber(cfg)
+		if err != nil {
+			return err
+		}
+
+		// Freezes covering the command are bypassed, but recorded.
+		tier := core.GetDefaultEngine().ClassifyCommand(command, cwd).Tier
+		var bypassedFreezes []string
+		if err := changeSchedule(cfg).CheckFreeze(tier, time.Now()); err != nil {
+			bypassedFreezes = append(bypassedFreezes, err.Error())
+		}
+		if freeze, err := core.ActiveChangeFreeze(dbConn, project, tier, time.Now()); err == nil && freeze != nil {
+			bypassedFreezes = append(bypassedFreezes, fmt.Sprintf("%s: %s", core.ErrChangeFreeze, core.DescribeFreeze(freeze)))
+		}
 
 		// Build command spec
 		cmdSpec := &db.CommandSpec{
@@ -178,21 +195,25 @@ To get the command hash for --ack, run:
 		fmt.Fprintf(logFile, "=== EMERGENCY EXECUTION ===\n")
 		fmt.Fprintf(logFile, "Time:    %s\n", time.Now().Format(time.RFC3339))
 		fmt.Fprintf(logFile, "Actor:   %s\n", GetActor())
-		fmt.Fprintf(logFile, "Command: %s\n", command)
+		loggedCommand, _ := scrubber.String(command)
+		fmt.Fprintf(logFile, "Command: %s\n", loggedCommand)
 		fmt.Fprintf(logFile, "Hash:    %s\n", commandHash)
 		fmt.Fprintf(logFile, "Reason:  %s\n", flagEmergencyReason)
 		fmt.Fprintf(logFile, "CWD:     %s\n", cwd)
+		for _, freeze := range bypassedFreezes {
+			fmt.Fprintf(logFile, "Bypassed: %s\n", freeze)
+		}
 		fmt.Fprintf(logFile, "============================\n\n")
 
 		// Execute the command
 		ctx, cancel := context.WithTimeout(context.Background(), time.Duration(flagEmergencyTimeout)*time.Second)
 		defer cancel()
 
-		var streamWriter *os.File
+		var streamWriter io.Writer
 		if GetOutput() != "json" {
 			streamWriter = os.Stdout
 		}
-		result, err := core.RunCommand(ctx, cmdSpec, logPath, streamWriter)
+		result, err := core.RunCommandWithOptions(ctx, cmdSpec, core.RunOptions{Scrubber: scrubber}, logPath, streamWriter)
 
 		// Build output
 		type emergencyResult struct {
@@ -206,6 +227,8 @@ To get the command hash for --ack, run:
 			Actor        string `json:"actor"`
 			ExecutedAt   string `json:"executed_at"`
 			Error        string `json:"error,omitempty"`
+			// BypassedFreezes are the freezes active for the command's tier.
+			BypassedFreezes []string `json:"bypassed_freezes,omitempty"`
 		}
 
 		resp := emergencyResult{
@@ -217,6 +240,7 @@ To get the command hash for --ack, run:
 			Actor:        GetActor(),
 			ExecutedAt:   time.Now().Format(time.RFC3339),
 		}
+		resp.BypassedFreezes = bypassedFreezes
 
 		if result != nil {
 			resp.ExitCode = result.ExitCode
@@ -245,6 +269,9 @@ To get the command hash for --ack, run:
 			fmt.Printf("Duration: %dms\n", resp.DurationMs)
 		}
 		fmt.Printf("Log: %s\n", resp.LogPath)
+		for _, freeze := range resp.BypassedFreezes {
+			fmt.Printf("Bypassed: %s\n", freeze)
+		}
 		if resp.RollbackPath != "" {
 			fmt.Printf("Rollback: %s\n", resp.RollbackPath)
 		}
@@ -18,6 +18,8 @@ var (
 	flagExecuteTimeout    int
 	flagExecuteBackground bool
 	flagExecuteLogDir     string
+	flagExecutePTY        bool
+	flagExecuteWaitWindow bool
 )
 
 func init() {
@@ -25,6 +27,8 @@ func init() {
 	executeCmd.Flags().IntVarP(&flagExecuteTimeout, "timeout", "t", 300, "execution timeout in seconds")
 	executeCmd.Flags().BoolVar(&flagExecuteBackground, "background", false, "run in background, return immediately")
 	executeCmd.Flags().StringVar(&flagExecuteLogDir, "log-dir", ".slb/logs", "directory for execution logs")
+	executeCmd.Flags().BoolVar(&flagExecutePTY, "pty", false, "run attached to a pseudo-terminal and record a replayable transcript")
+	executeCmd.Flags().BoolVar(&flagExecuteWaitWindow, "wait-window", false, "wait for the request's execution window to open instead of failing")
 	// Reuse Agent Mail notifier builder from approve/reject
 	_ = integrations.NoopNotifier{} // keep import if build tags change
 
@@ -44,11 +48,21 @@ Gate conditions are validated before execution:
 - Approval must not be expired
 - Command hash must match (no tampering)
 - Current pattern policy must not require higher tier
+- The request's execution window, if any, must be open
+- No freeze period may cover the request's tier
+
+With --pty the command runs attached to a pseudo-terminal, so TTY-sensitive
+tools (terraform prompts, kubectl progress, psql pagers) behave as they do
+interactively. Prompts matching general.pty_auto_answers are answered
+automatically, and the session is recorded next to the log as an asciicast
+transcript that "slb show --replay" plays back.
 
 Examples:
   slb execute abc123 -s $SESSION_ID
   slb execute abc123 -s $SESSION_ID --timeout 600
-  slb execute abc123 -s $SESSION_ID --background`,
+  slb execute abc123 -s $SESSION_ID --background
+  slb execute abc123 -s $SESSION_ID --pty
+  slb execute abc123 -s $SESSION_ID --wait-window`,
 	Args: cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		requestID := args[0]
@@ -78,8 +92,16 @@ Examples:
 			return fmt.Errorf("loading config: %w", err)
 		}
 
+		schedule := changeSchedule(cfg)
+		if flagExecuteWaitWindow && req.Status == db.StatusApproved {
+			if err := waitForWindow(cmd.Context(), schedule, req); err != nil {
+				return fmt.Errorf("cannot execute: %w", err)
+			}
+		}
+
 		// Create executor
-		executor := core.NewExecutor(dbConn, nil).WithNotifier(buildAgentMailNotifier(req.ProjectPath))
+		executor := core.NewExecutor(dbConn, nil).WithNotifier(buildRequestNotifier(req.ProjectPath)).
+			WithEnvironmentVars(cfg.General.FingerprintEnvVars).WithSchedule(schedule)
 
 		// Check if we can execute first
 		canExec, reason := executor.CanExecute(requestID)
@@ -97,20 +119,35 @@ Examples:
 			SuppressOutput:    GetOutput() == "json",
 			CaptureRollback:   cfg.General.EnableRollbackCapture,
 			MaxRollbackSizeMB: cfg.General.MaxRollbackSizeMB,
+			SandboxDefaults:   sandboxDefaults(cfg),
+			PTY:               flagExecutePTY,
 		}
+		if opts.Scrubber, err = newScrubber(cfg); err != nil {
+			return err
+		}
+		stream, closeStream := outputStream(requestID)
+		opts.OutputStream = stream
 
 		// Execute
 		ctx := context.Background()
 		result, err := executor.ExecuteApprovedRequest(ctx, opts)
+		closeStream()
 
 		// Build output
 		type executeResult struct {
-			RequestID  string `json:"request_id"`
-			ExitCode   int    `json:"exit_code"`
-			DurationMs int64  `json:"duration_ms"`
-			LogPath    string `json:"log_path"`
-			TimedOut   bool   `json:"timed_out,omitempty"`
-			Error      string `json:"error,omitempty"`
+			RequestID      string `json:"request_id"`
+			ExitCode       int    `json:"exit_code"`
+			DurationMs     int64  `json:"duration_ms"`
+			LogPath        string `json:"log_path"`
+			TranscriptPath string `json:"transcript_path,omitempty"`
+			TimedOut       bool   `json:"timed_out,omitempty"`
+			Error          string `json:"error,omitempty"`
+
+			SandboxViolations []string       `json:"sandbox_violations,omitempty"`
+			AutoAnswers       []string       `json:"auto_answers,omitempty"`
+			Redactions        map[string]int `json:"redactions,omitempty"`
+
+			Steps []db.PlanStepResult `json:"steps,omitempty"`
 		}
 
 		resp := executeResult{
@@ -122,6 +159,13 @@ Examples:
 			resp.DurationMs = result.Duration.Milliseconds()
 			resp.LogPath = result.LogPath
 			resp.TimedOut = result.TimedOut
+			resp.TranscriptPath = result.TranscriptPath
+			resp.SandboxViolations = result.SandboxViolations
+			resp.AutoAnswers = result.AutoAnswers
+			if totalRedactions(result.Redactions) > 0 {
+				resp.Redactions = result.Redactions
+			}
+			resp.Steps = result.Steps
 		}
 
 		if err != nil {
@@ -137,6 +181,7 @@ Examples:
 		}
 
 		// Human-readable output
+		printPlanSteps(resp.Steps)
 		if err != nil {
 			fmt.Printf("Execution failed: %s\n", err)
 			if result != nil && result.LogPath != "" {
@@ -149,6 +194,12 @@ Examples:
 		fmt.Printf("Exit code: %d\n", resp.ExitCode)
 		fmt.Printf("Duration: %dms\n", resp.DurationMs)
 		fmt.Printf("Log: %s\n", resp.LogPath)
+		if resp.TranscriptPath != "" {
+			fmt.Printf("Transcript: %s\n", resp.TranscriptPath)
+		}
+		printSandboxViolations(resp.SandboxViolations)
+		printAutoAnswers(resp.AutoAnswers)
+		printRedactions(resp.Redactions)
 
 		return nil
 	},
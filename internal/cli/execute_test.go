@@ -51,6 +51,7 @@ func resetExecuteFlags() {
 	flagExecuteTimeout = 300
 	flagExecuteBackground = false
 	flagExecuteLogDir = ".slb/logs"
+	flagExecuteWaitWindow = false
 }
 
 func TestExecuteCommand_RequiresRequestID(t *testing.T) {
@@ -151,6 +152,7 @@ func TestExecuteCommand_ExecutesApprovedRequest(t *testing.T) {
 	cmd := newTestExecuteCmd(h.DBPath)
 	stdout, err := executeCommandCapture(t, cmd, "execute", req.ID,
 		"-s", sess.ID,
+		"--log-dir", t.TempDir(),
 		"-j",
 	)
 
@@ -233,6 +235,7 @@ func TestExecuteCommand_CustomTimeout(t *testing.T) {
 	stdout, err := executeCommandCapture(t, cmd, "execute", req.ID,
 		"-s", sess.ID,
 		"-t", "10", // Short timeout
+		"--log-dir", t.TempDir(),
 		"-j",
 	)
 
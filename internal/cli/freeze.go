@@ -0,0 +1,213 @@
+// Package cli implements the freeze command for change freezes.
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Dicklesworthstone/slb/internal/config"
+	"github.com/Dicklesworthstone/slb/internal/core"
+	"github.com/Dicklesworthstone/slb/internal/daemon"
+	"github.com/Dicklesworthstone/slb/internal/db"
+	"github.com/Dicklesworthstone/slb/internal/output"
+	"github.com/spf13/cobra"
+)
+
+var (
+	flagFreezeReason string
+	flagFreezeUntil  string
+	flagFreezeTier   string
+
+	flagFreezeSessionID  string
+	flagFreezeSessionKey string
+)
+
+func init() {
+	freezeStartCmd.Flags().StringVarP(&flagFreezeReason, "reason", "r", "", "why changes are frozen (required)")
+	freezeStartCmd.Flags().StringVar(&flagFreezeUntil, "until", "", "end of the freeze (RFC3339, or a duration from now such as 4h); default until ended")
+	freezeStartCmd.Flags().StringVar(&flagFreezeTier, "tier", "caution", "lowest frozen tier: caution, dangerous or critical")
+	freezeStartCmd.Flags().StringVarP(&flagFreezeSessionID, "session-id", "s", "", "session starting the freeze (required)")
+	freezeStartCmd.Flags().StringVarP(&flagFreezeSessionKey, "session-key", "k", "", "session HMAC key (required)")
+	freezeEndCmd.Flags().StringVarP(&flagFreezeSessionID, "session-id", "s", "", "session ending the freeze (required)")
+	freezeEndCmd.Flags().StringVarP(&flagFreezeSessionKey, "session-key", "k", "", "session HMAC key (required)")
+
+	freezeCmd.AddCommand(freezeStartCmd)
+	freezeCmd.AddCommand(freezeEndCmd)
+	freezeCmd.AddCommand(freezeStatusCmd)
+	rootCmd.AddCommand(freezeCmd)
+}
+
+var freezeCmd = &cobra.Command{
+	Use:   "freeze",
+	Short: "Start, end or show a change freeze",
+	Long: `Declare a change freeze for the project (maintenance mode).
+
+While a freeze is active, requests at or above its tier are rejected when
+they are created, unless their execution window opens after the freeze
+ends, and approved requests are refused at execution. Safe commands are
+never frozen. Starting a freeze takes an active session and its key, and
+records the session and its agent. Only that session can end the freeze,
+or an active session of an agent listed in general.freeze_operators when
+the system config locks that key. Starting and ending a freeze is announced
+to daemon subscribers (slb watch, the TUI), and emergency-execute records
+that it bypassed an active freeze.
+
+Examples:
+  slb freeze start -s $SESSION_ID -k $SESSION_KEY --reason "Release 2.4" --until 2026-11-02T18:00:00Z
+  slb freeze start -s $SESSION_ID -k $SESSION_KEY --reason "Incident 1234" --until 4h --tier dangerous
+  slb freeze status
+  slb freeze end -s $SESSION_ID -k $SESSION_KEY`,
+}
+
+var freezeStartCmd = &cobra.Command{
+	Use:   "start",
+	Short: "Start a change freeze",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if flagFreezeSessionID == "" {
+			return fmt.Errorf("--session-id is required")
+		}
+		if flagFreezeSessionKey == "" {
+			return fmt.Errorf("--session-key is required")
+		}
+		project, err := projectPath()
+		if err != nil {
+			return err
+		}
+		until, err := parseWindowTime("--until", flagFreezeUntil, time.Now())
+		if err != nil {
+			return err
+		}
+
+		dbConn, err := db.OpenAndMigrate(GetDB())
+		if err != nil {
+			return fmt.Errorf("opening database: %w", err)
+		}
+		defer dbConn.Close()
+
+		freeze, err := core.StartChangeFreeze(dbConn, core.StartFreezeOptions{
+			ProjectPath: project,
+			Reason:      flagFreezeReason,
+			Tier:        core.RiskTier(flagFreezeTier),
+			Until:       until,
+			SessionID:   flagFreezeSessionID,
+			SessionKey:  flagFreezeSessionKey,
+		})
+		if errors.Is(err, db.ErrFreezeActive) {
+			return fmt.Errorf("%w; end it first with: slb freeze end -s <session-id> -k <session-key>", err)
+		}
+		if err != nil {
+			return fmt.Errorf("starting freeze: %w", err)
+		}
+		_ = daemon.NewEventNotifier(daemon.DefaultSocketPath()).NotifyFreezeStarted(freeze)
+
+		out := output.New(output.Format(GetOutput()))
+		return out.Write(freezeResponse("frozen", freeze))
+	},
+}
+
+var freezeEndCmd = &cobra.Command{
+	Use:   "end",
+	Short: "End the active change freeze",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if flagFreezeSessionID == "" {
+			return fmt.Errorf("--session-id is required")
+		}
+		if flagFreezeSessionKey == "" {
+			return fmt.Errorf("--session-key is required")
+		}
+		project, err := projectPath()
+		if err != nil {
+			return err
+		}
+		cfg, prov, err := config.LoadWithProvenance(config.LoadOptions{
+			ProjectDir: project,
+			ConfigPath: flagConfig,
+		})
+		if err != nil {
+			return fmt.Errorf("loading config: %w", err)
+		}
+
+		dbConn, err := db.OpenAndMigrate(GetDB())
+		if err != nil {
+			return fmt.Errorf("opening database: %w", err)
+		}
+		defer dbConn.Close()
+
+		freeze, err := core.EndChangeFreeze(dbConn, core.EndFreezeOptions{
+			ProjectPath: project,
+			SessionID:   flagFreezeSessionID,
+			SessionKey:  flagFreezeSessionKey,
+			Operators:   freezeOperators(cfg, prov),
+		})
+		if err != nil {
+			return fmt.Errorf("ending freeze: %w", err)
+		}
+		_ = daemon.NewEventNotifier(daemon.DefaultSocketPath()).NotifyFreezeEnded(freeze)
+
+		out := output.New(output.Format(GetOutput()))
+		return out.Write(freezeResponse("ended", freeze))
+	},
+}
+
+var freezeStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show the active change freeze",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		project, err := projectPath()
+		if err != nil {
+			return err
+		}
+
+		dbConn, err := db.OpenAndMigrate(GetDB())
+		if err != nil {
+			return fmt.Errorf("opening database: %w", err)
+		}
+		defer dbConn.Close()
+
+		out := output.New(output.Format(GetOutput()))
+		freeze, err := dbConn.GetActiveFreeze(project, time.Now())
+		if errors.Is(err, db.ErrFreezeNotFound) {
+			return out.Write(map[string]any{"status": "none", "project_path": project})
+		}
+		if err != nil {
+			return fmt.Errorf("getting freeze: %w", err)
+		}
+		return out.Write(freezeResponse("frozen", freeze))
+	},
+}
+
+// freezeOperators returns the agents allowed to end any freeze. Agents can
+// write the project config, so the list only counts when the system config
+// locks it.
+func freezeOperators(cfg config.Config, prov *config.Provenance) []string {
+	if prov.Lock("general.freeze_operators") != config.LockLocked {
+		return nil
+	}
+	return cfg.General.FreezeOperators
+}
+
+func freezeResponse(status string, f *db.Freeze) map[string]any {
+	resp := map[string]any{
+		"status":             status,
+		"freeze_id":          f.ID,
+		"project_path":       f.ProjectPath,
+		"tier":               string(f.Tier),
+		"reason":             f.Reason,
+		"started_by":         f.StartedBy,
+		"started_by_session": f.StartedBySession,
+		"started_at":         f.StartedAt.Format(time.RFC3339),
+		"summary":            core.DescribeFreeze(f),
+	}
+	if f.Until != nil {
+		resp["until"] = f.Until.Format(time.RFC3339)
+	}
+	if f.EndedAt != nil {
+		resp["ended_by"] = f.EndedBy
+		resp["ended_at"] = f.EndedAt.Format(time.RFC3339)
+	}
+	return resp
+}
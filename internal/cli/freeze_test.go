@@ -0,0 +1,205 @@
+package cli
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Dicklesworthstone/slb/internal/config"
+	"github.com/Dicklesworthstone/slb/internal/db"
+	"github.com/Dicklesworthstone/slb/internal/testutil"
+	"github.com/spf13/cobra"
+)
+
+func newTestFreezeCmd(dbPath string) *cobra.Command {
+	root := &cobra.Command{
+		Use:           "slb",
+		SilenceUsage:  true,
+		SilenceErrors: true,
+	}
+
+	root.PersistentFlags().StringVar(&flagDB, "db", dbPath, "database path")
+	root.PersistentFlags().StringVarP(&flagOutput, "output", "o", "text", "output format")
+	root.PersistentFlags().BoolVarP(&flagJSON, "json", "j", false, "json output")
+	root.PersistentFlags().StringVarP(&flagProject, "project", "C", "", "project directory")
+	root.PersistentFlags().StringVar(&flagActor, "actor", "", "actor identifier")
+
+	root.AddCommand(freezeCmd)
+
+	return root
+}
+
+func resetFreezeFlags() {
+	flagDB = ""
+	flagOutput = "text"
+	flagJSON = false
+	flagProject = ""
+	flagActor = ""
+	flagFreezeReason = ""
+	flagFreezeUntil = ""
+	flagFreezeTier = "caution"
+	flagFreezeSessionID = ""
+	flagFreezeSessionKey = ""
+}
+
+func TestFreezeCommand_StartStatusEnd(t *testing.T) {
+	h := testutil.NewHarness(t)
+	resetFreezeFlags()
+	t.Cleanup(resetFreezeFlags)
+	sess := testutil.MakeSession(t, h.DB, testutil.WithProject(h.ProjectDir), testutil.WithAgent("BlueLake"))
+
+	run := func(args ...string) map[string]any {
+		t.Helper()
+		resetFreezeFlags()
+		stdout, err := executeCommandCapture(t, newTestFreezeCmd(h.DBPath), append(args, "-C", h.ProjectDir, "-j")...)
+		if err != nil {
+			t.Fatalf("%v: %v", args, err)
+		}
+		var result map[string]any
+		if err := json.Unmarshal([]byte(stdout), &result); err != nil {
+			t.Fatalf("failed to parse JSON: %v\nstdout: %s", err, stdout)
+		}
+		return result
+	}
+
+	result := run("freeze", "start", "-s", sess.ID, "-k", sess.SessionKey, "--reason", "release", "--until", "2h", "--tier", "dangerous")
+	if result["status"] != "frozen" || result["tier"] != "dangerous" || result["started_by"] != "BlueLake" ||
+		result["started_by_session"] != sess.ID || result["until"] == nil {
+		t.Errorf("start: %v", result)
+	}
+
+	resetFreezeFlags()
+	_, err := executeCommandCapture(t, newTestFreezeCmd(h.DBPath), "freeze", "start", "-s", sess.ID, "-k", sess.SessionKey, "--reason", "again", "-C", h.ProjectDir)
+	if err == nil || !strings.Contains(err.Error(), "already active") {
+		t.Errorf("second start: expected an error, got %v", err)
+	}
+
+	if result := run("freeze", "status"); result["reason"] != "release" {
+		t.Errorf("status: %v", result)
+	}
+	if freeze := daemonProjectFreeze(h.ProjectDir); freeze == nil || freeze.Reason != "release" {
+		t.Errorf("daemonProjectFreeze = %+v", freeze)
+	}
+
+	if result := run("freeze", "end", "-s", sess.ID, "-k", sess.SessionKey); result["status"] != "ended" || result["ended_by"] != "BlueLake" {
+		t.Errorf("end: %v", result)
+	}
+	if result := run("freeze", "status"); result["status"] != "none" {
+		t.Errorf("status after end: %v", result)
+	}
+}
+
+func TestFreezeCommand_RequiresReason(t *testing.T) {
+	h := testutil.NewHarness(t)
+	resetFreezeFlags()
+	t.Cleanup(resetFreezeFlags)
+	sess := testutil.MakeSession(t, h.DB, testutil.WithProject(h.ProjectDir))
+
+	_, err := executeCommandCapture(t, newTestFreezeCmd(h.DBPath), "freeze", "start", "-s", sess.ID, "-k", sess.SessionKey, "-C", h.ProjectDir)
+	if err == nil || !strings.Contains(err.Error(), "reason is required") {
+		t.Errorf("expected a reason error, got %v", err)
+	}
+}
+
+func TestFreezeCommand_StartRequiresSession(t *testing.T) {
+	h := testutil.NewHarness(t)
+	resetFreezeFlags()
+	t.Cleanup(resetFreezeFlags)
+	sess := testutil.MakeSession(t, h.DB, testutil.WithProject(h.ProjectDir))
+
+	for _, tc := range []struct {
+		name string
+		args []string
+		want string
+	}{
+		{"no session", nil, "--session-id is required"},
+		{"no key", []string{"-s", sess.ID}, "--session-key is required"},
+		{"wrong key", []string{"-s", sess.ID, "-k", "not-the-key"}, "session key does not match"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			resetFreezeFlags()
+			args := append([]string{"freeze", "start", "--reason", "release", "-C", h.ProjectDir}, tc.args...)
+			_, err := executeCommandCapture(t, newTestFreezeCmd(h.DBPath), args...)
+			if err == nil || !strings.Contains(err.Error(), tc.want) {
+				t.Errorf("expected an error containing %q, got %v", tc.want, err)
+			}
+		})
+	}
+
+	if _, err := h.DB.GetActiveFreeze(h.ProjectDir, time.Now()); err == nil {
+		t.Error("no freeze should have started")
+	}
+}
+
+func TestFreezeCommand_EndRequiresStarter(t *testing.T) {
+	h := testutil.NewHarness(t)
+	resetFreezeFlags()
+	t.Cleanup(resetFreezeFlags)
+
+	t.Setenv("HOME", t.TempDir())
+	system := filepath.Join(t.TempDir(), "config.toml")
+	old := config.SystemConfigPath
+	config.SystemConfigPath = system
+	t.Cleanup(func() { config.SystemConfigPath = old })
+
+	starter := testutil.MakeSession(t, h.DB, testutil.WithProject(h.ProjectDir), testutil.WithAgent("BlueLake"))
+	other := testutil.MakeSession(t, h.DB, testutil.WithProject(h.ProjectDir), testutil.WithAgent("GreenField"))
+	if _, err := executeCommandCapture(t, newTestFreezeCmd(h.DBPath), "freeze", "start", "-s", starter.ID, "-k", starter.SessionKey, "--reason", "release", "-C", h.ProjectDir); err != nil {
+		t.Fatalf("start: %v", err)
+	}
+
+	end := func(sess *db.Session) error {
+		t.Helper()
+		resetFreezeFlags()
+		_, err := executeCommandCapture(t, newTestFreezeCmd(h.DBPath), "freeze", "end", "-s", sess.ID, "-k", sess.SessionKey, "-C", h.ProjectDir)
+		return err
+	}
+
+	for _, tc := range []struct {
+		name string
+		args []string
+		want string
+	}{
+		{"no session", nil, "--session-id is required"},
+		{"no key", []string{"-s", other.ID}, "--session-key is required"},
+		{"wrong key", []string{"-s", other.ID, "-k", "not-the-key"}, "session key does not match"},
+		{"unknown session", []string{"-s", "missing", "-k", other.SessionKey}, "session"},
+		{"another session", []string{"-s", other.ID, "-k", other.SessionKey}, "only the session that started the freeze"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			resetFreezeFlags()
+			args := append([]string{"freeze", "end", "-C", h.ProjectDir}, tc.args...)
+			_, err := executeCommandCapture(t, newTestFreezeCmd(h.DBPath), args...)
+			if err == nil || !strings.Contains(err.Error(), tc.want) {
+				t.Errorf("expected an error containing %q, got %v", tc.want, err)
+			}
+		})
+	}
+
+	// Operators named by the project config do not count: agents can write it.
+	if err := os.WriteFile(filepath.Join(h.ProjectDir, ".slb", "config.toml"), []byte("[general]\nfreeze_operators = [\"GreenField\"]\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := end(other); err == nil || !strings.Contains(err.Error(), "only the session that started the freeze") {
+		t.Errorf("operator from the project config: got %v", err)
+	}
+	if f, err := h.DB.GetActiveFreeze(h.ProjectDir, time.Now()); err != nil || f == nil {
+		t.Fatalf("freeze should still be active: %+v, %v", f, err)
+	}
+
+	if err := os.Remove(filepath.Join(h.ProjectDir, ".slb", "config.toml")); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(system, []byte("[general]\nfreeze_operators = [\"GreenField\"]\n\n[locks]\nlocked = [\"general.freeze_operators\"]\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := end(other); err != nil {
+		t.Errorf("operator from the locked system config: %v", err)
+	}
+	if _, err := h.DB.GetActiveFreeze(h.ProjectDir, time.Now()); err == nil {
+		t.Error("freeze should have ended")
+	}
+}
@@ -101,14 +101,19 @@ func showQuickReference() {
 
 	requestor := renderSection(useUnicode, "🔶 AS REQUESTOR (dangerous commands)", []string{
 		bullet("slb run \"rm -rf ./build\" -s $SID --reason \"Cleanup\" --timeout 300 -j", "classify, request approval, wait, then execute"),
+		bullet("slb run --plan plan.yaml -s $SID --reason \"Retire db\"", "approve ordered steps once, run them in order"),
+		bullet("slb run \"./migrate.sh\" -s $SID --window nightly --wait-window", "approve ahead, execute in a change window"),
 		bullet("slb status <request-id> --wait -j", "block until approved/rejected/timeout"),
 		bullet("slb execute <request-id> -s $SID -j", "execute once approved (client-side)"),
+		bullet("slb execute <request-id> -s $SID --pty", "execute in a pseudo-terminal (TTY-sensitive tools)"),
 	})
 
 	plumbing := renderSection(useUnicode, "🔧 PLUMBING (advanced)", []string{
 		bullet("slb request \"...\" --wait --execute -s $SID --reason \"...\"", "submit without shorthand"),
 		bullet("slb cancel <request-id>", "cancel pending"),
 		bullet("slb rollback <request-id>", "apply rollback capture (if available)"),
+		bullet("slb freeze start --reason \"...\" --until 4h [--tier dangerous]", "freeze changes (maintenance mode)"),
+		bullet("slb policy test \"...\" [--agent A --model M]", "show which policy rules fire"),
 	})
 
 	reviewer := renderSection(useUnicode, "🔷 AS REVIEWER (check frequently)", []string{
@@ -116,6 +121,8 @@ func showQuickReference() {
 		bullet("slb review <id> -j", "inspect details"),
 		bullet("slb approve <id> -s $SID -k $SKEY --reason-response \"Verified\"", "approve (signed)"),
 		bullet("slb reject <id> -s $SID -k $SKEY --reason \"Need safer path\"", "reject (signed)"),
+		bullet("slb tail <id>", "follow an approved command's output"),
+		bullet("slb abort <id> -s $SID -k $SKEY --reason \"...\"", "stop an approved command mid-run"),
 	})
 
 	patterns := renderSection(useUnicode, "🛡️ PATTERNS (agents can add, not remove)", []string{
@@ -3,8 +3,11 @@ package cli
 
 import (
 	"fmt"
+	"os"
+	"strings"
 	"time"
 
+	"github.com/Dicklesworthstone/slb/internal/config"
 	"github.com/Dicklesworthstone/slb/internal/db"
 	"github.com/Dicklesworthstone/slb/internal/output"
 	"github.com/spf13/cobra"
@@ -16,15 +19,19 @@ var (
 	flagHistoryAgent  string
 	flagHistoryTier   string
 	flagHistorySince  string
+	flagHistorySaved  string
+	flagHistoryCursor string
 	flagHistoryLimit  int
 )
 
 func init() {
-	historyCmd.Flags().StringVarP(&flagHistoryQuery, "query", "q", "", "full-text search query")
+	historyCmd.Flags().StringVarP(&flagHistoryQuery, "query", "q", "", "history query (e.g. 'tier:critical since:7d exit:!=0')")
 	historyCmd.Flags().StringVar(&flagHistoryStatus, "status", "", "filter by status (pending, approved, rejected, executed, etc.)")
 	historyCmd.Flags().StringVar(&flagHistoryAgent, "agent", "", "filter by requestor agent name")
-	historyCmd.Flags().StringVar(&flagHistoryTier, "tier", "", "filter by risk tier (safe, caution, dangerous, critical)")
-	historyCmd.Flags().StringVar(&flagHistorySince, "since", "", "only show requests after this date (RFC3339 or YYYY-MM-DD)")
+	historyCmd.Flags().StringVar(&flagHistoryTier, "tier", "", "filter by risk tier (caution, dangerous, critical)")
+	historyCmd.Flags().StringVar(&flagHistorySince, "since", "", "only show requests after this time (7d, 12h, YYYY-MM-DD or RFC3339)")
+	historyCmd.Flags().StringVar(&flagHistorySaved, "saved", "", "run a saved query from history.saved_queries")
+	historyCmd.Flags().StringVar(&flagHistoryCursor, "cursor", "", "continue after a previous page (printed to stderr)")
 	historyCmd.Flags().IntVar(&flagHistoryLimit, "limit", 50, "max results to return")
 
 	rootCmd.AddCommand(historyCmd)
@@ -35,43 +42,68 @@ var historyCmd = &cobra.Command{
 	Short: "Browse and search request history",
 	Long: `Browse and search command approval request history.
 
+Queries are filtered in the database. A query combines field:value terms
+and free-text words (matched with the full-text index):
+
+  tier:critical            risk tier; lists (tier:critical,dangerous) and
+                           comparisons (tier:>=dangerous) work
+  status:executed          request status
+  agent:BlueLake           requestor agent (model: for the requestor model)
+  reviewer:GreenHill       requests reviewed by an agent
+  since:7d  until:2025-12-01
+                           created after/before (30m, 12h, 7d, 2w, a date or RFC3339)
+  cmd:"kubectl delete"     command contains text (reason: for the justification)
+  exit:!=0                 exit code (=, !=, <, <=, >, >=)
+  problems:true            an outcome reported problems
+  project:/path            another project (default: the current one)
+  id:abc123                request ID prefix
+  @name                    a saved query from history.saved_queries
+
+Prefix a term with "-" to negate it. When more results exist, the cursor for
+the next page is printed to stderr.
+
 Examples:
-  slb history                          # Show recent requests
-  slb history -q "rm -rf"              # Search for commands containing "rm -rf"
-  slb history --status executed        # Show only executed requests
-  slb history --tier critical          # Show only critical tier requests
-  slb history --agent "BrownStone"     # Show requests from specific agent
-  slb history --since 2025-12-01       # Show requests since date`,
+  slb history                                      # Show recent requests
+  slb history -q "rm -rf"                          # Search for commands containing "rm -rf"
+  slb history -q 'tier:critical exit:!=0 since:7d' # Critical failures this week
+  slb history -q 'cmd:"kubectl delete" problems:true'
+  slb history --saved failures                     # Run a saved query
+  slb history --status executed                    # Show only executed requests
+  slb history --agent "BrownStone"                 # Show requests from specific agent
+  slb history --since 2025-12-01                   # Show requests since date`,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		dbConn, err := db.Open(GetDB())
+		project, err := projectPath()
 		if err != nil {
-			return fmt.Errorf("opening database: %w", err)
+			return fmt.Errorf("getting project path: %w", err)
+		}
+		cfg, err := config.Load(config.LoadOptions{
+			ProjectDir: project,
+			ConfigPath: flagConfig,
+		})
+		if err != nil {
+			return fmt.Errorf("loading config: %w", err)
 		}
-		defer dbConn.Close()
-
-		var requests []*db.Request
 
-		// If query is provided, use FTS search
-		if flagHistoryQuery != "" {
-			requests, err = dbConn.SearchRequests(flagHistoryQuery)
-			if err != nil {
-				return fmt.Errorf("searching requests: %w", err)
-			}
-		} else {
-			// Use filtered listing
-			requests, err = listRequestsWithFilters(dbConn)
-			if err != nil {
-				return fmt.Errorf("listing requests: %w", err)
-			}
+		query, err := db.ParseRequestQuery(historyQueryString(), cfg.History.SavedQueries)
+		if err != nil {
+			return fmt.Errorf("parsing query: %w", err)
 		}
 
-		// Apply additional filters
-		requests = applyHistoryFilters(requests)
+		dbConn, err := db.Open(GetDB())
+		if err != nil {
+			return fmt.Errorf("opening database: %w", err)
+		}
+		defer dbConn.Close()
 
-		// Limit results
-		if len(requests) > flagHistoryLimit {
-			requests = requests[:flagHistoryLimit]
+		page, err := dbConn.QueryRequests(query, db.QueryOptions{
+			ProjectPath: project,
+			Limit:       flagHistoryLimit,
+			Cursor:      flagHistoryCursor,
+		})
+		if err != nil {
+			return fmt.Errorf("querying history: %w", err)
 		}
+		requests := page.Requests
 
 		// Build response
 		type historyView struct {
@@ -106,81 +138,34 @@ Examples:
 			resp = append(resp, view)
 		}
 
+		if page.NextCursor != "" {
+			fmt.Fprintf(os.Stderr, "%d of %d results; next page: --cursor %s\n", len(resp), page.Total, page.NextCursor)
+		}
+
 		out := output.New(output.Format(GetOutput()))
 		return out.Write(resp)
 	},
 }
 
-// listRequestsWithFilters retrieves requests with basic filtering.
-// For now this returns all requests - we could add more DB-level filtering.
-func listRequestsWithFilters(dbConn *db.DB) ([]*db.Request, error) {
-	project, _ := projectPath()
-
-	// If status filter is set, use status-based listing
-	if flagHistoryStatus != "" {
-		status := db.RequestStatus(flagHistoryStatus)
-		return dbConn.ListRequestsByStatus(status, project)
+// historyQueryString combines --saved, --query and the shorthand filter
+// flags into one query.
+func historyQueryString() string {
+	var parts []string
+	if flagHistorySaved != "" {
+		parts = append(parts, "@"+flagHistorySaved)
 	}
-
-	// Otherwise get all pending (and use in-memory filtering)
-	// Note: This is a simplification - in production we'd want a more flexible DB query
-	pending, err := dbConn.ListPendingRequests(project)
-	if err != nil {
-		return nil, err
+	if flagHistoryQuery != "" {
+		parts = append(parts, flagHistoryQuery)
 	}
-
-	// Also get from FTS with wildcard to get all
-	// Note: This is a workaround - ideally we'd have a ListAllRequests function
-	all, err := dbConn.SearchRequests("*")
-	if err != nil {
-		// FTS might not work with wildcard, fall back to pending only
-		return pending, nil
-	}
-
-	return all, nil
-}
-
-// applyHistoryFilters applies in-memory filters to requests.
-func applyHistoryFilters(requests []*db.Request) []*db.Request {
-	result := make([]*db.Request, 0, len(requests))
-
-	var sinceTime time.Time
-	if flagHistorySince != "" {
-		// Try parsing as RFC3339 first, then as date only
-		var err error
-		sinceTime, err = time.Parse(time.RFC3339, flagHistorySince)
-		if err != nil {
-			sinceTime, err = time.Parse("2006-01-02", flagHistorySince)
-			if err != nil {
-				// Invalid date, ignore filter
-				sinceTime = time.Time{}
-			}
+	for _, f := range []struct{ field, value string }{
+		{"status", flagHistoryStatus},
+		{"agent", flagHistoryAgent},
+		{"tier", flagHistoryTier},
+		{"since", flagHistorySince},
+	} {
+		if f.value != "" {
+			parts = append(parts, f.field+`:"`+f.value+`"`)
 		}
 	}
-
-	for _, r := range requests {
-		// Filter by status
-		if flagHistoryStatus != "" && string(r.Status) != flagHistoryStatus {
-			continue
-		}
-
-		// Filter by agent
-		if flagHistoryAgent != "" && r.RequestorAgent != flagHistoryAgent {
-			continue
-		}
-
-		// Filter by tier
-		if flagHistoryTier != "" && string(r.RiskTier) != flagHistoryTier {
-			continue
-		}
-
-		// Filter by since
-		if !sinceTime.IsZero() && r.CreatedAt.Before(sinceTime) {
-			continue
-		}
-
-		result = append(result, r)
-	}
-
-	return result
+	return strings.Join(parts, " ")
 }
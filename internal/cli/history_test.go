@@ -3,6 +3,8 @@ package cli
 import (
 	"encoding/json"
 	"fmt"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 	"time"
@@ -36,6 +38,8 @@ func newTestHistoryCmd(dbPath string) *cobra.Command {
 	histCmd.Flags().StringVar(&flagHistoryAgent, "agent", "", "filter by agent")
 	histCmd.Flags().StringVar(&flagHistoryTier, "tier", "", "filter by risk tier")
 	histCmd.Flags().StringVar(&flagHistorySince, "since", "", "filter by date")
+	histCmd.Flags().StringVar(&flagHistorySaved, "saved", "", "saved query")
+	histCmd.Flags().StringVar(&flagHistoryCursor, "cursor", "", "page cursor")
 	histCmd.Flags().IntVar(&flagHistoryLimit, "limit", 50, "max results")
 
 	root.AddCommand(histCmd)
@@ -53,6 +57,8 @@ func resetHistoryFlags() {
 	flagHistoryAgent = ""
 	flagHistoryTier = ""
 	flagHistorySince = ""
+	flagHistorySaved = ""
+	flagHistoryCursor = ""
 	flagHistoryLimit = 50
 }
 
@@ -326,117 +332,115 @@ func TestHistoryCommand_Help(t *testing.T) {
 	}
 }
 
-func TestApplyHistoryFilters(t *testing.T) {
-	// Test the in-memory filtering function
-	requests := []*db.Request{
-		{ID: "1", Status: db.StatusPending, RequestorAgent: "Agent1", RiskTier: db.RiskTierDangerous},
-		{ID: "2", Status: db.StatusApproved, RequestorAgent: "Agent2", RiskTier: db.RiskTierCritical},
-		{ID: "3", Status: db.StatusRejected, RequestorAgent: "Agent1", RiskTier: db.RiskTierCritical},
-	}
-
-	tests := []struct {
-		name     string
-		status   string
-		agent    string
-		tier     string
-		expected int
-	}{
-		{"no filters", "", "", "", 3},
-		{"filter by status", "pending", "", "", 1},
-		{"filter by agent", "", "Agent1", "", 2},
-		{"filter by tier", "", "", "critical", 2},
-		{"multiple filters", "approved", "Agent2", "critical", 1},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			flagHistoryStatus = tt.status
-			flagHistoryAgent = tt.agent
-			flagHistoryTier = tt.tier
-			flagHistorySince = ""
-
-			result := applyHistoryFilters(requests)
-			if len(result) != tt.expected {
-				t.Errorf("expected %d results, got %d", tt.expected, len(result))
-			}
-		})
-	}
-
-	// Reset flags
+func TestHistoryQueryString(t *testing.T) {
 	resetHistoryFlags()
-}
+	defer resetHistoryFlags()
 
-func TestApplyHistoryFilters_SinceDateRFC3339(t *testing.T) {
-	now := time.Now()
-	old := now.Add(-48 * time.Hour)
-	recent := now.Add(-1 * time.Hour)
+	flagHistorySaved = "failures"
+	flagHistoryQuery = "cmd:rm"
+	flagHistoryStatus = "executed"
+	flagHistoryAgent = "Blue Lake"
+	flagHistoryTier = "critical"
+	flagHistorySince = "7d"
 
-	requests := []*db.Request{
-		{ID: "1", Status: db.StatusPending, CreatedAt: old},
-		{ID: "2", Status: db.StatusPending, CreatedAt: recent},
+	want := `@failures cmd:rm status:"executed" agent:"Blue Lake" tier:"critical" since:"7d"`
+	if got := historyQueryString(); got != want {
+		t.Errorf("historyQueryString() = %q, want %q", got, want)
 	}
+}
 
-	// Filter to only recent (since 24 hours ago)
-	flagHistoryStatus = ""
-	flagHistoryAgent = ""
-	flagHistoryTier = ""
-	flagHistorySince = now.Add(-24 * time.Hour).Format(time.RFC3339)
+func TestHistoryCommand_InvalidQuery(t *testing.T) {
+	h := testutil.NewHarness(t)
+	resetHistoryFlags()
 
-	result := applyHistoryFilters(requests)
-	if len(result) != 1 {
-		t.Errorf("expected 1 result with since filter, got %d", len(result))
-	}
-	if len(result) > 0 && result[0].ID != "2" {
-		t.Errorf("expected request 2, got %s", result[0].ID)
+	for _, args := range [][]string{
+		{"--since", "invalid-date"},
+		{"-q", "tier:extreme"},
+		{"--saved", "missing"},
+		{"--cursor", "bogus"},
+	} {
+		cmd := newTestHistoryCmd(h.DBPath)
+		_, err := executeCommandCapture(t, cmd, append([]string{"history", "-C", h.ProjectDir, "-j"}, args...)...)
+		if err == nil {
+			t.Errorf("history %v: expected error", args)
+		}
+		resetHistoryFlags()
 	}
-
-	resetHistoryFlags()
 }
 
-func TestApplyHistoryFilters_SinceDateOnly(t *testing.T) {
-	now := time.Now()
-	old := now.Add(-48 * time.Hour)
-	recent := now.Add(-1 * time.Hour)
+func TestHistoryCommand_QueryLanguageAndCursor(t *testing.T) {
+	h := testutil.NewHarness(t)
+	resetHistoryFlags()
 
-	requests := []*db.Request{
-		{ID: "1", Status: db.StatusPending, CreatedAt: old},
-		{ID: "2", Status: db.StatusPending, CreatedAt: recent},
+	slbDir := filepath.Join(h.ProjectDir, ".slb")
+	if err := os.MkdirAll(slbDir, 0o755); err != nil {
+		t.Fatal(err)
 	}
-
-	// Filter using date-only format
-	flagHistoryStatus = ""
-	flagHistoryAgent = ""
-	flagHistoryTier = ""
-	flagHistorySince = now.Format("2006-01-02")
-
-	result := applyHistoryFilters(requests)
-	// Both should pass since they're on or after today's date
-	// Actually, the old one is 2 days ago so it should fail
-	if len(result) != 1 {
-		t.Errorf("expected 1 result with date-only since filter, got %d", len(result))
+	config := "[history.saved_queries]\ncritical = \"tier:critical\"\n"
+	if err := os.WriteFile(filepath.Join(slbDir, "config.toml"), []byte(config), 0o644); err != nil {
+		t.Fatal(err)
 	}
 
-	resetHistoryFlags()
-}
-
-func TestApplyHistoryFilters_InvalidSinceDate(t *testing.T) {
-	requests := []*db.Request{
-		{ID: "1", Status: db.StatusPending, CreatedAt: time.Now()},
+	sess := testutil.MakeSession(t, h.DB,
+		testutil.WithProject(h.ProjectDir),
+		testutil.WithAgent("TestAgent"),
+	)
+	for i := 0; i < 3; i++ {
+		testutil.MakeRequest(t, h.DB, sess,
+			testutil.WithCommand(fmt.Sprintf("kubectl delete pod web-%d", i), h.ProjectDir, true),
+			testutil.WithRisk(db.RiskTierCritical),
+		)
+	}
+	failed := testutil.MakeRequest(t, h.DB, sess,
+		testutil.WithCommand("git push --force", h.ProjectDir, true),
+		testutil.WithRisk(db.RiskTierDangerous),
+	)
+	exit := 1
+	if err := h.DB.UpdateRequestExecution(failed.ID, &db.Execution{ExitCode: &exit}); err != nil {
+		t.Fatalf("UpdateRequestExecution: %v", err)
+	}
+
+	run := func(args ...string) []map[string]any {
+		t.Helper()
+		resetHistoryFlags()
+		cmd := newTestHistoryCmd(h.DBPath)
+		stdout, err := executeCommandCapture(t, cmd, append([]string{"history", "-C", h.ProjectDir, "-j"}, args...)...)
+		if err != nil {
+			t.Fatalf("history %v: %v", args, err)
+		}
+		var result []map[string]any
+		if err := json.Unmarshal([]byte(stdout), &result); err != nil {
+			t.Fatalf("failed to parse JSON: %v\nstdout: %s", err, stdout)
+		}
+		return result
 	}
 
-	// Invalid date should be ignored
-	flagHistoryStatus = ""
-	flagHistoryAgent = ""
-	flagHistoryTier = ""
-	flagHistorySince = "invalid-date"
-
-	result := applyHistoryFilters(requests)
-	// Should return all since invalid date is ignored
-	if len(result) != 1 {
-		t.Errorf("expected 1 result with invalid since filter (ignored), got %d", len(result))
+	if got := run("-q", "exit:!=0"); len(got) != 1 || got[0]["request_id"] != failed.ID {
+		t.Errorf("exit:!=0 returned %v", got)
+	}
+	if got := run("-q", `cmd:"kubectl delete" -tier:dangerous`); len(got) != 3 {
+		t.Errorf("cmd query returned %d results, want 3", len(got))
+	}
+	if got := run("--saved", "critical"); len(got) != 3 {
+		t.Errorf("saved query returned %d results, want 3", len(got))
 	}
 
-	resetHistoryFlags()
+	// Walk all four requests two at a time using the keyset cursor.
+	firstPage, err := h.DB.QueryRequests(db.RequestQuery{}, db.QueryOptions{ProjectPath: h.ProjectDir, Limit: 2})
+	if err != nil || firstPage.NextCursor == "" {
+		t.Fatalf("QueryRequests: %+v, %v", firstPage, err)
+	}
+	second := run("--limit", "2", "--cursor", firstPage.NextCursor)
+	if len(second) != 2 {
+		t.Fatalf("second page has %d results, want 2", len(second))
+	}
+	for _, r := range firstPage.Requests {
+		for _, s := range second {
+			if s["request_id"] == r.ID {
+				t.Errorf("request %s appears on both pages", r.ID)
+			}
+		}
+	}
 }
 
 func TestHistoryCommand_FilterByStatusApproved(t *testing.T) {
@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/Dicklesworthstone/slb/internal/config"
+	"github.com/Dicklesworthstone/slb/internal/core"
 	"github.com/Dicklesworthstone/slb/internal/db"
 	"github.com/Dicklesworthstone/slb/internal/output"
 	"github.com/spf13/cobra"
@@ -220,11 +222,25 @@ Shows:
 var outcomeAgentStatsCmd = &cobra.Command{
 	Use:   "agent-stats <agent-name>",
 	Short: "Show statistics for a specific agent",
-	Long:  `Display request and outcome statistics for a specific agent.`,
-	Args:  cobra.ExactArgs(1),
+	Long: `Display request and outcome statistics for a specific agent, with its
+reputation: a 0-100 score from approvals, rejections, failed executions and
+outcomes that caused problems, weighted toward recent requests.`,
+	Args: cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		agentName := args[0]
 
+		project, err := projectPath()
+		if err != nil {
+			return fmt.Errorf("getting project path: %w", err)
+		}
+		cfg, err := config.Load(config.LoadOptions{
+			ProjectDir: project,
+			ConfigPath: flagConfig,
+		})
+		if err != nil {
+			return fmt.Errorf("loading config: %w", err)
+		}
+
 		dbConn, err := db.Open(GetDB())
 		if err != nil {
 			return fmt.Errorf("opening database: %w", err)
@@ -235,6 +251,10 @@ var outcomeAgentStatsCmd = &cobra.Command{
 		if err != nil {
 			return fmt.Errorf("getting agent stats: %w", err)
 		}
+		reputation, err := core.AgentReputation(dbConn, agentName, toReputationConfig(cfg), time.Now())
+		if err != nil {
+			return fmt.Errorf("scoring agent reputation: %w", err)
+		}
 
 		out := output.New(output.Format(GetOutput()))
 		return out.Write(map[string]any{
@@ -244,6 +264,7 @@ var outcomeAgentStatsCmd = &cobra.Command{
 			"rejected_count":  stats.RejectedCount,
 			"executed_count":  stats.ExecutedCount,
 			"problematic_pct": stats.ProblematicPct,
+			"reputation":      reputation,
 		})
 	},
 }
@@ -21,6 +21,7 @@ func newTestOutcomeCmd(dbPath string) *cobra.Command {
 	root.PersistentFlags().StringVar(&flagDB, "db", dbPath, "database path")
 	root.PersistentFlags().StringVarP(&flagOutput, "output", "o", "text", "output format")
 	root.PersistentFlags().BoolVarP(&flagJSON, "json", "j", false, "json output")
+	root.PersistentFlags().StringVarP(&flagProject, "project", "C", "", "project directory")
 
 	// Create a fresh outcome command tree
 	outCmd := &cobra.Command{
@@ -53,7 +54,13 @@ func newTestOutcomeCmd(dbPath string) *cobra.Command {
 		RunE:  outcomeStatsCmd.RunE,
 	}
 
-	outCmd.AddCommand(recordCmd, listCmd, statsCmd)
+	agentStatsCmd := &cobra.Command{
+		Use:  "agent-stats <agent-name>",
+		Args: cobra.ExactArgs(1),
+		RunE: outcomeAgentStatsCmd.RunE,
+	}
+
+	outCmd.AddCommand(recordCmd, listCmd, statsCmd, agentStatsCmd)
 	root.AddCommand(outCmd)
 
 	return root
@@ -63,6 +70,7 @@ func resetOutcomeFlags() {
 	flagDB = ""
 	flagOutput = "text"
 	flagJSON = false
+	flagProject = ""
 	outcomeProblems = false
 	outcomeDescription = ""
 	outcomeRating = 0
@@ -252,6 +260,40 @@ func TestOutcomeStatsCommand_ShowsStats(t *testing.T) {
 	}
 }
 
+func TestOutcomeAgentStatsCommand_ShowsReputation(t *testing.T) {
+	h := testutil.NewHarness(t)
+	resetOutcomeFlags()
+
+	sess := testutil.MakeSession(t, h.DB, testutil.WithProject(h.ProjectDir), testutil.WithAgent("BlueLake"))
+	req := testutil.MakeRequest(t, h.DB, sess, testutil.WithStatus(db.StatusExecuted))
+	if err := h.DB.CreateOutcome(&db.ExecutionOutcome{RequestID: req.ID, CausedProblems: true}); err != nil {
+		t.Fatalf("CreateOutcome: %v", err)
+	}
+
+	cmd := newTestOutcomeCmd(h.DBPath)
+	stdout, err := executeCommandCapture(t, cmd, "outcome", "agent-stats", "BlueLake", "-C", h.ProjectDir, "-j")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var result struct {
+		TotalRequests int `json:"total_requests"`
+		Reputation    struct {
+			Subject string  `json:"subject"`
+			Score   float64 `json:"score"`
+			Level   string  `json:"level"`
+			Samples int     `json:"samples"`
+		} `json:"reputation"`
+	}
+	if err := json.Unmarshal([]byte(stdout), &result); err != nil {
+		t.Fatalf("failed to parse JSON: %v\nstdout: %s", err, stdout)
+	}
+	rep := result.Reputation
+	if result.TotalRequests != 1 || rep.Subject != "BlueLake" || rep.Samples != 1 || rep.Level != "unknown" || rep.Score >= 100 {
+		t.Errorf("unexpected agent stats: %+v", result)
+	}
+}
+
 func TestOutcomeCommand_Help(t *testing.T) {
 	h := testutil.NewHarness(t)
 	resetOutcomeFlags()
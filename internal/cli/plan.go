@@ -0,0 +1,109 @@
+// Package cli implements running and reporting multi-step plans.
+package cli
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/Dicklesworthstone/slb/internal/core"
+	"github.com/Dicklesworthstone/slb/internal/db"
+	"github.com/Dicklesworthstone/slb/internal/output"
+	"github.com/Dicklesworthstone/slb/internal/sandbox"
+	"github.com/Dicklesworthstone/slb/internal/scrub"
+	"github.com/spf13/cobra"
+)
+
+// runSafePlan runs a plan whose steps are all safe without a request, like
+// runSafeCommand does for a single command.
+func runSafePlan(cmd *cobra.Command, out *output.Writer, plan *db.Plan, project string, sandboxSpec *db.SandboxSpec, ptyOpts *core.PTYOptions, scrubber *scrub.Scrubber) (int, error) {
+	command := core.PlanSummary(plan)
+	logPath, err := createRunLogFile(project, "safe")
+	if err != nil {
+		return 0, writeError(cmd, out, "log_create_failed", command, err)
+	}
+
+	opts := core.PlanRunOptions{
+		LogPath:  logPath,
+		PTY:      ptyOpts,
+		Scrubber: scrubber,
+		Sandbox:  sandboxSpec,
+	}
+	if GetOutput() != "json" {
+		opts.Stream = os.Stdout
+	}
+	if sandboxSpec != nil {
+		if err := sandbox.Available(); err != nil {
+			return 0, writeError(cmd, out, "sandbox_unavailable", command, err)
+		}
+		opts.StepTimeout = time.Duration(sandboxSpec.TimeoutSeconds) * time.Second
+	}
+
+	result, execErr := core.RunPlan(cmd.Context(), plan, opts)
+
+	resp := map[string]any{
+		"status":           "executed",
+		"command":          command,
+		"exit_code":        result.ExitCode,
+		"duration_ms":      result.Duration.Milliseconds(),
+		"log_path":         logPath,
+		"tier":             "safe",
+		"skipped_approval": true,
+		"steps":            result.Steps,
+	}
+	if len(result.SandboxViolations) > 0 {
+		resp["sandbox_violations"] = result.SandboxViolations
+	}
+	if len(result.AutoAnswers) > 0 {
+		resp["auto_answers"] = result.AutoAnswers
+	}
+	if totalRedactions(result.Redactions) > 0 {
+		resp["redactions"] = result.Redactions
+	}
+	if execErr != nil {
+		resp["error"] = execErr.Error()
+	}
+
+	if GetOutput() == "json" {
+		_ = out.Write(resp)
+		if execErr != nil {
+			return 1, nil
+		}
+		return result.ExitCode, nil
+	}
+
+	printPlanSteps(result.Steps)
+	if execErr != nil {
+		fmt.Fprintf(os.Stderr, "[slb] Execution failed: %s\n", execErr.Error())
+		return 1, nil
+	}
+	printSandboxViolations(result.SandboxViolations)
+	printAutoAnswers(result.AutoAnswers)
+	printRedactions(result.Redactions)
+	if result.ExitCode != 0 {
+		fmt.Fprintf(os.Stderr, "\n[slb] Plan stopped with exit code %d\n", result.ExitCode)
+		return result.ExitCode, nil
+	}
+	return 0, nil
+}
+
+// printPlanSteps reports each step's outcome on stderr.
+func printPlanSteps(steps []db.PlanStepResult) {
+	if len(steps) == 0 {
+		return
+	}
+	fmt.Fprintf(os.Stderr, "\n[slb] Plan steps:\n")
+	writePlanSteps(os.Stderr, steps)
+}
+
+// writePlanSteps writes one line per step result.
+func writePlanSteps(w io.Writer, steps []db.PlanStepResult) {
+	for _, s := range steps {
+		fmt.Fprintf(w, "  %d. [%s] %s", s.Step, s.Status, s.Command)
+		if s.ExitCode != nil {
+			fmt.Fprintf(w, " (exit %d, %dms)", *s.ExitCode, s.DurationMs)
+		}
+		fmt.Fprintln(w)
+	}
+}
@@ -0,0 +1,215 @@
+// Package cli implements the policy command for policy-as-code rules.
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/Dicklesworthstone/slb/internal/config"
+	"github.com/Dicklesworthstone/slb/internal/core"
+	"github.com/Dicklesworthstone/slb/internal/db"
+	"github.com/Dicklesworthstone/slb/internal/output"
+	"github.com/Dicklesworthstone/slb/internal/policy"
+	"github.com/spf13/cobra"
+)
+
+var (
+	flagPolicyAgent  string
+	flagPolicyModel  string
+	flagPolicyReason string
+	flagPolicyAt     string
+)
+
+func init() {
+	policyTestCmd.Flags().StringVar(&flagPolicyAgent, "agent", "", "agent name to test with (default: the --session-id session's)")
+	policyTestCmd.Flags().StringVar(&flagPolicyModel, "model", "", "model to test with (default: the --session-id session's)")
+	policyTestCmd.Flags().StringVarP(&flagPolicyReason, "reason", "r", "", "justification reason to test with")
+	policyTestCmd.Flags().StringVar(&flagPolicyAt, "at", "", "evaluate at this time (RFC3339, or a duration from now such as 10h)")
+
+	policyCmd.AddCommand(policyTestCmd)
+	rootCmd.AddCommand(policyCmd)
+}
+
+var policyCmd = &cobra.Command{
+	Use:   "policy",
+	Short: "Test the approval policy",
+	Long: `Approval policy rules decide the approvals a request needs, who may
+approve and execute it, and whether it is denied.
+
+The default policy is compiled from the configuration (patterns.<tier>.min_approvals,
+general.require_different_model, agents.blocked); rules from the policy file
+(general.policy_file) are added to it and can only tighten it. The
+project's .slb/policy.toml is used instead only when the system config
+locks general.project_policy = true:
+
+  timezone = "Europe/Berlin"
+
+  [[rule]]
+  name = "prod-kube"
+  when = 'env["kube.context"].startsWith("prod") && classification.tier != "caution"'
+  min_approvals = 3
+  reviewer_models = ["opus*", "gpt-5*"]
+  executors = ["deploy-*"]
+
+  [[rule]]
+  name = "no-night-deletes"
+  when = 'request.command.contains("delete") && (time.hour < 7 || time.hour >= 22)'
+  deny = true
+  reason = "deletions wait for office hours"
+
+Examples:
+  slb policy test "kubectl delete namespace staging"
+  slb policy test "rm -rf ./build" --agent BlueDog --model gpt-5 --at 2026-10-18T23:00:00+02:00`,
+}
+
+var policyTestCmd = &cobra.Command{
+	Use:   "test \"<command>\"",
+	Short: "Show which policy rules fire for a command",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		command := args[0]
+
+		project, err := projectPath()
+		if err != nil {
+			return err
+		}
+		cfg, prov, err := config.LoadWithProvenance(config.LoadOptions{
+			ProjectDir: project,
+			ConfigPath: flagConfig,
+		})
+		if err != nil {
+			return fmt.Errorf("loading config: %w", err)
+		}
+		pol, policyFile, err := loadPolicy(cfg, prov, project)
+		if err != nil {
+			return err
+		}
+		now := time.Now()
+		at, err := parseWindowTime("--at", flagPolicyAt, now)
+		if err != nil {
+			return err
+		}
+		if at != nil {
+			now = *at
+		}
+
+		cwd, err := os.Getwd()
+		if err != nil {
+			cwd = project
+		}
+
+		dbConn, err := db.OpenAndMigrate(GetDB())
+		if err != nil {
+			return fmt.Errorf("opening database: %w", err)
+		}
+		defer dbConn.Close()
+
+		session := &db.Session{ID: flagSessionID}
+		if flagSessionID != "" {
+			if session, err = dbConn.GetSession(flagSessionID); err != nil {
+				return fmt.Errorf("getting session: %w", err)
+			}
+		}
+		if flagPolicyAgent != "" {
+			session.AgentName = flagPolicyAgent
+		}
+		if flagPolicyModel != "" {
+			session.Model = flagPolicyModel
+		}
+
+		out := output.New(output.Format(GetOutput()))
+		resp := map[string]any{
+			"command":      command,
+			"project_path": project,
+			"policy_file":  policyFile,
+		}
+		classification := core.GetDefaultEngine().ClassifyCommand(command, cwd)
+		resp["tier"] = string(classification.Tier)
+		if classification.IsSafe || !classification.NeedsApproval {
+			resp["status"] = "no_request"
+			resp["message"] = "command needs no approval; policy rules are not evaluated"
+			return out.Write(resp)
+		}
+
+		var environment db.EnvironmentFingerprint
+		if cfg.General.BindEnvironment {
+			environment = core.CaptureEnvironment(cwd, cfg.General.FingerprintEnvVars)
+		}
+		in := core.PolicyInput(core.CreateRequestOptions{
+			Command:       command,
+			Cwd:           cwd,
+			Shell:         true,
+			Justification: core.Justification{Reason: flagPolicyReason},
+		}, session, classification, project, environment, now)
+		if in.Reviewers, err = core.PolicyReviewers(dbConn, project, session.ID); err != nil {
+			return err
+		}
+
+		decision, err := pol.Evaluate(in)
+		if err != nil {
+			return err
+		}
+		resp["status"] = "allowed"
+		if decision.Denial != nil {
+			resp["status"] = "denied"
+			resp["error"] = decision.Err().Error()
+		}
+		resp["fired"] = decision.Fired
+		resp["min_approvals"] = decision.MinApprovals
+		resp["require_different_model"] = decision.RequireDifferentModel
+		if len(decision.Reviewers) > 0 {
+			resp["reviewers"] = decision.Reviewers
+		}
+		if len(decision.Executors) > 0 {
+			resp["executors"] = decision.Executors
+		}
+		return out.Write(resp)
+	},
+}
+
+// loadPolicy returns the policy compiled from cfg, extended with the rules
+// of the policy file, and the path of that file ("" when there is none).
+// Without general.policy_file, .slb/policy.toml is only loaded when the
+// system config locks general.project_policy to true: agents can write it.
+func loadPolicy(cfg config.Config, prov *config.Provenance, project string) (*policy.Policy, string, error) {
+	pol := policy.Default(policy.DefaultsFromConfig(cfg))
+	path := cfg.General.PolicyFile
+	required := path != ""
+	if !required {
+		if !cfg.General.ProjectPolicy || prov.Lock("general.project_policy") != config.LockLocked {
+			return pol, "", nil
+		}
+		path = filepath.Join(".slb", "policy.toml")
+	}
+	if !filepath.IsAbs(path) && project != "" {
+		path = filepath.Join(project, path)
+	}
+	file, err := policy.LoadFile(path)
+	if errors.Is(err, os.ErrNotExist) && !required {
+		return pol, "", nil
+	}
+	if err != nil {
+		return nil, "", fmt.Errorf("loading policy: %w", err)
+	}
+	pol, err = pol.Extend(file)
+	if err != nil {
+		return nil, "", fmt.Errorf("policy %s: %w", path, err)
+	}
+	return pol, path, nil
+}
+
+// describePolicyConstraint summarizes a reviewer or executor constraint.
+func describePolicyConstraint(c db.PolicyConstraint) string {
+	var parts []string
+	if len(c.Agents) > 0 {
+		parts = append(parts, "agents "+strings.Join(c.Agents, ", "))
+	}
+	if len(c.Models) > 0 {
+		parts = append(parts, "models "+strings.Join(c.Models, ", "))
+	}
+	return fmt.Sprintf("%s (rule %s)", strings.Join(parts, "; "), c.Rule)
+}
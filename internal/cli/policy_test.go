@@ -0,0 +1,138 @@
+package cli
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Dicklesworthstone/slb/internal/config"
+	"github.com/Dicklesworthstone/slb/internal/testutil"
+	"github.com/spf13/cobra"
+)
+
+func newTestPolicyCmd(dbPath string) *cobra.Command {
+	root := &cobra.Command{
+		Use:           "slb",
+		SilenceUsage:  true,
+		SilenceErrors: true,
+	}
+
+	root.PersistentFlags().StringVar(&flagDB, "db", dbPath, "database path")
+	root.PersistentFlags().StringVarP(&flagOutput, "output", "o", "text", "output format")
+	root.PersistentFlags().BoolVarP(&flagJSON, "json", "j", false, "json output")
+	root.PersistentFlags().StringVarP(&flagProject, "project", "C", "", "project directory")
+	root.PersistentFlags().StringVarP(&flagSessionID, "session-id", "s", "", "session ID")
+
+	root.AddCommand(policyCmd)
+
+	return root
+}
+
+func resetPolicyFlags() {
+	flagDB = ""
+	flagOutput = "text"
+	flagJSON = false
+	flagProject = ""
+	flagSessionID = ""
+	flagPolicyAgent = ""
+	flagPolicyModel = ""
+	flagPolicyReason = ""
+	flagPolicyAt = ""
+}
+
+func TestPolicyCommand_Test(t *testing.T) {
+	h := testutil.NewHarness(t)
+	resetPolicyFlags()
+	t.Cleanup(resetPolicyFlags)
+
+	policyFile := `
+timezone = "UTC"
+
+[[rule]]
+name = "night"
+when = 'time.hour >= 22 && session.model == "gpt-5"'
+deny = true
+reason = "not at night"
+`
+	if err := os.WriteFile(filepath.Join(h.ProjectDir, ".slb", "policy.toml"), []byte(policyFile), 0644); err != nil {
+		t.Fatalf("writing policy: %v", err)
+	}
+
+	t.Setenv("HOME", t.TempDir())
+	system := filepath.Join(t.TempDir(), "config.toml")
+	old := config.SystemConfigPath
+	config.SystemConfigPath = system
+	t.Cleanup(func() { config.SystemConfigPath = old })
+
+	exec := func(args ...string) (map[string]any, error) {
+		t.Helper()
+		resetPolicyFlags()
+		stdout, err := executeCommandCapture(t, newTestPolicyCmd(h.DBPath), append(append([]string{"policy", "test"}, args...), "-C", h.ProjectDir, "-j")...)
+		if err != nil {
+			return nil, err
+		}
+		var result map[string]any
+		if err := json.Unmarshal([]byte(stdout), &result); err != nil {
+			t.Fatalf("failed to parse JSON: %v\nstdout: %s", err, stdout)
+		}
+		return result, nil
+	}
+	run := func(args ...string) map[string]any {
+		t.Helper()
+		result, err := exec(args...)
+		if err != nil {
+			t.Fatalf("%v: %v", args, err)
+		}
+		return result
+	}
+
+	// The project's policy file is ignored unless the system config allows it.
+	if result := run("rm -rf /etc", "--model", "gpt-5", "--at", "2026-10-16T23:00:00Z"); result["status"] != "allowed" || result["policy_file"] != "" {
+		t.Fatalf("without system lock: %v", result)
+	}
+	if err := os.WriteFile(system, []byte("[general]\nproject_policy = true\n\n[locks]\nlocked = [\"general.project_policy\"]\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	result := run("rm -rf /etc", "--model", "gpt-5", "--at", "2026-10-16T23:00:00Z")
+	if result["status"] != "denied" || result["tier"] != "critical" || !strings.Contains(result["error"].(string), "not at night") {
+		t.Fatalf("night: %v", result)
+	}
+	fired, _ := json.Marshal(result["fired"])
+	for _, rule := range []string{"patterns.critical.min_approvals", "critical.require_different_model", "night"} {
+		if !strings.Contains(string(fired), rule) {
+			t.Errorf("fired = %s, want %s", fired, rule)
+		}
+	}
+
+	result = run("rm -rf /etc", "--model", "gpt-5", "--at", "2026-10-16T12:00:00Z")
+	if result["status"] != "allowed" || result["min_approvals"] != float64(2) || result["require_different_model"] != true {
+		t.Errorf("noon: %v", result)
+	}
+
+	result = run("ls -la")
+	if result["status"] != "no_request" {
+		t.Errorf("safe command: %v", result)
+	}
+
+	// A project policy cannot replace default rules or lower approvals.
+	for name, src := range map[string]string{
+		"replace default": "[[rule]]\nname = \"patterns.critical.min_approvals\"\nmin_approvals = 1",
+		"zero approvals":  "[[rule]]\nname = \"lax\"\nmin_approvals = 0",
+	} {
+		if err := os.WriteFile(filepath.Join(h.ProjectDir, ".slb", "policy.toml"), []byte(src), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := exec("rm -rf /etc"); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(h.ProjectDir, ".slb", "policy.toml"), []byte("[[rule]]\nname = \"lax\"\nmin_approvals = 1"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if result := run("rm -rf /etc"); result["min_approvals"] != float64(2) || result["require_different_model"] != true {
+		t.Errorf("lower file rule: %v", result)
+	}
+}
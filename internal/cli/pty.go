@@ -0,0 +1,43 @@
+// Package cli implements PTY execution settings shared by execute and run.
+package cli
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/Dicklesworthstone/slb/internal/config"
+	"github.com/Dicklesworthstone/slb/internal/core"
+	"github.com/Dicklesworthstone/slb/internal/db"
+)
+
+// ptyOptions returns the pseudo-terminal settings for --pty executions of
+// safe commands, with the configured auto-answers compiled. Approved
+// requests use the auto-answers recorded when they were created.
+func ptyOptions(cfg config.Config) (core.PTYOptions, error) {
+	answers, err := core.CompileAutoAnswers(autoAnswers(cfg))
+	if err != nil {
+		return core.PTYOptions{}, fmt.Errorf("general.pty_auto_answers: %w", err)
+	}
+	return core.PTYOptions{AutoAnswers: answers}, nil
+}
+
+// autoAnswers returns the configured auto-answers, which new requests
+// record for their reviewers.
+func autoAnswers(cfg config.Config) []db.AutoAnswer {
+	var answers []db.AutoAnswer
+	for _, aa := range cfg.General.PTYAutoAnswers {
+		answers = append(answers, db.AutoAnswer{Pattern: aa.Pattern, Answer: aa.Answer})
+	}
+	return answers
+}
+
+// printAutoAnswers reports the prompts answered automatically on stderr.
+func printAutoAnswers(answers []string) {
+	if len(answers) == 0 {
+		return
+	}
+	fmt.Fprintf(os.Stderr, "\n[slb] Auto-answered prompts:\n")
+	for _, a := range answers {
+		fmt.Fprintf(os.Stderr, "  - %s\n", a)
+	}
+}
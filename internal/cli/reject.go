@@ -17,6 +17,11 @@ var (
 	flagRejectReason        string
 	flagRejectComments      string
 	flagRejectTargetProject string
+
+	// Batch selection flags
+	flagRejectFilter  string
+	flagRejectAllFrom string
+	flagRejectYes     bool
 )
 
 func init() {
@@ -25,12 +30,15 @@ func init() {
 	rejectCmd.Flags().StringVarP(&flagRejectReason, "reason", "r", "", "reason for rejection (required)")
 	rejectCmd.Flags().StringVarP(&flagRejectComments, "comments", "m", "", "additional comments")
 	rejectCmd.Flags().StringVar(&flagRejectTargetProject, "target-project", "", "target project path for cross-project rejections")
+	rejectCmd.Flags().StringVar(&flagRejectFilter, "filter", "", "reject every pending request matching key=value[,key=value] (tier, agent, session, model, command glob; * also matches / but not shell separators)")
+	rejectCmd.Flags().StringVar(&flagRejectAllFrom, "all-from", "", "reject every pending request from this requestor session")
+	rejectCmd.Flags().BoolVarP(&flagRejectYes, "yes", "y", false, "skip the batch confirmation")
 
 	rootCmd.AddCommand(rejectCmd)
 }
 
 var rejectCmd = &cobra.Command{
-	Use:   "reject <request-id>",
+	Use:   "reject [request-id]",
 	Short: "Reject a pending request",
 	Long: `Reject a command request, preventing it from being executed.
 
@@ -43,13 +51,23 @@ authenticity.
 For cross-project reviews, use --target-project to specify which project's
 database contains the request you want to reject.
 
+To reject several requests at once, select them with --all-from and/or
+--filter instead of a request ID. The matching commands are listed for
+confirmation (skip with --yes, required with JSON output), then each request
+gets its own signed review carrying the same reason. A command glob
+matches the whole command, and its * also matches "/".
+
 	Examples:
 	  slb reject abc123 -s $SESSION_ID -k $SESSION_KEY -r "Command too dangerous"
 	  slb reject abc123 -s $SESSION_ID -k $SESSION_KEY -r "Justification insufficient" -m "Please add more context"
-	  slb reject abc123 -s $SESSION_ID -k $SESSION_KEY -r "Too risky" --target-project /path/to/other/project`,
-	Args: cobra.ExactArgs(1),
+	  slb reject abc123 -s $SESSION_ID -k $SESSION_KEY -r "Too risky" --target-project /path/to/other/project
+	  slb reject -s $SESSION_ID -k $SESSION_KEY -r "Runaway agent" --all-from $AGENT_SESSION`,
+	Args: batchReviewArgs(&flagRejectFilter, &flagRejectAllFrom),
 	RunE: func(cmd *cobra.Command, args []string) error {
-		requestID := args[0]
+		var requestID string
+		if len(args) > 0 {
+			requestID = args[0]
+		}
 
 		// Validate required flags
 		if flagRejectSessionID == "" {
@@ -98,7 +116,20 @@ database contains the request you want to reject.
 
 		// Create review service and submit
 		reviewSvc := core.NewReviewService(dbConn, core.DefaultReviewConfig())
-		reviewSvc.SetNotifier(buildAgentMailNotifier(project))
+		reviewSvc.SetNotifier(buildRequestNotifier(project))
+
+		if requestID == "" {
+			return runBatchReview(dbConn, batchReview{
+				Project: project,
+				Filter:  flagRejectFilter,
+				AllFrom: flagRejectAllFrom,
+				Yes:     flagRejectYes,
+				Opts:    opts,
+				Service: reviewSvc,
+				In:      cmd.InOrStdin(),
+			})
+		}
+
 		result, err := reviewSvc.SubmitReview(opts)
 		if err != nil {
 			return fmt.Errorf("submitting rejection: %w", err)
@@ -53,6 +53,9 @@ func resetRejectFlags() {
 	flagRejectReason = ""
 	flagRejectComments = ""
 	flagRejectTargetProject = ""
+	flagRejectFilter = ""
+	flagRejectAllFrom = ""
+	flagRejectYes = false
 }
 
 func TestRejectCommand_RequiresRequestID(t *testing.T) {
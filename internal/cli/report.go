@@ -0,0 +1,208 @@
+package cli
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/Dicklesworthstone/slb/internal/config"
+	"github.com/Dicklesworthstone/slb/internal/db"
+	"github.com/Dicklesworthstone/slb/internal/output"
+	"github.com/Dicklesworthstone/slb/internal/report"
+	"github.com/spf13/cobra"
+)
+
+var (
+	flagReportFormat      string
+	flagReportOutputFile  string
+	flagReportSince       string
+	flagReportUntil       string
+	flagReportAgent       string
+	flagReportQuery       string
+	flagReportAllProjects bool
+	flagReportLogDir      string
+)
+
+func init() {
+	reportCmd.Flags().StringVarP(&flagReportFormat, "format", "f", "", "report format: text, json, yaml, csv, jsonl, html (default: --output)")
+	reportCmd.Flags().StringVarP(&flagReportOutputFile, "output", "o", "", "output file (default: stdout)")
+	reportCmd.Flags().StringVar(&flagReportSince, "since", "30d", "start of the period (7d, 12h, YYYY-MM-DD or RFC3339; empty for all time)")
+	reportCmd.Flags().StringVar(&flagReportUntil, "until", "", "end of the period (default: now)")
+	reportCmd.Flags().StringVar(&flagReportAgent, "agent", "", "only requests from this agent")
+	reportCmd.Flags().StringVarP(&flagReportQuery, "query", "q", "", "additional history query (see slb history --help)")
+	reportCmd.Flags().BoolVar(&flagReportAllProjects, "all-projects", false, "include every project in the database")
+	reportCmd.Flags().StringVar(&flagReportLogDir, "emergency-log-dir", "", "emergency execution logs (default: <project>/.slb/logs)")
+
+	rootCmd.AddCommand(reportCmd)
+}
+
+var reportCmd = &cobra.Command{
+	Use:   "report",
+	Short: "Generate a period report of requests, approvals and incidents",
+	Long: `Generate a report over request history for a period.
+
+The report covers requests by risk tier, status and day, time to approval,
+per-agent statistics, executions whose outcome reported problems, and
+emergency executions (read from the emergency-execute logs).
+
+Formats:
+  text, json, yaml   summary on stdout (follows --output/--json by default)
+  csv                one section,key,metric,value row per figure
+  jsonl              one JSON object per line, tagged with "type"
+  html               a self-contained page with charts
+
+Examples:
+  slb report                                  # Last 30 days, this project
+  slb report -f html -o report.html           # Shareable HTML report
+  slb report -f csv --since 2025-11-01 --until 2025-12-01
+  slb report -f jsonl --all-projects --agent BlueLake
+  slb report -q 'tier:critical'               # Only critical requests`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		format := strings.ToLower(flagReportFormat)
+		if format == "" {
+			format = GetOutput()
+		}
+		switch format {
+		case "text", "json", "yaml", "csv", "jsonl", "html":
+		default:
+			return fmt.Errorf("unknown report format %q (use text, json, yaml, csv, jsonl or html)", flagReportFormat)
+		}
+
+		project, err := projectPath()
+		if err != nil {
+			return fmt.Errorf("getting project path: %w", err)
+		}
+		cfg, err := config.Load(config.LoadOptions{
+			ProjectDir: project,
+			ConfigPath: flagConfig,
+		})
+		if err != nil {
+			return fmt.Errorf("loading config: %w", err)
+		}
+
+		now := time.Now()
+		opts := report.Options{
+			ProjectPath:     project,
+			Agent:           flagReportAgent,
+			Query:           flagReportQuery,
+			SavedQueries:    cfg.History.SavedQueries,
+			EmergencyLogDir: flagReportLogDir,
+			Now:             now,
+		}
+		if flagReportAllProjects {
+			opts.ProjectPath = ""
+		}
+		if opts.EmergencyLogDir == "" {
+			opts.EmergencyLogDir = filepath.Join(project, ".slb", "logs")
+		}
+		if flagReportSince != "" {
+			if opts.Since, err = db.ParseQueryTime(flagReportSince, now); err != nil {
+				return fmt.Errorf("--since: %w", err)
+			}
+		}
+		if flagReportUntil != "" {
+			if opts.Until, err = db.ParseQueryTime(flagReportUntil, now); err != nil {
+				return fmt.Errorf("--until: %w", err)
+			}
+		}
+
+		dbConn, err := db.Open(GetDB())
+		if err != nil {
+			return fmt.Errorf("opening database: %w", err)
+		}
+		defer dbConn.Close()
+
+		rep, err := report.Build(dbConn, opts)
+		if err != nil {
+			return fmt.Errorf("building report: %w", err)
+		}
+
+		var buf bytes.Buffer
+		switch format {
+		case "csv":
+			err = report.WriteCSV(&buf, rep)
+		case "jsonl":
+			err = report.WriteJSONL(&buf, rep)
+		case "html":
+			err = report.WriteHTML(&buf, rep)
+		case "text":
+			writeReportText(&buf, rep)
+		default:
+			err = output.New(output.Format(format), output.WithOutput(&buf)).Write(rep)
+		}
+		if err != nil {
+			return fmt.Errorf("rendering report: %w", err)
+		}
+
+		if flagReportOutputFile == "" {
+			_, err = os.Stdout.Write(buf.Bytes())
+			return err
+		}
+		if err := os.WriteFile(flagReportOutputFile, buf.Bytes(), 0644); err != nil {
+			return fmt.Errorf("failed to write file: %w", err)
+		}
+		fmt.Fprintf(os.Stderr, "Wrote %s report to %s\n", format, flagReportOutputFile)
+		return nil
+	},
+}
+
+// writeReportText prints a human-readable summary of the report.
+func writeReportText(buf *bytes.Buffer, r *report.Report) {
+	scope := r.Project
+	if scope == "" {
+		scope = "all projects"
+	}
+	if r.Agent != "" {
+		scope += ", agent " + r.Agent
+	}
+	from, to := "beginning", r.GeneratedAt.Format("2006-01-02 15:04")
+	if r.Since != nil {
+		from = r.Since.Local().Format("2006-01-02 15:04")
+	}
+	if r.Until != nil {
+		to = r.Until.Local().Format("2006-01-02 15:04")
+	}
+	fmt.Fprintf(buf, "Report for %s\n", scope)
+	fmt.Fprintf(buf, "Period: %s – %s\n", from, to)
+	if r.Query != "" {
+		fmt.Fprintf(buf, "Query:  %s\n", r.Query)
+	}
+
+	fmt.Fprintf(buf, "\nRequests: %d\n", r.TotalRequests)
+	for _, c := range r.ByTier {
+		fmt.Fprintf(buf, "  %-12s %d\n", c.Key, c.Count)
+	}
+	if len(r.ByStatus) > 0 {
+		fmt.Fprintln(buf, "By status:")
+		for _, c := range r.ByStatus {
+			fmt.Fprintf(buf, "  %-12s %d\n", c.Key, c.Count)
+		}
+	}
+
+	l := r.ApprovalLatency
+	if l.SampleSize > 0 {
+		fmt.Fprintf(buf, "\nTime to approval (%d approved): avg %.1fm, median %.1fm, min %.1fm, max %.1fm\n",
+			l.SampleSize, l.AvgMinutes, l.MedianMinutes, l.MinMinutes, l.MaxMinutes)
+	}
+
+	if len(r.Agents) > 0 {
+		fmt.Fprintln(buf, "\nAgents:")
+		for _, a := range r.Agents {
+			fmt.Fprintf(buf, "  %-20s %4d requests, %d approved, %d rejected, %d executed, %.1f%% problematic\n",
+				a.Agent, a.TotalRequests, a.ApprovedCount, a.RejectedCount, a.ExecutedCount, a.ProblematicPct)
+		}
+	}
+
+	fmt.Fprintf(buf, "\nOutcomes with problems: %d\n", len(r.Problems))
+	for _, p := range r.Problems {
+		fmt.Fprintf(buf, "  %s [%s] %s: %s\n", p.RequestID, p.RiskTier, p.Command, p.Description)
+	}
+
+	fmt.Fprintf(buf, "\nEmergency executions: %d\n", len(r.Emergencies))
+	for _, e := range r.Emergencies {
+		fmt.Fprintf(buf, "  %s %s: %s (%s)\n", e.ExecutedAt.Local().Format("2006-01-02 15:04"), e.Actor, e.Command, e.Reason)
+	}
+}
@@ -0,0 +1,159 @@
+package cli
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Dicklesworthstone/slb/internal/db"
+	"github.com/Dicklesworthstone/slb/internal/testutil"
+	"github.com/spf13/cobra"
+)
+
+func newTestReportCmd(dbPath string) *cobra.Command {
+	root := &cobra.Command{
+		Use:           "slb",
+		SilenceUsage:  true,
+		SilenceErrors: true,
+	}
+
+	root.PersistentFlags().StringVar(&flagDB, "db", dbPath, "database path")
+	root.PersistentFlags().BoolVarP(&flagJSON, "json", "j", false, "json output")
+	root.PersistentFlags().StringVarP(&flagProject, "project", "C", "", "project directory")
+
+	repCmd := &cobra.Command{
+		Use:  "report",
+		RunE: reportCmd.RunE,
+	}
+	repCmd.Flags().StringVarP(&flagReportFormat, "format", "f", "", "report format")
+	repCmd.Flags().StringVarP(&flagReportOutputFile, "output", "o", "", "output file")
+	repCmd.Flags().StringVar(&flagReportSince, "since", "30d", "start of the period")
+	repCmd.Flags().StringVar(&flagReportUntil, "until", "", "end of the period")
+	repCmd.Flags().StringVar(&flagReportAgent, "agent", "", "agent")
+	repCmd.Flags().StringVarP(&flagReportQuery, "query", "q", "", "query")
+	repCmd.Flags().BoolVar(&flagReportAllProjects, "all-projects", false, "all projects")
+	repCmd.Flags().StringVar(&flagReportLogDir, "emergency-log-dir", "", "emergency logs")
+
+	root.AddCommand(repCmd)
+	return root
+}
+
+func resetReportFlags() {
+	flagDB = ""
+	flagOutput = "text"
+	flagJSON = false
+	flagProject = ""
+	flagReportFormat = ""
+	flagReportOutputFile = ""
+	flagReportSince = "30d"
+	flagReportUntil = ""
+	flagReportAgent = ""
+	flagReportQuery = ""
+	flagReportAllProjects = false
+	flagReportLogDir = ""
+}
+
+func TestReportCommand(t *testing.T) {
+	h := testutil.NewHarness(t)
+	resetReportFlags()
+
+	sess := testutil.MakeSession(t, h.DB, testutil.WithProject(h.ProjectDir), testutil.WithAgent("BlueLake"))
+	executed := testutil.MakeRequest(t, h.DB, sess,
+		testutil.WithCommand("rm -rf ./build", h.ProjectDir, true),
+		testutil.WithRisk(db.RiskTierDangerous),
+		testutil.WithStatus(db.StatusExecuted),
+	)
+	testutil.MakeRequest(t, h.DB, sess,
+		testutil.WithCommand("git push --force", h.ProjectDir, true),
+		testutil.WithRisk(db.RiskTierCritical),
+	)
+	if err := h.DB.CreateOutcome(&db.ExecutionOutcome{
+		RequestID: executed.ID, CausedProblems: true, ProblemDescription: "removed cached assets",
+	}); err != nil {
+		t.Fatalf("CreateOutcome: %v", err)
+	}
+
+	logDir := filepath.Join(h.ProjectDir, ".slb", "logs")
+	if err := os.MkdirAll(logDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	header := "=== EMERGENCY EXECUTION ===\nTime:    " + time.Now().UTC().Format(time.RFC3339) +
+		"\nActor:   BlueLake\nCommand: systemctl restart db\nHash:    x\nReason:  outage\nCWD:     /\n============================\n"
+	if err := os.WriteFile(filepath.Join(logDir, "emergency_20250101-000000.log"), []byte(header), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	run := func(args ...string) string {
+		t.Helper()
+		resetReportFlags()
+		cmd := newTestReportCmd(h.DBPath)
+		stdout, err := executeCommandCapture(t, cmd, append([]string{"report", "-C", h.ProjectDir}, args...)...)
+		if err != nil {
+			t.Fatalf("report %v: %v", args, err)
+		}
+		return stdout
+	}
+
+	t.Run("json", func(t *testing.T) {
+		var rep struct {
+			TotalRequests int               `json:"total_requests"`
+			Problems      []json.RawMessage `json:"problems"`
+			Emergencies   []json.RawMessage `json:"emergency_executions"`
+		}
+		if err := json.Unmarshal([]byte(run("-j")), &rep); err != nil {
+			t.Fatalf("parsing JSON: %v", err)
+		}
+		if rep.TotalRequests != 2 || len(rep.Problems) != 1 || len(rep.Emergencies) != 1 {
+			t.Errorf("report = %+v", rep)
+		}
+	})
+
+	t.Run("text", func(t *testing.T) {
+		out := run()
+		for _, want := range []string{"Requests: 2", "BlueLake", "removed cached assets", "systemctl restart db"} {
+			if !strings.Contains(out, want) {
+				t.Errorf("text report missing %q:\n%s", want, out)
+			}
+		}
+	})
+
+	t.Run("csv with query", func(t *testing.T) {
+		out := run("-f", "csv", "-q", "tier:critical")
+		if !strings.HasPrefix(out, "section,key,metric,value\n") || !strings.Contains(out, "summary,,total_requests,1\n") {
+			t.Errorf("csv report:\n%s", out)
+		}
+	})
+
+	t.Run("html file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "report.html")
+		if out := run("-f", "html", "-o", path); out != "" {
+			t.Errorf("expected nothing on stdout, got %q", out)
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatalf("reading report: %v", err)
+		}
+		if !strings.HasPrefix(string(data), "<!DOCTYPE html>") {
+			t.Errorf("not an HTML report: %.80s", data)
+		}
+	})
+}
+
+func TestReportCommand_InvalidFlags(t *testing.T) {
+	h := testutil.NewHarness(t)
+
+	for _, args := range [][]string{
+		{"-f", "pdf"},
+		{"--since", "yesterday"},
+		{"-q", "nope:1"},
+	} {
+		resetReportFlags()
+		cmd := newTestReportCmd(h.DBPath)
+		if _, err := executeCommandCapture(t, cmd, append([]string{"report", "-C", h.ProjectDir}, args...)...); err == nil {
+			t.Errorf("report %v: expected error", args)
+		}
+	}
+}
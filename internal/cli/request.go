@@ -9,6 +9,7 @@ import (
 
 	"github.com/Dicklesworthstone/slb/internal/config"
 	"github.com/Dicklesworthstone/slb/internal/core"
+	"github.com/Dicklesworthstone/slb/internal/daemon"
 	"github.com/Dicklesworthstone/slb/internal/db"
 	"github.com/Dicklesworthstone/slb/internal/output"
 	"github.com/spf13/cobra"
@@ -26,6 +27,8 @@ var (
 	flagRequestAttachFile     []string
 	flagRequestAttachContext  []string
 	flagRequestAttachScreen   []string
+	flagRequestSandbox        sandboxFlags
+	flagRequestWindow         windowFlags
 )
 
 func init() {
@@ -40,6 +43,8 @@ func init() {
 	requestCmd.Flags().StringSliceVar(&flagRequestAttachFile, "attach-file", nil, "attach file content as context")
 	requestCmd.Flags().StringSliceVar(&flagRequestAttachContext, "attach-context", nil, "run command and attach output as context")
 	requestCmd.Flags().StringSliceVar(&flagRequestAttachScreen, "attach-screenshot", nil, "attach screenshot/image file")
+	addSandboxFlags(requestCmd, &flagRequestSandbox)
+	addWindowFlags(requestCmd, &flagRequestWindow)
 
 	rootCmd.AddCommand(requestCmd)
 }
@@ -59,7 +64,9 @@ The command is classified by risk tier:
   SAFE       - Skipped (no request created)
 
 Use --wait to block until approval/rejection.
-Use --execute with --wait to execute after approval.`,
+Use --execute with --wait to execute after approval.
+Use --sandbox (Linux) to ask for execution in a sandbox.
+Use --not-before, --not-after or --window to limit execution to a window.`,
 	Args: cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		command := args[0]
@@ -73,7 +80,7 @@ Use --execute with --wait to execute after approval.`,
 			return err
 		}
 
-		cfg, err := config.Load(config.LoadOptions{
+		cfg, prov, err := config.LoadWithProvenance(config.LoadOptions{
 			ProjectDir: project,
 			ConfigPath: flagConfig,
 		})
@@ -92,19 +99,40 @@ Use --execute with --wait to execute after approval.`,
 		}
 		defer dbConn.Close()
 
+		sandboxSpec, err := flagRequestSandbox.spec()
+		if err != nil {
+			return err
+		}
+
+		window, err := flagRequestWindow.window(time.Now())
+		if err != nil {
+			return err
+		}
+
+		scrubber, err := newScrubber(cfg)
+		if err != nil {
+			return err
+		}
+
 		// Collect attachments from flags
 		attachments, err := CollectAttachments(cmd.Context(), AttachmentFlags{
 			Files:       flagRequestAttachFile,
 			Contexts:    flagRequestAttachContext,
 			Screenshots: flagRequestAttachScreen,
+			Scrubber:    scrubber,
 		})
 		if err != nil {
 			return fmt.Errorf("collecting attachments: %w", err)
 		}
 
 		// Create the request using the core logic (config-driven rate limits + integrations).
+		creatorCfg := toRequestCreatorConfig(cfg)
+		if creatorCfg.Policy, _, err = loadPolicy(cfg, prov, project); err != nil {
+			return err
+		}
 		rl := core.NewRateLimiter(dbConn, toRateLimitConfig(cfg))
-		creator := core.NewRequestCreator(dbConn, rl, nil, toRequestCreatorConfig(cfg))
+		creator := core.NewRequestCreator(dbConn, rl, nil, creatorCfg).
+			WithNotifier(daemon.NewEventNotifier(daemon.DefaultSocketPath()))
 		result, err := creator.CreateRequest(core.CreateRequestOptions{
 			SessionID: flagSessionID,
 			Command:   command,
@@ -118,6 +146,9 @@ Use --execute with --wait to execute after approval.`,
 			Attachments:    attachments,
 			RedactPatterns: flagRequestRedact,
 			ProjectPath:    project,
+			Sandbox:        sandboxSpec,
+			Window:         window,
+			AutoAnswers:    autoAnswers(cfg),
 		})
 		if err != nil {
 			return fmt.Errorf("creating request: %w", err)
@@ -154,6 +185,15 @@ Use --execute with --wait to execute after approval.`,
 		if request.ExpiresAt != nil {
 			resp["expires_at"] = request.ExpiresAt.Format(time.RFC3339)
 		}
+		if result.Reputation != nil {
+			resp["reputation"] = result.Reputation
+		}
+		if request.Sandbox != nil {
+			resp["sandbox"] = request.Sandbox
+		}
+		if request.Window != nil {
+			resp["window"] = request.Window
+		}
 
 		// If not waiting, return now
 		if !flagRequestWait {
@@ -183,15 +223,21 @@ Use --execute with --wait to execute after approval.`,
 
 		// Execute if approved and --execute was specified
 		if flagRequestExecute && request.Status == db.StatusApproved {
-			executor := core.NewExecutor(dbConn, nil).WithNotifier(buildAgentMailNotifier(project))
+			executor := core.NewExecutor(dbConn, nil).WithNotifier(buildRequestNotifier(project)).
+				WithEnvironmentVars(cfg.General.FingerprintEnvVars).WithSchedule(changeSchedule(cfg))
+			stream, closeStream := outputStream(request.ID)
 			execResult, execErr := executor.ExecuteApprovedRequest(context.Background(), core.ExecuteOptions{
 				RequestID:         request.ID,
 				SessionID:         flagSessionID,
-				LogDir:            ".slb/logs",
+				LogDir:            projectLogDir(project),
 				SuppressOutput:    GetOutput() == "json",
 				CaptureRollback:   cfg.General.EnableRollbackCapture,
 				MaxRollbackSizeMB: cfg.General.MaxRollbackSizeMB,
+				SandboxDefaults:   sandboxDefaults(cfg),
+				OutputStream:      stream,
+				Scrubber:          scrubber,
 			})
+			closeStream()
 
 			exitCode := 0
 			durationMs := int64(0)
@@ -200,6 +246,12 @@ Use --execute with --wait to execute after approval.`,
 				exitCode = execResult.ExitCode
 				durationMs = execResult.Duration.Milliseconds()
 				logPath = execResult.LogPath
+				if len(execResult.SandboxViolations) > 0 {
+					resp["sandbox_violations"] = execResult.SandboxViolations
+				}
+				if totalRedactions(execResult.Redactions) > 0 {
+					resp["redactions"] = execResult.Redactions
+				}
 			}
 
 			resp["executed"] = true
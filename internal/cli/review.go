@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/Dicklesworthstone/slb/internal/config"
+	"github.com/Dicklesworthstone/slb/internal/core"
 	"github.com/Dicklesworthstone/slb/internal/db"
 	"github.com/Dicklesworthstone/slb/internal/output"
 	"github.com/spf13/cobra"
@@ -102,6 +103,8 @@ var reviewListCmd = &cobra.Command{
 			MinApprovals   int    `json:"min_approvals"`
 			CreatedAt      string `json:"created_at"`
 			ProjectPath    string `json:"project_path,omitempty"`
+			// Similar summarizes earlier requests with the same command shape.
+			Similar *core.SimilarSummary `json:"similar,omitempty"`
 		}
 
 		summaries := make([]requestSummary, 0, len(requests))
@@ -126,6 +129,10 @@ var reviewListCmd = &cobra.Command{
 			if flagReviewAll {
 				summary.ProjectPath = r.ProjectPath
 			}
+			if similar, err := core.FindSimilarRequests(dbConn, r, core.DefaultSimilarLimit); err == nil && len(similar) > 0 {
+				s := core.SummarizeSimilar(similar)
+				summary.Similar = &s
+			}
 			summaries = append(summaries, summary)
 		}
 
@@ -168,37 +175,46 @@ func showRequestDetails(requestID string) error {
 
 	// Build output structure
 	type reviewView struct {
-		ID            string `json:"id"`
-		ReviewerAgent string `json:"reviewer_agent"`
-		ReviewerModel string `json:"reviewer_model"`
-		Decision      string `json:"decision"`
-		Comments      string `json:"comments,omitempty"`
-		CreatedAt     string `json:"created_at"`
+		ID             string `json:"id"`
+		ReviewerAgent  string `json:"reviewer_agent"`
+		ReviewerModel  string `json:"reviewer_model"`
+		Decision       string `json:"decision"`
+		Comments       string `json:"comments,omitempty"`
+		RequireSandbox bool   `json:"require_sandbox,omitempty"`
+		SignatureValid bool   `json:"signature_valid"`
+		CreatedAt      string `json:"created_at"`
 	}
 
 	type requestDetail struct {
-		ID                    string       `json:"id"`
-		Status                string       `json:"status"`
-		RiskTier              string       `json:"risk_tier"`
-		Command               string       `json:"command"`
-		CommandHash           string       `json:"command_hash"`
-		Cwd                   string       `json:"cwd"`
-		ProjectPath           string       `json:"project_path"`
-		RequestorAgent        string       `json:"requestor_agent"`
-		RequestorModel        string       `json:"requestor_model"`
-		JustificationReason   string       `json:"justification_reason"`
-		JustificationEffect   string       `json:"justification_expected_effect,omitempty"`
-		JustificationGoal     string       `json:"justification_goal,omitempty"`
-		JustificationSafety   string       `json:"justification_safety_argument,omitempty"`
-		MinApprovals          int          `json:"min_approvals"`
-		CurrentApprovals      int          `json:"current_approvals"`
-		CurrentRejections     int          `json:"current_rejections"`
-		RequireDifferentModel bool         `json:"require_different_model"`
-		Reviews               []reviewView `json:"reviews,omitempty"`
-		DryRunCommand         string       `json:"dry_run_command,omitempty"`
-		DryRunOutput          string       `json:"dry_run_output,omitempty"`
-		CreatedAt             string       `json:"created_at"`
-		ExpiresAt             string       `json:"expires_at,omitempty"`
+		ID                    string                    `json:"id"`
+		Status                string                    `json:"status"`
+		RiskTier              string                    `json:"risk_tier"`
+		Command               string                    `json:"command"`
+		CommandHash           string                    `json:"command_hash"`
+		Cwd                   string                    `json:"cwd"`
+		ProjectPath           string                    `json:"project_path"`
+		RequestorAgent        string                    `json:"requestor_agent"`
+		RequestorModel        string                    `json:"requestor_model"`
+		Environment           db.EnvironmentFingerprint `json:"environment,omitempty"`
+		Sandbox               *db.SandboxSpec           `json:"sandbox,omitempty"`
+		Window                *db.ExecutionWindow       `json:"window,omitempty"`
+		Policy                *db.PolicyDecision        `json:"policy,omitempty"`
+		AutoAnswers           []db.AutoAnswer           `json:"auto_answers,omitempty"`
+		JustificationReason   string                    `json:"justification_reason"`
+		JustificationEffect   string                    `json:"justification_expected_effect,omitempty"`
+		JustificationGoal     string                    `json:"justification_goal,omitempty"`
+		JustificationSafety   string                    `json:"justification_safety_argument,omitempty"`
+		MinApprovals          int                       `json:"min_approvals"`
+		CurrentApprovals      int                       `json:"current_approvals"`
+		CurrentRejections     int                       `json:"current_rejections"`
+		RequireDifferentModel bool                      `json:"require_different_model"`
+		Reviews               []reviewView              `json:"reviews,omitempty"`
+		Similar               []core.SimilarRequest     `json:"similar,omitempty"`
+		SimilarSummary        *core.SimilarSummary      `json:"similar_summary,omitempty"`
+		DryRunCommand         string                    `json:"dry_run_command,omitempty"`
+		DryRunOutput          string                    `json:"dry_run_output,omitempty"`
+		CreatedAt             string                    `json:"created_at"`
+		ExpiresAt             string                    `json:"expires_at,omitempty"`
 	}
 
 	// Build command display
@@ -217,6 +233,11 @@ func showRequestDetails(requestID string) error {
 		ProjectPath:           request.ProjectPath,
 		RequestorAgent:        request.RequestorAgent,
 		RequestorModel:        request.RequestorModel,
+		Environment:           request.Environment,
+		Sandbox:               request.Sandbox,
+		Window:                request.Window,
+		Policy:                request.Policy,
+		AutoAnswers:           request.AutoAnswers,
 		JustificationReason:   request.Justification.Reason,
 		JustificationEffect:   request.Justification.ExpectedEffect,
 		JustificationGoal:     request.Justification.Goal,
@@ -239,16 +260,30 @@ func showRequestDetails(requestID string) error {
 
 	// Add reviews
 	for _, rev := range reviews {
+		valid, _ := dbConn.VerifyStoredReviewSignature(rev)
 		detail.Reviews = append(detail.Reviews, reviewView{
-			ID:            rev.ID,
-			ReviewerAgent: rev.ReviewerAgent,
-			ReviewerModel: rev.ReviewerModel,
-			Decision:      string(rev.Decision),
-			Comments:      rev.Comments,
-			CreatedAt:     rev.CreatedAt.Format(time.RFC3339),
+			ID:             rev.ID,
+			ReviewerAgent:  rev.ReviewerAgent,
+			ReviewerModel:  rev.ReviewerModel,
+			Decision:       string(rev.Decision),
+			Comments:       rev.Comments,
+			RequireSandbox: rev.RequireSandbox,
+			SignatureValid: valid,
+			CreatedAt:      rev.CreatedAt.Format(time.RFC3339),
 		})
 	}
 
+	// Earlier requests with the same command shape and how they ended
+	similar, err := core.FindSimilarRequests(dbConn, request, core.DefaultSimilarLimit)
+	if err != nil {
+		return fmt.Errorf("finding similar requests: %w", err)
+	}
+	if len(similar) > 0 {
+		summary := core.SummarizeSimilar(similar)
+		detail.Similar = similar
+		detail.SimilarSummary = &summary
+	}
+
 	out := output.New(output.Format(GetOutput()))
 	if GetOutput() == "json" {
 		return out.Write(detail)
@@ -265,6 +300,28 @@ func showRequestDetails(requestID string) error {
 	fmt.Println()
 	fmt.Printf("Requestor: %s (%s)\n", detail.RequestorAgent, detail.RequestorModel)
 	fmt.Println()
+	if len(detail.Environment) > 0 {
+		fmt.Println("Environment:")
+		for _, key := range detail.Environment.Keys() {
+			fmt.Printf("  %s: %s\n", key, detail.Environment[key])
+		}
+		fmt.Println()
+	}
+	if detail.Sandbox != nil {
+		fmt.Printf("Sandbox: %s\n", describeSandbox(detail.Sandbox))
+		fmt.Println()
+	}
+	if detail.Window != nil {
+		fmt.Printf("Window:  %s\n", describeWindow(detail.Window))
+		fmt.Println()
+	}
+	if len(detail.AutoAnswers) > 0 {
+		fmt.Println("Auto-answers (--pty):")
+		for _, a := range detail.AutoAnswers {
+			fmt.Printf("  %s -> %q\n", a.Pattern, a.Answer)
+		}
+		fmt.Println()
+	}
 	fmt.Println("Justification:")
 	fmt.Printf("  Reason: %s\n", detail.JustificationReason)
 	if detail.JustificationEffect != "" {
@@ -284,6 +341,15 @@ func showRequestDetails(requestID string) error {
 	if detail.RequireDifferentModel {
 		fmt.Println("Note: Requires approval from a different model")
 	}
+	if detail.Policy != nil {
+		fmt.Printf("Policy: %s\n", strings.Join(detail.Policy.Rules, ", "))
+		for _, c := range detail.Policy.Reviewers {
+			fmt.Printf("  Approvers: %s\n", describePolicyConstraint(c))
+		}
+		for _, c := range detail.Policy.Executors {
+			fmt.Printf("  Executors: %s\n", describePolicyConstraint(c))
+		}
+	}
 
 	if detail.DryRunCommand != "" {
 		fmt.Println()
@@ -302,12 +368,46 @@ func showRequestDetails(requestID string) error {
 		fmt.Println("Reviews:")
 		for _, rev := range detail.Reviews {
 			fmt.Printf("  - %s by %s (%s)\n", strings.ToUpper(rev.Decision), rev.ReviewerAgent, rev.ReviewerModel)
+			if !rev.SignatureValid {
+				fmt.Println("    Signature does not verify")
+			}
+			if rev.RequireSandbox {
+				fmt.Println("    Requires sandboxed execution")
+			}
 			if rev.Comments != "" {
 				fmt.Printf("    Comment: %s\n", rev.Comments)
 			}
 		}
 	}
 
+	if len(detail.Similar) > 0 {
+		fmt.Println()
+		fmt.Printf("Similar Requests (%s):\n", detail.SimilarSummary)
+		for _, s := range detail.Similar {
+			line := fmt.Sprintf("  - %s [%s] %s by %s, %s", s.RequestID, s.Match, strings.ToUpper(string(s.Status)),
+				s.RequestorAgent, s.CreatedAt.Format(time.RFC3339))
+			if s.ExitCode != nil {
+				line += fmt.Sprintf(", exit %d", *s.ExitCode)
+			}
+			fmt.Println(line)
+			fmt.Printf("    Command: %s\n", s.Command)
+			for _, d := range s.Decisions {
+				fmt.Printf("    %s by %s", strings.ToUpper(string(d.Decision)), d.ReviewerAgent)
+				if d.Comments != "" {
+					fmt.Printf(": %s", d.Comments)
+				}
+				fmt.Println()
+			}
+			if s.CausedProblems {
+				fmt.Printf("    CAUSED PROBLEMS")
+				if s.ProblemDescription != "" {
+					fmt.Printf(": %s", s.ProblemDescription)
+				}
+				fmt.Println()
+			}
+		}
+	}
+
 	fmt.Println()
 	fmt.Printf("Created: %s\n", detail.CreatedAt)
 	if detail.ExpiresAt != "" {
@@ -0,0 +1,186 @@
+package cli
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/Dicklesworthstone/slb/internal/core"
+	"github.com/Dicklesworthstone/slb/internal/db"
+	"github.com/Dicklesworthstone/slb/internal/output"
+	"github.com/spf13/cobra"
+)
+
+// batchReviewArgs accepts no request ID when a batch selection flag is set
+// and exactly one otherwise.
+func batchReviewArgs(filter, allFrom *string) cobra.PositionalArgs {
+	return func(cmd *cobra.Command, args []string) error {
+		if *filter != "" || *allFrom != "" {
+			if len(args) > 0 {
+				return fmt.Errorf("cannot combine a request ID with --filter or --all-from")
+			}
+			return nil
+		}
+		return cobra.ExactArgs(1)(cmd, args)
+	}
+}
+
+// batchReview describes one batch approve/reject invocation.
+type batchReview struct {
+	Project string
+	Filter  string
+	AllFrom string
+	Yes     bool
+	Opts    core.ReviewOptions
+	Service *core.ReviewService
+	In      io.Reader
+}
+
+// batchReviewItem is the outcome for one request in a batch.
+type batchReviewItem struct {
+	RequestID        string `json:"request_id"`
+	RiskTier         string `json:"risk_tier"`
+	RequestorAgent   string `json:"requestor_agent"`
+	Command          string `json:"command"`
+	ReviewID         string `json:"review_id,omitempty"`
+	NewRequestStatus string `json:"new_request_status,omitempty"`
+	Error            string `json:"error,omitempty"`
+}
+
+// runBatchReview selects pending requests, asks for confirmation and submits
+// one signed review per request.
+func runBatchReview(dbConn *db.DB, b batchReview) error {
+	filter := core.RequestFilter{}
+	if b.Filter != "" {
+		f, err := core.ParseRequestFilter(b.Filter)
+		if err != nil {
+			return fmt.Errorf("parsing --filter: %w", err)
+		}
+		filter = f
+	}
+	if b.AllFrom != "" {
+		filter.Sessions = append(filter.Sessions, b.AllFrom)
+	}
+
+	requests, err := core.SelectPendingRequests(dbConn, b.Project, filter, b.Opts.SessionID)
+	if err != nil {
+		return err
+	}
+
+	verb, done := "Approve", "Approved"
+	if b.Opts.Decision == db.DecisionReject {
+		verb, done = "Reject", "Rejected"
+	}
+
+	out := output.New(output.Format(GetOutput()))
+	if len(requests) == 0 {
+		if GetOutput() == "json" {
+			return out.Write(map[string]any{
+				"decision":  string(b.Opts.Decision),
+				"matched":   0,
+				"succeeded": 0,
+				"failed":    0,
+				"results":   []batchReviewItem{},
+			})
+		}
+		fmt.Println("No pending requests match.")
+		return nil
+	}
+
+	if !b.Yes {
+		if GetOutput() == "json" {
+			return fmt.Errorf("--yes is required for batch reviews with JSON output (%d request(s) match)", len(requests))
+		}
+		headers := []string{"REQUEST_ID", "TIER", "AGENT", "COMMAND"}
+		rows := make([][]string, 0, len(requests))
+		for _, r := range requests {
+			rows = append(rows, []string{r.ID, string(r.RiskTier), r.RequestorAgent, displayCommand(r)})
+		}
+		output.OutputTable(headers, rows)
+		fmt.Fprintln(os.Stderr)
+		word := strings.ToUpper(verb)
+		fmt.Fprintf(os.Stderr, "%s %d request(s) listed above? Type '%s' to confirm: ", verb, len(requests), word)
+
+		in := b.In
+		if in == nil {
+			in = os.Stdin
+		}
+		input, err := bufio.NewReader(in).ReadString('\n')
+		if err != nil && input == "" {
+			return fmt.Errorf("reading confirmation: %w", err)
+		}
+		if strings.TrimSpace(input) != word {
+			return fmt.Errorf("batch review cancelled")
+		}
+	}
+
+	ids := make([]string, len(requests))
+	byID := make(map[string]*db.Request, len(requests))
+	for i, r := range requests {
+		ids[i] = r.ID
+		byID[r.ID] = r
+	}
+
+	results := b.Service.SubmitBatch(b.Opts, ids)
+	items := make([]batchReviewItem, 0, len(results))
+	failed := 0
+	for _, res := range results {
+		r := byID[res.RequestID]
+		item := batchReviewItem{
+			RequestID:      r.ID,
+			RiskTier:       string(r.RiskTier),
+			RequestorAgent: r.RequestorAgent,
+			Command:        displayCommand(r),
+		}
+		if res.Err != nil {
+			failed++
+			item.Error = res.Err.Error()
+		} else {
+			item.ReviewID = res.Result.Review.ID
+			if res.Result.RequestStatusChanged {
+				item.NewRequestStatus = string(res.Result.NewRequestStatus)
+			}
+		}
+		items = append(items, item)
+	}
+
+	if GetOutput() == "json" {
+		if err := out.Write(map[string]any{
+			"decision":  string(b.Opts.Decision),
+			"matched":   len(requests),
+			"succeeded": len(requests) - failed,
+			"failed":    failed,
+			"results":   items,
+		}); err != nil {
+			return err
+		}
+	} else {
+		for _, item := range items {
+			if item.Error != "" {
+				fmt.Printf("✗ %s  %s: %s\n", item.RequestID, item.Command, item.Error)
+				continue
+			}
+			status := ""
+			if item.NewRequestStatus != "" {
+				status = " → " + item.NewRequestStatus
+			}
+			fmt.Printf("✓ %s  %s%s\n", item.RequestID, item.Command, status)
+		}
+		fmt.Printf("%s %d of %d request(s)\n", done, len(requests)-failed, len(requests))
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d review(s) failed", failed, len(requests))
+	}
+	return nil
+}
+
+// displayCommand prefers the redacted form of a request's command.
+func displayCommand(r *db.Request) string {
+	if r.Command.DisplayRedacted != "" {
+		return r.Command.DisplayRedacted
+	}
+	return r.Command.Raw
+}
@@ -0,0 +1,216 @@
+package cli
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/Dicklesworthstone/slb/internal/db"
+	"github.com/Dicklesworthstone/slb/internal/testutil"
+	"github.com/spf13/cobra"
+)
+
+// newTestBatchReviewCmd creates approve/reject commands with the batch flags.
+func newTestBatchReviewCmd(dbPath string) *cobra.Command {
+	root := &cobra.Command{
+		Use:           "slb",
+		SilenceUsage:  true,
+		SilenceErrors: true,
+	}
+	root.PersistentFlags().StringVar(&flagDB, "db", dbPath, "database path")
+	root.PersistentFlags().StringVarP(&flagOutput, "output", "o", "text", "output format")
+	root.PersistentFlags().BoolVarP(&flagJSON, "json", "j", false, "json output")
+	root.PersistentFlags().StringVarP(&flagProject, "project", "C", "", "project directory")
+	root.PersistentFlags().StringVarP(&flagConfig, "config", "c", "", "config file")
+
+	approve := &cobra.Command{
+		Use:  "approve [request-id]",
+		Args: batchReviewArgs(&flagApproveFilter, &flagApproveAllFrom),
+		RunE: approveCmd.RunE,
+	}
+	approve.Flags().StringVarP(&flagApproveSessionID, "session-id", "s", "", "")
+	approve.Flags().StringVarP(&flagApproveSessionKey, "session-key", "k", "", "")
+	approve.Flags().StringVar(&flagApproveFilter, "filter", "", "")
+	approve.Flags().StringVar(&flagApproveAllFrom, "all-from", "", "")
+	approve.Flags().BoolVarP(&flagApproveYes, "yes", "y", false, "")
+
+	reject := &cobra.Command{
+		Use:  "reject [request-id]",
+		Args: batchReviewArgs(&flagRejectFilter, &flagRejectAllFrom),
+		RunE: rejectCmd.RunE,
+	}
+	reject.Flags().StringVarP(&flagRejectSessionID, "session-id", "s", "", "")
+	reject.Flags().StringVarP(&flagRejectSessionKey, "session-key", "k", "", "")
+	reject.Flags().StringVarP(&flagRejectReason, "reason", "r", "", "")
+	reject.Flags().StringVar(&flagRejectFilter, "filter", "", "")
+	reject.Flags().StringVar(&flagRejectAllFrom, "all-from", "", "")
+	reject.Flags().BoolVarP(&flagRejectYes, "yes", "y", false, "")
+
+	root.AddCommand(approve, reject)
+	return root
+}
+
+type batchFixture struct {
+	h        *testutil.Harness
+	reviewer *db.Session
+	blue     *db.Session
+	green    *db.Session
+	requests map[string]*db.Request
+}
+
+func newBatchFixture(t *testing.T) *batchFixture {
+	t.Helper()
+	h := testutil.NewHarness(t)
+	f := &batchFixture{h: h, requests: map[string]*db.Request{}}
+	f.reviewer = testutil.MakeSession(t, h.DB, testutil.WithProject(h.ProjectDir), testutil.WithAgent("Reviewer"), testutil.WithModel("model-r"))
+	f.blue = testutil.MakeSession(t, h.DB, testutil.WithProject(h.ProjectDir), testutil.WithAgent("BlueLake"), testutil.WithModel("model-b"))
+	f.green = testutil.MakeSession(t, h.DB, testutil.WithProject(h.ProjectDir), testutil.WithAgent("GreenHill"), testutil.WithModel("model-g"))
+
+	add := func(name string, s *db.Session, tier db.RiskTier, cmd string) {
+		r := testutil.MakeRequest(t, h.DB, s,
+			testutil.WithCommand(cmd, h.ProjectDir, true),
+			testutil.WithRisk(tier),
+			testutil.WithMinApprovals(1),
+			testutil.WithRequireDifferentModel(false),
+		)
+		f.requests[name] = r
+	}
+	add("blue1", f.blue, db.RiskTierCaution, "rm -rf ./dist-a")
+	add("blue2", f.blue, db.RiskTierCaution, "rm -rf ./dist-b")
+	add("blueCrit", f.blue, db.RiskTierCritical, "rm -rf /var/data")
+	add("green1", f.green, db.RiskTierCaution, "rm -rf ./dist-c")
+	return f
+}
+
+func (f *batchFixture) status(t *testing.T, name string) db.RequestStatus {
+	t.Helper()
+	r, err := f.h.DB.GetRequest(f.requests[name].ID)
+	if err != nil {
+		t.Fatalf("GetRequest: %v", err)
+	}
+	return r.Status
+}
+
+func TestApproveCommand_BatchFilterJSON(t *testing.T) {
+	f := newBatchFixture(t)
+	resetApproveFlags()
+
+	cmd := newTestBatchReviewCmd(f.h.DBPath)
+	stdout, err := executeCommandCapture(t, cmd, "approve",
+		"-s", f.reviewer.ID, "-k", f.reviewer.SessionKey,
+		"-C", f.h.ProjectDir,
+		"--filter", "tier=caution,agent=BlueLake",
+		"--yes", "-j",
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var result struct {
+		Decision  string            `json:"decision"`
+		Matched   int               `json:"matched"`
+		Succeeded int               `json:"succeeded"`
+		Results   []batchReviewItem `json:"results"`
+	}
+	if err := json.Unmarshal([]byte(stdout), &result); err != nil {
+		t.Fatalf("failed to parse JSON: %v\nstdout: %s", err, stdout)
+	}
+	if result.Decision != "approve" || result.Matched != 2 || result.Succeeded != 2 {
+		t.Fatalf("unexpected result: %+v", result)
+	}
+	for _, item := range result.Results {
+		if item.ReviewID == "" || item.RequestorAgent != "BlueLake" {
+			t.Errorf("unexpected item: %+v", item)
+		}
+	}
+
+	for name, want := range map[string]db.RequestStatus{
+		"blue1": db.StatusApproved, "blue2": db.StatusApproved,
+		"blueCrit": db.StatusPending, "green1": db.StatusPending,
+	} {
+		if got := f.status(t, name); got != want {
+			t.Errorf("%s: status %s, want %s", name, got, want)
+		}
+	}
+}
+
+func TestApproveCommand_BatchRequiresYesForJSON(t *testing.T) {
+	f := newBatchFixture(t)
+	resetApproveFlags()
+
+	cmd := newTestBatchReviewCmd(f.h.DBPath)
+	_, err := executeCommandCapture(t, cmd, "approve",
+		"-s", f.reviewer.ID, "-k", f.reviewer.SessionKey,
+		"-C", f.h.ProjectDir, "--filter", "tier=caution", "-j",
+	)
+	if err == nil || !strings.Contains(err.Error(), "--yes") {
+		t.Fatalf("expected --yes error, got %v", err)
+	}
+	if got := f.status(t, "blue1"); got != db.StatusPending {
+		t.Errorf("expected nothing reviewed, got %s", got)
+	}
+}
+
+func TestRejectCommand_AllFromWithConfirmation(t *testing.T) {
+	f := newBatchFixture(t)
+	resetRejectFlags()
+
+	cmd := newTestBatchReviewCmd(f.h.DBPath)
+	cmd.SetIn(strings.NewReader("REJECT\n"))
+	stdout, err := executeCommandCapture(t, cmd, "reject",
+		"-s", f.reviewer.ID, "-k", f.reviewer.SessionKey,
+		"-C", f.h.ProjectDir, "-r", "runaway agent",
+		"--all-from", f.blue.ID,
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(stdout, "Rejected 3 of 3") {
+		t.Errorf("unexpected output: %s", stdout)
+	}
+	for _, name := range []string{"blue1", "blue2", "blueCrit"} {
+		if got := f.status(t, name); got != db.StatusRejected {
+			t.Errorf("%s: status %s, want rejected", name, got)
+		}
+	}
+	if got := f.status(t, "green1"); got != db.StatusPending {
+		t.Errorf("green1: status %s, want pending", got)
+	}
+}
+
+func TestRejectCommand_BatchCancelled(t *testing.T) {
+	f := newBatchFixture(t)
+	resetRejectFlags()
+
+	cmd := newTestBatchReviewCmd(f.h.DBPath)
+	cmd.SetIn(strings.NewReader("no\n"))
+	_, err := executeCommandCapture(t, cmd, "reject",
+		"-s", f.reviewer.ID, "-k", f.reviewer.SessionKey,
+		"-C", f.h.ProjectDir, "-r", "nope",
+		"--all-from", f.green.ID,
+	)
+	if err == nil || !strings.Contains(err.Error(), "cancelled") {
+		t.Fatalf("expected cancellation, got %v", err)
+	}
+	if got := f.status(t, "green1"); got != db.StatusPending {
+		t.Errorf("expected request untouched, got %s", got)
+	}
+}
+
+func TestBatchReviewArgs(t *testing.T) {
+	resetApproveFlags()
+	f := newBatchFixture(t)
+
+	cmd := newTestBatchReviewCmd(f.h.DBPath)
+	_, err := executeCommandCapture(t, cmd, "approve", "abc", "--filter", "tier=caution", "-s", "x", "-k", "y")
+	if err == nil || !strings.Contains(err.Error(), "cannot combine") {
+		t.Errorf("expected combine error, got %v", err)
+	}
+
+	resetApproveFlags()
+	cmd = newTestBatchReviewCmd(f.h.DBPath)
+	_, err = executeCommandCapture(t, cmd, "approve", "-s", "x", "-k", "y")
+	if err == nil || !strings.Contains(err.Error(), "accepts 1 arg") {
+		t.Errorf("expected missing request ID error, got %v", err)
+	}
+}
@@ -744,3 +744,53 @@ func TestReviewShowCommand_TextOutputWithSafetyArgument(t *testing.T) {
 		t.Error("expected text output to contain 'Safety Argument:'")
 	}
 }
+
+func TestReviewShowCommand_TextOutputWithSimilar(t *testing.T) {
+	h := testutil.NewHarness(t)
+	resetReviewFlags()
+
+	sess := testutil.MakeSession(t, h.DB, testutil.WithProject(h.ProjectDir))
+	earlier := testutil.MakeRequest(t, h.DB, sess,
+		testutil.WithCommand("rm -rf ./build", h.ProjectDir, true),
+	)
+	if _, err := h.DB.RecordOutcome(earlier.ID, true, "removed release artifacts", nil, ""); err != nil {
+		t.Fatalf("RecordOutcome: %v", err)
+	}
+	req := testutil.MakeRequest(t, h.DB, sess,
+		testutil.WithCommand("rm -r ./build", h.ProjectDir, true),
+	)
+
+	stdout, err := executeCommandCapture(t, newTestReviewCmd(h.DBPath), "review", "show", req.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, want := range []string{"Similar Requests (1 similar:", earlier.ID + " [target]", "CAUSED PROBLEMS: removed release artifacts"} {
+		if !strings.Contains(stdout, want) {
+			t.Errorf("expected %q in output:\n%s", want, stdout)
+		}
+	}
+
+	// The pending list summarizes precedent per request.
+	resetReviewFlags()
+	stdout, err = executeCommandCapture(t, newTestReviewCmd(h.DBPath), "review", "list", "-j", "-C", h.ProjectDir)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var list []map[string]any
+	if err := json.Unmarshal([]byte(stdout), &list); err != nil {
+		t.Fatalf("failed to parse JSON: %v\nstdout: %s", err, stdout)
+	}
+	found := false
+	for _, item := range list {
+		if item["id"] == req.ID {
+			found = true
+			similar, ok := item["similar"].(map[string]any)
+			if !ok || similar["caused_problems"] != float64(1) {
+				t.Errorf("expected similar summary for %s, got %v", req.ID, item["similar"])
+			}
+		}
+	}
+	if !found {
+		t.Errorf("request %s not listed: %s", req.ID, stdout)
+	}
+}
@@ -3,7 +3,9 @@ package cli
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
 	"math"
 	"os"
 	"path/filepath"
@@ -11,8 +13,13 @@ import (
 
 	"github.com/Dicklesworthstone/slb/internal/config"
 	"github.com/Dicklesworthstone/slb/internal/core"
+	"github.com/Dicklesworthstone/slb/internal/daemon"
 	"github.com/Dicklesworthstone/slb/internal/db"
+	"github.com/Dicklesworthstone/slb/internal/integrations"
 	"github.com/Dicklesworthstone/slb/internal/output"
+	"github.com/Dicklesworthstone/slb/internal/policy"
+	"github.com/Dicklesworthstone/slb/internal/sandbox"
+	"github.com/Dicklesworthstone/slb/internal/scrub"
 	"github.com/spf13/cobra"
 )
 
@@ -26,6 +33,11 @@ var (
 	flagRunAttachFile     []string
 	flagRunAttachContext  []string
 	flagRunAttachScreen   []string
+	flagRunSandbox        sandboxFlags
+	flagRunPTY            bool
+	flagRunPlan           string
+	flagRunWindow         windowFlags
+	flagRunWaitWindow     bool
 )
 
 func init() {
@@ -38,12 +50,17 @@ func init() {
 	runCmd.Flags().StringSliceVar(&flagRunAttachFile, "attach-file", nil, "attach file content as context")
 	runCmd.Flags().StringSliceVar(&flagRunAttachContext, "attach-context", nil, "run command and attach output as context")
 	runCmd.Flags().StringSliceVar(&flagRunAttachScreen, "attach-screenshot", nil, "attach screenshot/image file")
+	addSandboxFlags(runCmd, &flagRunSandbox)
+	runCmd.Flags().BoolVar(&flagRunPTY, "pty", false, "run attached to a pseudo-terminal and record a replayable transcript")
+	runCmd.Flags().StringVar(&flagRunPlan, "plan", "", "run the steps of a plan file (YAML) as one request")
+	addWindowFlags(runCmd, &flagRunWindow)
+	runCmd.Flags().BoolVar(&flagRunWaitWindow, "wait-window", false, "once approved, wait for the execution window to open instead of failing")
 
 	rootCmd.AddCommand(runCmd)
 }
 
 var runCmd = &cobra.Command{
-	Use:   "run <command>",
+	Use:   "run <command> | --plan <file>",
 	Short: "Run a command with approval if required",
 	Long: `Run a command atomically with approval handling.
 
@@ -58,16 +75,39 @@ Flow:
 
 The command inherits the caller's environment and working directory.
 
+With --plan, the steps of a plan file are classified one by one, approved
+together at the highest tier of any step, and executed in order. A failing
+step stops the plan unless it sets continue_on_failure:
+
+  name: decommission-db
+  steps:
+    - command: kubectl scale deploy/db --replicas=0
+      expected_effect: database pods stop
+    - command: kubectl delete pvc data
+      cwd: k8s
+    - command: helm uninstall db
+
+With --not-before, --not-after or --window, the request may be approved ahead
+of time and only executes while its window is open; --wait-window waits for
+the window to open after approval. Freeze periods in config block requests of
+their tiers from being created or executed while they are active.
+
 Examples:
   slb run "rm -rf ./build" --reason "Clean build artifacts"
   slb run "git push --force" --reason "Rewrite history" --safety "Branch is not shared"
-  slb run "kubectl delete deployment nginx" --reason "Removing unused deployment"`,
-	Args: cobra.ExactArgs(1),
+  slb run "kubectl delete deployment nginx" --reason "Removing unused deployment"
+  slb run "rm -rf ./build" --sandbox --no-network --time-limit 60
+  slb run "terraform apply" --reason "Apply reviewed plan" --pty
+  slb run --plan decommission.yaml --reason "Retire the staging database"
+  slb run "./migrate.sh" --reason "Schema migration" --window nightly --wait-window`,
+	Args: cobra.MaximumNArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
-		command := args[0]
-
-		if flagSessionID == "" {
-			return fmt.Errorf("--session-id is required")
+		if (len(args) == 1) == (flagRunPlan != "") {
+			return fmt.Errorf("requires a command or --plan, but not both")
+		}
+		command := ""
+		if len(args) == 1 {
+			command = args[0]
 		}
 
 		project, err := projectPath()
@@ -75,7 +115,7 @@ Examples:
 			return err
 		}
 
-		cfg, err := config.Load(config.LoadOptions{
+		cfg, prov, err := config.LoadWithProvenance(config.LoadOptions{
 			ProjectDir: project,
 			ConfigPath: flagConfig,
 		})
@@ -88,29 +128,74 @@ Examples:
 			cwd = project
 		}
 
+		var plan *db.Plan
+		if flagRunPlan != "" {
+			plan, err = core.LoadPlan(flagRunPlan, cwd)
+			if err != nil {
+				return err
+			}
+			command = core.PlanSummary(plan)
+		}
+
 		dbConn, err := db.OpenAndMigrate(GetDB())
 		if err != nil {
 			return fmt.Errorf("opening database: %w", err)
 		}
 		defer dbConn.Close()
 
+		sessionID, err := runSessionID(dbConn, project)
+		if err != nil {
+			return err
+		}
+
 		out := output.New(output.Format(GetOutput()))
 
+		window, err := flagRunWindow.window(time.Now())
+		if err != nil {
+			return writeError(cmd, out, "invalid_window", command, err)
+		}
+
+		sandboxSpec, err := flagRunSandbox.spec()
+		if err != nil {
+			return writeError(cmd, out, "invalid_sandbox", command, err)
+		}
+
+		var ptyOpts *core.PTYOptions
+		if flagRunPTY {
+			opts, err := ptyOptions(cfg)
+			if err != nil {
+				return writeError(cmd, out, "invalid_config", command, err)
+			}
+			ptyOpts = &opts
+		}
+
+		scrubber, err := newScrubber(cfg)
+		if err != nil {
+			return writeError(cmd, out, "invalid_config", command, err)
+		}
+
 		// Collect attachments from flags
 		attachments, err := CollectAttachments(cmd.Context(), AttachmentFlags{
 			Files:       flagRunAttachFile,
 			Contexts:    flagRunAttachContext,
 			Screenshots: flagRunAttachScreen,
+			Scrubber:    scrubber,
 		})
 		if err != nil {
 			return writeError(cmd, out, "attachment_error", command, err)
 		}
 
-		// Step 1: Classify and create request using config-derived limits and notifiers
+		// Step 1: Classify and create request using config-derived limits,
+		// policy and notifiers
+		creatorCfg := toRequestCreatorConfig(cfg)
+		if creatorCfg.Policy, _, err = loadPolicy(cfg, prov, project); err != nil {
+			return writeError(cmd, out, "invalid_config", command, err)
+		}
 		rl := core.NewRateLimiter(dbConn, toRateLimitConfig(cfg))
-		creator := core.NewRequestCreator(dbConn, rl, nil, toRequestCreatorConfig(cfg))
+		creator := core.NewRequestCreator(dbConn, rl, nil, creatorCfg).
+			WithNotifier(daemon.NewEventNotifier(daemon.DefaultSocketPath()))
 		result, err := creator.CreateRequest(core.CreateRequestOptions{
-			SessionID: flagSessionID,
+			SessionID: sessionID,
 			Command:   command,
 			Cwd:       cwd,
 			Shell:     true, // run always uses shell
@@ -122,14 +207,29 @@ Examples:
 			},
 			Attachments: attachments,
 			ProjectPath: project,
+			Sandbox:     sandboxSpec,
+			Plan:        plan,
+			Window:      window,
+			AutoAnswers: autoAnswers(cfg),
 		})
+		if errors.Is(err, core.ErrChangeFreeze) || errors.Is(err, core.ErrFreezePeriod) {
+			return writeError(cmd, out, "frozen", command, err)
+		}
+		if errors.Is(err, policy.ErrDenied) {
+			return writeError(cmd, out, "denied", command, err)
+		}
 		if err != nil {
 			return writeError(cmd, out, "request_failed", command, err)
 		}
 
 		// Step 2: If SAFE, execute immediately
 		if result.Skipped {
-			exitCode, err := runSafeCommand(cmd, out, command, cwd, project)
+			var exitCode int
+			if plan != nil {
+				exitCode, err = runSafePlan(cmd, out, plan, project, sandboxSpec, ptyOpts, scrubber)
+			} else {
+				exitCode, err = runSafeCommand(cmd, out, command, cwd, project, sandboxSpec, ptyOpts, scrubber)
+			}
 			if err != nil {
 				return err
 			}
@@ -143,13 +243,17 @@ Examples:
 
 		// Step 3: If yield mode and not immediately approved, return request info
 		if flagRunYield && request.Status == db.StatusPending {
-			return out.Write(map[string]any{
+			resp := map[string]any{
 				"status":        "pending",
 				"request_id":    request.ID,
 				"tier":          string(request.RiskTier),
 				"min_approvals": request.MinApprovals,
 				"message":       "Request created, yielding to background. Check status with: slb status " + request.ID,
-			})
+			}
+			if result.Reputation != nil {
+				resp["reputation"] = result.Reputation
+			}
+			return out.Write(resp)
 		}
 
 		// Step 4: Wait for approval
@@ -183,8 +287,15 @@ Examples:
 				fmt.Errorf("request %s timed out waiting for approval", request.ID))
 		}
 
-		// Step 5: Execute the approved command
-		exitCode, err := runApprovedRequest(cmd.Context(), out, dbConn, cfg, project, request.ID)
+		// Step 5: Wait for the execution window if asked to
+		if flagRunWaitWindow {
+			if err := waitForWindow(cmd.Context(), changeSchedule(cfg), request); err != nil {
+				return writeError(cmd, out, "outside_window", command, err)
+			}
+		}
+
+		// Step 6: Execute the approved command
+		exitCode, err := runApprovedRequest(cmd.Context(), out, dbConn, cfg, project, request.ID, sessionID, ptyOpts, scrubber)
 		if err != nil {
 			return err
 		}
@@ -195,7 +306,41 @@ Examples:
 	},
 }
 
-func runSafeCommand(cmd *cobra.Command, out *output.Writer, command, cwd, project string) (int, error) {
+// runSessionID returns --session-id or, when it is omitted, the only active
+// session in the project whose program is the detected agent. It warns on
+// stderr when the session's declared program is not the detected one.
+func runSessionID(dbConn *db.DB, project string) (string, error) {
+	agent := detectAgent()
+	if flagSessionID != "" {
+		if agent != nil {
+			if sess, err := dbConn.GetSession(flagSessionID); err == nil && sess.Program != "" &&
+				!integrations.ProgramMatches(sess.Program, agent.Type) {
+				fmt.Fprintf(os.Stderr, "Warning: session %s declares program %q but %s was detected\n", sess.ID, sess.Program, agent.Type)
+			}
+		}
+		return flagSessionID, nil
+	}
+	if agent == nil {
+		return "", fmt.Errorf("--session-id is required")
+	}
+
+	sessions, err := dbConn.ListActiveSessions(project)
+	if err != nil {
+		return "", err
+	}
+	var matches []*db.Session
+	for _, s := range sessions {
+		if integrations.ProgramMatches(s.Program, agent.Type) {
+			matches = append(matches, s)
+		}
+	}
+	if len(matches) != 1 {
+		return "", fmt.Errorf("--session-id is required (%d active %s sessions in this project)", len(matches), agent.Type)
+	}
+	return matches[0].ID, nil
+}
+
+func runSafeCommand(cmd *cobra.Command, out *output.Writer, command, cwd, project string, sandboxSpec *db.SandboxSpec, ptyOpts *core.PTYOptions, scrubber *scrub.Scrubber) (int, error) {
 	logPath, err := createRunLogFile(project, "safe")
 	if err != nil {
 		return 0, writeError(cmd, out, "log_create_failed", command, err)
@@ -208,12 +353,32 @@ func runSafeCommand(cmd *cobra.Command, out *output.Writer, command, cwd, projec
 	}
 	spec.Hash = db.ComputeCommandHash(*spec)
 
-	var streamWriter *os.File
+	var streamWriter io.Writer
 	if GetOutput() != "json" {
 		streamWriter = os.Stdout
 	}
 
-	result, execErr := core.RunCommand(cmd.Context(), spec, logPath, streamWriter)
+	// Safe commands are only sandboxed when asked to be.
+	var policy *sandbox.Policy
+	ctx := cmd.Context()
+	if sandboxSpec != nil {
+		if err := sandbox.Available(); err != nil {
+			return 0, writeError(cmd, out, "sandbox_unavailable", command, err)
+		}
+		p := core.SandboxPolicy(sandboxSpec, *spec)
+		policy = &p
+		if sandboxSpec.TimeoutSeconds > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, time.Duration(sandboxSpec.TimeoutSeconds)*time.Second)
+			defer cancel()
+		}
+	}
+
+	result, execErr := core.RunCommandWithOptions(ctx, spec, core.RunOptions{
+		Sandbox:  policy,
+		PTY:      ptyOpts,
+		Scrubber: scrubber,
+	}, logPath, streamWriter)
 
 	exitCode := 0
 	durationMs := int64(0)
@@ -231,6 +396,18 @@ func runSafeCommand(cmd *cobra.Command, out *output.Writer, command, cwd, projec
 		"tier":             "safe",
 		"skipped_approval": true,
 	}
+	if ptyOpts != nil {
+		resp["transcript_path"] = core.TranscriptPath(logPath)
+	}
+	if result != nil && len(result.SandboxViolations) > 0 {
+		resp["sandbox_violations"] = result.SandboxViolations
+	}
+	if result != nil && len(result.AutoAnswers) > 0 {
+		resp["auto_answers"] = result.AutoAnswers
+	}
+	if result != nil && totalRedactions(result.Redactions) > 0 {
+		resp["redactions"] = result.Redactions
+	}
 	if execErr != nil {
 		resp["error"] = execErr.Error()
 	}
@@ -247,6 +424,11 @@ func runSafeCommand(cmd *cobra.Command, out *output.Writer, command, cwd, projec
 		fmt.Fprintf(os.Stderr, "[slb] Execution failed: %s\n", execErr.Error())
 		return 1, nil
 	}
+	if result != nil {
+		printSandboxViolations(result.SandboxViolations)
+		printAutoAnswers(result.AutoAnswers)
+		printRedactions(result.Redactions)
+	}
 	if exitCode != 0 {
 		fmt.Fprintf(os.Stderr, "\n[slb] Command exited with code %d\n", exitCode)
 		return exitCode, nil
@@ -254,25 +436,45 @@ func runSafeCommand(cmd *cobra.Command, out *output.Writer, command, cwd, projec
 	return 0, nil
 }
 
-func runApprovedRequest(ctx context.Context, out *output.Writer, dbConn *db.DB, cfg config.Config, project, requestID string) (int, error) {
-	executor := core.NewExecutor(dbConn, nil).WithNotifier(buildAgentMailNotifier(project))
+func runApprovedRequest(ctx context.Context, out *output.Writer, dbConn *db.DB, cfg config.Config, project, requestID, sessionID string, ptyOpts *core.PTYOptions, scrubber *scrub.Scrubber) (int, error) {
+	executor := core.NewExecutor(dbConn, nil).WithNotifier(buildRequestNotifier(project)).
+		WithEnvironmentVars(cfg.General.FingerprintEnvVars).WithSchedule(changeSchedule(cfg))
 
-	execResult, execErr := executor.ExecuteApprovedRequest(ctx, core.ExecuteOptions{
+	stream, closeStream := outputStream(requestID)
+	opts := core.ExecuteOptions{
 		RequestID:         requestID,
-		SessionID:         flagSessionID,
-		LogDir:            ".slb/logs",
+		SessionID:         sessionID,
+		LogDir:            projectLogDir(project),
 		SuppressOutput:    GetOutput() == "json",
 		CaptureRollback:   cfg.General.EnableRollbackCapture,
 		MaxRollbackSizeMB: cfg.General.MaxRollbackSizeMB,
-	})
+		SandboxDefaults:   sandboxDefaults(cfg),
+		OutputStream:      stream,
+		Scrubber:          scrubber,
+	}
+	if ptyOpts != nil {
+		opts.PTY = true
+		opts.PTYOptions = *ptyOpts
+	}
+	execResult, execErr := executor.ExecuteApprovedRequest(ctx, opts)
+	closeStream()
 
 	exitCode := 0
 	durationMs := int64(0)
 	logPath := ""
+	transcriptPath := ""
+	var violations, answers []string
+	var redactions map[string]int
+	var steps []db.PlanStepResult
 	if execResult != nil {
 		exitCode = execResult.ExitCode
 		durationMs = execResult.Duration.Milliseconds()
 		logPath = execResult.LogPath
+		transcriptPath = execResult.TranscriptPath
+		violations = execResult.SandboxViolations
+		answers = execResult.AutoAnswers
+		redactions = execResult.Redactions
+		steps = execResult.Steps
 	}
 
 	resp := map[string]any{
@@ -282,6 +484,21 @@ func runApprovedRequest(ctx context.Context, out *output.Writer, dbConn *db.DB,
 		"duration_ms": durationMs,
 		"log_path":    logPath,
 	}
+	if transcriptPath != "" {
+		resp["transcript_path"] = transcriptPath
+	}
+	if len(violations) > 0 {
+		resp["sandbox_violations"] = violations
+	}
+	if len(answers) > 0 {
+		resp["auto_answers"] = answers
+	}
+	if totalRedactions(redactions) > 0 {
+		resp["redactions"] = redactions
+	}
+	if len(steps) > 0 {
+		resp["steps"] = steps
+	}
 	if execErr != nil {
 		resp["error"] = execErr.Error()
 	}
@@ -294,10 +511,14 @@ func runApprovedRequest(ctx context.Context, out *output.Writer, dbConn *db.DB,
 		return exitCode, nil
 	}
 
+	printPlanSteps(steps)
 	if execErr != nil {
 		fmt.Fprintf(os.Stderr, "[slb] Execution failed: %s\n", execErr.Error())
 		return 1, nil
 	}
+	printSandboxViolations(violations)
+	printAutoAnswers(answers)
+	printRedactions(redactions)
 	if exitCode != 0 {
 		fmt.Fprintf(os.Stderr, "\n[slb] Command exited with code %d\n", exitCode)
 		return exitCode, nil
@@ -305,14 +526,19 @@ func runApprovedRequest(ctx context.Context, out *output.Writer, dbConn *db.DB,
 	return 0, nil
 }
 
+// projectLogDir is where execution logs of the project are written.
+func projectLogDir(project string) string {
+	if project == "" {
+		return ".slb/logs"
+	}
+	return filepath.Join(project, ".slb", "logs")
+}
+
 func createRunLogFile(project, prefix string) (string, error) {
 	if prefix == "" {
 		prefix = "run"
 	}
-	baseDir := ".slb/logs"
-	if project != "" {
-		baseDir = filepath.Join(project, ".slb", "logs")
-	}
+	baseDir := projectLogDir(project)
 	if err := os.MkdirAll(baseDir, 0700); err != nil {
 		return "", fmt.Errorf("creating log dir: %w", err)
 	}
@@ -402,6 +628,22 @@ func toRequestCreatorConfig(cfg config.Config) *core.RequestCreatorConfig {
 		AgentMailEnabled:           cfg.Integrations.AgentMailEnabled,
 		AgentMailThread:            cfg.Integrations.AgentMailThread,
 		AgentMailSender:            "",
+		Reputation:                 toReputationConfig(cfg),
+		BindEnvironment:            cfg.General.BindEnvironment,
+		EnvironmentVars:            cfg.General.FingerprintEnvVars,
+		Schedule:                   changeSchedule(cfg),
+		PolicyDefaults:             policy.DefaultsFromConfig(cfg),
+	}
+}
+
+func toReputationConfig(cfg config.Config) core.ReputationConfig {
+	return core.ReputationConfig{
+		Enabled:                  cfg.Agents.ReputationEnabled,
+		HalfLife:                 time.Duration(cfg.Agents.ReputationHalfLifeDays) * 24 * time.Hour,
+		MinSamples:               cfg.Agents.ReputationMinSamples,
+		LowScore:                 cfg.Agents.ReputationLowScore,
+		LowExtraApprovals:        cfg.Agents.ReputationLowExtraApprovals,
+		LowRequireDifferentModel: cfg.Agents.ReputationLowRequireDifferentModel,
 	}
 }
 
@@ -28,7 +28,7 @@ func TestRunSafeCommand_Success(t *testing.T) {
 
 	// Execute a safe command (echo)
 	flagOutput = "text"
-	exitCode, err := runSafeCommand(cmd, out, "echo safe", tmpDir, tmpDir)
+	exitCode, err := runSafeCommand(cmd, out, "echo safe", tmpDir, tmpDir, nil, nil, nil)
 
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
@@ -55,7 +55,7 @@ func TestRunSafeCommand_Failure(t *testing.T) {
 
 	// Execute a failing command
 	flagOutput = "text"
-	exitCode, err := runSafeCommand(cmd, out, "sh -c 'exit 42'", tmpDir, tmpDir)
+	exitCode, err := runSafeCommand(cmd, out, "sh -c 'exit 42'", tmpDir, tmpDir, nil, nil, nil)
 
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
@@ -142,7 +142,7 @@ func TestRunApprovedRequest_Success(t *testing.T) {
 	cfg := config.DefaultConfig()
 
 	flagOutput = "text"
-	exitCode, err := runApprovedRequest(context.Background(), out, h.DB, cfg, h.ProjectDir, req.ID)
+	exitCode, err := runApprovedRequest(context.Background(), out, h.DB, cfg, h.ProjectDir, req.ID, sess.ID, nil, nil)
 
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
@@ -174,13 +174,43 @@ func TestRunSafeCommand_LogFailure(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	_, err := runSafeCommand(cmd, out, "echo safe", tmpDir, tmpDir)
+	_, err := runSafeCommand(cmd, out, "echo safe", tmpDir, tmpDir, nil, nil, nil)
 
 	if err == nil {
 		t.Fatal("expected error when log creation fails")
 	}
 }
 
+func TestRunSafePlan_StopsOnFailure(t *testing.T) {
+	cmd := &cobra.Command{Use: "test"}
+	cmd.SetContext(context.Background())
+	outBuf := &bytes.Buffer{}
+	out := output.New(output.FormatJSON, output.WithOutput(outBuf))
+
+	tmpDir := t.TempDir()
+	plan := &db.Plan{Steps: []db.PlanStep{
+		{Command: db.CommandSpec{Raw: "echo one", Cwd: tmpDir, Shell: true}},
+		{Command: db.CommandSpec{Raw: "exit 4", Cwd: tmpDir, Shell: true}},
+		{Command: db.CommandSpec{Raw: "echo three", Cwd: tmpDir, Shell: true}},
+	}}
+
+	flagOutput = "json"
+	defer func() { flagOutput = "text" }()
+	exitCode, err := runSafePlan(cmd, out, plan, tmpDir, nil, nil, nil)
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if exitCode != 4 {
+		t.Errorf("expected exit code 4, got %d", exitCode)
+	}
+	for _, want := range []string{`"status": "succeeded"`, `"status": "failed"`, `"status": "skipped"`} {
+		if !strings.Contains(outBuf.String(), want) {
+			t.Errorf("output missing %s: %s", want, outBuf.String())
+		}
+	}
+}
+
 func TestRunApprovedRequest_ValidationFailure(t *testing.T) {
 	h := testutil.NewHarness(t)
 	// h.Close not needed
@@ -200,7 +230,7 @@ func TestRunApprovedRequest_ValidationFailure(t *testing.T) {
 	cfg := config.DefaultConfig()
 
 	flagOutput = "text"
-	exitCode, err := runApprovedRequest(context.Background(), out, h.DB, cfg, h.ProjectDir, req.ID)
+	exitCode, err := runApprovedRequest(context.Background(), out, h.DB, cfg, h.ProjectDir, req.ID, sess.ID, nil, nil)
 
 	if err != nil {
 		// It might return error if write fails?
@@ -229,7 +259,7 @@ func TestRunApprovedRequest_ExecutionFailure(t *testing.T) {
 	cfg := config.DefaultConfig()
 
 	flagOutput = "text"
-	exitCode, err := runApprovedRequest(context.Background(), out, h.DB, cfg, h.ProjectDir, req.ID)
+	exitCode, err := runApprovedRequest(context.Background(), out, h.DB, cfg, h.ProjectDir, req.ID, sess.ID, nil, nil)
 
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
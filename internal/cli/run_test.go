@@ -8,6 +8,7 @@ import (
 
 	"github.com/Dicklesworthstone/slb/internal/config"
 	"github.com/Dicklesworthstone/slb/internal/db"
+	"github.com/Dicklesworthstone/slb/internal/integrations"
 	"github.com/Dicklesworthstone/slb/internal/testutil"
 	"github.com/spf13/cobra"
 )
@@ -31,7 +32,7 @@ func newTestRunCmd(dbPath string) *cobra.Command {
 	rCmd := &cobra.Command{
 		Use:   "run <command>",
 		Short: "Run a command with approval if required",
-		Args:  cobra.ExactArgs(1),
+		Args:  runCmd.Args,
 		RunE:  runCmd.RunE,
 	}
 	rCmd.Flags().StringVar(&flagRunReason, "reason", "", "reason for command")
@@ -43,6 +44,9 @@ func newTestRunCmd(dbPath string) *cobra.Command {
 	rCmd.Flags().StringSliceVar(&flagRunAttachFile, "attach-file", nil, "attach file")
 	rCmd.Flags().StringSliceVar(&flagRunAttachContext, "attach-context", nil, "attach context")
 	rCmd.Flags().StringSliceVar(&flagRunAttachScreen, "attach-screenshot", nil, "attach screenshot")
+	rCmd.Flags().StringVar(&flagRunPlan, "plan", "", "plan file")
+	addWindowFlags(rCmd, &flagRunWindow)
+	rCmd.Flags().BoolVar(&flagRunWaitWindow, "wait-window", false, "wait for window")
 
 	root.AddCommand(rCmd)
 
@@ -65,6 +69,9 @@ func resetRunFlags() {
 	flagRunAttachFile = nil
 	flagRunAttachContext = nil
 	flagRunAttachScreen = nil
+	flagRunPlan = ""
+	flagRunWindow = windowFlags{}
+	flagRunWaitWindow = false
 }
 
 func TestRunCommand_RequiresCommand(t *testing.T) {
@@ -77,14 +84,27 @@ func TestRunCommand_RequiresCommand(t *testing.T) {
 	if err == nil {
 		t.Fatal("expected error when command is missing")
 	}
-	if !strings.Contains(err.Error(), "accepts 1 arg") {
+	if !strings.Contains(err.Error(), "requires a command or --plan") {
 		t.Errorf("unexpected error: %v", err)
 	}
 }
 
+func TestRunCommand_RejectsCommandWithPlan(t *testing.T) {
+	h := testutil.NewHarness(t)
+	resetRunFlags()
+
+	cmd := newTestRunCmd(h.DBPath)
+	_, _, err := executeCommand(cmd, "run", "echo hello", "--plan", "plan.yaml")
+
+	if err == nil || !strings.Contains(err.Error(), "not both") {
+		t.Errorf("expected an error for a command and --plan, got %v", err)
+	}
+}
+
 func TestRunCommand_RequiresSessionID(t *testing.T) {
 	h := testutil.NewHarness(t)
 	resetRunFlags()
+	withDetectedAgent(t, nil)
 
 	cmd := newTestRunCmd(h.DBPath)
 	_, _, err := executeCommand(cmd, "run", "echo hello", "-C", h.ProjectDir)
@@ -97,6 +117,27 @@ func TestRunCommand_RequiresSessionID(t *testing.T) {
 	}
 }
 
+func TestRunSessionID_DetectedAgent(t *testing.T) {
+	h := testutil.NewHarness(t)
+	resetRunFlags()
+	withDetectedAgent(t, &integrations.Agent{Type: integrations.AgentClaudeCode})
+
+	if _, err := runSessionID(h.DB, h.ProjectDir); err == nil || !strings.Contains(err.Error(), "--session-id is required") {
+		t.Fatalf("expected error without a matching session, got %v", err)
+	}
+
+	sess := testutil.MakeSession(t, h.DB, testutil.WithProject(h.ProjectDir), testutil.WithProgram("claude"))
+	testutil.MakeSession(t, h.DB, testutil.WithProject(h.ProjectDir), testutil.WithAgent("Other"), testutil.WithProgram("codex-cli"))
+
+	id, err := runSessionID(h.DB, h.ProjectDir)
+	if err != nil {
+		t.Fatalf("runSessionID: %v", err)
+	}
+	if id != sess.ID {
+		t.Errorf("session = %s, want %s", id, sess.ID)
+	}
+}
+
 func TestRunCommand_Help(t *testing.T) {
 	h := testutil.NewHarness(t)
 	resetRunFlags()
@@ -0,0 +1,93 @@
+// Package cli implements sandbox flags shared by request and run.
+package cli
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/Dicklesworthstone/slb/internal/config"
+	"github.com/Dicklesworthstone/slb/internal/db"
+	"github.com/spf13/cobra"
+)
+
+// sandboxFlags are the flags requesting sandboxed execution.
+type sandboxFlags struct {
+	enabled    bool
+	writePaths []string
+	noNetwork  bool
+	cpuSecs    int
+	memoryMB   int
+	timeSecs   int
+}
+
+func addSandboxFlags(cmd *cobra.Command, f *sandboxFlags) {
+	cmd.Flags().BoolVar(&f.enabled, "sandbox", false, "execute in a sandbox (Linux) that only allows writes to the paths named in the command")
+	cmd.Flags().StringSliceVar(&f.writePaths, "sandbox-write", nil, "additional path the sandboxed command may write to (repeatable)")
+	cmd.Flags().BoolVar(&f.noNetwork, "no-network", false, "disable network access for the sandboxed command")
+	cmd.Flags().IntVar(&f.cpuSecs, "cpu-limit", 0, "CPU time limit in seconds for the sandboxed command")
+	cmd.Flags().IntVar(&f.memoryMB, "memory-limit", 0, "memory limit in MB for the sandboxed command")
+	cmd.Flags().IntVar(&f.timeSecs, "time-limit", 0, "wall-clock limit in seconds for the sandboxed command")
+}
+
+// spec returns the requested sandbox, or nil when none of the sandbox flags
+// were given. Any restriction flag implies --sandbox.
+func (f *sandboxFlags) spec() (*db.SandboxSpec, error) {
+	if !f.enabled && len(f.writePaths) == 0 && !f.noNetwork && f.cpuSecs == 0 && f.memoryMB == 0 && f.timeSecs == 0 {
+		return nil, nil
+	}
+	if f.cpuSecs < 0 || f.memoryMB < 0 || f.timeSecs < 0 {
+		return nil, fmt.Errorf("sandbox limits cannot be negative")
+	}
+	return &db.SandboxSpec{
+		WritePaths:     f.writePaths,
+		DisableNetwork: f.noNetwork,
+		CPUSeconds:     f.cpuSecs,
+		MemoryMB:       f.memoryMB,
+		TimeoutSeconds: f.timeSecs,
+	}, nil
+}
+
+// sandboxDefaults returns the configured restrictions for sandboxes that
+// reviewers require.
+func sandboxDefaults(cfg config.Config) db.SandboxSpec {
+	return db.SandboxSpec{
+		WritePaths:     cfg.General.SandboxWritePaths,
+		DisableNetwork: cfg.General.SandboxDisableNetwork,
+		CPUSeconds:     cfg.General.SandboxCPUSeconds,
+		MemoryMB:       cfg.General.SandboxMemoryMB,
+		TimeoutSeconds: cfg.General.SandboxTimeoutSeconds,
+	}
+}
+
+// describeSandbox summarizes a sandbox spec on one line.
+func describeSandbox(s *db.SandboxSpec) string {
+	parts := []string{"writes limited to command paths"}
+	if len(s.WritePaths) > 0 {
+		parts[0] += " and " + strings.Join(s.WritePaths, ", ")
+	}
+	if s.DisableNetwork {
+		parts = append(parts, "no network")
+	}
+	if s.CPUSeconds > 0 {
+		parts = append(parts, fmt.Sprintf("cpu %ds", s.CPUSeconds))
+	}
+	if s.MemoryMB > 0 {
+		parts = append(parts, fmt.Sprintf("memory %d MB", s.MemoryMB))
+	}
+	if s.TimeoutSeconds > 0 {
+		parts = append(parts, fmt.Sprintf("time %ds", s.TimeoutSeconds))
+	}
+	return strings.Join(parts, "; ")
+}
+
+// printSandboxViolations reports violations on stderr.
+func printSandboxViolations(violations []string) {
+	if len(violations) == 0 {
+		return
+	}
+	fmt.Fprintf(os.Stderr, "\n[slb] Sandbox violations:\n")
+	for _, v := range violations {
+		fmt.Fprintf(os.Stderr, "  - %s\n", v)
+	}
+}
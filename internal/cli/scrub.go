@@ -0,0 +1,49 @@
+// Package cli implements secret scrubbing settings shared by commands that
+// record output.
+package cli
+
+import (
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/Dicklesworthstone/slb/internal/config"
+	"github.com/Dicklesworthstone/slb/internal/scrub"
+)
+
+// newScrubber returns the scrubber for the built-in detectors plus
+// general.scrub_patterns.
+func newScrubber(cfg config.Config) (*scrub.Scrubber, error) {
+	s, err := scrub.New(cfg.General.ScrubPatterns)
+	if err != nil {
+		return nil, fmt.Errorf("general.scrub_patterns: %w", err)
+	}
+	return s, nil
+}
+
+// totalRedactions returns the number of secrets scrubbed across artifacts.
+func totalRedactions(counts map[string]int) int {
+	total := 0
+	for _, n := range counts {
+		total += n
+	}
+	return total
+}
+
+// printRedactions reports the secrets scrubbed per artifact on stderr.
+func printRedactions(counts map[string]int) {
+	artifacts := make([]string, 0, len(counts))
+	for artifact, n := range counts {
+		if n > 0 {
+			artifacts = append(artifacts, artifact)
+		}
+	}
+	if len(artifacts) == 0 {
+		return
+	}
+	sort.Strings(artifacts)
+	fmt.Fprintf(os.Stderr, "\n[slb] Scrubbed secrets:\n")
+	for _, artifact := range artifacts {
+		fmt.Fprintf(os.Stderr, "  - %s: %d\n", artifact, counts[artifact])
+	}
+}
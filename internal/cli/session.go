@@ -8,8 +8,10 @@ import (
 	"strings"
 	"time"
 
+	"github.com/Dicklesworthstone/slb/internal/config"
 	"github.com/Dicklesworthstone/slb/internal/core"
 	"github.com/Dicklesworthstone/slb/internal/db"
+	"github.com/Dicklesworthstone/slb/internal/integrations"
 	"github.com/Dicklesworthstone/slb/internal/output"
 	"github.com/spf13/cobra"
 )
@@ -22,19 +24,27 @@ var (
 	flagResumeCreateIfMissing bool
 	flagResumeForce           bool
 
+	flagRotateSessionKey string
+
 	flagSessionGCDryRun    bool
 	flagSessionGCThreshold time.Duration
 	flagSessionGCForce     bool
 )
 
+// detectAgent detects the calling agent program; tests replace it so they do
+// not depend on the agent running them.
+var detectAgent = integrations.DetectAgent
+
 func init() {
 	sessionCmd.PersistentFlags().StringVarP(&flagSessionAgent, "agent", "a", "", "agent name (required for start/resume)")
-	sessionCmd.PersistentFlags().StringVarP(&flagSessionProg, "program", "p", "", "agent program (e.g., codex-cli)")
-	sessionCmd.PersistentFlags().StringVarP(&flagSessionModel, "model", "m", "", "agent model (e.g., gpt-5.1-codex)")
+	sessionCmd.PersistentFlags().StringVarP(&flagSessionProg, "program", "p", "", "agent program (e.g., codex-cli; detected when omitted)")
+	sessionCmd.PersistentFlags().StringVarP(&flagSessionModel, "model", "m", "", "agent model (e.g., gpt-5.1-codex; detected when omitted)")
 
 	sessionResumeCmd.Flags().BoolVar(&flagResumeCreateIfMissing, "create-if-missing", true, "create a new session if none active")
 	sessionResumeCmd.Flags().BoolVar(&flagResumeForce, "force", false, "end mismatched active session and create a new one")
 
+	sessionRotateKeyCmd.Flags().StringVarP(&flagRotateSessionKey, "session-key", "k", "", "current session key (required)")
+
 	sessionGcCmd.Flags().BoolVar(&flagSessionGCDryRun, "dry-run", false, "show what would be cleaned up without ending sessions")
 	sessionGcCmd.Flags().DurationVar(&flagSessionGCThreshold, "threshold", 30*time.Minute, "inactivity threshold (e.g., 30m, 2h)")
 	sessionGcCmd.Flags().BoolVarP(&flagSessionGCForce, "force", "f", false, "skip interactive confirmation")
@@ -46,6 +56,7 @@ func init() {
 	sessionCmd.AddCommand(sessionHeartbeatCmd)
 	sessionCmd.AddCommand(sessionResetLimitsCmd)
 	sessionCmd.AddCommand(sessionGcCmd)
+	sessionCmd.AddCommand(sessionRotateKeyCmd)
 }
 
 var sessionCmd = &cobra.Command{
@@ -70,11 +81,18 @@ var sessionStartCmd = &cobra.Command{
 		}
 		defer dbConn.Close()
 
+		attestation, _, err := sessionAttestation(project)
+		if err != nil {
+			return err
+		}
+		program, model := applyDetectedAgent(flagSessionProg, flagSessionModel, attestation)
+
 		session := &db.Session{
 			AgentName:   flagSessionAgent,
-			Program:     flagSessionProg,
-			Model:       flagSessionModel,
+			Program:     program,
+			Model:       model,
 			ProjectPath: project,
+			Attestation: attestation,
 		}
 
 		if err := dbConn.CreateSession(session); err != nil {
@@ -94,6 +112,9 @@ var sessionStartCmd = &cobra.Command{
 			"project_path": session.ProjectPath,
 			"started_at":   session.StartedAt.Format(time.RFC3339),
 		}
+		if session.Attestation != nil {
+			result["attestation"] = session.Attestation
+		}
 		return out.Write(result)
 	},
 }
@@ -140,20 +161,37 @@ var sessionResumeCmd = &cobra.Command{
 		}
 		defer dbConn.Close()
 
+		attestation, mode, err := sessionAttestation(project)
+		if err != nil {
+			return err
+		}
+		// An active session keeps its declared program unless one is given.
+		program := flagSessionProg
+		if program == "" {
+			if active, err := dbConn.GetActiveSession(flagSessionAgent, project); err == nil {
+				program = active.Program
+			}
+		}
+		program, model := applyDetectedAgent(program, flagSessionModel, attestation)
+
 		sess, err := core.ResumeSession(dbConn, core.ResumeOptions{
-			AgentName:        flagSessionAgent,
-			Program:          flagSessionProg,
-			Model:            flagSessionModel,
-			ProjectPath:      project,
-			CreateIfMissing:  flagResumeCreateIfMissing,
-			ForceEndMismatch: flagResumeForce,
+			AgentName:          flagSessionAgent,
+			Program:            program,
+			Model:              model,
+			ProjectPath:        project,
+			CreateIfMissing:    flagResumeCreateIfMissing,
+			ForceEndMismatch:   flagResumeForce,
+			Attestation:        attestation,
+			EnforceAttestation: mode == core.AttestationEnforce,
 		})
 		if err != nil {
+			if errors.Is(err, core.ErrSessionContextMismatch) {
+				return fmt.Errorf("%w (use --force to end it and start a new session)", err)
+			}
 			return err
 		}
 
-		out := output.New(output.Format(GetOutput()))
-		return out.Write(map[string]any{
+		result := map[string]any{
 			"session_id":     sess.ID,
 			"session_key":    sess.SessionKey,
 			"agent_name":     sess.AgentName,
@@ -162,7 +200,15 @@ var sessionResumeCmd = &cobra.Command{
 			"project_path":   sess.ProjectPath,
 			"started_at":     sess.StartedAt.Format(time.RFC3339),
 			"last_active_at": sess.LastActiveAt.Format(time.RFC3339),
-		})
+		}
+		if sess.Attestation != nil {
+			result["attestation"] = sess.Attestation
+			if fields := core.AttestationMismatch(sess.Attestation, attestation); len(fields) > 0 {
+				result["context_mismatch"] = fields
+			}
+		}
+		out := output.New(output.Format(GetOutput()))
+		return out.Write(result)
 	},
 }
 
@@ -186,13 +232,14 @@ var sessionListCmd = &cobra.Command{
 		}
 
 		type sessionView struct {
-			SessionID   string `json:"session_id"`
-			AgentName   string `json:"agent_name"`
-			Program     string `json:"program"`
-			Model       string `json:"model"`
-			ProjectPath string `json:"project_path"`
-			StartedAt   string `json:"started_at"`
-			LastActive  string `json:"last_active_at"`
+			SessionID   string                 `json:"session_id"`
+			AgentName   string                 `json:"agent_name"`
+			Program     string                 `json:"program"`
+			Model       string                 `json:"model"`
+			ProjectPath string                 `json:"project_path"`
+			StartedAt   string                 `json:"started_at"`
+			LastActive  string                 `json:"last_active_at"`
+			Attestation *db.SessionAttestation `json:"attestation,omitempty"`
 		}
 
 		resp := make([]sessionView, 0, len(sessions))
@@ -205,6 +252,7 @@ var sessionListCmd = &cobra.Command{
 				ProjectPath: s.ProjectPath,
 				StartedAt:   s.StartedAt.Format(time.RFC3339),
 				LastActive:  s.LastActiveAt.Format(time.RFC3339),
+				Attestation: s.Attestation,
 			})
 		}
 
@@ -213,6 +261,45 @@ var sessionListCmd = &cobra.Command{
 	},
 }
 
+var sessionRotateKeyCmd = &cobra.Command{
+	Use:   "rotate-key",
+	Short: "Replace a session's HMAC key",
+	Long: `Replace a session's HMAC key with a new one.
+
+The current key is required. Reviews signed with the old key remain
+verifiable; new reviews must be signed with the new key.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if flagSessionID == "" {
+			return fmt.Errorf("--session-id is required")
+		}
+		if flagRotateSessionKey == "" {
+			return fmt.Errorf("--session-key is required")
+		}
+		dbConn, err := db.OpenAndMigrate(GetDB())
+		if err != nil {
+			return err
+		}
+		defer dbConn.Close()
+
+		sess, err := core.RotateSessionKey(dbConn, flagSessionID, flagRotateSessionKey)
+		if err != nil {
+			return err
+		}
+		retired, err := dbConn.ListRetiredSessionKeys(sess.ID)
+		if err != nil {
+			return err
+		}
+
+		out := output.New(output.Format(GetOutput()))
+		return out.Write(map[string]any{
+			"session_id":   sess.ID,
+			"session_key":  sess.SessionKey,
+			"rotated_at":   retired[len(retired)-1].RetiredAt.Format(time.RFC3339),
+			"retired_keys": len(retired),
+		})
+	},
+}
+
 var sessionHeartbeatCmd = &cobra.Command{
 	Use:   "heartbeat",
 	Short: "Update session heartbeat (last_active_at)",
@@ -391,6 +478,39 @@ var sessionGcCmd = &cobra.Command{
 	},
 }
 
+// sessionAttestation collects the caller's process context unless session
+// attestation is turned off in config.
+func sessionAttestation(project string) (*db.SessionAttestation, core.AttestationMode, error) {
+	cfg, err := config.Load(config.LoadOptions{ProjectDir: project, ConfigPath: flagConfig})
+	if err != nil {
+		return nil, "", fmt.Errorf("loading config: %w", err)
+	}
+	mode := core.AttestationMode(cfg.Agents.SessionAttestation)
+	if mode == core.AttestationOff {
+		return nil, mode, nil
+	}
+	return core.CollectAttestation(), mode, nil
+}
+
+// applyDetectedAgent fills an empty program or model from the detected agent
+// and flags a declared program that is not the detected one on the
+// attestation.
+func applyDetectedAgent(program, model string, attestation *db.SessionAttestation) (string, string) {
+	agent := detectAgent()
+	if agent == nil {
+		return program, model
+	}
+	if program == "" {
+		program = string(agent.Type)
+	} else if attestation != nil && !integrations.ProgramMatches(program, agent.Type) {
+		attestation.ProgramMismatch = true
+	}
+	if model == "" {
+		model = agent.Model
+	}
+	return program, model
+}
+
 func projectPath() (string, error) {
 	if flagProject != "" {
 		return flagProject, nil
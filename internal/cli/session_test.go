@@ -10,6 +10,7 @@ import (
 	"time"
 
 	"github.com/Dicklesworthstone/slb/internal/db"
+	"github.com/Dicklesworthstone/slb/internal/integrations"
 	"github.com/Dicklesworthstone/slb/internal/testutil"
 	"github.com/spf13/cobra"
 )
@@ -88,10 +89,19 @@ func resetSessionFlags() {
 	flagSessionModel = ""
 	flagResumeCreateIfMissing = true
 	flagResumeForce = false
+	flagRotateSessionKey = ""
 	flagSessionGCDryRun = false
 	flagSessionGCForce = false
 }
 
+// withDetectedAgent makes agent detection return agent for the test.
+func withDetectedAgent(t *testing.T, agent *integrations.Agent) {
+	t.Helper()
+	old := detectAgent
+	detectAgent = func() *integrations.Agent { return agent }
+	t.Cleanup(func() { detectAgent = old })
+}
+
 func TestSessionStart_RequiresAgent(t *testing.T) {
 	h := testutil.NewHarness(t)
 	resetSessionFlags()
@@ -147,6 +157,42 @@ func TestSessionStart_CreatesSession(t *testing.T) {
 	}
 }
 
+func TestSessionStart_DetectedAgent(t *testing.T) {
+	h := testutil.NewHarness(t)
+	withDetectedAgent(t, &integrations.Agent{Type: integrations.AgentCodexCLI, Model: "gpt-5-codex"})
+
+	start := func(args ...string) map[string]any {
+		t.Helper()
+		resetSessionFlags()
+		cmd := newTestSessionCmd(h.DBPath)
+		stdout, err := executeCommandCapture(t, cmd, append([]string{"session", "start", "-C", h.ProjectDir, "-j"}, args...)...)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		var result map[string]any
+		if err := json.Unmarshal([]byte(stdout), &result); err != nil {
+			t.Fatalf("failed to parse JSON: %v\nstdout: %s", err, stdout)
+		}
+		return result
+	}
+
+	detected := start("-a", "Detected")
+	if detected["program"] != "codex-cli" || detected["model"] != "gpt-5-codex" {
+		t.Errorf("expected detected program and model, got %v %v", detected["program"], detected["model"])
+	}
+	if att, _ := detected["attestation"].(map[string]any); att == nil || att["program_mismatch"] != nil {
+		t.Errorf("unexpected attestation: %v", detected["attestation"])
+	}
+
+	declared := start("-a", "Declared", "-p", "claude-code", "-m", "opus")
+	if declared["program"] != "claude-code" || declared["model"] != "opus" {
+		t.Errorf("declared values should win, got %v %v", declared["program"], declared["model"])
+	}
+	if att, _ := declared["attestation"].(map[string]any); att == nil || att["program_mismatch"] != true {
+		t.Errorf("expected program_mismatch on attestation, got %v", declared["attestation"])
+	}
+}
+
 func TestSessionStart_DuplicatePrevented(t *testing.T) {
 	h := testutil.NewHarness(t)
 	resetSessionFlags()
@@ -410,6 +456,36 @@ func TestSessionHeartbeat_UpdatesTimestamp(t *testing.T) {
 	}
 }
 
+func TestSessionRotateKey(t *testing.T) {
+	h := testutil.NewHarness(t)
+	resetSessionFlags()
+
+	sess := testutil.MakeSession(t, h.DB, testutil.WithProject(h.ProjectDir))
+
+	cmd := newTestSessionCmd(h.DBPath)
+	if _, err := executeCommandCapture(t, cmd, "session", "rotate-key", "-s", sess.ID, "-k", "wrong", "-j"); err == nil {
+		t.Fatal("expected error for wrong session key")
+	}
+
+	resetSessionFlags()
+	cmd = newTestSessionCmd(h.DBPath)
+	stdout, err := executeCommandCapture(t, cmd, "session", "rotate-key", "-s", sess.ID, "-k", sess.SessionKey, "-j")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var result map[string]any
+	if err := json.Unmarshal([]byte(stdout), &result); err != nil {
+		t.Fatalf("failed to parse JSON: %v\nstdout: %s", err, stdout)
+	}
+	if key, _ := result["session_key"].(string); key == "" || key == sess.SessionKey {
+		t.Errorf("expected a new session_key, got %v", result["session_key"])
+	}
+	if result["retired_keys"] != float64(1) {
+		t.Errorf("expected retired_keys=1, got %v", result["retired_keys"])
+	}
+}
+
 func TestSessionResetLimits_RequiresSessionID(t *testing.T) {
 	h := testutil.NewHarness(t)
 	resetSessionFlags()
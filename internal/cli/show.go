@@ -3,8 +3,10 @@ package cli
 
 import (
 	"fmt"
+	"os"
 	"time"
 
+	"github.com/Dicklesworthstone/slb/internal/core"
 	"github.com/Dicklesworthstone/slb/internal/db"
 	"github.com/Dicklesworthstone/slb/internal/output"
 	"github.com/spf13/cobra"
@@ -14,12 +16,20 @@ var (
 	flagShowWithReviews     bool
 	flagShowWithExecution   bool
 	flagShowWithAttachments bool
+	flagShowWithSimilar     bool
+	flagShowReplay          bool
+	flagShowReplaySpeed     float64
+	flagShowReplayMaxIdle   time.Duration
 )
 
 func init() {
 	showCmd.Flags().BoolVar(&flagShowWithReviews, "with-reviews", true, "include full review details")
 	showCmd.Flags().BoolVar(&flagShowWithExecution, "with-execution", true, "include execution details")
 	showCmd.Flags().BoolVar(&flagShowWithAttachments, "with-attachments", false, "include attachment content")
+	showCmd.Flags().BoolVar(&flagShowWithSimilar, "with-similar", true, "include similar past requests and their outcomes")
+	showCmd.Flags().BoolVar(&flagShowReplay, "replay", false, "replay the terminal transcript of a --pty execution")
+	showCmd.Flags().Float64Var(&flagShowReplaySpeed, "speed", 1, "replay speed multiplier (0 prints the transcript at once)")
+	showCmd.Flags().DurationVar(&flagShowReplayMaxIdle, "max-idle", 2*time.Second, "shorten pauses in the replay to at most this long (0 keeps them)")
 
 	rootCmd.AddCommand(showCmd)
 }
@@ -30,11 +40,22 @@ var showCmd = &cobra.Command{
 	Long: `Show detailed information about a specific command approval request.
 
 This shows the full request details including:
-- Command and classification
+- Command and classification (each step of a plan)
 - Justification
+- Execution environment fingerprint (kube context, cloud profile, git branch, ...)
 - Reviews and approvals
-- Execution results (if executed)
-- Attachments (with --with-attachments)`,
+- Execution results (if executed), per step for plans
+- Similar past requests with their decisions and outcomes
+- Attachments (with --with-attachments)
+- Secrets scrubbed from each artifact (log, transcript, dry run, attachments)
+
+For commands executed with --pty, --replay plays the recorded terminal
+session back with its original timing instead.
+
+Examples:
+  slb show abc123
+  slb show abc123 --replay
+  slb show abc123 --replay --speed 4`,
 	Args: cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		requestID := args[0]
@@ -51,6 +72,10 @@ This shows the full request details including:
 			return fmt.Errorf("getting request: %w", err)
 		}
 
+		if flagShowReplay {
+			return replayExecution(cmd, request)
+		}
+
 		// Build detailed response
 		type attachmentView struct {
 			Type     string         `json:"type"`
@@ -73,13 +98,16 @@ This shows the full request details including:
 			Decision          string         `json:"decision"`
 			Signature         string         `json:"signature,omitempty"`
 			SignatureTime     string         `json:"signature_timestamp,omitempty"`
+			SignatureValid    bool           `json:"signature_valid"`
 			Responses         *responsesView `json:"responses,omitempty"`
 			Comments          string         `json:"comments,omitempty"`
+			RequireSandbox    bool           `json:"require_sandbox,omitempty"`
 			CreatedAt         string         `json:"created_at"`
 		}
 
 		type executionView struct {
 			LogPath             string `json:"log_path,omitempty"`
+			TranscriptPath      string `json:"transcript_path,omitempty"`
 			ExitCode            *int   `json:"exit_code,omitempty"`
 			DurationMs          *int64 `json:"duration_ms,omitempty"`
 			ExecutedAt          string `json:"executed_at,omitempty"`
@@ -116,26 +144,37 @@ This shows the full request details including:
 		}
 
 		type showView struct {
-			RequestID             string            `json:"request_id"`
-			ProjectPath           string            `json:"project_path"`
-			Command               commandView       `json:"command"`
-			RiskTier              string            `json:"risk_tier"`
-			Status                string            `json:"status"`
-			MinApprovals          int               `json:"min_approvals"`
-			RequireDifferentModel bool              `json:"require_different_model"`
-			RequestorSessionID    string            `json:"requestor_session_id"`
-			RequestorAgent        string            `json:"requestor_agent"`
-			RequestorModel        string            `json:"requestor_model"`
-			Justification         justificationView `json:"justification"`
-			DryRun                *dryRunView       `json:"dry_run,omitempty"`
-			Attachments           []attachmentView  `json:"attachments,omitempty"`
-			Reviews               []reviewView      `json:"reviews,omitempty"`
-			Execution             *executionView    `json:"execution,omitempty"`
-			Rollback              *rollbackView     `json:"rollback,omitempty"`
-			CreatedAt             string            `json:"created_at"`
-			ResolvedAt            string            `json:"resolved_at,omitempty"`
-			ExpiresAt             string            `json:"expires_at,omitempty"`
-			ApprovalExpiresAt     string            `json:"approval_expires_at,omitempty"`
+			RequestID             string                    `json:"request_id"`
+			ProjectPath           string                    `json:"project_path"`
+			Command               commandView               `json:"command"`
+			RiskTier              string                    `json:"risk_tier"`
+			Status                string                    `json:"status"`
+			MinApprovals          int                       `json:"min_approvals"`
+			RequireDifferentModel bool                      `json:"require_different_model"`
+			RequestorSessionID    string                    `json:"requestor_session_id"`
+			RequestorAgent        string                    `json:"requestor_agent"`
+			RequestorModel        string                    `json:"requestor_model"`
+			RequestorAttestation  *db.SessionAttestation    `json:"requestor_attestation,omitempty"`
+			Environment           db.EnvironmentFingerprint `json:"environment,omitempty"`
+			Sandbox               *db.SandboxSpec           `json:"sandbox,omitempty"`
+			Plan                  *db.Plan                  `json:"plan,omitempty"`
+			Window                *db.ExecutionWindow       `json:"window,omitempty"`
+			Policy                *db.PolicyDecision        `json:"policy,omitempty"`
+			AutoAnswers           []db.AutoAnswer           `json:"auto_answers,omitempty"`
+			Justification         justificationView         `json:"justification"`
+			DryRun                *dryRunView               `json:"dry_run,omitempty"`
+			Attachments           []attachmentView          `json:"attachments,omitempty"`
+			Redactions            map[string]int            `json:"redactions,omitempty"`
+			Reviews               []reviewView              `json:"reviews,omitempty"`
+			Execution             *executionView            `json:"execution,omitempty"`
+			PlanSteps             []db.PlanStepResult       `json:"plan_steps,omitempty"`
+			Rollback              *rollbackView             `json:"rollback,omitempty"`
+			Similar               []core.SimilarRequest     `json:"similar,omitempty"`
+			SimilarSummary        *core.SimilarSummary      `json:"similar_summary,omitempty"`
+			CreatedAt             string                    `json:"created_at"`
+			ResolvedAt            string                    `json:"resolved_at,omitempty"`
+			ExpiresAt             string                    `json:"expires_at,omitempty"`
+			ApprovalExpiresAt     string                    `json:"approval_expires_at,omitempty"`
 		}
 
 		view := showView{
@@ -148,6 +187,12 @@ This shows the full request details including:
 			RequestorSessionID:    request.RequestorSessionID,
 			RequestorAgent:        request.RequestorAgent,
 			RequestorModel:        request.RequestorModel,
+			Environment:           request.Environment,
+			Sandbox:               request.Sandbox,
+			Window:                request.Window,
+			Policy:                request.Policy,
+			AutoAnswers:           request.AutoAnswers,
+			Plan:                  request.Plan,
 			CreatedAt:             request.CreatedAt.Format(time.RFC3339),
 			Command: commandView{
 				Raw:               request.Command.Raw,
@@ -166,6 +211,11 @@ This shows the full request details including:
 			},
 		}
 
+		// Where the requestor's session was started from, when attested.
+		if sess, err := dbConn.GetSession(request.RequestorSessionID); err == nil {
+			view.RequestorAttestation = sess.Attestation
+		}
+
 		// Timestamps
 		if request.ResolvedAt != nil {
 			view.ResolvedAt = request.ResolvedAt.Format(time.RFC3339)
@@ -197,11 +247,15 @@ This shows the full request details including:
 					Decision:          string(r.Decision),
 					Signature:         r.Signature,
 					Comments:          r.Comments,
+					RequireSandbox:    r.RequireSandbox,
 					CreatedAt:         r.CreatedAt.Format(time.RFC3339),
 				}
 				if !r.SignatureTimestamp.IsZero() {
 					rv.SignatureTime = r.SignatureTimestamp.Format(time.RFC3339)
 				}
+				// Checked against the key the review was signed with, which
+				// may have been retired by a rotation since.
+				rv.SignatureValid, _ = dbConn.VerifyStoredReviewSignature(r)
 				// Include responses if any field is non-empty
 				if r.Responses.ReasonResponse != "" || r.Responses.EffectResponse != "" ||
 					r.Responses.GoalResponse != "" || r.Responses.SafetyResponse != "" {
@@ -226,6 +280,9 @@ This shows the full request details including:
 				ExecutedByAgent:     request.Execution.ExecutedByAgent,
 				ExecutedByModel:     request.Execution.ExecutedByModel,
 			}
+			if path := transcriptPath(request); path != "" {
+				view.Execution.TranscriptPath = path
+			}
 			if request.Execution.ExecutedAt != nil {
 				view.Execution.ExecutedAt = request.Execution.ExecutedAt.Format(time.RFC3339)
 			}
@@ -257,7 +314,61 @@ This shows the full request details including:
 			}
 		}
 
+		// Secrets scrubbed from the request's artifacts
+		if redactions, err := dbConn.ListRedactions(request.ID); err == nil && len(redactions) > 0 {
+			view.Redactions = make(map[string]int, len(redactions))
+			for _, r := range redactions {
+				view.Redactions[r.Artifact] = r.Count
+			}
+		}
+
+		// Per-step results of an executed plan
+		if request.Plan != nil {
+			if steps, err := dbConn.ListPlanStepResults(request.ID); err == nil && len(steps) > 0 {
+				view.PlanSteps = steps
+			}
+		}
+
+		// Similar past requests
+		if flagShowWithSimilar {
+			similar, err := core.FindSimilarRequests(dbConn, request, core.DefaultSimilarLimit)
+			if err != nil {
+				return fmt.Errorf("finding similar requests: %w", err)
+			}
+			if len(similar) > 0 {
+				summary := core.SummarizeSimilar(similar)
+				view.Similar = similar
+				view.SimilarSummary = &summary
+			}
+		}
+
 		out := output.New(output.Format(GetOutput()))
 		return out.Write(view)
 	},
 }
+
+// transcriptPath returns the transcript recorded for the request's
+// execution, or "" when it was not executed with --pty.
+func transcriptPath(request *db.Request) string {
+	if request.Execution == nil || request.Execution.LogPath == "" {
+		return ""
+	}
+	path := core.TranscriptPath(request.Execution.LogPath)
+	if _, err := os.Stat(path); err != nil {
+		return ""
+	}
+	return path
+}
+
+// replayExecution plays the request's PTY transcript back on stdout.
+func replayExecution(cmd *cobra.Command, request *db.Request) error {
+	path := transcriptPath(request)
+	if path == "" {
+		return fmt.Errorf("request %s has no terminal transcript (execute it with --pty to record one)", request.ID)
+	}
+	transcript, err := core.ReadTranscript(path)
+	if err != nil {
+		return err
+	}
+	return core.ReplayTranscript(cmd.Context(), os.Stdout, transcript, flagShowReplaySpeed, flagShowReplayMaxIdle)
+}
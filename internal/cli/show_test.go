@@ -2,9 +2,13 @@ package cli
 
 import (
 	"encoding/json"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/Dicklesworthstone/slb/internal/core"
 	"github.com/Dicklesworthstone/slb/internal/db"
 	"github.com/Dicklesworthstone/slb/internal/testutil"
 	"github.com/spf13/cobra"
@@ -33,6 +37,10 @@ func newTestShowCmd(dbPath string) *cobra.Command {
 	showCmdTest.Flags().BoolVar(&flagShowWithReviews, "with-reviews", true, "include reviews")
 	showCmdTest.Flags().BoolVar(&flagShowWithExecution, "with-execution", true, "include execution")
 	showCmdTest.Flags().BoolVar(&flagShowWithAttachments, "with-attachments", false, "include attachments")
+	showCmdTest.Flags().BoolVar(&flagShowWithSimilar, "with-similar", true, "include similar requests")
+	showCmdTest.Flags().BoolVar(&flagShowReplay, "replay", false, "replay transcript")
+	showCmdTest.Flags().Float64Var(&flagShowReplaySpeed, "speed", 1, "replay speed")
+	showCmdTest.Flags().DurationVar(&flagShowReplayMaxIdle, "max-idle", 2*time.Second, "max replay pause")
 
 	root.AddCommand(showCmdTest)
 
@@ -47,6 +55,10 @@ func resetShowFlags() {
 	flagShowWithReviews = true
 	flagShowWithExecution = true
 	flagShowWithAttachments = false
+	flagShowWithSimilar = true
+	flagShowReplay = false
+	flagShowReplaySpeed = 1
+	flagShowReplayMaxIdle = 2 * time.Second
 }
 
 func TestShowCommand_RequiresRequestID(t *testing.T) {
@@ -266,3 +278,215 @@ func TestShowCommand_JustificationFields(t *testing.T) {
 		t.Errorf("expected justification.expected_effect, got %v", just["expected_effect"])
 	}
 }
+
+func TestShowCommand_IncludesSimilarRequests(t *testing.T) {
+	h := testutil.NewHarness(t)
+	resetShowFlags()
+
+	sess := testutil.MakeSession(t, h.DB, testutil.WithProject(h.ProjectDir))
+	earlier := testutil.MakeRequest(t, h.DB, sess,
+		testutil.WithCommand("git push --force origin main", h.ProjectDir, true),
+	)
+	exit := 1
+	if err := h.DB.UpdateRequestExecution(earlier.ID, &db.Execution{ExitCode: &exit}); err != nil {
+		t.Fatalf("UpdateRequestExecution: %v", err)
+	}
+	if _, err := h.DB.RecordOutcome(earlier.ID, true, "overwrote commits", nil, ""); err != nil {
+		t.Fatalf("RecordOutcome: %v", err)
+	}
+	req := testutil.MakeRequest(t, h.DB, sess,
+		testutil.WithCommand("git push -f origin main", h.ProjectDir, true),
+	)
+
+	stdout, err := executeCommandCapture(t, newTestShowCmd(h.DBPath), "show", req.ID, "-j")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var result struct {
+		Similar []struct {
+			RequestID          string `json:"request_id"`
+			Match              string `json:"match"`
+			ExitCode           *int   `json:"exit_code"`
+			CausedProblems     bool   `json:"caused_problems"`
+			ProblemDescription string `json:"problem_description"`
+		} `json:"similar"`
+		SimilarSummary struct {
+			Total    int `json:"total"`
+			Problems int `json:"caused_problems"`
+		} `json:"similar_summary"`
+	}
+	if err := json.Unmarshal([]byte(stdout), &result); err != nil {
+		t.Fatalf("failed to parse JSON: %v\nstdout: %s", err, stdout)
+	}
+	if len(result.Similar) != 1 {
+		t.Fatalf("expected 1 similar request, got %+v", result.Similar)
+	}
+	s := result.Similar[0]
+	if s.RequestID != earlier.ID || s.Match != "target" || s.ExitCode == nil || *s.ExitCode != 1 ||
+		!s.CausedProblems || s.ProblemDescription != "overwrote commits" {
+		t.Errorf("unexpected similar request %+v", s)
+	}
+	if result.SimilarSummary.Total != 1 || result.SimilarSummary.Problems != 1 {
+		t.Errorf("unexpected summary %+v", result.SimilarSummary)
+	}
+
+	// --with-similar=false leaves it out.
+	resetShowFlags()
+	stdout, err = executeCommandCapture(t, newTestShowCmd(h.DBPath), "show", req.ID, "-j", "--with-similar=false")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if strings.Contains(stdout, `"similar"`) {
+		t.Errorf("expected no similar requests, got %s", stdout)
+	}
+}
+
+func TestShowCommand_ReplaysTranscript(t *testing.T) {
+	h := testutil.NewHarness(t)
+	resetShowFlags()
+
+	sess := testutil.MakeSession(t, h.DB, testutil.WithProject(h.ProjectDir))
+	req := testutil.MakeRequest(t, h.DB, sess, testutil.WithCommand("terraform apply", h.ProjectDir, true))
+
+	cmd := newTestShowCmd(h.DBPath)
+	if _, err := executeCommandCapture(t, cmd, "show", req.ID, "--replay"); err == nil || !strings.Contains(err.Error(), "no terminal transcript") {
+		t.Fatalf("expected missing transcript error, got %v", err)
+	}
+
+	logPath := filepath.Join(h.ProjectDir, ".slb", "logs", "20260101-120000_abcd1234.log")
+	if err := os.MkdirAll(filepath.Dir(logPath), 0o700); err != nil {
+		t.Fatal(err)
+	}
+	cast := `{"version": 2, "width": 80, "height": 24, "command": "terraform apply"}
+[0.1, "o", "Enter a value: "]
+[5.0, "i", "yes\r"]
+[5.1, "o", "yes\r\nApply complete!\r\n"]
+`
+	if err := os.WriteFile(strings.TrimSuffix(logPath, ".log")+".cast", []byte(cast), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := h.DB.UpdateRequestExecution(req.ID, &db.Execution{LogPath: logPath}); err != nil {
+		t.Fatalf("UpdateRequestExecution: %v", err)
+	}
+
+	resetShowFlags()
+	cmd = newTestShowCmd(h.DBPath)
+	stdout, err := executeCommandCapture(t, cmd, "show", req.ID, "--replay", "--speed", "0")
+	if err != nil {
+		t.Fatalf("replay: %v", err)
+	}
+	if stdout != "Enter a value: yes\r\nApply complete!\r\n" {
+		t.Errorf("replayed %q", stdout)
+	}
+
+	resetShowFlags()
+	cmd = newTestShowCmd(h.DBPath)
+	stdout, err = executeCommandCapture(t, cmd, "show", req.ID, "-j")
+	if err != nil {
+		t.Fatalf("show: %v", err)
+	}
+	if !strings.Contains(stdout, `"transcript_path"`) {
+		t.Errorf("expected transcript_path in JSON output:\n%s", stdout)
+	}
+}
+
+func TestShowCommand_ShowsRedactions(t *testing.T) {
+	h := testutil.NewHarness(t)
+	resetShowFlags()
+
+	sess := testutil.MakeSession(t, h.DB, testutil.WithProject(h.ProjectDir))
+	req := testutil.MakeRequest(t, h.DB, sess)
+	if err := h.DB.RecordRedactions(req.ID, core.ArtifactExecutionLog, 2); err != nil {
+		t.Fatalf("RecordRedactions: %v", err)
+	}
+
+	cmd := newTestShowCmd(h.DBPath)
+	stdout, err := executeCommandCapture(t, cmd, "show", req.ID, "-j")
+	if err != nil {
+		t.Fatalf("show: %v", err)
+	}
+	var result map[string]any
+	if err := json.Unmarshal([]byte(stdout), &result); err != nil {
+		t.Fatalf("parsing JSON: %v\n%s", err, stdout)
+	}
+	redactions, ok := result["redactions"].(map[string]any)
+	if !ok || redactions[core.ArtifactExecutionLog] != float64(2) {
+		t.Errorf("redactions = %v, want %s: 2", result["redactions"], core.ArtifactExecutionLog)
+	}
+}
+
+func TestShowCommand_ShowsAutoAnswers(t *testing.T) {
+	h := testutil.NewHarness(t)
+	resetShowFlags()
+
+	sess := testutil.MakeSession(t, h.DB, testutil.WithProject(h.ProjectDir))
+	req := testutil.MakeRequest(t, h.DB, sess, func(r *db.Request) {
+		r.AutoAnswers = []db.AutoAnswer{{Pattern: `Enter a value:\s*$`, Answer: "yes"}}
+	})
+
+	stdout, err := executeCommandCapture(t, newTestShowCmd(h.DBPath), "show", req.ID, "-j")
+	if err != nil {
+		t.Fatalf("show: %v", err)
+	}
+	var result struct {
+		AutoAnswers []db.AutoAnswer `json:"auto_answers"`
+	}
+	if err := json.Unmarshal([]byte(stdout), &result); err != nil {
+		t.Fatalf("parsing JSON: %v\n%s", err, stdout)
+	}
+	if len(result.AutoAnswers) != 1 || result.AutoAnswers[0].Answer != "yes" {
+		t.Errorf("auto_answers = %+v", result.AutoAnswers)
+	}
+}
+
+func TestShowCommand_VerifiesSignaturesAfterRotation(t *testing.T) {
+	h := testutil.NewHarness(t)
+	resetShowFlags()
+
+	requestor := testutil.MakeSession(t, h.DB, testutil.WithProject(h.ProjectDir), testutil.WithAgent("Requestor"))
+	signer := testutil.MakeSession(t, h.DB, testutil.WithProject(h.ProjectDir), testutil.WithAgent("Signer"))
+	unsigned := testutil.MakeSession(t, h.DB, testutil.WithProject(h.ProjectDir), testutil.WithAgent("Unsigned"))
+	req := testutil.MakeRequest(t, h.DB, requestor)
+
+	signedAt := time.Now().UTC().Truncate(time.Second)
+	for _, r := range []*db.Review{
+		{
+			RequestID: req.ID, ReviewerSessionID: signer.ID, ReviewerAgent: signer.AgentName, ReviewerModel: signer.Model,
+			Decision: db.DecisionApprove, SignatureTimestamp: signedAt,
+			Signature: db.ComputeReviewSignature(signer.SessionKey, req.ID, db.DecisionApprove, signedAt),
+		},
+		{
+			RequestID: req.ID, ReviewerSessionID: unsigned.ID, ReviewerAgent: unsigned.AgentName, ReviewerModel: unsigned.Model,
+			Decision: db.DecisionApprove, Signature: "forged",
+		},
+	} {
+		if err := h.DB.CreateReview(r); err != nil {
+			t.Fatalf("CreateReview: %v", err)
+		}
+	}
+	if _, err := h.DB.RotateSessionKey(signer.ID); err != nil {
+		t.Fatalf("RotateSessionKey: %v", err)
+	}
+
+	cmd := newTestShowCmd(h.DBPath)
+	stdout, err := executeCommandCapture(t, cmd, "show", req.ID, "-j")
+	if err != nil {
+		t.Fatalf("show: %v", err)
+	}
+	var result struct {
+		Reviews []struct {
+			ReviewerAgent  string `json:"reviewer_agent"`
+			SignatureValid bool   `json:"signature_valid"`
+		} `json:"reviews"`
+	}
+	if err := json.Unmarshal([]byte(stdout), &result); err != nil {
+		t.Fatalf("parsing JSON: %v\n%s", err, stdout)
+	}
+	valid := map[string]bool{}
+	for _, r := range result.Reviews {
+		valid[r.ReviewerAgent] = r.SignatureValid
+	}
+	if len(valid) != 2 || !valid["Signer"] || valid["Unsigned"] {
+		t.Errorf("signature_valid by reviewer = %v, want Signer valid after rotation and Unsigned invalid", valid)
+	}
+}
@@ -0,0 +1,227 @@
+// Package cli implements the tail command.
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/Dicklesworthstone/slb/internal/daemon"
+	"github.com/Dicklesworthstone/slb/internal/db"
+	"github.com/spf13/cobra"
+)
+
+var flagTailPollInterval time.Duration
+
+func init() {
+	tailCmd.Flags().DurationVar(&flagTailPollInterval, "poll-interval", 500*time.Millisecond, "log polling interval when the daemon is not running")
+	rootCmd.AddCommand(tailCmd)
+}
+
+var tailCmd = &cobra.Command{
+	Use:   "tail <request-id>",
+	Short: "Follow the output of an executing request",
+	Long: `Follow the output of an approved command while it executes.
+
+The executing slb process publishes the command's output through the daemon;
+tail prints it as it arrives and exits when the execution finishes. Output
+written before tail started is in the execution log. Without a running
+daemon, tail follows the execution log file instead.
+
+With --json each output chunk is printed as one JSON object per line.
+
+Stop a runaway command with 'slb abort <request-id>'.
+
+Examples:
+  slb tail abc123
+  slb tail abc123 --json`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		requestID := args[0]
+
+		dbConn, err := db.Open(GetDB())
+		if err != nil {
+			return fmt.Errorf("opening database: %w", err)
+		}
+		defer dbConn.Close()
+
+		request, err := dbConn.GetRequest(requestID)
+		if err != nil {
+			return fmt.Errorf("getting request: %w", err)
+		}
+		if request.Status.IsTerminal() {
+			return printExecutionLog(cmd.OutOrStdout(), request)
+		}
+
+		ctx, cancel := context.WithCancel(cmd.Context())
+		defer cancel()
+		sigCh := make(chan os.Signal, 1)
+		signal.Notify(sigCh, os.Interrupt, syscall.SIGTERM)
+		defer signal.Stop(sigCh)
+		go func() {
+			select {
+			case <-sigCh:
+				cancel()
+			case <-ctx.Done():
+			}
+		}()
+
+		if daemon.NewClient().IsDaemonRunning() {
+			return tailDaemon(ctx, dbConn, request, cmd.OutOrStdout())
+		}
+		daemon.ShowDegradedWarningQuiet()
+		return tailLog(ctx, dbConn, request.ID, cmd.OutOrStdout())
+	},
+}
+
+// tailDaemon prints the execution_output events of the request until it
+// has executed.
+func tailDaemon(ctx context.Context, dbConn *db.DB, request *db.Request, out io.Writer) error {
+	ipcClient := daemon.NewIPCClient(daemon.DefaultSocketPath())
+	defer ipcClient.Close()
+
+	events, err := ipcClient.Subscribe(ctx)
+	if err != nil {
+		return fmt.Errorf("subscribing to events: %w", err)
+	}
+
+	// The execution may have finished before the subscription started.
+	if current, err := dbConn.GetRequest(request.ID); err == nil && current.Status.IsTerminal() {
+		return printExecutionLog(out, current)
+	}
+
+	enc := json.NewEncoder(out)
+	var seq int64
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case event, ok := <-events:
+			if !ok {
+				return nil
+			}
+			if chunk := daemon.ToExecutionOutput(event); chunk != nil {
+				if chunk.RequestID != request.ID {
+					continue
+				}
+				if GetOutput() == "json" {
+					if err := enc.Encode(chunk); err != nil {
+						return fmt.Errorf("encoding output: %w", err)
+					}
+					seq = chunk.Seq
+					continue
+				}
+				if seq > 0 && chunk.Seq > seq+1 {
+					fmt.Fprintf(os.Stderr, "\n[slb] %d output chunk(s) missed; see the execution log\n", chunk.Seq-seq-1)
+				}
+				if chunk.Dropped > 0 {
+					fmt.Fprintf(os.Stderr, "\n[slb] %d bytes of output dropped; see the execution log\n", chunk.Dropped)
+				}
+				seq = chunk.Seq
+				if _, err := io.WriteString(out, chunk.Data); err != nil {
+					return err
+				}
+				continue
+			}
+
+			ev := daemon.ToRequestStreamEvent(event)
+			if ev.RequestID != request.ID {
+				continue
+			}
+			switch ev.Event {
+			case daemon.EventExecutionAborted:
+				if GetOutput() == "json" {
+					_ = enc.Encode(ev)
+					continue
+				}
+				fmt.Fprintf(os.Stderr, "\n[slb] Execution aborted by %s", ev.AbortedBy)
+				if ev.Reason != "" {
+					fmt.Fprintf(os.Stderr, ": %s", ev.Reason)
+				}
+				fmt.Fprintln(os.Stderr)
+			case daemon.EventRequestExecuted:
+				if GetOutput() == "json" {
+					return enc.Encode(ev)
+				}
+				if ev.ExitCode != nil {
+					fmt.Fprintf(os.Stderr, "\n[slb] Command exited with code %d\n", *ev.ExitCode)
+				}
+				return nil
+			}
+		}
+	}
+}
+
+// tailLog follows the request's execution log until the request has
+// finished executing.
+func tailLog(ctx context.Context, dbConn *db.DB, requestID string, out io.Writer) error {
+	var offset int64
+	ticker := time.NewTicker(flagTailPollInterval)
+	defer ticker.Stop()
+	for {
+		request, err := dbConn.GetRequest(requestID)
+		if err != nil {
+			return fmt.Errorf("getting request: %w", err)
+		}
+		if request.Execution != nil && request.Execution.LogPath != "" {
+			n, err := copyLogFrom(out, request.Execution.LogPath, offset)
+			if err != nil {
+				return err
+			}
+			offset += n
+		}
+		if request.Status.IsTerminal() {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// copyLogFrom copies the log from offset to out and returns the number of
+// bytes copied.
+func copyLogFrom(out io.Writer, path string, offset int64) (int64, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return 0, nil
+		}
+		return 0, fmt.Errorf("opening execution log: %w", err)
+	}
+	defer f.Close()
+	if _, err := f.Seek(offset, io.SeekStart); err != nil {
+		return 0, fmt.Errorf("reading execution log: %w", err)
+	}
+	return io.Copy(out, f)
+}
+
+// printExecutionLog prints the log of a request that has finished.
+func printExecutionLog(out io.Writer, request *db.Request) error {
+	if request.Execution == nil || request.Execution.LogPath == "" {
+		return fmt.Errorf("request %s is %s and has no execution log", request.ID, request.Status)
+	}
+	if _, err := copyLogFrom(out, request.Execution.LogPath, 0); err != nil {
+		return err
+	}
+	return nil
+}
+
+// outputStream returns a writer publishing a request's execution output
+// for 'slb tail' and the TUI, and a func that flushes and closes it. The
+// writer is nil when no daemon is running.
+func outputStream(requestID string) (io.Writer, func()) {
+	p := daemon.NewOutputPublisher(daemon.DefaultSocketPath(), requestID)
+	if p == nil {
+		return nil, func() {}
+	}
+	return p, func() { _ = p.Close() }
+}
@@ -0,0 +1,98 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Dicklesworthstone/slb/internal/db"
+	"github.com/Dicklesworthstone/slb/internal/testutil"
+	"github.com/spf13/cobra"
+)
+
+func newTestTailCmd(dbPath string) *cobra.Command {
+	root := &cobra.Command{
+		Use:           "slb",
+		SilenceUsage:  true,
+		SilenceErrors: true,
+	}
+
+	root.PersistentFlags().StringVar(&flagDB, "db", dbPath, "database path")
+	root.PersistentFlags().StringVarP(&flagOutput, "output", "o", "text", "output format")
+	root.PersistentFlags().BoolVarP(&flagJSON, "json", "j", false, "json output")
+
+	root.AddCommand(tailCmd)
+
+	return root
+}
+
+func TestTailCommand_FinishedRequestPrintsLog(t *testing.T) {
+	h := testutil.NewHarness(t)
+	flagDB, flagOutput, flagJSON = "", "text", false
+
+	sess := testutil.MakeSession(t, h.DB, testutil.WithProject(h.ProjectDir))
+	req := testutil.MakeRequest(t, h.DB, sess, testutil.WithStatus(db.StatusExecuted))
+	logPath := filepath.Join(t.TempDir(), "exec.log")
+	if err := os.WriteFile(logPath, []byte("=== SLB Command Execution ===\nbuild ok\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := h.DB.UpdateRequestExecution(req.ID, &db.Execution{LogPath: logPath}); err != nil {
+		t.Fatalf("UpdateRequestExecution: %v", err)
+	}
+
+	stdout, err := executeCommandCapture(t, newTestTailCmd(h.DBPath), "tail", req.ID)
+	if err != nil {
+		t.Fatalf("tail: %v", err)
+	}
+	if !strings.Contains(stdout, "build ok") {
+		t.Errorf("expected the execution log, got %q", stdout)
+	}
+}
+
+func TestTailLog_FollowsUntilFinished(t *testing.T) {
+	h := testutil.NewHarness(t)
+	orig := flagTailPollInterval
+	flagTailPollInterval = 10 * time.Millisecond
+	t.Cleanup(func() { flagTailPollInterval = orig })
+
+	sess := testutil.MakeSession(t, h.DB, testutil.WithProject(h.ProjectDir))
+	req := testutil.MakeRequest(t, h.DB, sess, testutil.WithStatus(db.StatusExecuting))
+	logPath := filepath.Join(t.TempDir(), "exec.log")
+	if err := os.WriteFile(logPath, []byte("first\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := h.DB.UpdateRequestExecution(req.ID, &db.Execution{LogPath: logPath}); err != nil {
+		t.Fatalf("UpdateRequestExecution: %v", err)
+	}
+
+	var out bytes.Buffer
+	done := make(chan error, 1)
+	go func() { done <- tailLog(context.Background(), h.DB, req.ID, &out) }()
+
+	time.Sleep(50 * time.Millisecond)
+	f, err := os.OpenFile(logPath, os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _ = f.WriteString("second\n")
+	_ = f.Close()
+	if err := h.DB.UpdateRequestStatus(req.ID, db.StatusExecuted); err != nil {
+		t.Fatalf("UpdateRequestStatus: %v", err)
+	}
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("tailLog: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("tailLog did not stop after the request finished")
+	}
+	if out.String() != "first\nsecond\n" {
+		t.Errorf("tailLog output = %q", out.String())
+	}
+}
@@ -3,7 +3,9 @@ package cli
 import (
 	"fmt"
 	"os"
+	"path/filepath"
 
+	"github.com/Dicklesworthstone/slb/internal/config"
 	"github.com/Dicklesworthstone/slb/internal/tui"
 	"github.com/spf13/cobra"
 )
@@ -14,6 +16,10 @@ var (
 	flagTuiTheme          string
 	flagTuiSessionID      string
 	flagTuiSessionKey     string
+	flagTuiBell           bool
+	flagTuiProjects       []string
+	flagTuiReviewPool     bool
+	flagTuiDiscover       bool
 )
 
 func init() {
@@ -22,6 +28,10 @@ func init() {
 	tuiCmd.Flags().StringVar(&flagTuiTheme, "theme", "", "override theme (mocha, macchiato, frappe, latte)")
 	tuiCmd.Flags().StringVar(&flagTuiSessionID, "session-id", "", "session ID for approvals")
 	tuiCmd.Flags().StringVar(&flagTuiSessionKey, "session-key", "", "session key for approvals")
+	tuiCmd.Flags().BoolVar(&flagTuiBell, "bell", false, "ring the terminal bell when a new CRITICAL request arrives")
+	tuiCmd.Flags().StringSliceVar(&flagTuiProjects, "projects", nil, "additional projects to show (comma-separated or repeated)")
+	tuiCmd.Flags().BoolVar(&flagTuiReviewPool, "review-pool", false, "also show projects from the configured review pool")
+	tuiCmd.Flags().BoolVar(&flagTuiDiscover, "discover", false, "add projects seen in daemon events")
 
 	rootCmd.AddCommand(tuiCmd)
 }
@@ -31,13 +41,27 @@ var tuiCmd = &cobra.Command{
 	Short: "Launch the interactive TUI dashboard",
 	Long: `Launch the SLB Bubble Tea dashboard.
 
-If the daemon is running, live updates are streamed; otherwise polling is used.
+If the daemon is running, the dashboard, request detail and history views are
+updated from its event stream; the header shows the connection state. When the
+daemon is unreachable the views poll every --refresh-interval seconds and
+reconnect automatically, resyncing from the database once the stream is back.
+New CRITICAL requests flash in the pending list (and ring the bell with --bell).
 Providing --session-id and --session-key enables interactive approval/rejection.
 
+Several projects can be supervised from one TUI: add them with --projects,
+--review-pool (general.review_pool) or --discover (projects seen in daemon
+events). The pending queue gains a project column, agents are grouped by
+project, and p cycles the project filter. Reviews are recorded in the
+request's own project, signed with the session given by --session-id when it
+belongs to that project, otherwise with the same agent's active session there.
+
 Key bindings:
   tab/shift+tab  Switch between panels
   up/down (j/k)  Navigate within panels
   enter          View selected request details
+  space / *      Select request / all visible requests
+  a / x          Approve / reject selected requests (confirmation screen)
+  p              Cycle project filter (multi-project)
   m              Pattern management
   H              History browser
   q              Quit
@@ -50,6 +74,19 @@ Theme options: mocha (default), macchiato, frappe, latte`,
 			return fmt.Errorf("getting working directory: %w", err)
 		}
 
+		projects, err := tuiProjects(projectPath)
+		if err != nil {
+			return err
+		}
+		cfg, err := config.Load(config.LoadOptions{
+			ProjectDir: projectPath,
+			ConfigPath: flagConfig,
+		})
+		if err != nil {
+			return fmt.Errorf("loading config: %w", err)
+		}
+
+		reputation := toReputationConfig(cfg)
 		opts := tui.Options{
 			ProjectPath:     projectPath,
 			Theme:           flagTuiTheme,
@@ -57,6 +94,11 @@ Theme options: mocha (default), macchiato, frappe, latte`,
 			RefreshInterval: flagTuiRefreshSeconds,
 			SessionID:       flagTuiSessionID,
 			SessionKey:      flagTuiSessionKey,
+			Bell:            flagTuiBell,
+			Projects:        projects,
+			Discover:        flagTuiDiscover,
+			SavedQueries:    cfg.History.SavedQueries,
+			Reputation:      &reputation,
 		}
 
 		if err := tui.RunWithOptions(opts); err != nil {
@@ -65,3 +107,27 @@ Theme options: mocha (default), macchiato, frappe, latte`,
 		return nil
 	},
 }
+
+// tuiProjects collects the additional projects requested with --projects and
+// --review-pool.
+func tuiProjects(projectPath string) ([]string, error) {
+	var projects []string
+	for _, p := range flagTuiProjects {
+		abs, err := filepath.Abs(p)
+		if err != nil {
+			return nil, fmt.Errorf("resolving project %q: %w", p, err)
+		}
+		projects = append(projects, abs)
+	}
+	if flagTuiReviewPool {
+		cfg, err := config.Load(config.LoadOptions{
+			ProjectDir: projectPath,
+			ConfigPath: flagConfig,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("loading config: %w", err)
+		}
+		projects = append(projects, cfg.General.ReviewPool...)
+	}
+	return dedupeStrings(projects), nil
+}
@@ -0,0 +1,43 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestTuiProjects(t *testing.T) {
+	project := t.TempDir()
+	pool := t.TempDir()
+	cfgPath := filepath.Join(t.TempDir(), "config.toml")
+	if err := os.WriteFile(cfgPath, []byte("[general]\nreview_pool = [\""+pool+"\"]\n"), 0644); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+
+	defer func() {
+		flagTuiProjects = nil
+		flagTuiReviewPool = false
+		flagConfig = ""
+	}()
+
+	flagTuiProjects = []string{"rel/api", pool}
+	got, err := tuiProjects(project)
+	if err != nil {
+		t.Fatalf("tuiProjects: %v", err)
+	}
+	abs, _ := filepath.Abs("rel/api")
+	if len(got) != 2 || got[0] != abs || got[1] != pool {
+		t.Fatalf("unexpected projects: %v", got)
+	}
+
+	flagTuiProjects = nil
+	flagTuiReviewPool = true
+	flagConfig = cfgPath
+	got, err = tuiProjects(project)
+	if err != nil {
+		t.Fatalf("tuiProjects: %v", err)
+	}
+	if len(got) != 1 || got[0] != pool {
+		t.Fatalf("expected review pool project, got %v", got)
+	}
+}
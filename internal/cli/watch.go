@@ -97,6 +97,11 @@ func runWatchDaemon(ctx context.Context, client *daemon.Client, out io.Writer) e
 				return nil
 			}
 
+			// Command output is for 'slb tail', not the lifecycle stream.
+			if event.Type == daemon.EventExecutionOutput {
+				continue
+			}
+
 			watchEvent := daemon.ToRequestStreamEvent(event)
 			if err := enc.Encode(watchEvent); err != nil {
 				return fmt.Errorf("encoding event: %w", err)
@@ -0,0 +1,163 @@
+// Package cli implements the webhooks command for inspecting webhook endpoints and deliveries.
+package cli
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/Dicklesworthstone/slb/internal/config"
+	"github.com/Dicklesworthstone/slb/internal/db"
+	"github.com/Dicklesworthstone/slb/internal/output"
+	"github.com/spf13/cobra"
+)
+
+var (
+	webhooksStatus   string
+	webhooksEndpoint string
+	webhooksRequest  string
+	webhooksLimit    int
+)
+
+func init() {
+	rootCmd.AddCommand(webhooksCmd)
+	webhooksCmd.AddCommand(webhooksListCmd)
+	webhooksCmd.AddCommand(webhooksDeliveriesCmd)
+	webhooksCmd.AddCommand(webhooksRetryCmd)
+
+	webhooksDeliveriesCmd.Flags().StringVar(&webhooksStatus, "status", "", "filter by status (pending, retrying, delivered, dead)")
+	webhooksDeliveriesCmd.Flags().StringVar(&webhooksEndpoint, "endpoint", "", "filter by endpoint name")
+	webhooksDeliveriesCmd.Flags().StringVar(&webhooksRequest, "request", "", "filter by request ID")
+	webhooksDeliveriesCmd.Flags().IntVar(&webhooksLimit, "limit", 50, "maximum number of deliveries to list")
+}
+
+var webhooksCmd = &cobra.Command{
+	Use:   "webhooks",
+	Short: "Inspect webhook endpoints and deliveries",
+	Long: `Inspect the named webhook endpoints configured under [[notifications.webhooks]]
+and the delivery log kept by the daemon.
+
+Failed deliveries are retried with exponential backoff. Once their attempts are
+exhausted they move to the dead-letter queue (status "dead"), where they can be
+requeued with "slb webhooks retry".
+
+Examples:
+  slb webhooks list                          # Configured endpoints
+  slb webhooks deliveries --status dead      # Dead-letter queue
+  slb webhooks deliveries --request <id>     # Deliveries for one request
+  slb webhooks retry 42                      # Requeue delivery 42`,
+}
+
+var webhooksListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List configured webhook endpoints",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		project, err := projectPath()
+		if err != nil {
+			return err
+		}
+		cfg, err := config.Load(config.LoadOptions{
+			ProjectDir: project,
+			ConfigPath: flagConfig,
+		})
+		if err != nil {
+			return fmt.Errorf("loading config: %w", err)
+		}
+
+		endpoints := make([]map[string]any, 0, len(cfg.Notifications.Webhooks))
+		for _, wc := range cfg.Notifications.Webhooks {
+			format := wc.Format
+			if format == "" {
+				format = "json"
+			}
+			endpoints = append(endpoints, map[string]any{
+				"name":   wc.Name,
+				"url":    wc.URL,
+				"format": format,
+				"events": wc.Events,
+				"tiers":  wc.Tiers,
+				"signed": wc.Secret != "" || wc.SecretEnv != "",
+			})
+		}
+
+		out := output.New(output.Format(GetOutput()))
+		return out.Write(map[string]any{
+			"endpoints":    endpoints,
+			"count":        len(endpoints),
+			"max_attempts": cfg.Notifications.WebhookMaxAttempts,
+		})
+	},
+}
+
+var webhooksDeliveriesCmd = &cobra.Command{
+	Use:   "deliveries",
+	Short: "List webhook deliveries, most recent first",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		status := db.WebhookDeliveryStatus(webhooksStatus)
+		switch status {
+		case "", db.WebhookPending, db.WebhookRetrying, db.WebhookDelivered, db.WebhookDead:
+		default:
+			return fmt.Errorf("invalid status %q (valid: pending, retrying, delivered, dead)", webhooksStatus)
+		}
+
+		dbConn, err := db.Open(GetDB())
+		if err != nil {
+			return fmt.Errorf("opening database: %w", err)
+		}
+		defer dbConn.Close()
+
+		deliveries, err := dbConn.ListWebhookDeliveries(db.WebhookDeliveryFilter{
+			Endpoint:  webhooksEndpoint,
+			Status:    status,
+			RequestID: webhooksRequest,
+			Limit:     webhooksLimit,
+		})
+		if err != nil {
+			return err
+		}
+		if deliveries == nil {
+			deliveries = []*db.WebhookDelivery{}
+		}
+
+		out := output.New(output.Format(GetOutput()))
+		return out.Write(map[string]any{
+			"deliveries": deliveries,
+			"count":      len(deliveries),
+		})
+	},
+}
+
+var webhooksRetryCmd = &cobra.Command{
+	Use:   "retry <delivery-id>",
+	Short: "Requeue a failed or dead-lettered delivery",
+	Long: `Requeue a webhook delivery with a fresh attempt budget. The running daemon
+picks it up on its next notification check.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		id, err := strconv.ParseInt(args[0], 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid delivery id %q", args[0])
+		}
+
+		dbConn, err := db.Open(GetDB())
+		if err != nil {
+			return fmt.Errorf("opening database: %w", err)
+		}
+		defer dbConn.Close()
+
+		if err := dbConn.RequeueWebhookDelivery(id); err != nil {
+			return err
+		}
+		delivery, err := dbConn.GetWebhookDelivery(id)
+		if err != nil {
+			return err
+		}
+
+		out := output.New(output.Format(GetOutput()))
+		return out.Write(map[string]any{
+			"id":       delivery.ID,
+			"endpoint": delivery.Endpoint,
+			"event":    delivery.Event,
+			"status":   delivery.Status,
+		})
+	},
+}
@@ -0,0 +1,97 @@
+package cli
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/Dicklesworthstone/slb/internal/db"
+	"github.com/Dicklesworthstone/slb/internal/testutil"
+	"github.com/spf13/cobra"
+)
+
+func newTestWebhooksCmd(dbPath string) *cobra.Command {
+	root := &cobra.Command{
+		Use:           "slb",
+		SilenceUsage:  true,
+		SilenceErrors: true,
+	}
+	root.PersistentFlags().StringVar(&flagDB, "db", dbPath, "database path")
+	root.PersistentFlags().StringVarP(&flagOutput, "output", "o", "text", "output format")
+	root.PersistentFlags().BoolVarP(&flagJSON, "json", "j", false, "json output")
+
+	whCmd := &cobra.Command{Use: "webhooks"}
+	deliveriesCmd := &cobra.Command{Use: "deliveries", RunE: webhooksDeliveriesCmd.RunE}
+	deliveriesCmd.Flags().StringVar(&webhooksStatus, "status", "", "status")
+	deliveriesCmd.Flags().StringVar(&webhooksEndpoint, "endpoint", "", "endpoint")
+	deliveriesCmd.Flags().StringVar(&webhooksRequest, "request", "", "request")
+	deliveriesCmd.Flags().IntVar(&webhooksLimit, "limit", 50, "limit")
+	retryCmd := &cobra.Command{Use: "retry <delivery-id>", Args: cobra.ExactArgs(1), RunE: webhooksRetryCmd.RunE}
+
+	whCmd.AddCommand(deliveriesCmd, retryCmd)
+	root.AddCommand(whCmd)
+	return root
+}
+
+func resetWebhooksFlags() {
+	flagDB = ""
+	flagOutput = "text"
+	flagJSON = false
+	webhooksStatus = ""
+	webhooksEndpoint = ""
+	webhooksRequest = ""
+	webhooksLimit = 50
+}
+
+func TestWebhooksDeliveriesAndRetry(t *testing.T) {
+	h := testutil.NewHarness(t)
+	resetWebhooksFlags()
+
+	dead := &db.WebhookDelivery{Endpoint: "ops", URL: "https://hooks.example.com", Event: "request_pending", Body: "{}", Status: db.WebhookDead, Attempts: 6}
+	ok := &db.WebhookDelivery{Endpoint: "chat", URL: "https://chat.example.com", Event: "request_pending", Body: "{}", Status: db.WebhookDelivered, Attempts: 1}
+	for _, d := range []*db.WebhookDelivery{dead, ok} {
+		if err := h.DB.CreateWebhookDelivery(d); err != nil {
+			t.Fatalf("create delivery: %v", err)
+		}
+	}
+
+	cmd := newTestWebhooksCmd(h.DBPath)
+	stdout, err := executeCommandCapture(t, cmd, "webhooks", "deliveries", "--status", "dead", "-j")
+	if err != nil {
+		t.Fatalf("deliveries: %v", err)
+	}
+	var list struct {
+		Deliveries []db.WebhookDelivery `json:"deliveries"`
+		Count      int                  `json:"count"`
+	}
+	if err := json.Unmarshal([]byte(stdout), &list); err != nil {
+		t.Fatalf("parse JSON: %v\nstdout: %s", err, stdout)
+	}
+	if list.Count != 1 || list.Deliveries[0].ID != dead.ID {
+		t.Fatalf("unexpected deliveries: %s", stdout)
+	}
+
+	resetWebhooksFlags()
+	cmd = newTestWebhooksCmd(h.DBPath)
+	if _, err := executeCommandCapture(t, cmd, "webhooks", "retry", strconv.FormatInt(dead.ID, 10), "-j"); err != nil {
+		t.Fatalf("retry: %v", err)
+	}
+	got, err := h.DB.GetWebhookDelivery(dead.ID)
+	if err != nil || got.Status != db.WebhookPending || got.Attempts != 0 {
+		t.Fatalf("expected requeued delivery, got %+v (%v)", got, err)
+	}
+
+	resetWebhooksFlags()
+	cmd = newTestWebhooksCmd(h.DBPath)
+	_, err = executeCommandCapture(t, cmd, "webhooks", "retry", strconv.FormatInt(ok.ID, 10), "-j")
+	if err == nil || !strings.Contains(err.Error(), "already delivered") {
+		t.Fatalf("expected already delivered error, got %v", err)
+	}
+
+	resetWebhooksFlags()
+	cmd = newTestWebhooksCmd(h.DBPath)
+	if _, err := executeCommandCapture(t, cmd, "webhooks", "deliveries", "--status", "lost"); err == nil {
+		t.Fatalf("expected invalid status error")
+	}
+}
@@ -0,0 +1,125 @@
+// Package cli implements execution window flags shared by request and run.
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/Dicklesworthstone/slb/internal/config"
+	"github.com/Dicklesworthstone/slb/internal/core"
+	"github.com/Dicklesworthstone/slb/internal/db"
+	"github.com/Dicklesworthstone/slb/internal/schedule"
+	"github.com/spf13/cobra"
+)
+
+// windowFlags are the flags limiting when an approved request may execute.
+type windowFlags struct {
+	notBefore string
+	notAfter  string
+	recurring string
+}
+
+func addWindowFlags(cmd *cobra.Command, f *windowFlags) {
+	cmd.Flags().StringVar(&f.notBefore, "not-before", "", "earliest execution time (RFC3339, or a duration from now such as 2h)")
+	cmd.Flags().StringVar(&f.notAfter, "not-after", "", "latest execution time (RFC3339, or a duration from now such as 8h)")
+	cmd.Flags().StringVar(&f.recurring, "window", "", "only execute while this change window (general.change_windows) is open")
+}
+
+// window returns the requested execution window, or nil when none of the
+// window flags were given.
+func (f *windowFlags) window(now time.Time) (*db.ExecutionWindow, error) {
+	if f.notBefore == "" && f.notAfter == "" && f.recurring == "" {
+		return nil, nil
+	}
+	w := &db.ExecutionWindow{Recurring: f.recurring}
+	var err error
+	if w.NotBefore, err = parseWindowTime("--not-before", f.notBefore, now); err != nil {
+		return nil, err
+	}
+	if w.NotAfter, err = parseWindowTime("--not-after", f.notAfter, now); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+// parseWindowTime parses an RFC3339 time or a duration from now.
+func parseWindowTime(flag, value string, now time.Time) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		t = t.UTC()
+		return &t, nil
+	}
+	d, err := time.ParseDuration(strings.TrimPrefix(value, "+"))
+	if err != nil || d < 0 {
+		return nil, fmt.Errorf("%s: %q is neither an RFC3339 time nor a duration", flag, value)
+	}
+	t := now.Add(d).UTC().Truncate(time.Second)
+	return &t, nil
+}
+
+// changeSchedule returns the configured change windows and freeze periods.
+// config.Load has validated them.
+func changeSchedule(cfg config.Config) *core.ChangeSchedule {
+	s := &core.ChangeSchedule{}
+	for _, w := range cfg.General.ChangeWindows {
+		if window, err := schedule.NewWindow(w.Schedule, w.Duration, w.Timezone); err == nil {
+			s.Windows = append(s.Windows, core.ChangeWindow{Name: w.Name, Window: window})
+		}
+	}
+	for _, f := range cfg.General.FreezePeriods {
+		window, err := schedule.NewWindow(f.Schedule, f.Duration, f.Timezone)
+		if err != nil {
+			continue
+		}
+		freeze := core.FreezePeriod{Name: f.Name, Reason: f.Reason, Window: window}
+		for _, tier := range f.Tiers {
+			freeze.Tiers = append(freeze.Tiers, core.RiskTier(strings.ToLower(tier)))
+		}
+		s.Freezes = append(s.Freezes, freeze)
+	}
+	return s
+}
+
+// waitForWindow blocks until the request's execution window opens. It
+// returns at once when the request has no window or it is open.
+func waitForWindow(ctx context.Context, s *core.ChangeSchedule, request *db.Request) error {
+	opening, err := s.NextOpening(request.Window, time.Now())
+	if err != nil {
+		return err
+	}
+	wait := time.Until(opening)
+	if wait <= 0 {
+		return nil
+	}
+	if GetOutput() != "json" {
+		fmt.Fprintf(os.Stderr, "[slb] Waiting for the execution window to open at %s\n", opening.Format(time.RFC3339))
+	}
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// describeWindow summarizes an execution window on one line.
+func describeWindow(w *db.ExecutionWindow) string {
+	var parts []string
+	if w.NotBefore != nil {
+		parts = append(parts, "not before "+w.NotBefore.Format(time.RFC3339))
+	}
+	if w.NotAfter != nil {
+		parts = append(parts, "not after "+w.NotAfter.Format(time.RFC3339))
+	}
+	if w.Recurring != "" {
+		parts = append(parts, fmt.Sprintf("while %q is open", w.Recurring))
+	}
+	return strings.Join(parts, "; ")
+}
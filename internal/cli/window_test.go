@@ -0,0 +1,38 @@
+package cli
+
+import (
+	"testing"
+	"time"
+)
+
+func TestWindowFlags(t *testing.T) {
+	now := time.Date(2026, 10, 16, 10, 0, 0, 0, time.UTC)
+
+	if w, err := (&windowFlags{}).window(now); err != nil || w != nil {
+		t.Errorf("no flags: got %+v, %v", w, err)
+	}
+
+	f := windowFlags{notBefore: "+2h", notAfter: "2026-10-16T18:00:00+02:00", recurring: "nightly"}
+	w, err := f.window(now)
+	if err != nil {
+		t.Fatalf("window: %v", err)
+	}
+	if w.NotBefore == nil || !w.NotBefore.Equal(now.Add(2*time.Hour)) {
+		t.Errorf("NotBefore = %v", w.NotBefore)
+	}
+	if w.NotAfter == nil || !w.NotAfter.Equal(time.Date(2026, 10, 16, 16, 0, 0, 0, time.UTC)) {
+		t.Errorf("NotAfter = %v", w.NotAfter)
+	}
+	if w.Recurring != "nightly" {
+		t.Errorf("Recurring = %q", w.Recurring)
+	}
+	if got := describeWindow(w); got != `not before 2026-10-16T12:00:00Z; not after 2026-10-16T16:00:00Z; while "nightly" is open` {
+		t.Errorf("describeWindow = %q", got)
+	}
+
+	for _, bad := range []string{"tomorrow", "-1h"} {
+		if _, err := (&windowFlags{notBefore: bad}).window(now); err == nil {
+			t.Errorf("--not-before %q: expected an error", bad)
+		}
+	}
+}
@@ -1,5 +1,6 @@
 // Package config implements hierarchical configuration for SLB.
-// Precedence: defaults < user (~/.slb/config.toml) < project (.slb/config.toml) < env (SLB_*) < flags.
+// Precedence: defaults < system (/etc/slb/config.toml) < user (~/.slb/config.toml) <
+// project (.slb/config.toml) < env (SLB_*) < flags.
 package config
 
 // Note: Additional imports will be added as needed during implementation.
@@ -31,6 +32,70 @@ type GeneralConfig struct {
 	MaxRollbackSizeMB         int      `toml:"max_rollback_size_mb" mapstructure:"max_rollback_size_mb"`
 	CrossProjectReviews       bool     `toml:"cross_project_reviews" mapstructure:"cross_project_reviews"`
 	ReviewPool                []string `toml:"review_pool" mapstructure:"review_pool"`
+	BindEnvironment           bool     `toml:"bind_environment" mapstructure:"bind_environment"`         // capture an environment fingerprint and refuse execution when it changes
+	FingerprintEnvVars        []string `toml:"fingerprint_env_vars" mapstructure:"fingerprint_env_vars"` // extra environment variables included in the fingerprint
+
+	// Sandbox restrictions applied when a reviewer requires sandboxed
+	// execution of a request that did not ask for specific ones. Writes are
+	// always limited to the paths the command names plus sandbox_write_paths.
+	SandboxWritePaths     []string `toml:"sandbox_write_paths" mapstructure:"sandbox_write_paths"`
+	SandboxDisableNetwork bool     `toml:"sandbox_disable_network" mapstructure:"sandbox_disable_network"`
+	SandboxCPUSeconds     int      `toml:"sandbox_cpu_seconds" mapstructure:"sandbox_cpu_seconds"`         // 0 = unlimited
+	SandboxMemoryMB       int      `toml:"sandbox_memory_mb" mapstructure:"sandbox_memory_mb"`             // 0 = unlimited
+	SandboxTimeoutSeconds int      `toml:"sandbox_timeout_seconds" mapstructure:"sandbox_timeout_seconds"` // 0 = execution timeout
+
+	// PTYAutoAnswers answer known prompts of commands executed with --pty.
+	PTYAutoAnswers []AutoAnswerConfig `toml:"pty_auto_answers" mapstructure:"pty_auto_answers"`
+
+	// ScrubPatterns are regular expressions for secrets removed from
+	// execution logs, dry-run output and attachments, in addition to the
+	// built-in detectors.
+	ScrubPatterns []string `toml:"scrub_patterns" mapstructure:"scrub_patterns"`
+
+	// ChangeWindows are named recurring windows a request can be limited to
+	// with --window. FreezePeriods are recurring periods during which
+	// requests of their tiers are neither created nor executed.
+	ChangeWindows []ChangeWindowConfig `toml:"change_windows" mapstructure:"change_windows"`
+	FreezePeriods []FreezePeriodConfig `toml:"freeze_periods" mapstructure:"freeze_periods"`
+	// FreezeOperators are agent names that may end a change freeze started
+	// by another session. Only honoured when the system config locks it.
+	FreezeOperators []string `toml:"freeze_operators" mapstructure:"freeze_operators"`
+
+	// PolicyFile holds policy rules added to the default policy compiled
+	// from this configuration. Relative paths are resolved against the
+	// project; empty uses .slb/policy.toml when it exists.
+	PolicyFile string `toml:"policy_file" mapstructure:"policy_file"`
+	// ProjectPolicy loads .slb/policy.toml when PolicyFile is empty. Agents
+	// can write that file, so it is only honoured when the system config
+	// locks this key.
+	ProjectPolicy bool `toml:"project_policy" mapstructure:"project_policy"`
+}
+
+// AutoAnswerConfig types Answer, followed by Enter, whenever a command
+// running in a pseudo-terminal prints output matching Pattern.
+type AutoAnswerConfig struct {
+	Pattern string `toml:"pattern" mapstructure:"pattern"` // regular expression
+	Answer  string `toml:"answer" mapstructure:"answer"`
+}
+
+// ChangeWindowConfig defines a recurring window that opens whenever the
+// cron expression Schedule matches and stays open for Duration.
+type ChangeWindowConfig struct {
+	Name     string `toml:"name" mapstructure:"name"`
+	Schedule string `toml:"schedule" mapstructure:"schedule"` // cron: minute hour day-of-month month day-of-week
+	Duration string `toml:"duration" mapstructure:"duration"` // e.g. "2h"
+	Timezone string `toml:"timezone" mapstructure:"timezone"` // IANA name; empty = local time
+}
+
+// FreezePeriodConfig defines a recurring period, like a change window,
+// during which requests of Tiers are refused.
+type FreezePeriodConfig struct {
+	Name     string   `toml:"name" mapstructure:"name"`
+	Schedule string   `toml:"schedule" mapstructure:"schedule"`
+	Duration string   `toml:"duration" mapstructure:"duration"`
+	Timezone string   `toml:"timezone" mapstructure:"timezone"`
+	Tiers    []string `toml:"tiers" mapstructure:"tiers"` // empty = every tier that needs approval
+	Reason   string   `toml:"reason" mapstructure:"reason"`
 }
 
 // DaemonConfig holds daemon process settings.
@@ -42,6 +107,18 @@ type DaemonConfig struct {
 	TCPAllowedIPs  []string `toml:"tcp_allowed_ips" mapstructure:"tcp_allowed_ips"`
 	LogLevel       string   `toml:"log_level" mapstructure:"log_level"`
 	PIDFile        string   `toml:"pid_file" mapstructure:"pid_file"`
+
+	// TLS for the TCP listener. Setting cert+key enables TLS; setting a client CA
+	// verifies client certificates (mTLS), whose Common Name is the principal.
+	TCPTLSCert              string   `toml:"tcp_tls_cert" mapstructure:"tcp_tls_cert"`
+	TCPTLSKey               string   `toml:"tcp_tls_key" mapstructure:"tcp_tls_key"`
+	TCPTLSClientCA          string   `toml:"tcp_tls_client_ca" mapstructure:"tcp_tls_client_ca"`
+	TCPTLSRequireClientCert bool     `toml:"tcp_tls_require_client_cert" mapstructure:"tcp_tls_require_client_cert"`
+	TCPTLSPrincipals        []string `toml:"tcp_tls_principals" mapstructure:"tcp_tls_principals"` // extra cert CNs allowed besides active session agent names
+
+	// HTTPAddr enables the HTTP/JSON + SSE gateway. It reuses the TCP auth,
+	// IP allowlist and TLS settings above.
+	HTTPAddr string `toml:"http_addr" mapstructure:"http_addr"`
 }
 
 // RateLimitConfig holds rate-limiting settings.
@@ -57,6 +134,39 @@ type NotificationsConfig struct {
 	DesktopDelaySecs int    `toml:"desktop_delay_seconds" mapstructure:"desktop_delay_seconds"`
 	WebhookURL       string `toml:"webhook_url" mapstructure:"webhook_url"`
 	EmailEnabled     bool   `toml:"email_enabled" mapstructure:"email_enabled"`
+
+	// SMTP delivery. Recipients fall back to email_to when a tier has no list
+	// of its own; CAUTION requests are batched into a digest.
+	EmailSMTPHost          string   `toml:"email_smtp_host" mapstructure:"email_smtp_host"`
+	EmailSMTPPort          int      `toml:"email_smtp_port" mapstructure:"email_smtp_port"`
+	EmailSMTPUsername      string   `toml:"email_smtp_username" mapstructure:"email_smtp_username"`
+	EmailSMTPPassword      string   `toml:"email_smtp_password" mapstructure:"email_smtp_password"`
+	EmailStartTLS          bool     `toml:"email_starttls" mapstructure:"email_starttls"`
+	EmailFrom              string   `toml:"email_from" mapstructure:"email_from"`
+	EmailTo                []string `toml:"email_to" mapstructure:"email_to"`
+	EmailCriticalTo        []string `toml:"email_critical_to" mapstructure:"email_critical_to"`
+	EmailDangerousTo       []string `toml:"email_dangerous_to" mapstructure:"email_dangerous_to"`
+	EmailCautionTo         []string `toml:"email_caution_to" mapstructure:"email_caution_to"`
+	EmailCautionDigestMins int      `toml:"email_caution_digest_minutes" mapstructure:"email_caution_digest_minutes"` // 0 sends CAUTION emails individually
+
+	// Named webhook endpoints with event/tier filters, signing and retries.
+	// webhook_url above remains a single unsigned endpoint for pending alerts.
+	Webhooks             []WebhookEndpointConfig `toml:"webhooks" mapstructure:"webhooks"`
+	WebhookMaxAttempts   int                     `toml:"webhook_max_attempts" mapstructure:"webhook_max_attempts"`
+	WebhookRetryBaseSecs int                     `toml:"webhook_retry_base_seconds" mapstructure:"webhook_retry_base_seconds"`
+}
+
+// WebhookEndpointConfig configures one named webhook endpoint.
+type WebhookEndpointConfig struct {
+	Name   string   `toml:"name" mapstructure:"name"`
+	URL    string   `toml:"url" mapstructure:"url"`
+	Format string   `toml:"format" mapstructure:"format"` // json | slack | discord | teams | matrix
+	Events []string `toml:"events" mapstructure:"events"` // empty = all events
+	Tiers  []string `toml:"tiers" mapstructure:"tiers"`   // empty = all tiers
+	// Secret signs payloads (X-SLB-Signature). SecretEnv names an environment
+	// variable holding the secret instead, keeping it out of config files.
+	Secret    string `toml:"secret" mapstructure:"secret"`
+	SecretEnv string `toml:"secret_env" mapstructure:"secret_env"`
 }
 
 // HistoryConfig holds history/audit persistence settings.
@@ -65,6 +175,9 @@ type HistoryConfig struct {
 	GitRepoPath   string `toml:"git_repo_path" mapstructure:"git_repo_path"`
 	RetentionDays int    `toml:"retention_days" mapstructure:"retention_days"`
 	AutoGitCommit bool   `toml:"auto_git_commit" mapstructure:"auto_git_commit"`
+	// SavedQueries maps names to history queries, used as @name or with
+	// slb history --saved name.
+	SavedQueries map[string]string `toml:"saved_queries" mapstructure:"saved_queries"`
 }
 
 // PatternsConfig defines tiers and patterns.
@@ -96,4 +209,20 @@ type AgentsConfig struct {
 	TrustedSelfApprove          []string `toml:"trusted_self_approve" mapstructure:"trusted_self_approve"`
 	TrustedSelfApproveDelaySecs int      `toml:"trusted_self_approve_delay_seconds" mapstructure:"trusted_self_approve_delay_seconds"`
 	Blocked                     []string `toml:"blocked" mapstructure:"blocked"`
+
+	// Reputation scores agents and models from their request history, decayed
+	// with the given half-life. When enabled, agents or models scoring below
+	// reputation_low_score (with at least reputation_min_samples resolved
+	// requests) get extra approvals and/or different-model review.
+	ReputationEnabled                  bool    `toml:"reputation_enabled" mapstructure:"reputation_enabled"`
+	ReputationHalfLifeDays             int     `toml:"reputation_half_life_days" mapstructure:"reputation_half_life_days"`
+	ReputationMinSamples               int     `toml:"reputation_min_samples" mapstructure:"reputation_min_samples"`
+	ReputationLowScore                 float64 `toml:"reputation_low_score" mapstructure:"reputation_low_score"`
+	ReputationLowExtraApprovals        int     `toml:"reputation_low_extra_approvals" mapstructure:"reputation_low_extra_approvals"`
+	ReputationLowRequireDifferentModel bool    `toml:"reputation_low_require_different_model" mapstructure:"reputation_low_require_different_model"`
+
+	// SessionAttestation controls the process context recorded on sessions:
+	// "off", "record" (note resumes from a different context) or "enforce"
+	// (refuse them).
+	SessionAttestation string `toml:"session_attestation" mapstructure:"session_attestation"`
 }
@@ -45,6 +45,25 @@ func TestValidate_Errors(t *testing.T) {
 	}
 }
 
+func TestValidate_TCPTLS(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Daemon.TCPTLSCert = "/tmp/server.pem"
+	if err := Validate(cfg); err == nil || !strings.Contains(err.Error(), "tcp_tls_key") {
+		t.Fatalf("expected cert/key pairing error, got %v", err)
+	}
+
+	cfg.Daemon.TCPTLSKey = "/tmp/server-key.pem"
+	cfg.Daemon.TCPTLSRequireClientCert = true
+	if err := Validate(cfg); err == nil || !strings.Contains(err.Error(), "tcp_tls_client_ca") {
+		t.Fatalf("expected client CA error, got %v", err)
+	}
+
+	cfg.Daemon.TCPTLSClientCA = "/tmp/ca.pem"
+	if err := Validate(cfg); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
 func TestLoad_Precedence_DefaultsUserProjectEnvFlags(t *testing.T) {
 	home := t.TempDir()
 	t.Setenv("HOME", home)
@@ -246,6 +265,14 @@ func TestGetValue(t *testing.T) {
 		{"daemon.tcp_allowed_ips", cfg.Daemon.TCPAllowedIPs},
 		{"daemon.log_level", cfg.Daemon.LogLevel},
 		{"daemon.pid_file", cfg.Daemon.PIDFile},
+		{"daemon.tcp_tls_cert", cfg.Daemon.TCPTLSCert},
+		{"daemon.tcp_tls_key", cfg.Daemon.TCPTLSKey},
+		{"daemon.tcp_tls_client_ca", cfg.Daemon.TCPTLSClientCA},
+		{"daemon.tcp_tls_require_client_cert", cfg.Daemon.TCPTLSRequireClientCert},
+		{"daemon.tcp_tls_principals", cfg.Daemon.TCPTLSPrincipals},
+		{"daemon.http_addr", cfg.Daemon.HTTPAddr},
+		{"notifications.email_smtp_port", cfg.Notifications.EmailSMTPPort},
+		{"notifications.email_caution_to", cfg.Notifications.EmailCautionTo},
 
 		{"rate_limits.max_pending_per_session", cfg.RateLimits.MaxPendingPerSession},
 		{"rate_limits.max_requests_per_minute", cfg.RateLimits.MaxRequestsPerMinute},
@@ -378,3 +405,374 @@ func TestWriteValue_DecodeExistingInvalidTOMLErrors(t *testing.T) {
 		t.Fatalf("unexpected error: %v", err)
 	}
 }
+
+func TestValidate_Email(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Notifications.EmailEnabled = true
+	err := Validate(cfg)
+	if err == nil {
+		t.Fatalf("expected validation error for incomplete email config")
+	}
+	for _, key := range []string{"email_smtp_host", "email_from", "email_to"} {
+		if !strings.Contains(err.Error(), key) {
+			t.Errorf("expected %s in error, got %v", key, err)
+		}
+	}
+
+	cfg.Notifications.EmailSMTPHost = "smtp.example.com"
+	cfg.Notifications.EmailFrom = "slb@example.com"
+	cfg.Notifications.EmailCriticalTo = []string{"oncall@example.com"}
+	if err := Validate(cfg); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	cfg.Notifications.EmailCautionDigestMins = -1
+	if err := Validate(cfg); err == nil || !strings.Contains(err.Error(), "email_caution_digest_minutes") {
+		t.Fatalf("expected digest error, got %v", err)
+	}
+}
+
+func TestValidate_Webhooks(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Notifications.Webhooks = []WebhookEndpointConfig{
+		{Name: "ops", URL: "https://hooks.example.com/ops", Format: "slack"},
+		{Name: "ops", Format: "irc", Events: []string{"request_exploded"}, Tiers: []string{"scary"}},
+	}
+	err := Validate(cfg)
+	if err == nil {
+		t.Fatalf("expected validation error")
+	}
+	for _, want := range []string{"duplicated", "webhooks[1].url", "webhooks[1].format", "request_exploded", "scary"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("expected %q in error, got %v", want, err)
+		}
+	}
+
+	cfg.Notifications.Webhooks = cfg.Notifications.Webhooks[:1]
+	if err := Validate(cfg); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestLoad_WebhookEndpoints(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	project := t.TempDir()
+
+	path := filepath.Join(project, ".slb", "config.toml")
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	content := `
+[notifications]
+webhook_max_attempts = 3
+
+[[notifications.webhooks]]
+name = "chat"
+url = "https://hooks.slack.com/services/T/B/X"
+format = "slack"
+events = ["request_pending", "request_rejected"]
+tiers = ["critical"]
+secret_env = "SLB_CHAT_SECRET"
+`
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := Load(LoadOptions{ProjectDir: project})
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if cfg.Notifications.WebhookMaxAttempts != 3 {
+		t.Errorf("webhook_max_attempts=%d want 3", cfg.Notifications.WebhookMaxAttempts)
+	}
+	if len(cfg.Notifications.Webhooks) != 1 {
+		t.Fatalf("got %d webhooks, want 1", len(cfg.Notifications.Webhooks))
+	}
+	wh := cfg.Notifications.Webhooks[0]
+	if wh.Name != "chat" || wh.Format != "slack" || wh.SecretEnv != "SLB_CHAT_SECRET" ||
+		len(wh.Events) != 2 || len(wh.Tiers) != 1 {
+		t.Errorf("unexpected webhook: %+v", wh)
+	}
+}
+
+func TestLoad_PTYAutoAnswers(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	project := t.TempDir()
+
+	path := filepath.Join(project, ".slb", "config.toml")
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	content := `
+[[general.pty_auto_answers]]
+pattern = 'Enter a value:\s*$'
+answer = "yes"
+`
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := Load(LoadOptions{ProjectDir: project})
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if len(cfg.General.PTYAutoAnswers) != 1 {
+		t.Fatalf("got %d auto-answers, want 1", len(cfg.General.PTYAutoAnswers))
+	}
+	if aa := cfg.General.PTYAutoAnswers[0]; aa.Pattern != `Enter a value:\s*$` || aa.Answer != "yes" {
+		t.Errorf("unexpected auto-answer: %+v", aa)
+	}
+
+	cfg.General.PTYAutoAnswers = append(cfg.General.PTYAutoAnswers, AutoAnswerConfig{Pattern: "(", Answer: "y"}, AutoAnswerConfig{Answer: "y"})
+	err = Validate(cfg)
+	if err == nil {
+		t.Fatal("expected validation error")
+	}
+	for _, want := range []string{"pty_auto_answers[1].pattern is not a valid", "pty_auto_answers[2].pattern is required"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("expected %q in error, got %v", want, err)
+		}
+	}
+}
+
+func TestLoad_ChangeWindowsAndFreezePeriods(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	project := t.TempDir()
+
+	path := filepath.Join(project, ".slb", "config.toml")
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	content := `
+[[general.change_windows]]
+name = "nightly"
+schedule = "0 2 * * *"
+duration = "2h"
+timezone = "Europe/Berlin"
+
+[[general.freeze_periods]]
+name = "friday-afternoon"
+schedule = "0 15 * * fri"
+duration = "9h"
+tiers = ["critical"]
+reason = "No critical changes before the weekend"
+`
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := Load(LoadOptions{ProjectDir: project})
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if len(cfg.General.ChangeWindows) != 1 || cfg.General.ChangeWindows[0].Name != "nightly" || cfg.General.ChangeWindows[0].Timezone != "Europe/Berlin" {
+		t.Errorf("unexpected change windows: %+v", cfg.General.ChangeWindows)
+	}
+	if len(cfg.General.FreezePeriods) != 1 || cfg.General.FreezePeriods[0].Tiers[0] != "critical" || cfg.General.FreezePeriods[0].Duration != "9h" {
+		t.Errorf("unexpected freeze periods: %+v", cfg.General.FreezePeriods)
+	}
+
+	cfg.General.ChangeWindows = append(cfg.General.ChangeWindows,
+		ChangeWindowConfig{Name: "nightly", Schedule: "0 2 * * *", Duration: "1h"},
+		ChangeWindowConfig{Schedule: "0 25 * * *", Duration: "1h"})
+	cfg.General.FreezePeriods = append(cfg.General.FreezePeriods, FreezePeriodConfig{Schedule: "0 15 * * fri", Duration: "forever", Tiers: []string{"severe"}})
+	err = Validate(cfg)
+	if err == nil {
+		t.Fatal("expected validation error")
+	}
+	for _, want := range []string{`change_windows[1].name "nightly" is already used`, "change_windows[2].name is required", "change_windows[2]: cron expression", "freeze_periods[1]: duration", `freeze_periods[1].tiers: unknown tier "severe"`} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("expected %q in error, got %v", want, err)
+		}
+	}
+}
+
+func TestLoad_ScrubPatterns(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("SLB_SCRUB_PATTERNS", `corp-[0-9a-f]{8},session=\w+`)
+
+	cfg, err := Load(LoadOptions{ProjectDir: t.TempDir()})
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if got := cfg.General.ScrubPatterns; len(got) != 2 || got[0] != `corp-[0-9a-f]{8}` {
+		t.Fatalf("ScrubPatterns = %q", got)
+	}
+
+	cfg.General.ScrubPatterns = append(cfg.General.ScrubPatterns, "(")
+	if err := Validate(cfg); err == nil || !strings.Contains(err.Error(), "general.scrub_patterns[2] is not a valid") {
+		t.Errorf("expected scrub pattern validation error, got %v", err)
+	}
+}
+
+func TestLoad_SavedQueries(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	project := t.TempDir()
+
+	path := filepath.Join(project, ".slb", "config.toml")
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	content := `
+[history.saved_queries]
+failures = "exit:!=0 since:7d"
+k8s = 'cmd:"kubectl delete" tier:critical'
+`
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := Load(LoadOptions{ProjectDir: project})
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if got := cfg.History.SavedQueries["failures"]; got != "exit:!=0 since:7d" {
+		t.Errorf("saved_queries.failures = %q", got)
+	}
+	if got, ok := GetValue(cfg, "history.saved_queries"); !ok || len(got.(map[string]string)) != 2 {
+		t.Errorf("GetValue(history.saved_queries) = %v, %v", got, ok)
+	}
+
+	cfg.History.SavedQueries["empty"] = " "
+	if err := Validate(cfg); err == nil || !strings.Contains(err.Error(), "history.saved_queries.empty") {
+		t.Errorf("expected empty saved query error, got %v", err)
+	}
+}
+
+func TestLoad_SystemLocks(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	system := filepath.Join(t.TempDir(), "config.toml")
+	old := SystemConfigPath
+	SystemConfigPath = system
+	t.Cleanup(func() { SystemConfigPath = old })
+
+	content := `
+[general]
+min_approvals = 3
+policy_file = "/etc/slb/policy.toml"
+
+[patterns.critical]
+patterns = ["^danger"]
+
+[locks]
+locked = ["general.policy_file"]
+stricter = ["general.min_approvals", "patterns.critical.patterns"]
+`
+	if err := os.WriteFile(system, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	project := t.TempDir()
+	projectPath := filepath.Join(project, ".slb", "config.toml")
+
+	cfg, prov, err := LoadWithProvenance(LoadOptions{ProjectDir: project})
+	if err != nil {
+		t.Fatalf("LoadWithProvenance: %v", err)
+	}
+	if cfg.General.MinApprovals != 3 || prov.Source("general.min_approvals") != (Source{Layer: LayerSystem, Origin: system}) {
+		t.Errorf("min_approvals = %d from %+v", cfg.General.MinApprovals, prov.Source("general.min_approvals"))
+	}
+	if prov.Lock("general.policy_file") != LockLocked || prov.Lock("general.min_approvals") != LockStricter || prov.Lock("general.timeout_action") != "" {
+		t.Error("unexpected lock modes")
+	}
+
+	// Stricter project values are accepted.
+	if err := WriteValue(projectPath, "general.min_approvals", 4); err != nil {
+		t.Fatal(err)
+	}
+	if err := WriteValue(projectPath, "patterns.critical.patterns", []string{"^danger", "^more"}); err != nil {
+		t.Fatal(err)
+	}
+	cfg, prov, err = LoadWithProvenance(LoadOptions{ProjectDir: project})
+	if err != nil {
+		t.Fatalf("stricter project: %v", err)
+	}
+	if cfg.General.MinApprovals != 4 || prov.Source("general.min_approvals").Layer != LayerProject {
+		t.Errorf("min_approvals = %d from %+v", cfg.General.MinApprovals, prov.Source("general.min_approvals"))
+	}
+	if src := prov.Source("patterns.critical"); src.Layer != LayerProject {
+		t.Errorf("patterns.critical source = %+v", src)
+	}
+
+	// Weaker values from any later layer are ignored with a warning.
+	t.Setenv("SLB_MIN_APPROVALS", "1")
+	var warnings []string
+	warn := func(msg string) { warnings = append(warnings, msg) }
+	cfg, prov, err = LoadWithProvenance(LoadOptions{ProjectDir: project, Warn: warn})
+	if err != nil {
+		t.Fatalf("weaker env: %v", err)
+	}
+	if cfg.General.MinApprovals != 3 || prov.Source("general.min_approvals") != (Source{Layer: LayerSystem, Origin: system}) {
+		t.Errorf("weaker env: min_approvals = %d from %+v", cfg.General.MinApprovals, prov.Source("general.min_approvals"))
+	}
+	src, problem := prov.Violation("general.min_approvals")
+	if len(warnings) != 1 || !strings.Contains(warnings[0], "SLB_MIN_APPROVALS") || src.Layer != LayerEnv || problem != warnings[0] {
+		t.Errorf("weaker env: warnings = %q", warnings)
+	}
+	t.Setenv("SLB_MIN_APPROVALS", "")
+	for key, value := range map[string]any{
+		"patterns.critical.patterns": []string{"^more"},
+		"general.policy_file":        "none.toml",
+	} {
+		warnings = nil
+		cfg, prov, err := LoadWithProvenance(LoadOptions{ProjectDir: project, FlagOverrides: map[string]any{key: value}, Warn: warn})
+		if err != nil {
+			t.Fatalf("%s override: %v", key, err)
+		}
+		if _, problem := prov.Violation(key); len(warnings) != 1 || problem == "" {
+			t.Errorf("%s override: warnings = %q", key, warnings)
+		}
+		if got, _ := GetValue(cfg, key); reflect.DeepEqual(got, value) {
+			t.Errorf("%s override: weaker value %v kept", key, got)
+		}
+	}
+	if cfg, _ := Load(LoadOptions{ProjectDir: project, FlagOverrides: map[string]any{"general.policy_file": "none.toml"}, Warn: warn}); cfg.General.PolicyFile != "/etc/slb/policy.toml" {
+		t.Errorf("policy_file = %q, want the system value", cfg.General.PolicyFile)
+	}
+
+	// The fallback for a config that fails to load keeps the system layer.
+	if cfg, err := LoadSystem(); err != nil || cfg.General.MinApprovals != 3 {
+		t.Errorf("LoadSystem = %d, %v", cfg.General.MinApprovals, err)
+	}
+
+	// Only the system config may hold locks.
+	if err := WriteValue(projectPath, "locks.locked", []string{}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(LoadOptions{ProjectDir: project}); err == nil || !strings.Contains(err.Error(), "[locks]") {
+		t.Errorf("project locks: got %v", err)
+	}
+
+	for _, bad := range []string{
+		"[locks]\nlocked = [\"general.nope\"]",
+		"[locks]\nstricter = [\"general.timeout_action\"]",
+	} {
+		if err := os.WriteFile(system, []byte(bad), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := Load(LoadOptions{ProjectDir: t.TempDir()}); err == nil {
+			t.Errorf("%q: expected an error", bad)
+		}
+	}
+}
+
+func TestAtLeastAsStrict(t *testing.T) {
+	for _, tc := range []struct {
+		s         strictness
+		got, want any
+		ok        bool
+	}{
+		{higherIsStricter, 2, 3, false},
+		{lowerIsStricter, 10, 30, true},
+		{limitIsStricter, 0, 5, false},
+		{limitIsStricter, 9, 0, true},
+		{trueIsStricter, false, true, false},
+		{falseIsStricter, false, true, true},
+		{subsetIsStricter, []string{"a", "b"}, []string{"a"}, false},
+		{attestationIsStricter, "record", "enforce", false},
+		{attestationIsStricter, "enforce", "off", true},
+	} {
+		if got := atLeastAsStrict(tc.s, tc.got, tc.want); got != tc.ok {
+			t.Errorf("atLeastAsStrict(%d, %v, %v) = %v, want %v", tc.s, tc.got, tc.want, got, tc.ok)
+		}
+	}
+}
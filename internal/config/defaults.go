@@ -76,6 +76,17 @@ func DefaultConfig() Config {
 			MaxRollbackSizeMB:         100,
 			CrossProjectReviews:       false,
 			ReviewPool:                []string{},
+			BindEnvironment:           true,
+			FingerprintEnvVars:        []string{},
+			SandboxWritePaths:         []string{},
+			SandboxDisableNetwork:     true,
+			PTYAutoAnswers:            []AutoAnswerConfig{},
+			ScrubPatterns:             []string{},
+			ChangeWindows:             []ChangeWindowConfig{},
+			FreezePeriods:             []FreezePeriodConfig{},
+			FreezeOperators:           []string{},
+			PolicyFile:                "",
+			ProjectPolicy:             false,
 		},
 		Daemon: DaemonConfig{
 			UseFileWatcher: true,
@@ -85,6 +96,14 @@ func DefaultConfig() Config {
 			TCPAllowedIPs:  []string{},
 			LogLevel:       "info",
 			PIDFile:        "",
+
+			TCPTLSCert:              "",
+			TCPTLSKey:               "",
+			TCPTLSClientCA:          "",
+			TCPTLSRequireClientCert: false,
+			TCPTLSPrincipals:        []string{},
+
+			HTTPAddr: "",
 		},
 		RateLimits: RateLimitConfig{
 			MaxPendingPerSession: 5,
@@ -96,6 +115,22 @@ func DefaultConfig() Config {
 			DesktopDelaySecs: 60,
 			WebhookURL:       "",
 			EmailEnabled:     false,
+
+			EmailSMTPHost:          "",
+			EmailSMTPPort:          587,
+			EmailSMTPUsername:      "",
+			EmailSMTPPassword:      "",
+			EmailStartTLS:          true,
+			EmailFrom:              "",
+			EmailTo:                []string{},
+			EmailCriticalTo:        []string{},
+			EmailDangerousTo:       []string{},
+			EmailCautionTo:         []string{},
+			EmailCautionDigestMins: 60,
+
+			Webhooks:             []WebhookEndpointConfig{},
+			WebhookMaxAttempts:   6,
+			WebhookRetryBaseSecs: 5,
 		},
 		History: HistoryConfig{
 			DatabasePath:  "",
@@ -142,6 +177,15 @@ func DefaultConfig() Config {
 			TrustedSelfApprove:          []string{},
 			TrustedSelfApproveDelaySecs: 300,
 			Blocked:                     []string{},
+
+			ReputationEnabled:                  false,
+			ReputationHalfLifeDays:             30,
+			ReputationMinSamples:               5,
+			ReputationLowScore:                 60,
+			ReputationLowExtraApprovals:        1,
+			ReputationLowRequireDifferentModel: true,
+
+			SessionAttestation: "record",
 		},
 	}
 }
@@ -20,11 +20,24 @@ type LoadOptions struct {
 	ConfigPath string
 	// FlagOverrides are highest-priority overrides from CLI flags (dot-notated keys).
 	FlagOverrides map[string]any
+	// Warn receives a message for each locked key restored to its system
+	// value. Defaults to printing a warning on stderr.
+	Warn func(msg string)
 }
 
 // Load returns the effective configuration after applying precedence:
-// defaults < user (~/.slb/config.toml) < project (.slb/config.toml) < env (SLB_*) < flags.
+// defaults < system (/etc/slb/config.toml) < user (~/.slb/config.toml) <
+// project (.slb/config.toml) < env (SLB_*) < flags.
 func Load(opts LoadOptions) (Config, error) {
+	cfg, _, err := LoadWithProvenance(opts)
+	return cfg, err
+}
+
+// LoadWithProvenance is Load that also reports which layer set each key.
+// Keys locked by the system config are checked against the later layers; a
+// later layer weakening one is ignored with a warning and the system value
+// kept.
+func LoadWithProvenance(opts LoadOptions) (Config, *Provenance, error) {
 	v := viper.New()
 	setDefaults(v)
 
@@ -35,29 +48,73 @@ func Load(opts LoadOptions) (Config, error) {
 		}
 	}
 
-	// 1) User config
-	if err := mergeConfigFile(v, userConfigPath()); err != nil {
-		return Config{}, err
+	prov := &Provenance{}
+	addFile := func(layer, path string) error {
+		if err := mergeConfigFile(v, path); err != nil {
+			return err
+		}
+		keys, err := fileKeys(path)
+		if err != nil {
+			return err
+		}
+		if layer != LayerSystem {
+			if err := rejectLocksTable(keys); err != nil {
+				return err
+			}
+		}
+		prov.layers = append(prov.layers, layerKeys{layer: layer, origins: keys})
+		return nil
 	}
-	// 2) Project config
-	if err := mergeConfigFile(v, projectConfigPath(projectDir, opts.ConfigPath)); err != nil {
-		return Config{}, err
+
+	// 1) System config, and the keys it locks
+	if err := addFile(LayerSystem, SystemConfigPath); err != nil {
+		return Config{}, nil, err
 	}
-	// 3) Environment variables
+	locks, err := loadLocks(SystemConfigPath)
+	if err != nil {
+		return Config{}, nil, err
+	}
+	prov.locks = locks
+	var system Config
+	if err := v.Unmarshal(&system); err != nil {
+		return Config{}, nil, fmt.Errorf("unmarshal config: %w", err)
+	}
+
+	// 2) User config
+	if err := addFile(LayerUser, userConfigPath()); err != nil {
+		return Config{}, nil, err
+	}
+	// 3) Project config
+	if err := addFile(LayerProject, projectConfigPath(projectDir, opts.ConfigPath)); err != nil {
+		return Config{}, nil, err
+	}
+	// 4) Environment variables
 	if err := applyEnvOverrides(v); err != nil {
-		return Config{}, err
+		return Config{}, nil, err
 	}
-	// 4) CLI flags (highest)
+	prov.layers = append(prov.layers, layerKeys{layer: LayerEnv, origins: envKeys()})
+	// 5) CLI flags (highest)
 	applyFlagOverrides(v, opts.FlagOverrides)
+	prov.layers = append(prov.layers, layerKeys{layer: LayerFlag, origins: flagKeys(opts.FlagOverrides)})
 
 	var cfg Config
 	if err := v.Unmarshal(&cfg); err != nil {
-		return Config{}, fmt.Errorf("unmarshal config: %w", err)
+		return Config{}, nil, fmt.Errorf("unmarshal config: %w", err)
+	}
+	if err := enforceLocks(locks, system, &cfg, prov); err != nil {
+		return Config{}, nil, err
 	}
 	if err := Validate(cfg); err != nil {
-		return Config{}, err
+		return Config{}, nil, err
+	}
+	warn := opts.Warn
+	if warn == nil {
+		warn = func(msg string) { fmt.Fprintf(os.Stderr, "warning: %s\n", msg) }
 	}
-	return cfg, nil
+	for _, v := range prov.violations {
+		warn(v.message)
+	}
+	return cfg, prov, nil
 }
 
 // setDefaults seeds viper with built-in defaults.
@@ -77,6 +134,20 @@ func setDefaults(v *viper.Viper) {
 	v.SetDefault("general.max_rollback_size_mb", def.General.MaxRollbackSizeMB)
 	v.SetDefault("general.cross_project_reviews", def.General.CrossProjectReviews)
 	v.SetDefault("general.review_pool", def.General.ReviewPool)
+	v.SetDefault("general.bind_environment", def.General.BindEnvironment)
+	v.SetDefault("general.fingerprint_env_vars", def.General.FingerprintEnvVars)
+	v.SetDefault("general.sandbox_write_paths", def.General.SandboxWritePaths)
+	v.SetDefault("general.sandbox_disable_network", def.General.SandboxDisableNetwork)
+	v.SetDefault("general.sandbox_cpu_seconds", def.General.SandboxCPUSeconds)
+	v.SetDefault("general.sandbox_memory_mb", def.General.SandboxMemoryMB)
+	v.SetDefault("general.sandbox_timeout_seconds", def.General.SandboxTimeoutSeconds)
+	v.SetDefault("general.pty_auto_answers", def.General.PTYAutoAnswers)
+	v.SetDefault("general.scrub_patterns", def.General.ScrubPatterns)
+	v.SetDefault("general.change_windows", def.General.ChangeWindows)
+	v.SetDefault("general.freeze_periods", def.General.FreezePeriods)
+	v.SetDefault("general.freeze_operators", def.General.FreezeOperators)
+	v.SetDefault("general.policy_file", def.General.PolicyFile)
+	v.SetDefault("general.project_policy", def.General.ProjectPolicy)
 
 	v.SetDefault("daemon.use_file_watcher", def.Daemon.UseFileWatcher)
 	v.SetDefault("daemon.ipc_socket", def.Daemon.IPCSocket)
@@ -85,6 +156,12 @@ func setDefaults(v *viper.Viper) {
 	v.SetDefault("daemon.tcp_allowed_ips", def.Daemon.TCPAllowedIPs)
 	v.SetDefault("daemon.log_level", def.Daemon.LogLevel)
 	v.SetDefault("daemon.pid_file", def.Daemon.PIDFile)
+	v.SetDefault("daemon.tcp_tls_cert", def.Daemon.TCPTLSCert)
+	v.SetDefault("daemon.tcp_tls_key", def.Daemon.TCPTLSKey)
+	v.SetDefault("daemon.tcp_tls_client_ca", def.Daemon.TCPTLSClientCA)
+	v.SetDefault("daemon.tcp_tls_require_client_cert", def.Daemon.TCPTLSRequireClientCert)
+	v.SetDefault("daemon.tcp_tls_principals", def.Daemon.TCPTLSPrincipals)
+	v.SetDefault("daemon.http_addr", def.Daemon.HTTPAddr)
 
 	v.SetDefault("rate_limits.max_pending_per_session", def.RateLimits.MaxPendingPerSession)
 	v.SetDefault("rate_limits.max_requests_per_minute", def.RateLimits.MaxRequestsPerMinute)
@@ -94,6 +171,20 @@ func setDefaults(v *viper.Viper) {
 	v.SetDefault("notifications.desktop_delay_seconds", def.Notifications.DesktopDelaySecs)
 	v.SetDefault("notifications.webhook_url", def.Notifications.WebhookURL)
 	v.SetDefault("notifications.email_enabled", def.Notifications.EmailEnabled)
+	v.SetDefault("notifications.email_smtp_host", def.Notifications.EmailSMTPHost)
+	v.SetDefault("notifications.email_smtp_port", def.Notifications.EmailSMTPPort)
+	v.SetDefault("notifications.email_smtp_username", def.Notifications.EmailSMTPUsername)
+	v.SetDefault("notifications.email_smtp_password", def.Notifications.EmailSMTPPassword)
+	v.SetDefault("notifications.email_starttls", def.Notifications.EmailStartTLS)
+	v.SetDefault("notifications.email_from", def.Notifications.EmailFrom)
+	v.SetDefault("notifications.email_to", def.Notifications.EmailTo)
+	v.SetDefault("notifications.email_critical_to", def.Notifications.EmailCriticalTo)
+	v.SetDefault("notifications.email_dangerous_to", def.Notifications.EmailDangerousTo)
+	v.SetDefault("notifications.email_caution_to", def.Notifications.EmailCautionTo)
+	v.SetDefault("notifications.email_caution_digest_minutes", def.Notifications.EmailCautionDigestMins)
+	v.SetDefault("notifications.webhooks", def.Notifications.Webhooks)
+	v.SetDefault("notifications.webhook_max_attempts", def.Notifications.WebhookMaxAttempts)
+	v.SetDefault("notifications.webhook_retry_base_seconds", def.Notifications.WebhookRetryBaseSecs)
 
 	v.SetDefault("history.database_path", def.History.DatabasePath)
 	v.SetDefault("history.git_repo_path", def.History.GitRepoPath)
@@ -113,6 +204,13 @@ func setDefaults(v *viper.Viper) {
 	v.SetDefault("agents.trusted_self_approve", def.Agents.TrustedSelfApprove)
 	v.SetDefault("agents.trusted_self_approve_delay_seconds", def.Agents.TrustedSelfApproveDelaySecs)
 	v.SetDefault("agents.blocked", def.Agents.Blocked)
+	v.SetDefault("agents.reputation_enabled", def.Agents.ReputationEnabled)
+	v.SetDefault("agents.reputation_half_life_days", def.Agents.ReputationHalfLifeDays)
+	v.SetDefault("agents.reputation_min_samples", def.Agents.ReputationMinSamples)
+	v.SetDefault("agents.reputation_low_score", def.Agents.ReputationLowScore)
+	v.SetDefault("agents.reputation_low_extra_approvals", def.Agents.ReputationLowExtraApprovals)
+	v.SetDefault("agents.reputation_low_require_different_model", def.Agents.ReputationLowRequireDifferentModel)
+	v.SetDefault("agents.session_attestation", def.Agents.SessionAttestation)
 }
 
 func setTierDefaults(v *viper.Viper, prefix string, tier PatternTierConfig) {
@@ -258,6 +356,34 @@ func GetValue(cfg Config, key string) (any, bool) {
 				return c.CrossProjectReviews, true
 			case "review_pool":
 				return c.ReviewPool, true
+			case "bind_environment":
+				return c.BindEnvironment, true
+			case "fingerprint_env_vars":
+				return c.FingerprintEnvVars, true
+			case "sandbox_write_paths":
+				return c.SandboxWritePaths, true
+			case "sandbox_disable_network":
+				return c.SandboxDisableNetwork, true
+			case "sandbox_cpu_seconds":
+				return c.SandboxCPUSeconds, true
+			case "sandbox_memory_mb":
+				return c.SandboxMemoryMB, true
+			case "sandbox_timeout_seconds":
+				return c.SandboxTimeoutSeconds, true
+			case "pty_auto_answers":
+				return c.PTYAutoAnswers, true
+			case "scrub_patterns":
+				return c.ScrubPatterns, true
+			case "change_windows":
+				return c.ChangeWindows, true
+			case "freeze_periods":
+				return c.FreezePeriods, true
+			case "freeze_operators":
+				return c.FreezeOperators, true
+			case "policy_file":
+				return c.PolicyFile, true
+			case "project_policy":
+				return c.ProjectPolicy, true
 			default:
 				return nil, false
 			}
@@ -277,6 +403,18 @@ func GetValue(cfg Config, key string) (any, bool) {
 				return c.LogLevel, true
 			case "pid_file":
 				return c.PIDFile, true
+			case "tcp_tls_cert":
+				return c.TCPTLSCert, true
+			case "tcp_tls_key":
+				return c.TCPTLSKey, true
+			case "tcp_tls_client_ca":
+				return c.TCPTLSClientCA, true
+			case "tcp_tls_require_client_cert":
+				return c.TCPTLSRequireClientCert, true
+			case "tcp_tls_principals":
+				return c.TCPTLSPrincipals, true
+			case "http_addr":
+				return c.HTTPAddr, true
 			default:
 				return nil, false
 			}
@@ -301,6 +439,34 @@ func GetValue(cfg Config, key string) (any, bool) {
 				return c.WebhookURL, true
 			case "email_enabled":
 				return c.EmailEnabled, true
+			case "email_smtp_host":
+				return c.EmailSMTPHost, true
+			case "email_smtp_port":
+				return c.EmailSMTPPort, true
+			case "email_smtp_username":
+				return c.EmailSMTPUsername, true
+			case "email_smtp_password":
+				return c.EmailSMTPPassword, true
+			case "email_starttls":
+				return c.EmailStartTLS, true
+			case "email_from":
+				return c.EmailFrom, true
+			case "email_to":
+				return c.EmailTo, true
+			case "email_critical_to":
+				return c.EmailCriticalTo, true
+			case "email_dangerous_to":
+				return c.EmailDangerousTo, true
+			case "email_caution_to":
+				return c.EmailCautionTo, true
+			case "email_caution_digest_minutes":
+				return c.EmailCautionDigestMins, true
+			case "webhooks":
+				return c.Webhooks, true
+			case "webhook_max_attempts":
+				return c.WebhookMaxAttempts, true
+			case "webhook_retry_base_seconds":
+				return c.WebhookRetryBaseSecs, true
 			default:
 				return nil, false
 			}
@@ -314,6 +480,8 @@ func GetValue(cfg Config, key string) (any, bool) {
 				return c.RetentionDays, true
 			case "auto_git_commit":
 				return c.AutoGitCommit, true
+			case "saved_queries":
+				return c.SavedQueries, true
 			default:
 				return nil, false
 			}
@@ -364,6 +532,20 @@ func GetValue(cfg Config, key string) (any, bool) {
 				return c.TrustedSelfApproveDelaySecs, true
 			case "blocked":
 				return c.Blocked, true
+			case "reputation_enabled":
+				return c.ReputationEnabled, true
+			case "reputation_half_life_days":
+				return c.ReputationHalfLifeDays, true
+			case "reputation_min_samples":
+				return c.ReputationMinSamples, true
+			case "reputation_low_score":
+				return c.ReputationLowScore, true
+			case "reputation_low_extra_approvals":
+				return c.ReputationLowExtraApprovals, true
+			case "reputation_low_require_different_model":
+				return c.ReputationLowRequireDifferentModel, true
+			case "session_attestation":
+				return c.SessionAttestation, true
 			default:
 				return nil, false
 			}
@@ -464,23 +646,53 @@ var keyKinds = map[string]valueKind{
 	"general.max_rollback_size_mb":          kindInt,
 	"general.cross_project_reviews":         kindBool,
 	"general.review_pool":                   kindStringSlice,
-
-	"daemon.use_file_watcher": kindBool,
-	"daemon.ipc_socket":       kindString,
-	"daemon.tcp_addr":         kindString,
-	"daemon.tcp_require_auth": kindBool,
-	"daemon.tcp_allowed_ips":  kindStringSlice,
-	"daemon.log_level":        kindString,
-	"daemon.pid_file":         kindString,
+	"general.bind_environment":              kindBool,
+	"general.fingerprint_env_vars":          kindStringSlice,
+	"general.sandbox_write_paths":           kindStringSlice,
+	"general.sandbox_disable_network":       kindBool,
+	"general.sandbox_cpu_seconds":           kindInt,
+	"general.sandbox_memory_mb":             kindInt,
+	"general.sandbox_timeout_seconds":       kindInt,
+	"general.scrub_patterns":                kindStringSlice,
+	"general.freeze_operators":              kindStringSlice,
+	"general.policy_file":                   kindString,
+	"general.project_policy":                kindBool,
+
+	"daemon.use_file_watcher":            kindBool,
+	"daemon.ipc_socket":                  kindString,
+	"daemon.tcp_addr":                    kindString,
+	"daemon.tcp_require_auth":            kindBool,
+	"daemon.tcp_allowed_ips":             kindStringSlice,
+	"daemon.log_level":                   kindString,
+	"daemon.pid_file":                    kindString,
+	"daemon.tcp_tls_cert":                kindString,
+	"daemon.tcp_tls_key":                 kindString,
+	"daemon.tcp_tls_client_ca":           kindString,
+	"daemon.tcp_tls_require_client_cert": kindBool,
+	"daemon.tcp_tls_principals":          kindStringSlice,
+	"daemon.http_addr":                   kindString,
 
 	"rate_limits.max_pending_per_session": kindInt,
 	"rate_limits.max_requests_per_minute": kindInt,
 	"rate_limits.rate_limit_action":       kindString,
 
-	"notifications.desktop_enabled":       kindBool,
-	"notifications.desktop_delay_seconds": kindInt,
-	"notifications.webhook_url":           kindString,
-	"notifications.email_enabled":         kindBool,
+	"notifications.desktop_enabled":              kindBool,
+	"notifications.desktop_delay_seconds":        kindInt,
+	"notifications.webhook_url":                  kindString,
+	"notifications.email_enabled":                kindBool,
+	"notifications.email_smtp_host":              kindString,
+	"notifications.email_smtp_port":              kindInt,
+	"notifications.email_smtp_username":          kindString,
+	"notifications.email_smtp_password":          kindString,
+	"notifications.email_starttls":               kindBool,
+	"notifications.email_from":                   kindString,
+	"notifications.email_to":                     kindStringSlice,
+	"notifications.email_critical_to":            kindStringSlice,
+	"notifications.email_dangerous_to":           kindStringSlice,
+	"notifications.email_caution_to":             kindStringSlice,
+	"notifications.email_caution_digest_minutes": kindInt,
+	"notifications.webhook_max_attempts":         kindInt,
+	"notifications.webhook_retry_base_seconds":   kindInt,
 
 	"history.database_path":   kindString,
 	"history.git_repo_path":   kindString,
@@ -538,6 +750,17 @@ var envBindings = []struct {
 	{"SLB_MAX_ROLLBACK_SIZE_MB", "general.max_rollback_size_mb", kindInt},
 	{"SLB_CROSS_PROJECT_REVIEWS", "general.cross_project_reviews", kindBool},
 	{"SLB_REVIEW_POOL", "general.review_pool", kindStringSlice},
+	{"SLB_BIND_ENVIRONMENT", "general.bind_environment", kindBool},
+	{"SLB_FINGERPRINT_ENV_VARS", "general.fingerprint_env_vars", kindStringSlice},
+	{"SLB_SANDBOX_WRITE_PATHS", "general.sandbox_write_paths", kindStringSlice},
+	{"SLB_SANDBOX_DISABLE_NETWORK", "general.sandbox_disable_network", kindBool},
+	{"SLB_SANDBOX_CPU_SECONDS", "general.sandbox_cpu_seconds", kindInt},
+	{"SLB_SANDBOX_MEMORY_MB", "general.sandbox_memory_mb", kindInt},
+	{"SLB_SANDBOX_TIMEOUT_SECONDS", "general.sandbox_timeout_seconds", kindInt},
+	{"SLB_SCRUB_PATTERNS", "general.scrub_patterns", kindStringSlice},
+	{"SLB_FREEZE_OPERATORS", "general.freeze_operators", kindStringSlice},
+	{"SLB_POLICY_FILE", "general.policy_file", kindString},
+	{"SLB_PROJECT_POLICY", "general.project_policy", kindBool},
 
 	{"SLB_DAEMON_USE_FILE_WATCHER", "daemon.use_file_watcher", kindBool},
 	{"SLB_DAEMON_IPC_SOCKET", "daemon.ipc_socket", kindString},
@@ -546,6 +769,12 @@ var envBindings = []struct {
 	{"SLB_DAEMON_TCP_ALLOWED_IPS", "daemon.tcp_allowed_ips", kindStringSlice},
 	{"SLB_DAEMON_LOG_LEVEL", "daemon.log_level", kindString},
 	{"SLB_DAEMON_PID_FILE", "daemon.pid_file", kindString},
+	{"SLB_DAEMON_TCP_TLS_CERT", "daemon.tcp_tls_cert", kindString},
+	{"SLB_DAEMON_TCP_TLS_KEY", "daemon.tcp_tls_key", kindString},
+	{"SLB_DAEMON_TCP_TLS_CLIENT_CA", "daemon.tcp_tls_client_ca", kindString},
+	{"SLB_DAEMON_TCP_TLS_REQUIRE_CLIENT_CERT", "daemon.tcp_tls_require_client_cert", kindBool},
+	{"SLB_DAEMON_TCP_TLS_PRINCIPALS", "daemon.tcp_tls_principals", kindStringSlice},
+	{"SLB_DAEMON_HTTP_ADDR", "daemon.http_addr", kindString},
 
 	{"SLB_MAX_PENDING_PER_SESSION", "rate_limits.max_pending_per_session", kindInt},
 	{"SLB_MAX_REQUESTS_PER_MINUTE", "rate_limits.max_requests_per_minute", kindInt},
@@ -555,6 +784,19 @@ var envBindings = []struct {
 	{"SLB_DESKTOP_DELAY_SECONDS", "notifications.desktop_delay_seconds", kindInt},
 	{"SLB_WEBHOOK_URL", "notifications.webhook_url", kindString},
 	{"SLB_EMAIL_ENABLED", "notifications.email_enabled", kindBool},
+	{"SLB_EMAIL_SMTP_HOST", "notifications.email_smtp_host", kindString},
+	{"SLB_EMAIL_SMTP_PORT", "notifications.email_smtp_port", kindInt},
+	{"SLB_EMAIL_SMTP_USERNAME", "notifications.email_smtp_username", kindString},
+	{"SLB_EMAIL_SMTP_PASSWORD", "notifications.email_smtp_password", kindString},
+	{"SLB_EMAIL_STARTTLS", "notifications.email_starttls", kindBool},
+	{"SLB_EMAIL_FROM", "notifications.email_from", kindString},
+	{"SLB_EMAIL_TO", "notifications.email_to", kindStringSlice},
+	{"SLB_EMAIL_CRITICAL_TO", "notifications.email_critical_to", kindStringSlice},
+	{"SLB_EMAIL_DANGEROUS_TO", "notifications.email_dangerous_to", kindStringSlice},
+	{"SLB_EMAIL_CAUTION_TO", "notifications.email_caution_to", kindStringSlice},
+	{"SLB_EMAIL_CAUTION_DIGEST_MINUTES", "notifications.email_caution_digest_minutes", kindInt},
+	{"SLB_WEBHOOK_MAX_ATTEMPTS", "notifications.webhook_max_attempts", kindInt},
+	{"SLB_WEBHOOK_RETRY_BASE_SECONDS", "notifications.webhook_retry_base_seconds", kindInt},
 
 	{"SLB_HISTORY_DB_PATH", "history.database_path", kindString},
 	{"SLB_HISTORY_GIT_PATH", "history.git_repo_path", kindString},
@@ -0,0 +1,455 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"github.com/spf13/viper"
+)
+
+// SystemConfigPath is the organisation-wide config file, loaded before the
+// user config. Besides ordinary settings it may hold a [locks] table naming
+// keys the user and project configs, SLB_* variables and flags may not
+// change (locked) or may only make stricter (stricter):
+//
+//	[locks]
+//	locked = ["general.policy_file", "agents.trusted_self_approve"]
+//	stricter = ["general.min_approvals", "patterns.critical.patterns"]
+var SystemConfigPath = "/etc/slb/config.toml"
+
+// ErrLocked is returned by Load when a later layer weakens a locked key whose
+// system value cannot be restored, and by callers refusing to write such a
+// value.
+var ErrLocked = errors.New("config key locked by the system config")
+
+// Configuration layers, lowest precedence first.
+const (
+	LayerDefault = "default"
+	LayerSystem  = "system"
+	LayerUser    = "user"
+	LayerProject = "project"
+	LayerEnv     = "env"
+	LayerFlag    = "flag"
+)
+
+// LoadSystem returns the built-in defaults with only the system config
+// applied, for callers falling back from a config that failed to load.
+func LoadSystem() (Config, error) {
+	v := viper.New()
+	setDefaults(v)
+	if err := mergeConfigFile(v, SystemConfigPath); err != nil {
+		return Config{}, err
+	}
+	var cfg Config
+	if err := v.Unmarshal(&cfg); err != nil {
+		return Config{}, fmt.Errorf("unmarshal config: %w", err)
+	}
+	if err := Validate(cfg); err != nil {
+		return Config{}, err
+	}
+	return cfg, nil
+}
+
+// Lock modes reported by Provenance.Lock.
+const (
+	LockLocked   = "locked"
+	LockStricter = "stricter"
+)
+
+// Locks is the [locks] table of the system config.
+type Locks struct {
+	Locked   []string `toml:"locked"`
+	Stricter []string `toml:"stricter"`
+}
+
+// Source names where an effective value came from: its layer and the file,
+// environment variable or flag that set it ("" for defaults).
+type Source struct {
+	Layer  string `json:"layer"`
+	Origin string `json:"origin,omitempty"`
+}
+
+// Provenance records which layer set each key while loading.
+type Provenance struct {
+	layers     []layerKeys // lowest precedence first
+	locks      Locks
+	violations []lockViolation
+}
+
+// lockViolation is a locked key a later layer weakened, which Load reset to
+// its system value.
+type lockViolation struct {
+	key     string
+	source  Source // where the weaker value came from
+	message string
+}
+
+type layerKeys struct {
+	layer string
+	// origins maps each flattened key the layer set to its origin.
+	origins map[string]string
+}
+
+// Source returns where the effective value of key came from. For a table
+// such as patterns.critical it is the highest layer setting any of its keys.
+func (p *Provenance) Source(key string) Source {
+	key = strings.ToLower(key)
+	for i := len(p.layers) - 1; i >= 0; i-- {
+		for k, origin := range p.layers[i].origins {
+			if k == key || strings.HasPrefix(k, key+".") || strings.HasPrefix(key, k+".") {
+				return Source{Layer: p.layers[i].layer, Origin: origin}
+			}
+		}
+	}
+	return Source{Layer: LayerDefault}
+}
+
+// Violation returns the warning for a locked key (or a table containing it,
+// or a key inside it) that a later layer weakened and Load reset to its
+// system value, with where the weaker value came from. The warning is ""
+// when there is none.
+func (p *Provenance) Violation(key string) (Source, string) {
+	key = strings.ToLower(key)
+	for _, v := range p.violations {
+		if v.key == key || strings.HasPrefix(key, v.key+".") || strings.HasPrefix(v.key, key+".") {
+			return v.source, v.message
+		}
+	}
+	return Source{}, ""
+}
+
+// Warnings returns the warning for every locked key Load reset to its system
+// value.
+func (p *Provenance) Warnings() []string {
+	var out []string
+	for _, v := range p.violations {
+		out = append(out, v.message)
+	}
+	return out
+}
+
+// Lock returns LockLocked or LockStricter when the system config locks key
+// (or a table containing it), and "" otherwise.
+func (p *Provenance) Lock(key string) string {
+	covers := func(locked string) bool {
+		return locked == key || strings.HasPrefix(key, locked+".")
+	}
+	for _, k := range p.locks.Locked {
+		if covers(k) {
+			return LockLocked
+		}
+	}
+	for _, k := range p.locks.Stricter {
+		if covers(k) {
+			return LockStricter
+		}
+	}
+	return ""
+}
+
+// strictness says which values of a key are stricter.
+type strictness int
+
+const (
+	higherIsStricter strictness = iota
+	lowerIsStricter
+	limitIsStricter // lower is stricter, 0 (no limit) is the weakest
+	trueIsStricter
+	falseIsStricter
+	supersetIsStricter
+	subsetIsStricter
+	attestationIsStricter
+)
+
+// stricterKeys lists the keys that may be marked stricter. Other keys can
+// only be locked.
+var stricterKeys = map[string]strictness{
+	"general.min_approvals":                 higherIsStricter,
+	"general.require_different_model":       trueIsStricter,
+	"general.different_model_timeout":       higherIsStricter,
+	"general.approval_ttl_minutes":          lowerIsStricter,
+	"general.approval_ttl_critical_minutes": lowerIsStricter,
+	"general.cross_project_reviews":         falseIsStricter,
+	"general.bind_environment":              trueIsStricter,
+	"general.fingerprint_env_vars":          supersetIsStricter,
+	"general.sandbox_write_paths":           subsetIsStricter,
+	"general.sandbox_disable_network":       trueIsStricter,
+	"general.sandbox_cpu_seconds":           limitIsStricter,
+	"general.sandbox_memory_mb":             limitIsStricter,
+	"general.scrub_patterns":                supersetIsStricter,
+	"general.project_policy":                falseIsStricter,
+	"general.freeze_operators":              subsetIsStricter,
+
+	"daemon.tcp_require_auth":            trueIsStricter,
+	"daemon.tcp_tls_require_client_cert": trueIsStricter,
+	"daemon.tcp_tls_principals":          subsetIsStricter,
+
+	"rate_limits.max_pending_per_session": limitIsStricter,
+	"rate_limits.max_requests_per_minute": limitIsStricter,
+
+	"patterns.critical.min_approvals":         higherIsStricter,
+	"patterns.critical.dynamic_quorum":        falseIsStricter,
+	"patterns.critical.dynamic_quorum_floor":  higherIsStricter,
+	"patterns.critical.patterns":              supersetIsStricter,
+	"patterns.dangerous.min_approvals":        higherIsStricter,
+	"patterns.dangerous.dynamic_quorum":       falseIsStricter,
+	"patterns.dangerous.dynamic_quorum_floor": higherIsStricter,
+	"patterns.dangerous.patterns":             supersetIsStricter,
+	"patterns.caution.min_approvals":          higherIsStricter,
+	"patterns.caution.dynamic_quorum":         falseIsStricter,
+	"patterns.caution.dynamic_quorum_floor":   higherIsStricter,
+	"patterns.caution.patterns":               supersetIsStricter,
+	"patterns.safe.patterns":                  subsetIsStricter,
+
+	"agents.trusted_self_approve":                   subsetIsStricter,
+	"agents.trusted_self_approve_delay_seconds":     higherIsStricter,
+	"agents.blocked":                                supersetIsStricter,
+	"agents.reputation_enabled":                     trueIsStricter,
+	"agents.reputation_min_samples":                 lowerIsStricter,
+	"agents.reputation_low_score":                   higherIsStricter,
+	"agents.reputation_low_extra_approvals":         higherIsStricter,
+	"agents.reputation_low_require_different_model": trueIsStricter,
+	"agents.session_attestation":                    attestationIsStricter,
+}
+
+// attestationLevels orders agents.session_attestation from weakest.
+var attestationLevels = []string{"off", "record", "enforce"}
+
+// loadLocks reads the [locks] table of the system config.
+func loadLocks(path string) (Locks, error) {
+	var file struct {
+		Locks Locks `toml:"locks"`
+	}
+	if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
+		return Locks{}, nil
+	}
+	if _, err := toml.DecodeFile(path, &file); err != nil {
+		return Locks{}, fmt.Errorf("decode system config %s: %w", path, err)
+	}
+	def := DefaultConfig()
+	for _, key := range file.Locks.Locked {
+		if _, ok := GetValue(def, key); !ok {
+			return Locks{}, fmt.Errorf("system config %s: cannot lock unknown key %q", path, key)
+		}
+	}
+	for _, key := range file.Locks.Stricter {
+		if _, ok := stricterKeys[key]; !ok {
+			return Locks{}, fmt.Errorf("system config %s: key %q cannot be marked stricter; lock it instead", path, key)
+		}
+	}
+	return file.Locks, nil
+}
+
+// fileKeys returns the flattened keys set by a TOML config file, each mapped
+// to the file's path, or nil when the file does not exist.
+func fileKeys(path string) (map[string]string, error) {
+	if path == "" {
+		return nil, nil
+	}
+	var raw map[string]any
+	if _, err := toml.DecodeFile(path, &raw); err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("decode config %s: %w", path, err)
+	}
+	keys := map[string]string{}
+	flattenKeys("", raw, func(key string) { keys[key] = path })
+	return keys, nil
+}
+
+func flattenKeys(prefix string, m map[string]any, add func(string)) {
+	for k, val := range m {
+		key := strings.ToLower(k)
+		if prefix != "" {
+			key = prefix + "." + key
+		}
+		if child, ok := val.(map[string]any); ok {
+			flattenKeys(key, child, add)
+			continue
+		}
+		add(key)
+	}
+}
+
+// enforceLocks compares the effective configuration against the values of
+// the system layer for every locked key, resets each weakened key to its
+// system value and records it on prov. The reset keys are attributed to the
+// system layer.
+func enforceLocks(locks Locks, system Config, effective *Config, prov *Provenance) error {
+	var unrestored []string
+	restored := map[string]string{}
+	reset := func(key, problem string) {
+		if !copyValue(effective, system, key) {
+			unrestored = append(unrestored, problem)
+			return
+		}
+		restored[key] = SystemConfigPath
+		prov.violations = append(prov.violations, lockViolation{key: key, source: prov.Source(key), message: problem + "; keeping the system value"})
+	}
+	for _, key := range locks.Locked {
+		want, _ := GetValue(system, key)
+		got, _ := GetValue(*effective, key)
+		if !reflect.DeepEqual(want, got) {
+			reset(key, fmt.Sprintf("%s is locked to %v; %s sets %v", key, want, describeSource(prov.Source(key)), got))
+		}
+	}
+	for _, key := range locks.Stricter {
+		want, _ := GetValue(system, key)
+		got, _ := GetValue(*effective, key)
+		if !atLeastAsStrict(stricterKeys[key], got, want) {
+			reset(key, fmt.Sprintf("%s may only be made stricter than %v; %s sets %v", key, want, describeSource(prov.Source(key)), got))
+		}
+	}
+	if len(restored) > 0 {
+		prov.layers = append(prov.layers, layerKeys{layer: LayerSystem, origins: restored})
+	}
+	if len(unrestored) > 0 {
+		return fmt.Errorf("%w: %s", ErrLocked, strings.Join(unrestored, "; "))
+	}
+	return nil
+}
+
+// copyValue sets the dot-notated key of dst to its value in src, following
+// the mapstructure tags. It reports false for a key it cannot find.
+func copyValue(dst *Config, src Config, key string) bool {
+	d, s := reflect.ValueOf(dst).Elem(), reflect.ValueOf(src)
+	for _, seg := range strings.Split(key, ".") {
+		if d.Kind() != reflect.Struct {
+			return false
+		}
+		i := fieldIndex(d.Type(), seg)
+		if i < 0 {
+			return false
+		}
+		d, s = d.Field(i), s.Field(i)
+	}
+	d.Set(s)
+	return true
+}
+
+func fieldIndex(t reflect.Type, name string) int {
+	for i := 0; i < t.NumField(); i++ {
+		if tag, _, _ := strings.Cut(t.Field(i).Tag.Get("mapstructure"), ","); tag == name {
+			return i
+		}
+	}
+	return -1
+}
+
+func describeSource(s Source) string {
+	if s.Origin == "" {
+		return s.Layer + " config"
+	}
+	switch s.Layer {
+	case LayerEnv:
+		return "environment variable " + s.Origin
+	case LayerFlag:
+		return "flag " + s.Origin
+	default:
+		return s.Layer + " config " + s.Origin
+	}
+}
+
+// atLeastAsStrict reports whether got is as strict as want or stricter.
+func atLeastAsStrict(s strictness, got, want any) bool {
+	switch s {
+	case higherIsStricter, lowerIsStricter, limitIsStricter:
+		g, w := toFloat(got), toFloat(want)
+		switch {
+		case s == higherIsStricter:
+			return g >= w
+		case s == limitIsStricter && w == 0:
+			return true
+		case s == limitIsStricter && g == 0:
+			return false
+		default:
+			return g <= w
+		}
+	case trueIsStricter:
+		return got == true || want == false
+	case falseIsStricter:
+		return got == false || want == true
+	case supersetIsStricter:
+		return containsAll(got.([]string), want.([]string))
+	case subsetIsStricter:
+		return containsAll(want.([]string), got.([]string))
+	case attestationIsStricter:
+		return attestationLevel(got.(string)) >= attestationLevel(want.(string))
+	default:
+		return reflect.DeepEqual(got, want)
+	}
+}
+
+func toFloat(v any) float64 {
+	switch n := v.(type) {
+	case int:
+		return float64(n)
+	case float64:
+		return n
+	default:
+		return 0
+	}
+}
+
+func containsAll(set, items []string) bool {
+	have := make(map[string]bool, len(set))
+	for _, s := range set {
+		have[s] = true
+	}
+	for _, s := range items {
+		if !have[s] {
+			return false
+		}
+	}
+	return true
+}
+
+func attestationLevel(mode string) int {
+	for i, level := range attestationLevels {
+		if strings.EqualFold(mode, level) {
+			return i
+		}
+	}
+	return 0
+}
+
+// envKeys returns the keys set by SLB_* environment variables.
+func envKeys() map[string]string {
+	keys := map[string]string{}
+	for _, binding := range envBindings {
+		if os.Getenv(binding.Env) != "" {
+			keys[binding.Key] = binding.Env
+		}
+	}
+	return keys
+}
+
+// flagKeys returns the keys set by CLI flag overrides.
+func flagKeys(overrides map[string]any) map[string]string {
+	keys := make(map[string]string, len(overrides))
+	for k := range overrides {
+		keys[strings.ToLower(k)] = k
+	}
+	return keys
+}
+
+// rejectLocksTable refuses a [locks] table outside the system config.
+func rejectLocksTable(keys map[string]string) error {
+	var found []string
+	for k, path := range keys {
+		if k == "locks" || strings.HasPrefix(k, "locks.") {
+			found = append(found, path)
+		}
+	}
+	if len(found) == 0 {
+		return nil
+	}
+	sort.Strings(found)
+	return fmt.Errorf("config %s: [locks] may only be set in the system config %s", found[0], SystemConfigPath)
+}
@@ -2,7 +2,10 @@ package config
 
 import (
 	"fmt"
+	"regexp"
 	"strings"
+
+	"github.com/Dicklesworthstone/slb/internal/schedule"
 )
 
 // Validate checks the configuration for semantic errors.
@@ -24,6 +27,46 @@ func Validate(cfg Config) error {
 	if cfg.General.MaxRollbackSizeMB < 0 {
 		errs = append(errs, "general.max_rollback_size_mb cannot be negative")
 	}
+	if cfg.General.SandboxCPUSeconds < 0 || cfg.General.SandboxMemoryMB < 0 || cfg.General.SandboxTimeoutSeconds < 0 {
+		errs = append(errs, "general.sandbox limits cannot be negative")
+	}
+	for i, aa := range cfg.General.PTYAutoAnswers {
+		label := fmt.Sprintf("general.pty_auto_answers[%d]", i)
+		if aa.Pattern == "" {
+			errs = append(errs, label+".pattern is required")
+		} else if _, err := regexp.Compile(aa.Pattern); err != nil {
+			errs = append(errs, fmt.Sprintf("%s.pattern is not a valid regular expression: %v", label, err))
+		}
+	}
+	for i, p := range cfg.General.ScrubPatterns {
+		if _, err := regexp.Compile(p); err != nil {
+			errs = append(errs, fmt.Sprintf("general.scrub_patterns[%d] is not a valid regular expression: %v", i, err))
+		}
+	}
+	windowNames := map[string]bool{}
+	for i, w := range cfg.General.ChangeWindows {
+		label := fmt.Sprintf("general.change_windows[%d]", i)
+		if w.Name == "" {
+			errs = append(errs, label+".name is required")
+		} else if windowNames[w.Name] {
+			errs = append(errs, fmt.Sprintf("%s.name %q is already used", label, w.Name))
+		}
+		windowNames[w.Name] = true
+		if _, err := schedule.NewWindow(w.Schedule, w.Duration, w.Timezone); err != nil {
+			errs = append(errs, fmt.Sprintf("%s: %v", label, err))
+		}
+	}
+	for i, f := range cfg.General.FreezePeriods {
+		label := fmt.Sprintf("general.freeze_periods[%d]", i)
+		if _, err := schedule.NewWindow(f.Schedule, f.Duration, f.Timezone); err != nil {
+			errs = append(errs, fmt.Sprintf("%s: %v", label, err))
+		}
+		for _, tier := range f.Tiers {
+			if !oneOf(strings.ToLower(tier), "critical", "dangerous", "caution") {
+				errs = append(errs, fmt.Sprintf("%s.tiers: unknown tier %q", label, tier))
+			}
+		}
+	}
 	if !oneOf(cfg.General.ConflictResolution, "any_rejection_blocks", "first_wins", "human_breaks_tie") {
 		errs = append(errs, "general.conflict_resolution must be one of any_rejection_blocks|first_wins|human_breaks_tie")
 	}
@@ -41,13 +84,77 @@ func Validate(cfg Config) error {
 		errs = append(errs, "rate_limits.rate_limit_action must be one of reject|queue|warn")
 	}
 
+	if (cfg.Daemon.TCPTLSCert == "") != (cfg.Daemon.TCPTLSKey == "") {
+		errs = append(errs, "daemon.tcp_tls_cert and daemon.tcp_tls_key must be set together")
+	}
+	if cfg.Daemon.TCPTLSRequireClientCert && cfg.Daemon.TCPTLSClientCA == "" {
+		errs = append(errs, "daemon.tcp_tls_require_client_cert requires daemon.tcp_tls_client_ca")
+	}
+
 	if cfg.Notifications.DesktopDelaySecs < 0 {
 		errs = append(errs, "notifications.desktop_delay_seconds cannot be negative")
 	}
+	if cfg.Notifications.EmailCautionDigestMins < 0 {
+		errs = append(errs, "notifications.email_caution_digest_minutes cannot be negative")
+	}
+	if cfg.Notifications.EmailEnabled {
+		n := cfg.Notifications
+		if strings.TrimSpace(n.EmailSMTPHost) == "" {
+			errs = append(errs, "notifications.email_smtp_host is required when email is enabled")
+		}
+		if n.EmailSMTPPort < 1 || n.EmailSMTPPort > 65535 {
+			errs = append(errs, "notifications.email_smtp_port must be between 1 and 65535")
+		}
+		if strings.TrimSpace(n.EmailFrom) == "" {
+			errs = append(errs, "notifications.email_from is required when email is enabled")
+		}
+		if len(n.EmailTo)+len(n.EmailCriticalTo)+len(n.EmailDangerousTo)+len(n.EmailCautionTo) == 0 {
+			errs = append(errs, "notifications.email_to (or a per-tier recipient list) is required when email is enabled")
+		}
+	}
+
+	if cfg.Notifications.WebhookMaxAttempts < 1 {
+		errs = append(errs, "notifications.webhook_max_attempts must be >= 1")
+	}
+	if cfg.Notifications.WebhookRetryBaseSecs < 0 {
+		errs = append(errs, "notifications.webhook_retry_base_seconds cannot be negative")
+	}
+	seenWebhooks := make(map[string]bool)
+	for i, wh := range cfg.Notifications.Webhooks {
+		name := strings.TrimSpace(wh.Name)
+		label := fmt.Sprintf("notifications.webhooks[%d]", i)
+		if name == "" {
+			errs = append(errs, label+".name is required")
+		} else if seenWebhooks[name] {
+			errs = append(errs, fmt.Sprintf("%s.name %q is duplicated", label, name))
+		}
+		seenWebhooks[name] = true
+		if strings.TrimSpace(wh.URL) == "" {
+			errs = append(errs, label+".url is required")
+		}
+		if wh.Format != "" && !oneOf(wh.Format, WebhookFormats...) {
+			errs = append(errs, fmt.Sprintf("%s.format must be one of %s", label, strings.Join(WebhookFormats, "|")))
+		}
+		for _, ev := range wh.Events {
+			if !oneOf(ev, WebhookEvents...) {
+				errs = append(errs, fmt.Sprintf("%s.events: unknown event %q", label, ev))
+			}
+		}
+		for _, tier := range wh.Tiers {
+			if !oneOf(strings.ToLower(tier), "critical", "dangerous", "caution", "safe") {
+				errs = append(errs, fmt.Sprintf("%s.tiers: unknown tier %q", label, tier))
+			}
+		}
+	}
 
 	if cfg.History.RetentionDays < 0 {
 		errs = append(errs, "history.retention_days cannot be negative")
 	}
+	for name, query := range cfg.History.SavedQueries {
+		if strings.TrimSpace(query) == "" {
+			errs = append(errs, fmt.Sprintf("history.saved_queries.%s is empty", name))
+		}
+	}
 
 	validateTier := func(name string, tier PatternTierConfig) {
 		if tier.MinApprovals < 0 {
@@ -68,6 +175,21 @@ func Validate(cfg Config) error {
 	if cfg.Agents.TrustedSelfApproveDelaySecs < 0 {
 		errs = append(errs, "agents.trusted_self_approve_delay_seconds cannot be negative")
 	}
+	if cfg.Agents.ReputationHalfLifeDays <= 0 {
+		errs = append(errs, "agents.reputation_half_life_days must be positive")
+	}
+	if cfg.Agents.ReputationMinSamples < 0 {
+		errs = append(errs, "agents.reputation_min_samples cannot be negative")
+	}
+	if cfg.Agents.ReputationLowScore < 0 || cfg.Agents.ReputationLowScore > 100 {
+		errs = append(errs, "agents.reputation_low_score must be between 0 and 100")
+	}
+	if cfg.Agents.ReputationLowExtraApprovals < 0 {
+		errs = append(errs, "agents.reputation_low_extra_approvals cannot be negative")
+	}
+	if !oneOf(cfg.Agents.SessionAttestation, "off", "record", "enforce") {
+		errs = append(errs, "agents.session_attestation must be one of off|record|enforce")
+	}
 
 	if len(errs) > 0 {
 		return fmt.Errorf("config validation failed: %s", strings.Join(errs, "; "))
@@ -75,6 +197,20 @@ func Validate(cfg Config) error {
 	return nil
 }
 
+// WebhookFormats lists the supported webhook payload formats.
+var WebhookFormats = []string{"json", "slack", "discord", "teams", "matrix"}
+
+// WebhookEvents lists the events a webhook endpoint can subscribe to.
+var WebhookEvents = []string{
+	"request_pending",
+	"request_approved",
+	"request_rejected",
+	"request_executed",
+	"request_cancelled",
+	"request_timeout",
+	"request_escalated",
+}
+
 func oneOf(val string, options ...string) bool {
 	for _, opt := range options {
 		if val == opt {
@@ -0,0 +1,141 @@
+// Package core implements remote abort of executing commands.
+package core
+
+import (
+	"context"
+	"crypto/hmac"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Dicklesworthstone/slb/internal/db"
+)
+
+// Abort errors.
+var (
+	ErrExecutionAborted = errors.New("execution aborted")
+	ErrNotExecuting     = errors.New("request is not executing")
+	ErrNotApprover      = errors.New("only an approver of the request can abort it")
+)
+
+// OutcomeAborted is the outcome result recorded when an approver aborted
+// the execution.
+const OutcomeAborted = "aborted"
+
+// DefaultAbortPollInterval is how often the executing process checks for an
+// abort.
+const DefaultAbortPollInterval = time.Second
+
+// AbortOptions holds parameters for aborting an execution.
+type AbortOptions struct {
+	// RequestID is the executing request (required).
+	RequestID string
+	// SessionID is the session of an approver of the request (required).
+	SessionID string
+	// SessionKey is the session's HMAC key (required).
+	SessionKey string
+	// Reason explains the abort.
+	Reason string
+}
+
+// AbortExecution records an approver's abort of an executing request. The
+// caller must hold the approver session's key. The process running the
+// command notices it within its poll interval, stops the command and marks
+// the request failed.
+func AbortExecution(database *db.DB, opts AbortOptions) (*db.Request, *db.ExecutionAbort, error) {
+	if opts.RequestID == "" {
+		return nil, nil, errors.New("request_id is required")
+	}
+	if opts.SessionID == "" {
+		return nil, nil, ErrSessionRequired
+	}
+	if opts.SessionKey == "" {
+		return nil, nil, ErrMissingSessionKey
+	}
+
+	session, err := database.GetSession(opts.SessionID)
+	if err != nil {
+		if errors.Is(err, db.ErrSessionNotFound) {
+			return nil, nil, ErrSessionNotFound
+		}
+		return nil, nil, fmt.Errorf("getting session: %w", err)
+	}
+	if session.EndedAt != nil {
+		return nil, nil, ErrSessionInactive
+	}
+	if !hmac.Equal([]byte(opts.SessionKey), []byte(session.SessionKey)) {
+		return nil, nil, ErrSessionKeyMismatch
+	}
+
+	request, err := database.GetRequest(opts.RequestID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("getting request: %w", err)
+	}
+
+	reviews, err := database.ListReviewsForRequest(request.ID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("getting reviews: %w", err)
+	}
+	approver := false
+	for _, r := range reviews {
+		if r.ReviewerSessionID == session.ID && r.Decision == db.DecisionApprove {
+			approver = true
+			break
+		}
+	}
+	if !approver {
+		return nil, nil, ErrNotApprover
+	}
+
+	if request.Status != db.StatusExecuting {
+		return nil, nil, fmt.Errorf("%w: status is %s", ErrNotExecuting, request.Status)
+	}
+
+	abort := &db.ExecutionAbort{
+		RequestID: request.ID,
+		SessionID: session.ID,
+		Agent:     session.AgentName,
+		Reason:    opts.Reason,
+	}
+	if err := database.CreateExecutionAbort(abort); err != nil {
+		return nil, nil, err
+	}
+	return request, abort, nil
+}
+
+// abortCause is the context cancellation cause set when an abort is seen.
+type abortCause struct {
+	abort *db.ExecutionAbort
+}
+
+func (c *abortCause) Error() string {
+	return abortError(c.abort).Error()
+}
+
+// watchAbort polls for an abort of requestID until ctx is done and cancels
+// the execution with an *abortCause when one appears.
+func (e *Executor) watchAbort(ctx context.Context, requestID string, interval time.Duration, cancel context.CancelCauseFunc) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			abort, err := e.db.GetExecutionAbort(requestID)
+			if err != nil {
+				continue
+			}
+			cancel(&abortCause{abort: abort})
+			return
+		}
+	}
+}
+
+// abortError describes an abort as an ErrExecutionAborted error.
+func abortError(a *db.ExecutionAbort) error {
+	if a.Reason == "" {
+		return fmt.Errorf("%w by %s", ErrExecutionAborted, a.Agent)
+	}
+	return fmt.Errorf("%w by %s: %s", ErrExecutionAborted, a.Agent, a.Reason)
+}
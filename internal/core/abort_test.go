@@ -0,0 +1,174 @@
+package core
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Dicklesworthstone/slb/internal/db"
+	"github.com/Dicklesworthstone/slb/internal/testutil"
+)
+
+// syncBuffer is a bytes.Buffer safe for the concurrent writes of a running
+// command and reads from the test.
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+// approvedShellRequest creates an approved shell request with one approval
+// by reviewer.
+func approvedShellRequest(t *testing.T, database *db.DB, requestor, reviewer *db.Session, raw string) *db.Request {
+	t.Helper()
+	cmd := db.CommandSpec{Raw: raw, Cwd: t.TempDir(), Shell: true}
+	cmd.Hash = db.ComputeCommandHash(cmd)
+	request := &db.Request{
+		ProjectPath:        requestor.ProjectPath,
+		RequestorSessionID: requestor.ID,
+		RequestorAgent:     requestor.AgentName,
+		RequestorModel:     requestor.Model,
+		RiskTier:           db.RiskTierCaution,
+		Command:            cmd,
+		Justification:      db.Justification{Reason: "test"},
+		Status:             db.StatusApproved,
+		MinApprovals:       1,
+	}
+	if err := database.CreateRequest(request); err != nil {
+		t.Fatalf("CreateRequest: %v", err)
+	}
+	if err := database.CreateReview(&db.Review{
+		RequestID: request.ID, ReviewerSessionID: reviewer.ID, ReviewerAgent: reviewer.AgentName,
+		ReviewerModel: reviewer.Model, Decision: db.DecisionApprove, Signature: "sig",
+	}); err != nil {
+		t.Fatalf("CreateReview: %v", err)
+	}
+	return request
+}
+
+func TestAbortExecution_Validation(t *testing.T) {
+	database := testutil.NewTestDB(t)
+	requestor := testutil.MakeSession(t, database)
+	reviewer := testutil.MakeSession(t, database, testutil.WithAgent("Reviewer"))
+	request := approvedShellRequest(t, database, requestor, reviewer, "true")
+
+	if _, _, err := AbortExecution(database, AbortOptions{RequestID: request.ID, SessionID: reviewer.ID, SessionKey: reviewer.SessionKey}); !errors.Is(err, ErrNotExecuting) {
+		t.Errorf("abort of an approved request: got %v, want ErrNotExecuting", err)
+	}
+	if err := database.UpdateRequestStatus(request.ID, db.StatusExecuting); err != nil {
+		t.Fatalf("UpdateRequestStatus: %v", err)
+	}
+	if _, _, err := AbortExecution(database, AbortOptions{RequestID: request.ID, SessionID: requestor.ID, SessionKey: requestor.SessionKey}); !errors.Is(err, ErrNotApprover) {
+		t.Errorf("abort by the requestor: got %v, want ErrNotApprover", err)
+	}
+	if _, _, err := AbortExecution(database, AbortOptions{RequestID: request.ID, SessionID: reviewer.ID}); !errors.Is(err, ErrMissingSessionKey) {
+		t.Errorf("abort without a key: got %v, want ErrMissingSessionKey", err)
+	}
+	if _, _, err := AbortExecution(database, AbortOptions{RequestID: request.ID, SessionID: reviewer.ID, SessionKey: requestor.SessionKey}); !errors.Is(err, ErrSessionKeyMismatch) {
+		t.Errorf("abort with another session's key: got %v, want ErrSessionKeyMismatch", err)
+	}
+
+	_, abort, err := AbortExecution(database, AbortOptions{RequestID: request.ID, SessionID: reviewer.ID, SessionKey: reviewer.SessionKey, Reason: "wrong host"})
+	if err != nil {
+		t.Fatalf("AbortExecution: %v", err)
+	}
+	if abort.Agent != reviewer.AgentName || abort.Reason != "wrong host" {
+		t.Errorf("abort = %+v", abort)
+	}
+	if _, _, err := AbortExecution(database, AbortOptions{RequestID: request.ID, SessionID: reviewer.ID, SessionKey: reviewer.SessionKey}); !errors.Is(err, db.ErrAbortExists) {
+		t.Errorf("second abort: got %v, want ErrAbortExists", err)
+	}
+}
+
+func TestExecutor_AbortStopsProcessGroup(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("shell execution test uses /bin/sh or $SHELL")
+	}
+	database := testutil.NewTestDB(t)
+	requestor := testutil.MakeSession(t, database)
+	reviewer := testutil.MakeSession(t, database, testutil.WithAgent("Reviewer"))
+	// The background sleep keeps the output pipe open: unless the whole
+	// process group is signalled the execution cannot finish.
+	request := approvedShellRequest(t, database, requestor, reviewer, "echo started; sleep 30 & sleep 30; echo finished")
+
+	stream := &syncBuffer{}
+	type outcome struct {
+		result *ExecutionResult
+		err    error
+	}
+	done := make(chan outcome, 1)
+	go func() {
+		result, err := NewExecutor(database, nil).ExecuteApprovedRequest(context.Background(), ExecuteOptions{
+			RequestID:         request.ID,
+			SessionID:         requestor.ID,
+			LogDir:            t.TempDir(),
+			SuppressOutput:    true,
+			OutputStream:      stream,
+			AbortPollInterval: 20 * time.Millisecond,
+		})
+		done <- outcome{result, err}
+	}()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for !strings.Contains(stream.String(), "started") {
+		if time.Now().After(deadline) {
+			t.Fatalf("command output never reached the stream: %q", stream.String())
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if _, _, err := AbortExecution(database, AbortOptions{RequestID: request.ID, SessionID: reviewer.ID, SessionKey: reviewer.SessionKey, Reason: "stop"}); err != nil {
+		t.Fatalf("AbortExecution: %v", err)
+	}
+
+	var got outcome
+	select {
+	case got = <-done:
+	case <-time.After(killGracePeriod):
+		t.Fatal("execution did not stop after the abort")
+	}
+	if !errors.Is(got.err, ErrExecutionAborted) || got.result.Abort == nil {
+		t.Fatalf("ExecuteApprovedRequest = %+v, %v", got.result, got.err)
+	}
+	if strings.Contains(stream.String(), "finished") {
+		t.Error("command ran to completion despite the abort")
+	}
+
+	stored, err := database.GetRequest(request.ID)
+	if err != nil {
+		t.Fatalf("GetRequest: %v", err)
+	}
+	if stored.Status != db.StatusExecutionFailed {
+		t.Errorf("status = %s, want %s", stored.Status, db.StatusExecutionFailed)
+	}
+	out, err := database.GetOutcomeForRequest(request.ID)
+	if err != nil {
+		t.Fatalf("GetOutcomeForRequest: %v", err)
+	}
+	if out.Result != OutcomeAborted || !strings.Contains(out.Notes, "stop") {
+		t.Errorf("outcome = %+v", out)
+	}
+	logData, err := os.ReadFile(got.result.LogPath)
+	if err != nil {
+		t.Fatalf("reading log: %v", err)
+	}
+	if !strings.Contains(string(logData), "=== Execution Aborted ===") || !strings.Contains(string(logData), "Reason: stop") {
+		t.Errorf("log missing abort section:\n%s", logData)
+	}
+}
@@ -19,6 +19,7 @@ import (
 	"time"
 
 	"github.com/Dicklesworthstone/slb/internal/db"
+	"github.com/Dicklesworthstone/slb/internal/scrub"
 )
 
 // AttachmentConfig holds configuration for attachment handling.
@@ -34,6 +35,9 @@ type AttachmentConfig struct {
 	MaxImageSize int
 	// AllowedFileTypes restricts file types (empty means all allowed).
 	AllowedFileTypes []string
+	// Scrubber removes secrets from text attachments (default
+	// scrub.Default()).
+	Scrubber *scrub.Scrubber
 }
 
 // DefaultAttachmentConfig returns default configuration.
@@ -121,7 +125,7 @@ func LoadAttachmentFromFile(path string, config *AttachmentConfig) (*db.Attachme
 		contentStr = string(content)
 	}
 
-	return &db.Attachment{
+	attachment := &db.Attachment{
 		Type:    attachType,
 		Content: contentStr,
 		Metadata: map[string]any{
@@ -129,7 +133,9 @@ func LoadAttachmentFromFile(path string, config *AttachmentConfig) (*db.Attachme
 			"filename": filepath.Base(absPath),
 			"size":     info.Size(),
 		},
-	}, nil
+	}
+	scrubAttachment(attachment, config.Scrubber)
+	return attachment, nil
 }
 
 // LoadScreenshot loads an image file as a screenshot attachment.
@@ -355,11 +361,13 @@ func RunContextCommand(ctx context.Context, command string, config *AttachmentCo
 		meta["error"] = runErr.Error()
 	}
 
-	return &db.Attachment{
+	attachment := &db.Attachment{
 		Type:     db.AttachmentTypeContext,
 		Content:  outputStr,
 		Metadata: meta,
-	}, nil
+	}
+	scrubAttachment(attachment, config.Scrubber)
+	return attachment, nil
 }
 
 // CreateLogExcerpt creates a log excerpt attachment from a file.
@@ -403,7 +411,7 @@ func CreateLogExcerpt(path string, startLine, endLine int, config *AttachmentCon
 	// Extract lines
 	excerpt := strings.Join(lines[startLine-1:endLine], "\n")
 
-	return &db.Attachment{
+	attachment := &db.Attachment{
 		Type:    db.AttachmentTypeFile, // Log excerpts are a type of file attachment
 		Content: excerpt,
 		Metadata: map[string]any{
@@ -412,7 +420,9 @@ func CreateLogExcerpt(path string, startLine, endLine int, config *AttachmentCon
 			"total_lines": len(lines),
 			"type":        "log_excerpt",
 		},
-	}, nil
+	}
+	scrubAttachment(attachment, config.Scrubber)
+	return attachment, nil
 }
 
 // CreateDiffAttachment creates a diff attachment from git or a file.
@@ -0,0 +1,140 @@
+// Package core implements session attestation.
+package core
+
+import (
+	"os"
+	"os/user"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Dicklesworthstone/slb/internal/db"
+	"github.com/Dicklesworthstone/slb/internal/integrations"
+	"github.com/Dicklesworthstone/slb/internal/utils"
+)
+
+// AttestationMode controls how session attestation is used.
+type AttestationMode string
+
+const (
+	// AttestationOff collects nothing.
+	AttestationOff AttestationMode = "off"
+	// AttestationRecord records the process context and notes resumes
+	// from a different one.
+	AttestationRecord AttestationMode = "record"
+	// AttestationEnforce refuses resumes from a different process context.
+	AttestationEnforce AttestationMode = "enforce"
+)
+
+// maxContextChanges bounds the context changes kept on a session.
+const maxContextChanges = 20
+
+// transientProcesses are shells and wrappers that sit between an agent and
+// slb and change on every invocation, so they are skipped when choosing the
+// anchor process.
+var transientProcesses = map[string]bool{
+	"sh": true, "bash": true, "zsh": true, "fish": true, "dash": true, "ksh": true,
+	"tcsh": true, "csh": true, "env": true, "sudo": true, "doas": true, "nohup": true,
+	"timeout": true, "xargs": true, "script": true, "slb": true,
+}
+
+var containerIDPattern = regexp.MustCompile(`[0-9a-f]{64}`)
+
+// CollectAttestation gathers facts about the calling process context: the
+// ancestor process tree (Linux only), terminal, container, host, user and
+// the agent detected from the environment and process ancestry.
+func CollectAttestation() *db.SessionAttestation {
+	a := &db.SessionAttestation{CollectedAt: time.Now().UTC()}
+
+	ancestors := utils.ProcessAncestors(os.Getppid())
+	for _, p := range ancestors {
+		a.ProcessTree = append(a.ProcessTree, db.ProcessInfo{PID: p.PID, Name: p.Name, StartTime: p.StartTime})
+	}
+	if len(a.ProcessTree) == 0 {
+		a.ProcessTree = []db.ProcessInfo{{PID: os.Getppid()}}
+	}
+	for i := range a.ProcessTree {
+		if p := a.ProcessTree[i]; p.Name != "" && !transientProcesses[p.Name] {
+			a.Anchor = &p
+			break
+		}
+	}
+
+	a.TTY = controllingTTY()
+	a.ContainerID = containerID()
+	a.Hostname, _ = os.Hostname()
+	if u, err := user.Current(); err == nil {
+		a.User = u.Username
+	}
+	home, _ := os.UserHomeDir()
+	if agent := integrations.DetectAgentFrom(os.Getenv, ancestors, home); agent != nil {
+		a.DetectedProgram = string(agent.Type)
+		a.DetectedModel = agent.Model
+		a.DetectionSignals = agent.Signals
+	}
+	return a
+}
+
+// AttestationMismatch lists the fields in which current differs from the
+// attested context. Fields missing on either side are not compared.
+func AttestationMismatch(attested, current *db.SessionAttestation) []string {
+	if attested == nil || current == nil {
+		return nil
+	}
+	var fields []string
+	if attested.Anchor != nil && current.Anchor != nil && !sameProcess(*attested.Anchor, *current.Anchor) {
+		fields = append(fields, "anchor")
+	}
+	if attested.TTY != current.TTY {
+		fields = append(fields, "tty")
+	}
+	if attested.ContainerID != current.ContainerID {
+		fields = append(fields, "container_id")
+	}
+	if attested.Hostname != "" && current.Hostname != "" && attested.Hostname != current.Hostname {
+		fields = append(fields, "hostname")
+	}
+	if attested.User != "" && current.User != "" && attested.User != current.User {
+		fields = append(fields, "user")
+	}
+	return fields
+}
+
+func sameProcess(a, b db.ProcessInfo) bool {
+	if a.PID != b.PID || a.Name != b.Name {
+		return false
+	}
+	return a.StartTime == 0 || b.StartTime == 0 || a.StartTime == b.StartTime
+}
+
+func controllingTTY() string {
+	for _, f := range []*os.File{os.Stdin, os.Stderr} {
+		fi, err := f.Stat()
+		if err != nil || fi.Mode()&os.ModeCharDevice == 0 {
+			continue
+		}
+		if target, err := os.Readlink(filepath.Join(utils.ProcRoot, "self", "fd", strconv.Itoa(int(f.Fd())))); err == nil {
+			if strings.HasPrefix(target, "/dev/pts/") || strings.HasPrefix(target, "/dev/tty") {
+				return target
+			}
+		}
+	}
+	return ""
+}
+
+func containerID() string {
+	if data, err := os.ReadFile(filepath.Join(utils.ProcRoot, "self", "cgroup")); err == nil {
+		if id := containerIDPattern.FindString(string(data)); id != "" {
+			return id
+		}
+	}
+	for _, marker := range []string{"/.dockerenv", "/run/.containerenv"} {
+		if _, err := os.Stat(marker); err == nil {
+			name, _ := os.Hostname()
+			return "container:" + name
+		}
+	}
+	return ""
+}
@@ -0,0 +1,133 @@
+package core
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Dicklesworthstone/slb/internal/db"
+	"github.com/Dicklesworthstone/slb/internal/testutil"
+)
+
+func TestAttestationMismatch(t *testing.T) {
+	attested := &db.SessionAttestation{
+		Anchor:   &db.ProcessInfo{PID: 200, Name: "claude", StartTime: 4000},
+		TTY:      "/dev/pts/1",
+		Hostname: "dev",
+	}
+	same := &db.SessionAttestation{
+		Anchor:   &db.ProcessInfo{PID: 200, Name: "claude", StartTime: 4000},
+		TTY:      "/dev/pts/1",
+		Hostname: "dev",
+	}
+	if fields := AttestationMismatch(attested, same); len(fields) != 0 {
+		t.Errorf("same context reported mismatch: %v", fields)
+	}
+
+	reused := &db.SessionAttestation{
+		Anchor:   &db.ProcessInfo{PID: 200, Name: "claude", StartTime: 9000},
+		Hostname: "other",
+	}
+	fields := AttestationMismatch(attested, reused)
+	want := []string{"anchor", "tty", "hostname"}
+	if len(fields) != len(want) {
+		t.Fatalf("fields = %v, want %v", fields, want)
+	}
+	for i := range want {
+		if fields[i] != want[i] {
+			t.Errorf("fields = %v, want %v", fields, want)
+		}
+	}
+
+	if AttestationMismatch(nil, same) != nil {
+		t.Error("unattested session should not mismatch")
+	}
+}
+
+func TestResumeSession_ContextChange(t *testing.T) {
+	database := testutil.NewTestDB(t)
+	attest := func(pid int) *db.SessionAttestation {
+		return &db.SessionAttestation{
+			CollectedAt: time.Now().UTC(),
+			Anchor:      &db.ProcessInfo{PID: pid, Name: "claude"},
+		}
+	}
+	opts := ResumeOptions{
+		AgentName:       "BlueSnow",
+		Program:         "claude-code",
+		ProjectPath:     "/test/project",
+		CreateIfMissing: true,
+		Attestation:     attest(200),
+	}
+
+	sess, err := ResumeSession(database, opts)
+	if err != nil {
+		t.Fatalf("ResumeSession() error = %v", err)
+	}
+	if sess.Attestation == nil || sess.Attestation.Anchor.PID != 200 {
+		t.Fatalf("new session not attested: %+v", sess.Attestation)
+	}
+
+	// Same context: nothing recorded.
+	again, err := ResumeSession(database, opts)
+	if err != nil {
+		t.Fatalf("ResumeSession() error = %v", err)
+	}
+	if len(again.Attestation.ContextChanges) != 0 {
+		t.Errorf("unexpected context changes: %+v", again.Attestation.ContextChanges)
+	}
+
+	// Different context in record mode: resumed, change noted.
+	opts.Attestation = attest(300)
+	moved, err := ResumeSession(database, opts)
+	if err != nil {
+		t.Fatalf("ResumeSession() error = %v", err)
+	}
+	if moved.ID != sess.ID || moved.SessionKey != sess.SessionKey {
+		t.Error("record mode should resume the same session")
+	}
+	changes := moved.Attestation.ContextChanges
+	if len(changes) != 1 || changes[0].Fields[0] != "anchor" || changes[0].Anchor.PID != 300 {
+		t.Errorf("context changes = %+v", changes)
+	}
+	if moved.Attestation.Anchor.PID != 200 {
+		t.Error("original attestation should be kept")
+	}
+
+	// Enforce mode refuses, unless forced.
+	opts.EnforceAttestation = true
+	if _, err := ResumeSession(database, opts); !errors.Is(err, ErrSessionContextMismatch) {
+		t.Fatalf("expected ErrSessionContextMismatch, got %v", err)
+	}
+	opts.ForceEndMismatch = true
+	replaced, err := ResumeSession(database, opts)
+	if err != nil {
+		t.Fatalf("ResumeSession(force) error = %v", err)
+	}
+	if replaced.ID == sess.ID || replaced.Attestation.Anchor.PID != 300 {
+		t.Errorf("forced resume should start a new attested session: %+v", replaced)
+	}
+}
+
+func TestRotateSessionKey(t *testing.T) {
+	database := testutil.NewTestDB(t)
+	sess := testutil.MakeSession(t, database)
+
+	if _, err := RotateSessionKey(database, sess.ID, ""); !errors.Is(err, ErrMissingSessionKey) {
+		t.Errorf("expected ErrMissingSessionKey, got %v", err)
+	}
+	if _, err := RotateSessionKey(database, sess.ID, "00"); !errors.Is(err, ErrSessionKeyMismatch) {
+		t.Errorf("expected ErrSessionKeyMismatch, got %v", err)
+	}
+
+	rotated, err := RotateSessionKey(database, sess.ID, sess.SessionKey)
+	if err != nil {
+		t.Fatalf("RotateSessionKey: %v", err)
+	}
+	if rotated.SessionKey == sess.SessionKey {
+		t.Error("key not rotated")
+	}
+	if _, err := RotateSessionKey(database, sess.ID, sess.SessionKey); !errors.Is(err, ErrSessionKeyMismatch) {
+		t.Errorf("retired key should not rotate again, got %v", err)
+	}
+}
@@ -0,0 +1,238 @@
+// Package core provides batch review selection and submission.
+package core
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/Dicklesworthstone/slb/internal/db"
+)
+
+// RequestFilter selects pending requests for a batch review. Each field lists
+// accepted values; an empty field matches everything. All non-empty fields
+// must match.
+type RequestFilter struct {
+	// Tiers matches the risk tier (critical, dangerous, caution).
+	Tiers []string
+	// Agents matches the requesting agent name.
+	Agents []string
+	// Sessions matches the requesting session ID.
+	Sessions []string
+	// Models matches the requesting model.
+	Models []string
+	// Commands are glob patterns matched against the whole command; see
+	// commandGlob.
+	Commands []string
+}
+
+// ParseRequestFilter parses a filter expression such as
+// "tier=caution,agent=BlueLake". Keys are tier, agent, session, model and
+// command; alternatives for one key are separated by "|"
+// (e.g. "tier=caution|dangerous"). Repeating a key adds alternatives.
+func ParseRequestFilter(expr string) (RequestFilter, error) {
+	var f RequestFilter
+	expr = strings.TrimSpace(expr)
+	if expr == "" {
+		return f, fmt.Errorf("filter is empty")
+	}
+	for _, clause := range strings.Split(expr, ",") {
+		clause = strings.TrimSpace(clause)
+		if clause == "" {
+			continue
+		}
+		key, value, ok := strings.Cut(clause, "=")
+		if !ok {
+			return f, fmt.Errorf("invalid filter clause %q (want key=value)", clause)
+		}
+		key = strings.ToLower(strings.TrimSpace(key))
+		var values []string
+		for _, v := range strings.Split(value, "|") {
+			if v = strings.TrimSpace(v); v != "" {
+				values = append(values, v)
+			}
+		}
+		if len(values) == 0 {
+			return f, fmt.Errorf("filter clause %q has no value", clause)
+		}
+		switch key {
+		case "tier":
+			for _, v := range values {
+				tier := db.RiskTier(strings.ToLower(v))
+				if !tier.Valid() {
+					return f, fmt.Errorf("invalid tier %q", v)
+				}
+				f.Tiers = append(f.Tiers, string(tier))
+			}
+		case "agent":
+			f.Agents = append(f.Agents, values...)
+		case "session":
+			f.Sessions = append(f.Sessions, values...)
+		case "model":
+			f.Models = append(f.Models, values...)
+		case "command":
+			for _, v := range values {
+				if _, err := commandGlob(v); err != nil {
+					return f, fmt.Errorf("invalid command pattern %q: %w", v, err)
+				}
+			}
+			f.Commands = append(f.Commands, values...)
+		default:
+			return f, fmt.Errorf("unknown filter key %q (want tier, agent, session, model or command)", key)
+		}
+	}
+	if f.IsEmpty() {
+		return f, fmt.Errorf("filter is empty")
+	}
+	return f, nil
+}
+
+// globShellMeta are the characters "*", "?" and negated classes of a command
+// glob never match: command separators, pipes, redirections, subshells and
+// command substitution. A glob therefore cannot select a compound command
+// unless it spells out the separator.
+const globShellMeta = ";&|<>()`\n\r"
+
+// commandGlob compiles a command glob. Unlike a path glob, "*" matches any
+// run of characters including "/" and spaces, so "rm -rf ./dist/*" matches
+// "rm -rf ./dist/linux/amd64", but not "rm -rf ./dist/a && rm -rf ~".
+// "?" matches one character, "[...]" a character class ("[!...]" or
+// "[^...]" negated) and "\" escapes the next character.
+func commandGlob(pattern string) (*regexp.Regexp, error) {
+	word := "[^" + regexp.QuoteMeta(globShellMeta) + "]"
+	var b strings.Builder
+	b.WriteString(`^`)
+	for i := 0; i < len(pattern); i++ {
+		switch pattern[i] {
+		case '*':
+			b.WriteString(word + `*`)
+		case '?':
+			b.WriteString(word)
+		case '\\':
+			if i+1 == len(pattern) {
+				return nil, fmt.Errorf("trailing backslash")
+			}
+			i++
+			b.WriteString(regexp.QuoteMeta(pattern[i : i+1]))
+		case '[':
+			end := strings.IndexByte(pattern[i+1:], ']')
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated character class")
+			}
+			class := pattern[i+1 : i+1+end]
+			if class == "" {
+				return nil, fmt.Errorf("empty character class")
+			}
+			b.WriteByte('[')
+			if class[0] == '!' || class[0] == '^' {
+				b.WriteByte('^')
+				b.WriteString(regexp.QuoteMeta(globShellMeta))
+				class = class[1:]
+			}
+			b.WriteString(strings.NewReplacer(`\`, `\\`, `[`, `\[`, `^`, `\^`).Replace(class))
+			b.WriteByte(']')
+			i += end + 1
+		default:
+			b.WriteString(regexp.QuoteMeta(pattern[i : i+1]))
+		}
+	}
+	b.WriteString(`$`)
+	return regexp.Compile(b.String())
+}
+
+// IsEmpty reports whether the filter has no conditions.
+func (f RequestFilter) IsEmpty() bool {
+	return len(f.Tiers) == 0 && len(f.Agents) == 0 && len(f.Sessions) == 0 &&
+		len(f.Models) == 0 && len(f.Commands) == 0
+}
+
+// Match reports whether the request satisfies the filter.
+func (f RequestFilter) Match(r *db.Request) bool {
+	if r == nil {
+		return false
+	}
+	if len(f.Tiers) > 0 && !containsFold(f.Tiers, string(r.RiskTier)) {
+		return false
+	}
+	if len(f.Agents) > 0 && !containsFold(f.Agents, r.RequestorAgent) {
+		return false
+	}
+	if len(f.Sessions) > 0 && !contains(f.Sessions, r.RequestorSessionID) {
+		return false
+	}
+	if len(f.Models) > 0 && !containsFold(f.Models, r.RequestorModel) {
+		return false
+	}
+	if len(f.Commands) > 0 {
+		matched := false
+		for _, pattern := range f.Commands {
+			if re, err := commandGlob(pattern); err == nil && re.MatchString(r.Command.Raw) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+	return true
+}
+
+// SelectPendingRequests returns the project's pending requests that match the
+// filter, newest first. Requests made by excludeSessionID (the reviewer, who
+// cannot review their own requests) are left out.
+func SelectPendingRequests(database *db.DB, projectPath string, filter RequestFilter, excludeSessionID string) ([]*db.Request, error) {
+	pending, err := database.ListPendingRequests(projectPath)
+	if err != nil {
+		return nil, fmt.Errorf("listing pending requests: %w", err)
+	}
+	out := make([]*db.Request, 0, len(pending))
+	for _, r := range pending {
+		if excludeSessionID != "" && r.RequestorSessionID == excludeSessionID {
+			continue
+		}
+		if filter.Match(r) {
+			out = append(out, r)
+		}
+	}
+	return out, nil
+}
+
+// BatchReviewResult is the outcome of one review in a batch.
+type BatchReviewResult struct {
+	RequestID string
+	Result    *ReviewResult
+	Err       error
+}
+
+// SubmitBatch submits the same decision for each request. Every review is
+// signed and recorded individually through SubmitReview; a failure does not
+// stop the remaining reviews. opts.RequestID is ignored.
+func (rs *ReviewService) SubmitBatch(opts ReviewOptions, requestIDs []string) []BatchReviewResult {
+	results := make([]BatchReviewResult, 0, len(requestIDs))
+	for _, id := range requestIDs {
+		o := opts
+		o.RequestID = id
+		res, err := rs.SubmitReview(o)
+		results = append(results, BatchReviewResult{RequestID: id, Result: res, Err: err})
+	}
+	return results
+}
+
+func contains(values []string, s string) bool {
+	for _, v := range values {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+func containsFold(values []string, s string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, s) {
+			return true
+		}
+	}
+	return false
+}
@@ -12,6 +12,8 @@ import (
 	"time"
 
 	"github.com/Dicklesworthstone/slb/internal/db"
+	"github.com/Dicklesworthstone/slb/internal/sandbox"
+	"github.com/Dicklesworthstone/slb/internal/scrub"
 )
 
 // CommandResult holds the result of running a command.
@@ -22,12 +24,52 @@ type CommandResult struct {
 	Output string
 	// Duration is the execution time.
 	Duration time.Duration
+	// SandboxViolations lists restrictions a sandboxed command ran into.
+	SandboxViolations []string
+	// AutoAnswers lists the prompts answered automatically in a PTY.
+	AutoAnswers []string
+	// Redactions counts the secrets scrubbed per artifact (ArtifactExecutionLog,
+	// ArtifactTranscript).
+	Redactions map[string]int
+}
+
+// RunOptions configures RunCommandWithOptions.
+type RunOptions struct {
+	// Sandbox confines the command when non-nil.
+	Sandbox *sandbox.Policy
+	// PTY attaches the command to a pseudo-terminal when non-nil.
+	PTY *PTYOptions
+	// Scrubber removes secrets from the log, the transcript and the
+	// returned output (default scrub.Default()). The stream is not scrubbed.
+	Scrubber *scrub.Scrubber
 }
 
 // RunCommand executes a command and captures output to both terminal and log file.
 // The command runs in the current shell environment, inheriting all env vars.
 func RunCommand(ctx context.Context, spec *db.CommandSpec, logPath string, stream io.Writer) (*CommandResult, error) {
+	return RunSandboxedCommand(ctx, spec, nil, logPath, stream)
+}
+
+// RunSandboxedCommand is RunCommand confined by policy when it is non-nil.
+// Sandbox violations are appended to the log and returned in the result.
+func RunSandboxedCommand(ctx context.Context, spec *db.CommandSpec, policy *sandbox.Policy, logPath string, stream io.Writer) (*CommandResult, error) {
+	return RunCommandWithOptions(ctx, spec, RunOptions{Sandbox: policy}, logPath, stream)
+}
+
+// RunPTYCommand is RunSandboxedCommand with the command attached to a
+// pseudo-terminal, for commands that behave differently without one. The
+// session is recorded as an asciicast transcript at TranscriptPath(logPath).
+func RunPTYCommand(ctx context.Context, spec *db.CommandSpec, policy *sandbox.Policy, opts PTYOptions, logPath string, stream io.Writer) (*CommandResult, error) {
+	return RunCommandWithOptions(ctx, spec, RunOptions{Sandbox: policy, PTY: &opts}, logPath, stream)
+}
+
+// RunCommandWithOptions is RunCommand with the options above. Secrets are
+// scrubbed from everything it persists or returns.
+func RunCommandWithOptions(ctx context.Context, spec *db.CommandSpec, opts RunOptions, logPath string, stream io.Writer) (*CommandResult, error) {
 	startTime := time.Now()
+	policy, ptyOpts := opts.Sandbox, opts.PTY
+	scrubber := scrubberOrDefault(opts.Scrubber)
+	redactions := map[string]int{}
 
 	// Open log file for writing
 	var logFile *os.File
@@ -42,32 +84,51 @@ func RunCommand(ctx context.Context, spec *db.CommandSpec, logPath string, strea
 		// Write header
 		fmt.Fprintf(logFile, "=== SLB Command Execution ===\n")
 		fmt.Fprintf(logFile, "Time: %s\n", startTime.Format(time.RFC3339))
-		fmt.Fprintf(logFile, "Command: %s\n", spec.Raw)
+		command, n := scrubber.String(spec.Raw)
+		redactions[ArtifactExecutionLog] += n
+		fmt.Fprintf(logFile, "Command: %s\n", command)
 		fmt.Fprintf(logFile, "CWD: %s\n", spec.Cwd)
 		fmt.Fprintf(logFile, "Shell: %v\n", spec.Shell)
 		fmt.Fprintf(logFile, "Hash: %s\n", spec.Hash)
+		if policy != nil {
+			fmt.Fprintf(logFile, "Sandbox:\n%s", indent(policy.Describe()))
+		}
+		if ptyOpts != nil {
+			fmt.Fprintf(logFile, "PTY: yes\n")
+		}
 		fmt.Fprintf(logFile, "=============================\n\n")
 	}
 
 	// Build the command
-	var cmd *exec.Cmd
+	var argv []string
 	if spec.Shell {
 		// Use shell execution
 		shell := os.Getenv("SHELL")
 		if shell == "" {
 			shell = "/bin/sh"
 		}
-		cmd = exec.CommandContext(ctx, shell, "-c", spec.Raw)
+		argv = []string{shell, "-c", spec.Raw}
 	} else if len(spec.Argv) > 0 {
 		// Use parsed argv
-		cmd = exec.CommandContext(ctx, spec.Argv[0], spec.Argv[1:]...)
+		argv = spec.Argv
 	} else {
 		// Parse the raw command
-		parts := strings.Fields(spec.Raw)
-		if len(parts) == 0 {
+		argv = strings.Fields(spec.Raw)
+		if len(argv) == 0 {
 			return nil, fmt.Errorf("empty command")
 		}
-		cmd = exec.CommandContext(ctx, parts[0], parts[1:]...)
+	}
+
+	var cmd *exec.Cmd
+	if policy != nil {
+		var err error
+		if cmd, err = sandbox.Command(ctx, *policy, argv[0], argv[1:]...); err != nil {
+			return nil, err
+		}
+	} else {
+		cmd = exec.CommandContext(ctx, argv[0], argv[1:]...)
+		// Inherit environment
+		cmd.Env = os.Environ()
 	}
 
 	// Set working directory
@@ -75,36 +136,53 @@ func RunCommand(ctx context.Context, spec *db.CommandSpec, logPath string, strea
 		cmd.Dir = spec.Cwd
 	}
 
-	// Inherit environment
-	cmd.Env = os.Environ()
-
-	// Set up output capture
+	// Set up output capture: the buffer and log file only see scrubbed
+	// output, the caller's stream sees it as printed.
 	var outputBuf bytes.Buffer
-	var writers []io.Writer
-
-	// Always capture to buffer
-	writers = append(writers, &outputBuf)
+	persisted := []io.Writer{&outputBuf}
+	if logFile != nil {
+		persisted = append(persisted, logFile)
+	}
+	scrubbed := scrubber.NewWriter(io.MultiWriter(persisted...))
+	writers := []io.Writer{scrubbed}
 
 	// Stream to caller-provided writer (optional)
 	if stream != nil {
 		writers = append(writers, stream)
 	}
 
-	// Write to log file
-	if logFile != nil {
-		writers = append(writers, logFile)
-	}
-
 	// Combine writers
 	multiWriter := io.MultiWriter(writers...)
-	cmd.Stdout = multiWriter
-	cmd.Stderr = multiWriter
-
-	// Connect stdin to terminal for interactive commands
-	cmd.Stdin = os.Stdin
 
-	// Run the command
-	err := cmd.Run()
+	var err error
+	var answers []string
+	if ptyOpts != nil {
+		transcriptPath := ""
+		if logPath != "" {
+			transcriptPath = TranscriptPath(logPath)
+		}
+		var transcriptRedactions int
+		answers, transcriptRedactions, err = runInPTY(cmd, *ptyOpts, spec.Raw, multiWriter, transcriptPath, scrubber)
+		redactions[ArtifactTranscript] = transcriptRedactions
+	} else {
+		cmd.Stdout = multiWriter
+		cmd.Stderr = multiWriter
+
+		// Connect stdin to terminal for interactive commands
+		cmd.Stdin = os.Stdin
+
+		// Run the command in its own process group so cancelling it stops
+		// its children too.
+		group := newProcessGroup(cmd)
+		err = cmd.Start()
+		if err == nil {
+			group.started()
+			err = cmd.Wait()
+		}
+		group.done()
+	}
+	_ = scrubbed.Close()
+	redactions[ArtifactExecutionLog] += scrubbed.Count()
 
 	duration := time.Since(startTime)
 
@@ -121,8 +199,28 @@ func RunCommand(ctx context.Context, spec *db.CommandSpec, logPath string, strea
 		}
 	}
 
+	var violations []string
+	if policy != nil {
+		if err := sandbox.SetupError(exitCode, outputBuf.String()); err != nil {
+			return nil, err
+		}
+		violations = sandbox.Violations(*policy, outputBuf.String(), cmd.ProcessState)
+	}
+
 	// Write footer to log
 	if logFile != nil {
+		if len(answers) > 0 {
+			fmt.Fprintf(logFile, "\n=== Auto-Answers ===\n")
+			for _, a := range answers {
+				fmt.Fprintf(logFile, "%s\n", a)
+			}
+		}
+		if len(violations) > 0 {
+			fmt.Fprintf(logFile, "\n=== Sandbox Violations ===\n")
+			for _, v := range violations {
+				fmt.Fprintf(logFile, "%s\n", v)
+			}
+		}
 		fmt.Fprintf(logFile, "\n=============================\n")
 		fmt.Fprintf(logFile, "Exit Code: %d\n", exitCode)
 		fmt.Fprintf(logFile, "Duration: %s\n", duration)
@@ -130,8 +228,16 @@ func RunCommand(ctx context.Context, spec *db.CommandSpec, logPath string, strea
 	}
 
 	return &CommandResult{
-		ExitCode: exitCode,
-		Output:   outputBuf.String(),
-		Duration: duration,
+		ExitCode:          exitCode,
+		Output:            outputBuf.String(),
+		Duration:          duration,
+		SandboxViolations: violations,
+		AutoAnswers:       answers,
+		Redactions:        redactions,
 	}, nil
 }
+
+func indent(s string) string {
+	lines := strings.Split(strings.TrimRight(s, "\n"), "\n")
+	return "  " + strings.Join(lines, "\n  ") + "\n"
+}
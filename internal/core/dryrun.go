@@ -11,6 +11,7 @@ import (
 	"time"
 
 	"github.com/Dicklesworthstone/slb/internal/db"
+	"github.com/Dicklesworthstone/slb/internal/scrub"
 	"github.com/mattn/go-shellwords"
 )
 
@@ -26,9 +27,10 @@ func GetDryRunCommand(cmd string) (string, bool) {
 	return shellJoin(tokens), true
 }
 
-// RunDryRun executes a dry-run variant for spec when supported.
+// RunDryRun executes a dry-run variant for spec when supported, scrubbing
+// secrets from its output with scrubber (default scrub.Default()).
 // If the command type is unsupported, it returns (nil, nil).
-func RunDryRun(spec *db.CommandSpec) (*db.DryRunResult, error) {
+func RunDryRun(spec *db.CommandSpec, scrubber *scrub.Scrubber) (*db.DryRunResult, error) {
 	if spec == nil {
 		return nil, fmt.Errorf("spec is required")
 	}
@@ -55,11 +57,12 @@ func RunDryRun(spec *db.CommandSpec) (*db.DryRunResult, error) {
 	cmd.Stderr = &stderr
 
 	err := cmd.Run()
-	out := combineStdoutStderr(stdout.String(), stderr.String())
+	out, redactions := scrubberOrDefault(scrubber).String(combineStdoutStderr(stdout.String(), stderr.String()))
 
 	res := &db.DryRunResult{
-		Command: shellJoin(tokens),
-		Output:  out,
+		Command:    shellJoin(tokens),
+		Output:     out,
+		Redactions: redactions,
 	}
 
 	if err != nil {
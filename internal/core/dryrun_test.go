@@ -275,7 +275,7 @@ func TestRunDryRun(t *testing.T) {
 	}
 
 	t.Run("nil spec returns error", func(t *testing.T) {
-		_, err := RunDryRun(nil)
+		_, err := RunDryRun(nil, nil)
 		if err == nil {
 			t.Error("expected error for nil spec")
 		}
@@ -286,7 +286,7 @@ func TestRunDryRun(t *testing.T) {
 			Raw: "",
 			Cwd: "/tmp",
 		}
-		_, err := RunDryRun(spec)
+		_, err := RunDryRun(spec, nil)
 		if err == nil {
 			t.Error("expected error for empty command")
 		}
@@ -297,7 +297,7 @@ func TestRunDryRun(t *testing.T) {
 			Raw: "   \t  ",
 			Cwd: "/tmp",
 		}
-		_, err := RunDryRun(spec)
+		_, err := RunDryRun(spec, nil)
 		if err == nil {
 			t.Error("expected error for whitespace-only command")
 		}
@@ -308,7 +308,7 @@ func TestRunDryRun(t *testing.T) {
 			Raw: "echo hello",
 			Cwd: "/tmp",
 		}
-		result, err := RunDryRun(spec)
+		result, err := RunDryRun(spec, nil)
 		if err != nil {
 			t.Errorf("expected nil error for unsupported command, got %v", err)
 		}
@@ -329,7 +329,7 @@ func TestRunDryRun(t *testing.T) {
 			Raw: "rm -rf " + testFile,
 			Cwd: tmpDir,
 		}
-		result, err := RunDryRun(spec)
+		result, err := RunDryRun(spec, nil)
 		if err != nil {
 			t.Fatalf("RunDryRun error: %v", err)
 		}
@@ -350,7 +350,7 @@ func TestRunDryRun(t *testing.T) {
 			Raw: "rm -rf /nonexistent/path/that/definitely/does/not/exist/anywhere",
 			Cwd: tmpDir,
 		}
-		result, err := RunDryRun(spec)
+		result, err := RunDryRun(spec, nil)
 		// rm dry-run converts to ls, which will fail on nonexistent path
 		if err == nil && result != nil {
 			// ls may return error or empty output for nonexistent path
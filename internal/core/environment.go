@@ -0,0 +1,275 @@
+// Package core implements environment fingerprinting.
+package core
+
+import (
+	"bufio"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/Dicklesworthstone/slb/internal/db"
+	"github.com/Dicklesworthstone/slb/internal/git"
+	"go.yaml.in/yaml/v3"
+)
+
+// Environment fingerprint keys. Extra environment variables are recorded
+// under envKeyPrefix + NAME.
+const (
+	envKeyKubeContext        = "kube.context"
+	envKeyKubeCluster        = "kube.cluster"
+	envKeyKubeNamespace      = "kube.namespace"
+	envKeyAWSProfile         = "aws.profile"
+	envKeyAWSRegion          = "aws.region"
+	envKeyGCloudProject      = "gcloud.project"
+	envKeyTerraformWorkspace = "terraform.workspace"
+	envKeyGitBranch          = "git.branch"
+	envKeyGitRemote          = "git.remote"
+	envKeyDatabaseHost       = "database.host"
+	envKeyPrefix             = "env."
+)
+
+// CaptureEnvironment fingerprints the risk-relevant context a command run in
+// cwd would see: kube context and namespace, AWS profile and region, gcloud
+// project, terraform workspace, git branch and remote, the DATABASE_URL host
+// and the values of extraEnv. The result is never nil: an empty fingerprint
+// still binds the request to a context where none of these is set.
+func CaptureEnvironment(cwd string, extraEnv []string) db.EnvironmentFingerprint {
+	home, _ := os.UserHomeDir()
+	return captureEnvironment(os.Getenv, home, cwd, extraEnv)
+}
+
+func captureEnvironment(getenv func(string) string, home, cwd string, extraEnv []string) db.EnvironmentFingerprint {
+	f := db.EnvironmentFingerprint{}
+	set := func(key, value string) {
+		if value = strings.TrimSpace(value); value != "" {
+			f[key] = value
+		}
+	}
+
+	kubeContext, kubeCluster, kubeNamespace := kubeContext(getenv, home)
+	set(envKeyKubeContext, kubeContext)
+	set(envKeyKubeCluster, kubeCluster)
+	set(envKeyKubeNamespace, kubeNamespace)
+
+	set(envKeyAWSProfile, firstEnv(getenv, "AWS_PROFILE", "AWS_DEFAULT_PROFILE"))
+	set(envKeyAWSRegion, firstEnv(getenv, "AWS_REGION", "AWS_DEFAULT_REGION"))
+	set(envKeyGCloudProject, gcloudProject(getenv, home))
+	set(envKeyTerraformWorkspace, terraformWorkspace(getenv, cwd))
+
+	if cwd != "" {
+		if branch, err := git.GetBranch(cwd); err == nil {
+			set(envKeyGitBranch, branch)
+		}
+		if remote, err := git.GetRemoteURL(cwd, "origin"); err == nil {
+			set(envKeyGitRemote, stripURLCredentials(remote))
+		}
+	}
+
+	if raw := getenv("DATABASE_URL"); raw != "" {
+		if u, err := url.Parse(raw); err == nil && u.Host != "" {
+			set(envKeyDatabaseHost, u.Host)
+		}
+	}
+
+	for _, name := range extraEnv {
+		if name = strings.TrimSpace(name); name != "" {
+			set(envKeyPrefix+name, getenv(name))
+		}
+	}
+	return f
+}
+
+// VerifyEnvironment checks a live fingerprint against the one recorded on
+// the request, returning ErrEnvironmentChanged naming each differing key.
+// Requests created without environment binding (a nil fingerprint) always
+// pass; an empty fingerprint requires an empty live one.
+func VerifyEnvironment(request *db.Request, live db.EnvironmentFingerprint) error {
+	if request.Environment == nil {
+		return nil
+	}
+	changed := request.Environment.Diff(live)
+	if len(changed) == 0 {
+		return nil
+	}
+	parts := make([]string, len(changed))
+	for i, key := range changed {
+		parts[i] = fmt.Sprintf("%s %s -> %s", key, fingerprintValue(request.Environment, key), fingerprintValue(live, key))
+	}
+	return fmt.Errorf("%w: %s", ErrEnvironmentChanged, strings.Join(parts, ", "))
+}
+
+// CaptureEnvironmentFor captures the live fingerprint to compare with the
+// request's: in the command's working directory and covering the extra
+// variables recorded on the request as well as extraEnv, so that a variable
+// set only after approval is noticed.
+func CaptureEnvironmentFor(request *db.Request, extraEnv []string) db.EnvironmentFingerprint {
+	return CaptureEnvironment(request.Command.Cwd, fingerprintEnvVars(request.Environment, extraEnv))
+}
+
+func fingerprintValue(f db.EnvironmentFingerprint, key string) string {
+	if v, ok := f[key]; ok {
+		return fmt.Sprintf("%q", v)
+	}
+	return "(unset)"
+}
+
+// fingerprintEnvVars merges the extra variables recorded in f with extra.
+func fingerprintEnvVars(f db.EnvironmentFingerprint, extra []string) []string {
+	seen := map[string]bool{}
+	var names []string
+	add := func(name string) {
+		if name != "" && !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+	for _, key := range f.Keys() {
+		if strings.HasPrefix(key, envKeyPrefix) {
+			add(strings.TrimPrefix(key, envKeyPrefix))
+		}
+	}
+	for _, name := range extra {
+		add(strings.TrimSpace(name))
+	}
+	return names
+}
+
+func firstEnv(getenv func(string) string, keys ...string) string {
+	for _, key := range keys {
+		if v := getenv(key); v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// kubeContext reads the current context, its cluster and namespace from the
+// kubeconfig files kubectl would use.
+func kubeContext(getenv func(string) string, home string) (context, cluster, namespace string) {
+	var paths []string
+	if kc := getenv("KUBECONFIG"); kc != "" {
+		paths = filepath.SplitList(kc)
+	} else if home != "" {
+		paths = []string{filepath.Join(home, ".kube", "config")}
+	}
+
+	type kubeconfig struct {
+		CurrentContext string `yaml:"current-context"`
+		Contexts       []struct {
+			Name    string `yaml:"name"`
+			Context struct {
+				Cluster   string `yaml:"cluster"`
+				Namespace string `yaml:"namespace"`
+			} `yaml:"context"`
+		} `yaml:"contexts"`
+	}
+	var configs []kubeconfig
+	for _, p := range paths {
+		data, err := os.ReadFile(p)
+		if err != nil {
+			continue
+		}
+		var cfg kubeconfig
+		if yaml.Unmarshal(data, &cfg) != nil {
+			continue
+		}
+		configs = append(configs, cfg)
+		// As with kubectl, the first file setting current-context wins.
+		if context == "" {
+			context = cfg.CurrentContext
+		}
+	}
+	if context == "" {
+		return "", "", ""
+	}
+	for _, cfg := range configs {
+		for _, c := range cfg.Contexts {
+			if c.Name == context {
+				return context, c.Context.Cluster, c.Context.Namespace
+			}
+		}
+	}
+	return context, "", ""
+}
+
+// gcloudProject reads the core/project property of the active gcloud
+// configuration.
+func gcloudProject(getenv func(string) string, home string) string {
+	if p := getenv("CLOUDSDK_CORE_PROJECT"); p != "" {
+		return p
+	}
+	dir := getenv("CLOUDSDK_CONFIG")
+	if dir == "" {
+		if home == "" {
+			return ""
+		}
+		dir = filepath.Join(home, ".config", "gcloud")
+	}
+	name := getenv("CLOUDSDK_ACTIVE_CONFIG_NAME")
+	if name == "" {
+		data, err := os.ReadFile(filepath.Join(dir, "active_config"))
+		if err != nil {
+			name = "default"
+		} else {
+			name = strings.TrimSpace(string(data))
+		}
+	}
+
+	file, err := os.Open(filepath.Join(dir, "configurations", "config_"+name))
+	if err != nil {
+		return ""
+	}
+	defer file.Close()
+
+	section := ""
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+			section = strings.TrimSpace(line[1 : len(line)-1])
+			continue
+		}
+		if section != "core" {
+			continue
+		}
+		if key, value, ok := strings.Cut(line, "="); ok && strings.TrimSpace(key) == "project" {
+			return strings.TrimSpace(value)
+		}
+	}
+	return ""
+}
+
+// terraformWorkspace returns the selected terraform workspace for cwd.
+func terraformWorkspace(getenv func(string) string, cwd string) string {
+	if ws := getenv("TF_WORKSPACE"); ws != "" {
+		return ws
+	}
+	if cwd == "" {
+		return ""
+	}
+	dataDir := getenv("TF_DATA_DIR")
+	if dataDir == "" {
+		dataDir = ".terraform"
+	}
+	if !filepath.IsAbs(dataDir) {
+		dataDir = filepath.Join(cwd, dataDir)
+	}
+	data, err := os.ReadFile(filepath.Join(dataDir, "environment"))
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(data))
+}
+
+// stripURLCredentials removes any user and password from a remote URL.
+// scp-like remotes (git@host:path) are returned unchanged.
+func stripURLCredentials(raw string) string {
+	u, err := url.Parse(raw)
+	if err != nil || u.User == nil || u.Host == "" {
+		return raw
+	}
+	u.User = nil
+	return u.String()
+}
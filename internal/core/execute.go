@@ -5,12 +5,17 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/Dicklesworthstone/slb/internal/db"
 	"github.com/Dicklesworthstone/slb/internal/integrations"
+	"github.com/Dicklesworthstone/slb/internal/pty"
+	"github.com/Dicklesworthstone/slb/internal/sandbox"
+	"github.com/Dicklesworthstone/slb/internal/scrub"
 )
 
 // Execution errors.
@@ -22,8 +27,16 @@ var (
 	ErrAlreadyExecuted     = errors.New("request has already been executed")
 	ErrAlreadyExecuting    = errors.New("request is already being executed")
 	ErrExecutionTimeout    = errors.New("command execution timed out")
+	ErrEnvironmentChanged  = errors.New("execution environment differs from the approved one")
+	ErrSandboxUnavailable  = errors.New("sandboxed execution required but unavailable")
+	ErrPTYUnavailable      = errors.New("PTY execution requested but unavailable")
+	ErrPolicyExecutor      = errors.New("policy does not allow this session to execute")
 )
 
+// OutcomeSandboxViolation is the outcome result recorded when a sandboxed
+// command ran into its restrictions.
+const OutcomeSandboxViolation = "sandbox_violation"
+
 // DefaultExecutionTimeout is the default timeout for command execution.
 const DefaultExecutionTimeout = 5 * time.Minute
 
@@ -47,6 +60,29 @@ type ExecuteOptions struct {
 	CaptureRollback bool
 	// MaxRollbackSizeMB limits filesystem rollback capture (0 uses config default).
 	MaxRollbackSizeMB int
+
+	// SandboxDefaults are the restrictions applied when a reviewer requires
+	// sandboxing but the request did not specify its own.
+	SandboxDefaults db.SandboxSpec
+
+	// OutputStream receives the command's output in addition to stdout,
+	// e.g. a daemon.OutputPublisher so reviewers can follow it. A stream with
+	// a Flush method is flushed before request_executed is published.
+	OutputStream io.Writer
+	// AbortPollInterval is how often to check whether an approver aborted
+	// the execution (default 1 second).
+	AbortPollInterval time.Duration
+
+	// PTY runs the command attached to a pseudo-terminal and records an
+	// asciicast transcript next to the log.
+	PTY bool
+	// PTYOptions configures the pseudo-terminal when PTY is set. Its
+	// AutoAnswers are replaced by those recorded on the request.
+	PTYOptions PTYOptions
+
+	// Scrubber removes secrets from the log, the transcript and the
+	// OutputStream (default scrub.Default()). Stdout is not scrubbed.
+	Scrubber *scrub.Scrubber
 }
 
 // ExecutionResult holds the result of command execution.
@@ -57,12 +93,27 @@ type ExecutionResult struct {
 	ExitCode int
 	// LogPath is the path to the execution log.
 	LogPath string
+	// TranscriptPath is the path to the asciicast transcript of a PTY
+	// execution.
+	TranscriptPath string
 	// Duration is the execution duration.
 	Duration time.Duration
 	// Output is the combined stdout/stderr output.
 	Output string
 	// TimedOut indicates if the command timed out.
 	TimedOut bool
+	// Sandbox is the sandbox the command ran in (nil if unsandboxed).
+	Sandbox *db.SandboxSpec
+	// SandboxViolations lists restrictions the command ran into.
+	SandboxViolations []string
+	// AutoAnswers lists the prompts answered automatically in the PTY.
+	AutoAnswers []string
+	// Redactions counts the secrets scrubbed per artifact.
+	Redactions map[string]int
+	// Steps has the result of each step of a plan request.
+	Steps []db.PlanStepResult
+	// Abort is set when an approver aborted the execution.
+	Abort *db.ExecutionAbort
 	// Error contains any execution error.
 	Error error
 }
@@ -72,6 +123,8 @@ type Executor struct {
 	db            *db.DB
 	patternEngine *PatternEngine
 	notifier      integrations.RequestNotifier
+	envVars       []string
+	schedule      *ChangeSchedule
 }
 
 // NewExecutor creates a new executor.
@@ -94,6 +147,20 @@ func (e *Executor) WithNotifier(n integrations.RequestNotifier) *Executor {
 	return e
 }
 
+// WithEnvironmentVars adds environment variables to those checked against
+// a request's environment fingerprint before execution.
+func (e *Executor) WithEnvironmentVars(names []string) *Executor {
+	e.envVars = names
+	return e
+}
+
+// WithSchedule sets the change windows and freeze periods checked before
+// execution.
+func (e *Executor) WithSchedule(s *ChangeSchedule) *Executor {
+	e.schedule = s
+	return e
+}
+
 // ExecuteApprovedRequest validates and executes an approved request.
 // This runs the command in the CALLER'S shell environment (client-side execution).
 func (e *Executor) ExecuteApprovedRequest(ctx context.Context, opts ExecuteOptions) (*ExecutionResult, error) {
@@ -112,6 +179,9 @@ func (e *Executor) ExecuteApprovedRequest(ctx context.Context, opts ExecuteOptio
 	if opts.LogDir == "" {
 		opts.LogDir = ".slb/logs"
 	}
+	if opts.AbortPollInterval <= 0 {
+		opts.AbortPollInterval = DefaultAbortPollInterval
+	}
 
 	if opts.MaxRollbackSizeMB <= 0 {
 		opts.MaxRollbackSizeMB = 100
@@ -140,8 +210,17 @@ func (e *Executor) ExecuteApprovedRequest(ctx context.Context, opts ExecuteOptio
 		return nil, fmt.Errorf("%w: status is %s", ErrRequestNotApproved, request.Status)
 	}
 
-	// Gate 2: Approval must not be expired
-	if request.ApprovalExpiresAt != nil && time.Now().After(*request.ApprovalExpiresAt) {
+	// Gate 1b: The policy must allow this session to execute
+	if rule := request.Policy.AllowsExecutor(session.AgentName, session.Model); rule != "" {
+		return nil, fmt.Errorf("%w: rule %q", ErrPolicyExecutor, rule)
+	}
+
+	// Gate 2: Approval must not be expired, and execution must fall in the
+	// request's window outside any freeze period
+	if err := e.checkSchedule(request, time.Now()); err != nil {
+		return nil, err
+	}
+	if approvalExpired(request) {
 		return nil, ErrApprovalExpired
 	}
 
@@ -150,21 +229,60 @@ func (e *Executor) ExecuteApprovedRequest(ctx context.Context, opts ExecuteOptio
 	if expectedHash != request.Command.Hash {
 		return nil, fmt.Errorf("%w: stored=%s computed=%s", ErrCommandHashMismatch, request.Command.Hash, expectedHash)
 	}
+	if request.Plan != nil {
+		if err := VerifyPlan(request.Plan); err != nil {
+			return nil, err
+		}
+	}
 
 	// Gate 4: Current pattern policy doesn't require higher tier
-	classification := e.patternEngine.ClassifyCommand(request.Command.Raw, request.Command.Cwd)
+	classification := e.classify(request)
 	if tierHigher(classification.Tier, request.RiskTier) {
 		return nil, fmt.Errorf("%w: approved as %s but now classified as %s",
 			ErrTierEscalated, request.RiskTier, classification.Tier)
 	}
 
+	// Gate 5: Execution environment must match the approved fingerprint
+	if request.Environment != nil {
+		if err := VerifyEnvironment(request, CaptureEnvironmentFor(request, e.envVars)); err != nil {
+			return nil, err
+		}
+	}
+
+	// Gate 6: A required sandbox must be available
+	sandboxSpec, err := e.requiredSandbox(request, opts.SandboxDefaults)
+	if err != nil {
+		return nil, err
+	}
+	var policy *sandbox.Policy
+	if sandboxSpec != nil && request.Plan == nil {
+		p := SandboxPolicy(sandboxSpec, request.Command)
+		policy = &p
+		if sandboxSpec.TimeoutSeconds > 0 {
+			if limit := time.Duration(sandboxSpec.TimeoutSeconds) * time.Second; limit < opts.Timeout {
+				opts.Timeout = limit
+			}
+		}
+	}
+
+	if opts.PTY {
+		if err := pty.Available(); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrPTYUnavailable, err)
+		}
+		// Only the answers the reviewers saw are typed.
+		if opts.PTYOptions.AutoAnswers, err = CompileAutoAnswers(request.AutoAnswers); err != nil {
+			return nil, err
+		}
+	}
+
 	// Preflight: create log file and capture rollback state before locking EXECUTING.
 	logPath, err := e.createLogFile(opts.LogDir, request.ID)
 	if err != nil {
 		return nil, fmt.Errorf("creating log file: %w", err)
 	}
 
-	if opts.CaptureRollback && (request.Rollback == nil || request.Rollback.Path == "") {
+	// A plan captures rollback state before each step instead.
+	if opts.CaptureRollback && request.Plan == nil && (request.Rollback == nil || request.Rollback.Path == "") {
 		data, err := CaptureRollbackState(ctx, request, RollbackCaptureOptions{
 			MaxSizeBytes: int64(opts.MaxRollbackSizeMB) * 1024 * 1024,
 		})
@@ -179,7 +297,7 @@ func (e *Executor) ExecuteApprovedRequest(ctx context.Context, opts ExecuteOptio
 		}
 	}
 
-	// Gate 5: First executor wins - transition to EXECUTING
+	// Gate 7: First executor wins - transition to EXECUTING
 	if err := e.db.UpdateRequestStatus(opts.RequestID, db.StatusExecuting); err != nil {
 		// If another executor already started, we'll get an error
 		if errors.Is(err, db.ErrInvalidTransition) {
@@ -208,19 +326,97 @@ func (e *Executor) ExecuteApprovedRequest(ctx context.Context, opts ExecuteOptio
 	result := &ExecutionResult{
 		Request: request,
 		LogPath: logPath,
+		Sandbox: sandboxSpec,
+	}
+	if opts.PTY {
+		result.TranscriptPath = TranscriptPath(logPath)
 	}
 
-	execCtx, cancel := context.WithTimeout(ctx, opts.Timeout)
+	// An approver's abort cancels the command like a timeout does; the
+	// cause tells them apart.
+	abortCtx, abort := context.WithCancelCause(ctx)
+	defer abort(nil)
+	var execCtx context.Context
+	var cancel context.CancelFunc
+	if request.Plan != nil {
+		// The timeout applies to each step.
+		execCtx, cancel = context.WithCancel(abortCtx)
+	} else {
+		execCtx, cancel = context.WithTimeout(abortCtx, opts.Timeout)
+	}
 	defer cancel()
-
-	var streamWriter *os.File
-	if !opts.SuppressOutput {
+	go e.watchAbort(execCtx, opts.RequestID, opts.AbortPollInterval, abort)
+
+	scrubber := scrubberOrDefault(opts.Scrubber)
+	var published *scrub.Writer
+	if opts.OutputStream != nil {
+		published = scrubber.NewWriter(opts.OutputStream)
+	}
+	var streamWriter io.Writer
+	switch {
+	case !opts.SuppressOutput && published != nil:
+		streamWriter = io.MultiWriter(os.Stdout, published)
+	case !opts.SuppressOutput:
 		streamWriter = os.Stdout
+	case published != nil:
+		streamWriter = published
+	}
+	runOpts := RunOptions{Sandbox: policy, Scrubber: scrubber}
+	if opts.PTY {
+		runOpts.PTY = &opts.PTYOptions
+	}
+	var cmdResult *CommandResult
+	var redactions map[string]int
+	if request.Plan != nil {
+		var planResult *PlanResult
+		planResult, err = RunPlan(execCtx, request.Plan, PlanRunOptions{
+			RequestID:         request.ID,
+			ProjectPath:       request.ProjectPath,
+			LogPath:           logPath,
+			StepTimeout:       opts.Timeout,
+			Sandbox:           sandboxSpec,
+			PTY:               runOpts.PTY,
+			Scrubber:          scrubber,
+			CaptureRollback:   opts.CaptureRollback,
+			MaxRollbackSizeMB: opts.MaxRollbackSizeMB,
+			Stream:            streamWriter,
+			OnStep: func(r db.PlanStepResult) {
+				_ = e.db.RecordPlanStepResult(&r)
+			},
+		})
+		result.Steps = planResult.Steps
+		redactions = planResult.Redactions
+		// Like a single command, a plan has no result when it timed out or
+		// could not run, but does when it was aborted.
+		if err == nil || errors.Is(err, context.Canceled) {
+			cmdResult = planResult.commandResult()
+		}
+	} else {
+		cmdResult, err = RunCommandWithOptions(execCtx, &request.Command, runOpts, logPath, streamWriter)
+		if cmdResult != nil {
+			redactions = cmdResult.Redactions
+		}
 	}
-	cmdResult, err := RunCommand(execCtx, &request.Command, logPath, streamWriter)
-	if err != nil {
+	var aborted *abortCause
+	if errors.As(context.Cause(abortCtx), &aborted) {
+		result.Abort = aborted.abort
+		result.Error = abortError(aborted.abort)
+		if cmdResult != nil {
+			result.ExitCode = cmdResult.ExitCode
+			result.Duration = cmdResult.Duration
+			result.Output = cmdResult.Output
+			result.SandboxViolations = cmdResult.SandboxViolations
+			result.AutoAnswers = cmdResult.AutoAnswers
+		}
+		appendAbort(logPath, aborted.abort)
+		_ = e.db.UpdateRequestStatus(opts.RequestID, db.StatusExecutionFailed)
+	} else if err != nil {
 		if errors.Is(err, context.DeadlineExceeded) {
 			result.TimedOut = true
+			if sandboxSpec != nil && sandboxSpec.TimeoutSeconds > 0 {
+				result.SandboxViolations = []string{fmt.Sprintf("time: exceeded the %ds time limit", sandboxSpec.TimeoutSeconds)}
+				appendSandboxViolations(logPath, result.SandboxViolations)
+			}
 			result.Error = ErrExecutionTimeout
 			_ = e.db.UpdateRequestStatus(opts.RequestID, db.StatusTimedOut)
 		} else {
@@ -231,6 +427,8 @@ func (e *Executor) ExecuteApprovedRequest(ctx context.Context, opts ExecuteOptio
 		result.ExitCode = cmdResult.ExitCode
 		result.Duration = cmdResult.Duration
 		result.Output = cmdResult.Output
+		result.SandboxViolations = cmdResult.SandboxViolations
+		result.AutoAnswers = cmdResult.AutoAnswers
 
 		// Determine final status based on exit code
 		if cmdResult.ExitCode == 0 {
@@ -250,12 +448,82 @@ func (e *Executor) ExecuteApprovedRequest(ctx context.Context, opts ExecuteOptio
 	}
 	_ = e.db.UpdateRequestExecution(opts.RequestID, exec)
 
+	result.Redactions = redactions
+	recordRedactions(e.db, opts.RequestID, redactions)
+
+	if result.Abort != nil {
+		_ = e.db.CreateOutcome(&db.ExecutionOutcome{
+			RequestID: opts.RequestID,
+			Result:    OutcomeAborted,
+			Notes:     result.Error.Error(),
+		})
+	}
+	if len(result.SandboxViolations) > 0 {
+		_ = e.db.CreateOutcome(&db.ExecutionOutcome{
+			RequestID:          opts.RequestID,
+			Result:             OutcomeSandboxViolation,
+			Notes:              strings.Join(result.SandboxViolations, "\n"),
+			ProblemDescription: fmt.Sprintf("%d sandbox violation(s)", len(result.SandboxViolations)),
+		})
+	}
+
+	if published != nil {
+		_ = published.Flush()
+	}
+	if f, ok := opts.OutputStream.(interface{ Flush() error }); ok {
+		_ = f.Flush()
+	}
+
 	// Notify (best effort)
 	_ = e.notifier.NotifyRequestExecuted(request, exec, result.ExitCode)
 
 	return result, result.Error
 }
 
+// requiredSandbox returns the sandbox the request must run in, or
+// ErrSandboxUnavailable when one is required but cannot be set up here.
+func (e *Executor) requiredSandbox(request *db.Request, defaults db.SandboxSpec) (*db.SandboxSpec, error) {
+	var reviews []*db.Review
+	if request.Sandbox == nil {
+		var err error
+		if reviews, err = e.db.ListReviewsForRequest(request.ID); err != nil {
+			return nil, fmt.Errorf("getting reviews: %w", err)
+		}
+	}
+	spec := EffectiveSandbox(request, reviews, defaults)
+	if spec == nil {
+		return nil, nil
+	}
+	if err := sandbox.Available(); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrSandboxUnavailable, err)
+	}
+	return spec, nil
+}
+
+// appendSandboxViolations records violations detected after the command's
+// log was closed.
+func appendSandboxViolations(logPath string, violations []string) {
+	f, err := os.OpenFile(logPath, os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		return
+	}
+	defer f.Close()
+	fmt.Fprintf(f, "\n=== Sandbox Violations ===\n%s\n", strings.Join(violations, "\n"))
+}
+
+// appendAbort records an abort at the end of the command's log.
+func appendAbort(logPath string, a *db.ExecutionAbort) {
+	f, err := os.OpenFile(logPath, os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		return
+	}
+	defer f.Close()
+	fmt.Fprintf(f, "\n=== Execution Aborted ===\nAborted by: %s\nTime: %s\n", a.Agent, a.CreatedAt.Format(time.RFC3339))
+	if a.Reason != "" {
+		fmt.Fprintf(f, "Reason: %s\n", a.Reason)
+	}
+}
+
 // createLogFile creates the log file for command output.
 func (e *Executor) createLogFile(logDir, requestID string) (string, error) {
 	// Ensure log directory exists
@@ -281,6 +549,16 @@ func (e *Executor) createLogFile(logDir, requestID string) (string, error) {
 	return logPath, nil
 }
 
+// classify classifies the request's command, or each step of its plan,
+// with the current patterns.
+func (e *Executor) classify(request *db.Request) *MatchResult {
+	if request.Plan != nil {
+		classification, _ := ClassifyPlan(e.patternEngine, request.Plan)
+		return classification
+	}
+	return e.patternEngine.ClassifyCommand(request.Command.Raw, request.Command.Cwd)
+}
+
 // tierHigher returns true if tier1 is higher (more restrictive) than tier2.
 func tierHigher(tier1, tier2 db.RiskTier) bool {
 	tierOrder := map[db.RiskTier]int{
@@ -291,6 +569,28 @@ func tierHigher(tier1, tier2 db.RiskTier) bool {
 	return tierOrder[tier1] > tierOrder[tier2]
 }
 
+// approvalExpired reports whether the approval TTL has passed. A request with
+// an execution window gets its TTL at creation, counted from when the window
+// first opens; without one its approval never counts as fresh.
+func approvalExpired(request *db.Request) bool {
+	if request.ApprovalExpiresAt == nil {
+		return request.Window != nil
+	}
+	return time.Now().After(*request.ApprovalExpiresAt)
+}
+
+// checkSchedule checks the request's execution window, the freeze periods
+// and the project's change freeze.
+func (e *Executor) checkSchedule(request *db.Request, now time.Time) error {
+	if err := e.schedule.CheckWindow(request.Window, now); err != nil {
+		return err
+	}
+	if err := e.schedule.CheckFreeze(request.RiskTier, now); err != nil {
+		return err
+	}
+	return CheckChangeFreeze(e.db, request.ProjectPath, request.RiskTier, now)
+}
+
 // CanExecute checks if a request can be executed and returns the reason if not.
 func (e *Executor) CanExecute(requestID string) (bool, string) {
 	request, err := e.db.GetRequest(requestID)
@@ -307,7 +607,10 @@ func (e *Executor) CanExecute(requestID string) (bool, string) {
 	if request.Status != db.StatusApproved {
 		return false, fmt.Sprintf("request is not approved (status: %s)", request.Status)
 	}
-	if request.ApprovalExpiresAt != nil && time.Now().After(*request.ApprovalExpiresAt) {
+	if err := e.checkSchedule(request, time.Now()); err != nil {
+		return false, err.Error()
+	}
+	if approvalExpired(request) {
 		return false, "approval has expired"
 	}
 
@@ -316,10 +619,26 @@ func (e *Executor) CanExecute(requestID string) (bool, string) {
 		return false, "command hash mismatch (command may have been modified)"
 	}
 
-	classification := e.patternEngine.ClassifyCommand(request.Command.Raw, request.Command.Cwd)
+	if request.Plan != nil {
+		if err := VerifyPlan(request.Plan); err != nil {
+			return false, err.Error()
+		}
+	}
+
+	classification := e.classify(request)
 	if tierHigher(classification.Tier, request.RiskTier) {
 		return false, fmt.Sprintf("policy escalation: command now classified as %s", classification.Tier)
 	}
 
+	if request.Environment != nil {
+		if err := VerifyEnvironment(request, CaptureEnvironmentFor(request, e.envVars)); err != nil {
+			return false, err.Error()
+		}
+	}
+
+	if _, err := e.requiredSandbox(request, db.SandboxSpec{}); err != nil {
+		return false, err.Error()
+	}
+
 	return true, ""
 }
@@ -0,0 +1,164 @@
+// Package core implements change freezes: human-declared periods during
+// which requests of a project are neither created nor executed.
+package core
+
+import (
+	"crypto/hmac"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/Dicklesworthstone/slb/internal/db"
+)
+
+// ErrChangeFreeze is returned while a change freeze covers a request.
+var ErrChangeFreeze = errors.New("change freeze in effect")
+
+// ErrFreezeNotStarter is returned when a session that neither started the
+// freeze nor belongs to a freeze operator tries to end it.
+var ErrFreezeNotStarter = errors.New("only the session that started the freeze or a freeze operator can end it")
+
+// ActiveChangeFreeze returns the project's freeze covering tier at t, or nil.
+func ActiveChangeFreeze(database *db.DB, projectPath string, tier RiskTier, t time.Time) (*db.Freeze, error) {
+	f, err := database.GetActiveFreeze(projectPath, t)
+	if errors.Is(err, db.ErrFreezeNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !f.Covers(tier) {
+		return nil, nil
+	}
+	return f, nil
+}
+
+// CheckChangeFreeze returns an error wrapping ErrChangeFreeze when a freeze
+// of the project covers tier at t.
+func CheckChangeFreeze(database *db.DB, projectPath string, tier RiskTier, t time.Time) error {
+	f, err := ActiveChangeFreeze(database, projectPath, tier, t)
+	if err != nil {
+		return fmt.Errorf("checking change freeze: %w", err)
+	}
+	if f == nil {
+		return nil
+	}
+	return fmt.Errorf("%w: %s", ErrChangeFreeze, DescribeFreeze(f))
+}
+
+// DescribeFreeze summarizes a freeze on one line.
+func DescribeFreeze(f *db.Freeze) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s and higher frozen by %s", f.Tier, f.StartedBy)
+	if f.Until != nil {
+		fmt.Fprintf(&b, " until %s", f.Until.Format(time.RFC3339))
+	} else {
+		b.WriteString(" until it is ended")
+	}
+	if f.Reason != "" {
+		fmt.Fprintf(&b, " (%s)", f.Reason)
+	}
+	return b.String()
+}
+
+// StartFreezeOptions describes a change freeze to start.
+type StartFreezeOptions struct {
+	ProjectPath string
+	Reason      string
+	// Tier is the lowest frozen tier (default caution: every tier that
+	// needs approval).
+	Tier RiskTier
+	// Until ends the freeze automatically; nil lasts until it is ended.
+	Until *time.Time
+	// SessionID and SessionKey identify the active session starting the
+	// freeze; only it can end the freeze again.
+	SessionID  string
+	SessionKey string
+}
+
+// StartChangeFreeze validates opts and records the freeze.
+func StartChangeFreeze(database *db.DB, opts StartFreezeOptions) (*db.Freeze, error) {
+	if strings.TrimSpace(opts.Reason) == "" {
+		return nil, errors.New("a reason is required to start a freeze")
+	}
+	tier := RiskTier(strings.ToLower(string(opts.Tier)))
+	switch tier {
+	case "":
+		tier = RiskTierCaution
+	case RiskTierCaution, RiskTierDangerous, RiskTierCritical:
+	default:
+		return nil, fmt.Errorf("invalid tier %q (expected caution, dangerous or critical)", opts.Tier)
+	}
+	sess, err := authenticateFreezeSession(database, opts.SessionID, opts.SessionKey)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now().UTC()
+	if opts.Until != nil && !opts.Until.After(now) {
+		return nil, fmt.Errorf("until (%s) must be in the future", opts.Until.Format(time.RFC3339))
+	}
+	f := &db.Freeze{
+		ProjectPath:      opts.ProjectPath,
+		Reason:           opts.Reason,
+		Tier:             tier,
+		StartedBy:        sess.AgentName,
+		StartedBySession: sess.ID,
+		StartedAt:        now,
+		Until:            opts.Until,
+	}
+	if err := database.CreateFreeze(f); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// EndFreezeOptions identifies the session ending a change freeze.
+type EndFreezeOptions struct {
+	ProjectPath string
+	SessionID   string
+	SessionKey  string
+	// Operators are agent names allowed to end a freeze started by another
+	// session.
+	Operators []string
+}
+
+// EndChangeFreeze ends the active freeze of a project on behalf of the
+// session that started it, or of an active session of a freeze operator,
+// and records the session's agent as the one who ended it.
+func EndChangeFreeze(database *db.DB, opts EndFreezeOptions) (*db.Freeze, error) {
+	sess, err := authenticateFreezeSession(database, opts.SessionID, opts.SessionKey)
+	if err != nil {
+		return nil, err
+	}
+	f, err := database.GetActiveFreeze(opts.ProjectPath, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	if f.StartedBySession != sess.ID && !slices.Contains(opts.Operators, sess.AgentName) {
+		return nil, ErrFreezeNotStarter
+	}
+	return database.EndFreeze(opts.ProjectPath, sess.AgentName)
+}
+
+// authenticateFreezeSession returns the active session id holding key.
+func authenticateFreezeSession(database *db.DB, id, key string) (*db.Session, error) {
+	if id == "" {
+		return nil, errors.New("session_id is required")
+	}
+	if key == "" {
+		return nil, ErrMissingSessionKey
+	}
+	sess, err := database.GetSession(id)
+	if err != nil {
+		return nil, fmt.Errorf("getting session: %w", err)
+	}
+	if !sess.IsActive() {
+		return nil, ErrSessionInactive
+	}
+	if !hmac.Equal([]byte(key), []byte(sess.SessionKey)) {
+		return nil, ErrSessionKeyMismatch
+	}
+	return sess, nil
+}
@@ -0,0 +1,161 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Dicklesworthstone/slb/internal/db"
+	"github.com/Dicklesworthstone/slb/internal/testutil"
+)
+
+func TestStartChangeFreeze(t *testing.T) {
+	database := testutil.NewTestDB(t)
+	sess := testutil.MakeSession(t, database, testutil.SessionWithAgentName("BlueLake"))
+	past := time.Now().Add(-time.Hour)
+
+	for _, opts := range []StartFreezeOptions{
+		{ProjectPath: "/p", SessionID: sess.ID, SessionKey: sess.SessionKey},
+		{ProjectPath: "/p", Reason: "release", Tier: "safe", SessionID: sess.ID, SessionKey: sess.SessionKey},
+		{ProjectPath: "/p", Reason: "release", Until: &past, SessionID: sess.ID, SessionKey: sess.SessionKey},
+		{ProjectPath: "/p", Reason: "release"},
+		{ProjectPath: "/p", Reason: "release", SessionID: sess.ID, SessionKey: "wrong"},
+	} {
+		if _, err := StartChangeFreeze(database, opts); err == nil {
+			t.Errorf("StartChangeFreeze(%+v): expected an error", opts)
+		}
+	}
+
+	f, err := StartChangeFreeze(database, StartFreezeOptions{
+		ProjectPath: "/p", Reason: "release", Tier: "DANGEROUS",
+		SessionID: sess.ID, SessionKey: sess.SessionKey,
+	})
+	if err != nil {
+		t.Fatalf("StartChangeFreeze: %v", err)
+	}
+	if f.Tier != RiskTierDangerous || f.StartedBy != "BlueLake" || f.StartedBySession != sess.ID || f.Until != nil {
+		t.Errorf("freeze = %+v", f)
+	}
+	if err := CheckChangeFreeze(database, "/p", RiskTierCaution, time.Now()); err != nil {
+		t.Errorf("caution during a dangerous freeze: %v", err)
+	}
+	if err := CheckChangeFreeze(database, "/p", RiskTierCritical, time.Now()); !errors.Is(err, ErrChangeFreeze) {
+		t.Errorf("critical during a dangerous freeze: got %v, want ErrChangeFreeze", err)
+	}
+	if err := CheckChangeFreeze(database, "/other", RiskTierCritical, time.Now()); err != nil {
+		t.Errorf("another project: %v", err)
+	}
+}
+
+func TestEndChangeFreeze(t *testing.T) {
+	database := testutil.NewTestDB(t)
+	starter := testutil.MakeSession(t, database, testutil.SessionWithAgentName("BlueLake"))
+	other := testutil.MakeSession(t, database, testutil.SessionWithAgentName("GreenField"))
+	ended := testutil.MakeSession(t, database, testutil.SessionWithAgentName("RedRiver"))
+	if err := database.EndSession(ended.ID); err != nil {
+		t.Fatalf("EndSession: %v", err)
+	}
+	start := func() {
+		t.Helper()
+		if _, err := StartChangeFreeze(database, StartFreezeOptions{
+			ProjectPath: "/p", Reason: "release", SessionID: starter.ID, SessionKey: starter.SessionKey,
+		}); err != nil {
+			t.Fatalf("StartChangeFreeze: %v", err)
+		}
+	}
+	start()
+
+	for _, tc := range []struct {
+		opts EndFreezeOptions
+		want error
+	}{
+		{EndFreezeOptions{ProjectPath: "/p", SessionID: starter.ID}, ErrMissingSessionKey},
+		{EndFreezeOptions{ProjectPath: "/p", SessionID: starter.ID, SessionKey: "wrong"}, ErrSessionKeyMismatch},
+		{EndFreezeOptions{ProjectPath: "/p", SessionID: ended.ID, SessionKey: ended.SessionKey}, ErrSessionInactive},
+		{EndFreezeOptions{ProjectPath: "/p", SessionID: other.ID, SessionKey: other.SessionKey}, ErrFreezeNotStarter},
+		{EndFreezeOptions{ProjectPath: "/p", SessionID: other.ID, SessionKey: other.SessionKey, Operators: []string{"ops"}}, ErrFreezeNotStarter},
+		{EndFreezeOptions{ProjectPath: "/other", SessionID: starter.ID, SessionKey: starter.SessionKey}, db.ErrFreezeNotFound},
+	} {
+		if _, err := EndChangeFreeze(database, tc.opts); !errors.Is(err, tc.want) {
+			t.Errorf("EndChangeFreeze(%+v) = %v, want %v", tc.opts, err, tc.want)
+		}
+	}
+
+	f, err := EndChangeFreeze(database, EndFreezeOptions{ProjectPath: "/p", SessionID: starter.ID, SessionKey: starter.SessionKey})
+	if err != nil {
+		t.Fatalf("EndChangeFreeze: %v", err)
+	}
+	if f.EndedBy != "BlueLake" || f.EndedAt == nil {
+		t.Errorf("ended freeze = %+v", f)
+	}
+
+	// A freeze operator can end a freeze started by another session.
+	start()
+	f, err = EndChangeFreeze(database, EndFreezeOptions{
+		ProjectPath: "/p", SessionID: other.ID, SessionKey: other.SessionKey, Operators: []string{"GreenField"},
+	})
+	if err != nil {
+		t.Fatalf("EndChangeFreeze as operator: %v", err)
+	}
+	if f.EndedBy != "GreenField" {
+		t.Errorf("ended freeze = %+v", f)
+	}
+}
+
+func TestCreateRequest_ChangeFreeze(t *testing.T) {
+	database := testutil.NewTestDB(t)
+	session := testutil.MakeSession(t, database, testutil.SessionWithAgentName("agent1"))
+	until := time.Now().UTC().Add(time.Hour).Truncate(time.Second)
+	if _, err := StartChangeFreeze(database, StartFreezeOptions{
+		ProjectPath: session.ProjectPath, Reason: "release", Until: &until,
+		SessionID: session.ID, SessionKey: session.SessionKey,
+	}); err != nil {
+		t.Fatalf("StartChangeFreeze: %v", err)
+	}
+	creator := NewRequestCreator(database, nil, nil, nil)
+
+	opts := CreateRequestOptions{
+		SessionID:     session.ID,
+		Command:       "rm -rf /etc",
+		Justification: Justification{Reason: "cleanup"},
+	}
+	if _, err := creator.CreateRequest(opts); !errors.Is(err, ErrChangeFreeze) {
+		t.Fatalf("during the freeze: got %v, want ErrChangeFreeze", err)
+	}
+
+	// A request whose window opens when the freeze ends is queued.
+	opts.Window = &db.ExecutionWindow{NotBefore: &until}
+	if _, err := creator.CreateRequest(opts); err != nil {
+		t.Errorf("queued after the freeze: %v", err)
+	}
+}
+
+func TestExecutor_ChangeFreeze(t *testing.T) {
+	database := testutil.NewTestDB(t)
+	requestor := testutil.MakeSession(t, database)
+	reviewer := testutil.MakeSession(t, database, testutil.WithAgent("Reviewer"))
+	request := approvedShellRequest(t, database, requestor, reviewer, "true")
+	if _, err := StartChangeFreeze(database, StartFreezeOptions{
+		ProjectPath: request.ProjectPath, Reason: "incident",
+		SessionID: reviewer.ID, SessionKey: reviewer.SessionKey,
+	}); err != nil {
+		t.Fatalf("StartChangeFreeze: %v", err)
+	}
+
+	executor := NewExecutor(database, nil)
+	if ok, reason := executor.CanExecute(request.ID); ok {
+		t.Error("CanExecute during a freeze = true")
+	} else if reason == "" {
+		t.Error("CanExecute gave no reason")
+	}
+	_, err := executor.ExecuteApprovedRequest(context.Background(), ExecuteOptions{
+		RequestID:      request.ID,
+		SessionID:      requestor.ID,
+		LogDir:         t.TempDir(),
+		SuppressOutput: true,
+	})
+	if !errors.Is(err, ErrChangeFreeze) {
+		t.Errorf("got %v, want ErrChangeFreeze", err)
+	}
+}
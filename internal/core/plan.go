@@ -0,0 +1,407 @@
+// Package core implements multi-step plans: ordered commands approved as one
+// request and executed step by step.
+package core
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/Dicklesworthstone/slb/internal/db"
+	"github.com/Dicklesworthstone/slb/internal/scrub"
+	"go.yaml.in/yaml/v3"
+)
+
+// Plan errors.
+var (
+	// ErrPlanEmpty is returned for a plan without steps.
+	ErrPlanEmpty = errors.New("plan has no steps")
+	// ErrPlanHashMismatch is returned when a plan's steps changed after
+	// it was approved.
+	ErrPlanHashMismatch = errors.New("plan hash mismatch")
+)
+
+// PlanFile is the YAML form of a plan read by `slb run --plan`:
+//
+//	name: decommission-db
+//	cwd: deploy                  # optional, relative to the current directory
+//	steps:
+//	  - command: kubectl scale deploy/db --replicas=0
+//	    expected_effect: database pods stop
+//	  - command: kubectl delete pvc data
+//	    cwd: k8s                 # optional, relative to the plan's cwd
+//	  - command: helm uninstall db
+//	    continue_on_failure: true
+type PlanFile struct {
+	Name  string         `yaml:"name"`
+	Cwd   string         `yaml:"cwd"`
+	Steps []PlanFileStep `yaml:"steps"`
+}
+
+// PlanFileStep is one step of a PlanFile.
+type PlanFileStep struct {
+	Command           string `yaml:"command"`
+	Cwd               string `yaml:"cwd"`
+	ExpectedEffect    string `yaml:"expected_effect"`
+	ContinueOnFailure bool   `yaml:"continue_on_failure"`
+}
+
+// LoadPlan reads a plan file; relative working directories are resolved
+// against cwd.
+func LoadPlan(path, cwd string) (*db.Plan, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading plan: %w", err)
+	}
+	plan, err := ParsePlan(data, cwd)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return plan, nil
+}
+
+// ParsePlan parses a plan in the PlanFile format. Steps run through the
+// shell, like `slb run` commands.
+func ParsePlan(data []byte, cwd string) (*db.Plan, error) {
+	var f PlanFile
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(&f); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("parsing plan: %w", err)
+	}
+	if len(f.Steps) == 0 {
+		return nil, ErrPlanEmpty
+	}
+
+	base := resolveDir(cwd, f.Cwd)
+	plan := &db.Plan{Name: strings.TrimSpace(f.Name)}
+	for i, s := range f.Steps {
+		command := strings.TrimSpace(s.Command)
+		if command == "" {
+			return nil, fmt.Errorf("step %d: command is required", i+1)
+		}
+		argv, _ := ParseCommandToArgv(command)
+		plan.Steps = append(plan.Steps, db.PlanStep{
+			Command: db.CommandSpec{
+				Raw:   command,
+				Argv:  argv,
+				Cwd:   resolveDir(base, s.Cwd),
+				Shell: true,
+			},
+			ExpectedEffect:    strings.TrimSpace(s.ExpectedEffect),
+			ContinueOnFailure: s.ContinueOnFailure,
+		})
+	}
+	return plan, nil
+}
+
+func resolveDir(base, dir string) string {
+	if dir == "" {
+		return base
+	}
+	if filepath.IsAbs(dir) || base == "" {
+		return filepath.Clean(dir)
+	}
+	return filepath.Join(base, dir)
+}
+
+// PlanSummary describes a plan in one line, e.g. for the Command of its
+// request.
+func PlanSummary(plan *db.Plan) string {
+	commands := make([]string, len(plan.Steps))
+	for i, s := range plan.Steps {
+		commands[i] = s.Command.Raw
+	}
+	name := "plan"
+	if plan.Name != "" {
+		name = "plan " + plan.Name
+	}
+	return fmt.Sprintf("%s (%d steps): %s", name, len(plan.Steps), strings.Join(commands, "; "))
+}
+
+// ClassifyPlan classifies each step with the pattern engine and returns the
+// plan's classification, with the highest tier and quorum of its steps,
+// followed by each step's own. A plan is safe only when every step is.
+func ClassifyPlan(engine *PatternEngine, plan *db.Plan) (*MatchResult, []*MatchResult) {
+	result := &MatchResult{IsSafe: len(plan.Steps) > 0}
+	steps := make([]*MatchResult, len(plan.Steps))
+	for i, s := range plan.Steps {
+		c := engine.ClassifyCommand(s.Command.Raw, s.Command.Cwd)
+		steps[i] = c
+		result.IsSafe = result.IsSafe && c.IsSafe
+		result.ParseError = result.ParseError || c.ParseError
+		if c.MatchedPattern != "" {
+			result.MatchedSegments = append(result.MatchedSegments, SegmentMatch{
+				Segment:        s.Command.Raw,
+				Tier:           c.Tier,
+				MatchedPattern: c.MatchedPattern,
+			})
+		}
+		if !c.NeedsApproval {
+			continue
+		}
+		if !result.NeedsApproval || tierHigher(c.Tier, result.Tier) {
+			result.Tier = c.Tier
+			result.MatchedPattern = c.MatchedPattern
+		}
+		result.NeedsApproval = true
+		if c.MinApprovals > result.MinApprovals {
+			result.MinApprovals = c.MinApprovals
+		}
+	}
+	if result.NeedsApproval {
+		result.IsSafe = false
+	} else if result.IsSafe {
+		result.Tier = RiskTier(RiskSafe)
+	}
+	return result, steps
+}
+
+// VerifyPlan checks that a plan is intact: it has steps and they still hash
+// to the approved hash.
+func VerifyPlan(plan *db.Plan) error {
+	if len(plan.Steps) == 0 {
+		return ErrPlanEmpty
+	}
+	if computed := db.ComputePlanHash(plan.Steps); computed != plan.Hash {
+		return fmt.Errorf("%w: stored=%s computed=%s", ErrPlanHashMismatch, plan.Hash, computed)
+	}
+	return nil
+}
+
+// PlanRunOptions configures RunPlan.
+type PlanRunOptions struct {
+	// RequestID names the steps' rollback captures (req-<id>-stepN).
+	RequestID string
+	// ProjectPath is where rollback captures are stored.
+	ProjectPath string
+	// LogPath is the plan's log. Each step logs next to it, in
+	// <log>_stepN.log; no logs are written when it is empty.
+	LogPath string
+	// StepTimeout limits each step (0 = no limit).
+	StepTimeout time.Duration
+	// Sandbox confines every step when non-nil.
+	Sandbox *db.SandboxSpec
+	// PTY runs every step in a pseudo-terminal when non-nil.
+	PTY *PTYOptions
+	// Scrubber removes secrets from the logs (default scrub.Default()).
+	Scrubber *scrub.Scrubber
+	// CaptureRollback captures rollback state before each supported step.
+	CaptureRollback bool
+	// MaxRollbackSizeMB limits filesystem rollback capture (0 = 100).
+	MaxRollbackSizeMB int
+	// Stream receives every step's output, preceded by a step banner.
+	Stream io.Writer
+	// OnStep is called with each step's result as soon as it is known,
+	// including the steps skipped after the plan stopped.
+	OnStep func(db.PlanStepResult)
+}
+
+// PlanResult is the outcome of RunPlan.
+type PlanResult struct {
+	// Steps has one result per plan step.
+	Steps []db.PlanStepResult
+	// ExitCode is the exit code of the step that stopped the plan, or 0
+	// when every step ran.
+	ExitCode int
+	// Duration is the time spent running steps.
+	Duration time.Duration
+	// Output is the scrubbed output of every step.
+	Output string
+	// SandboxViolations and AutoAnswers are those of every step, prefixed
+	// with "step N: ".
+	SandboxViolations []string
+	AutoAnswers       []string
+	// Redactions counts the secrets scrubbed per artifact, e.g.
+	// "step[1].execution_log".
+	Redactions map[string]int
+}
+
+// StepArtifact names an artifact of the n-th (1-based) step of a plan.
+func StepArtifact(n int, artifact string) string {
+	return fmt.Sprintf("step[%d].%s", n, artifact)
+}
+
+// PlanStepLogPath returns the log of the n-th (1-based) step of the plan
+// logging to logPath.
+func PlanStepLogPath(logPath string, n int) string {
+	if logPath == "" {
+		return ""
+	}
+	return fmt.Sprintf("%s_step%d.log", strings.TrimSuffix(logPath, ".log"), n)
+}
+
+// RunPlan runs a plan's steps in order. A step that exits non-zero stops the
+// plan unless it allows continuing; a timeout or a cancelled ctx always
+// does, and is returned as the error (context.DeadlineExceeded for a step
+// timeout). The result is never nil.
+func RunPlan(ctx context.Context, plan *db.Plan, opts PlanRunOptions) (*PlanResult, error) {
+	start := time.Now()
+	result := &PlanResult{Redactions: map[string]int{}}
+	scrubber := scrubberOrDefault(opts.Scrubber)
+	if opts.MaxRollbackSizeMB <= 0 {
+		opts.MaxRollbackSizeMB = 100
+	}
+
+	var log io.Writer = io.Discard
+	if opts.LogPath != "" {
+		f, err := os.OpenFile(opts.LogPath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+		if err != nil {
+			return result, fmt.Errorf("opening log file: %w", err)
+		}
+		defer f.Close()
+		log = f
+	}
+	fmt.Fprintf(log, "=== SLB Plan Execution ===\n")
+	fmt.Fprintf(log, "Time: %s\n", start.Format(time.RFC3339))
+	if plan.Name != "" {
+		fmt.Fprintf(log, "Plan: %s\n", plan.Name)
+	}
+	fmt.Fprintf(log, "Hash: %s\n", plan.Hash)
+	for i, s := range plan.Steps {
+		command, _ := scrubber.String(s.Command.Raw)
+		fmt.Fprintf(log, "Step %d: %s (cwd %s)\n", i+1, command, s.Command.Cwd)
+	}
+	fmt.Fprintf(log, "==========================\n\n")
+
+	var output strings.Builder
+	var runErr error
+	stopped := false
+	for i, step := range plan.Steps {
+		n := i + 1
+		stepResult := db.PlanStepResult{
+			RequestID: opts.RequestID,
+			Step:      n,
+			Command:   step.Command.Raw,
+			Status:    db.PlanStepSkipped,
+		}
+		if stopped {
+			result.Steps = append(result.Steps, stepResult)
+			fmt.Fprintf(log, "Step %d: skipped\n", n)
+			if opts.OnStep != nil {
+				opts.OnStep(stepResult)
+			}
+			continue
+		}
+
+		if opts.Stream != nil {
+			fmt.Fprintf(opts.Stream, "[slb] Step %d/%d: %s\n", n, len(plan.Steps), step.Command.Raw)
+		}
+		started := time.Now().UTC()
+		stepResult.StartedAt = &started
+		stepResult.LogPath = PlanStepLogPath(opts.LogPath, n)
+
+		cmdResult, err := runPlanStep(ctx, step, n, &stepResult, opts, scrubber)
+		finished := time.Now().UTC()
+		stepResult.FinishedAt = &finished
+		stepResult.DurationMs = finished.Sub(started).Milliseconds()
+
+		if cmdResult != nil {
+			exitCode := cmdResult.ExitCode
+			stepResult.ExitCode = &exitCode
+			output.WriteString(cmdResult.Output)
+			for artifact, count := range cmdResult.Redactions {
+				result.Redactions[StepArtifact(n, artifact)] += count
+			}
+			for _, v := range cmdResult.SandboxViolations {
+				result.SandboxViolations = append(result.SandboxViolations, fmt.Sprintf("step %d: %s", n, v))
+			}
+			for _, a := range cmdResult.AutoAnswers {
+				result.AutoAnswers = append(result.AutoAnswers, fmt.Sprintf("step %d: %s", n, a))
+			}
+		}
+
+		switch {
+		case ctx.Err() != nil:
+			stepResult.Status = db.PlanStepAborted
+			runErr = ctx.Err()
+		case errors.Is(err, context.DeadlineExceeded):
+			stepResult.Status = db.PlanStepTimedOut
+			runErr = context.DeadlineExceeded
+		case err != nil:
+			stepResult.Status = db.PlanStepFailed
+			runErr = fmt.Errorf("step %d: %w", n, err)
+		case cmdResult.ExitCode != 0:
+			stepResult.Status = db.PlanStepFailed
+		default:
+			stepResult.Status = db.PlanStepSucceeded
+		}
+		if runErr != nil || (stepResult.Status == db.PlanStepFailed && !step.ContinueOnFailure) {
+			stopped = true
+			if stepResult.ExitCode != nil {
+				result.ExitCode = *stepResult.ExitCode
+			}
+		}
+
+		fmt.Fprintf(log, "Step %d: %s", n, stepResult.Status)
+		if stepResult.ExitCode != nil {
+			fmt.Fprintf(log, " (exit %d)", *stepResult.ExitCode)
+		}
+		fmt.Fprintf(log, " in %dms, log %s\n", stepResult.DurationMs, stepResult.LogPath)
+		result.Steps = append(result.Steps, stepResult)
+		if opts.OnStep != nil {
+			opts.OnStep(stepResult)
+		}
+	}
+
+	result.Duration = time.Since(start)
+	result.Output = output.String()
+	fmt.Fprintf(log, "\n==========================\n")
+	fmt.Fprintf(log, "Exit Code: %d\n", result.ExitCode)
+	fmt.Fprintf(log, "Duration: %s\n", result.Duration)
+	fmt.Fprintf(log, "Completed: %s\n", time.Now().Format(time.RFC3339))
+	return result, runErr
+}
+
+// runPlanStep captures the step's rollback state if asked to, then runs it.
+func runPlanStep(ctx context.Context, step db.PlanStep, n int, stepResult *db.PlanStepResult, opts PlanRunOptions, scrubber *scrub.Scrubber) (*CommandResult, error) {
+	if opts.CaptureRollback && opts.RequestID != "" {
+		data, err := CaptureRollbackState(ctx, &db.Request{
+			ID:          fmt.Sprintf("%s-step%d", opts.RequestID, n),
+			ProjectPath: opts.ProjectPath,
+			Command:     step.Command,
+		}, RollbackCaptureOptions{MaxSizeBytes: int64(opts.MaxRollbackSizeMB) * 1024 * 1024})
+		if err != nil {
+			return nil, fmt.Errorf("capturing rollback state: %w", err)
+		}
+		if data != nil {
+			stepResult.RollbackPath = data.RollbackPath
+		}
+	}
+
+	runOpts := RunOptions{PTY: opts.PTY, Scrubber: scrubber}
+	if opts.Sandbox != nil {
+		policy := SandboxPolicy(opts.Sandbox, step.Command)
+		runOpts.Sandbox = &policy
+	}
+	stepCtx := ctx
+	if opts.StepTimeout > 0 {
+		var cancel context.CancelFunc
+		stepCtx, cancel = context.WithTimeout(ctx, opts.StepTimeout)
+		defer cancel()
+	}
+	cmdResult, err := RunCommandWithOptions(stepCtx, &step.Command, runOpts, stepResult.LogPath, opts.Stream)
+	if err == nil && stepCtx.Err() == context.DeadlineExceeded {
+		// The killed command exits with a signal rather than an error.
+		err = context.DeadlineExceeded
+	}
+	return cmdResult, err
+}
+
+// commandResult summarises the plan as a single command's result.
+func (r *PlanResult) commandResult() *CommandResult {
+	return &CommandResult{
+		ExitCode:          r.ExitCode,
+		Output:            r.Output,
+		Duration:          r.Duration,
+		SandboxViolations: r.SandboxViolations,
+		AutoAnswers:       r.AutoAnswers,
+		Redactions:        r.Redactions,
+	}
+}
@@ -0,0 +1,299 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/Dicklesworthstone/slb/internal/db"
+	"github.com/Dicklesworthstone/slb/internal/testutil"
+)
+
+func TestParsePlan(t *testing.T) {
+	plan, err := ParsePlan([]byte(`
+name: decommission-db
+cwd: deploy
+steps:
+  - command: kubectl scale deploy/db --replicas=0
+    expected_effect: database pods stop
+  - command: kubectl delete pvc data
+    cwd: k8s
+  - command: helm uninstall db
+    cwd: /srv/charts
+    continue_on_failure: true
+`), "/work")
+	if err != nil {
+		t.Fatalf("ParsePlan: %v", err)
+	}
+	if plan.Name != "decommission-db" || len(plan.Steps) != 3 {
+		t.Fatalf("plan = %+v", plan)
+	}
+	for i, want := range []string{"/work/deploy", "/work/deploy/k8s", "/srv/charts"} {
+		if got := plan.Steps[i].Command.Cwd; got != want {
+			t.Errorf("step %d cwd = %q, want %q", i+1, got, want)
+		}
+		if !plan.Steps[i].Command.Shell {
+			t.Errorf("step %d does not run through the shell", i+1)
+		}
+	}
+	if plan.Steps[0].ExpectedEffect != "database pods stop" || !plan.Steps[2].ContinueOnFailure || plan.Steps[1].ContinueOnFailure {
+		t.Errorf("steps = %+v", plan.Steps)
+	}
+
+	for name, data := range map[string]string{
+		"no steps":      "name: empty\n",
+		"empty command": "steps:\n  - command: ' '\n",
+		"unknown field": "steps:\n  - command: ls\n    retries: 3\n",
+	} {
+		if _, err := ParsePlan([]byte(data), "/work"); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
+
+func TestClassifyPlan(t *testing.T) {
+	engine := NewPatternEngine()
+	plan := &db.Plan{Steps: []db.PlanStep{
+		{Command: db.CommandSpec{Raw: "git stash drop"}},
+		{Command: db.CommandSpec{Raw: "rm -rf /etc"}},
+		{Command: db.CommandSpec{Raw: "git status"}},
+	}}
+	result, steps := ClassifyPlan(engine, plan)
+	if result.Tier != RiskTierCritical || !result.NeedsApproval || result.IsSafe {
+		t.Errorf("plan: Tier=%q NeedsApproval=%v IsSafe=%v", result.Tier, result.NeedsApproval, result.IsSafe)
+	}
+	if want := steps[1].MinApprovals; result.MinApprovals != want {
+		t.Errorf("MinApprovals = %d, want %d", result.MinApprovals, want)
+	}
+	if len(steps) != 3 || steps[0].Tier != RiskTierCaution || steps[1].Tier != RiskTierCritical {
+		t.Errorf("step tiers = %q, %q", steps[0].Tier, steps[1].Tier)
+	}
+
+	safe, _ := ClassifyPlan(engine, &db.Plan{Steps: []db.PlanStep{
+		{Command: db.CommandSpec{Raw: "git stash"}},
+		{Command: db.CommandSpec{Raw: "npm cache clean"}},
+	}})
+	if !safe.IsSafe || safe.NeedsApproval || safe.Tier != RiskTier(RiskSafe) {
+		t.Errorf("safe plan: Tier=%q NeedsApproval=%v IsSafe=%v", safe.Tier, safe.NeedsApproval, safe.IsSafe)
+	}
+}
+
+func TestCreateRequest_Plan(t *testing.T) {
+	database := testutil.NewTestDB(t)
+	session := testutil.MakeSession(t, database, testutil.SessionWithAgentName("agent1"))
+	creator := NewRequestCreator(database, nil, nil, nil)
+
+	plan := &db.Plan{Name: "cleanup", Steps: []db.PlanStep{
+		{Command: db.CommandSpec{Raw: "git stash drop", Cwd: "/srv", Shell: true}},
+		{Command: db.CommandSpec{Raw: "rm -rf /etc", Cwd: "/srv", Shell: true}},
+	}}
+	result, err := creator.CreateRequest(CreateRequestOptions{
+		SessionID:     session.ID,
+		Plan:          plan,
+		Justification: Justification{Reason: "cleanup"},
+	})
+	if err != nil {
+		t.Fatalf("CreateRequest: %v", err)
+	}
+	req := result.Request
+	if req.RiskTier != db.RiskTierCritical {
+		t.Errorf("RiskTier = %q, want critical", req.RiskTier)
+	}
+	if req.Command.Raw != PlanSummary(plan) || req.Command.Cwd != "/srv" {
+		t.Errorf("Command = %+v", req.Command)
+	}
+
+	stored, err := database.GetRequest(req.ID)
+	if err != nil {
+		t.Fatalf("GetRequest: %v", err)
+	}
+	if stored.Plan == nil || len(stored.Plan.Steps) != 2 {
+		t.Fatalf("Plan = %+v", stored.Plan)
+	}
+	if stored.Plan.Steps[0].RiskTier != db.RiskTierCaution || stored.Plan.Steps[1].RiskTier != db.RiskTierCritical {
+		t.Errorf("step tiers = %q, %q", stored.Plan.Steps[0].RiskTier, stored.Plan.Steps[1].RiskTier)
+	}
+	if err := VerifyPlan(stored.Plan); err != nil {
+		t.Errorf("VerifyPlan: %v", err)
+	}
+
+	if _, err := creator.CreateRequest(CreateRequestOptions{SessionID: session.ID, Plan: &db.Plan{}}); !errors.Is(err, ErrPlanEmpty) {
+		t.Errorf("empty plan: got %v, want ErrPlanEmpty", err)
+	}
+}
+
+func shellPlan(t *testing.T, commands ...string) *db.Plan {
+	t.Helper()
+	dir := t.TempDir()
+	plan := &db.Plan{Name: "test"}
+	for _, c := range commands {
+		plan.Steps = append(plan.Steps, db.PlanStep{Command: db.CommandSpec{Raw: c, Cwd: dir, Shell: true}})
+	}
+	plan.Hash = db.ComputePlanHash(plan.Steps)
+	return plan
+}
+
+func planStatuses(steps []db.PlanStepResult) string {
+	statuses := make([]string, len(steps))
+	for i, s := range steps {
+		statuses[i] = string(s.Status)
+	}
+	return strings.Join(statuses, ",")
+}
+
+func TestRunPlan_StopsOnFailure(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("shell execution test uses /bin/sh or $SHELL")
+	}
+	plan := shellPlan(t, "echo one", "echo two; exit 3", "echo three")
+	logPath := filepath.Join(t.TempDir(), "plan.log")
+	stream := &syncBuffer{}
+	var reported []db.PlanStepResult
+
+	result, err := RunPlan(context.Background(), plan, PlanRunOptions{
+		RequestID: "req",
+		LogPath:   logPath,
+		Stream:    stream,
+		OnStep:    func(r db.PlanStepResult) { reported = append(reported, r) },
+	})
+	if err != nil {
+		t.Fatalf("RunPlan: %v", err)
+	}
+	if got := planStatuses(result.Steps); got != "succeeded,failed,skipped" {
+		t.Errorf("statuses = %s", got)
+	}
+	if result.ExitCode != 3 || *result.Steps[1].ExitCode != 3 || result.Steps[2].ExitCode != nil {
+		t.Errorf("ExitCode = %d, steps = %+v", result.ExitCode, result.Steps)
+	}
+	if len(reported) != 3 {
+		t.Errorf("OnStep called %d times, want 3", len(reported))
+	}
+	if strings.Contains(result.Output, "three") || !strings.Contains(result.Output, "two") {
+		t.Errorf("Output = %q", result.Output)
+	}
+	if !strings.Contains(stream.String(), "[slb] Step 2/3: echo two; exit 3") {
+		t.Errorf("stream = %q, want a step banner", stream.String())
+	}
+
+	stepLog, err := os.ReadFile(PlanStepLogPath(logPath, 1))
+	if err != nil || !strings.Contains(string(stepLog), "one") {
+		t.Errorf("step 1 log = %q, %v", stepLog, err)
+	}
+	if _, err := os.Stat(PlanStepLogPath(logPath, 3)); !os.IsNotExist(err) {
+		t.Errorf("skipped step has a log: %v", err)
+	}
+	planLog, _ := os.ReadFile(logPath)
+	if !strings.Contains(string(planLog), "Step 3: skipped") || !strings.Contains(string(planLog), "Exit Code: 3") {
+		t.Errorf("plan log = %q", planLog)
+	}
+}
+
+func TestRunPlan_ContinueOnFailure(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("shell execution test uses /bin/sh or $SHELL")
+	}
+	plan := shellPlan(t, "exit 2", "echo after")
+	plan.Steps[0].ContinueOnFailure = true
+
+	result, err := RunPlan(context.Background(), plan, PlanRunOptions{})
+	if err != nil {
+		t.Fatalf("RunPlan: %v", err)
+	}
+	if got := planStatuses(result.Steps); got != "failed,succeeded" {
+		t.Errorf("statuses = %s", got)
+	}
+	if result.ExitCode != 0 || !strings.Contains(result.Output, "after") {
+		t.Errorf("ExitCode = %d, Output = %q", result.ExitCode, result.Output)
+	}
+}
+
+func TestExecutor_RunsPlan(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("shell execution test uses /bin/sh or $SHELL")
+	}
+	database := testutil.NewTestDB(t)
+	requestor := testutil.MakeSession(t, database)
+	reviewer := testutil.MakeSession(t, database, testutil.WithAgent("Reviewer"))
+	request := approvedPlanRequest(t, database, requestor, reviewer, shellPlan(t, "echo first", "false", "echo last"))
+
+	result, err := NewExecutor(database, nil).ExecuteApprovedRequest(context.Background(), ExecuteOptions{
+		RequestID:      request.ID,
+		SessionID:      requestor.ID,
+		LogDir:         t.TempDir(),
+		SuppressOutput: true,
+	})
+	if err != nil {
+		t.Fatalf("ExecuteApprovedRequest: %v", err)
+	}
+	if result.ExitCode != 1 || len(result.Steps) != 3 {
+		t.Fatalf("ExitCode = %d, Steps = %+v", result.ExitCode, result.Steps)
+	}
+
+	recorded, err := database.ListPlanStepResults(request.ID)
+	if err != nil {
+		t.Fatalf("ListPlanStepResults: %v", err)
+	}
+	if got := planStatuses(recorded); got != "succeeded,failed,skipped" {
+		t.Errorf("recorded statuses = %s", got)
+	}
+	if recorded[0].LogPath == "" || recorded[0].ExitCode == nil || *recorded[0].ExitCode != 0 {
+		t.Errorf("step 1 = %+v", recorded[0])
+	}
+	stored, _ := database.GetRequest(request.ID)
+	if stored.Status != db.StatusExecutionFailed {
+		t.Errorf("Status = %q, want %q", stored.Status, db.StatusExecutionFailed)
+	}
+}
+
+func TestExecutor_RejectsTamperedPlan(t *testing.T) {
+	database := testutil.NewTestDB(t)
+	requestor := testutil.MakeSession(t, database)
+	reviewer := testutil.MakeSession(t, database, testutil.WithAgent("Reviewer"))
+	plan := shellPlan(t, "echo first")
+	plan.Hash = "tampered"
+	request := approvedPlanRequest(t, database, requestor, reviewer, plan)
+
+	_, err := NewExecutor(database, nil).ExecuteApprovedRequest(context.Background(), ExecuteOptions{
+		RequestID:      request.ID,
+		SessionID:      requestor.ID,
+		LogDir:         t.TempDir(),
+		SuppressOutput: true,
+	})
+	if !errors.Is(err, ErrPlanHashMismatch) {
+		t.Errorf("got %v, want ErrPlanHashMismatch", err)
+	}
+}
+
+// approvedPlanRequest creates an approved plan request with one approval by
+// reviewer.
+func approvedPlanRequest(t *testing.T, database *db.DB, requestor, reviewer *db.Session, plan *db.Plan) *db.Request {
+	t.Helper()
+	cmd := db.CommandSpec{Raw: PlanSummary(plan), Cwd: plan.Steps[0].Command.Cwd, Shell: true}
+	cmd.Hash = db.ComputeCommandHash(cmd)
+	request := &db.Request{
+		ProjectPath:        requestor.ProjectPath,
+		RequestorSessionID: requestor.ID,
+		RequestorAgent:     requestor.AgentName,
+		RequestorModel:     requestor.Model,
+		RiskTier:           db.RiskTierCaution,
+		Command:            cmd,
+		Justification:      db.Justification{Reason: "test"},
+		Status:             db.StatusApproved,
+		MinApprovals:       1,
+		Plan:               plan,
+	}
+	if err := database.CreateRequest(request); err != nil {
+		t.Fatalf("CreateRequest: %v", err)
+	}
+	if err := database.CreateReview(&db.Review{
+		RequestID: request.ID, ReviewerSessionID: reviewer.ID, ReviewerAgent: reviewer.AgentName,
+		ReviewerModel: reviewer.Model, Decision: db.DecisionApprove, Signature: "sig",
+	}); err != nil {
+		t.Fatalf("CreateReview: %v", err)
+	}
+	return request
+}
@@ -0,0 +1,81 @@
+// Package core implements policy evaluation for new requests.
+package core
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/Dicklesworthstone/slb/internal/db"
+	"github.com/Dicklesworthstone/slb/internal/policy"
+)
+
+// Policy returns the policy requests are evaluated with.
+func (rc *RequestCreator) Policy() *policy.Policy {
+	if rc.config.Policy != nil {
+		return rc.config.Policy
+	}
+	defaults := rc.config.PolicyDefaults
+	defaults.Blocked = rc.config.BlockedAgents
+	return policy.Default(defaults)
+}
+
+// evaluatePolicy runs the policy rules for a request about to be created.
+// The reviewers the rules see are the other active sessions of the project.
+func (rc *RequestCreator) evaluatePolicy(opts CreateRequestOptions, session *db.Session, classification *MatchResult, projectPath string, environment db.EnvironmentFingerprint, now time.Time) (*policy.Decision, error) {
+	in := PolicyInput(opts, session, classification, projectPath, environment, now)
+	reviewers, err := PolicyReviewers(rc.db, projectPath, session.ID)
+	if err != nil {
+		return nil, err
+	}
+	in.Reviewers = reviewers
+	return rc.Policy().Evaluate(in)
+}
+
+// PolicyReviewers returns the active sessions of the project other than the
+// requestor's, the reviewers policy rules see.
+func PolicyReviewers(database *db.DB, projectPath, requestorSessionID string) ([]policy.Session, error) {
+	sessions, err := database.ListActiveSessions(projectPath)
+	if err != nil {
+		return nil, fmt.Errorf("listing reviewers: %w", err)
+	}
+	var reviewers []policy.Session
+	for _, s := range sessions {
+		if s.ID != requestorSessionID {
+			reviewers = append(reviewers, policySession(s))
+		}
+	}
+	return reviewers, nil
+}
+
+// PolicyInput describes a request to the policy rules; the caller adds the
+// available reviewers.
+func PolicyInput(opts CreateRequestOptions, session *db.Session, classification *MatchResult, projectPath string, environment db.EnvironmentFingerprint, now time.Time) policy.Input {
+	in := policy.Input{
+		Request: policy.Request{
+			Command:     opts.Command,
+			Cwd:         opts.Cwd,
+			ProjectPath: projectPath,
+			Shell:       opts.Shell,
+			Reason:      opts.Justification.Reason,
+		},
+		Classification: policy.Classification{
+			Tier:           string(classification.Tier),
+			MatchedPattern: classification.MatchedPattern,
+			MinApprovals:   classification.MinApprovals,
+		},
+		Session:     policySession(session),
+		Environment: environment,
+		Time:        now,
+	}
+	if opts.Plan != nil {
+		in.Request.Steps = len(opts.Plan.Steps)
+	}
+	if opts.Window != nil {
+		in.Request.Window = opts.Window.Recurring
+	}
+	return in
+}
+
+func policySession(s *db.Session) policy.Session {
+	return policy.Session{Agent: s.AgentName, Model: s.Model, Program: s.Program}
+}
@@ -0,0 +1,167 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/Dicklesworthstone/slb/internal/config"
+	"github.com/Dicklesworthstone/slb/internal/db"
+	"github.com/Dicklesworthstone/slb/internal/policy"
+	"github.com/Dicklesworthstone/slb/internal/testutil"
+)
+
+func TestCreateRequest_Policy(t *testing.T) {
+	database := testutil.NewTestDB(t)
+	project := testutil.WithProject("/project")
+	requestor := testutil.MakeSession(t, database, project, testutil.WithAgent("BlueDog"), testutil.WithModel("gpt-5"))
+	sameModel := testutil.MakeSession(t, database, project, testutil.WithAgent("RedCat"), testutil.WithModel("gpt-5"))
+	opus := testutil.MakeSession(t, database, project, testutil.WithAgent("GreenOwl"), testutil.WithModel("opus-4.5"))
+	intern := testutil.MakeSession(t, database, project, testutil.WithAgent("Intern"))
+
+	file, err := policy.Parse([]byte(`
+[[rule]]
+name = "build-dirs"
+when = 'request.command.contains("build") && reviewers.count >= 2'
+min_approvals = 2
+reviewer_models = ["opus*"]
+executors = ["deploy-*"]
+
+[[rule]]
+name = "interns"
+when = 'session.agent == "Intern"'
+deny = true
+reason = "interns ask a human"
+`))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	cfg := DefaultRequestCreatorConfig()
+	if cfg.Policy, err = policy.Default(policy.Defaults{}).Extend(file); err != nil {
+		t.Fatalf("Extend: %v", err)
+	}
+	creator := NewRequestCreator(database, nil, nil, cfg)
+
+	opts := CreateRequestOptions{
+		SessionID:     requestor.ID,
+		Command:       "rm -rf ./build",
+		Justification: Justification{Reason: "clean build"},
+	}
+	result, err := creator.CreateRequest(opts)
+	if err != nil {
+		t.Fatalf("CreateRequest: %v", err)
+	}
+	request, err := database.GetRequest(result.Request.ID)
+	if err != nil {
+		t.Fatalf("GetRequest: %v", err)
+	}
+	if request.MinApprovals != 2 || request.Policy == nil || len(request.Policy.Reviewers) != 1 || len(request.Policy.Executors) != 1 {
+		t.Fatalf("request = min %d, policy %+v", request.MinApprovals, request.Policy)
+	}
+
+	rs := NewReviewService(database, DefaultReviewConfig())
+	review := func(s *db.Session) error {
+		_, err := rs.SubmitReview(ReviewOptions{SessionID: s.ID, SessionKey: s.SessionKey, RequestID: request.ID, Decision: db.DecisionApprove})
+		return err
+	}
+	if err := review(sameModel); !errors.Is(err, ErrPolicyReviewer) {
+		t.Errorf("gpt-5 reviewer: got %v, want ErrPolicyReviewer", err)
+	}
+	if err := review(opus); err != nil {
+		t.Errorf("opus reviewer: %v", err)
+	}
+
+	opts.SessionID = intern.ID
+	if _, err := creator.CreateRequest(opts); !errors.Is(err, policy.ErrDenied) {
+		t.Errorf("intern: got %v, want policy.ErrDenied", err)
+	}
+}
+
+func TestCreateRequest_DefaultPolicy(t *testing.T) {
+	database := testutil.NewTestDB(t)
+	session := testutil.MakeSession(t, database)
+	creator := NewRequestCreator(database, nil, nil, nil)
+
+	result, err := creator.CreateRequest(CreateRequestOptions{
+		SessionID:     session.ID,
+		Command:       "rm -rf /etc",
+		Justification: Justification{Reason: "cleanup"},
+	})
+	if err != nil {
+		t.Fatalf("CreateRequest: %v", err)
+	}
+	if !result.Request.RequireDifferentModel || result.Request.MinApprovals != result.Classification.MinApprovals {
+		t.Errorf("critical request: min %d, different model %v", result.Request.MinApprovals, result.Request.RequireDifferentModel)
+	}
+	if result.Request.Policy == nil || !slices.Equal(result.Request.Policy.Rules, []string{"patterns.critical.min_approvals", "critical.require_different_model"}) {
+		t.Errorf("Policy = %+v", result.Request.Policy)
+	}
+
+	// Without a policy file the configured keys still apply.
+	conf := config.DefaultConfig()
+	conf.Patterns.Dangerous.MinApprovals = 3
+	conf.General.RequireDifferentModel = true
+	cfg := DefaultRequestCreatorConfig()
+	cfg.PolicyDefaults = policy.DefaultsFromConfig(conf)
+	creator = NewRequestCreator(database, nil, nil, cfg)
+	result, err = creator.CreateRequest(CreateRequestOptions{
+		SessionID:     session.ID,
+		Command:       "rm -rf ./build",
+		Justification: Justification{Reason: "cleanup"},
+	})
+	if err != nil {
+		t.Fatalf("CreateRequest: %v", err)
+	}
+	if result.Request.MinApprovals != 3 || !result.Request.RequireDifferentModel {
+		t.Errorf("dangerous request: min %d, different model %v", result.Request.MinApprovals, result.Request.RequireDifferentModel)
+	}
+}
+
+func TestExecutor_PolicyExecutor(t *testing.T) {
+	database := testutil.NewTestDB(t)
+	requestor := testutil.MakeSession(t, database)
+	reviewer := testutil.MakeSession(t, database, testutil.WithAgent("Reviewer"))
+	deployer := testutil.MakeSession(t, database, testutil.WithAgent("deploy-1"))
+
+	cmd := db.CommandSpec{Raw: "true", Cwd: t.TempDir(), Shell: true}
+	request := &db.Request{
+		ProjectPath:        requestor.ProjectPath,
+		RequestorSessionID: requestor.ID,
+		RequestorAgent:     requestor.AgentName,
+		RiskTier:           db.RiskTierCaution,
+		Command:            cmd,
+		Justification:      db.Justification{Reason: "test"},
+		Status:             db.StatusApproved,
+		MinApprovals:       1,
+		Policy: &db.PolicyDecision{
+			Rules:     []string{"deployers"},
+			Executors: []db.PolicyConstraint{{Rule: "deployers", Agents: []string{"deploy-*"}}},
+		},
+	}
+	if err := database.CreateRequest(request); err != nil {
+		t.Fatalf("CreateRequest: %v", err)
+	}
+	if err := database.CreateReview(&db.Review{
+		RequestID: request.ID, ReviewerSessionID: reviewer.ID, ReviewerAgent: reviewer.AgentName,
+		ReviewerModel: reviewer.Model, Decision: db.DecisionApprove, Signature: "sig",
+	}); err != nil {
+		t.Fatalf("CreateReview: %v", err)
+	}
+
+	run := func(s *db.Session) error {
+		_, err := NewExecutor(database, nil).ExecuteApprovedRequest(context.Background(), ExecuteOptions{
+			RequestID:      request.ID,
+			SessionID:      s.ID,
+			LogDir:         t.TempDir(),
+			SuppressOutput: true,
+		})
+		return err
+	}
+	if err := run(requestor); !errors.Is(err, ErrPolicyExecutor) {
+		t.Errorf("requestor: got %v, want ErrPolicyExecutor", err)
+	}
+	if err := run(deployer); err != nil {
+		t.Errorf("deployer: %v", err)
+	}
+}
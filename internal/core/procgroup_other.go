@@ -0,0 +1,17 @@
+//go:build !unix
+
+package core
+
+import "os/exec"
+
+// processGroup is a no-op where process groups are not supported; the
+// command is killed on its own when cancelled.
+type processGroup struct{}
+
+func newProcessGroup(*exec.Cmd) *processGroup { return &processGroup{} }
+
+func newSessionGroup(*exec.Cmd) *processGroup { return &processGroup{} }
+
+func (g *processGroup) started() {}
+
+func (g *processGroup) done() {}
@@ -0,0 +1,109 @@
+//go:build unix
+
+package core
+
+import (
+	"os"
+	"os/exec"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"golang.org/x/sys/unix"
+)
+
+// killGracePeriod is how long a cancelled command's process group has to
+// exit after SIGTERM before it is sent SIGKILL.
+const killGracePeriod = 5 * time.Second
+
+// processGroup runs a command in its own process group, so that aborting or
+// timing it out stops everything it started rather than only the shell.
+type processGroup struct {
+	cmd *exec.Cmd
+	// foreground is slb's own process group when the command was made the
+	// terminal's foreground group, restored once it exits.
+	foreground int
+	kill       *time.Timer
+	signals    chan os.Signal
+}
+
+// newProcessGroup configures cmd to start in a new process group. When
+// stdin is the terminal and slb is in its foreground, the command's group
+// takes over the terminal so interactive commands keep working and ^C
+// reaches them.
+func newProcessGroup(cmd *exec.Cmd) *processGroup {
+	g := &processGroup{cmd: cmd}
+	if cmd.SysProcAttr == nil {
+		cmd.SysProcAttr = &syscall.SysProcAttr{}
+	}
+	cmd.SysProcAttr.Setpgid = true
+	if cmd.Stdin == os.Stdin {
+		if pgrp, err := unix.IoctlGetInt(int(os.Stdin.Fd()), unix.TIOCGPGRP); err == nil && pgrp == unix.Getpgrp() {
+			cmd.SysProcAttr.Foreground = true
+			cmd.SysProcAttr.Ctty = 0 // the child's stdin
+			g.foreground = pgrp
+		}
+	}
+	g.setCancel()
+	return g
+}
+
+// newSessionGroup configures cmd to start as the leader of a new session,
+// as a command attached to its own pseudo-terminal must. A session leader
+// also leads a new process group, which is stopped the same way.
+func newSessionGroup(cmd *exec.Cmd) *processGroup {
+	g := &processGroup{cmd: cmd}
+	if cmd.SysProcAttr == nil {
+		cmd.SysProcAttr = &syscall.SysProcAttr{}
+	}
+	cmd.SysProcAttr.Setsid = true
+	g.setCancel()
+	return g
+}
+
+// setCancel makes cancelling the command terminate its process group,
+// killing it after killGracePeriod.
+func (g *processGroup) setCancel() {
+	cmd := g.cmd
+	cmd.Cancel = func() error {
+		pid := cmd.Process.Pid
+		g.kill = time.AfterFunc(killGracePeriod, func() { _ = syscall.Kill(-pid, syscall.SIGKILL) })
+		return syscall.Kill(-pid, syscall.SIGTERM)
+	}
+	cmd.WaitDelay = killGracePeriod + time.Second
+}
+
+// started forwards termination signals sent to slb to the command's group,
+// which no longer receives them from the terminal along with slb.
+func (g *processGroup) started() {
+	g.signals = make(chan os.Signal, 1)
+	signal.Notify(g.signals, syscall.SIGINT, syscall.SIGTERM, syscall.SIGHUP)
+	pid := g.cmd.Process.Pid
+	go func() {
+		for sig := range g.signals {
+			_ = syscall.Kill(-pid, sig.(syscall.Signal))
+		}
+	}()
+}
+
+// done stops signal forwarding and any pending kill, and gives the terminal
+// back to slb. It must be called after the command was waited for.
+func (g *processGroup) done() {
+	if g.signals != nil {
+		signal.Stop(g.signals)
+		close(g.signals)
+	}
+	if g.kill != nil {
+		g.kill.Stop()
+	}
+	if g.foreground != 0 {
+		// slb is now a background group: changing the foreground group
+		// raises SIGTTOU unless it is ignored.
+		wasIgnored := signal.Ignored(syscall.SIGTTOU)
+		signal.Ignore(syscall.SIGTTOU)
+		_ = unix.IoctlSetPointerInt(int(os.Stdin.Fd()), unix.TIOCSPGRP, g.foreground)
+		if !wasIgnored {
+			signal.Reset(syscall.SIGTTOU)
+		}
+	}
+}
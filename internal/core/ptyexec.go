@@ -0,0 +1,219 @@
+// Package core implements pseudo-terminal execution.
+package core
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Dicklesworthstone/slb/internal/db"
+	"github.com/Dicklesworthstone/slb/internal/pty"
+	"github.com/Dicklesworthstone/slb/internal/scrub"
+	"golang.org/x/term"
+)
+
+// ptyDrainTimeout is how long output is still read from the terminal after
+// the command exited, when a background process keeps it open.
+const ptyDrainTimeout = time.Second
+
+// autoAnswerWindow bounds the output an auto-answer pattern is matched
+// against.
+const autoAnswerWindow = 4096
+
+// PTYOptions configures execution in a pseudo-terminal.
+type PTYOptions struct {
+	// Size is the terminal size; zero uses slb's own terminal size, or
+	// pty.DefaultSize when it has none.
+	Size pty.Winsize
+	// AutoAnswers answer known prompts.
+	AutoAnswers []AutoAnswer
+}
+
+// AutoAnswer types Answer, followed by Enter, when the command's recent
+// output matches Pattern.
+type AutoAnswer struct {
+	Pattern *regexp.Regexp
+	Answer  string
+}
+
+// NewAutoAnswer compiles an auto-answer.
+func NewAutoAnswer(pattern, answer string) (AutoAnswer, error) {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return AutoAnswer{}, fmt.Errorf("invalid auto-answer pattern %q: %w", pattern, err)
+	}
+	return AutoAnswer{Pattern: re, Answer: answer}, nil
+}
+
+// CompileAutoAnswers compiles the auto-answers recorded on a request.
+func CompileAutoAnswers(answers []db.AutoAnswer) ([]AutoAnswer, error) {
+	var compiled []AutoAnswer
+	for _, a := range answers {
+		answer, err := NewAutoAnswer(a.Pattern, a.Answer)
+		if err != nil {
+			return nil, err
+		}
+		compiled = append(compiled, answer)
+	}
+	return compiled, nil
+}
+
+// runInPTY runs cmd attached to a new pseudo-terminal, copying everything
+// it prints to out and recording the session to transcriptPath when set.
+// When slb itself runs in a terminal, typing is forwarded to the command.
+// The transcript is scrubbed of secrets. It returns the auto-answers given,
+// as "prompt -> answer" lines, and the number of secrets scrubbed from the
+// transcript.
+func runInPTY(cmd *exec.Cmd, opts PTYOptions, command string, out io.Writer, transcriptPath string, scrubber *scrub.Scrubber) ([]string, int, error) {
+	stdin := int(os.Stdin.Fd())
+	interactive := term.IsTerminal(stdin)
+
+	size := opts.Size
+	if size == (pty.Winsize{}) {
+		size = pty.DefaultSize
+		if s, err := pty.GetSize(os.Stdin); err == nil && s.Rows > 0 && s.Cols > 0 {
+			size = s
+		}
+	}
+
+	if cmd.Env == nil {
+		cmd.Env = os.Environ()
+	}
+	termName := os.Getenv("TERM")
+	if termName == "" || termName == "dumb" {
+		termName = "xterm-256color"
+		cmd.Env = append(cmd.Env, "TERM="+termName)
+	}
+
+	var transcript *transcriptWriter
+	var scrubbed *scrub.Writer
+	redactions := 0
+	if transcriptPath != "" {
+		var err error
+		var n int
+		command, n = scrubber.String(command)
+		redactions += n
+		transcript, err = newTranscriptWriter(transcriptPath, TranscriptHeader{
+			Width:   int(size.Cols),
+			Height:  int(size.Rows),
+			Command: command,
+			Env:     map[string]string{"TERM": termName, "SHELL": os.Getenv("SHELL")},
+		})
+		if err != nil {
+			return nil, 0, err
+		}
+		defer transcript.Close()
+		scrubbed = scrubber.NewWriter(transcript)
+		out = io.MultiWriter(out, scrubbed)
+	}
+
+	group := newSessionGroup(cmd)
+	master, err := pty.Start(cmd, size)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer master.Close()
+	group.started()
+
+	answerer := &autoAnswerer{answers: opts.AutoAnswers, terminal: master}
+	if transcript != nil {
+		answerer.record = func(input string) {
+			// Output held back by the scrubber came before the answer.
+			_ = scrubbed.Flush()
+			transcript.Input(input)
+		}
+	}
+	copied := make(chan struct{})
+	go func() {
+		defer close(copied)
+		// Reading fails with EIO once every process holding the terminal
+		// has exited.
+		_, _ = io.Copy(io.MultiWriter(out, answerer), master)
+	}()
+
+	if interactive {
+		if state, err := term.MakeRaw(stdin); err == nil {
+			defer func() { _ = term.Restore(stdin, state) }()
+		}
+		stop := pty.NotifyResize(func() {
+			s, err := pty.GetSize(os.Stdin)
+			if err != nil || pty.SetSize(master, s) != nil {
+				return
+			}
+			if transcript != nil {
+				transcript.Resize(int(s.Cols), int(s.Rows))
+			}
+		})
+		defer stop()
+		// Typed input is not recorded: it may be a password entered at a
+		// prompt that does not echo.
+		go func() { _, _ = io.Copy(master, os.Stdin) }()
+	}
+
+	err = cmd.Wait()
+	select {
+	case <-copied:
+	case <-time.After(ptyDrainTimeout):
+	}
+	master.Close()
+	<-copied
+	group.done()
+	if scrubbed != nil {
+		_ = scrubbed.Close()
+		redactions += scrubbed.Count()
+	}
+	return answerer.given(), redactions, err
+}
+
+// autoAnswerer watches a command's output and types the answer of the first
+// auto-answer whose pattern matches what was printed since the last answer.
+type autoAnswerer struct {
+	answers  []AutoAnswer
+	terminal io.Writer
+	record   func(input string)
+
+	mu     sync.Mutex
+	recent []byte
+	log    []string
+}
+
+var ansiEscape = regexp.MustCompile(`\x1b(\[[0-9;?]*[ -/]*[@-~]|\][^\x07\x1b]*(\x07|\x1b\\)|[()][0-9A-Za-z])`)
+
+func (a *autoAnswerer) Write(p []byte) (int, error) {
+	if len(a.answers) == 0 {
+		return len(p), nil
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.recent = append(a.recent, p...)
+	if len(a.recent) > autoAnswerWindow {
+		a.recent = a.recent[len(a.recent)-autoAnswerWindow:]
+	}
+	text := ansiEscape.ReplaceAll(a.recent, nil)
+	for _, aa := range a.answers {
+		loc := aa.Pattern.FindIndex(text)
+		if loc == nil {
+			continue
+		}
+		a.recent = a.recent[:0]
+		input := aa.Answer + "\r"
+		if a.record != nil {
+			a.record(input)
+		}
+		a.log = append(a.log, fmt.Sprintf("%s -> %s", strings.TrimSpace(string(text[loc[0]:loc[1]])), aa.Answer))
+		_, _ = io.WriteString(a.terminal, input)
+		break
+	}
+	return len(p), nil
+}
+
+func (a *autoAnswerer) given() []string {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.log
+}
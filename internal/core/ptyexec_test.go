@@ -0,0 +1,186 @@
+package core
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Dicklesworthstone/slb/internal/db"
+	"github.com/Dicklesworthstone/slb/internal/pty"
+	"github.com/Dicklesworthstone/slb/internal/testutil"
+)
+
+func TestRunPTYCommand_AttachesTerminal(t *testing.T) {
+	if err := pty.Available(); err != nil {
+		t.Skipf("pty unavailable: %v", err)
+	}
+	spec := &db.CommandSpec{Raw: `if [ -t 0 ] && [ -t 1 ]; then echo "tty $(stty size)"; else echo notty; fi`, Shell: true}
+	logPath := filepath.Join(t.TempDir(), "run.log")
+
+	result, err := RunPTYCommand(context.Background(), spec, nil, PTYOptions{Size: pty.Winsize{Rows: 30, Cols: 100}}, logPath, nil)
+	if err != nil {
+		t.Fatalf("RunPTYCommand: %v", err)
+	}
+	if result.ExitCode != 0 || !strings.Contains(result.Output, "tty 30 100") {
+		t.Fatalf("exit %d, output %q", result.ExitCode, result.Output)
+	}
+
+	tr, err := ReadTranscript(TranscriptPath(logPath))
+	if err != nil {
+		t.Fatalf("ReadTranscript: %v", err)
+	}
+	if tr.Header.Width != 100 || tr.Header.Height != 30 || tr.Header.Command != spec.Raw {
+		t.Errorf("unexpected header: %+v", tr.Header)
+	}
+	var out strings.Builder
+	if err := ReplayTranscript(context.Background(), &out, tr, 0, 0); err != nil {
+		t.Fatal(err)
+	}
+	if out.String() != result.Output {
+		t.Errorf("transcript output %q, command output %q", out.String(), result.Output)
+	}
+}
+
+func TestRunPTYCommand_AutoAnswers(t *testing.T) {
+	if err := pty.Available(); err != nil {
+		t.Skipf("pty unavailable: %v", err)
+	}
+	answer, err := NewAutoAnswer(`Enter a value:\s*$`, "yes")
+	if err != nil {
+		t.Fatal(err)
+	}
+	spec := &db.CommandSpec{Raw: `printf '\033[1mEnter a value:\033[0m '; read v; echo "got=$v"`, Shell: true}
+	logPath := filepath.Join(t.TempDir(), "run.log")
+
+	result, err := RunPTYCommand(context.Background(), spec, nil, PTYOptions{AutoAnswers: []AutoAnswer{answer}}, logPath, nil)
+	if err != nil {
+		t.Fatalf("RunPTYCommand: %v", err)
+	}
+	if !strings.Contains(result.Output, "got=yes") {
+		t.Fatalf("prompt not answered, output %q", result.Output)
+	}
+	if len(result.AutoAnswers) != 1 || result.AutoAnswers[0] != "Enter a value: -> yes" {
+		t.Errorf("AutoAnswers = %q", result.AutoAnswers)
+	}
+
+	logData, err := os.ReadFile(logPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(logData), "PTY: yes") || !strings.Contains(string(logData), "=== Auto-Answers ===") {
+		t.Errorf("log missing PTY sections:\n%s", logData)
+	}
+
+	tr, err := ReadTranscript(TranscriptPath(logPath))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var inputs []string
+	for _, ev := range tr.Events {
+		if ev.Type == TranscriptInput {
+			inputs = append(inputs, ev.Data)
+		}
+	}
+	if len(inputs) != 1 || inputs[0] != "yes\r" {
+		t.Errorf("recorded input %q", inputs)
+	}
+}
+
+func TestNewAutoAnswer_InvalidPattern(t *testing.T) {
+	if _, err := NewAutoAnswer("(", "y"); err == nil {
+		t.Fatal("expected error for invalid pattern")
+	}
+}
+
+func TestExecutor_PTY(t *testing.T) {
+	if err := pty.Available(); err != nil {
+		t.Skipf("pty unavailable: %v", err)
+	}
+	database := testutil.NewTestDB(t)
+	sess := testutil.MakeSession(t, database)
+
+	cmd := db.CommandSpec{Raw: `test -t 1 && echo interactive`, Cwd: t.TempDir(), Shell: true}
+	cmd.Hash = db.ComputeCommandHash(cmd)
+	request := &db.Request{
+		ProjectPath:        sess.ProjectPath,
+		RequestorSessionID: sess.ID,
+		RequestorAgent:     sess.AgentName,
+		RequestorModel:     sess.Model,
+		RiskTier:           db.RiskTierDangerous,
+		Command:            cmd,
+		Justification:      db.Justification{Reason: "test"},
+		Status:             db.StatusApproved,
+		MinApprovals:       1,
+	}
+	if err := database.CreateRequest(request); err != nil {
+		t.Fatalf("CreateRequest: %v", err)
+	}
+
+	result, err := NewExecutor(database, nil).ExecuteApprovedRequest(context.Background(), ExecuteOptions{
+		RequestID:      request.ID,
+		SessionID:      sess.ID,
+		LogDir:         t.TempDir(),
+		SuppressOutput: true,
+		PTY:            true,
+	})
+	if err != nil {
+		t.Fatalf("ExecuteApprovedRequest: %v", err)
+	}
+	if result.ExitCode != 0 || !strings.Contains(result.Output, "interactive") {
+		t.Fatalf("exit %d, output %q", result.ExitCode, result.Output)
+	}
+	if result.TranscriptPath != TranscriptPath(result.LogPath) {
+		t.Errorf("TranscriptPath = %q", result.TranscriptPath)
+	}
+	if _, err := os.Stat(result.TranscriptPath); err != nil {
+		t.Errorf("transcript not written: %v", err)
+	}
+}
+
+func TestExecutor_PTYUsesRecordedAutoAnswers(t *testing.T) {
+	if err := pty.Available(); err != nil {
+		t.Skipf("pty unavailable: %v", err)
+	}
+	database := testutil.NewTestDB(t)
+	sess := testutil.MakeSession(t, database)
+
+	cmd := db.CommandSpec{Raw: `printf 'Enter a value: '; read v; echo "got=$v"`, Cwd: t.TempDir(), Shell: true}
+	cmd.Hash = db.ComputeCommandHash(cmd)
+	request := &db.Request{
+		ProjectPath:        sess.ProjectPath,
+		RequestorSessionID: sess.ID,
+		RequestorAgent:     sess.AgentName,
+		RequestorModel:     sess.Model,
+		RiskTier:           db.RiskTierDangerous,
+		Command:            cmd,
+		Justification:      db.Justification{Reason: "test"},
+		AutoAnswers:        []db.AutoAnswer{{Pattern: `Enter a value:\s*$`, Answer: "yes"}},
+		Status:             db.StatusApproved,
+		MinApprovals:       1,
+	}
+	if err := database.CreateRequest(request); err != nil {
+		t.Fatalf("CreateRequest: %v", err)
+	}
+
+	// Answers configured at execution time are not the ones reviewers saw.
+	injected, err := NewAutoAnswer(`Enter a value:\s*$`, "injected")
+	if err != nil {
+		t.Fatal(err)
+	}
+	result, err := NewExecutor(database, nil).ExecuteApprovedRequest(context.Background(), ExecuteOptions{
+		RequestID:      request.ID,
+		SessionID:      sess.ID,
+		LogDir:         t.TempDir(),
+		SuppressOutput: true,
+		PTY:            true,
+		PTYOptions:     PTYOptions{AutoAnswers: []AutoAnswer{injected}},
+	})
+	if err != nil {
+		t.Fatalf("ExecuteApprovedRequest: %v", err)
+	}
+	if !strings.Contains(result.Output, "got=yes") {
+		t.Errorf("output %q, want the recorded answer", result.Output)
+	}
+}
@@ -0,0 +1,201 @@
+// Package core implements agent reputation scoring.
+package core
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Dicklesworthstone/slb/internal/db"
+)
+
+// ReputationLevel buckets a reputation score.
+type ReputationLevel string
+
+const (
+	// ReputationUnknown means there is too little history to judge.
+	ReputationUnknown ReputationLevel = "unknown"
+	// ReputationLow is below the configured low-score threshold.
+	ReputationLow ReputationLevel = "low"
+	// ReputationNormal is an ordinary track record.
+	ReputationNormal ReputationLevel = "normal"
+	// ReputationHigh is a clean track record.
+	ReputationHigh ReputationLevel = "high"
+)
+
+// Penalty weights per request, relative to a clean request weighing 1.
+const (
+	reputationRejectPenalty  = 1.0
+	reputationFailurePenalty = 0.5
+	reputationProblemPenalty = 2.0
+	// reputationPrior is the weight of clean history every subject starts
+	// with, so one early rejection does not sink a new agent.
+	reputationPrior = 1.0
+	// reputationHighScore is the score at and above which a record counts
+	// as high.
+	reputationHighScore = 90.0
+)
+
+// ReputationConfig controls reputation scoring and the policy applied to
+// low-scoring agents.
+type ReputationConfig struct {
+	// Enabled applies the low-score policy when requests are created.
+	// Scores are computed for display either way.
+	Enabled bool
+	// HalfLife is how long it takes for a request's weight to halve.
+	HalfLife time.Duration
+	// MinSamples is the number of resolved requests needed for a score to
+	// leave the unknown level.
+	MinSamples int
+	// LowScore is the score below which an agent is low.
+	LowScore float64
+	// LowExtraApprovals is added to min approvals for low agents.
+	LowExtraApprovals int
+	// LowRequireDifferentModel forces different-model review for low agents.
+	LowRequireDifferentModel bool
+}
+
+// DefaultReputationConfig returns the default reputation configuration.
+func DefaultReputationConfig() ReputationConfig {
+	return ReputationConfig{
+		Enabled:                  false,
+		HalfLife:                 30 * 24 * time.Hour,
+		MinSamples:               5,
+		LowScore:                 60,
+		LowExtraApprovals:        1,
+		LowRequireDifferentModel: true,
+	}
+}
+
+// ReasonCount counts a rejection reason.
+type ReasonCount struct {
+	Reason string `json:"reason"`
+	Count  int    `json:"count"`
+}
+
+// Reputation is the decayed track record of an agent or model.
+type Reputation struct {
+	// Subject is the agent name or model; Kind says which.
+	Subject string          `json:"subject"`
+	Kind    string          `json:"kind"`
+	Score   float64         `json:"score"`
+	Level   ReputationLevel `json:"level"`
+	// Samples is the number of resolved requests considered.
+	Samples int `json:"samples"`
+	// The weighted counts below are decayed by age.
+	Weight   float64 `json:"weight"`
+	Approved float64 `json:"approved"`
+	Rejected float64 `json:"rejected"`
+	Failed   float64 `json:"failed"`
+	Problems float64 `json:"problems"`
+	// RejectionReasons are the most common rejecting review comments.
+	RejectionReasons []ReasonCount `json:"rejection_reasons,omitempty"`
+}
+
+// IsLow reports whether the low-score policy applies.
+func (r *Reputation) IsLow() bool {
+	return r != nil && r.Level == ReputationLow
+}
+
+// AgentReputation scores an agent from its resolved requests.
+func AgentReputation(database *db.DB, agent string, cfg ReputationConfig, now time.Time) (*Reputation, error) {
+	samples, err := database.ListReputationSamples(agent, "", reputationSince(cfg, now))
+	if err != nil {
+		return nil, err
+	}
+	return ScoreReputation(agent, "agent", samples, cfg, now), nil
+}
+
+// ModelReputation scores a requestor model across all agents using it.
+func ModelReputation(database *db.DB, model string, cfg ReputationConfig, now time.Time) (*Reputation, error) {
+	samples, err := database.ListReputationSamples("", model, reputationSince(cfg, now))
+	if err != nil {
+		return nil, err
+	}
+	return ScoreReputation(model, "model", samples, cfg, now), nil
+}
+
+// reputationSince bounds the history read: after eight half-lives a
+// request weighs under 0.4% and no longer matters.
+func reputationSince(cfg ReputationConfig, now time.Time) time.Time {
+	return now.Add(-8 * halfLife(cfg))
+}
+
+func halfLife(cfg ReputationConfig) time.Duration {
+	if cfg.HalfLife <= 0 {
+		return DefaultReputationConfig().HalfLife
+	}
+	return cfg.HalfLife
+}
+
+// ScoreReputation computes a reputation from samples. Each request weighs
+// 0.5^(age/half-life); rejections, failed executions and outcomes that
+// caused problems subtract from a perfect 100 in proportion to their weight.
+func ScoreReputation(subject, kind string, samples []db.ReputationSample, cfg ReputationConfig, now time.Time) *Reputation {
+	rep := &Reputation{Subject: subject, Kind: kind, Samples: len(samples)}
+	hl := halfLife(cfg).Hours()
+	reasons := map[string]*ReasonCount{}
+
+	for _, s := range samples {
+		age := now.Sub(s.CreatedAt).Hours()
+		if age < 0 {
+			age = 0
+		}
+		w := math.Pow(0.5, age/hl)
+		rep.Weight += w
+
+		if s.Status == db.StatusRejected {
+			rep.Rejected += w
+		} else {
+			rep.Approved += w
+		}
+		if s.Status == db.StatusExecutionFailed || (s.ExitCode != nil && *s.ExitCode != 0) {
+			rep.Failed += w
+		}
+		if s.CausedProblems {
+			rep.Problems += w
+		}
+		for _, c := range s.RejectionComments {
+			c = strings.TrimSpace(c)
+			key := strings.ToLower(c)
+			if key == "" {
+				continue
+			}
+			if reasons[key] == nil {
+				reasons[key] = &ReasonCount{Reason: c}
+			}
+			reasons[key].Count++
+		}
+	}
+
+	penalty := rep.Rejected*reputationRejectPenalty + rep.Failed*reputationFailurePenalty + rep.Problems*reputationProblemPenalty
+	rep.Score = 100 * math.Max(0, 1-penalty/(rep.Weight+reputationPrior))
+	rep.Score = math.Round(rep.Score*10) / 10
+
+	switch {
+	case rep.Samples < cfg.MinSamples:
+		rep.Level = ReputationUnknown
+	case rep.Score < cfg.LowScore:
+		rep.Level = ReputationLow
+	case rep.Score >= reputationHighScore:
+		rep.Level = ReputationHigh
+	default:
+		rep.Level = ReputationNormal
+	}
+
+	for _, rc := range reasons {
+		rep.RejectionReasons = append(rep.RejectionReasons, *rc)
+	}
+	sort.Slice(rep.RejectionReasons, func(i, j int) bool {
+		a, b := rep.RejectionReasons[i], rep.RejectionReasons[j]
+		if a.Count != b.Count {
+			return a.Count > b.Count
+		}
+		return a.Reason < b.Reason
+	})
+	if len(rep.RejectionReasons) > 5 {
+		rep.RejectionReasons = rep.RejectionReasons[:5]
+	}
+	return rep
+}
@@ -0,0 +1,168 @@
+package core
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Dicklesworthstone/slb/internal/db"
+	"github.com/Dicklesworthstone/slb/internal/testutil"
+)
+
+func TestScoreReputation(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	cfg := DefaultReputationConfig()
+	cfg.HalfLife = 10 * 24 * time.Hour
+	cfg.MinSamples = 3
+	exit1 := 1
+
+	sample := func(status db.RequestStatus, age time.Duration) db.ReputationSample {
+		return db.ReputationSample{Status: status, CreatedAt: now.Add(-age)}
+	}
+
+	t.Run("clean history is high", func(t *testing.T) {
+		samples := []db.ReputationSample{
+			sample(db.StatusExecuted, time.Hour),
+			sample(db.StatusExecuted, 2*time.Hour),
+			sample(db.StatusApproved, 3*time.Hour),
+		}
+		rep := ScoreReputation("BlueLake", "agent", samples, cfg, now)
+		if rep.Score != 100 || rep.Level != ReputationHigh || rep.Samples != 3 {
+			t.Errorf("got score=%v level=%s samples=%d", rep.Score, rep.Level, rep.Samples)
+		}
+	})
+
+	t.Run("too few samples is unknown", func(t *testing.T) {
+		rep := ScoreReputation("BlueLake", "agent", []db.ReputationSample{sample(db.StatusRejected, 0)}, cfg, now)
+		if rep.Level != ReputationUnknown || rep.IsLow() {
+			t.Errorf("level = %s", rep.Level)
+		}
+		// One rejection against the prior: 1 - 1/(1+1).
+		if rep.Score != 50 {
+			t.Errorf("score = %v, want 50", rep.Score)
+		}
+	})
+
+	t.Run("recent problems are low", func(t *testing.T) {
+		problem := sample(db.StatusExecuted, time.Hour)
+		problem.CausedProblems = true
+		failed := sample(db.StatusExecutionFailed, time.Hour)
+		failed.ExitCode = &exit1
+		rejected := sample(db.StatusRejected, time.Hour)
+		rejected.RejectionComments = []string{"No rollback plan", "no rollback plan "}
+		other := sample(db.StatusRejected, 2*time.Hour)
+		other.RejectionComments = []string{"wrong cluster"}
+
+		rep := ScoreReputation("BlueLake", "agent", []db.ReputationSample{problem, failed, rejected, other}, cfg, now)
+		if !rep.IsLow() {
+			t.Errorf("expected low, got score=%v level=%s", rep.Score, rep.Level)
+		}
+		if len(rep.RejectionReasons) != 2 || rep.RejectionReasons[0].Reason != "No rollback plan" || rep.RejectionReasons[0].Count != 2 {
+			t.Errorf("RejectionReasons = %+v", rep.RejectionReasons)
+		}
+	})
+
+	t.Run("old problems decay", func(t *testing.T) {
+		var samples []db.ReputationSample
+		for i := 0; i < 3; i++ {
+			s := sample(db.StatusExecuted, 60*24*time.Hour)
+			s.CausedProblems = true
+			samples = append(samples, s)
+		}
+		for i := 0; i < 3; i++ {
+			samples = append(samples, sample(db.StatusExecuted, time.Hour))
+		}
+		rep := ScoreReputation("BlueLake", "agent", samples, cfg, now)
+		if rep.Score < 90 {
+			t.Errorf("problems six half-lives ago should barely count, score = %v", rep.Score)
+		}
+
+		cfg := cfg
+		cfg.HalfLife = 365 * 24 * time.Hour
+		if slow := ScoreReputation("BlueLake", "agent", samples, cfg, now); slow.Score >= rep.Score {
+			t.Errorf("longer half-life should keep old problems: %v >= %v", slow.Score, rep.Score)
+		}
+	})
+}
+
+func TestAgentReputation(t *testing.T) {
+	database := testutil.NewTestDB(t)
+	sess := testutil.MakeSession(t, database, testutil.WithAgent("BlueLake"), testutil.WithModel("opus"))
+	other := testutil.MakeSession(t, database, testutil.WithAgent("RedRiver"), testutil.WithModel("opus"))
+
+	bad := testutil.MakeRequest(t, database, sess, testutil.WithStatus(db.StatusExecuted))
+	if err := database.CreateOutcome(&db.ExecutionOutcome{RequestID: bad.ID, CausedProblems: true}); err != nil {
+		t.Fatalf("CreateOutcome: %v", err)
+	}
+	testutil.MakeRequest(t, database, sess, testutil.WithStatus(db.StatusRejected))
+	testutil.MakeRequest(t, database, sess, testutil.WithStatus(db.StatusPending)) // not resolved
+	testutil.MakeRequest(t, database, other, testutil.WithStatus(db.StatusExecuted))
+
+	cfg := DefaultReputationConfig()
+	cfg.MinSamples = 2
+	rep, err := AgentReputation(database, "bluelake", cfg, time.Now())
+	if err != nil {
+		t.Fatalf("AgentReputation: %v", err)
+	}
+	if rep.Samples != 2 || rep.Problems == 0 || rep.Rejected == 0 || !rep.IsLow() {
+		t.Errorf("agent reputation = %+v", rep)
+	}
+
+	// A request nobody reviewed says nothing about its requestor.
+	testutil.MakeRequest(t, database, sess, testutil.WithStatus(db.StatusTimedOut))
+	after, err := AgentReputation(database, "bluelake", cfg, time.Now())
+	if err != nil {
+		t.Fatalf("AgentReputation after timeout: %v", err)
+	}
+	if after.Samples != rep.Samples || after.Score != rep.Score {
+		t.Errorf("timeout changed reputation: before %+v, after %+v", rep, after)
+	}
+
+	model, err := ModelReputation(database, "opus", cfg, time.Now())
+	if err != nil {
+		t.Fatalf("ModelReputation: %v", err)
+	}
+	if model.Samples != 3 || model.Kind != "model" {
+		t.Errorf("model reputation = %+v", model)
+	}
+}
+
+func TestCreateRequest_LowReputationTightensReview(t *testing.T) {
+	database := testutil.NewTestDB(t)
+	sess := testutil.MakeSession(t, database, testutil.WithAgent("BlueLake"), testutil.WithModel("opus"))
+	for i := 0; i < 3; i++ {
+		testutil.MakeRequest(t, database, sess, testutil.WithStatus(db.StatusRejected))
+	}
+
+	create := func(enabled bool) *CreateRequestResult {
+		t.Helper()
+		config := DefaultRequestCreatorConfig()
+		config.AgentMailEnabled = false
+		config.Reputation.Enabled = enabled
+		config.Reputation.MinSamples = 3
+		creator := NewRequestCreator(database, nil, nil, config)
+		result, err := creator.CreateRequest(CreateRequestOptions{
+			SessionID: sess.ID,
+			Command:   "rm -rf ./build",
+		})
+		if err != nil {
+			t.Fatalf("CreateRequest: %v", err)
+		}
+		return result
+	}
+
+	plain := create(false)
+	if plain.Reputation != nil {
+		t.Errorf("policy disabled but reputation applied: %+v", plain.Reputation)
+	}
+
+	tightened := create(true)
+	if tightened.Reputation == nil || !tightened.Reputation.IsLow() {
+		t.Fatalf("expected low reputation, got %+v", tightened.Reputation)
+	}
+	if tightened.Request.MinApprovals != plain.Request.MinApprovals+1 {
+		t.Errorf("MinApprovals = %d, want %d", tightened.Request.MinApprovals, plain.Request.MinApprovals+1)
+	}
+	if !tightened.Request.RequireDifferentModel {
+		t.Error("expected RequireDifferentModel for a low-reputation agent")
+	}
+}
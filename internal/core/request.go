@@ -8,8 +8,10 @@ import (
 	"strings"
 	"time"
 
+	"github.com/Dicklesworthstone/slb/internal/config"
 	"github.com/Dicklesworthstone/slb/internal/db"
 	"github.com/Dicklesworthstone/slb/internal/integrations"
+	"github.com/Dicklesworthstone/slb/internal/policy"
 	shellwords "github.com/mattn/go-shellwords"
 )
 
@@ -31,6 +33,23 @@ type CreateRequestOptions struct {
 	RedactPatterns []string
 	// ProjectPath overrides the project path (defaults to session's project).
 	ProjectPath string
+	// Environment is the execution environment fingerprint. When nil and
+	// environment binding is enabled it is captured from the current process.
+	Environment db.EnvironmentFingerprint
+	// Sandbox requests sandboxed execution with the given restrictions.
+	Sandbox *db.SandboxSpec
+	// Plan makes this a multi-step plan request. Each step is classified on
+	// its own and the plan is reviewed at the highest tier; Command and Cwd
+	// default to PlanSummary and the first step's working directory.
+	Plan *db.Plan
+	// Window limits when the approved request may execute. Reviewers can
+	// approve ahead of time: the request stays pending until the window
+	// first opens plus the usual request timeout, and the approval is valid
+	// for the approval TTL from that opening.
+	Window *db.ExecutionWindow
+	// AutoAnswers are recorded on the request, shown to reviewers and typed
+	// into matching prompts when it executes with --pty.
+	AutoAnswers []db.AutoAnswer
 }
 
 // CreateRequestResult holds the result of creating a request.
@@ -43,6 +62,11 @@ type CreateRequestResult struct {
 	SkipReason string
 	// Classification is the risk classification result.
 	Classification *MatchResult
+	// Reputation is the requestor's low reputation that tightened review,
+	// or nil when the policy did not apply.
+	Reputation *Reputation
+	// Policy is the outcome of the policy rules (nil when skipped).
+	Policy *policy.Decision
 }
 
 // Request creation errors.
@@ -88,6 +112,22 @@ type RequestCreatorConfig struct {
 	AgentMailThread string
 	// AgentMailSender optional sender name.
 	AgentMailSender string
+	// Reputation raises review requirements for low-scoring agents.
+	Reputation ReputationConfig
+	// BindEnvironment records an environment fingerprint on new requests.
+	BindEnvironment bool
+	// EnvironmentVars are extra variables included in the fingerprint.
+	EnvironmentVars []string
+	// Schedule holds the change windows requests may name and the freeze
+	// periods that refuse requests.
+	Schedule *ChangeSchedule
+	// Policy sets required approvals, reviewer and executor constraints,
+	// or denies requests. Nil uses the default policy of PolicyDefaults and
+	// BlockedAgents.
+	Policy *policy.Policy
+	// PolicyDefaults are the configuration keys the default policy is
+	// compiled from. Its Blocked list is replaced by BlockedAgents.
+	PolicyDefaults policy.Defaults
 }
 
 // DefaultRequestCreatorConfig returns the default configuration.
@@ -102,6 +142,9 @@ func DefaultRequestCreatorConfig() *RequestCreatorConfig {
 		AgentMailEnabled:           true,
 		AgentMailThread:            "SLB-Reviews",
 		AgentMailSender:            "SLB-System",
+		Reputation:                 DefaultReputationConfig(),
+		BindEnvironment:            true,
+		PolicyDefaults:             policy.DefaultsFromConfig(config.DefaultConfig()),
 	}
 }
 
@@ -125,15 +168,37 @@ func NewRequestCreator(database *db.DB, rateLimiter *RateLimiter, patternEngine
 	}
 }
 
+// WithNotifier sets the notifier used for new-request events.
+func (rc *RequestCreator) WithNotifier(n integrations.RequestNotifier) *RequestCreator {
+	if n != nil {
+		rc.notifier = n
+	}
+	return rc
+}
+
 // CreateRequest creates a new command approval request with full validation.
 func (rc *RequestCreator) CreateRequest(opts CreateRequestOptions) (*CreateRequestResult, error) {
 	// Validate required fields
 	if opts.SessionID == "" {
 		return nil, ErrSessionRequired
 	}
+	if opts.Plan != nil {
+		if len(opts.Plan.Steps) == 0 {
+			return nil, ErrPlanEmpty
+		}
+		if opts.Command == "" {
+			opts.Command = PlanSummary(opts.Plan)
+		}
+		if opts.Cwd == "" {
+			opts.Cwd = opts.Plan.Steps[0].Command.Cwd
+		}
+	}
 	if opts.Command == "" {
 		return nil, ErrCommandRequired
 	}
+	if _, err := CompileAutoAnswers(opts.AutoAnswers); err != nil {
+		return nil, err
+	}
 
 	// Step 1: Validate session exists and is active
 	session, err := rc.db.GetSession(opts.SessionID)
@@ -150,7 +215,10 @@ func (rc *RequestCreator) CreateRequest(opts CreateRequestOptions) (*CreateReque
 	// Initialize notifier with project context if enabled.
 	notifier := rc.notifier
 	if rc.config != nil && rc.config.AgentMailEnabled {
-		notifier = integrations.NewAgentMailClient(session.ProjectPath, rc.config.AgentMailThread, rc.config.AgentMailSender)
+		notifier = integrations.MultiNotifier{
+			notifier,
+			integrations.NewAgentMailClient(session.ProjectPath, rc.config.AgentMailThread, rc.config.AgentMailSender),
+		}
 	}
 
 	// Step 2: Check agent not blocked
@@ -169,8 +237,17 @@ func (rc *RequestCreator) CreateRequest(opts CreateRequestOptions) (*CreateReque
 		return nil, fmt.Errorf("rate limit exceeded (action=%s): %s", limitResult.Action, limitResult.Message)
 	}
 
-	// Step 4: Classify command
-	classification := rc.patternEngine.ClassifyCommand(opts.Command, opts.Cwd)
+	// Step 4: Classify command (each step of a plan)
+	var classification *MatchResult
+	if opts.Plan != nil {
+		var steps []*MatchResult
+		classification, steps = ClassifyPlan(rc.patternEngine, opts.Plan)
+		for i, c := range steps {
+			opts.Plan.Steps[i].RiskTier = c.Tier
+		}
+	} else {
+		classification = rc.patternEngine.ClassifyCommand(opts.Command, opts.Cwd)
+	}
 
 	// Step 5: If SAFE, skip
 	if classification.IsSafe {
@@ -192,8 +269,11 @@ func (rc *RequestCreator) CreateRequest(opts CreateRequestOptions) (*CreateReque
 		}, nil
 	}
 
-	// Step 6: Parse command to argv
-	argv, _ := ParseCommandToArgv(opts.Command)
+	// Step 6: Parse command to argv (a plan's summary is never run)
+	var argv []string
+	if opts.Plan == nil {
+		argv, _ = ParseCommandToArgv(opts.Command)
+	}
 
 	// Step 7: Build command spec (hash computed by db.CreateRequest)
 	cmdSpec := db.CommandSpec{
@@ -206,40 +286,103 @@ func (rc *RequestCreator) CreateRequest(opts CreateRequestOptions) (*CreateReque
 	// Step 8: Apply redaction
 	cmdSpec.DisplayRedacted = ApplyRedaction(opts.Command, opts.RedactPatterns)
 	cmdSpec.ContainsSensitive = cmdSpec.DisplayRedacted != opts.Command
+	if opts.Plan != nil {
+		for i := range opts.Plan.Steps {
+			step := &opts.Plan.Steps[i].Command
+			step.DisplayRedacted = ApplyRedaction(step.Raw, opts.RedactPatterns)
+			step.ContainsSensitive = step.DisplayRedacted != step.Raw
+		}
+	}
 
-	// Step 9: Get min approvals (with dynamic quorum check)
-	minApprovals := classification.MinApprovals
+	// Determine project path
+	projectPath := opts.ProjectPath
+	if projectPath == "" {
+		projectPath = session.ProjectPath
+	}
+	now := time.Now().UTC()
+
+	environment := opts.Environment
+	if environment == nil && rc.config.BindEnvironment {
+		environment = CaptureEnvironment(opts.Cwd, rc.config.EnvironmentVars)
+	}
+
+	// Step 9: Evaluate the policy for min approvals (with dynamic quorum
+	// check) and reviewer and executor constraints
+	decision, err := rc.evaluatePolicy(opts, session, classification, projectPath, environment, now)
+	if err != nil {
+		return nil, err
+	}
+	if err := decision.Err(); err != nil {
+		return nil, err
+	}
+	minApprovals := decision.MinApprovals
 	if rc.config.DynamicQuorumEnabled {
 		minApprovals = rc.checkDynamicQuorum(classification.Tier, minApprovals, opts.ProjectPath)
 	}
 
 	// Step 10: Set expiry times
-	now := time.Now().UTC()
-	requestExpiry := now.Add(time.Duration(rc.config.RequestTimeoutMinutes) * time.Minute)
 
-	// Determine project path
-	projectPath := opts.ProjectPath
-	if projectPath == "" {
-		projectPath = session.ProjectPath
+	// Step 10b: Tighten review for agents or models with a low reputation
+	lowReputation := rc.lowReputation(session, now)
+	if lowReputation != nil {
+		minApprovals += rc.config.Reputation.LowExtraApprovals
+	}
+
+	// Step 10c: The request must be able to execute: its window opens and
+	// no freeze period covers its tier when it does.
+	opening := now
+	if opts.Window != nil {
+		if err := rc.config.Schedule.ValidateWindow(opts.Window, now); err != nil {
+			return nil, err
+		}
+		opening, _ = rc.config.Schedule.NextOpening(opts.Window, now)
+	}
+	if err := rc.config.Schedule.CheckFreeze(classification.Tier, opening); err != nil {
+		return nil, err
+	}
+	requestExpiry := opening.UTC().Add(time.Duration(rc.config.RequestTimeoutMinutes) * time.Minute)
+
+	// An approval given ahead of a window is valid for the approval TTL
+	// from when the window first opens, not for as long as it recurs.
+	var approvalExpiry *time.Time
+	if opts.Window != nil {
+		expiry := opening.UTC().Add(rc.approvalTTL(classification.Tier))
+		approvalExpiry = &expiry
+	}
+
+	// Step 10d: A change freeze rejects the request unless its window opens
+	// after the freeze ends, which queues it until then.
+	if err := CheckChangeFreeze(rc.db, projectPath, classification.Tier, opening); err != nil {
+		if errors.Is(err, ErrChangeFreeze) {
+			return nil, fmt.Errorf("%w; give the request an execution window starting after the freeze to queue it", err)
+		}
+		return nil, err
 	}
 
 	// Step 11: Create request in DB
 	request := &db.Request{
-		ProjectPath:        projectPath,
-		Command:            cmdSpec,
-		RiskTier:           classification.Tier,
-		RequestorSessionID: opts.SessionID,
-		RequestorAgent:     session.AgentName,
-		RequestorModel:     session.Model,
-		Justification:      opts.Justification,
-		Attachments:        opts.Attachments,
-		Status:             db.StatusPending,
-		MinApprovals:       minApprovals,
-		ExpiresAt:          &requestExpiry,
-	}
-
-	// Set require_different_model based on tier
-	if classification.Tier == RiskTierCritical {
+		ProjectPath:           projectPath,
+		Command:               cmdSpec,
+		RiskTier:              classification.Tier,
+		RequestorSessionID:    opts.SessionID,
+		RequestorAgent:        session.AgentName,
+		RequestorModel:        session.Model,
+		Justification:         opts.Justification,
+		Attachments:           opts.Attachments,
+		Environment:           environment,
+		Sandbox:               opts.Sandbox,
+		Plan:                  opts.Plan,
+		Window:                opts.Window,
+		Policy:                decision.Record(),
+		AutoAnswers:           opts.AutoAnswers,
+		Status:                db.StatusPending,
+		MinApprovals:          minApprovals,
+		RequireDifferentModel: decision.RequireDifferentModel,
+		ExpiresAt:             &requestExpiry,
+		ApprovalExpiresAt:     approvalExpiry,
+	}
+
+	if lowReputation != nil && rc.config.Reputation.LowRequireDifferentModel {
 		request.RequireDifferentModel = true
 	}
 
@@ -247,6 +390,15 @@ func (rc *RequestCreator) CreateRequest(opts CreateRequestOptions) (*CreateReque
 		return nil, fmt.Errorf("creating request: %w", err)
 	}
 
+	redactions := map[string]int{}
+	for i, a := range request.Attachments {
+		redactions[AttachmentArtifact(i)] = attachmentRedactions(a)
+	}
+	if request.DryRun != nil {
+		redactions[ArtifactDryRun] = request.DryRun.Redactions
+	}
+	recordRedactions(rc.db, request.ID, redactions)
+
 	// Step 12: Notify via Agent Mail (best effort; errors ignored)
 	_ = notifier.NotifyNewRequest(request)
 
@@ -257,9 +409,43 @@ func (rc *RequestCreator) CreateRequest(opts CreateRequestOptions) (*CreateReque
 		Request:        request,
 		Skipped:        false,
 		Classification: classification,
+		Reputation:     lowReputation,
+		Policy:         decision,
 	}, nil
 }
 
+// lowReputation returns the session's agent or model reputation when the
+// reputation policy is enabled and it is low. Scoring errors leave review
+// requirements unchanged.
+func (rc *RequestCreator) lowReputation(session *db.Session, now time.Time) *Reputation {
+	cfg := rc.config.Reputation
+	if !cfg.Enabled {
+		return nil
+	}
+	if rep, err := AgentReputation(rc.db, session.AgentName, cfg, now); err == nil && rep.IsLow() {
+		return rep
+	}
+	if session.Model == "" {
+		return nil
+	}
+	if rep, err := ModelReputation(rc.db, session.Model, cfg, now); err == nil && rep.IsLow() {
+		return rep
+	}
+	return nil
+}
+
+// approvalTTL returns how long an approval of a request of tier stays valid.
+func (rc *RequestCreator) approvalTTL(tier RiskTier) time.Duration {
+	minutes, fallback := rc.config.ApprovalTTLMinutes, defaultApprovalTTL
+	if tier == RiskTierCritical {
+		minutes, fallback = rc.config.ApprovalTTLCriticalMinutes, defaultApprovalTTLCritical
+	}
+	if minutes <= 0 {
+		return fallback
+	}
+	return time.Duration(minutes) * time.Minute
+}
+
 // isAgentBlocked checks if an agent is in the blocked list.
 func (rc *RequestCreator) isAgentBlocked(agentName string) bool {
 	for _, blocked := range rc.config.BlockedAgents {
@@ -115,6 +115,35 @@ func TestCreateRequest_DangerousCommand_Created(t *testing.T) {
 	}
 }
 
+func TestCreateRequest_RecordsAutoAnswers(t *testing.T) {
+	database := testutil.NewTestDB(t)
+	session := testutil.MakeSession(t, database, testutil.SessionWithAgentName("agent1"))
+	creator := NewRequestCreator(database, nil, nil, nil)
+
+	opts := CreateRequestOptions{
+		SessionID:     session.ID,
+		Command:       "git reset --hard HEAD~3",
+		Justification: Justification{Reason: "drop the last commits"},
+		AutoAnswers:   []db.AutoAnswer{{Pattern: "(", Answer: "yes"}},
+	}
+	if _, err := creator.CreateRequest(opts); err == nil {
+		t.Fatal("expected an error for an invalid auto-answer pattern")
+	}
+
+	opts.AutoAnswers = []db.AutoAnswer{{Pattern: `Enter a value:\s*$`, Answer: "yes"}}
+	result, err := creator.CreateRequest(opts)
+	if err != nil {
+		t.Fatalf("CreateRequest: %v", err)
+	}
+	stored, err := database.GetRequest(result.Request.ID)
+	if err != nil {
+		t.Fatalf("GetRequest: %v", err)
+	}
+	if len(stored.AutoAnswers) != 1 || stored.AutoAnswers[0] != opts.AutoAnswers[0] {
+		t.Errorf("AutoAnswers = %+v", stored.AutoAnswers)
+	}
+}
+
 func TestCreateRequest_CriticalCommand_RequiresDifferentModel(t *testing.T) {
 	database := testutil.NewTestDB(t)
 	session := testutil.MakeSession(t, database, testutil.SessionWithAgentName("agent1"))
@@ -17,6 +17,7 @@ var (
 	ErrSelfReview         = errors.New("cannot review your own request")
 	ErrAlreadyReviewed    = errors.New("you have already reviewed this request")
 	ErrRequireDiffModel   = errors.New("different model required for approval")
+	ErrPolicyReviewer     = errors.New("policy does not allow this reviewer to approve")
 	ErrInvalidDecision    = errors.New("invalid decision (must be approve or reject)")
 	ErrMissingSessionKey  = errors.New("session key required for signature")
 	ErrSessionKeyMismatch = errors.New("session key does not match session")
@@ -48,6 +49,8 @@ type ReviewOptions struct {
 	Responses db.ReviewResponse
 	// Comments contains optional additional comments.
 	Comments string
+	// RequireSandbox makes an approval conditional on sandboxed execution.
+	RequireSandbox bool
 }
 
 // ReviewConfig provides configuration for the review process.
@@ -177,6 +180,13 @@ func (rs *ReviewService) SubmitReview(opts ReviewOptions) (*ReviewResult, error)
 		}
 	}
 
+	// Step 5b: Check the policy's reviewer constraints (for approvals only)
+	if opts.Decision == db.DecisionApprove {
+		if rule := request.Policy.AllowsReviewer(session.AgentName, session.Model); rule != "" {
+			return nil, fmt.Errorf("%w: rule %q", ErrPolicyReviewer, rule)
+		}
+	}
+
 	// Step 6: Generate signature
 	timestamp := time.Now().UTC()
 	signature := db.ComputeReviewSignature(opts.SessionKey, opts.RequestID, opts.Decision, timestamp)
@@ -191,6 +201,7 @@ func (rs *ReviewService) SubmitReview(opts ReviewOptions) (*ReviewResult, error)
 		SignatureTimestamp: timestamp,
 		Responses:          opts.Responses,
 		Comments:           opts.Comments,
+		RequireSandbox:     opts.RequireSandbox && opts.Decision == db.DecisionApprove,
 	}
 
 	result := &ReviewResult{
@@ -244,6 +255,10 @@ func (rs *ReviewService) SubmitReview(opts ReviewOptions) (*ReviewResult, error)
 		return nil, err
 	}
 
+	if result.RequestStatusChanged {
+		request.Status = result.NewRequestStatus
+	}
+
 	// Notify asynchronously (best effort)
 	switch opts.Decision {
 	case db.DecisionApprove:
@@ -309,7 +324,9 @@ func (rs *ReviewService) determineNewStatus(
 	return "" // No status change
 }
 
-// VerifyReview validates a review's signature.
+// VerifyReview validates a review's signature against the given key. For a
+// stored review, db.VerifyStoredReviewSignature picks the key it was signed
+// with, even after a rotation.
 func VerifyReview(review *db.Review, sessionKey string) bool {
 	return db.VerifyReviewSignature(
 		sessionKey,
@@ -0,0 +1,149 @@
+// Package core implements sandboxed execution policy.
+package core
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode"
+
+	"github.com/Dicklesworthstone/slb/internal/db"
+	"github.com/Dicklesworthstone/slb/internal/sandbox"
+	shellwords "github.com/mattn/go-shellwords"
+)
+
+// EffectiveSandbox returns the sandbox a request must execute in: the one
+// the requestor asked for, or defaults when an approving reviewer required
+// one. Returns nil when execution is not sandboxed.
+func EffectiveSandbox(request *db.Request, reviews []*db.Review, defaults db.SandboxSpec) *db.SandboxSpec {
+	if request.Sandbox != nil {
+		spec := *request.Sandbox
+		return &spec
+	}
+	for _, r := range reviews {
+		if r.Decision == db.DecisionApprove && r.RequireSandbox {
+			spec := defaults
+			return &spec
+		}
+	}
+	return nil
+}
+
+// SandboxPolicy turns a sandbox spec into the policy for running cmd:
+// writes are allowed beneath the spec's write paths and the paths the
+// command names.
+func SandboxPolicy(spec *db.SandboxSpec, cmd db.CommandSpec) sandbox.Policy {
+	p := sandbox.Policy{
+		DisableNetwork: spec.DisableNetwork,
+		CPUSeconds:     spec.CPUSeconds,
+		MemoryMB:       spec.MemoryMB,
+	}
+	seen := map[string]bool{}
+	add := func(path string) {
+		if path != "" && !seen[path] {
+			seen[path] = true
+			p.WritePaths = append(p.WritePaths, path)
+		}
+	}
+	for _, path := range spec.WritePaths {
+		add(resolveCommandPath(path, cmd.Cwd))
+	}
+	for _, path := range CommandWritePaths(cmd) {
+		add(path)
+	}
+	return p
+}
+
+// CommandWritePaths returns the absolute paths named in a command's
+// arguments: arguments that look like paths or exist relative to its
+// working directory. A path that does not exist yet is replaced by its
+// parent so that it can be created. The file system root is never
+// returned.
+func CommandWritePaths(cmd db.CommandSpec) []string {
+	commands := [][]string{cmd.Argv}
+	if cmd.Shell || len(cmd.Argv) == 0 {
+		commands = shellCommands(cmd.Raw)
+	}
+
+	var paths []string
+	for _, words := range commands {
+		// The first word is the program, not a path it writes to.
+		for i := 1; i < len(words); i++ {
+			arg := words[i]
+			if arg == "" || strings.HasPrefix(arg, "-") {
+				continue
+			}
+			looksLikePath := strings.ContainsRune(arg, '/') || strings.HasPrefix(arg, ".") || strings.HasPrefix(arg, "~")
+			path := resolveCommandPath(arg, cmd.Cwd)
+			if _, err := os.Lstat(path); err != nil {
+				if !looksLikePath {
+					continue
+				}
+				path = filepath.Dir(path)
+				if _, err := os.Stat(path); err != nil {
+					continue
+				}
+			}
+			if path != string(filepath.Separator) {
+				paths = append(paths, path)
+			}
+		}
+	}
+	return paths
+}
+
+// shellCommands splits a shell command line into the words of each simple
+// command. Redirection targets stay with the command they belong to.
+func shellCommands(raw string) [][]string {
+	var commands [][]string
+	var words []string
+	rest := []rune(raw)
+	for len(rest) > 0 {
+		parser := shellwords.NewParser()
+		args, err := parser.Parse(string(rest))
+		if err != nil {
+			break
+		}
+		words = append(words, args...)
+		if parser.Position < 0 {
+			break
+		}
+		rest = rest[parser.Position:]
+
+		// Consume the operator, including a redirected fd such as 2>&1.
+		n := 0
+		for n < len(rest) && unicode.IsDigit(rest[n]) {
+			n++
+		}
+		for n < len(rest) && strings.ContainsRune(";&|<>", rest[n]) {
+			n++
+		}
+		if n == 0 {
+			break
+		}
+		op := string(rest[:n])
+		rest = rest[n:]
+		if strings.ContainsAny(op, "<>") {
+			continue
+		}
+		commands = append(commands, words)
+		words = nil
+	}
+	if len(words) > 0 {
+		commands = append(commands, words)
+	}
+	return commands
+}
+
+// resolveCommandPath expands ~ and makes path absolute relative to cwd.
+func resolveCommandPath(path, cwd string) string {
+	if path == "~" || strings.HasPrefix(path, "~/") {
+		if home, err := os.UserHomeDir(); err == nil {
+			path = filepath.Join(home, strings.TrimPrefix(path, "~"))
+		}
+	}
+	if !filepath.IsAbs(path) && cwd != "" {
+		path = filepath.Join(cwd, path)
+	}
+	return filepath.Clean(path)
+}
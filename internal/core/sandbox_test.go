@@ -0,0 +1,142 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/Dicklesworthstone/slb/internal/db"
+	"github.com/Dicklesworthstone/slb/internal/sandbox"
+	"github.com/Dicklesworthstone/slb/internal/testutil"
+)
+
+func TestMain(m *testing.M) {
+	sandbox.MaybeRunHelper()
+	os.Exit(m.Run())
+}
+
+func TestEffectiveSandbox(t *testing.T) {
+	defaults := db.SandboxSpec{DisableNetwork: true, CPUSeconds: 30}
+	approve := &db.Review{Decision: db.DecisionApprove}
+	requireSandbox := &db.Review{Decision: db.DecisionApprove, RequireSandbox: true}
+
+	if got := EffectiveSandbox(&db.Request{}, []*db.Review{approve}, defaults); got != nil {
+		t.Errorf("no sandbox requested or required: got %+v", got)
+	}
+	if got := EffectiveSandbox(&db.Request{}, []*db.Review{approve, requireSandbox}, defaults); got == nil || !reflect.DeepEqual(*got, defaults) {
+		t.Errorf("reviewer required sandbox: got %+v, want defaults", got)
+	}
+	own := &db.SandboxSpec{MemoryMB: 512}
+	if got := EffectiveSandbox(&db.Request{Sandbox: own}, []*db.Review{requireSandbox}, defaults); got == nil || got.MemoryMB != 512 || got.DisableNetwork {
+		t.Errorf("requested sandbox: got %+v", got)
+	}
+}
+
+func TestCommandWritePaths(t *testing.T) {
+	cwd := t.TempDir()
+	if err := os.Mkdir(filepath.Join(cwd, "build"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(cwd, "notes.txt"), nil, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		cmd  db.CommandSpec
+		want []string
+	}{
+		{"existing dir", db.CommandSpec{Raw: "rm -rf build", Cwd: cwd, Shell: true}, []string{filepath.Join(cwd, "build")}},
+		{"new file under existing dir", db.CommandSpec{Raw: "cp notes.txt ./build/copy.txt", Cwd: cwd, Shell: true},
+			[]string{filepath.Join(cwd, "notes.txt"), filepath.Join(cwd, "build")}},
+		{"redirect", db.CommandSpec{Raw: "echo hi > ./out.log", Cwd: cwd, Shell: true}, []string{cwd}},
+		{"pipeline", db.CommandSpec{Raw: "make 2>&1 | tee build/make.log", Cwd: cwd, Shell: true}, []string{filepath.Join(cwd, "build")}},
+		{"words are not paths", db.CommandSpec{Raw: "git push origin main", Cwd: cwd, Shell: true}, nil},
+		{"root is never writable", db.CommandSpec{Raw: "rm -rf /nonexistent-slb-test", Cwd: cwd, Shell: true}, nil},
+		{"argv", db.CommandSpec{Argv: []string{"rm", "-f", "notes.txt"}, Cwd: cwd}, []string{filepath.Join(cwd, "notes.txt")}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := CommandWritePaths(tt.cmd); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("CommandWritePaths(%q) = %v, want %v", tt.cmd.Raw, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExecutor_SandboxRequiredByReviewer(t *testing.T) {
+	if err := sandbox.Available(); err != nil {
+		t.Skipf("sandbox unavailable: %v", err)
+	}
+	database := testutil.NewTestDB(t)
+	sess := testutil.MakeSession(t, database)
+	reviewer := testutil.MakeSession(t, database, testutil.WithAgent("Reviewer"))
+	cwd := t.TempDir()
+	outside := t.TempDir()
+	t.Setenv("SLB_TEST_OUTSIDE", outside)
+
+	cmd := db.CommandSpec{Raw: `touch ./inside.txt && touch "$SLB_TEST_OUTSIDE/outside.txt"`, Cwd: cwd, Shell: true}
+	cmd.Hash = db.ComputeCommandHash(cmd)
+	request := &db.Request{
+		ProjectPath:        sess.ProjectPath,
+		RequestorSessionID: sess.ID,
+		RequestorAgent:     sess.AgentName,
+		RequestorModel:     sess.Model,
+		RiskTier:           db.RiskTierDangerous,
+		Command:            cmd,
+		Justification:      db.Justification{Reason: "test"},
+		Status:             db.StatusApproved,
+		MinApprovals:       1,
+	}
+	if err := database.CreateRequest(request); err != nil {
+		t.Fatalf("CreateRequest: %v", err)
+	}
+	if err := database.CreateReview(&db.Review{
+		RequestID: request.ID, ReviewerSessionID: reviewer.ID, ReviewerAgent: reviewer.AgentName,
+		ReviewerModel: reviewer.Model, Decision: db.DecisionApprove, Signature: "sig", RequireSandbox: true,
+	}); err != nil {
+		t.Fatalf("CreateReview: %v", err)
+	}
+
+	result, err := NewExecutor(database, nil).ExecuteApprovedRequest(context.Background(), ExecuteOptions{
+		RequestID:      request.ID,
+		SessionID:      sess.ID,
+		LogDir:         t.TempDir(),
+		SuppressOutput: true,
+	})
+	if err != nil {
+		t.Fatalf("ExecuteApprovedRequest: %v", err)
+	}
+	if result.Sandbox == nil {
+		t.Fatal("expected the command to run sandboxed")
+	}
+	if _, err := os.Stat(filepath.Join(cwd, "inside.txt")); err != nil {
+		t.Errorf("write to the command's cwd was blocked: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(outside, "outside.txt")); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("write outside the sandbox succeeded (stat err %v)", err)
+	}
+	if result.ExitCode == 0 || len(result.SandboxViolations) == 0 {
+		t.Fatalf("exit code %d, violations %v", result.ExitCode, result.SandboxViolations)
+	}
+
+	logData, err := os.ReadFile(result.LogPath)
+	if err != nil {
+		t.Fatalf("reading log: %v", err)
+	}
+	if !strings.Contains(string(logData), "Sandbox:") || !strings.Contains(string(logData), "=== Sandbox Violations ===") {
+		t.Errorf("log missing sandbox sections:\n%s", logData)
+	}
+
+	outcome, err := database.GetOutcomeForRequest(request.ID)
+	if err != nil {
+		t.Fatalf("GetOutcomeForRequest: %v", err)
+	}
+	if outcome.Result != OutcomeSandboxViolation || !strings.Contains(outcome.Notes, "write") {
+		t.Errorf("outcome = %+v", outcome)
+	}
+}
@@ -0,0 +1,67 @@
+// Package core implements secret scrubbing of request artifacts.
+package core
+
+import (
+	"fmt"
+
+	"github.com/Dicklesworthstone/slb/internal/db"
+	"github.com/Dicklesworthstone/slb/internal/scrub"
+)
+
+// Artifacts whose scrubbed secrets are counted.
+const (
+	ArtifactExecutionLog = "execution_log"
+	ArtifactTranscript   = "transcript"
+	ArtifactDryRun       = "dry_run"
+)
+
+// AttachmentArtifact names the i-th attachment of a request.
+func AttachmentArtifact(i int) string {
+	return fmt.Sprintf("attachment[%d]", i)
+}
+
+// scrubberOrDefault returns s, or the built-in scrubber when s is nil.
+func scrubberOrDefault(s *scrub.Scrubber) *scrub.Scrubber {
+	if s == nil {
+		return scrub.Default()
+	}
+	return s
+}
+
+// scrubAttachment replaces secrets in a text attachment's content and notes
+// how many were replaced in its metadata.
+func scrubAttachment(a *db.Attachment, s *scrub.Scrubber) {
+	if a.Type == db.AttachmentTypeScreenshot {
+		return
+	}
+	content, n := scrubberOrDefault(s).String(a.Content)
+	if n == 0 {
+		return
+	}
+	a.Content = content
+	if a.Metadata == nil {
+		a.Metadata = map[string]any{}
+	}
+	a.Metadata["redactions"] = n
+}
+
+// attachmentRedactions returns the count scrubAttachment noted.
+func attachmentRedactions(a db.Attachment) int {
+	switch n := a.Metadata["redactions"].(type) {
+	case int:
+		return n
+	case float64:
+		return int(n)
+	}
+	return 0
+}
+
+// recordRedactions stores the non-zero redaction counts of a request's
+// artifacts (best effort).
+func recordRedactions(database *db.DB, requestID string, counts map[string]int) {
+	for artifact, n := range counts {
+		if n > 0 {
+			_ = database.RecordRedactions(requestID, artifact, n)
+		}
+	}
+}
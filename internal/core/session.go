@@ -1,8 +1,10 @@
 package core
 
 import (
+	"crypto/hmac"
 	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/Dicklesworthstone/slb/internal/db"
@@ -11,6 +13,10 @@ import (
 // ErrSessionProgramMismatch indicates an active session exists, but belongs to a different program.
 var ErrSessionProgramMismatch = errors.New("active session belongs to a different program")
 
+// ErrSessionContextMismatch indicates an active session is being resumed from a different
+// process context than the one it was attested with.
+var ErrSessionContextMismatch = errors.New("active session was started from a different process context")
+
 // SessionSummary is a safe-to-serialize view of a session (excludes session_key).
 type SessionSummary struct {
 	ID           string
@@ -47,12 +53,19 @@ type ResumeOptions struct {
 	ProjectPath      string
 	CreateIfMissing  bool
 	ForceEndMismatch bool
+	// Attestation is the caller's process context. It is recorded on new
+	// sessions and compared against the attested context on resume.
+	Attestation *db.SessionAttestation
+	// EnforceAttestation refuses resumes from a different process context
+	// (unless ForceEndMismatch is set) instead of recording them.
+	EnforceAttestation bool
 }
 
 // ResumeSession resumes an existing active session (agent_name + project_path) or creates a new one.
 //
 // Behavior:
 // - If an active session exists and Program is specified, it must match (unless ForceEndMismatch is true).
+// - If the session and caller attestations differ, the change is recorded (EnforceAttestation refuses it unless ForceEndMismatch).
 // - On successful resume, updates the session heartbeat (last_active_at) and returns the session (with session_key).
 // - If no active session exists:
 //   - CreateIfMissing=true → creates a new session and returns it
@@ -72,16 +85,7 @@ func ResumeSession(dbConn *db.DB, opts ResumeOptions) (*db.Session, error) {
 				return nil, db.ErrSessionNotFound
 			}
 
-			newSess := &db.Session{
-				AgentName:   opts.AgentName,
-				Program:     opts.Program,
-				Model:       opts.Model,
-				ProjectPath: opts.ProjectPath,
-			}
-			if err := dbConn.CreateSession(newSess); err != nil {
-				return nil, err
-			}
-			return newSess, nil
+			return createResumedSession(dbConn, opts)
 		}
 		return nil, err
 	}
@@ -95,16 +99,36 @@ func ResumeSession(dbConn *db.DB, opts ResumeOptions) (*db.Session, error) {
 		if err := dbConn.EndSession(sess.ID); err != nil {
 			return nil, err
 		}
-		newSess := &db.Session{
-			AgentName:   opts.AgentName,
-			Program:     opts.Program,
-			Model:       opts.Model,
-			ProjectPath: opts.ProjectPath,
+		return createResumedSession(dbConn, opts)
+	}
+
+	switch fields := AttestationMismatch(sess.Attestation, opts.Attestation); {
+	case sess.Attestation == nil && opts.Attestation != nil:
+		// Sessions started without attestation are attested on first resume.
+		if err := dbConn.UpdateSessionAttestation(sess.ID, opts.Attestation); err != nil {
+			return nil, err
+		}
+	case len(fields) > 0 && opts.EnforceAttestation:
+		if !opts.ForceEndMismatch {
+			return nil, fmt.Errorf("%w: %s", ErrSessionContextMismatch, strings.Join(fields, ", "))
 		}
-		if err := dbConn.CreateSession(newSess); err != nil {
+		if err := dbConn.EndSession(sess.ID); err != nil {
+			return nil, err
+		}
+		return createResumedSession(dbConn, opts)
+	case len(fields) > 0:
+		attestation := *sess.Attestation
+		attestation.ContextChanges = append(attestation.ContextChanges, db.ContextChange{
+			At:     opts.Attestation.CollectedAt,
+			Fields: fields,
+			Anchor: opts.Attestation.Anchor,
+		})
+		if n := len(attestation.ContextChanges); n > maxContextChanges {
+			attestation.ContextChanges = attestation.ContextChanges[n-maxContextChanges:]
+		}
+		if err := dbConn.UpdateSessionAttestation(sess.ID, &attestation); err != nil {
 			return nil, err
 		}
-		return newSess, nil
 	}
 
 	// If model changed (and we didn't force-recreate), update the session model.
@@ -121,6 +145,43 @@ func ResumeSession(dbConn *db.DB, opts ResumeOptions) (*db.Session, error) {
 	return dbConn.GetSession(sess.ID)
 }
 
+func createResumedSession(dbConn *db.DB, opts ResumeOptions) (*db.Session, error) {
+	newSess := &db.Session{
+		AgentName:   opts.AgentName,
+		Program:     opts.Program,
+		Model:       opts.Model,
+		ProjectPath: opts.ProjectPath,
+		Attestation: opts.Attestation,
+	}
+	if err := dbConn.CreateSession(newSess); err != nil {
+		return nil, err
+	}
+	return newSess, nil
+}
+
+// RotateSessionKey replaces the key of an active session after checking the
+// caller holds the current one. Reviews signed with the old key still verify
+// through db.VerifyStoredReviewSignature.
+func RotateSessionKey(dbConn *db.DB, sessionID, currentKey string) (*db.Session, error) {
+	if currentKey == "" {
+		return nil, ErrMissingSessionKey
+	}
+	sess, err := dbConn.GetSession(sessionID)
+	if err != nil {
+		return nil, err
+	}
+	if sess.EndedAt != nil {
+		return nil, ErrSessionInactive
+	}
+	if !hmac.Equal([]byte(currentKey), []byte(sess.SessionKey)) {
+		return nil, ErrSessionKeyMismatch
+	}
+	if _, err := dbConn.RotateSessionKey(sessionID); err != nil {
+		return nil, err
+	}
+	return dbConn.GetSession(sessionID)
+}
+
 // GarbageCollectStaleSessions finds stale sessions for a project and ends them unless DryRun is set.
 func GarbageCollectStaleSessions(dbConn *db.DB, opts SessionGCOptions) (*SessionGCResult, error) {
 	if dbConn == nil {
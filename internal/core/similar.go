@@ -0,0 +1,321 @@
+// Package core provides lookup of similar past requests for reviewers.
+package core
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mattn/go-shellwords"
+
+	"github.com/Dicklesworthstone/slb/internal/db"
+)
+
+// DefaultSimilarLimit is the number of similar requests returned when no
+// limit is given.
+const DefaultSimilarLimit = 10
+
+// subcommandPrograms are programs whose first positional argument is a
+// subcommand (git push, kubectl delete, terraform destroy, ...).
+var subcommandPrograms = map[string]bool{
+	"git": true, "kubectl": true, "docker": true, "podman": true, "helm": true,
+	"terraform": true, "tofu": true, "npm": true, "pnpm": true, "yarn": true,
+	"cargo": true, "go": true, "aws": true, "gcloud": true, "az": true,
+	"systemctl": true, "apt": true, "apt-get": true, "brew": true, "pip": true,
+	"gh": true, "heroku": true, "flyctl": true, "fly": true, "vercel": true,
+	"redis-cli": true, "make": true, "supabase": true, "prisma": true,
+}
+
+// CommandShape is the normalized shape of a command used to match similar
+// requests: the program, its subcommand (for programs that have them) and
+// the target, which is the last positional argument.
+type CommandShape struct {
+	Program    string `json:"program"`
+	Subcommand string `json:"subcommand,omitempty"`
+	Target     string `json:"target,omitempty"`
+}
+
+// ShapeOf computes the shape of a command's primary segment after wrappers
+// such as sudo and env are stripped.
+func ShapeOf(command string) CommandShape {
+	primary := NormalizeCommand(command).Primary
+	if primary == "" {
+		return CommandShape{}
+	}
+	tokens, err := shellwords.NewParser().Parse(primary)
+	if err != nil || len(tokens) == 0 {
+		tokens = strings.Fields(primary)
+	}
+	if len(tokens) == 0 {
+		return CommandShape{}
+	}
+
+	shape := CommandShape{Program: filepath.Base(tokens[0])}
+	var positional []string
+	for _, tok := range tokens[1:] {
+		if strings.HasPrefix(tok, "-") {
+			continue
+		}
+		positional = append(positional, tok)
+	}
+	if subcommandPrograms[shape.Program] && len(positional) > 0 {
+		shape.Subcommand = positional[0]
+		positional = positional[1:]
+	}
+	if len(positional) > 0 {
+		shape.Target = strings.TrimSuffix(positional[len(positional)-1], "/")
+	}
+	return shape
+}
+
+// SimilarityMatch describes how closely a past request matches.
+type SimilarityMatch string
+
+const (
+	// MatchExact is the same command in the same directory.
+	MatchExact SimilarityMatch = "exact"
+	// MatchTarget is the same program, subcommand and target.
+	MatchTarget SimilarityMatch = "target"
+	// MatchSubcommand is the same program and subcommand with another target.
+	MatchSubcommand SimilarityMatch = "subcommand"
+)
+
+func (m SimilarityMatch) rank() int {
+	switch m {
+	case MatchExact:
+		return 3
+	case MatchTarget:
+		return 2
+	case MatchSubcommand:
+		return 1
+	}
+	return 0
+}
+
+// matchShape compares two shapes. Programs without a subcommand only match
+// on the same target: "rm -rf ./build" says little about "rm -rf /".
+func matchShape(a, b CommandShape) (SimilarityMatch, bool) {
+	if a.Program == "" || a.Program != b.Program || a.Subcommand != b.Subcommand {
+		return "", false
+	}
+	if a.Target == b.Target {
+		return MatchTarget, true
+	}
+	if a.Subcommand != "" {
+		return MatchSubcommand, true
+	}
+	return "", false
+}
+
+// SimilarDecision is one review of a similar request.
+type SimilarDecision struct {
+	ReviewerAgent string      `json:"reviewer_agent"`
+	Decision      db.Decision `json:"decision"`
+	Comments      string      `json:"comments,omitempty"`
+}
+
+// SimilarRequest is a past request resembling the one under review, with
+// its outcome.
+type SimilarRequest struct {
+	RequestID          string            `json:"request_id"`
+	Command            string            `json:"command"`
+	Match              SimilarityMatch   `json:"match"`
+	Status             db.RequestStatus  `json:"status"`
+	RiskTier           db.RiskTier       `json:"risk_tier"`
+	RequestorAgent     string            `json:"requestor_agent"`
+	CreatedAt          time.Time         `json:"created_at"`
+	Decisions          []SimilarDecision `json:"decisions,omitempty"`
+	ExitCode           *int              `json:"exit_code,omitempty"`
+	CausedProblems     bool              `json:"caused_problems"`
+	ProblemDescription string            `json:"problem_description,omitempty"`
+}
+
+// Approvals counts approving decisions.
+func (s SimilarRequest) Approvals() int {
+	n := 0
+	for _, d := range s.Decisions {
+		if d.Decision == db.DecisionApprove {
+			n++
+		}
+	}
+	return n
+}
+
+// Rejections counts rejecting decisions.
+func (s SimilarRequest) Rejections() int {
+	return len(s.Decisions) - s.Approvals()
+}
+
+// SimilarSummary aggregates the outcomes of similar requests.
+type SimilarSummary struct {
+	Total    int `json:"total"`
+	Approved int `json:"approved"`
+	Rejected int `json:"rejected"`
+	Executed int `json:"executed"`
+	Failed   int `json:"failed"`
+	Problems int `json:"caused_problems"`
+}
+
+// SummarizeSimilar counts how similar requests ended. Approved includes
+// requests that were later executed; Failed counts non-zero exit codes.
+func SummarizeSimilar(similar []SimilarRequest) SimilarSummary {
+	s := SimilarSummary{Total: len(similar)}
+	for _, r := range similar {
+		switch r.Status {
+		case db.StatusApproved, db.StatusExecuting, db.StatusExecuted, db.StatusExecutionFailed, db.StatusTimedOut:
+			s.Approved++
+		case db.StatusRejected:
+			s.Rejected++
+		}
+		if r.ExitCode != nil {
+			s.Executed++
+			if *r.ExitCode != 0 {
+				s.Failed++
+			}
+		}
+		if r.CausedProblems {
+			s.Problems++
+		}
+	}
+	return s
+}
+
+// String renders the summary on one line, e.g.
+// "3 similar: 2 approved, 1 rejected, 1 caused problems".
+func (s SimilarSummary) String() string {
+	if s.Total == 0 {
+		return "no similar requests"
+	}
+	parts := []string{fmt.Sprintf("%d approved", s.Approved), fmt.Sprintf("%d rejected", s.Rejected)}
+	if s.Failed > 0 {
+		parts = append(parts, fmt.Sprintf("%d failed", s.Failed))
+	}
+	if s.Problems > 0 {
+		parts = append(parts, fmt.Sprintf("%d caused problems", s.Problems))
+	}
+	return fmt.Sprintf("%d similar: %s", s.Total, strings.Join(parts, ", "))
+}
+
+// FindSimilarRequests finds earlier requests with the same command shape as
+// req: identical commands first, then the same program/subcommand/target,
+// then the same program and subcommand. Candidates come from the command
+// hash index and a full-text search on the program name. Each result carries
+// its review decisions, exit code and any recorded problem report.
+func FindSimilarRequests(database *db.DB, req *db.Request, limit int) ([]SimilarRequest, error) {
+	if database == nil || req == nil {
+		return nil, nil
+	}
+	if limit <= 0 {
+		limit = DefaultSimilarLimit
+	}
+
+	shape := ShapeOf(req.Command.Raw)
+	candidates, err := database.ListRequestsByCommandHash(req.Command.Hash, req.ID, 0)
+	if err != nil {
+		return nil, err
+	}
+	if term := ftsTerm(shape.Program); term != "" {
+		found, err := database.SearchRequests("command_raw:" + term)
+		if err != nil {
+			return nil, err
+		}
+		candidates = append(candidates, found...)
+	}
+
+	seen := map[string]bool{req.ID: true}
+	var out []SimilarRequest
+	for _, c := range candidates {
+		if c == nil || seen[c.ID] || (!req.CreatedAt.IsZero() && c.CreatedAt.After(req.CreatedAt)) {
+			continue
+		}
+		seen[c.ID] = true
+
+		match := MatchExact
+		if c.Command.Hash != req.Command.Hash {
+			m, ok := matchShape(shape, ShapeOf(c.Command.Raw))
+			if !ok {
+				continue
+			}
+			match = m
+		}
+		out = append(out, SimilarRequest{
+			RequestID:      c.ID,
+			Command:        displayedCommand(c),
+			Match:          match,
+			Status:         c.Status,
+			RiskTier:       c.RiskTier,
+			RequestorAgent: c.RequestorAgent,
+			CreatedAt:      c.CreatedAt,
+		})
+	}
+
+	sort.SliceStable(out, func(i, j int) bool {
+		if ri, rj := out[i].Match.rank(), out[j].Match.rank(); ri != rj {
+			return ri > rj
+		}
+		return out[i].CreatedAt.After(out[j].CreatedAt)
+	})
+	if len(out) > limit {
+		out = out[:limit]
+	}
+
+	for i := range out {
+		if err := loadSimilarOutcome(database, &out[i]); err != nil {
+			return nil, err
+		}
+	}
+	return out, nil
+}
+
+// loadSimilarOutcome fills in reviews, exit code and problem report.
+func loadSimilarOutcome(database *db.DB, s *SimilarRequest) error {
+	r, reviews, err := database.GetRequestWithReviews(s.RequestID)
+	if err != nil {
+		return fmt.Errorf("loading similar request %s: %w", s.RequestID, err)
+	}
+	for _, rev := range reviews {
+		s.Decisions = append(s.Decisions, SimilarDecision{
+			ReviewerAgent: rev.ReviewerAgent,
+			Decision:      rev.Decision,
+			Comments:      rev.Comments,
+		})
+	}
+	if r.Execution != nil {
+		s.ExitCode = r.Execution.ExitCode
+	}
+	outcome, err := database.GetOutcomeForRequest(s.RequestID)
+	switch {
+	case errors.Is(err, db.ErrOutcomeNotFound):
+	case err != nil:
+		return fmt.Errorf("loading outcome for %s: %w", s.RequestID, err)
+	default:
+		s.CausedProblems = outcome.CausedProblems
+		s.ProblemDescription = outcome.ProblemDescription
+	}
+	return nil
+}
+
+// ftsTerm quotes a program name for an FTS5 query ("" if nothing is left).
+func ftsTerm(program string) string {
+	program = strings.Map(func(r rune) rune {
+		if r == '"' {
+			return -1
+		}
+		return r
+	}, program)
+	if strings.TrimSpace(program) == "" {
+		return ""
+	}
+	return `"` + program + `"`
+}
+
+// displayedCommand prefers the redacted form of a command.
+func displayedCommand(r *db.Request) string {
+	if r.Command.ContainsSensitive && r.Command.DisplayRedacted != "" {
+		return r.Command.DisplayRedacted
+	}
+	return r.Command.Raw
+}
@@ -0,0 +1,152 @@
+package core
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Dicklesworthstone/slb/internal/db"
+)
+
+func TestShapeOf(t *testing.T) {
+	cases := []struct {
+		cmd  string
+		want CommandShape
+	}{
+		{"rm -rf ./build/", CommandShape{Program: "rm", Target: "./build"}},
+		{"sudo /bin/rm -rf /var/log/app", CommandShape{Program: "rm", Target: "/var/log/app"}},
+		{"git push --force origin main", CommandShape{Program: "git", Subcommand: "push", Target: "main"}},
+		{"kubectl delete pod web-1 -n prod", CommandShape{Program: "kubectl", Subcommand: "delete", Target: "prod"}},
+		{"env FOO=1 terraform destroy", CommandShape{Program: "terraform", Subcommand: "destroy"}},
+		{"", CommandShape{}},
+	}
+	for _, tc := range cases {
+		if got := ShapeOf(tc.cmd); got != tc.want {
+			t.Errorf("ShapeOf(%q) = %+v, want %+v", tc.cmd, got, tc.want)
+		}
+	}
+}
+
+func TestMatchShape(t *testing.T) {
+	cases := []struct {
+		a, b   string
+		want   SimilarityMatch
+		wantOK bool
+	}{
+		{"git push origin main", "git push -f origin main", MatchTarget, true},
+		{"git push origin main", "git push origin dev", MatchSubcommand, true},
+		{"git push origin main", "git reset --hard", "", false},
+		{"rm -rf ./build", "rm -r ./build", MatchTarget, true},
+		{"rm -rf ./build", "rm -rf /", "", false},
+		{"rm -rf ./build", "rmdir ./build", "", false},
+	}
+	for _, tc := range cases {
+		got, ok := matchShape(ShapeOf(tc.a), ShapeOf(tc.b))
+		if got != tc.want || ok != tc.wantOK {
+			t.Errorf("matchShape(%q, %q) = %q, %v; want %q, %v", tc.a, tc.b, got, ok, tc.want, tc.wantOK)
+		}
+	}
+}
+
+func TestFindSimilarRequests(t *testing.T) {
+	dbConn, err := db.Open(filepath.Join(t.TempDir(), "state.db"))
+	if err != nil {
+		t.Fatalf("db.Open: %v", err)
+	}
+	defer dbConn.Close()
+
+	const project = "/test/project"
+	sess := &db.Session{AgentName: "BlueLake", Program: "test", Model: "opus", ProjectPath: project}
+	if err := dbConn.CreateSession(sess); err != nil {
+		t.Fatalf("CreateSession: %v", err)
+	}
+	reviewer := &db.Session{AgentName: "GreenHill", Program: "test", Model: "gpt", ProjectPath: project}
+	if err := dbConn.CreateSession(reviewer); err != nil {
+		t.Fatalf("CreateSession: %v", err)
+	}
+
+	base := time.Now().UTC().Add(-time.Hour).Truncate(time.Second)
+	n := 0
+	newRequest := func(cmd string) *db.Request {
+		r := &db.Request{
+			ProjectPath:        project,
+			RequestorSessionID: sess.ID,
+			RequestorAgent:     sess.AgentName,
+			RequestorModel:     sess.Model,
+			RiskTier:           db.RiskTierDangerous,
+			MinApprovals:       1,
+			Command:            db.CommandSpec{Raw: cmd, Cwd: project},
+			Justification:      db.Justification{Reason: "deploy"},
+		}
+		if err := dbConn.CreateRequest(r); err != nil {
+			t.Fatalf("CreateRequest: %v", err)
+		}
+		n++
+		r.CreatedAt = base.Add(time.Duration(n) * time.Minute)
+		if _, err := dbConn.Exec(`UPDATE requests SET created_at = ? WHERE id = ?`, r.CreatedAt.Format(time.RFC3339), r.ID); err != nil {
+			t.Fatalf("update created_at: %v", err)
+		}
+		return r
+	}
+
+	exact := newRequest("git push --force origin main")
+	sameTarget := newRequest("git push -f origin main")
+	otherTarget := newRequest("git push --force origin dev")
+	newRequest("git reset --hard")
+	newRequest("rm -rf ./build")
+	current := newRequest("git push --force origin main")
+	newRequest("git push --force origin main") // later; not a precedent
+
+	// The exact match was approved, executed and caused problems.
+	if err := dbConn.CreateReview(&db.Review{
+		RequestID: exact.ID, ReviewerSessionID: reviewer.ID, ReviewerAgent: reviewer.AgentName,
+		ReviewerModel: reviewer.Model, Decision: db.DecisionApprove, Signature: "sig", Comments: "ok",
+	}); err != nil {
+		t.Fatalf("CreateReview: %v", err)
+	}
+	exit := 1
+	if err := dbConn.UpdateRequestExecution(exact.ID, &db.Execution{ExitCode: &exit}); err != nil {
+		t.Fatalf("UpdateRequestExecution: %v", err)
+	}
+	if _, err := dbConn.RecordOutcome(exact.ID, true, "overwrote teammate commits", nil, ""); err != nil {
+		t.Fatalf("RecordOutcome: %v", err)
+	}
+
+	similar, err := FindSimilarRequests(dbConn, current, 0)
+	if err != nil {
+		t.Fatalf("FindSimilarRequests: %v", err)
+	}
+	if len(similar) != 3 {
+		t.Fatalf("expected 3 similar requests, got %d: %+v", len(similar), similar)
+	}
+	want := []struct {
+		id    string
+		match SimilarityMatch
+	}{{exact.ID, MatchExact}, {sameTarget.ID, MatchTarget}, {otherTarget.ID, MatchSubcommand}}
+	for i, w := range want {
+		if similar[i].RequestID != w.id || similar[i].Match != w.match {
+			t.Errorf("similar[%d] = %s/%s, want %s/%s", i, similar[i].RequestID, similar[i].Match, w.id, w.match)
+		}
+	}
+
+	first := similar[0]
+	if first.Approvals() != 1 || first.Rejections() != 0 || first.Decisions[0].ReviewerAgent != "GreenHill" {
+		t.Errorf("unexpected decisions %+v", first.Decisions)
+	}
+	if first.ExitCode == nil || *first.ExitCode != 1 || !first.CausedProblems || first.ProblemDescription != "overwrote teammate commits" {
+		t.Errorf("unexpected outcome %+v", first)
+	}
+
+	summary := SummarizeSimilar(similar)
+	if summary.Total != 3 || summary.Executed != 1 || summary.Failed != 1 || summary.Problems != 1 {
+		t.Errorf("unexpected summary %+v", summary)
+	}
+	if got := summary.String(); got != "3 similar: 0 approved, 0 rejected, 1 failed, 1 caused problems" {
+		t.Errorf("summary string = %q", got)
+	}
+
+	limited, err := FindSimilarRequests(dbConn, current, 1)
+	if err != nil || len(limited) != 1 || limited[0].RequestID != exact.ID {
+		t.Errorf("expected only the exact match with limit 1, got %+v, %v", limited, err)
+	}
+}
@@ -0,0 +1,235 @@
+// Package core implements asciicast transcripts of PTY executions.
+package core
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+// Transcript event types (asciicast v2).
+const (
+	TranscriptOutput = "o"
+	TranscriptInput  = "i"
+	TranscriptResize = "r"
+)
+
+// TranscriptHeader is the first line of an asciicast v2 file.
+type TranscriptHeader struct {
+	Version   int               `json:"version"`
+	Width     int               `json:"width"`
+	Height    int               `json:"height"`
+	Timestamp int64             `json:"timestamp,omitempty"`
+	Command   string            `json:"command,omitempty"`
+	Title     string            `json:"title,omitempty"`
+	Env       map[string]string `json:"env,omitempty"`
+}
+
+// TranscriptEvent is one line after the header: the seconds since the
+// start, the event type and its data.
+type TranscriptEvent struct {
+	Time float64
+	Type string
+	Data string
+}
+
+// MarshalJSON encodes the event as asciicast's [time, type, data] array.
+func (e TranscriptEvent) MarshalJSON() ([]byte, error) {
+	return json.Marshal([]any{e.Time, e.Type, e.Data})
+}
+
+// UnmarshalJSON decodes a [time, type, data] array.
+func (e *TranscriptEvent) UnmarshalJSON(b []byte) error {
+	var raw []json.RawMessage
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return err
+	}
+	if len(raw) != 3 {
+		return fmt.Errorf("transcript event has %d fields, want 3", len(raw))
+	}
+	if err := json.Unmarshal(raw[0], &e.Time); err != nil {
+		return err
+	}
+	if err := json.Unmarshal(raw[1], &e.Type); err != nil {
+		return err
+	}
+	return json.Unmarshal(raw[2], &e.Data)
+}
+
+// Transcript is a recorded terminal session.
+type Transcript struct {
+	Header TranscriptHeader
+	Events []TranscriptEvent
+}
+
+// TranscriptPath is where the transcript of the execution logged to
+// logPath is written: the log path with a .cast extension.
+func TranscriptPath(logPath string) string {
+	return strings.TrimSuffix(logPath, ".log") + ".cast"
+}
+
+// transcriptWriter records a session as asciicast v2. It is safe for
+// concurrent use; output split inside a UTF-8 sequence is held back until
+// the sequence is complete.
+type transcriptWriter struct {
+	mu      sync.Mutex
+	f       *os.File
+	enc     *json.Encoder
+	start   time.Time
+	pending []byte
+}
+
+func newTranscriptWriter(path string, header TranscriptHeader) (*transcriptWriter, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("creating transcript: %w", err)
+	}
+	w := &transcriptWriter{f: f, enc: json.NewEncoder(f), start: time.Now()}
+	header.Version = 2
+	if header.Timestamp == 0 {
+		header.Timestamp = w.start.Unix()
+	}
+	if err := w.enc.Encode(header); err != nil {
+		f.Close()
+		return nil, fmt.Errorf("writing transcript: %w", err)
+	}
+	return w, nil
+}
+
+// Write records output.
+func (w *transcriptWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	data := append(w.pending, p...)
+	n := completeUTF8(data)
+	w.pending = append([]byte(nil), data[n:]...)
+	if n > 0 {
+		if err := w.event(TranscriptOutput, string(data[:n])); err != nil {
+			return 0, err
+		}
+	}
+	return len(p), nil
+}
+
+// Input records data typed into the session.
+func (w *transcriptWriter) Input(s string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	_ = w.event(TranscriptInput, s)
+}
+
+// Resize records a terminal size change.
+func (w *transcriptWriter) Resize(cols, rows int) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	_ = w.event(TranscriptResize, fmt.Sprintf("%dx%d", cols, rows))
+}
+
+// Close records any held-back output and closes the file.
+func (w *transcriptWriter) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if len(w.pending) > 0 {
+		_ = w.event(TranscriptOutput, string(w.pending))
+		w.pending = nil
+	}
+	return w.f.Close()
+}
+
+func (w *transcriptWriter) event(typ, data string) error {
+	elapsed := float64(time.Since(w.start).Microseconds()) / 1e6
+	return w.enc.Encode(TranscriptEvent{Time: elapsed, Type: typ, Data: data})
+}
+
+// completeUTF8 returns the length of b without a trailing incomplete UTF-8
+// sequence.
+func completeUTF8(b []byte) int {
+	for i := len(b) - 1; i >= 0 && i >= len(b)-utf8.UTFMax; i-- {
+		if !utf8.RuneStart(b[i]) {
+			continue
+		}
+		if !utf8.FullRune(b[i:]) {
+			return i
+		}
+		break
+	}
+	return len(b)
+}
+
+// ReadTranscript reads an asciicast v2 file.
+func ReadTranscript(path string) (*Transcript, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	r := bufio.NewReader(f)
+	t := &Transcript{}
+	for first := true; ; first = false {
+		line, err := r.ReadBytes('\n')
+		if len(strings.TrimSpace(string(line))) > 0 {
+			if first {
+				if err := json.Unmarshal(line, &t.Header); err != nil {
+					return nil, fmt.Errorf("reading transcript header: %w", err)
+				}
+				if t.Header.Version != 2 {
+					return nil, fmt.Errorf("unsupported transcript version %d", t.Header.Version)
+				}
+			} else {
+				var ev TranscriptEvent
+				if err := json.Unmarshal(line, &ev); err != nil {
+					return nil, fmt.Errorf("reading transcript event: %w", err)
+				}
+				t.Events = append(t.Events, ev)
+			}
+		}
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("reading transcript: %w", err)
+		}
+	}
+	return t, nil
+}
+
+// ReplayTranscript writes the transcript's output to w with its original
+// timing divided by speed; speed <= 0 writes it all at once. Pauses longer
+// than maxIdle are shortened to maxIdle when it is positive.
+func ReplayTranscript(ctx context.Context, w io.Writer, t *Transcript, speed float64, maxIdle time.Duration) error {
+	var last float64
+	for _, ev := range t.Events {
+		if ev.Type != TranscriptOutput {
+			continue
+		}
+		if speed > 0 {
+			delay := time.Duration((ev.Time - last) * float64(time.Second))
+			if maxIdle > 0 && delay > maxIdle {
+				delay = maxIdle
+			}
+			if delay > 0 {
+				timer := time.NewTimer(time.Duration(float64(delay) / speed))
+				select {
+				case <-ctx.Done():
+					timer.Stop()
+					return ctx.Err()
+				case <-timer.C:
+				}
+			}
+		}
+		last = ev.Time
+		if _, err := io.WriteString(w, ev.Data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,78 @@
+package core
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestTranscript_WriteReadReplay(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "run.cast")
+	w, err := newTranscriptWriter(path, TranscriptHeader{Width: 80, Height: 24, Command: "terraform apply"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	euro := []byte("€")
+	_, _ = w.Write([]byte("Enter a value: "))
+	w.Input("yes\r")
+	// A multi-byte rune split across writes is recorded whole.
+	_, _ = w.Write(append([]byte("cost "), euro[:1]...))
+	_, _ = w.Write(append(euro[1:], '\n'))
+	w.Resize(120, 40)
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	tr, err := ReadTranscript(path)
+	if err != nil {
+		t.Fatalf("ReadTranscript: %v", err)
+	}
+	if tr.Header.Version != 2 || tr.Header.Width != 80 || tr.Header.Height != 24 || tr.Header.Command != "terraform apply" {
+		t.Errorf("unexpected header: %+v", tr.Header)
+	}
+	var types []string
+	for _, ev := range tr.Events {
+		types = append(types, ev.Type)
+	}
+	want := []string{TranscriptOutput, TranscriptInput, TranscriptOutput, TranscriptOutput, TranscriptResize}
+	if len(types) != len(want) {
+		t.Fatalf("event types = %v, want %v", types, want)
+	}
+	for i := range want {
+		if types[i] != want[i] {
+			t.Fatalf("event types = %v, want %v", types, want)
+		}
+	}
+	if tr.Events[2].Data != "cost " || tr.Events[3].Data != "€\n" {
+		t.Errorf("split rune recorded as %q, %q", tr.Events[2].Data, tr.Events[3].Data)
+	}
+	if tr.Events[4].Data != "120x40" {
+		t.Errorf("resize recorded as %q", tr.Events[4].Data)
+	}
+
+	var out bytes.Buffer
+	if err := ReplayTranscript(context.Background(), &out, tr, 0, 0); err != nil {
+		t.Fatalf("ReplayTranscript: %v", err)
+	}
+	if out.String() != "Enter a value: cost €\n" {
+		t.Errorf("replayed %q", out.String())
+	}
+}
+
+func TestTranscriptPath(t *testing.T) {
+	if got := TranscriptPath(".slb/logs/20260101-120000_abcd1234.log"); got != ".slb/logs/20260101-120000_abcd1234.cast" {
+		t.Errorf("TranscriptPath = %q", got)
+	}
+}
+
+func TestReadTranscript_RejectsOtherVersions(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "old.cast")
+	if err := os.WriteFile(path, []byte(`{"version": 1, "width": 80, "height": 24}`+"\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ReadTranscript(path); err == nil {
+		t.Fatal("expected error for asciicast v1")
+	}
+}
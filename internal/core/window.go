@@ -0,0 +1,196 @@
+// Package core implements execution windows and freeze periods: when an
+// approved request may execute.
+package core
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Dicklesworthstone/slb/internal/db"
+	"github.com/Dicklesworthstone/slb/internal/schedule"
+)
+
+// Execution window errors.
+var (
+	// ErrOutsideWindow is returned when a request's execution window is not
+	// open yet.
+	ErrOutsideWindow = errors.New("outside the execution window")
+	// ErrWindowClosed is returned when a request's execution window will
+	// not open again.
+	ErrWindowClosed = errors.New("execution window has closed")
+	// ErrUnknownWindow is returned for a change window that is not
+	// configured.
+	ErrUnknownWindow = errors.New("unknown change window")
+	// ErrFreezePeriod is returned while a freeze period covers the
+	// request's tier.
+	ErrFreezePeriod = errors.New("freeze period in effect")
+)
+
+// ChangeWindow is a named recurring window requests can be limited to.
+type ChangeWindow struct {
+	Name string
+	schedule.Window
+}
+
+// FreezePeriod is a recurring period during which requests of its tiers are
+// neither created nor executed.
+type FreezePeriod struct {
+	Name   string
+	Reason string
+	// Tiers are the frozen tiers; empty freezes every tier that needs
+	// approval.
+	Tiers []RiskTier
+	schedule.Window
+}
+
+// Covers reports whether the freeze applies to tier.
+func (f FreezePeriod) Covers(tier RiskTier) bool {
+	if tier == RiskTier(RiskSafe) || tier == "" {
+		return false
+	}
+	if len(f.Tiers) == 0 {
+		return true
+	}
+	for _, t := range f.Tiers {
+		if t == tier {
+			return true
+		}
+	}
+	return false
+}
+
+// ChangeSchedule holds the configured change windows and freeze periods. A
+// nil *ChangeSchedule has neither.
+type ChangeSchedule struct {
+	Windows []ChangeWindow
+	Freezes []FreezePeriod
+}
+
+func (s *ChangeSchedule) window(name string) (*ChangeWindow, error) {
+	if s != nil {
+		for i := range s.Windows {
+			if s.Windows[i].Name == name {
+				return &s.Windows[i], nil
+			}
+		}
+	}
+	return nil, fmt.Errorf("%w %q", ErrUnknownWindow, name)
+}
+
+// ValidateWindow checks the execution window of a new request: its bounds
+// are ordered, its change window exists and it opens before it closes.
+func (s *ChangeSchedule) ValidateWindow(w *db.ExecutionWindow, now time.Time) error {
+	if w == nil {
+		return nil
+	}
+	if w.NotBefore != nil && w.NotAfter != nil && !w.NotAfter.After(*w.NotBefore) {
+		return fmt.Errorf("not-after (%s) must be later than not-before (%s)",
+			w.NotAfter.Format(time.RFC3339), w.NotBefore.Format(time.RFC3339))
+	}
+	_, err := s.NextOpening(w, now)
+	return err
+}
+
+// NextOpening returns when w is next open: now when it is open, or when it
+// opens. It returns ErrWindowClosed when it will not open again.
+func (s *ChangeSchedule) NextOpening(w *db.ExecutionWindow, now time.Time) (time.Time, error) {
+	if w == nil {
+		return now, nil
+	}
+	at := now
+	if w.NotBefore != nil && w.NotBefore.After(at) {
+		at = *w.NotBefore
+	}
+	if w.Recurring != "" {
+		cw, err := s.window(w.Recurring)
+		if err != nil {
+			return time.Time{}, err
+		}
+		if _, open := cw.Active(at); !open {
+			next, ok := cw.Next(at)
+			if !ok {
+				return time.Time{}, fmt.Errorf("%w: change window %q does not open within a year", ErrWindowClosed, cw.Name)
+			}
+			at = next
+		}
+	}
+	if w.NotAfter != nil && !at.Before(*w.NotAfter) {
+		return time.Time{}, fmt.Errorf("%w at %s", ErrWindowClosed, w.NotAfter.Format(time.RFC3339))
+	}
+	return at, nil
+}
+
+// WindowCloses returns when w closes if it is open at now; ok is false when
+// it is not open or never closes.
+func (s *ChangeSchedule) WindowCloses(w *db.ExecutionWindow, now time.Time) (time.Time, bool) {
+	if w == nil {
+		return time.Time{}, false
+	}
+	var end time.Time
+	if w.Recurring != "" {
+		cw, err := s.window(w.Recurring)
+		if err != nil {
+			return time.Time{}, false
+		}
+		var open bool
+		if end, open = cw.Active(now); !open {
+			return time.Time{}, false
+		}
+	}
+	if w.NotAfter != nil && (end.IsZero() || w.NotAfter.Before(end)) {
+		end = *w.NotAfter
+	}
+	return end, !end.IsZero()
+}
+
+// CheckWindow returns nil when w is open at now, and otherwise an error
+// wrapping ErrOutsideWindow, naming when it opens, or ErrWindowClosed.
+func (s *ChangeSchedule) CheckWindow(w *db.ExecutionWindow, now time.Time) error {
+	opening, err := s.NextOpening(w, now)
+	if err != nil {
+		return err
+	}
+	if opening.After(now) {
+		return fmt.Errorf("%w: opens at %s", ErrOutsideWindow, opening.Format(time.RFC3339))
+	}
+	return nil
+}
+
+// ActiveFreeze returns the freeze period covering tier at now, or nil.
+func (s *ChangeSchedule) ActiveFreeze(tier RiskTier, now time.Time) *FreezePeriod {
+	if s == nil {
+		return nil
+	}
+	for i := range s.Freezes {
+		f := &s.Freezes[i]
+		if !f.Covers(tier) {
+			continue
+		}
+		if _, active := f.Active(now); active {
+			return f
+		}
+	}
+	return nil
+}
+
+// CheckFreeze returns an error wrapping ErrFreezePeriod when a freeze period
+// covers tier at now.
+func (s *ChangeSchedule) CheckFreeze(tier RiskTier, now time.Time) error {
+	f := s.ActiveFreeze(tier, now)
+	if f == nil {
+		return nil
+	}
+	end, _ := f.Active(now)
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s requests are frozen", tier)
+	if f.Name != "" {
+		fmt.Fprintf(&b, " by %q", f.Name)
+	}
+	fmt.Fprintf(&b, " until %s", end.Format(time.RFC3339))
+	if f.Reason != "" {
+		fmt.Fprintf(&b, " (%s)", f.Reason)
+	}
+	return fmt.Errorf("%w: %s", ErrFreezePeriod, b.String())
+}
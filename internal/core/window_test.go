@@ -0,0 +1,256 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/Dicklesworthstone/slb/internal/db"
+	"github.com/Dicklesworthstone/slb/internal/schedule"
+	"github.com/Dicklesworthstone/slb/internal/testutil"
+)
+
+func mustWindow(t *testing.T, expr, duration string) schedule.Window {
+	t.Helper()
+	w, err := schedule.NewWindow(expr, duration, "UTC")
+	if err != nil {
+		t.Fatalf("NewWindow(%q): %v", expr, err)
+	}
+	return w
+}
+
+func utc(s string) time.Time {
+	t, err := time.Parse("2006-01-02 15:04", s)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+func TestChangeSchedule_Windows(t *testing.T) {
+	s := &ChangeSchedule{Windows: []ChangeWindow{{Name: "nightly", Window: mustWindow(t, "0 2 * * *", "2h")}}}
+	now := utc("2026-10-16 10:00")
+	later := utc("2026-10-16 12:00")
+	tooEarly := utc("2026-10-17 01:00")
+
+	if err := s.CheckWindow(nil, now); err != nil {
+		t.Errorf("CheckWindow(nil) = %v", err)
+	}
+	if err := s.CheckWindow(&db.ExecutionWindow{NotBefore: &later}, now); !errors.Is(err, ErrOutsideWindow) {
+		t.Errorf("before not-before: got %v, want ErrOutsideWindow", err)
+	}
+	if err := s.CheckWindow(&db.ExecutionWindow{NotAfter: &later}, utc("2026-10-16 12:00")); !errors.Is(err, ErrWindowClosed) {
+		t.Errorf("at not-after: got %v, want ErrWindowClosed", err)
+	}
+
+	nightly := &db.ExecutionWindow{Recurring: "nightly"}
+	if opening, err := s.NextOpening(nightly, now); err != nil || !opening.Equal(utc("2026-10-17 02:00")) {
+		t.Errorf("NextOpening(nightly) = %v, %v", opening, err)
+	}
+	if err := s.CheckWindow(nightly, utc("2026-10-17 03:00")); err != nil {
+		t.Errorf("CheckWindow during the window = %v", err)
+	}
+	if closes, ok := s.WindowCloses(nightly, utc("2026-10-17 03:00")); !ok || !closes.Equal(utc("2026-10-17 04:00")) {
+		t.Errorf("WindowCloses = %v, %v", closes, ok)
+	}
+	if _, err := s.NextOpening(&db.ExecutionWindow{Recurring: "nightly", NotAfter: &tooEarly}, now); !errors.Is(err, ErrWindowClosed) {
+		t.Errorf("window closing before it opens: got %v, want ErrWindowClosed", err)
+	}
+	if err := s.ValidateWindow(&db.ExecutionWindow{Recurring: "weekly"}, now); !errors.Is(err, ErrUnknownWindow) {
+		t.Errorf("unknown window: got %v, want ErrUnknownWindow", err)
+	}
+	if err := s.ValidateWindow(&db.ExecutionWindow{NotBefore: &later, NotAfter: &now}, now); err == nil {
+		t.Error("expected an error for not-after before not-before")
+	}
+}
+
+func TestChangeSchedule_CheckFreeze(t *testing.T) {
+	s := &ChangeSchedule{Freezes: []FreezePeriod{{
+		Name:   "friday",
+		Reason: "weekend",
+		Tiers:  []RiskTier{RiskTierCritical},
+		Window: mustWindow(t, "0 15 * * fri", "9h"),
+	}}}
+	friday := utc("2026-10-16 16:00")
+
+	if err := s.CheckFreeze(RiskTierCritical, friday); !errors.Is(err, ErrFreezePeriod) {
+		t.Errorf("critical on Friday: got %v, want ErrFreezePeriod", err)
+	}
+	if err := s.CheckFreeze(RiskTierDangerous, friday); err != nil {
+		t.Errorf("dangerous on Friday: %v", err)
+	}
+	if err := s.CheckFreeze(RiskTierCritical, utc("2026-10-17 00:00")); err != nil {
+		t.Errorf("critical on Saturday: %v", err)
+	}
+
+	all := FreezePeriod{Window: mustWindow(t, "* * * * *", "1h")}
+	if !all.Covers(RiskTierCaution) || all.Covers(RiskSafe) {
+		t.Error("a freeze without tiers should cover every tier that needs approval")
+	}
+	var none *ChangeSchedule
+	if err := none.CheckFreeze(RiskTierCritical, friday); err != nil {
+		t.Errorf("nil schedule: %v", err)
+	}
+}
+
+func TestCreateRequest_FreezeAndWindow(t *testing.T) {
+	database := testutil.NewTestDB(t)
+	session := testutil.MakeSession(t, database, testutil.SessionWithAgentName("agent1"))
+
+	// A freeze on critical requests for the hour starting now.
+	now := time.Now().UTC()
+	expr := fmtCron(now)
+	cfg := DefaultRequestCreatorConfig()
+	cfg.Schedule = &ChangeSchedule{Freezes: []FreezePeriod{{
+		Name: "release", Tiers: []RiskTier{RiskTierCritical}, Window: mustWindow(t, expr, "1h"),
+	}}}
+	creator := NewRequestCreator(database, nil, nil, cfg)
+
+	opts := CreateRequestOptions{
+		SessionID:     session.ID,
+		Command:       "rm -rf /etc",
+		Justification: Justification{Reason: "cleanup"},
+	}
+	if _, err := creator.CreateRequest(opts); !errors.Is(err, ErrFreezePeriod) {
+		t.Fatalf("during the freeze: got %v, want ErrFreezePeriod", err)
+	}
+
+	// Scheduled after the freeze, the request is accepted and stays pending
+	// until its window opens.
+	notBefore := now.Add(2 * time.Hour).Truncate(time.Second)
+	opts.Window = &db.ExecutionWindow{NotBefore: &notBefore}
+	result, err := creator.CreateRequest(opts)
+	if err != nil {
+		t.Fatalf("CreateRequest: %v", err)
+	}
+	wantExpiry := notBefore.Add(time.Duration(cfg.RequestTimeoutMinutes) * time.Minute)
+	if result.Request.ExpiresAt == nil || !result.Request.ExpiresAt.Equal(wantExpiry) {
+		t.Errorf("ExpiresAt = %v, want %v", result.Request.ExpiresAt, wantExpiry)
+	}
+	stored, err := database.GetRequest(result.Request.ID)
+	if err != nil {
+		t.Fatalf("GetRequest: %v", err)
+	}
+	if stored.Window == nil || stored.Window.NotBefore == nil || !stored.Window.NotBefore.Equal(notBefore) {
+		t.Errorf("Window = %+v", stored.Window)
+	}
+}
+
+// fmtCron returns a cron expression matching the minute of t, once a year.
+func fmtCron(t time.Time) string {
+	return fmt.Sprintf("%d %d %d %d *", t.Minute(), t.Hour(), t.Day(), t.Month())
+}
+
+func TestExecutor_Window(t *testing.T) {
+	database := testutil.NewTestDB(t)
+	requestor := testutil.MakeSession(t, database)
+	reviewer := testutil.MakeSession(t, database, testutil.WithAgent("Reviewer"))
+
+	run := func(w *db.ExecutionWindow, approvalExpiresAt *time.Time) error {
+		request := windowedShellRequest(t, database, requestor, reviewer, w, approvalExpiresAt)
+		_, err := NewExecutor(database, nil).ExecuteApprovedRequest(context.Background(), ExecuteOptions{
+			RequestID:      request.ID,
+			SessionID:      requestor.ID,
+			LogDir:         t.TempDir(),
+			SuppressOutput: true,
+		})
+		return err
+	}
+
+	now := time.Now().UTC()
+	notBefore := now.Add(time.Hour)
+	fresh := notBefore.Add(30 * time.Minute)
+	if err := run(&db.ExecutionWindow{NotBefore: &notBefore}, &fresh); !errors.Is(err, ErrOutsideWindow) {
+		t.Errorf("before the window: got %v, want ErrOutsideWindow", err)
+	}
+	notAfter := now.Add(time.Hour)
+	fresh = now.Add(time.Minute)
+	if err := run(&db.ExecutionWindow{NotAfter: &notAfter}, &fresh); err != nil {
+		t.Errorf("inside the window: %v", err)
+	}
+	// The window is open but the approval TTL counted from its opening has
+	// passed, or the request has none.
+	expired := now.Add(-time.Minute)
+	if err := run(&db.ExecutionWindow{NotAfter: &notAfter}, &expired); !errors.Is(err, ErrApprovalExpired) {
+		t.Errorf("expired approval: got %v, want ErrApprovalExpired", err)
+	}
+	if err := run(&db.ExecutionWindow{NotAfter: &notAfter}, nil); !errors.Is(err, ErrApprovalExpired) {
+		t.Errorf("no approval TTL: got %v, want ErrApprovalExpired", err)
+	}
+}
+
+func TestCreateRequest_WindowApprovalTTL(t *testing.T) {
+	database := testutil.NewTestDB(t)
+	session := testutil.MakeSession(t, database)
+	now := time.Now().UTC()
+	cfg := DefaultRequestCreatorConfig()
+	cfg.Schedule = &ChangeSchedule{Windows: []ChangeWindow{{Name: "hourly", Window: mustWindow(t, fmt.Sprintf("%d * * * *", now.Add(-10*time.Minute).Minute()), "20m")}}}
+	creator := NewRequestCreator(database, nil, nil, cfg)
+	ttl := time.Duration(cfg.ApprovalTTLCriticalMinutes) * time.Minute
+
+	create := func(w *db.ExecutionWindow) *db.Request {
+		t.Helper()
+		result, err := creator.CreateRequest(CreateRequestOptions{
+			SessionID:     session.ID,
+			Command:       "rm -rf /etc",
+			Justification: Justification{Reason: "cleanup"},
+			Window:        w,
+		})
+		if err != nil {
+			t.Fatalf("CreateRequest: %v", err)
+		}
+		return result.Request
+	}
+
+	// Only not-before: the TTL counts from not-before, not from approval.
+	notBefore := now.Add(time.Second).Truncate(time.Second)
+	request := create(&db.ExecutionWindow{NotBefore: &notBefore})
+	if request.ApprovalExpiresAt == nil || !request.ApprovalExpiresAt.Equal(notBefore.Add(ttl)) {
+		t.Errorf("not-before only: ApprovalExpiresAt = %v, want %v", request.ApprovalExpiresAt, notBefore.Add(ttl))
+	}
+
+	// A recurring window without an end: the TTL counts from the opening the
+	// request was created for, not from each later one.
+	request = create(&db.ExecutionWindow{Recurring: "hourly"})
+	if request.ApprovalExpiresAt == nil || request.ApprovalExpiresAt.After(now.Add(ttl+time.Second)) {
+		t.Errorf("recurring: ApprovalExpiresAt = %v, want at most %v", request.ApprovalExpiresAt, now.Add(ttl))
+	}
+	if !approvalExpired(&db.Request{Window: request.Window, ApprovalExpiresAt: ptrTime(now.Add(-time.Second))}) {
+		t.Error("a windowed approval past its TTL should be expired")
+	}
+}
+
+func ptrTime(t time.Time) *time.Time { return &t }
+
+// windowedShellRequest creates an approved request with an execution window
+// and the given approval expiry.
+func windowedShellRequest(t *testing.T, database *db.DB, requestor, reviewer *db.Session, w *db.ExecutionWindow, approvalExpiresAt *time.Time) *db.Request {
+	t.Helper()
+	cmd := db.CommandSpec{Raw: "true", Cwd: t.TempDir(), Shell: true}
+	cmd.Hash = db.ComputeCommandHash(cmd)
+	request := &db.Request{
+		ProjectPath:        requestor.ProjectPath,
+		RequestorSessionID: requestor.ID,
+		RequestorAgent:     requestor.AgentName,
+		RequestorModel:     requestor.Model,
+		RiskTier:           db.RiskTierCaution,
+		Command:            cmd,
+		Justification:      db.Justification{Reason: "test"},
+		Status:             db.StatusApproved,
+		MinApprovals:       1,
+		ApprovalExpiresAt:  approvalExpiresAt,
+		Window:             w,
+	}
+	if err := database.CreateRequest(request); err != nil {
+		t.Fatalf("CreateRequest: %v", err)
+	}
+	if err := database.CreateReview(&db.Review{
+		RequestID: request.ID, ReviewerSessionID: reviewer.ID, ReviewerAgent: reviewer.AgentName,
+		ReviewerModel: reviewer.Model, Decision: db.DecisionApprove, Signature: "sig",
+	}); err != nil {
+		t.Fatalf("CreateReview: %v", err)
+	}
+	return request
+}
@@ -6,6 +6,7 @@ import (
 	"bufio"
 	"context"
 	"crypto/sha256"
+	"crypto/tls"
 	"encoding/hex"
 	"encoding/json"
 	"fmt"
@@ -294,9 +295,35 @@ func pingDaemonTCP(ctx context.Context, addr string, sessionKey string) error {
 	return dialAndPing(ctx, "tcp", addr, &auth)
 }
 
+// dialDaemonTCP dials the daemon TCP listener, negotiating TLS when the host or
+// environment asks for it. An explicit tlsConfig takes precedence over SLB_TLS_*.
+func dialDaemonTCP(ctx context.Context, host string, tlsConfig *tls.Config) (net.Conn, error) {
+	addr, envConfig, err := clientTLSFromEnv(host)
+	if err != nil {
+		return nil, err
+	}
+	if tlsConfig == nil {
+		tlsConfig = envConfig
+	}
+
+	if tlsConfig == nil {
+		var d net.Dialer
+		return d.DialContext(ctx, "tcp", addr)
+	}
+
+	d := tls.Dialer{Config: tlsConfig}
+	return d.DialContext(ctx, "tcp", addr)
+}
+
 func dialAndPing(ctx context.Context, network, addr string, auth *string) error {
-	var d net.Dialer
-	conn, err := d.DialContext(ctx, network, addr)
+	var conn net.Conn
+	var err error
+	if network == "tcp" {
+		conn, err = dialDaemonTCP(ctx, addr, nil)
+	} else {
+		var d net.Dialer
+		conn, err = d.DialContext(ctx, network, addr)
+	}
 	if err != nil {
 		return err
 	}
@@ -6,6 +6,7 @@ package daemon
 
 import (
 	"context"
+	"crypto/tls"
 	"fmt"
 	"os"
 	"os/exec"
@@ -17,6 +18,7 @@ import (
 	"time"
 
 	"github.com/Dicklesworthstone/slb/internal/config"
+	"github.com/Dicklesworthstone/slb/internal/core"
 	"github.com/Dicklesworthstone/slb/internal/db"
 	"github.com/Dicklesworthstone/slb/internal/utils"
 	"github.com/charmbracelet/log"
@@ -128,6 +130,21 @@ func RunDaemon(ctx context.Context, opts ServerOptions) error {
 		logger = l
 	}
 
+	// Keys locked by the system config keep their system value, with a
+	// warning for each later layer that tried to weaken one. Other errors
+	// fall back to the defaults with the system config applied.
+	projectPath, _ := os.Getwd()
+	cfg, err := config.Load(config.LoadOptions{
+		ProjectDir: projectPath,
+		Warn:       func(msg string) { logger.Warn("ignoring locked config value", "problem", msg) },
+	})
+	if err != nil {
+		logger.Warn("failed to load config; using defaults and the system config", "error", err)
+		if cfg, err = config.LoadSystem(); err != nil {
+			return fmt.Errorf("loading system config: %w", err)
+		}
+	}
+
 	// Ensure PID file exists for clients.
 	if err := writePIDFile(opts.PIDFile, os.Getpid()); err != nil {
 		return err
@@ -153,48 +170,36 @@ func RunDaemon(ctx context.Context, opts ServerOptions) error {
 
 	logger.Info("daemon started", "pid", os.Getpid(), "pid_file", opts.PIDFile, "socket", opts.SocketPath)
 
-	projectPath, _ := os.Getwd()
-	cfg := config.DefaultConfig()
-	if loaded, err := config.Load(config.LoadOptions{ProjectDir: projectPath}); err != nil {
-		logger.Warn("failed to load config; using defaults", "error", err)
-	} else {
-		cfg = loaded
-	}
-
 	notifications := NewNotificationManager(projectPath, cfg.Notifications, logger, nil)
 	go notifications.Run(signalCtx, 10*time.Second)
 
 	servers := []*IPCServer{ipcServer}
 	if strings.TrimSpace(cfg.Daemon.TCPAddr) != "" {
-		tcpSrv, err := NewTCPServer(TCPServerOptions{
-			Addr:        cfg.Daemon.TCPAddr,
-			RequireAuth: cfg.Daemon.TCPRequireAuth,
-			AllowedIPs:  cfg.Daemon.TCPAllowedIPs,
-			ValidateAuth: func(ctx context.Context, sessionKey string) (bool, error) {
-				dbPath := filepath.Join(projectPath, ".slb", "state.db")
-				opts := db.OpenOptions{
-					CreateIfNotExists: false,
-					InitSchema:        false,
-					ReadOnly:          true,
-				}
-				dbConn, err := db.OpenWithOptions(dbPath, opts)
-				if err != nil {
-					return false, err
-				}
-				defer dbConn.Close()
-
-				var count int
-				if err := dbConn.QueryRow(`SELECT COUNT(*) FROM sessions WHERE session_key = ? AND ended_at IS NULL`, sessionKey).Scan(&count); err != nil {
-					return false, err
-				}
-				return count > 0, nil
-			},
-		}, logger)
+		tcpSrv, err := newDaemonTCPServer(projectPath, cfg.Daemon, logger)
 		if err != nil {
 			logger.Warn("tcp listener disabled", "error", err)
 		} else {
 			servers = append(servers, tcpSrv)
-			logger.Info("tcp listener started", "addr", cfg.Daemon.TCPAddr, "require_auth", cfg.Daemon.TCPRequireAuth)
+			logger.Info("tcp listener started",
+				"addr", cfg.Daemon.TCPAddr,
+				"require_auth", cfg.Daemon.TCPRequireAuth,
+				"tls", cfg.Daemon.TCPTLSCert != "",
+				"client_certs", cfg.Daemon.TCPTLSClientCA != "",
+			)
+		}
+	}
+
+	if strings.TrimSpace(cfg.Daemon.HTTPAddr) != "" {
+		httpSrv, err := newDaemonHTTPServer(projectPath, cfg.Daemon, cfg.History.SavedQueries, ipcServer, logger)
+		if err != nil {
+			logger.Warn("http gateway disabled", "error", err)
+		} else {
+			go func() {
+				if err := httpSrv.Start(signalCtx); err != nil {
+					logger.Warn("http gateway stopped", "error", err)
+				}
+			}()
+			defer func() { _ = httpSrv.Stop() }()
 		}
 	}
 
@@ -233,6 +238,115 @@ func RunDaemon(ctx context.Context, opts ServerOptions) error {
 	}
 }
 
+// daemonListenerAuth holds the auth settings shared by the TCP listener and the
+// HTTP gateway. Session keys and client certificate principals are both
+// validated against the project database.
+type daemonListenerAuth struct {
+	TLSConfig         *tls.Config
+	ValidateAuth      func(ctx context.Context, sessionKey string) (bool, error)
+	ValidatePrincipal func(ctx context.Context, principal string) (bool, error)
+}
+
+func openProjectDB(projectPath string, readOnly bool) (*db.DB, error) {
+	dbPath := filepath.Join(projectPath, ".slb", "state.db")
+	return db.OpenWithOptions(dbPath, db.OpenOptions{
+		CreateIfNotExists: false,
+		InitSchema:        false,
+		ReadOnly:          readOnly,
+	})
+}
+
+func newDaemonListenerAuth(projectPath string, cfg config.DaemonConfig) (*daemonListenerAuth, error) {
+	openDB := func() (*db.DB, error) { return openProjectDB(projectPath, true) }
+
+	auth := &daemonListenerAuth{
+		ValidateAuth: func(ctx context.Context, sessionKey string) (bool, error) {
+			dbConn, err := openDB()
+			if err != nil {
+				return false, err
+			}
+			defer dbConn.Close()
+
+			var count int
+			if err := dbConn.QueryRow(`SELECT COUNT(*) FROM sessions WHERE session_key = ? AND ended_at IS NULL`, sessionKey).Scan(&count); err != nil {
+				return false, err
+			}
+			return count > 0, nil
+		},
+	}
+
+	if strings.TrimSpace(cfg.TCPTLSCert) != "" {
+		tlsConfig, err := ServerTLSConfig(cfg.TCPTLSCert, cfg.TCPTLSKey, cfg.TCPTLSClientCA, cfg.TCPTLSRequireClientCert)
+		if err != nil {
+			return nil, err
+		}
+		auth.TLSConfig = tlsConfig
+
+		principals := make(map[string]bool, len(cfg.TCPTLSPrincipals))
+		for _, p := range cfg.TCPTLSPrincipals {
+			if p = strings.TrimSpace(p); p != "" {
+				principals[p] = true
+			}
+		}
+		auth.ValidatePrincipal = func(ctx context.Context, principal string) (bool, error) {
+			if principals[principal] {
+				return true, nil
+			}
+			dbConn, err := openDB()
+			if err != nil {
+				return false, err
+			}
+			defer dbConn.Close()
+
+			var count int
+			if err := dbConn.QueryRow(`SELECT COUNT(*) FROM sessions WHERE agent_name = ? AND ended_at IS NULL`, principal).Scan(&count); err != nil {
+				return false, err
+			}
+			return count > 0, nil
+		}
+	}
+
+	return auth, nil
+}
+
+// newDaemonTCPServer builds the TCP listener from daemon config.
+func newDaemonTCPServer(projectPath string, cfg config.DaemonConfig, logger *log.Logger) (*IPCServer, error) {
+	auth, err := newDaemonListenerAuth(projectPath, cfg)
+	if err != nil {
+		return nil, err
+	}
+	return NewTCPServer(TCPServerOptions{
+		Addr:              cfg.TCPAddr,
+		RequireAuth:       cfg.TCPRequireAuth,
+		AllowedIPs:        cfg.TCPAllowedIPs,
+		TLSConfig:         auth.TLSConfig,
+		ValidateAuth:      auth.ValidateAuth,
+		ValidatePrincipal: auth.ValidatePrincipal,
+	}, logger)
+}
+
+// newDaemonHTTPServer builds the HTTP gateway from daemon config. It uses the
+// same auth settings as the TCP listener and mirrors events from ipcServer.
+func newDaemonHTTPServer(projectPath string, cfg config.DaemonConfig, saved map[string]string, ipcServer *IPCServer, logger *log.Logger) (*HTTPServer, error) {
+	auth, err := newDaemonListenerAuth(projectPath, cfg)
+	if err != nil {
+		return nil, err
+	}
+	return NewHTTPServer(HTTPServerOptions{
+		Addr:              cfg.HTTPAddr,
+		RequireAuth:       cfg.TCPRequireAuth,
+		AllowedIPs:        cfg.TCPAllowedIPs,
+		TLSConfig:         auth.TLSConfig,
+		ValidateAuth:      auth.ValidateAuth,
+		ValidatePrincipal: auth.ValidatePrincipal,
+		ProjectPath:       projectPath,
+		OpenDB:            func() (*db.DB, error) { return openProjectDB(projectPath, false) },
+		Events:            ipcServer,
+		ReviewConfig:      core.DefaultReviewConfig(),
+		SavedQueries:      saved,
+	}, logger)
+}
+
 func normalizeServerOptions(opts ServerOptions) ServerOptions {
 	if strings.TrimSpace(opts.SocketPath) == "" {
 		opts.SocketPath = DefaultSocketPath()
@@ -0,0 +1,372 @@
+package daemon
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/tls"
+	"encoding/hex"
+	"fmt"
+	htmltemplate "html/template"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"net/textproto"
+	"strconv"
+	"strings"
+	texttemplate "text/template"
+	"time"
+
+	"github.com/Dicklesworthstone/slb/internal/config"
+	"github.com/Dicklesworthstone/slb/internal/db"
+)
+
+// EmailTimeout bounds a single SMTP delivery (dial through QUIT).
+const EmailTimeout = 30 * time.Second
+
+// EmailMessage is a rendered notification email.
+type EmailMessage struct {
+	To      []string
+	Subject string
+	Text    string
+	HTML    string
+}
+
+// EmailNotifier delivers notification emails.
+type EmailNotifier interface {
+	SendEmail(ctx context.Context, msg EmailMessage) error
+}
+
+// SMTPNotifier sends email through an SMTP relay.
+type SMTPNotifier struct {
+	Host     string
+	Port     int
+	Username string
+	Password string
+	// StartTLS requires the server to offer STARTTLS; delivery fails rather
+	// than falling back to plain text.
+	StartTLS bool
+	From     string
+	// TLSConfig overrides the STARTTLS client config (ServerName defaults to Host).
+	TLSConfig *tls.Config
+}
+
+// NewSMTPNotifier builds an SMTP notifier from notification settings.
+func NewSMTPNotifier(cfg config.NotificationsConfig) *SMTPNotifier {
+	return &SMTPNotifier{
+		Host:     strings.TrimSpace(cfg.EmailSMTPHost),
+		Port:     cfg.EmailSMTPPort,
+		Username: cfg.EmailSMTPUsername,
+		Password: cfg.EmailSMTPPassword,
+		StartTLS: cfg.EmailStartTLS,
+		From:     strings.TrimSpace(cfg.EmailFrom),
+	}
+}
+
+// SendEmail delivers msg to all of its recipients in one SMTP transaction.
+func (n *SMTPNotifier) SendEmail(ctx context.Context, msg EmailMessage) error {
+	if len(msg.To) == 0 {
+		return nil
+	}
+	if n.Host == "" {
+		return fmt.Errorf("smtp host is required")
+	}
+	from, err := mail.ParseAddress(n.From)
+	if err != nil {
+		return fmt.Errorf("invalid from address %q: %w", n.From, err)
+	}
+
+	body, err := buildEmailBody(from, msg, time.Now())
+	if err != nil {
+		return err
+	}
+
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, EmailTimeout)
+		defer cancel()
+	}
+
+	addr := net.JoinHostPort(n.Host, strconv.Itoa(n.Port))
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return fmt.Errorf("dialing smtp %s: %w", addr, err)
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+
+	c, err := smtp.NewClient(conn, n.Host)
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("smtp handshake: %w", err)
+	}
+	defer c.Close()
+
+	if n.StartTLS {
+		if ok, _ := c.Extension("STARTTLS"); !ok {
+			return fmt.Errorf("smtp server %s does not support STARTTLS", addr)
+		}
+		tlsConfig := n.TLSConfig
+		if tlsConfig == nil {
+			tlsConfig = &tls.Config{ServerName: n.Host, MinVersion: tls.VersionTLS12}
+		}
+		if err := c.StartTLS(tlsConfig); err != nil {
+			return fmt.Errorf("smtp starttls: %w", err)
+		}
+	}
+
+	if n.Username != "" {
+		if ok, _ := c.Extension("AUTH"); !ok {
+			return fmt.Errorf("smtp server %s does not support AUTH", addr)
+		}
+		if err := c.Auth(smtp.PlainAuth("", n.Username, n.Password, n.Host)); err != nil {
+			return fmt.Errorf("smtp auth: %w", err)
+		}
+	}
+
+	if err := c.Mail(from.Address); err != nil {
+		return fmt.Errorf("smtp MAIL FROM: %w", err)
+	}
+	for _, rcpt := range msg.To {
+		if err := c.Rcpt(rcpt); err != nil {
+			return fmt.Errorf("smtp RCPT TO %s: %w", rcpt, err)
+		}
+	}
+	w, err := c.Data()
+	if err != nil {
+		return fmt.Errorf("smtp DATA: %w", err)
+	}
+	if _, err := w.Write(body); err != nil {
+		return fmt.Errorf("writing smtp body: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("smtp DATA: %w", err)
+	}
+	return c.Quit()
+}
+
+// buildEmailBody renders a multipart/alternative message with text and HTML parts.
+func buildEmailBody(from *mail.Address, msg EmailMessage, now time.Time) ([]byte, error) {
+	var buf bytes.Buffer
+	mw := multipart.NewWriter(&buf)
+
+	headers := []struct{ k, v string }{
+		{"From", from.String()},
+		{"To", strings.Join(msg.To, ", ")},
+		{"Subject", mime.QEncoding.Encode("utf-8", msg.Subject)},
+		{"Date", now.Format(time.RFC1123Z)},
+		{"Message-ID", messageID(from.Address)},
+		{"MIME-Version", "1.0"},
+		{"Content-Type", `multipart/alternative; boundary="` + mw.Boundary() + `"`},
+		{"X-Mailer", "SLB"},
+	}
+	var head bytes.Buffer
+	for _, h := range headers {
+		fmt.Fprintf(&head, "%s: %s\r\n", h.k, h.v)
+	}
+	head.WriteString("\r\n")
+
+	for _, part := range []struct{ ctype, body string }{
+		{"text/plain; charset=utf-8", msg.Text},
+		{"text/html; charset=utf-8", msg.HTML},
+	} {
+		if part.body == "" {
+			continue
+		}
+		pw, err := mw.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {part.ctype},
+			"Content-Transfer-Encoding": {"quoted-printable"},
+		})
+		if err != nil {
+			return nil, err
+		}
+		qp := quotedprintable.NewWriter(pw)
+		if _, err := qp.Write([]byte(part.body)); err != nil {
+			return nil, err
+		}
+		if err := qp.Close(); err != nil {
+			return nil, err
+		}
+	}
+	if err := mw.Close(); err != nil {
+		return nil, err
+	}
+
+	return append(head.Bytes(), buf.Bytes()...), nil
+}
+
+func messageID(from string) string {
+	domain := "slb.local"
+	if at := strings.LastIndex(from, "@"); at >= 0 && at < len(from)-1 {
+		domain = from[at+1:]
+	}
+	var b [12]byte
+	_, _ = rand.Read(b[:])
+	return "<" + hex.EncodeToString(b[:]) + "@" + domain + ">"
+}
+
+// emailRecipients returns the recipients for a tier, falling back to email_to.
+func emailRecipients(cfg config.NotificationsConfig, tier db.RiskTier) []string {
+	var list []string
+	switch tier {
+	case db.RiskTierCritical:
+		list = cfg.EmailCriticalTo
+	case db.RiskTierDangerous:
+		list = cfg.EmailDangerousTo
+	case db.RiskTierCaution:
+		list = cfg.EmailCautionTo
+	}
+	if len(list) == 0 {
+		list = cfg.EmailTo
+	}
+	out := make([]string, 0, len(list))
+	for _, addr := range list {
+		if addr = strings.TrimSpace(addr); addr != "" {
+			out = append(out, addr)
+		}
+	}
+	return out
+}
+
+// emailRequest is the template view of a request.
+type emailRequest struct {
+	ID            string
+	ShortID       string
+	Tier          string
+	Command       string
+	Requestor     string
+	Model         string
+	Project       string
+	Justification db.Justification
+	Created       string
+	ReviewCmd     string
+}
+
+func newEmailRequest(req *db.Request, project string) emailRequest {
+	return emailRequest{
+		ID:            req.ID,
+		ShortID:       shortID(req.ID),
+		Tier:          strings.ToUpper(string(req.RiskTier)),
+		Command:       displayCommand(req),
+		Requestor:     req.RequestorAgent,
+		Model:         req.RequestorModel,
+		Project:       project,
+		Justification: req.Justification,
+		Created:       req.CreatedAt.UTC().Format(time.RFC3339),
+		ReviewCmd:     "slb review " + req.ID,
+	}
+}
+
+var requestEmailText = texttemplate.Must(texttemplate.New("request").Parse(`{{.Tier}} request pending approval
+
+Command:   {{.Command}}
+Requestor: {{.Requestor}}{{if .Model}} ({{.Model}}){{end}}
+Project:   {{.Project}}
+Request:   {{.ID}}
+Created:   {{.Created}}
+
+Reason: {{.Justification.Reason}}
+{{- with .Justification.ExpectedEffect}}
+Expected effect: {{.}}{{end}}
+{{- with .Justification.Goal}}
+Goal: {{.}}{{end}}
+{{- with .Justification.SafetyArgument}}
+Safety: {{.}}{{end}}
+
+Review it with:
+
+    {{.ReviewCmd}}
+`))
+
+var requestEmailHTML = htmltemplate.Must(htmltemplate.New("request").Parse(`<!doctype html>
+<html><body style="font-family:sans-serif;font-size:14px">
+<p><strong>{{.Tier}}</strong> request pending approval</p>
+<pre style="background:#f4f4f4;padding:8px;white-space:pre-wrap">{{.Command}}</pre>
+<table cellpadding="2">
+<tr><td>Requestor</td><td>{{.Requestor}}{{if .Model}} ({{.Model}}){{end}}</td></tr>
+<tr><td>Project</td><td>{{.Project}}</td></tr>
+<tr><td>Request</td><td><code>{{.ID}}</code></td></tr>
+<tr><td>Created</td><td>{{.Created}}</td></tr>
+<tr><td>Reason</td><td>{{.Justification.Reason}}</td></tr>
+{{- with .Justification.ExpectedEffect}}<tr><td>Expected effect</td><td>{{.}}</td></tr>{{end}}
+{{- with .Justification.Goal}}<tr><td>Goal</td><td>{{.}}</td></tr>{{end}}
+{{- with .Justification.SafetyArgument}}<tr><td>Safety</td><td>{{.}}</td></tr>{{end}}
+</table>
+<p>Review it with:</p>
+<pre style="background:#f4f4f4;padding:8px">{{.ReviewCmd}}</pre>
+</body></html>
+`))
+
+var digestEmailText = texttemplate.Must(texttemplate.New("digest").Parse(`{{len .Requests}} CAUTION request(s) pending in {{.Project}}
+{{range .Requests}}
+- {{.Command}}
+  {{.Requestor}} · {{.Justification.Reason}}
+  {{.ReviewCmd}}
+{{end}}`))
+
+var digestEmailHTML = htmltemplate.Must(htmltemplate.New("digest").Parse(`<!doctype html>
+<html><body style="font-family:sans-serif;font-size:14px">
+<p>{{len .Requests}} CAUTION request(s) pending in <code>{{.Project}}</code></p>
+<table cellpadding="4" style="border-collapse:collapse">
+<tr><th align="left">Command</th><th align="left">Requestor</th><th align="left">Reason</th><th align="left">Review</th></tr>
+{{- range .Requests}}
+<tr><td><code>{{.Command}}</code></td><td>{{.Requestor}}</td><td>{{.Justification.Reason}}</td><td><code>{{.ReviewCmd}}</code></td></tr>
+{{- end}}
+</table>
+</body></html>
+`))
+
+// renderRequestEmail renders the email for a single pending request.
+func renderRequestEmail(req *db.Request, project string, to []string) (EmailMessage, error) {
+	view := newEmailRequest(req, project)
+	msg := EmailMessage{
+		To:      to,
+		Subject: fmt.Sprintf("[SLB] %s request pending: %s", view.Tier, truncateRunes(view.Command, 60)),
+	}
+	var text, html bytes.Buffer
+	if err := requestEmailText.Execute(&text, view); err != nil {
+		return msg, err
+	}
+	if err := requestEmailHTML.Execute(&html, view); err != nil {
+		return msg, err
+	}
+	msg.Text, msg.HTML = text.String(), html.String()
+	return msg, nil
+}
+
+// renderDigestEmail renders a digest of pending CAUTION requests.
+func renderDigestEmail(reqs []*db.Request, project string, to []string) (EmailMessage, error) {
+	view := struct {
+		Project  string
+		Requests []emailRequest
+	}{Project: project}
+	for _, req := range reqs {
+		view.Requests = append(view.Requests, newEmailRequest(req, project))
+	}
+	msg := EmailMessage{
+		To:      to,
+		Subject: fmt.Sprintf("[SLB] %d CAUTION request(s) pending", len(reqs)),
+	}
+	var text, html bytes.Buffer
+	if err := digestEmailText.Execute(&text, view); err != nil {
+		return msg, err
+	}
+	if err := digestEmailHTML.Execute(&html, view); err != nil {
+		return msg, err
+	}
+	msg.Text, msg.HTML = text.String(), html.String()
+	return msg, nil
+}
+
+func truncateRunes(s string, n int) string {
+	r := []rune(strings.TrimSpace(s))
+	if len(r) <= n {
+		return string(r)
+	}
+	return string(r[:n]) + "…"
+}
@@ -0,0 +1,368 @@
+package daemon
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"encoding/base64"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net"
+	"net/mail"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Dicklesworthstone/slb/internal/config"
+	"github.com/Dicklesworthstone/slb/internal/db"
+)
+
+// smtpStandIn is a minimal SMTP server that records delivered messages.
+type smtpStandIn struct {
+	addr      string
+	tlsConfig *tls.Config // non-nil advertises STARTTLS
+
+	mu       sync.Mutex
+	messages []smtpDelivery
+	authUser string
+	usedTLS  bool
+}
+
+type smtpDelivery struct {
+	From string
+	To   []string
+	Data string
+}
+
+func startSMTPStandIn(t *testing.T, tlsConfig *tls.Config) *smtpStandIn {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	t.Cleanup(func() { ln.Close() })
+
+	s := &smtpStandIn{addr: ln.Addr().String(), tlsConfig: tlsConfig}
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go s.serve(conn)
+		}
+	}()
+	return s
+}
+
+func (s *smtpStandIn) serve(conn net.Conn) {
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+	w := bufio.NewWriter(conn)
+	reply := func(line string) {
+		w.WriteString(line + "\r\n")
+		w.Flush()
+	}
+
+	var cur smtpDelivery
+	reply("220 localhost ESMTP stand-in")
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+		line = strings.TrimRight(line, "\r\n")
+		verb := strings.ToUpper(strings.SplitN(line, " ", 2)[0])
+		switch verb {
+		case "EHLO", "HELO":
+			w.WriteString("250-localhost\r\n")
+			if s.tlsConfig != nil {
+				w.WriteString("250-STARTTLS\r\n")
+			}
+			reply("250 AUTH PLAIN")
+		case "STARTTLS":
+			reply("220 ready")
+			tlsConn := tls.Server(conn, s.tlsConfig)
+			if err := tlsConn.Handshake(); err != nil {
+				return
+			}
+			conn = tlsConn
+			r = bufio.NewReader(conn)
+			w = bufio.NewWriter(conn)
+			s.mu.Lock()
+			s.usedTLS = true
+			s.mu.Unlock()
+		case "AUTH":
+			parts := strings.Fields(line)
+			if len(parts) == 3 {
+				raw, _ := base64.StdEncoding.DecodeString(parts[2])
+				fields := strings.Split(string(raw), "\x00")
+				if len(fields) == 3 {
+					s.mu.Lock()
+					s.authUser = fields[1]
+					s.mu.Unlock()
+				}
+			}
+			reply("235 ok")
+		case "MAIL":
+			cur = smtpDelivery{From: strings.Trim(strings.TrimPrefix(line[5:], "FROM:"), "<> ")}
+			reply("250 ok")
+		case "RCPT":
+			cur.To = append(cur.To, strings.Trim(strings.TrimPrefix(line[5:], "TO:"), "<> "))
+			reply("250 ok")
+		case "DATA":
+			reply("354 go ahead")
+			var data strings.Builder
+			for {
+				l, err := r.ReadString('\n')
+				if err != nil {
+					return
+				}
+				if l == ".\r\n" {
+					break
+				}
+				data.WriteString(strings.TrimPrefix(l, "."))
+			}
+			cur.Data = data.String()
+			s.mu.Lock()
+			s.messages = append(s.messages, cur)
+			s.mu.Unlock()
+			reply("250 queued")
+		case "QUIT":
+			reply("221 bye")
+			return
+		default:
+			reply("250 ok")
+		}
+	}
+}
+
+func (s *smtpStandIn) deliveries() []smtpDelivery {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]smtpDelivery(nil), s.messages...)
+}
+
+func (s *smtpStandIn) notifier(t *testing.T) *SMTPNotifier {
+	t.Helper()
+	host, port, _ := net.SplitHostPort(s.addr)
+	n := NewSMTPNotifier(config.NotificationsConfig{
+		EmailSMTPHost: host,
+		EmailFrom:     "SLB <slb@example.com>",
+	})
+	n.Port, _ = strconv.Atoi(port)
+	return n
+}
+
+// emailParts parses a delivered message into its text and HTML bodies.
+func emailParts(t *testing.T, data string) (subject, text, html string) {
+	t.Helper()
+	msg, err := mail.ReadMessage(strings.NewReader(data))
+	if err != nil {
+		t.Fatalf("parse message: %v", err)
+	}
+	subject, _ = new(mime.WordDecoder).DecodeHeader(msg.Header.Get("Subject"))
+	_, params, err := mime.ParseMediaType(msg.Header.Get("Content-Type"))
+	if err != nil {
+		t.Fatalf("content type: %v", err)
+	}
+	mr := multipart.NewReader(msg.Body, params["boundary"])
+	for {
+		p, err := mr.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("next part: %v", err)
+		}
+		b, _ := io.ReadAll(p) // multipart.Reader decodes quoted-printable
+		if strings.HasPrefix(p.Header.Get("Content-Type"), "text/html") {
+			html = string(b)
+		} else {
+			text = string(b)
+		}
+	}
+	return subject, text, html
+}
+
+func TestSMTPNotifier_SendPlainAuth(t *testing.T) {
+	srv := startSMTPStandIn(t, nil)
+	n := srv.notifier(t)
+	n.Username = "lead"
+	n.Password = "secret"
+
+	msg := EmailMessage{To: []string{"a@example.com", "b@example.com"}, Subject: "hello", Text: "plain body", HTML: "<p>html body</p>"}
+	if err := n.SendEmail(context.Background(), msg); err != nil {
+		t.Fatalf("SendEmail: %v", err)
+	}
+
+	got := srv.deliveries()
+	if len(got) != 1 {
+		t.Fatalf("expected 1 delivery, got %d", len(got))
+	}
+	if got[0].From != "slb@example.com" || len(got[0].To) != 2 {
+		t.Fatalf("unexpected envelope: %+v", got[0])
+	}
+	srv.mu.Lock()
+	authUser := srv.authUser
+	srv.mu.Unlock()
+	if authUser != "lead" {
+		t.Fatalf("expected AUTH as lead, got %q", authUser)
+	}
+	subject, text, html := emailParts(t, got[0].Data)
+	if subject != "hello" || !strings.Contains(text, "plain body") || !strings.Contains(html, "html body") {
+		t.Fatalf("unexpected message: %q %q %q", subject, text, html)
+	}
+}
+
+func TestSMTPNotifier_StartTLS(t *testing.T) {
+	dir := newTestPKI(t)
+	cert, err := tls.LoadX509KeyPair(filepath.Join(dir, "server.pem"), filepath.Join(dir, "server-key.pem"))
+	if err != nil {
+		t.Fatalf("load cert: %v", err)
+	}
+
+	plain := startSMTPStandIn(t, nil)
+	n := plain.notifier(t)
+	n.StartTLS = true
+	if err := n.SendEmail(context.Background(), EmailMessage{To: []string{"a@example.com"}, Text: "x"}); err == nil || !strings.Contains(err.Error(), "STARTTLS") {
+		t.Fatalf("expected STARTTLS error from server without it, got %v", err)
+	}
+
+	secure := startSMTPStandIn(t, &tls.Config{Certificates: []tls.Certificate{cert}})
+	n = secure.notifier(t)
+	n.StartTLS = true
+	n.TLSConfig, err = ClientTLSConfig(filepath.Join(dir, "ca.pem"), "", "", "127.0.0.1")
+	if err != nil {
+		t.Fatalf("ClientTLSConfig: %v", err)
+	}
+	if err := n.SendEmail(context.Background(), EmailMessage{To: []string{"a@example.com"}, Text: "x"}); err != nil {
+		t.Fatalf("SendEmail over STARTTLS: %v", err)
+	}
+	secure.mu.Lock()
+	usedTLS := secure.usedTLS
+	secure.mu.Unlock()
+	if !usedTLS || len(secure.deliveries()) != 1 {
+		t.Fatalf("expected one delivery over TLS")
+	}
+}
+
+func TestEmailRecipients_TierFallback(t *testing.T) {
+	cfg := config.NotificationsConfig{
+		EmailTo:         []string{"team@example.com"},
+		EmailCriticalTo: []string{"oncall@example.com", " "},
+	}
+	if got := emailRecipients(cfg, db.RiskTierCritical); len(got) != 1 || got[0] != "oncall@example.com" {
+		t.Fatalf("critical recipients = %v", got)
+	}
+	if got := emailRecipients(cfg, db.RiskTierDangerous); len(got) != 1 || got[0] != "team@example.com" {
+		t.Fatalf("dangerous recipients = %v", got)
+	}
+}
+
+func TestRenderRequestEmail_RedactsAndEscapes(t *testing.T) {
+	req := &db.Request{
+		ID:             "req-1234567890",
+		RiskTier:       db.RiskTierCritical,
+		Command:        db.CommandSpec{Raw: "curl -H 'token: abc' <x>", DisplayRedacted: "curl -H 'token: [REDACTED]' <x>"},
+		RequestorAgent: "BlueLake",
+		Justification:  db.Justification{Reason: "deploy <now>", Goal: "ship"},
+		CreatedAt:      time.Now(),
+	}
+	msg, err := renderRequestEmail(req, "/proj", []string{"a@example.com"})
+	if err != nil {
+		t.Fatalf("render: %v", err)
+	}
+	if strings.Contains(msg.Text, "abc") || strings.Contains(msg.HTML, "abc") {
+		t.Fatalf("email leaked unredacted command")
+	}
+	if !strings.Contains(msg.Text, "slb review req-1234567890") || !strings.Contains(msg.HTML, "slb review req-1234567890") {
+		t.Fatalf("email missing review command line")
+	}
+	if !strings.Contains(msg.HTML, "deploy &lt;now&gt;") {
+		t.Fatalf("html body not escaped: %s", msg.HTML)
+	}
+	if !strings.Contains(msg.Text, "Goal: ship") || !strings.HasPrefix(msg.Subject, "[SLB] CRITICAL") {
+		t.Fatalf("unexpected text/subject: %q / %q", msg.Text, msg.Subject)
+	}
+}
+
+func TestNotificationManager_EmailAndCautionDigest(t *testing.T) {
+	project := t.TempDir()
+	dbConn, err := db.OpenProjectDB(project)
+	if err != nil {
+		t.Fatalf("open project db: %v", err)
+	}
+	t.Cleanup(func() { _ = dbConn.Close() })
+
+	if err := dbConn.CreateSession(&db.Session{ID: "s1", AgentName: "AgentA", Program: "test", Model: "model", ProjectPath: project}); err != nil {
+		t.Fatalf("create session: %v", err)
+	}
+	newReq := func(tier db.RiskTier, cmd string) *db.Request {
+		req := &db.Request{
+			ProjectPath:        project,
+			Command:            db.CommandSpec{Raw: cmd, Cwd: project},
+			RiskTier:           tier,
+			RequestorSessionID: "s1",
+			RequestorAgent:     "AgentA",
+			RequestorModel:     "model",
+			Justification:      db.Justification{Reason: "test"},
+			MinApprovals:       1,
+		}
+		if err := dbConn.CreateRequest(req); err != nil {
+			t.Fatalf("create request: %v", err)
+		}
+		return req
+	}
+	newReq(db.RiskTierCritical, "rm -rf /data")
+	newReq(db.RiskTierCaution, "git stash drop")
+	newReq(db.RiskTierCaution, "npm uninstall left-pad")
+
+	srv := startSMTPStandIn(t, nil)
+	cfg := config.NotificationsConfig{
+		EmailEnabled:           true,
+		EmailTo:                []string{"team@example.com"},
+		EmailCriticalTo:        []string{"oncall@example.com"},
+		EmailCautionDigestMins: 30,
+	}
+	clock := time.Now().Add(time.Minute)
+	manager := NewNotificationManager(project, cfg, nil, DesktopNotifierFunc(func(string, string) error { return nil })).
+		WithEmail(srv.notifier(t))
+	manager.now = func() time.Time { return clock }
+
+	if err := manager.Check(context.Background()); err != nil {
+		t.Fatalf("check: %v", err)
+	}
+	got := srv.deliveries()
+	if len(got) != 1 || got[0].To[0] != "oncall@example.com" {
+		t.Fatalf("expected only the critical email, got %+v", got)
+	}
+
+	// Digest is held until the interval elapses, then sent once.
+	clock = clock.Add(31 * time.Minute)
+	if err := manager.Check(context.Background()); err != nil {
+		t.Fatalf("check: %v", err)
+	}
+	got = srv.deliveries()
+	if len(got) != 2 {
+		t.Fatalf("expected digest email, got %d deliveries", len(got))
+	}
+	subject, text, _ := emailParts(t, got[1].Data)
+	if got[1].To[0] != "team@example.com" || !strings.Contains(subject, "2 CAUTION") {
+		t.Fatalf("unexpected digest: to=%v subject=%q", got[1].To, subject)
+	}
+	if !strings.Contains(text, "git stash drop") || !strings.Contains(text, "npm uninstall left-pad") {
+		t.Fatalf("digest missing requests: %s", text)
+	}
+
+	if err := manager.Check(context.Background()); err != nil {
+		t.Fatalf("check: %v", err)
+	}
+	if len(srv.deliveries()) != 2 {
+		t.Fatalf("expected no repeat emails")
+	}
+}
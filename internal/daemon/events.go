@@ -0,0 +1,180 @@
+package daemon
+
+import (
+	"context"
+	"errors"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/Dicklesworthstone/slb/internal/db"
+)
+
+// Request lifecycle events published to daemon subscribers.
+const (
+	EventRequestPending   = "request_pending"
+	EventRequestApproved  = "request_approved"
+	EventRequestRejected  = "request_rejected"
+	EventRequestExecuted  = "request_executed"
+	EventRequestCancelled = "request_cancelled"
+	// EventReviewSubmitted is sent for a review that did not resolve the request.
+	EventReviewSubmitted = "review_submitted"
+	// EventExecutionOutput carries a chunk of an executing command's output.
+	EventExecutionOutput = "execution_output"
+	// EventExecutionAborted is sent when an approver aborts an execution.
+	EventExecutionAborted = "execution_aborted"
+	// EventFreezeStarted is sent when a change freeze starts.
+	EventFreezeStarted = "freeze_started"
+	// EventFreezeEnded is sent when a change freeze is ended.
+	EventFreezeEnded = "freeze_ended"
+)
+
+// eventPublishTimeout bounds how long a CLI command may spend publishing.
+const eventPublishTimeout = 500 * time.Millisecond
+
+// EventNotifier publishes request lifecycle events to the running daemon so
+// subscribers (slb watch, the TUI) see them immediately. It satisfies
+// integrations.RequestNotifier. Publishing is best effort: when no daemon is
+// running the calls return nil without waiting.
+type EventNotifier struct {
+	socketPath string
+	timeout    time.Duration
+}
+
+// NewEventNotifier creates a notifier for the daemon listening on socketPath.
+func NewEventNotifier(socketPath string) *EventNotifier {
+	return &EventNotifier{socketPath: socketPath, timeout: eventPublishTimeout}
+}
+
+// NotifyNewRequest publishes request_pending.
+func (n *EventNotifier) NotifyNewRequest(req *db.Request) error {
+	if req == nil || req.Status != db.StatusPending {
+		return nil
+	}
+	return n.Publish(EventRequestPending, RequestEventPayload(req, nil))
+}
+
+// NotifyRequestApproved publishes request_approved once the request is
+// approved, or review_submitted while it still needs more approvals.
+func (n *EventNotifier) NotifyRequestApproved(req *db.Request, review *db.Review) error {
+	return n.publishReview(EventRequestApproved, db.StatusApproved, "approved_by", req, review)
+}
+
+// NotifyRequestRejected publishes request_rejected once the request is
+// rejected, or review_submitted otherwise.
+func (n *EventNotifier) NotifyRequestRejected(req *db.Request, review *db.Review) error {
+	return n.publishReview(EventRequestRejected, db.StatusRejected, "rejected_by", req, review)
+}
+
+func (n *EventNotifier) publishReview(event string, resolved db.RequestStatus, byKey string, req *db.Request, review *db.Review) error {
+	payload := RequestEventPayload(req, nil)
+	if review != nil {
+		payload[byKey] = review.ReviewerAgent
+		payload["decision"] = string(review.Decision)
+		payload["reason"] = review.Comments
+	}
+	if req == nil || req.Status != resolved {
+		event = EventReviewSubmitted
+	}
+	return n.Publish(event, payload)
+}
+
+// NotifyRequestExecuted publishes request_executed.
+func (n *EventNotifier) NotifyRequestExecuted(req *db.Request, exec *db.Execution, exitCode int) error {
+	payload := RequestEventPayload(req, map[string]any{"exit_code": exitCode})
+	if exec != nil && exec.ExecutedByAgent != "" {
+		payload["executed_by"] = exec.ExecutedByAgent
+	}
+	return n.Publish(EventRequestExecuted, payload)
+}
+
+// NotifyRequestCancelled publishes request_cancelled.
+func (n *EventNotifier) NotifyRequestCancelled(req *db.Request) error {
+	return n.Publish(EventRequestCancelled, RequestEventPayload(req, nil))
+}
+
+// NotifyExecutionAborted publishes execution_aborted.
+func (n *EventNotifier) NotifyExecutionAborted(req *db.Request, abort *db.ExecutionAbort) error {
+	payload := RequestEventPayload(req, nil)
+	if abort != nil {
+		payload["aborted_by"] = abort.Agent
+		payload["reason"] = abort.Reason
+	}
+	return n.Publish(EventExecutionAborted, payload)
+}
+
+// NotifyFreezeStarted publishes freeze_started.
+func (n *EventNotifier) NotifyFreezeStarted(f *db.Freeze) error {
+	payload := FreezeEventPayload(f)
+	payload["frozen_by"] = f.StartedBy
+	return n.Publish(EventFreezeStarted, payload)
+}
+
+// NotifyFreezeEnded publishes freeze_ended.
+func (n *EventNotifier) NotifyFreezeEnded(f *db.Freeze) error {
+	payload := FreezeEventPayload(f)
+	payload["ended_by"] = f.EndedBy
+	return n.Publish(EventFreezeEnded, payload)
+}
+
+// Publish sends an arbitrary event to the daemon.
+func (n *EventNotifier) Publish(eventType string, payload any) error {
+	if n == nil {
+		return nil
+	}
+	if strings.TrimSpace(os.Getenv("SLB_HOST")) == "" {
+		if _, err := os.Stat(n.socketPath); errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), n.timeout)
+	defer cancel()
+
+	client := NewIPCClient(n.socketPath)
+	defer client.Close()
+	if err := client.Connect(ctx); err != nil {
+		return err
+	}
+	client.mu.Lock()
+	_ = client.conn.SetDeadline(time.Now().Add(n.timeout))
+	client.mu.Unlock()
+
+	return client.Notify(ctx, eventType, payload)
+}
+
+// RequestEventPayload builds the payload shared by request events. The keys
+// match those read by ToRequestStreamEvent.
+func RequestEventPayload(req *db.Request, extra map[string]any) map[string]any {
+	payload := map[string]any{}
+	if req != nil {
+		cmd := req.Command.DisplayRedacted
+		if cmd == "" {
+			cmd = req.Command.Raw
+		}
+		payload["request_id"] = req.ID
+		payload["risk_tier"] = string(req.RiskTier)
+		payload["command"] = cmd
+		payload["requestor"] = req.RequestorAgent
+		payload["status"] = string(req.Status)
+		payload["project_path"] = req.ProjectPath
+	}
+	for k, v := range extra {
+		payload[k] = v
+	}
+	return payload
+}
+
+// FreezeEventPayload builds the payload shared by freeze events.
+func FreezeEventPayload(f *db.Freeze) map[string]any {
+	payload := map[string]any{
+		"freeze_id":    f.ID,
+		"risk_tier":    string(f.Tier),
+		"reason":       f.Reason,
+		"project_path": f.ProjectPath,
+	}
+	if f.Until != nil {
+		payload["until"] = f.Until.Format(time.RFC3339)
+	}
+	return payload
+}
@@ -0,0 +1,89 @@
+package daemon
+
+import (
+	"context"
+	"io"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/Dicklesworthstone/slb/internal/db"
+	"github.com/charmbracelet/log"
+)
+
+func TestEventNotifier_NoDaemonIsNoop(t *testing.T) {
+	t.Setenv("SLB_HOST", "")
+	n := NewEventNotifier(filepath.Join(t.TempDir(), "missing.sock"))
+	start := time.Now()
+	if err := n.NotifyNewRequest(&db.Request{ID: "r1", Status: db.StatusPending}); err != nil {
+		t.Fatalf("expected nil error without daemon, got %v", err)
+	}
+	if time.Since(start) > 100*time.Millisecond {
+		t.Fatalf("publishing without a daemon should not wait")
+	}
+}
+
+func TestEventNotifier_PublishesToSubscribers(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("unix socket tests not supported on windows")
+	}
+	t.Setenv("SLB_HOST", "")
+
+	socketPath := filepath.Join(shortSocketDir(t), "t.sock")
+	srv, err := NewIPCServer(socketPath, log.New(io.Discard))
+	if err != nil {
+		t.Fatalf("NewIPCServer: %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() { _ = srv.Start(ctx) }()
+	defer srv.Stop()
+	time.Sleep(50 * time.Millisecond)
+
+	events, _, unsubscribe := srv.SubscribeEvents()
+	defer unsubscribe()
+
+	req := &db.Request{
+		ID:             "req-1",
+		ProjectPath:    "/p",
+		RiskTier:       db.RiskTierCritical,
+		Command:        db.CommandSpec{Raw: "rm -rf /tmp/x", DisplayRedacted: "rm -rf <redacted>"},
+		RequestorAgent: "BlueLake",
+		Status:         db.StatusPending,
+	}
+	review := &db.Review{ReviewerAgent: "GreenHill", Decision: db.DecisionApprove}
+
+	n := NewEventNotifier(socketPath)
+	if err := n.NotifyNewRequest(req); err != nil {
+		t.Fatalf("NotifyNewRequest: %v", err)
+	}
+	// Still pending after one approval: a review event, not an approval.
+	if err := n.NotifyRequestApproved(req, review); err != nil {
+		t.Fatalf("NotifyRequestApproved: %v", err)
+	}
+	req.Status = db.StatusApproved
+	if err := n.NotifyRequestApproved(req, review); err != nil {
+		t.Fatalf("NotifyRequestApproved: %v", err)
+	}
+
+	var got []*RequestStreamEvent
+	for len(got) < 3 {
+		select {
+		case ev := <-events:
+			got = append(got, ToRequestStreamEvent(ev))
+		case <-time.After(2 * time.Second):
+			t.Fatalf("timed out waiting for events, got %d", len(got))
+		}
+	}
+
+	if got[0].Event != EventRequestPending || got[0].Command != "rm -rf <redacted>" || got[0].RiskTier != "critical" {
+		t.Errorf("unexpected pending event: %+v", got[0])
+	}
+	if got[1].Event != EventReviewSubmitted {
+		t.Errorf("expected review_submitted, got %s", got[1].Event)
+	}
+	if got[2].Event != EventRequestApproved || got[2].ApprovedBy != "GreenHill" {
+		t.Errorf("unexpected approval event: %+v", got[2])
+	}
+}
@@ -0,0 +1,667 @@
+package daemon
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Dicklesworthstone/slb/internal/core"
+	"github.com/Dicklesworthstone/slb/internal/db"
+	"github.com/charmbracelet/log"
+)
+
+// sseKeepAlive is how often an idle event stream sends a comment line so proxies
+// do not close the connection.
+const sseKeepAlive = 15 * time.Second
+
+// HTTPServerOptions configures the optional HTTP/JSON gateway.
+//
+// The gateway shares the TCP listener's auth model: an optional IP allowlist,
+// then either a verified client certificate principal or a session key passed as
+// "Authorization: Bearer <session_key>".
+type HTTPServerOptions struct {
+	Addr        string
+	RequireAuth bool
+	AllowedIPs  []string
+	TLSConfig   *tls.Config
+
+	ValidateAuth      func(ctx context.Context, sessionKey string) (bool, error)
+	ValidatePrincipal func(ctx context.Context, principal string) (bool, error)
+
+	// ProjectPath scopes request listings to a project.
+	ProjectPath string
+	// OpenDB opens the project database; the gateway closes it after each call.
+	OpenDB func() (*db.DB, error)
+	// Events is the IPC server whose subscribe stream is mirrored over SSE.
+	// Reviews submitted through the gateway are also broadcast on it.
+	Events *IPCServer
+	// ReviewConfig is used when reviews are submitted through the gateway.
+	ReviewConfig core.ReviewConfig
+	// SavedQueries are the history queries available as @name.
+	SavedQueries map[string]string
+}
+
+// HTTPServer serves the REST and Server-Sent Events gateway.
+type HTTPServer struct {
+	opts     HTTPServerOptions
+	auth     *listenerAuth
+	listener net.Listener
+	server   *http.Server
+	logger   *log.Logger
+}
+
+// NewHTTPServer creates the gateway and binds its listener.
+func NewHTTPServer(opts HTTPServerOptions, logger *log.Logger) (*HTTPServer, error) {
+	if logger == nil {
+		logger = log.Default()
+	}
+	addr := strings.TrimSpace(opts.Addr)
+	if addr == "" {
+		return nil, fmt.Errorf("http addr is required")
+	}
+	if opts.OpenDB == nil {
+		return nil, fmt.Errorf("http gateway requires a database opener")
+	}
+
+	auth, err := newListenerAuth(opts.RequireAuth, opts.AllowedIPs, opts.ValidateAuth, opts.ValidatePrincipal)
+	if err != nil {
+		return nil, err
+	}
+
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("listen http %s: %w", addr, err)
+	}
+	if opts.TLSConfig != nil {
+		ln = tls.NewListener(ln, opts.TLSConfig)
+	}
+
+	s := &HTTPServer{
+		opts:     opts,
+		auth:     auth,
+		listener: ln,
+		logger:   logger,
+	}
+	s.server = &http.Server{
+		Handler:           s.Handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	return s, nil
+}
+
+// Addr returns the bound listener address.
+func (s *HTTPServer) Addr() string {
+	return s.listener.Addr().String()
+}
+
+// Handler returns the gateway's HTTP handler: the API routes plus the
+// embedded web dashboard at /.
+func (s *HTTPServer) Handler() http.Handler {
+	mux := http.NewServeMux()
+	for _, rt := range httpRoutes {
+		h := rt.handler(s)
+		if rt.Auth {
+			h = s.requireAuth(h)
+		}
+		mux.HandleFunc(rt.Method+" "+rt.Path, h)
+	}
+	mux.Handle("GET /", webHandler())
+	return mux
+}
+
+// Start serves until ctx is cancelled or Stop is called.
+func (s *HTTPServer) Start(ctx context.Context) error {
+	s.logger.Info("http gateway started", "addr", s.Addr())
+
+	go func() {
+		<-ctx.Done()
+		_ = s.Stop()
+	}()
+
+	err := s.server.Serve(s.listener)
+	if errors.Is(err, http.ErrServerClosed) {
+		return nil
+	}
+	return err
+}
+
+// Stop shuts the gateway down, closing open event streams.
+func (s *HTTPServer) Stop() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := s.server.Shutdown(ctx); err != nil {
+		return s.server.Close()
+	}
+	return nil
+}
+
+func (s *HTTPServer) requireAuth(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ip, err := extractRemoteIP(remoteAddr(r.RemoteAddr))
+		if err != nil {
+			writeHTTPError(w, http.StatusForbidden, err.Error())
+			return
+		}
+		if err := s.auth.checkIP(ip); err != nil {
+			writeHTTPError(w, http.StatusForbidden, err.Error())
+			return
+		}
+
+		principal := ""
+		if r.TLS != nil {
+			principal = peerPrincipal(*r.TLS)
+		}
+		if err := s.auth.authenticate(principal, bearerToken(r)); err != nil {
+			writeHTTPError(w, http.StatusUnauthorized, err.Error())
+			return
+		}
+		next(w, r)
+	}
+}
+
+// remoteAddr adapts http.Request.RemoteAddr to net.Addr for extractRemoteIP.
+type remoteAddr string
+
+func (a remoteAddr) Network() string { return "tcp" }
+func (a remoteAddr) String() string  { return string(a) }
+
+func bearerToken(r *http.Request) string {
+	h := strings.TrimSpace(r.Header.Get("Authorization"))
+	if len(h) > 7 && strings.EqualFold(h[:7], "bearer ") {
+		return strings.TrimSpace(h[7:])
+	}
+	return ""
+}
+
+// HTTPError is the JSON body returned for failed gateway calls.
+type HTTPError struct {
+	Error string `json:"error"`
+}
+
+func writeHTTPError(w http.ResponseWriter, status int, msg string) {
+	writeHTTPJSON(w, status, HTTPError{Error: msg})
+}
+
+func writeHTTPJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// withDB opens the project database for a single handler invocation.
+func (s *HTTPServer) withDB(w http.ResponseWriter, fn func(*db.DB)) {
+	dbConn, err := s.opts.OpenDB()
+	if err != nil {
+		writeHTTPError(w, http.StatusServiceUnavailable, "database unavailable: "+err.Error())
+		return
+	}
+	defer dbConn.Close()
+	fn(dbConn)
+}
+
+// loadRequest fetches the request named by the {id} path value, writing a 404 when missing.
+func loadRequest(w http.ResponseWriter, r *http.Request, dbConn *db.DB) (*db.Request, bool) {
+	req, err := dbConn.GetRequest(r.PathValue("id"))
+	if err != nil {
+		if errors.Is(err, db.ErrRequestNotFound) {
+			writeHTTPError(w, http.StatusNotFound, "request not found")
+		} else {
+			writeHTTPError(w, http.StatusInternalServerError, err.Error())
+		}
+		return nil, false
+	}
+	return req, true
+}
+
+// Gateway response and input types. These also drive the generated OpenAPI schemas.
+type (
+	// HTTPHealth is returned by GET /v1/health.
+	HTTPHealth struct {
+		OK            bool  `json:"ok"`
+		UptimeSeconds int64 `json:"uptime_seconds"`
+		Subscribers   int   `json:"subscribers"`
+	}
+
+	// HTTPRequestDetail is a request with its reviews.
+	HTTPRequestDetail struct {
+		Request    *db.Request  `json:"request"`
+		Reviews    []*db.Review `json:"reviews"`
+		Approvals  int          `json:"approvals"`
+		Rejections int          `json:"rejections"`
+	}
+
+	// HTTPOperator identifies the caller of the gateway: the session owning
+	// the bearer key and/or the verified client certificate principal.
+	HTTPOperator struct {
+		Session   *db.Session `json:"session,omitempty"`
+		Principal string      `json:"principal,omitempty"`
+	}
+
+	// HTTPReviewInput is the body of POST /v1/requests/{id}/reviews. The session
+	// key used for signing is the bearer token; SessionID defaults to the
+	// session owning that key.
+	HTTPReviewInput struct {
+		SessionID string            `json:"session_id,omitempty"`
+		Decision  string            `json:"decision"`
+		Responses db.ReviewResponse `json:"responses,omitempty"`
+		Comments  string            `json:"comments,omitempty"`
+		// RequireSandbox makes an approval conditional on sandboxed execution.
+		RequireSandbox bool `json:"require_sandbox,omitempty"`
+	}
+
+	// HTTPReviewResult is returned after a review is recorded.
+	HTTPReviewResult struct {
+		Review           *db.Review `json:"review"`
+		Approvals        int        `json:"approvals"`
+		Rejections       int        `json:"rejections"`
+		NewRequestStatus string     `json:"new_request_status,omitempty"`
+	}
+
+	// HTTPExecutionStatus reports execution progress and outcome for a request.
+	HTTPExecutionStatus struct {
+		RequestID string               `json:"request_id"`
+		Status    string               `json:"status"`
+		Execution *db.Execution        `json:"execution,omitempty"`
+		Outcome   *db.ExecutionOutcome `json:"outcome,omitempty"`
+	}
+)
+
+func (s *HTTPServer) handleHealth(w http.ResponseWriter, r *http.Request) {
+	resp := HTTPHealth{OK: true}
+	if s.opts.Events != nil {
+		s.opts.Events.subscribersMu.RLock()
+		resp.Subscribers = len(s.opts.Events.subscribers)
+		s.opts.Events.subscribersMu.RUnlock()
+		resp.UptimeSeconds = int64(time.Since(s.opts.Events.startTime).Seconds())
+	}
+	writeHTTPJSON(w, http.StatusOK, resp)
+}
+
+func (s *HTTPServer) handleOperator(w http.ResponseWriter, r *http.Request) {
+	var op HTTPOperator
+	if r.TLS != nil {
+		op.Principal = peerPrincipal(*r.TLS)
+	}
+	key := bearerToken(r)
+	if key == "" {
+		writeHTTPJSON(w, http.StatusOK, op)
+		return
+	}
+	s.withDB(w, func(dbConn *db.DB) {
+		sess, err := dbConn.GetActiveSessionByKey(key)
+		if err != nil && !errors.Is(err, db.ErrSessionNotFound) {
+			writeHTTPError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		op.Session = sess
+		writeHTTPJSON(w, http.StatusOK, op)
+	})
+}
+
+func (s *HTTPServer) handleListRequests(w http.ResponseWriter, r *http.Request) {
+	status := strings.ToLower(strings.TrimSpace(r.URL.Query().Get("status")))
+	tier := strings.ToLower(strings.TrimSpace(r.URL.Query().Get("tier")))
+
+	s.withDB(w, func(dbConn *db.DB) {
+		var (
+			reqs []*db.Request
+			err  error
+		)
+		switch status {
+		case "", string(db.StatusPending):
+			reqs, err = dbConn.ListPendingRequests(s.opts.ProjectPath)
+		case "all":
+			reqs, err = dbConn.ListAllRequests(s.opts.ProjectPath)
+		default:
+			reqs, err = dbConn.ListRequestsByStatus(db.RequestStatus(status), s.opts.ProjectPath)
+		}
+		if err != nil {
+			writeHTTPError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+
+		reviewer := isReviewer(r, dbConn)
+		out := make([]*db.Request, 0, len(reqs))
+		for _, req := range reqs {
+			if tier != "" && string(req.RiskTier) != tier {
+				continue
+			}
+			if !reviewer {
+				req = redactRequest(req)
+			}
+			out = append(out, req)
+		}
+		writeHTTPJSON(w, http.StatusOK, out)
+	})
+}
+
+func (s *HTTPServer) handleHistory(w http.ResponseWriter, r *http.Request) {
+	params := r.URL.Query()
+	query, err := db.ParseRequestQuery(params.Get("q"), s.opts.SavedQueries)
+	if err != nil {
+		writeHTTPError(w, http.StatusBadRequest, "invalid query: "+err.Error())
+		return
+	}
+	limit := 0
+	if raw := params.Get("limit"); raw != "" {
+		if limit, err = strconv.Atoi(raw); err != nil || limit < 0 {
+			writeHTTPError(w, http.StatusBadRequest, "invalid limit")
+			return
+		}
+	}
+
+	s.withDB(w, func(dbConn *db.DB) {
+		// Non-reviewers search the redacted commands they are shown, so
+		// cmd: and free-text terms cannot probe the raw text.
+		reviewer := isReviewer(r, dbConn)
+		page, err := dbConn.QueryRequests(query, db.QueryOptions{
+			ProjectPath:      s.opts.ProjectPath,
+			Limit:            limit,
+			Cursor:           params.Get("cursor"),
+			RedactedCommands: !reviewer,
+		})
+		switch {
+		case errors.Is(err, db.ErrInvalidCursor):
+			writeHTTPError(w, http.StatusBadRequest, err.Error())
+		case err != nil:
+			writeHTTPError(w, http.StatusInternalServerError, err.Error())
+		default:
+			if !reviewer {
+				for i, req := range page.Requests {
+					page.Requests[i] = redactRequest(req)
+				}
+			}
+			writeHTTPJSON(w, http.StatusOK, page)
+		}
+	})
+}
+
+func (s *HTTPServer) handleGetRequest(w http.ResponseWriter, r *http.Request) {
+	s.withDB(w, func(dbConn *db.DB) {
+		req, ok := loadRequest(w, r, dbConn)
+		if !ok {
+			return
+		}
+		reviews, err := dbConn.ListReviewsForRequest(req.ID)
+		if err != nil {
+			writeHTTPError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		if !isReviewer(r, dbConn) {
+			req = redactRequest(req)
+		}
+		detail := HTTPRequestDetail{Request: req, Reviews: reviews}
+		for _, rev := range reviews {
+			switch rev.Decision {
+			case db.DecisionApprove:
+				detail.Approvals++
+			case db.DecisionReject:
+				detail.Rejections++
+			}
+		}
+		writeHTTPJSON(w, http.StatusOK, detail)
+	})
+}
+
+func (s *HTTPServer) handleListReviews(w http.ResponseWriter, r *http.Request) {
+	s.withDB(w, func(dbConn *db.DB) {
+		req, ok := loadRequest(w, r, dbConn)
+		if !ok {
+			return
+		}
+		reviews, err := dbConn.ListReviewsForRequest(req.ID)
+		if err != nil {
+			writeHTTPError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		if reviews == nil {
+			reviews = []*db.Review{}
+		}
+		writeHTTPJSON(w, http.StatusOK, reviews)
+	})
+}
+
+func (s *HTTPServer) handleSubmitReview(w http.ResponseWriter, r *http.Request) {
+	var in HTTPReviewInput
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 1<<20)).Decode(&in); err != nil {
+		writeHTTPError(w, http.StatusBadRequest, "invalid body: "+err.Error())
+		return
+	}
+	sessionKey := bearerToken(r)
+	if sessionKey == "" {
+		writeHTTPError(w, http.StatusUnauthorized, "a session key bearer token is required to sign reviews")
+		return
+	}
+
+	s.withDB(w, func(dbConn *db.DB) {
+		req, ok := loadRequest(w, r, dbConn)
+		if !ok {
+			return
+		}
+
+		if strings.TrimSpace(in.SessionID) == "" {
+			sess, err := dbConn.GetActiveSessionByKey(sessionKey)
+			if err != nil {
+				writeHTTPError(w, http.StatusForbidden, "no active session for the bearer key")
+				return
+			}
+			in.SessionID = sess.ID
+		}
+
+		svc := core.NewReviewService(dbConn, s.opts.ReviewConfig)
+		result, err := svc.SubmitReview(core.ReviewOptions{
+			SessionID:      in.SessionID,
+			SessionKey:     sessionKey,
+			RequestID:      req.ID,
+			Decision:       db.Decision(strings.ToLower(strings.TrimSpace(in.Decision))),
+			Responses:      in.Responses,
+			Comments:       in.Comments,
+			RequireSandbox: in.RequireSandbox,
+		})
+		if err != nil {
+			writeHTTPError(w, reviewErrorStatus(err), err.Error())
+			return
+		}
+
+		resp := HTTPReviewResult{
+			Review:     result.Review,
+			Approvals:  result.Approvals,
+			Rejections: result.Rejections,
+		}
+		if result.RequestStatusChanged {
+			resp.NewRequestStatus = string(result.NewRequestStatus)
+			s.broadcastReview(req, result)
+		}
+		writeHTTPJSON(w, http.StatusCreated, resp)
+	})
+}
+
+func (s *HTTPServer) broadcastReview(req *db.Request, result *core.ReviewResult) {
+	if s.opts.Events == nil {
+		return
+	}
+	payload := RequestEventPayload(req, map[string]any{"status": string(result.NewRequestStatus)})
+	switch result.NewRequestStatus {
+	case db.StatusApproved:
+		payload["approved_by"] = result.Review.ReviewerAgent
+		s.opts.Events.BroadcastEvent("request_approved", payload)
+	case db.StatusRejected:
+		payload["rejected_by"] = result.Review.ReviewerAgent
+		s.opts.Events.BroadcastEvent("request_rejected", payload)
+	}
+}
+
+func reviewErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, core.ErrSessionKeyMismatch), errors.Is(err, core.ErrMissingSessionKey), errors.Is(err, core.ErrSessionInactive):
+		return http.StatusForbidden
+	case errors.Is(err, core.ErrSelfReview), errors.Is(err, core.ErrAlreadyReviewed), errors.Is(err, core.ErrRequestNotPending), errors.Is(err, core.ErrRequireDiffModel):
+		return http.StatusConflict
+	default:
+		return http.StatusBadRequest
+	}
+}
+
+func (s *HTTPServer) handleExecutionStatus(w http.ResponseWriter, r *http.Request) {
+	s.withDB(w, func(dbConn *db.DB) {
+		req, ok := loadRequest(w, r, dbConn)
+		if !ok {
+			return
+		}
+		resp := HTTPExecutionStatus{
+			RequestID: req.ID,
+			Status:    string(req.Status),
+			Execution: req.Execution,
+		}
+		if outcome, err := dbConn.GetOutcomeForRequest(req.ID); err == nil {
+			resp.Outcome = outcome
+		}
+		writeHTTPJSON(w, http.StatusOK, resp)
+	})
+}
+
+// handleEvents streams daemon events as Server-Sent Events. Each event is sent
+// with the event type as the SSE "event" field and the Event JSON as "data".
+func (s *HTTPServer) handleEvents(w http.ResponseWriter, r *http.Request) {
+	if s.opts.Events == nil {
+		writeHTTPError(w, http.StatusServiceUnavailable, "event stream not available")
+		return
+	}
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeHTTPError(w, http.StatusInternalServerError, "streaming unsupported")
+		return
+	}
+
+	// Non-reviewers get request events with redacted commands and no
+	// execution output.
+	opened, reviewer := false, false
+	s.withDB(w, func(dbConn *db.DB) {
+		opened, reviewer = true, isReviewer(r, dbConn)
+	})
+	if !opened {
+		return
+	}
+	events, done, cancel := s.opts.Events.SubscribeEvents()
+	defer cancel()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	_, _ = fmt.Fprint(w, ": subscribed\n\n")
+	flusher.Flush()
+
+	keepAlive := time.NewTicker(sseKeepAlive)
+	defer keepAlive.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-done:
+			return
+		case <-keepAlive.C:
+			if _, err := fmt.Fprint(w, ": keepalive\n\n"); err != nil {
+				return
+			}
+			flusher.Flush()
+		case event := <-events:
+			if !reviewer {
+				var ok bool
+				if event, ok = redactEvent(event); !ok {
+					continue
+				}
+			}
+			data, err := json.Marshal(event)
+			if err != nil {
+				continue
+			}
+			if _, err := fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event.Type, data); err != nil {
+				return
+			}
+			flusher.Flush()
+		}
+	}
+}
+
+func (s *HTTPServer) handleOpenAPI(w http.ResponseWriter, r *http.Request) {
+	writeHTTPJSON(w, http.StatusOK, openAPIDocument)
+}
+
+// isReviewer reports whether the caller is an authenticated reviewer: its
+// bearer key belongs to an active session. Callers authenticated only by a
+// client certificate, or not at all, are not.
+func isReviewer(r *http.Request, dbConn *db.DB) bool {
+	key := bearerToken(r)
+	if key == "" {
+		return false
+	}
+	_, err := dbConn.GetActiveSessionByKey(key)
+	return err == nil
+}
+
+// redactRequest returns a copy of req whose commands only show their
+// redacted display form.
+func redactRequest(req *db.Request) *db.Request {
+	out := *req
+	out.Command = redactCommand(req.Command)
+	if req.Plan != nil {
+		plan := *req.Plan
+		plan.Steps = append([]db.PlanStep(nil), req.Plan.Steps...)
+		for i := range plan.Steps {
+			plan.Steps[i].Command = redactCommand(plan.Steps[i].Command)
+		}
+		out.Plan = &plan
+	}
+	return &out
+}
+
+// redactEvent returns event as a non-reviewer may see it: execution output
+// is withheld (ok is false) and the command of a request event is redacted.
+func redactEvent(event Event) (redacted Event, ok bool) {
+	if event.Type == EventExecutionOutput {
+		return event, false
+	}
+	payload, isMap := event.Payload.(map[string]any)
+	if !isMap {
+		return event, true
+	}
+	cmd, hasCommand := payload["command"].(string)
+	if !hasCommand {
+		return event, true
+	}
+	copied := make(map[string]any, len(payload))
+	for k, v := range payload {
+		copied[k] = v
+	}
+	copied["command"] = core.ApplyRedaction(cmd, nil)
+	event.Payload = copied
+	return event, true
+}
+
+func redactCommand(c db.CommandSpec) db.CommandSpec {
+	display := c.DisplayRedacted
+	if display == "" {
+		display = core.ApplyRedaction(c.Raw, nil)
+	}
+	if display != c.Raw {
+		c.Raw = display
+		c.Argv = nil
+	}
+	return c
+}
+
+func displayCommand(req *db.Request) string {
+	if req.Command.DisplayRedacted != "" {
+		return req.Command.DisplayRedacted
+	}
+	return req.Command.Raw
+}
@@ -0,0 +1,536 @@
+package daemon
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Dicklesworthstone/slb/internal/core"
+	"github.com/Dicklesworthstone/slb/internal/db"
+)
+
+const httpTestProject = "/test/project"
+
+type httpTestEnv struct {
+	server   *httptest.Server
+	events   *IPCServer
+	reviewer *db.Session
+	request  *db.Request
+	dbPath   string
+}
+
+func newHTTPTestEnv(t *testing.T) *httpTestEnv {
+	t.Helper()
+
+	dbPath := filepath.Join(t.TempDir(), "state.db")
+	seed, err := db.OpenAndMigrate(dbPath)
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	defer seed.Close()
+
+	requestor := &db.Session{ID: "sess-req", AgentName: "BlueLake", Program: "test", Model: "model-a", ProjectPath: httpTestProject, SessionKey: "requestor-key"}
+	reviewer := &db.Session{ID: "sess-rev", AgentName: "GreenCastle", Program: "test", Model: "model-b", ProjectPath: httpTestProject, SessionKey: "reviewer-key"}
+	for _, s := range []*db.Session{requestor, reviewer} {
+		if err := seed.CreateSession(s); err != nil {
+			t.Fatalf("create session: %v", err)
+		}
+	}
+
+	expires := time.Now().Add(30 * time.Minute)
+	req := &db.Request{
+		ID:                 "req-http-1",
+		ProjectPath:        httpTestProject,
+		Command:            db.CommandSpec{Raw: "rm -rf ./build", Cwd: httpTestProject},
+		RiskTier:           db.RiskTierDangerous,
+		RequestorSessionID: requestor.ID,
+		RequestorAgent:     requestor.AgentName,
+		RequestorModel:     requestor.Model,
+		Justification:      db.Justification{Reason: "clean build"},
+		Status:             db.StatusPending,
+		MinApprovals:       1,
+		ExpiresAt:          &expires,
+	}
+	if err := seed.CreateRequest(req); err != nil {
+		t.Fatalf("create request: %v", err)
+	}
+
+	events, err := NewIPCServer(filepath.Join(shortSocketDir(t), "d.sock"), newTestLogger())
+	if err != nil {
+		t.Fatalf("NewIPCServer: %v", err)
+	}
+	t.Cleanup(func() { _ = events.Stop() })
+
+	srv, err := NewHTTPServer(HTTPServerOptions{
+		Addr:        "127.0.0.1:0",
+		RequireAuth: true,
+		ValidateAuth: func(ctx context.Context, key string) (bool, error) {
+			return key == requestor.SessionKey || key == reviewer.SessionKey, nil
+		},
+		ProjectPath: httpTestProject,
+		OpenDB: func() (*db.DB, error) {
+			return db.OpenWithOptions(dbPath, db.OpenOptions{})
+		},
+		Events:       events,
+		ReviewConfig: core.DefaultReviewConfig(),
+		SavedQueries: map[string]string{"builds": `cmd:"build"`},
+	}, newTestLogger())
+	if err != nil {
+		t.Fatalf("NewHTTPServer: %v", err)
+	}
+	t.Cleanup(func() { _ = srv.Stop() })
+
+	ts := httptest.NewServer(srv.Handler())
+	t.Cleanup(ts.Close)
+
+	return &httpTestEnv{server: ts, events: events, reviewer: reviewer, request: req, dbPath: dbPath}
+}
+
+func (e *httpTestEnv) do(t *testing.T, method, path, token string, body string, out any) int {
+	t.Helper()
+
+	req, err := http.NewRequest(method, e.server.URL+path, strings.NewReader(body))
+	if err != nil {
+		t.Fatalf("new request: %v", err)
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("%s %s: %v", method, path, err)
+	}
+	defer resp.Body.Close()
+	if out != nil {
+		if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+			t.Fatalf("decode %s %s: %v", method, path, err)
+		}
+	}
+	return resp.StatusCode
+}
+
+func TestHTTPGateway_Auth(t *testing.T) {
+	env := newHTTPTestEnv(t)
+
+	var health HTTPHealth
+	if code := env.do(t, http.MethodGet, "/v1/health", "", "", &health); code != http.StatusOK || !health.OK {
+		t.Fatalf("health = %d %+v", code, health)
+	}
+
+	var herr HTTPError
+	if code := env.do(t, http.MethodGet, "/v1/requests", "", "", &herr); code != http.StatusUnauthorized {
+		t.Fatalf("expected 401 without token, got %d", code)
+	}
+	if code := env.do(t, http.MethodGet, "/v1/requests", "wrong", "", &herr); code != http.StatusUnauthorized {
+		t.Fatalf("expected 401 with bad token, got %d", code)
+	}
+}
+
+func TestHTTPGateway_History(t *testing.T) {
+	env := newHTTPTestEnv(t)
+	key := env.reviewer.SessionKey
+
+	var page db.RequestPage
+	if code := env.do(t, http.MethodGet, "/v1/history?q=@builds+tier:dangerous&limit=5", key, "", &page); code != http.StatusOK {
+		t.Fatalf("history = %d", code)
+	}
+	if page.Total != 1 || len(page.Requests) != 1 || page.Requests[0].ID != env.request.ID || page.NextCursor != "" {
+		t.Fatalf("unexpected page: %+v", page)
+	}
+	if code := env.do(t, http.MethodGet, "/v1/history?q=tier:critical", key, "", &page); code != http.StatusOK || page.Total != 0 || len(page.Requests) != 0 {
+		t.Fatalf("tier query = %d %+v", code, page)
+	}
+
+	var herr HTTPError
+	for _, path := range []string{"/v1/history?q=teir:critical", "/v1/history?cursor=bogus", "/v1/history?limit=x"} {
+		if code := env.do(t, http.MethodGet, path, key, "", &herr); code != http.StatusBadRequest {
+			t.Errorf("%s: expected 400, got %d", path, code)
+		}
+	}
+}
+
+func TestHTTPGateway_RequestsAndReviews(t *testing.T) {
+	env := newHTTPTestEnv(t)
+	key := env.reviewer.SessionKey
+
+	var list []map[string]any
+	if code := env.do(t, http.MethodGet, "/v1/requests", key, "", &list); code != http.StatusOK {
+		t.Fatalf("list = %d", code)
+	}
+	if len(list) != 1 || list[0]["id"] != env.request.ID {
+		t.Fatalf("unexpected list: %+v", list)
+	}
+	if code := env.do(t, http.MethodGet, "/v1/requests?tier=critical", key, "", &list); code != http.StatusOK || len(list) != 0 {
+		t.Fatalf("tier filter = %d %+v", code, list)
+	}
+
+	var herr HTTPError
+	if code := env.do(t, http.MethodGet, "/v1/requests/missing", key, "", &herr); code != http.StatusNotFound {
+		t.Fatalf("expected 404, got %d", code)
+	}
+
+	events, done, cancel := env.events.SubscribeEvents()
+	defer cancel()
+
+	body := `{"session_id":"` + env.reviewer.ID + `","decision":"approve","comments":"looks fine"}`
+	var result HTTPReviewResult
+	if code := env.do(t, http.MethodPost, "/v1/requests/"+env.request.ID+"/reviews", key, body, &result); code != http.StatusCreated {
+		t.Fatalf("submit review = %d", code)
+	}
+	if result.Approvals != 1 || result.NewRequestStatus != string(db.StatusApproved) {
+		t.Fatalf("unexpected review result: %+v", result)
+	}
+
+	select {
+	case ev := <-events:
+		if ev.Type != "request_approved" {
+			t.Fatalf("event type = %q", ev.Type)
+		}
+	case <-done:
+		t.Fatal("subscription closed")
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for request_approved event")
+	}
+
+	if code := env.do(t, http.MethodPost, "/v1/requests/"+env.request.ID+"/reviews", key, body, &herr); code != http.StatusConflict {
+		t.Fatalf("expected 409 on second review, got %d (%s)", code, herr.Error)
+	}
+
+	var detail HTTPRequestDetail
+	if code := env.do(t, http.MethodGet, "/v1/requests/"+env.request.ID, key, "", &detail); code != http.StatusOK {
+		t.Fatalf("detail = %d", code)
+	}
+	if detail.Approvals != 1 || len(detail.Reviews) != 1 || detail.Reviews[0].Comments != "looks fine" {
+		t.Fatalf("unexpected detail: %+v", detail)
+	}
+
+	var exec HTTPExecutionStatus
+	if code := env.do(t, http.MethodGet, "/v1/requests/"+env.request.ID+"/execution", key, "", &exec); code != http.StatusOK {
+		t.Fatalf("execution = %d", code)
+	}
+	if exec.Status != string(db.StatusApproved) || exec.Outcome != nil {
+		t.Fatalf("unexpected execution status: %+v", exec)
+	}
+}
+
+func TestHTTPGateway_RedactsCommandsForNonReviewers(t *testing.T) {
+	env := newHTTPTestEnv(t)
+
+	seed, err := db.OpenWithOptions(env.dbPath, db.OpenOptions{})
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	secret := &db.Request{
+		ID:          "req-http-secret",
+		ProjectPath: httpTestProject,
+		Command: db.CommandSpec{
+			Raw:               "curl -H 'Authorization: Bearer s3cr3t-t0ken' https://api",
+			Argv:              []string{"curl", "-H", "Authorization: Bearer s3cr3t-t0ken", "https://api"},
+			Cwd:               httpTestProject,
+			DisplayRedacted:   "curl -H 'Authorization: Bearer [REDACTED]' https://api",
+			ContainsSensitive: true,
+		},
+		RiskTier:           db.RiskTierDangerous,
+		RequestorSessionID: "sess-req",
+		RequestorAgent:     "BlueLake",
+		RequestorModel:     "model-a",
+		Justification:      db.Justification{Reason: "call the api"},
+		Status:             db.StatusPending,
+		MinApprovals:       1,
+	}
+	if err := seed.CreateRequest(secret); err != nil {
+		t.Fatalf("create request: %v", err)
+	}
+	seed.Close()
+
+	// Without auth required, an anonymous caller can read requests but not
+	// their raw commands.
+	anon := newAnonymousHTTPTestEnv(t, env)
+
+	check := func(name string, e *httpTestEnv, token string, wantRaw bool) {
+		t.Helper()
+		var detail HTTPRequestDetail
+		if code := e.do(t, http.MethodGet, "/v1/requests/"+secret.ID, token, "", &detail); code != http.StatusOK {
+			t.Fatalf("%s: detail = %d", name, code)
+		}
+		var list []*db.Request
+		if code := e.do(t, http.MethodGet, "/v1/requests", token, "", &list); code != http.StatusOK || len(list) != 2 {
+			t.Fatalf("%s: list = %d (%d requests)", name, code, len(list))
+		}
+		var page db.RequestPage
+		if code := e.do(t, http.MethodGet, "/v1/history?q=curl", token, "", &page); code != http.StatusOK || len(page.Requests) != 1 {
+			t.Fatalf("%s: history = %d %+v", name, code, page)
+		}
+		for _, cmd := range []db.CommandSpec{detail.Request.Command, list[0].Command, list[1].Command, page.Requests[0].Command} {
+			leaked := strings.Contains(cmd.Raw, "s3cr3t") || strings.Contains(strings.Join(cmd.Argv, " "), "s3cr3t")
+			if cmd.Hash == secret.Command.Hash && leaked != wantRaw {
+				t.Errorf("%s: command = %+v, want raw %v", name, cmd, wantRaw)
+			}
+		}
+	}
+	check("reviewer", env, env.reviewer.SessionKey, true)
+	check("anonymous", anon, "", false)
+
+	// Searches by non-reviewers only see the redacted command.
+	for _, tc := range []struct {
+		query          string
+		reviewer, anon int
+	}{
+		{"cmd:s3cr3t", 1, 0},
+		{"-cmd:s3cr3t", 1, 2},
+		{"s3cr3t", 1, 0},
+		{"cmd:REDACTED", 0, 1},
+		{"api", 1, 1},
+		{"call", 1, 1},
+	} {
+		for _, c := range []struct {
+			name  string
+			e     *httpTestEnv
+			token string
+			want  int
+		}{{"reviewer", env, env.reviewer.SessionKey, tc.reviewer}, {"anonymous", anon, "", tc.anon}} {
+			var page db.RequestPage
+			if code := c.e.do(t, http.MethodGet, "/v1/history?q="+url.QueryEscape(tc.query), c.token, "", &page); code != http.StatusOK || page.Total != c.want {
+				t.Errorf("%s: history %q = %d, %d matches, want %d", c.name, tc.query, code, page.Total, c.want)
+			}
+		}
+	}
+}
+
+func TestHTTPGateway_EventStreamRedactsForNonReviewers(t *testing.T) {
+	env := newHTTPTestEnv(t)
+	anon := newAnonymousHTTPTestEnv(t, env)
+
+	subscribe := func(e *httpTestEnv, token string) <-chan string {
+		t.Helper()
+		req, err := http.NewRequest(http.MethodGet, e.server.URL+"/v1/events", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("GET /v1/events: %v", err)
+		}
+		t.Cleanup(func() { resp.Body.Close() })
+		reader := bufio.NewReader(resp.Body)
+		if line, _ := reader.ReadString('\n'); !strings.HasPrefix(line, ": subscribed") {
+			t.Fatalf("unexpected first line %q", line)
+		}
+		lines := make(chan string, 16)
+		go func() {
+			defer close(lines)
+			for {
+				line, err := reader.ReadString('\n')
+				if err != nil {
+					return
+				}
+				if line = strings.TrimSpace(line); strings.HasPrefix(line, "data: ") {
+					lines <- line
+				}
+			}
+		}()
+		return lines
+	}
+	next := func(name string, lines <-chan string) string {
+		t.Helper()
+		select {
+		case line := <-lines:
+			return line
+		case <-time.After(2 * time.Second):
+			t.Fatalf("%s: timed out waiting for an event", name)
+			return ""
+		}
+	}
+
+	reviewerLines := subscribe(env, env.reviewer.SessionKey)
+	anonLines := subscribe(anon, "")
+
+	env.events.BroadcastEvent(EventExecutionOutput, ExecutionOutput{RequestID: "req-x", Seq: 1, Data: "password=hunter2\n"})
+	env.events.BroadcastEvent(EventRequestPending, map[string]any{
+		"request_id": "req-x",
+		"command":    "curl -H 'Authorization: Bearer s3cr3t-t0ken' https://api",
+	})
+
+	if line := next("reviewer", reviewerLines); !strings.Contains(line, "hunter2") {
+		t.Errorf("reviewer: first event = %q, want the output", line)
+	}
+	if line := next("reviewer", reviewerLines); !strings.Contains(line, "s3cr3t") {
+		t.Errorf("reviewer: second event = %q, want the raw command", line)
+	}
+	// The output is withheld from the anonymous caller, and the command
+	// redacted.
+	line := next("anonymous", anonLines)
+	if !strings.Contains(line, "req-x") || strings.Contains(line, "hunter2") || strings.Contains(line, "s3cr3t") {
+		t.Errorf("anonymous: first event = %q, want the redacted request event", line)
+	}
+}
+
+// newAnonymousHTTPTestEnv serves env's database and events without
+// requiring authentication.
+func newAnonymousHTTPTestEnv(t *testing.T, env *httpTestEnv) *httpTestEnv {
+	t.Helper()
+	srv, err := NewHTTPServer(HTTPServerOptions{
+		Addr:        "127.0.0.1:0",
+		ProjectPath: httpTestProject,
+		OpenDB: func() (*db.DB, error) {
+			return db.OpenWithOptions(env.dbPath, db.OpenOptions{})
+		},
+		ReviewConfig: core.DefaultReviewConfig(),
+		Events:       env.events,
+	}, newTestLogger())
+	if err != nil {
+		t.Fatalf("NewHTTPServer: %v", err)
+	}
+	anon := &httpTestEnv{server: httptest.NewServer(srv.Handler()), events: env.events, dbPath: env.dbPath}
+	t.Cleanup(anon.server.Close)
+	return anon
+}
+
+func TestHTTPGateway_SubmitReviewWrongKey(t *testing.T) {
+	env := newHTTPTestEnv(t)
+
+	body := `{"session_id":"` + env.reviewer.ID + `","decision":"approve"}`
+	var herr HTTPError
+	// requestor-key authenticates but does not belong to the reviewer session.
+	if code := env.do(t, http.MethodPost, "/v1/requests/"+env.request.ID+"/reviews", "requestor-key", body, &herr); code != http.StatusForbidden {
+		t.Fatalf("expected 403, got %d (%s)", code, herr.Error)
+	}
+}
+
+func TestHTTPGateway_EventStream(t *testing.T) {
+	env := newHTTPTestEnv(t)
+
+	req, err := http.NewRequest(http.MethodGet, env.server.URL+"/v1/events", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Authorization", "Bearer "+env.reviewer.SessionKey)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("GET /v1/events: %v", err)
+	}
+	defer resp.Body.Close()
+	if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Fatalf("content type = %q", ct)
+	}
+
+	reader := bufio.NewReader(resp.Body)
+	if line, _ := reader.ReadString('\n'); !strings.HasPrefix(line, ": subscribed") {
+		t.Fatalf("unexpected first line %q", line)
+	}
+
+	env.events.BroadcastEvent("request_pending", map[string]any{"request_id": "req-x"})
+
+	lines := make(chan string, 4)
+	go func() {
+		for {
+			line, err := reader.ReadString('\n')
+			if err != nil {
+				close(lines)
+				return
+			}
+			if strings.TrimSpace(line) != "" {
+				lines <- strings.TrimSpace(line)
+			}
+		}
+	}()
+
+	want := []string{"event: request_pending", "data: "}
+	for _, prefix := range want {
+		select {
+		case line := <-lines:
+			if !strings.HasPrefix(line, prefix) {
+				t.Fatalf("got %q, want prefix %q", line, prefix)
+			}
+			if prefix == "data: " && !strings.Contains(line, "req-x") {
+				t.Fatalf("data line missing payload: %q", line)
+			}
+		case <-time.After(2 * time.Second):
+			t.Fatalf("timed out waiting for %q", prefix)
+		}
+	}
+}
+
+func TestOpenAPIDocument_CoversRoutes(t *testing.T) {
+	doc := OpenAPIDocument()
+	if _, err := json.Marshal(doc); err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+
+	paths := doc["paths"].(map[string]any)
+	for _, rt := range httpRoutes {
+		item, ok := paths[rt.Path].(map[string]any)
+		if !ok {
+			t.Fatalf("path %s missing", rt.Path)
+		}
+		if _, ok := item[strings.ToLower(rt.Method)]; !ok {
+			t.Fatalf("%s %s missing", rt.Method, rt.Path)
+		}
+	}
+
+	schemas := doc["components"].(map[string]any)["schemas"].(map[string]any)
+	for _, name := range []string{"Request", "Review", "HTTPReviewInput", "Event", "HTTPError"} {
+		if _, ok := schemas[name]; !ok {
+			t.Errorf("schema %s missing", name)
+		}
+	}
+}
+
+func TestHTTPGateway_Operator(t *testing.T) {
+	env := newHTTPTestEnv(t)
+
+	var op HTTPOperator
+	if code := env.do(t, http.MethodGet, "/v1/session", env.reviewer.SessionKey, "", &op); code != http.StatusOK {
+		t.Fatalf("session = %d", code)
+	}
+	if op.Session == nil || op.Session.AgentName != env.reviewer.AgentName {
+		t.Fatalf("unexpected operator: %+v", op)
+	}
+
+	// session_id is optional: the session owning the bearer key signs.
+	var result HTTPReviewResult
+	body := `{"decision":"reject","comments":"not now"}`
+	if code := env.do(t, http.MethodPost, "/v1/requests/"+env.request.ID+"/reviews", env.reviewer.SessionKey, body, &result); code != http.StatusCreated {
+		t.Fatalf("submit review = %d", code)
+	}
+	if result.Review == nil || result.Review.ReviewerSessionID != env.reviewer.ID {
+		t.Fatalf("unexpected review: %+v", result.Review)
+	}
+}
+
+func TestHTTPGateway_WebDashboard(t *testing.T) {
+	env := newHTTPTestEnv(t)
+
+	for path, want := range map[string]string{
+		"/":        "SLB Review Dashboard",
+		"/app.js":  "/v1/events",
+		"/app.css": "--critical",
+	} {
+		resp, err := http.Get(env.server.URL + path)
+		if err != nil {
+			t.Fatalf("GET %s: %v", path, err)
+		}
+		data, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("GET %s = %d", path, resp.StatusCode)
+		}
+		if !strings.Contains(string(data), want) {
+			t.Errorf("GET %s missing %q", path, want)
+		}
+		if resp.Header.Get("Content-Security-Policy") == "" {
+			t.Errorf("GET %s missing CSP header", path)
+		}
+	}
+}
@@ -112,9 +112,15 @@ type IPCServer struct {
 // subscriber tracks an event subscription.
 type subscriber struct {
 	id     int64
-	conn   net.Conn
+	conn   net.Conn // nil for in-process subscribers
 	events chan Event
 	done   chan struct{}
+
+	closeOnce sync.Once
+}
+
+func (sub *subscriber) close() {
+	sub.closeOnce.Do(func() { close(sub.done) })
 }
 
 // Event represents a daemon event sent to subscribers.
@@ -227,7 +233,7 @@ func (s *IPCServer) Stop() error {
 	// Close all subscribers.
 	s.subscribersMu.Lock()
 	for _, sub := range s.subscribers {
-		close(sub.done)
+		sub.close()
 	}
 	s.subscribers = make(map[int64]*subscriber)
 	s.subscribersMu.Unlock()
@@ -472,6 +478,27 @@ func (s *IPCServer) broadcast(event Event) {
 	}
 }
 
+// SubscribeEvents registers an in-process subscriber that receives the same
+// events as IPC "subscribe" clients. The returned done channel closes when the
+// server stops; call cancel to unsubscribe.
+func (s *IPCServer) SubscribeEvents() (events <-chan Event, done <-chan struct{}, cancel func()) {
+	id := s.nextSubID.Add(1)
+	sub := &subscriber{
+		id:     id,
+		events: make(chan Event, 100),
+		done:   make(chan struct{}),
+	}
+
+	s.subscribersMu.Lock()
+	s.subscribers[id] = sub
+	s.subscribersMu.Unlock()
+
+	return sub.events, sub.done, func() {
+		s.removeSubscriber(id)
+		sub.close()
+	}
+}
+
 // removeSubscriber removes a subscriber from the map.
 func (s *IPCServer) removeSubscriber(id int64) {
 	s.subscribersMu.Lock()
@@ -539,7 +566,7 @@ func (s *IPCServer) handleVerifyExecute(req RPCRequest) *RPCResponse {
 		}
 	}
 
-	result, err := s.verifier.VerifyAndMarkExecuting(params.RequestID, params.SessionID)
+	result, err := s.verifier.VerifyAndMarkExecuting(params.RequestID, params.SessionID, params.Environment)
 	if err != nil {
 		return &RPCResponse{
 			Error: &Error{Code: ErrCodeInternal, Message: err.Error()},
@@ -4,6 +4,7 @@ package daemon
 import (
 	"bufio"
 	"context"
+	"crypto/tls"
 	"encoding/json"
 	"fmt"
 	"net"
@@ -15,8 +16,13 @@ import (
 )
 
 // IPCClient provides methods to communicate with the daemon via IPC.
+//
+// When SLB_HOST is set the client dials the daemon's TCP listener first. TLS is
+// used when SLB_HOST starts with tls:// or SLB_TLS_CA/SLB_TLS_CERT/SLB_TLS_KEY
+// are set, or when a config is supplied via SetTLSConfig.
 type IPCClient struct {
 	socketPath string
+	tlsConfig  *tls.Config
 	conn       net.Conn
 	scanner    *bufio.Scanner
 	mu         sync.Mutex
@@ -30,6 +36,13 @@ func NewIPCClient(socketPath string) *IPCClient {
 	}
 }
 
+// SetTLSConfig overrides the TLS settings used for TCP connections (SLB_HOST).
+func (c *IPCClient) SetTLSConfig(cfg *tls.Config) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.tlsConfig = cfg
+}
+
 // Connect establishes a connection to the daemon IPC socket.
 func (c *IPCClient) Connect(ctx context.Context) error {
 	c.mu.Lock()
@@ -44,7 +57,7 @@ func (c *IPCClient) Connect(ctx context.Context) error {
 	var err error
 
 	if host := strings.TrimSpace(os.Getenv("SLB_HOST")); host != "" {
-		conn, err = d.DialContext(ctx, "tcp", host)
+		conn, err = dialDaemonTCP(ctx, host, c.tlsConfig)
 		if err == nil {
 			hello, err := json.Marshal(map[string]string{
 				"auth": strings.TrimSpace(os.Getenv("SLB_SESSION_KEY")),
@@ -334,6 +347,10 @@ type RequestStreamEvent struct {
 	Requestor  string `json:"requestor,omitempty"`
 	ApprovedBy string `json:"approved_by,omitempty"`
 	RejectedBy string `json:"rejected_by,omitempty"`
+	AbortedBy  string `json:"aborted_by,omitempty"`
+	FrozenBy   string `json:"frozen_by,omitempty"`
+	EndedBy    string `json:"ended_by,omitempty"`
+	Until      string `json:"until,omitempty"`
 	Reason     string `json:"reason,omitempty"`
 	ExitCode   *int   `json:"exit_code,omitempty"`
 	CreatedAt  string `json:"created_at,omitempty"`
@@ -367,6 +384,18 @@ func ToRequestStreamEvent(e Event) *RequestStreamEvent {
 		if v, ok := payload["rejected_by"].(string); ok {
 			we.RejectedBy = v
 		}
+		if v, ok := payload["aborted_by"].(string); ok {
+			we.AbortedBy = v
+		}
+		if v, ok := payload["frozen_by"].(string); ok {
+			we.FrozenBy = v
+		}
+		if v, ok := payload["ended_by"].(string); ok {
+			we.EndedBy = v
+		}
+		if v, ok := payload["until"].(string); ok {
+			we.Until = v
+		}
 		if v, ok := payload["reason"].(string); ok {
 			we.Reason = v
 		}
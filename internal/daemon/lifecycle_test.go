@@ -1,7 +1,9 @@
 package daemon
 
 import (
+	"bytes"
 	"context"
+	"errors"
 	"io"
 	"os"
 	"os/exec"
@@ -10,6 +12,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/Dicklesworthstone/slb/internal/config"
 	"github.com/charmbracelet/log"
 )
 
@@ -66,6 +69,38 @@ func TestRunDaemon_IPCServerFailure(t *testing.T) {
 	}
 }
 
+func TestRunDaemon_LockedConfig(t *testing.T) {
+	tmp := t.TempDir()
+	t.Setenv("HOME", tmp)
+	system := filepath.Join(tmp, "system.toml")
+	old := config.SystemConfigPath
+	config.SystemConfigPath = system
+	t.Cleanup(func() { config.SystemConfigPath = old })
+	if err := os.WriteFile(system, []byte("[general]\nmin_approvals = 3\n\n[locks]\nstricter = [\"general.min_approvals\"]\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("SLB_MIN_APPROVALS", "1")
+
+	// A weakened locked key is logged and ignored rather than stopping the
+	// daemon, which goes on to fail at the listen error forced below.
+	socketPath := filepath.Join(tmp, "slb.sock")
+	if err := os.MkdirAll(socketPath, 0700); err != nil {
+		t.Fatal(err)
+	}
+	var logs bytes.Buffer
+	err := RunDaemon(context.Background(), ServerOptions{
+		SocketPath: socketPath,
+		PIDFile:    filepath.Join(tmp, "slb.pid"),
+		Logger:     log.New(&logs),
+	})
+	if err == nil || errors.Is(err, config.ErrLocked) {
+		t.Fatalf("expected the listen error, got %v", err)
+	}
+	if !strings.Contains(logs.String(), "SLB_MIN_APPROVALS") {
+		t.Errorf("expected a warning naming SLB_MIN_APPROVALS, got %q", logs.String())
+	}
+}
+
 func TestStopDaemon_Timeout(t *testing.T) {
 	tmp := t.TempDir()
 	pidFile := filepath.Join(tmp, "test.pid")
@@ -46,6 +46,13 @@ type WebhookPayload struct {
 	Requestor string       `json:"requestor"`
 	Timestamp string       `json:"timestamp"`
 	Project   string       `json:"project,omitempty"`
+
+	// Populated for named webhook endpoints; see WebhookDispatcher.
+	Status        string               `json:"status,omitempty"`
+	Justification *db.Justification    `json:"justification,omitempty"`
+	Reviews       []WebhookReview      `json:"reviews,omitempty"`
+	Execution     *WebhookExecution    `json:"execution,omitempty"`
+	Outcome       *db.ExecutionOutcome `json:"outcome,omitempty"`
 }
 
 // WebhookNotifier handles webhook notifications.
@@ -69,10 +76,20 @@ type NotificationManager struct {
 	logger      *log.Logger
 	notifier    DesktopNotifier
 	webhook     WebhookNotifier
+	email       EmailNotifier
+	webhooks    *WebhookDispatcher
 	now         func() time.Time
 
 	mu       sync.Mutex
 	notified map[string]time.Time
+
+	// CAUTION requests waiting for the next email digest, oldest first.
+	digest      []string
+	digestSince time.Time
+
+	// Last status seen for requests announced to named webhook endpoints,
+	// used to emit approve/reject/execute transitions. Dropped once terminal.
+	tracked map[string]db.RequestStatus
 }
 
 // DefaultWebhookNotifier is the default implementation of WebhookNotifier.
@@ -138,14 +155,31 @@ func NewNotificationManager(projectPath string, cfg config.NotificationsConfig,
 		webhook = NewDefaultWebhookNotifier()
 	}
 
+	var email EmailNotifier
+	if cfg.EmailEnabled && strings.TrimSpace(cfg.EmailSMTPHost) != "" {
+		email = NewSMTPNotifier(cfg)
+	}
+
+	// Named endpoints record deliveries, so they need a writable database
+	// with the current schema.
+	webhooks := NewWebhookDispatcher(cfg, func() (*db.DB, error) {
+		return db.OpenWithOptions(filepath.Join(projectPath, ".slb", "state.db"), db.OpenOptions{
+			CreateIfNotExists: false,
+			InitSchema:        true,
+		})
+	}, logger)
+
 	return &NotificationManager{
 		projectPath: projectPath,
 		cfg:         cfg,
 		logger:      logger,
 		notifier:    notifier,
 		webhook:     webhook,
+		email:       email,
+		webhooks:    webhooks,
 		now:         time.Now,
 		notified:    make(map[string]time.Time),
+		tracked:     make(map[string]db.RequestStatus),
 	}
 }
 
@@ -155,6 +189,18 @@ func (m *NotificationManager) WithWebhook(w WebhookNotifier) *NotificationManage
 	return m
 }
 
+// WithEmail sets a custom email notifier (for testing).
+func (m *NotificationManager) WithEmail(e EmailNotifier) *NotificationManager {
+	m.email = e
+	return m
+}
+
+// WithWebhookDispatcher sets the dispatcher for named webhook endpoints (for testing).
+func (m *NotificationManager) WithWebhookDispatcher(d *WebhookDispatcher) *NotificationManager {
+	m.webhooks = d
+	return m
+}
+
 func (m *NotificationManager) Run(ctx context.Context, interval time.Duration) {
 	if m == nil {
 		return
@@ -176,7 +222,7 @@ func (m *NotificationManager) Run(ctx context.Context, interval time.Duration) {
 	}
 }
 
-// Check scans for notable events and sends notifications (desktop and/or webhook).
+// Check scans for notable events and sends notifications (desktop, webhook and/or email).
 func (m *NotificationManager) Check(ctx context.Context) error {
 	if m == nil {
 		return nil
@@ -185,7 +231,9 @@ func (m *NotificationManager) Check(ctx context.Context) error {
 	// Check if there's anything to do
 	hasDesktop := m.cfg.DesktopEnabled
 	hasWebhook := m.webhook != nil && m.cfg.WebhookURL != ""
-	if !hasDesktop && !hasWebhook {
+	hasEmail := m.email != nil && m.cfg.EmailEnabled
+	hasEndpoints := m.webhooks != nil
+	if !hasDesktop && !hasWebhook && !hasEmail && !hasEndpoints {
 		return nil
 	}
 
@@ -213,6 +261,17 @@ func (m *NotificationManager) Check(ctx context.Context) error {
 		return nil
 	}
 
+	if hasEmail {
+		m.checkCautionDigest(ctx, pending, now)
+	}
+
+	if hasEndpoints {
+		m.checkWebhookEvents(ctx, dbConn, pending, now)
+		if err := m.webhooks.ProcessDue(ctx); err != nil {
+			m.logger.Warn("webhook retries failed", "error", err)
+		}
+	}
+
 	for _, req := range pending {
 		if req == nil {
 			continue
@@ -292,11 +351,147 @@ func (m *NotificationManager) Check(ctx context.Context) error {
 			}
 			cancel()
 		}
+
+		if hasEmail {
+			m.sendRequestEmail(ctx, req)
+		}
 	}
 
 	return nil
 }
 
+// sendRequestEmail emails a single pending request to its tier's recipients.
+func (m *NotificationManager) sendRequestEmail(ctx context.Context, req *db.Request) {
+	to := emailRecipients(m.cfg, req.RiskTier)
+	if len(to) == 0 {
+		return
+	}
+	msg, err := renderRequestEmail(req, m.projectPath, to)
+	if err != nil {
+		m.logger.Warn("rendering notification email failed", "error", err, "request_id", req.ID)
+		return
+	}
+
+	emailCtx, cancel := context.WithTimeout(ctx, EmailTimeout)
+	defer cancel()
+	if err := m.email.SendEmail(emailCtx, msg); err != nil {
+		m.logger.Warn("email notification failed", "error", err, "request_id", req.ID)
+		return
+	}
+	m.logger.Debug("email notification sent", "request_id", req.ID, "recipients", len(to))
+}
+
+// checkCautionDigest queues newly pending CAUTION requests and sends them as one
+// digest email once the oldest has waited email_caution_digest_minutes. With a
+// zero interval each CAUTION request is emailed on its own.
+func (m *NotificationManager) checkCautionDigest(ctx context.Context, pending []*db.Request, now time.Time) {
+	byID := make(map[string]*db.Request)
+	for _, req := range pending {
+		if req == nil || req.RiskTier != db.RiskTierCaution {
+			continue
+		}
+		byID[req.ID] = req
+		if !m.markOnce("caution_email:"+req.ID, now) {
+			continue
+		}
+		if m.cfg.EmailCautionDigestMins <= 0 {
+			m.sendRequestEmail(ctx, req)
+			continue
+		}
+		if len(m.digest) == 0 {
+			m.digestSince = now
+		}
+		m.digest = append(m.digest, req.ID)
+	}
+
+	if len(m.digest) == 0 {
+		return
+	}
+	if now.Sub(m.digestSince) < time.Duration(m.cfg.EmailCautionDigestMins)*time.Minute {
+		return
+	}
+
+	// Requests resolved while queued are dropped from the digest.
+	var reqs []*db.Request
+	for _, id := range m.digest {
+		if req, ok := byID[id]; ok {
+			reqs = append(reqs, req)
+		}
+	}
+	m.digest = nil
+	if len(reqs) == 0 {
+		return
+	}
+
+	to := emailRecipients(m.cfg, db.RiskTierCaution)
+	if len(to) == 0 {
+		return
+	}
+	msg, err := renderDigestEmail(reqs, m.projectPath, to)
+	if err != nil {
+		m.logger.Warn("rendering digest email failed", "error", err)
+		return
+	}
+	emailCtx, cancel := context.WithTimeout(ctx, EmailTimeout)
+	defer cancel()
+	if err := m.email.SendEmail(emailCtx, msg); err != nil {
+		m.logger.Warn("caution digest email failed", "error", err, "requests", len(reqs))
+		return
+	}
+	m.logger.Debug("caution digest email sent", "requests", len(reqs), "recipients", len(to))
+}
+
+// checkWebhookEvents announces newly pending requests to the named webhook
+// endpoints and follows them until they reach a terminal status, emitting an
+// event for each status change.
+func (m *NotificationManager) checkWebhookEvents(ctx context.Context, dbConn *db.DB, pending []*db.Request, now time.Time) {
+	stillPending := make(map[string]bool, len(pending))
+	for _, req := range pending {
+		if req == nil {
+			continue
+		}
+		stillPending[req.ID] = true
+		if !m.markOnce("webhook_pending:"+req.ID, now) {
+			continue
+		}
+		m.tracked[req.ID] = db.StatusPending
+		m.dispatchWebhook(ctx, buildWebhookPayload(WebhookEventRequestPending, req, nil, nil, m.projectPath, now))
+	}
+
+	for id, last := range m.tracked {
+		if stillPending[id] {
+			continue
+		}
+		req, reviews, err := dbConn.GetRequestWithReviews(id)
+		if err != nil {
+			delete(m.tracked, id)
+			continue
+		}
+		if req.Status == last {
+			continue
+		}
+		m.tracked[id] = req.Status
+		if req.Status.IsTerminal() {
+			delete(m.tracked, id)
+		}
+		event, ok := webhookEventForStatus(req.Status)
+		if !ok || !m.markOnce("webhook_"+string(event)+":"+id, now) {
+			continue
+		}
+		var outcome *db.ExecutionOutcome
+		if event == WebhookEventRequestExecuted {
+			outcome, _ = dbConn.GetOutcomeForRequest(id)
+		}
+		m.dispatchWebhook(ctx, buildWebhookPayload(event, req, reviews, outcome, m.projectPath, now))
+	}
+}
+
+func (m *NotificationManager) dispatchWebhook(ctx context.Context, payload WebhookPayload) {
+	if err := m.webhooks.Dispatch(ctx, payload); err != nil {
+		m.logger.Warn("webhook dispatch failed", "error", err, "request_id", payload.RequestID, "event", payload.Event)
+	}
+}
+
 // SendWebhook sends a webhook notification for a specific event (can be called directly).
 func (m *NotificationManager) SendWebhook(ctx context.Context, event WebhookEvent, req *db.Request) error {
 	if m == nil || m.webhook == nil || m.cfg.WebhookURL == "" {
@@ -0,0 +1,348 @@
+package daemon
+
+import (
+	"net/http"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Dicklesworthstone/slb/internal/db"
+)
+
+// httpRoute describes one gateway endpoint. The same table registers handlers
+// and generates the OpenAPI document, so the two cannot drift apart.
+type httpRoute struct {
+	Method  string
+	Path    string // net/http pattern path, e.g. /v1/requests/{id}
+	Summary string
+	Auth    bool
+	Query   []httpParam
+	Body    any // zero value of the JSON request body type, if any
+	// Response is the zero value of the JSON response type. Ignored for streams.
+	Response any
+	// Stream marks text/event-stream responses.
+	Stream bool
+
+	handler func(s *HTTPServer) http.HandlerFunc
+}
+
+type httpParam struct {
+	Name        string
+	Description string
+}
+
+var httpRoutes = []httpRoute{
+	{
+		Method:   http.MethodGet,
+		Path:     "/v1/health",
+		Summary:  "Gateway health and daemon uptime",
+		Response: HTTPHealth{},
+		handler:  func(s *HTTPServer) http.HandlerFunc { return s.handleHealth },
+	},
+	{
+		Method:   http.MethodGet,
+		Path:     "/v1/session",
+		Summary:  "The operator identity behind the bearer key or client certificate",
+		Auth:     true,
+		Response: HTTPOperator{},
+		handler:  func(s *HTTPServer) http.HandlerFunc { return s.handleOperator },
+	},
+	{
+		Method:  http.MethodGet,
+		Path:    "/v1/requests",
+		Summary: "List requests for the daemon's project (pending by default)",
+		Auth:    true,
+		Query: []httpParam{
+			{Name: "status", Description: "pending (default), all, or any request status"},
+			{Name: "tier", Description: "filter by risk tier: critical, dangerous, caution, safe"},
+		},
+		Response: []*db.Request{},
+		handler:  func(s *HTTPServer) http.HandlerFunc { return s.handleListRequests },
+	},
+	{
+		Method:  http.MethodGet,
+		Path:    "/v1/history",
+		Summary: "Search request history with the history query language, newest first (non-reviewers search redacted commands)",
+		Auth:    true,
+		Query: []httpParam{
+			{Name: "q", Description: "history query, e.g. tier:critical since:7d exit:!=0 or @saved"},
+			{Name: "limit", Description: "page size (default 50)"},
+			{Name: "cursor", Description: "next_cursor from the previous page"},
+		},
+		Response: db.RequestPage{},
+		handler:  func(s *HTTPServer) http.HandlerFunc { return s.handleHistory },
+	},
+	{
+		Method:   http.MethodGet,
+		Path:     "/v1/requests/{id}",
+		Summary:  "Request details with reviews (commands redacted unless the caller is a reviewer)",
+		Auth:     true,
+		Response: HTTPRequestDetail{},
+		handler:  func(s *HTTPServer) http.HandlerFunc { return s.handleGetRequest },
+	},
+	{
+		Method:   http.MethodGet,
+		Path:     "/v1/requests/{id}/reviews",
+		Summary:  "Reviews recorded for a request",
+		Auth:     true,
+		Response: []*db.Review{},
+		handler:  func(s *HTTPServer) http.HandlerFunc { return s.handleListReviews },
+	},
+	{
+		Method:   http.MethodPost,
+		Path:     "/v1/requests/{id}/reviews",
+		Summary:  "Approve or reject a request, signed with the bearer session key",
+		Auth:     true,
+		Body:     HTTPReviewInput{},
+		Response: HTTPReviewResult{},
+		handler:  func(s *HTTPServer) http.HandlerFunc { return s.handleSubmitReview },
+	},
+	{
+		Method:   http.MethodGet,
+		Path:     "/v1/requests/{id}/execution",
+		Summary:  "Execution status and recorded outcome",
+		Auth:     true,
+		Response: HTTPExecutionStatus{},
+		handler:  func(s *HTTPServer) http.HandlerFunc { return s.handleExecutionStatus },
+	},
+	{
+		Method:  http.MethodGet,
+		Path:    "/v1/events",
+		Summary: "Server-Sent Events mirror of the IPC subscribe stream (without execution output and with redacted commands unless the caller is a reviewer)",
+		Auth:    true,
+		Stream:  true,
+		handler: func(s *HTTPServer) http.HandlerFunc { return s.handleEvents },
+	},
+	{
+		Method:   http.MethodGet,
+		Path:     "/v1/openapi.json",
+		Summary:  "This OpenAPI document",
+		Response: map[string]any{},
+		handler:  func(s *HTTPServer) http.HandlerFunc { return s.handleOpenAPI },
+	},
+}
+
+// openAPIDocument is built once at init; building it during httpRoutes
+// initialization would be a cycle since the route table serves it.
+var openAPIDocument map[string]any
+
+func init() {
+	openAPIDocument = OpenAPIDocument()
+}
+
+// OpenAPIDocument returns the OpenAPI 3.0 description of the HTTP gateway,
+// generated from the route table and the Go response types.
+func OpenAPIDocument() map[string]any {
+	gen := &schemaGen{components: map[string]any{}}
+
+	paths := map[string]any{}
+	for _, rt := range httpRoutes {
+		op := map[string]any{
+			"summary":     rt.Summary,
+			"operationId": operationID(rt),
+		}
+
+		var params []any
+		for _, name := range pathParams(rt.Path) {
+			params = append(params, map[string]any{
+				"name": name, "in": "path", "required": true,
+				"schema": map[string]any{"type": "string"},
+			})
+		}
+		for _, q := range rt.Query {
+			params = append(params, map[string]any{
+				"name": q.Name, "in": "query", "required": false, "description": q.Description,
+				"schema": map[string]any{"type": "string"},
+			})
+		}
+		if len(params) > 0 {
+			op["parameters"] = params
+		}
+
+		if rt.Body != nil {
+			op["requestBody"] = map[string]any{
+				"required": true,
+				"content": map[string]any{
+					"application/json": map[string]any{"schema": gen.schema(reflect.TypeOf(rt.Body))},
+				},
+			}
+		}
+
+		responses := map[string]any{}
+		switch {
+		case rt.Stream:
+			responses["200"] = map[string]any{
+				"description": "Event stream; each message has event=<type> and data=<Event JSON>",
+				"content": map[string]any{
+					"text/event-stream": map[string]any{"schema": gen.schema(reflect.TypeOf(Event{}))},
+				},
+			}
+		case rt.Response != nil:
+			code := "200"
+			if rt.Method == http.MethodPost {
+				code = "201"
+			}
+			responses[code] = map[string]any{
+				"description": "OK",
+				"content": map[string]any{
+					"application/json": map[string]any{"schema": gen.schema(reflect.TypeOf(rt.Response))},
+				},
+			}
+		}
+		responses["default"] = map[string]any{
+			"description": "Error",
+			"content": map[string]any{
+				"application/json": map[string]any{"schema": gen.schema(reflect.TypeOf(HTTPError{}))},
+			},
+		}
+		op["responses"] = responses
+
+		if rt.Auth {
+			op["security"] = []any{
+				map[string]any{"sessionKey": []any{}},
+				map[string]any{"clientCertificate": []any{}},
+			}
+		}
+
+		item, _ := paths[rt.Path].(map[string]any)
+		if item == nil {
+			item = map[string]any{}
+			paths[rt.Path] = item
+		}
+		item[strings.ToLower(rt.Method)] = op
+	}
+
+	return map[string]any{
+		"openapi": "3.0.3",
+		"info": map[string]any{
+			"title":       "SLB daemon HTTP gateway",
+			"version":     "1",
+			"description": "REST and Server-Sent Events access to pending requests, reviews and execution status.",
+		},
+		"paths": paths,
+		"components": map[string]any{
+			"schemas": gen.components,
+			"securitySchemes": map[string]any{
+				"sessionKey":        map[string]any{"type": "http", "scheme": "bearer", "description": "Active session key"},
+				"clientCertificate": map[string]any{"type": "mutualTLS", "description": "Client certificate whose CN is an authorized principal"},
+			},
+		},
+	}
+}
+
+func operationID(rt httpRoute) string {
+	var b strings.Builder
+	b.WriteString(strings.ToLower(rt.Method))
+	for _, seg := range strings.Split(rt.Path, "/") {
+		seg = strings.Trim(seg, "{}")
+		seg = strings.TrimSuffix(seg, ".json")
+		if seg == "" || seg == "v1" {
+			continue
+		}
+		b.WriteString(strings.ToUpper(seg[:1]) + seg[1:])
+	}
+	return b.String()
+}
+
+func pathParams(path string) []string {
+	var out []string
+	for _, seg := range strings.Split(path, "/") {
+		if strings.HasPrefix(seg, "{") && strings.HasSuffix(seg, "}") {
+			out = append(out, strings.Trim(seg, "{}"))
+		}
+	}
+	return out
+}
+
+// schemaGen converts Go types to OpenAPI schemas, registering named structs as
+// components and referencing them with $ref.
+type schemaGen struct {
+	components map[string]any
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+func (g *schemaGen) schema(t reflect.Type) map[string]any {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t == timeType {
+		return map[string]any{"type": "string", "format": "date-time"}
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return map[string]any{"type": "string"}
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]any{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]any{"type": "number"}
+	case reflect.Slice, reflect.Array:
+		return map[string]any{"type": "array", "items": g.schema(t.Elem())}
+	case reflect.Map:
+		return map[string]any{"type": "object", "additionalProperties": g.schema(t.Elem())}
+	case reflect.Interface:
+		return map[string]any{}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return g.structSchema(t)
+		}
+		name := t.Name()
+		if _, ok := g.components[name]; !ok {
+			g.components[name] = map[string]any{} // placeholder breaks recursion
+			g.components[name] = g.structSchema(t)
+		}
+		return map[string]any{"$ref": "#/components/schemas/" + name}
+	default:
+		return map[string]any{}
+	}
+}
+
+func (g *schemaGen) structSchema(t reflect.Type) map[string]any {
+	props := map[string]any{}
+	var required []string
+
+	var walk func(t reflect.Type)
+	walk = func(t reflect.Type) {
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			if !f.IsExported() {
+				continue
+			}
+			tag := f.Tag.Get("json")
+			if tag == "-" {
+				continue
+			}
+			name, opts, _ := strings.Cut(tag, ",")
+			if f.Anonymous && name == "" {
+				ft := f.Type
+				if ft.Kind() == reflect.Pointer {
+					ft = ft.Elem()
+				}
+				if ft.Kind() == reflect.Struct {
+					walk(ft)
+					continue
+				}
+			}
+			if name == "" {
+				name = f.Name
+			}
+			props[name] = g.schema(f.Type)
+			if !strings.Contains(opts, "omitempty") && f.Type.Kind() != reflect.Pointer {
+				required = append(required, name)
+			}
+		}
+	}
+	walk(t)
+
+	out := map[string]any{"type": "object", "properties": props}
+	if len(required) > 0 {
+		sort.Strings(required)
+		out["required"] = required
+	}
+	return out
+}
@@ -0,0 +1,233 @@
+package daemon
+
+import (
+	"context"
+	"errors"
+	"os"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+// Output publishing limits. Output is flushed at least every
+// outputFlushInterval or once outputChunkSize bytes are buffered; anything
+// beyond outputMaxBuffered while the daemon is slow is dropped (the log file
+// still has it).
+const (
+	outputFlushInterval = 250 * time.Millisecond
+	outputChunkSize     = 8 * 1024
+	outputMaxBuffered   = 256 * 1024
+)
+
+// ExecutionOutput is the payload of an execution_output event.
+type ExecutionOutput struct {
+	RequestID string `json:"request_id"`
+	// Seq numbers the chunks of one execution from 1, so followers can tell
+	// when chunks were lost.
+	Seq  int64  `json:"seq"`
+	Data string `json:"data"`
+	// Dropped counts bytes discarded before this chunk because the daemon
+	// did not keep up.
+	Dropped int64 `json:"dropped,omitempty"`
+}
+
+// ToExecutionOutput decodes an execution_output event. It returns nil for
+// other events.
+func ToExecutionOutput(e Event) *ExecutionOutput {
+	if e.Type != EventExecutionOutput {
+		return nil
+	}
+	payload, ok := e.Payload.(map[string]any)
+	if !ok {
+		return nil
+	}
+	out := &ExecutionOutput{}
+	out.RequestID, _ = payload["request_id"].(string)
+	out.Data, _ = payload["data"].(string)
+	if v, ok := payload["seq"].(float64); ok {
+		out.Seq = int64(v)
+	}
+	if v, ok := payload["dropped"].(float64); ok {
+		out.Dropped = int64(v)
+	}
+	return out
+}
+
+// OutputPublisher is an io.Writer that publishes what is written to it as
+// execution_output events for one request. Writes never block on the daemon
+// and never fail, so it can sit in an io.MultiWriter next to the terminal
+// and the log file. Close flushes what is left.
+type OutputPublisher struct {
+	requestID string
+	publish   func(eventType string, payload any) error
+
+	// flushMu keeps chunks in sequence order when Flush races the
+	// background flush.
+	flushMu sync.Mutex
+
+	mu      sync.Mutex
+	buf     []byte
+	dropped int64
+	seq     int64
+	closed  bool
+	wake    chan struct{}
+	done    chan struct{}
+}
+
+// NewOutputPublisher creates a publisher for requestID that sends through
+// the daemon listening on socketPath. It returns nil when no daemon is
+// reachable; a nil *OutputPublisher discards writes.
+func NewOutputPublisher(socketPath, requestID string) *OutputPublisher {
+	if strings.TrimSpace(os.Getenv("SLB_HOST")) == "" {
+		if _, err := os.Stat(socketPath); errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+	}
+	client := NewIPCClient(socketPath)
+	publish := func(eventType string, payload any) error {
+		ctx, cancel := context.WithTimeout(context.Background(), eventPublishTimeout)
+		defer cancel()
+		if err := client.Connect(ctx); err != nil {
+			return err
+		}
+		client.mu.Lock()
+		_ = client.conn.SetDeadline(time.Now().Add(eventPublishTimeout))
+		client.mu.Unlock()
+		if err := client.Notify(ctx, eventType, payload); err != nil {
+			// Reconnect on the next chunk.
+			_ = client.Close()
+			return err
+		}
+		return nil
+	}
+	p := newOutputPublisher(requestID, publish)
+	go func() {
+		<-p.done
+		_ = client.Close()
+	}()
+	return p
+}
+
+func newOutputPublisher(requestID string, publish func(string, any) error) *OutputPublisher {
+	p := &OutputPublisher{
+		requestID: requestID,
+		publish:   publish,
+		wake:      make(chan struct{}, 1),
+		done:      make(chan struct{}),
+	}
+	go p.run()
+	return p
+}
+
+// Write buffers p for publishing.
+func (p *OutputPublisher) Write(data []byte) (int, error) {
+	if p == nil {
+		return len(data), nil
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.closed {
+		return len(data), nil
+	}
+	take := min(outputMaxBuffered-len(p.buf), len(data))
+	p.buf = append(p.buf, data[:take]...)
+	p.dropped += int64(len(data) - take)
+	if len(p.buf) >= outputChunkSize {
+		select {
+		case p.wake <- struct{}{}:
+		default:
+		}
+	}
+	return len(data), nil
+}
+
+// Flush publishes everything written so far before returning.
+func (p *OutputPublisher) Flush() error {
+	if p == nil {
+		return nil
+	}
+	p.flush(true)
+	return nil
+}
+
+// Close flushes the remaining output and stops the publisher.
+func (p *OutputPublisher) Close() error {
+	if p == nil {
+		return nil
+	}
+	p.mu.Lock()
+	if p.closed {
+		p.mu.Unlock()
+		return nil
+	}
+	p.closed = true
+	close(p.wake)
+	p.mu.Unlock()
+	<-p.done
+	return nil
+}
+
+func (p *OutputPublisher) run() {
+	defer close(p.done)
+	ticker := time.NewTicker(outputFlushInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case _, ok := <-p.wake:
+			if !ok {
+				p.flush(true)
+				return
+			}
+		case <-ticker.C:
+		}
+		p.flush(false)
+	}
+}
+
+// flush publishes the buffered output in chunks. Unless final, a trailing
+// partial UTF-8 sequence is kept back for the next flush.
+func (p *OutputPublisher) flush(final bool) {
+	p.flushMu.Lock()
+	defer p.flushMu.Unlock()
+	for {
+		p.mu.Lock()
+		n := min(len(p.buf), outputChunkSize)
+		if !final {
+			n = completeUTF8(p.buf[:n])
+		}
+		if n == 0 && (p.dropped == 0 || len(p.buf) > 0) {
+			p.mu.Unlock()
+			return
+		}
+		p.seq++
+		chunk := ExecutionOutput{
+			RequestID: p.requestID,
+			Seq:       p.seq,
+			Data:      string(p.buf[:n]),
+			Dropped:   p.dropped,
+		}
+		p.buf = append(p.buf[:0], p.buf[n:]...)
+		p.dropped = 0
+		p.mu.Unlock()
+
+		// Best effort: a chunk the daemon did not take is lost, and later
+		// sequence numbers show the gap.
+		_ = p.publish(EventExecutionOutput, chunk)
+	}
+}
+
+// completeUTF8 returns the length of the longest prefix of b that does not
+// end inside a multi-byte UTF-8 sequence.
+func completeUTF8(b []byte) int {
+	for i := len(b) - 1; i >= 0 && i >= len(b)-utf8.UTFMax; i-- {
+		if !utf8.RuneStart(b[i]) {
+			continue
+		}
+		if utf8.FullRune(b[i:]) {
+			return len(b)
+		}
+		return i
+	}
+	return len(b)
+}
@@ -0,0 +1,113 @@
+package daemon
+
+import (
+	"context"
+	"io"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/log"
+)
+
+func TestOutputPublisher_StreamsChunks(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("unix socket tests not supported on windows")
+	}
+	t.Setenv("SLB_HOST", "")
+
+	socketPath := filepath.Join(shortSocketDir(t), "t.sock")
+	srv, err := NewIPCServer(socketPath, log.New(io.Discard))
+	if err != nil {
+		t.Fatalf("NewIPCServer: %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() { _ = srv.Start(ctx) }()
+	defer srv.Stop()
+	time.Sleep(50 * time.Millisecond)
+
+	events, _, unsubscribe := srv.SubscribeEvents()
+	defer unsubscribe()
+
+	p := NewOutputPublisher(socketPath, "req-1")
+	if p == nil {
+		t.Fatal("expected a publisher while the daemon is running")
+	}
+	want := strings.Repeat("x", outputChunkSize+10) + "héllo\n"
+	// Split inside the two-byte é: the publisher must not emit half a rune.
+	cut := strings.Index(want, "é") + 1
+	_, _ = p.Write([]byte(want[:cut]))
+	_, _ = p.Write([]byte(want[cut:]))
+	if err := p.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	var got strings.Builder
+	var seq int64
+	for got.Len() < len(want) {
+		select {
+		case ev := <-events:
+			out := ToExecutionOutput(ev)
+			if out == nil || out.RequestID != "req-1" {
+				t.Fatalf("unexpected event: %+v", ev)
+			}
+			if out.Seq != seq+1 {
+				t.Errorf("seq = %d, want %d", out.Seq, seq+1)
+			}
+			seq = out.Seq
+			got.WriteString(out.Data)
+		case <-time.After(2 * time.Second):
+			t.Fatalf("timed out after %d bytes", got.Len())
+		}
+	}
+	if got.String() != want {
+		t.Errorf("published output differs from what was written")
+	}
+	if seq < 2 {
+		t.Errorf("expected output larger than a chunk to be split, got %d chunks", seq)
+	}
+}
+
+func TestOutputPublisher_DropsWhenBehind(t *testing.T) {
+	release := make(chan struct{})
+	var chunks []ExecutionOutput
+	p := newOutputPublisher("req-1", func(_ string, payload any) error {
+		<-release
+		chunks = append(chunks, payload.(ExecutionOutput))
+		return nil
+	})
+	data := []byte(strings.Repeat("y", 1024))
+	for i := 0; i < 2*outputMaxBuffered/len(data); i++ {
+		if n, err := p.Write(data); n != len(data) || err != nil {
+			t.Fatalf("Write = %d, %v", n, err)
+		}
+	}
+	close(release)
+	_ = p.Close()
+
+	var total, dropped int64
+	for _, c := range chunks {
+		total += int64(len(c.Data))
+		dropped += c.Dropped
+	}
+	if dropped == 0 {
+		t.Fatal("expected output beyond the buffer limit to be dropped")
+	}
+	if total+dropped != int64(2*outputMaxBuffered) {
+		t.Errorf("published %d + dropped %d != written %d", total, dropped, 2*outputMaxBuffered)
+	}
+}
+
+func TestOutputPublisher_NoDaemon(t *testing.T) {
+	t.Setenv("SLB_HOST", "")
+	p := NewOutputPublisher(filepath.Join(t.TempDir(), "missing.sock"), "req-1")
+	if n, err := p.Write([]byte("hi")); n != 2 || err != nil {
+		t.Fatalf("Write on nil publisher = %d, %v", n, err)
+	}
+	if err := p.Close(); err != nil {
+		t.Fatalf("Close on nil publisher: %v", err)
+	}
+}
@@ -3,6 +3,7 @@ package daemon
 import (
 	"bufio"
 	"context"
+	"crypto/tls"
 	"encoding/json"
 	"fmt"
 	"net"
@@ -21,6 +22,15 @@ type TCPServerOptions struct {
 	// ValidateAuth returns true if the provided session key is authorized to connect.
 	// If nil, any non-empty auth key is accepted when RequireAuth is true.
 	ValidateAuth func(ctx context.Context, sessionKey string) (bool, error)
+
+	// TLSConfig enables TLS on the listener when non-nil (see ServerTLSConfig).
+	TLSConfig *tls.Config
+
+	// ValidatePrincipal returns true if the Common Name of a verified client
+	// certificate is authorized to connect. A connection authenticated this way
+	// does not need a session key in the handshake. If nil, verified client
+	// certificates are not treated as authentication.
+	ValidatePrincipal func(ctx context.Context, principal string) (bool, error)
 }
 
 // NewTCPServer starts a TCP listener implementing the same line-delimited JSON-RPC protocol
@@ -28,13 +38,16 @@ type TCPServerOptions struct {
 //
 // Handshake: client must first send a single line JSON object: {"auth":"<session_key>"}.
 // If RequireAuth is true, the auth value must validate; otherwise it may be empty.
+//
+// With TLSConfig set, the TLS handshake completes before the auth line is read. A client
+// certificate whose principal passes ValidatePrincipal satisfies RequireAuth on its own.
 func NewTCPServer(opts TCPServerOptions, logger *log.Logger) (*IPCServer, error) {
 	addr := strings.TrimSpace(opts.Addr)
 	if addr == "" {
 		return nil, fmt.Errorf("tcp addr is required")
 	}
 
-	allowedNets, err := parseAllowedIPNets(opts.AllowedIPs)
+	auth, err := newListenerAuth(opts.RequireAuth, opts.AllowedIPs, opts.ValidateAuth, opts.ValidatePrincipal)
 	if err != nil {
 		return nil, err
 	}
@@ -43,20 +56,34 @@ func NewTCPServer(opts TCPServerOptions, logger *log.Logger) (*IPCServer, error)
 	if err != nil {
 		return nil, fmt.Errorf("listen tcp %s: %w", addr, err)
 	}
+	if opts.TLSConfig != nil {
+		ln = tls.NewListener(ln, opts.TLSConfig)
+	}
 
 	guard := func(conn net.Conn, scanner *bufio.Scanner) error {
 		remoteIP, err := extractRemoteIP(conn.RemoteAddr())
 		if err != nil {
 			return err
 		}
-		if len(allowedNets) > 0 && !ipAllowed(remoteIP, allowedNets) {
-			return fmt.Errorf("tcp client ip not allowed: %s", remoteIP.String())
+		if err := auth.checkIP(remoteIP); err != nil {
+			return err
 		}
 
 		// Require a handshake line from the client.
 		_ = conn.SetReadDeadline(time.Now().Add(3 * time.Second))
 		defer conn.SetReadDeadline(time.Time{})
 
+		principal := ""
+		if tlsConn := unwrapTLSConn(conn); tlsConn != nil {
+			hctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+			err := tlsConn.HandshakeContext(hctx)
+			cancel()
+			if err != nil {
+				return fmt.Errorf("tls handshake: %w", err)
+			}
+			principal = peerPrincipal(tlsConn.ConnectionState())
+		}
+
 		if !scanner.Scan() {
 			if err := scanner.Err(); err != nil {
 				return fmt.Errorf("handshake read error: %w", err)
@@ -71,28 +98,94 @@ func NewTCPServer(opts TCPServerOptions, logger *log.Logger) (*IPCServer, error)
 			return fmt.Errorf("invalid handshake: %w", err)
 		}
 
-		auth := strings.TrimSpace(hello.Auth)
-		if opts.RequireAuth && auth == "" {
-			return fmt.Errorf("auth required")
+		return auth.authenticate(principal, hello.Auth)
+	}
+
+	return newIPCServer(ln, addr, logger, nil, guard), nil
+}
+
+// listenerAuth is the auth model shared by the network listeners (TCP and HTTP):
+// an optional IP allowlist, then either a verified client certificate principal
+// or a session key.
+type listenerAuth struct {
+	requireAuth       bool
+	allowedNets       []*net.IPNet
+	validateAuth      func(ctx context.Context, sessionKey string) (bool, error)
+	validatePrincipal func(ctx context.Context, principal string) (bool, error)
+}
+
+func newListenerAuth(
+	requireAuth bool,
+	allowedIPs []string,
+	validateAuth func(ctx context.Context, sessionKey string) (bool, error),
+	validatePrincipal func(ctx context.Context, principal string) (bool, error),
+) (*listenerAuth, error) {
+	allowedNets, err := parseAllowedIPNets(allowedIPs)
+	if err != nil {
+		return nil, err
+	}
+	return &listenerAuth{
+		requireAuth:       requireAuth,
+		allowedNets:       allowedNets,
+		validateAuth:      validateAuth,
+		validatePrincipal: validatePrincipal,
+	}, nil
+}
+
+func (a *listenerAuth) checkIP(ip net.IP) error {
+	if len(a.allowedNets) > 0 && !ipAllowed(ip, a.allowedNets) {
+		return fmt.Errorf("tcp client ip not allowed: %s", ip.String())
+	}
+	return nil
+}
+
+// authenticate validates the client certificate principal (empty when no verified
+// certificate was presented) and the session key.
+func (a *listenerAuth) authenticate(principal, sessionKey string) error {
+	principalOK := false
+	if principal != "" && a.validatePrincipal != nil {
+		vctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		ok, err := a.validatePrincipal(vctx, principal)
+		cancel()
+		if err != nil {
+			return fmt.Errorf("principal validation error: %w", err)
+		}
+		if !ok {
+			return fmt.Errorf("client certificate principal not authorized: %s", principal)
 		}
+		principalOK = true
+	}
 
-		if auth != "" && opts.ValidateAuth != nil {
-			vctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
-			defer cancel()
+	sessionKey = strings.TrimSpace(sessionKey)
+	if a.requireAuth && sessionKey == "" && !principalOK {
+		return fmt.Errorf("auth required")
+	}
 
-			ok, err := opts.ValidateAuth(vctx, auth)
-			if err != nil {
-				return fmt.Errorf("auth validation error: %w", err)
-			}
-			if !ok {
-				return fmt.Errorf("invalid auth")
-			}
+	if sessionKey != "" && a.validateAuth != nil {
+		vctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		defer cancel()
+
+		ok, err := a.validateAuth(vctx, sessionKey)
+		if err != nil {
+			return fmt.Errorf("auth validation error: %w", err)
+		}
+		if !ok {
+			return fmt.Errorf("invalid auth")
 		}
+	}
+	return nil
+}
 
+// unwrapTLSConn returns the underlying TLS connection, if any.
+func unwrapTLSConn(conn net.Conn) *tls.Conn {
+	switch c := conn.(type) {
+	case *tls.Conn:
+		return c
+	case *lockedConn:
+		return unwrapTLSConn(c.Conn)
+	default:
 		return nil
 	}
-
-	return newIPCServer(ln, addr, logger, nil, guard), nil
 }
 
 func parseAllowedIPNets(values []string) ([]*net.IPNet, error) {
@@ -3,9 +3,12 @@ package daemon
 import (
 	"bufio"
 	"context"
+	"crypto/tls"
 	"encoding/json"
 	"io"
 	"net"
+	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
@@ -407,3 +410,245 @@ func TestExtractRemoteIP_HostName(t *testing.T) {
 		t.Errorf("expected nil IP, got %v", ip)
 	}
 }
+
+// newTestPKI creates a CA, a server certificate for 127.0.0.1 and client certificates
+// for the given principals, returning the directory holding the PEM files.
+func newTestPKI(t *testing.T, clients ...string) string {
+	t.Helper()
+	dir := t.TempDir()
+
+	if _, err := GenerateCA(dir, "test CA", time.Hour); err != nil {
+		t.Fatalf("GenerateCA: %v", err)
+	}
+	if _, err := IssueCertificate(dir, dir, "server", CertOptions{
+		CommonName: "127.0.0.1",
+		Hosts:      []string{"127.0.0.1"},
+		ValidFor:   time.Hour,
+	}); err != nil {
+		t.Fatalf("IssueCertificate(server): %v", err)
+	}
+	for _, name := range clients {
+		if _, err := IssueCertificate(dir, dir, "client-"+name, CertOptions{
+			CommonName: name,
+			Client:     true,
+			ValidFor:   time.Hour,
+		}); err != nil {
+			t.Fatalf("IssueCertificate(%s): %v", name, err)
+		}
+	}
+	return dir
+}
+
+func startTestTCPServer(t *testing.T, opts TCPServerOptions) string {
+	t.Helper()
+
+	srv, err := NewTCPServer(opts, log.New(io.Discard))
+	if err != nil {
+		t.Fatalf("NewTCPServer: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() { _ = srv.Start(ctx) }()
+	t.Cleanup(func() {
+		cancel()
+		_ = srv.Stop()
+	})
+	return srv.listener.Addr().String()
+}
+
+// tlsPing dials addr with TLS, sends the handshake line and a ping, and returns
+// the first response line.
+func tlsPing(addr string, cfg *tls.Config, auth string) ([]byte, error) {
+	d := tls.Dialer{NetDialer: &net.Dialer{Timeout: 500 * time.Millisecond}, Config: cfg}
+	conn, err := d.Dial("tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	_ = conn.SetDeadline(time.Now().Add(time.Second))
+	if _, err := conn.Write([]byte(`{"auth":"` + auth + `"}` + "\n")); err != nil {
+		return nil, err
+	}
+	if _, err := conn.Write([]byte(`{"method":"ping","id":1}` + "\n")); err != nil {
+		return nil, err
+	}
+	return bufio.NewReader(conn).ReadBytes('\n')
+}
+
+func TestTCPServer_TLS(t *testing.T) {
+	dir := newTestPKI(t)
+
+	serverTLS, err := ServerTLSConfig(filepath.Join(dir, "server.pem"), filepath.Join(dir, "server-key.pem"), "", false)
+	if err != nil {
+		t.Fatalf("ServerTLSConfig: %v", err)
+	}
+
+	addr := startTestTCPServer(t, TCPServerOptions{
+		Addr:        "127.0.0.1:0",
+		RequireAuth: true,
+		TLSConfig:   serverTLS,
+		ValidateAuth: func(_ context.Context, sessionKey string) (bool, error) {
+			return sessionKey == "good", nil
+		},
+	})
+
+	clientTLS, err := ClientTLSConfig(filepath.Join(dir, "ca.pem"), "", "", "127.0.0.1")
+	if err != nil {
+		t.Fatalf("ClientTLSConfig: %v", err)
+	}
+
+	t.Run("accepts session key over tls", func(t *testing.T) {
+		line, err := tlsPing(addr, clientTLS, "good")
+		if err != nil {
+			t.Fatalf("tls ping: %v", err)
+		}
+		if !strings.Contains(string(line), `"pong":true`) {
+			t.Fatalf("unexpected response: %s", line)
+		}
+	})
+
+	t.Run("rejects bad key over tls", func(t *testing.T) {
+		if _, err := tlsPing(addr, clientTLS, "bad"); err == nil {
+			t.Fatalf("expected connection to be rejected")
+		}
+	})
+
+	t.Run("rejects plaintext client", func(t *testing.T) {
+		conn, err := net.DialTimeout("tcp", addr, 500*time.Millisecond)
+		if err != nil {
+			t.Fatalf("dial: %v", err)
+		}
+		defer conn.Close()
+
+		_ = conn.SetDeadline(time.Now().Add(time.Second))
+		_, _ = conn.Write([]byte(`{"auth":"good"}` + "\n"))
+		_, _ = conn.Write([]byte(`{"method":"ping","id":1}` + "\n"))
+		line, _ := bufio.NewReader(conn).ReadBytes('\n')
+		if strings.Contains(string(line), "pong") {
+			t.Fatalf("plaintext client should not receive a pong")
+		}
+	})
+}
+
+func TestTCPServer_MutualTLSPrincipal(t *testing.T) {
+	dir := newTestPKI(t, "BlueLake", "Mallory")
+
+	serverTLS, err := ServerTLSConfig(
+		filepath.Join(dir, "server.pem"),
+		filepath.Join(dir, "server-key.pem"),
+		filepath.Join(dir, "ca.pem"),
+		true,
+	)
+	if err != nil {
+		t.Fatalf("ServerTLSConfig: %v", err)
+	}
+
+	addr := startTestTCPServer(t, TCPServerOptions{
+		Addr:        "127.0.0.1:0",
+		RequireAuth: true,
+		AllowedIPs:  []string{"127.0.0.1"},
+		TLSConfig:   serverTLS,
+		ValidatePrincipal: func(_ context.Context, principal string) (bool, error) {
+			return principal == "BlueLake", nil
+		},
+	})
+
+	clientConfig := func(name string) *tls.Config {
+		cfg, err := ClientTLSConfig(
+			filepath.Join(dir, "ca.pem"),
+			filepath.Join(dir, "client-"+name+".pem"),
+			filepath.Join(dir, "client-"+name+"-key.pem"),
+			"127.0.0.1",
+		)
+		if err != nil {
+			t.Fatalf("ClientTLSConfig(%s): %v", name, err)
+		}
+		return cfg
+	}
+
+	t.Run("authorized principal needs no session key", func(t *testing.T) {
+		line, err := tlsPing(addr, clientConfig("BlueLake"), "")
+		if err != nil {
+			t.Fatalf("tls ping: %v", err)
+		}
+		if !strings.Contains(string(line), `"pong":true`) {
+			t.Fatalf("unexpected response: %s", line)
+		}
+	})
+
+	t.Run("unknown principal rejected", func(t *testing.T) {
+		if _, err := tlsPing(addr, clientConfig("Mallory"), ""); err == nil {
+			t.Fatalf("expected connection to be rejected")
+		}
+	})
+
+	t.Run("missing client certificate rejected", func(t *testing.T) {
+		cfg, err := ClientTLSConfig(filepath.Join(dir, "ca.pem"), "", "", "127.0.0.1")
+		if err != nil {
+			t.Fatalf("ClientTLSConfig: %v", err)
+		}
+		if _, err := tlsPing(addr, cfg, ""); err == nil {
+			t.Fatalf("expected connection without client cert to be rejected")
+		}
+	})
+}
+
+func TestIPCClient_ConnectTLSFromEnv(t *testing.T) {
+	dir := newTestPKI(t, "BlueLake")
+
+	serverTLS, err := ServerTLSConfig(
+		filepath.Join(dir, "server.pem"),
+		filepath.Join(dir, "server-key.pem"),
+		filepath.Join(dir, "ca.pem"),
+		true,
+	)
+	if err != nil {
+		t.Fatalf("ServerTLSConfig: %v", err)
+	}
+
+	addr := startTestTCPServer(t, TCPServerOptions{
+		Addr:        "127.0.0.1:0",
+		RequireAuth: true,
+		TLSConfig:   serverTLS,
+		ValidatePrincipal: func(_ context.Context, principal string) (bool, error) {
+			return principal == "BlueLake", nil
+		},
+	})
+
+	t.Setenv("SLB_HOST", "tls://"+addr)
+	t.Setenv("SLB_SESSION_KEY", "")
+	t.Setenv("SLB_TLS_CA", filepath.Join(dir, "ca.pem"))
+	t.Setenv("SLB_TLS_CERT", filepath.Join(dir, "client-BlueLake.pem"))
+	t.Setenv("SLB_TLS_KEY", filepath.Join(dir, "client-BlueLake-key.pem"))
+
+	client := NewIPCClient(filepath.Join(shortSocketDir(t), "missing.sock"))
+	defer client.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	if err := client.Ping(ctx); err != nil {
+		t.Fatalf("Ping over tls: %v", err)
+	}
+
+	if err := pingDaemonTCP(ctx, "tls://"+addr, ""); err != nil {
+		t.Fatalf("pingDaemonTCP over tls: %v", err)
+	}
+}
+
+func TestServerTLSConfig_Errors(t *testing.T) {
+	dir := newTestPKI(t)
+
+	if _, err := ServerTLSConfig("", "", "", false); err == nil {
+		t.Fatalf("expected error for missing cert/key")
+	}
+	if _, err := ServerTLSConfig(filepath.Join(dir, "server.pem"), filepath.Join(dir, "server-key.pem"), "", true); err == nil {
+		t.Fatalf("expected error when requiring client certs without a CA")
+	}
+	if _, err := ServerTLSConfig(filepath.Join(dir, "server.pem"), filepath.Join(dir, "server-key.pem"), filepath.Join(dir, "missing.pem"), false); err == nil {
+		t.Fatalf("expected error for missing client CA")
+	}
+	if _, err := ClientTLSConfig("", filepath.Join(dir, "server.pem"), "", ""); err == nil {
+		t.Fatalf("expected error when client cert is set without key")
+	}
+}
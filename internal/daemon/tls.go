@@ -0,0 +1,347 @@
+package daemon
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Environment variables used by clients to connect to a TLS-enabled TCP listener.
+const (
+	envTLSCA         = "SLB_TLS_CA"
+	envTLSCert       = "SLB_TLS_CERT"
+	envTLSKey        = "SLB_TLS_KEY"
+	envTLSServerName = "SLB_TLS_SERVER_NAME"
+)
+
+// tlsHostPrefix marks an SLB_HOST value that must be dialed with TLS.
+const tlsHostPrefix = "tls://"
+
+// ServerTLSConfig builds the TLS configuration for the TCP listener.
+//
+// When clientCAFile is set, client certificates signed by that CA are verified.
+// If requireClientCert is also true, connections without a valid client certificate
+// are rejected during the TLS handshake (mutual TLS).
+func ServerTLSConfig(certFile, keyFile, clientCAFile string, requireClientCert bool) (*tls.Config, error) {
+	if strings.TrimSpace(certFile) == "" || strings.TrimSpace(keyFile) == "" {
+		return nil, fmt.Errorf("tls cert and key are both required")
+	}
+
+	cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		return nil, fmt.Errorf("loading tls key pair: %w", err)
+	}
+
+	cfg := &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		MinVersion:   tls.VersionTLS12,
+		ClientAuth:   tls.NoClientCert,
+	}
+
+	if strings.TrimSpace(clientCAFile) != "" {
+		pool, err := loadCertPool(clientCAFile)
+		if err != nil {
+			return nil, err
+		}
+		cfg.ClientCAs = pool
+		cfg.ClientAuth = tls.VerifyClientCertIfGiven
+		if requireClientCert {
+			cfg.ClientAuth = tls.RequireAndVerifyClientCert
+		}
+	} else if requireClientCert {
+		return nil, fmt.Errorf("client certificate verification requires a client CA")
+	}
+
+	return cfg, nil
+}
+
+// ClientTLSConfig builds the TLS configuration used to dial a TLS-enabled daemon.
+//
+// caFile pins the CA used to verify the daemon certificate (system roots are used
+// when empty). certFile/keyFile present a client certificate for mutual TLS.
+func ClientTLSConfig(caFile, certFile, keyFile, serverName string) (*tls.Config, error) {
+	cfg := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		ServerName: strings.TrimSpace(serverName),
+	}
+
+	if strings.TrimSpace(caFile) != "" {
+		pool, err := loadCertPool(caFile)
+		if err != nil {
+			return nil, err
+		}
+		cfg.RootCAs = pool
+	}
+
+	certFile = strings.TrimSpace(certFile)
+	keyFile = strings.TrimSpace(keyFile)
+	if certFile != "" || keyFile != "" {
+		if certFile == "" || keyFile == "" {
+			return nil, fmt.Errorf("client tls cert and key must be set together")
+		}
+		cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+		if err != nil {
+			return nil, fmt.Errorf("loading client tls key pair: %w", err)
+		}
+		cfg.Certificates = []tls.Certificate{cert}
+	}
+
+	return cfg, nil
+}
+
+// clientTLSFromEnv resolves the TCP dial target and TLS settings from the
+// environment. TLS is used when SLB_HOST has a tls:// prefix or any SLB_TLS_*
+// variable is set; otherwise the returned config is nil (plaintext).
+func clientTLSFromEnv(host string) (string, *tls.Config, error) {
+	addr := strings.TrimSpace(host)
+	useTLS := false
+	if strings.HasPrefix(addr, tlsHostPrefix) {
+		addr = strings.TrimPrefix(addr, tlsHostPrefix)
+		useTLS = true
+	}
+
+	caFile := strings.TrimSpace(os.Getenv(envTLSCA))
+	certFile := strings.TrimSpace(os.Getenv(envTLSCert))
+	keyFile := strings.TrimSpace(os.Getenv(envTLSKey))
+	serverName := strings.TrimSpace(os.Getenv(envTLSServerName))
+	if caFile != "" || certFile != "" || keyFile != "" || serverName != "" {
+		useTLS = true
+	}
+	if !useTLS {
+		return addr, nil, nil
+	}
+
+	if serverName == "" {
+		if h, _, err := net.SplitHostPort(addr); err == nil {
+			serverName = h
+		}
+	}
+
+	cfg, err := ClientTLSConfig(caFile, certFile, keyFile, serverName)
+	if err != nil {
+		return addr, nil, err
+	}
+	return addr, cfg, nil
+}
+
+// peerPrincipal returns the identity asserted by a verified client certificate.
+// The certificate's Common Name is the principal; it is matched against session
+// agent names or configured principals by the listener's validator.
+func peerPrincipal(state tls.ConnectionState) string {
+	if len(state.VerifiedChains) == 0 || len(state.VerifiedChains[0]) == 0 {
+		return ""
+	}
+	return strings.TrimSpace(state.VerifiedChains[0][0].Subject.CommonName)
+}
+
+func loadCertPool(path string) (*x509.CertPool, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading ca %s: %w", path, err)
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(data) {
+		return nil, fmt.Errorf("no certificates found in %s", path)
+	}
+	return pool, nil
+}
+
+// CertFiles describes the PEM files written by the certificate helpers.
+type CertFiles struct {
+	CertPath string `json:"cert_path"`
+	KeyPath  string `json:"key_path"`
+	Subject  string `json:"subject"`
+	NotAfter string `json:"not_after"`
+}
+
+// CertOptions configures certificate generation.
+type CertOptions struct {
+	// CommonName is the certificate subject. For client certificates this is the
+	// principal (typically the agent name) presented to the daemon.
+	CommonName string
+	// Hosts are DNS names or IP addresses for server certificates.
+	Hosts []string
+	// Client issues a client-auth certificate instead of a server certificate.
+	Client bool
+	// ValidFor is the certificate lifetime (defaults to one year).
+	ValidFor time.Duration
+}
+
+// GenerateCA creates a self-signed certificate authority in dir as ca.pem and ca-key.pem.
+func GenerateCA(dir, commonName string, validFor time.Duration) (CertFiles, error) {
+	if strings.TrimSpace(commonName) == "" {
+		commonName = "slb local CA"
+	}
+	if validFor <= 0 {
+		validFor = 10 * 365 * 24 * time.Hour
+	}
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return CertFiles{}, fmt.Errorf("generating ca key: %w", err)
+	}
+	serial, err := randomSerial()
+	if err != nil {
+		return CertFiles{}, err
+	}
+
+	now := time.Now()
+	tmpl := &x509.Certificate{
+		SerialNumber:          serial,
+		Subject:               pkix.Name{CommonName: commonName, Organization: []string{"slb"}},
+		NotBefore:             now.Add(-5 * time.Minute),
+		NotAfter:              now.Add(validFor),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign | x509.KeyUsageDigitalSignature,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+		MaxPathLenZero:        true,
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		return CertFiles{}, fmt.Errorf("creating ca certificate: %w", err)
+	}
+
+	return writeCertFiles(dir, "ca", der, key, tmpl)
+}
+
+// IssueCertificate signs a server or client certificate with the CA at caDir
+// and writes it to dir as <name>.pem and <name>-key.pem.
+func IssueCertificate(caDir, dir, name string, opts CertOptions) (CertFiles, error) {
+	if strings.TrimSpace(name) == "" {
+		return CertFiles{}, fmt.Errorf("certificate name is required")
+	}
+	if strings.TrimSpace(opts.CommonName) == "" {
+		return CertFiles{}, fmt.Errorf("common name is required")
+	}
+	if opts.ValidFor <= 0 {
+		opts.ValidFor = 365 * 24 * time.Hour
+	}
+
+	caCert, caKey, err := loadCA(caDir)
+	if err != nil {
+		return CertFiles{}, err
+	}
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return CertFiles{}, fmt.Errorf("generating key: %w", err)
+	}
+	serial, err := randomSerial()
+	if err != nil {
+		return CertFiles{}, err
+	}
+
+	now := time.Now()
+	tmpl := &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      pkix.Name{CommonName: opts.CommonName, Organization: []string{"slb"}},
+		NotBefore:    now.Add(-5 * time.Minute),
+		NotAfter:     now.Add(opts.ValidFor),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+	}
+	if opts.Client {
+		tmpl.ExtKeyUsage = []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth}
+	} else {
+		tmpl.ExtKeyUsage = []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth}
+		for _, h := range opts.Hosts {
+			h = strings.TrimSpace(h)
+			if h == "" {
+				continue
+			}
+			if ip := net.ParseIP(h); ip != nil {
+				tmpl.IPAddresses = append(tmpl.IPAddresses, ip)
+			} else {
+				tmpl.DNSNames = append(tmpl.DNSNames, h)
+			}
+		}
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, caCert, &key.PublicKey, caKey)
+	if err != nil {
+		return CertFiles{}, fmt.Errorf("creating certificate: %w", err)
+	}
+
+	return writeCertFiles(dir, name, der, key, tmpl)
+}
+
+func loadCA(dir string) (*x509.Certificate, *ecdsa.PrivateKey, error) {
+	certPEM, err := os.ReadFile(filepath.Join(dir, "ca.pem"))
+	if err != nil {
+		return nil, nil, fmt.Errorf("reading ca certificate: %w", err)
+	}
+	keyPEM, err := os.ReadFile(filepath.Join(dir, "ca-key.pem"))
+	if err != nil {
+		return nil, nil, fmt.Errorf("reading ca key: %w", err)
+	}
+
+	certBlock, _ := pem.Decode(certPEM)
+	if certBlock == nil {
+		return nil, nil, fmt.Errorf("invalid ca certificate pem")
+	}
+	cert, err := x509.ParseCertificate(certBlock.Bytes)
+	if err != nil {
+		return nil, nil, fmt.Errorf("parsing ca certificate: %w", err)
+	}
+	if !cert.IsCA {
+		return nil, nil, fmt.Errorf("ca.pem is not a CA certificate")
+	}
+
+	keyBlock, _ := pem.Decode(keyPEM)
+	if keyBlock == nil {
+		return nil, nil, fmt.Errorf("invalid ca key pem")
+	}
+	key, err := x509.ParseECPrivateKey(keyBlock.Bytes)
+	if err != nil {
+		return nil, nil, fmt.Errorf("parsing ca key: %w", err)
+	}
+	return cert, key, nil
+}
+
+func writeCertFiles(dir, name string, der []byte, key *ecdsa.PrivateKey, tmpl *x509.Certificate) (CertFiles, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return CertFiles{}, fmt.Errorf("creating cert dir: %w", err)
+	}
+
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		return CertFiles{}, fmt.Errorf("marshal key: %w", err)
+	}
+
+	files := CertFiles{
+		CertPath: filepath.Join(dir, name+".pem"),
+		KeyPath:  filepath.Join(dir, name+"-key.pem"),
+		Subject:  tmpl.Subject.CommonName,
+		NotAfter: tmpl.NotAfter.UTC().Format(time.RFC3339),
+	}
+
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	if err := os.WriteFile(files.CertPath, certPEM, 0644); err != nil {
+		return CertFiles{}, fmt.Errorf("write certificate: %w", err)
+	}
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+	if err := os.WriteFile(files.KeyPath, keyPEM, 0600); err != nil {
+		return CertFiles{}, fmt.Errorf("write key: %w", err)
+	}
+	return files, nil
+}
+
+func randomSerial() (*big.Int, error) {
+	limit := new(big.Int).Lsh(big.NewInt(1), 128)
+	serial, err := rand.Int(rand.Reader, limit)
+	if err != nil {
+		return nil, fmt.Errorf("generating serial: %w", err)
+	}
+	return serial, nil
+}
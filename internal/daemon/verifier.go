@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/Dicklesworthstone/slb/internal/core"
 	"github.com/Dicklesworthstone/slb/internal/db"
 )
 
@@ -17,13 +18,22 @@ type VerificationResult struct {
 	Reason string `json:"reason,omitempty"`
 	// Request is the request data (only if allowed).
 	Request *db.Request `json:"request,omitempty"`
-	// ApprovalRemainingSeconds is time left on approval TTL.
+	// ApprovalRemainingSeconds is time left on approval TTL, or until the
+	// execution window closes.
 	ApprovalRemainingSeconds int `json:"approval_remaining_seconds"`
+	// Sandbox is the sandbox the command must run in (nil if none). When a
+	// reviewer required sandboxing without restrictions of its own it is
+	// empty, and the executing client applies its configured defaults.
+	Sandbox *db.SandboxSpec `json:"sandbox,omitempty"`
+	// WindowOpensAt is when the request's execution window opens, set when
+	// execution was denied because it is not open yet.
+	WindowOpensAt *time.Time `json:"window_opens_at,omitempty"`
 }
 
 // Verifier validates execution gate conditions.
 type Verifier struct {
-	db *db.DB
+	db       *db.DB
+	schedule *core.ChangeSchedule
 }
 
 // NewVerifier creates a new execution verifier.
@@ -31,9 +41,18 @@ func NewVerifier(database *db.DB) *Verifier {
 	return &Verifier{db: database}
 }
 
+// WithSchedule sets the change windows and freeze periods checked before
+// execution.
+func (v *Verifier) WithSchedule(s *core.ChangeSchedule) *Verifier {
+	v.schedule = s
+	return v
+}
+
 // VerifyExecutionAllowed checks all gate conditions for executing a request.
+// env is the executing client's environment fingerprint; a request created
+// with a fingerprint is refused when env is missing or differs.
 // Does NOT mark the request as executing - use VerifyAndMarkExecuting for that.
-func (v *Verifier) VerifyExecutionAllowed(requestID, sessionID string) (*VerificationResult, error) {
+func (v *Verifier) VerifyExecutionAllowed(requestID, sessionID string, env db.EnvironmentFingerprint) (*VerificationResult, error) {
 	if requestID == "" {
 		return nil, errors.New("request_id is required")
 	}
@@ -55,7 +74,33 @@ func (v *Verifier) VerifyExecutionAllowed(requestID, sessionID string) (*Verific
 		}, nil
 	}
 
-	// Gate 2: Check approval hasn't expired.
+	// Gate 1b: The policy must allow the session to execute.
+	if request.Policy != nil {
+		session, err := v.db.GetSession(sessionID)
+		if err != nil {
+			return nil, fmt.Errorf("getting session: %w", err)
+		}
+		if rule := request.Policy.AllowsExecutor(session.AgentName, session.Model); rule != "" {
+			return &VerificationResult{
+				Allowed: false,
+				Reason:  fmt.Sprintf("%s: rule %q", core.ErrPolicyExecutor, rule),
+			}, nil
+		}
+	}
+
+	// Gate 2: Check approval hasn't expired. A request with an execution
+	// window is approved ahead of time: its approval TTL counts from when the
+	// window first opens, and it may only execute while the window is open.
+	now := time.Now()
+	if request.Window != nil {
+		if err := v.schedule.CheckWindow(request.Window, now); err != nil {
+			result := &VerificationResult{Allowed: false, Reason: err.Error()}
+			if opening, err := v.schedule.NextOpening(request.Window, now); err == nil {
+				result.WindowOpensAt = &opening
+			}
+			return result, nil
+		}
+	}
 	if request.ApprovalExpiresAt == nil {
 		return &VerificationResult{
 			Allowed: false,
@@ -63,7 +108,6 @@ func (v *Verifier) VerifyExecutionAllowed(requestID, sessionID string) (*Verific
 		}, nil
 	}
 
-	now := time.Now()
 	if now.After(*request.ApprovalExpiresAt) {
 		return &VerificationResult{
 			Allowed: false,
@@ -72,6 +116,26 @@ func (v *Verifier) VerifyExecutionAllowed(requestID, sessionID string) (*Verific
 	}
 
 	remainingSeconds := int(request.ApprovalExpiresAt.Sub(now).Seconds())
+	if closes, ok := v.schedule.WindowCloses(request.Window, now); ok && closes.Before(*request.ApprovalExpiresAt) {
+		remainingSeconds = int(closes.Sub(now).Seconds())
+	}
+
+	// Gate 2b: No freeze period or change freeze may cover the request's tier.
+	if err := v.schedule.CheckFreeze(request.RiskTier, now); err != nil {
+		return &VerificationResult{
+			Allowed: false,
+			Reason:  err.Error(),
+		}, nil
+	}
+	if err := core.CheckChangeFreeze(v.db, request.ProjectPath, request.RiskTier, now); err != nil {
+		if !errors.Is(err, core.ErrChangeFreeze) {
+			return nil, err
+		}
+		return &VerificationResult{
+			Allowed: false,
+			Reason:  err.Error(),
+		}, nil
+	}
 
 	// Gate 3: Command hash verification (already stored in request.Command.Hash).
 	// The hash is computed at request creation and stored; we verify it hasn't
@@ -98,19 +162,28 @@ func (v *Verifier) VerifyExecutionAllowed(requestID, sessionID string) (*Verific
 		}, nil
 	}
 
+	// Gate 5: The client's environment must match the approved fingerprint.
+	if err := core.VerifyEnvironment(request, env); err != nil {
+		return &VerificationResult{
+			Allowed: false,
+			Reason:  err.Error(),
+		}, nil
+	}
+
 	// All gates passed.
 	return &VerificationResult{
 		Allowed:                  true,
 		Request:                  request,
 		ApprovalRemainingSeconds: remainingSeconds,
+		Sandbox:                  core.EffectiveSandbox(request, reviews, db.SandboxSpec{}),
 	}, nil
 }
 
 // VerifyAndMarkExecuting verifies gate conditions and atomically marks the
 // request as EXECUTING. This implements "first executor wins" semantics.
-func (v *Verifier) VerifyAndMarkExecuting(requestID, sessionID string) (*VerificationResult, error) {
+func (v *Verifier) VerifyAndMarkExecuting(requestID, sessionID string, env db.EnvironmentFingerprint) (*VerificationResult, error) {
 	// First verify all conditions.
-	result, err := v.VerifyExecutionAllowed(requestID, sessionID)
+	result, err := v.VerifyExecutionAllowed(requestID, sessionID, env)
 	if err != nil {
 		return nil, err
 	}
@@ -162,8 +235,14 @@ func (v *Verifier) RevertExecutingOnFailure(requestID string) error {
 		return fmt.Errorf("request status is %s, expected executing", request.Status)
 	}
 
-	// Check approval hasn't expired.
-	if request.ApprovalExpiresAt != nil && time.Now().After(*request.ApprovalExpiresAt) {
+	// Check approval hasn't expired (or, with a window, that the window
+	// has not closed).
+	expired := request.ApprovalExpiresAt != nil && time.Now().After(*request.ApprovalExpiresAt)
+	if request.Window != nil {
+		_, err := v.schedule.NextOpening(request.Window, time.Now())
+		expired = errors.Is(err, core.ErrWindowClosed)
+	}
+	if expired {
 		// Approval expired, transition to TIMED_OUT instead.
 		if err := v.db.UpdateRequestStatus(requestID, db.StatusTimedOut); err != nil {
 			return fmt.Errorf("updating status to timed_out: %w", err)
@@ -223,17 +302,21 @@ func (v *Verifier) MarkExecutionComplete(requestID string, exitCode int, success
 type VerifyExecuteParams struct {
 	RequestID string `json:"request_id"`
 	SessionID string `json:"session_id"`
+	// Environment is the client's live environment fingerprint.
+	Environment db.EnvironmentFingerprint `json:"environment,omitempty"`
 }
 
 // VerifyExecuteResponse is the response for the verify_execute IPC method.
 type VerifyExecuteResponse struct {
-	Allowed                  bool   `json:"allowed"`
-	Reason                   string `json:"reason,omitempty"`
-	ApprovalRemainingSeconds int    `json:"approval_remaining_seconds"`
-	RequestID                string `json:"request_id,omitempty"`
-	Command                  string `json:"command,omitempty"`
-	CommandHash              string `json:"command_hash,omitempty"`
-	RiskTier                 string `json:"risk_tier,omitempty"`
+	Allowed                  bool            `json:"allowed"`
+	Reason                   string          `json:"reason,omitempty"`
+	ApprovalRemainingSeconds int             `json:"approval_remaining_seconds"`
+	RequestID                string          `json:"request_id,omitempty"`
+	Command                  string          `json:"command,omitempty"`
+	CommandHash              string          `json:"command_hash,omitempty"`
+	RiskTier                 string          `json:"risk_tier,omitempty"`
+	Sandbox                  *db.SandboxSpec `json:"sandbox,omitempty"`
+	WindowOpensAt            *time.Time      `json:"window_opens_at,omitempty"`
 }
 
 // ToIPCResponse converts a VerificationResult to an IPC response.
@@ -242,6 +325,8 @@ func (r *VerificationResult) ToIPCResponse() *VerifyExecuteResponse {
 		Allowed:                  r.Allowed,
 		Reason:                   r.Reason,
 		ApprovalRemainingSeconds: r.ApprovalRemainingSeconds,
+		Sandbox:                  r.Sandbox,
+		WindowOpensAt:            r.WindowOpensAt,
 	}
 	if r.Request != nil {
 		resp.RequestID = r.Request.ID
@@ -3,10 +3,13 @@ package daemon
 
 import (
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/Dicklesworthstone/slb/internal/core"
 	"github.com/Dicklesworthstone/slb/internal/db"
+	"github.com/Dicklesworthstone/slb/internal/schedule"
 )
 
 func setupTestDB(t *testing.T) *db.DB {
@@ -101,7 +104,7 @@ func TestVerifier_VerifyExecutionAllowed_MissingParams(t *testing.T) {
 
 	for _, tc := range tests {
 		t.Run(tc.name, func(t *testing.T) {
-			_, err := v.VerifyExecutionAllowed(tc.requestID, tc.sessionID)
+			_, err := v.VerifyExecutionAllowed(tc.requestID, tc.sessionID, nil)
 			if err == nil {
 				t.Fatalf("expected error, got nil")
 			}
@@ -116,7 +119,7 @@ func TestVerifier_VerifyExecutionAllowed_RequestNotFound(t *testing.T) {
 	database := setupTestDB(t)
 	v := NewVerifier(database)
 
-	_, err := v.VerifyExecutionAllowed("nonexistent-id", "sess1")
+	_, err := v.VerifyExecutionAllowed("nonexistent-id", "sess1", nil)
 	if err == nil {
 		t.Fatalf("expected error for nonexistent request")
 	}
@@ -129,7 +132,7 @@ func TestVerifier_VerifyExecutionAllowed_NotApproved(t *testing.T) {
 	createTestSession(t, database, "sess1")
 	createTestRequest(t, database, "req1", "sess1", db.StatusPending, 1)
 
-	result, err := v.VerifyExecutionAllowed("req1", "sess1")
+	result, err := v.VerifyExecutionAllowed("req1", "sess1", nil)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -180,7 +183,7 @@ func TestVerifier_VerifyExecutionAllowed_ExpiredApproval(t *testing.T) {
 	createTestSession(t, database, "reviewer-sess")
 	createTestReview(t, database, "req-expired", "reviewer-sess", db.DecisionApprove)
 
-	result, err := v.VerifyExecutionAllowed("req-expired", "sess1")
+	result, err := v.VerifyExecutionAllowed("req-expired", "sess1", nil)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -203,7 +206,7 @@ func TestVerifier_VerifyExecutionAllowed_InsufficientApprovals(t *testing.T) {
 	createTestSession(t, database, "reviewer-sess")
 	createTestReview(t, database, "req1", "reviewer-sess", db.DecisionApprove)
 
-	result, err := v.VerifyExecutionAllowed("req1", "sess1")
+	result, err := v.VerifyExecutionAllowed("req1", "sess1", nil)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -225,7 +228,7 @@ func TestVerifier_VerifyExecutionAllowed_Success(t *testing.T) {
 	createTestSession(t, database, "reviewer-sess")
 	createTestReview(t, database, "req1", "reviewer-sess", db.DecisionApprove)
 
-	result, err := v.VerifyExecutionAllowed("req1", "sess1")
+	result, err := v.VerifyExecutionAllowed("req1", "sess1", nil)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -250,7 +253,7 @@ func TestVerifier_VerifyAndMarkExecuting_Success(t *testing.T) {
 	createTestSession(t, database, "reviewer-sess")
 	createTestReview(t, database, "req1", "reviewer-sess", db.DecisionApprove)
 
-	result, err := v.VerifyAndMarkExecuting("req1", "sess1")
+	result, err := v.VerifyAndMarkExecuting("req1", "sess1", nil)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -279,7 +282,7 @@ func TestVerifier_VerifyAndMarkExecuting_RaceCondition(t *testing.T) {
 	createTestReview(t, database, "req1", "reviewer-sess", db.DecisionApprove)
 
 	// First executor wins
-	result1, err := v.VerifyAndMarkExecuting("req1", "sess1")
+	result1, err := v.VerifyAndMarkExecuting("req1", "sess1", nil)
 	if err != nil {
 		t.Fatalf("first VerifyAndMarkExecuting failed: %v", err)
 	}
@@ -288,7 +291,7 @@ func TestVerifier_VerifyAndMarkExecuting_RaceCondition(t *testing.T) {
 	}
 
 	// Second executor should fail
-	result2, err := v.VerifyAndMarkExecuting("req1", "sess1")
+	result2, err := v.VerifyAndMarkExecuting("req1", "sess1", nil)
 	if err != nil {
 		t.Fatalf("second VerifyAndMarkExecuting returned error: %v", err)
 	}
@@ -308,7 +311,7 @@ func TestVerifier_MarkExecutionComplete_Success(t *testing.T) {
 	createTestReview(t, database, "req1", "reviewer-sess", db.DecisionApprove)
 
 	// Mark as executing first
-	_, err := v.VerifyAndMarkExecuting("req1", "sess1")
+	_, err := v.VerifyAndMarkExecuting("req1", "sess1", nil)
 	if err != nil {
 		t.Fatalf("VerifyAndMarkExecuting failed: %v", err)
 	}
@@ -339,7 +342,7 @@ func TestVerifier_MarkExecutionComplete_Failure(t *testing.T) {
 	createTestReview(t, database, "req1", "reviewer-sess", db.DecisionApprove)
 
 	// Mark as executing first
-	_, err := v.VerifyAndMarkExecuting("req1", "sess1")
+	_, err := v.VerifyAndMarkExecuting("req1", "sess1", nil)
 	if err != nil {
 		t.Fatalf("VerifyAndMarkExecuting failed: %v", err)
 	}
@@ -476,7 +479,7 @@ func TestVerifier_NilApprovalExpiresAt(t *testing.T) {
 	createTestSession(t, database, "reviewer-sess")
 	createTestReview(t, database, "req-no-approval-expiry", "reviewer-sess", db.DecisionApprove)
 
-	result, err := v.VerifyExecutionAllowed("req-no-approval-expiry", "sess1")
+	result, err := v.VerifyExecutionAllowed("req-no-approval-expiry", "sess1", nil)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -541,7 +544,7 @@ func TestVerifier_RevertExecutingOnFailure_Success(t *testing.T) {
 	createTestReview(t, database, "req1", "reviewer-sess", db.DecisionApprove)
 
 	// First mark as executing
-	_, err := v.VerifyAndMarkExecuting("req1", "sess1")
+	_, err := v.VerifyAndMarkExecuting("req1", "sess1", nil)
 	if err != nil {
 		t.Fatalf("VerifyAndMarkExecuting failed: %v", err)
 	}
@@ -672,7 +675,7 @@ func TestVerifier_VerifyAndMarkExecuting_NotAllowed(t *testing.T) {
 	// Create with PENDING status - should not be allowed
 	createTestRequest(t, database, "req1", "sess1", db.StatusPending, 1)
 
-	result, err := v.VerifyAndMarkExecuting("req1", "sess1")
+	result, err := v.VerifyAndMarkExecuting("req1", "sess1", nil)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -696,3 +699,197 @@ func TestVerifier_MarkExecutionComplete_RequestNotFound(t *testing.T) {
 		t.Fatal("expected error for nonexistent request")
 	}
 }
+
+func TestVerifier_VerifyExecutionAllowed_EnvironmentFingerprint(t *testing.T) {
+	database := setupTestDB(t)
+	v := NewVerifier(database)
+
+	createTestSession(t, database, "sess1")
+	now := time.Now().UTC()
+	expiresAt := now.Add(30 * time.Minute)
+	approvalExpiresAt := now.Add(5 * time.Minute)
+	request := &db.Request{
+		ID:                 "req-env",
+		ProjectPath:        "/test/project",
+		Command:            db.CommandSpec{Raw: "kubectl delete deployment api", Cwd: "/tmp", Hash: "testhash123"},
+		RiskTier:           db.RiskTierDangerous,
+		RequestorSessionID: "sess1",
+		RequestorAgent:     "TestAgent",
+		RequestorModel:     "test-model",
+		Justification:      db.Justification{Reason: "Testing execution"},
+		Environment:        db.EnvironmentFingerprint{"kube.context": "staging"},
+		Status:             db.StatusApproved,
+		MinApprovals:       1,
+		ExpiresAt:          &expiresAt,
+		ApprovalExpiresAt:  &approvalExpiresAt,
+	}
+	if err := database.CreateRequest(request); err != nil {
+		t.Fatalf("failed to create request: %v", err)
+	}
+	createTestSession(t, database, "reviewer-sess")
+	createTestReview(t, database, "req-env", "reviewer-sess", db.DecisionApprove)
+
+	for name, env := range map[string]db.EnvironmentFingerprint{
+		"missing":   nil,
+		"different": {"kube.context": "production"},
+	} {
+		result, err := v.VerifyExecutionAllowed("req-env", "sess1", env)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", name, err)
+		}
+		if result.Allowed || !strings.Contains(result.Reason, "kube.context") {
+			t.Errorf("%s: expected refusal naming kube.context, got %+v", name, result)
+		}
+	}
+
+	result, err := v.VerifyAndMarkExecuting("req-env", "sess1", db.EnvironmentFingerprint{"kube.context": "staging"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !result.Allowed {
+		t.Errorf("expected Allowed=true with matching fingerprint, got reason %q", result.Reason)
+	}
+}
+
+func TestVerifier_VerifyExecutionAllowed_Window(t *testing.T) {
+	database := setupTestDB(t)
+	createTestSession(t, database, "sess1")
+	createTestSession(t, database, "reviewer-sess")
+
+	// The window has not opened yet.
+	now := time.Now().UTC()
+	notBefore := now.Add(time.Hour).Truncate(time.Second)
+	approvalExpiresAt := notBefore.Add(30 * time.Minute)
+	request := &db.Request{
+		ID:                 "req-window",
+		ProjectPath:        "/test/project",
+		Command:            db.CommandSpec{Raw: "rm -rf /tmp/test", Cwd: "/tmp", Hash: "testhash123"},
+		RiskTier:           db.RiskTierDangerous,
+		RequestorSessionID: "sess1",
+		RequestorAgent:     "TestAgent",
+		RequestorModel:     "test-model",
+		Justification:      db.Justification{Reason: "Testing execution"},
+		Status:             db.StatusApproved,
+		MinApprovals:       1,
+		ApprovalExpiresAt:  &approvalExpiresAt,
+		Window:             &db.ExecutionWindow{NotBefore: &notBefore},
+	}
+	if err := database.CreateRequest(request); err != nil {
+		t.Fatalf("failed to create request: %v", err)
+	}
+	createTestReview(t, database, "req-window", "reviewer-sess", db.DecisionApprove)
+
+	result, err := NewVerifier(database).VerifyExecutionAllowed("req-window", "sess1", nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Allowed {
+		t.Error("expected Allowed=false before the window opens")
+	}
+	if result.WindowOpensAt == nil || !result.WindowOpensAt.Equal(notBefore) {
+		t.Errorf("WindowOpensAt = %v, want %v", result.WindowOpensAt, notBefore)
+	}
+	if !strings.Contains(result.Reason, "outside the execution window") {
+		t.Errorf("unexpected reason %q", result.Reason)
+	}
+
+	// Once open, the approval TTL counted from the opening still applies.
+	opened := now.Add(-time.Hour)
+	expired := now.Add(-time.Minute)
+	request.ID = "req-window-expired"
+	request.Window = &db.ExecutionWindow{NotBefore: &opened}
+	request.ApprovalExpiresAt = &expired
+	if err := database.CreateRequest(request); err != nil {
+		t.Fatalf("failed to create request: %v", err)
+	}
+	createTestReview(t, database, request.ID, "reviewer-sess", db.DecisionApprove)
+	result, err = NewVerifier(database).VerifyExecutionAllowed(request.ID, "sess1", nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Allowed || result.Reason != "approval has expired" {
+		t.Errorf("expected an expired approval, got allowed=%v reason=%q", result.Allowed, result.Reason)
+	}
+}
+
+func TestVerifier_VerifyExecutionAllowed_FreezePeriod(t *testing.T) {
+	database := setupTestDB(t)
+	createTestSession(t, database, "sess1")
+	createTestRequest(t, database, "req1", "sess1", db.StatusApproved, 1)
+	createTestSession(t, database, "reviewer-sess")
+	createTestReview(t, database, "req1", "reviewer-sess", db.DecisionApprove)
+
+	always, err := schedule.NewWindow("* * * * *", "1h", "UTC")
+	if err != nil {
+		t.Fatalf("NewWindow: %v", err)
+	}
+	v := NewVerifier(database).WithSchedule(&core.ChangeSchedule{
+		Freezes: []core.FreezePeriod{{Name: "release", Window: always}},
+	})
+
+	result, err := v.VerifyExecutionAllowed("req1", "sess1", nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Allowed || !strings.Contains(result.Reason, `frozen by "release"`) {
+		t.Errorf("expected a freeze denial, got allowed=%v reason=%q", result.Allowed, result.Reason)
+	}
+}
+
+func TestVerifier_VerifyExecutionAllowed_ChangeFreeze(t *testing.T) {
+	database := setupTestDB(t)
+	createTestSession(t, database, "sess1")
+	createTestRequest(t, database, "req1", "sess1", db.StatusApproved, 1)
+	createTestSession(t, database, "reviewer-sess")
+	createTestReview(t, database, "req1", "reviewer-sess", db.DecisionApprove)
+
+	if err := database.CreateFreeze(&db.Freeze{ProjectPath: "/test/project", Reason: "incident", StartedBy: "ops"}); err != nil {
+		t.Fatalf("CreateFreeze: %v", err)
+	}
+
+	result, err := NewVerifier(database).VerifyExecutionAllowed("req1", "sess1", nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Allowed || !strings.Contains(result.Reason, "change freeze in effect") {
+		t.Errorf("expected a change freeze denial, got allowed=%v reason=%q", result.Allowed, result.Reason)
+	}
+}
+
+func TestVerifier_VerifyExecutionAllowed_PolicyExecutor(t *testing.T) {
+	database := setupTestDB(t)
+	createTestSession(t, database, "sess1")
+	createTestSession(t, database, "reviewer-sess")
+	approvalExpiresAt := time.Now().UTC().Add(5 * time.Minute)
+	request := &db.Request{
+		ID:                 "req1",
+		ProjectPath:        "/test/project",
+		Command:            db.CommandSpec{Raw: "rm -rf /tmp/test", Cwd: "/tmp"},
+		RiskTier:           db.RiskTierDangerous,
+		RequestorSessionID: "sess1",
+		Justification:      db.Justification{Reason: "Testing execution"},
+		Status:             db.StatusApproved,
+		MinApprovals:       1,
+		ApprovalExpiresAt:  &approvalExpiresAt,
+		Policy: &db.PolicyDecision{
+			Rules:     []string{"deployers"},
+			Executors: []db.PolicyConstraint{{Rule: "deployers", Agents: []string{"Agent-reviewer-*"}}},
+		},
+	}
+	if err := database.CreateRequest(request); err != nil {
+		t.Fatalf("CreateRequest: %v", err)
+	}
+	createTestReview(t, database, "req1", "reviewer-sess", db.DecisionApprove)
+
+	verifier := NewVerifier(database)
+	result, err := verifier.VerifyExecutionAllowed("req1", "sess1", nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Allowed || !strings.Contains(result.Reason, `rule "deployers"`) {
+		t.Errorf("expected a policy denial, got allowed=%v reason=%q", result.Allowed, result.Reason)
+	}
+	if result, err = verifier.VerifyExecutionAllowed("req1", "reviewer-sess", nil); err != nil || !result.Allowed {
+		t.Errorf("allowed executor: result=%+v err=%v", result, err)
+	}
+}
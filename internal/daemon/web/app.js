@@ -0,0 +1,385 @@
+// SLB browser review dashboard. Talks to the daemon's /v1 HTTP gateway with the
+// operator's session key as a bearer token; reviews are signed server-side with
+// that key exactly like `slb approve` / `slb reject`.
+(function () {
+  "use strict";
+
+  const KEY_STORAGE = "slb.sessionKey";
+  const $ = (id) => document.getElementById(id);
+
+  const state = {
+    key: sessionStorage.getItem(KEY_STORAGE) || "",
+    operator: null,
+    requests: [],
+    selected: 0,
+    detail: null,
+    streamAbort: null,
+    busy: false,
+  };
+
+  // --- API ---------------------------------------------------------------
+
+  async function api(method, path, body) {
+    const opts = { method, headers: { Authorization: "Bearer " + state.key } };
+    if (body !== undefined) {
+      opts.headers["Content-Type"] = "application/json";
+      opts.body = JSON.stringify(body);
+    }
+    const resp = await fetch(path, opts);
+    const data = await resp.json().catch(() => ({}));
+    if (!resp.ok) {
+      const err = new Error(data.error || resp.statusText);
+      err.status = resp.status;
+      throw err;
+    }
+    return data;
+  }
+
+  // --- sign-in -----------------------------------------------------------
+
+  async function signIn(key) {
+    state.key = key;
+    const op = await api("GET", "/v1/session");
+    if (!op.session) {
+      throw new Error("no active session for this key");
+    }
+    state.operator = op.session;
+    sessionStorage.setItem(KEY_STORAGE, key);
+    $("operator").textContent = "Signed in as " + op.session.agent_name + " (" + op.session.model + ")";
+    $("signout").hidden = false;
+    $("login").hidden = true;
+    $("app").hidden = false;
+    await refresh();
+    connectEvents();
+  }
+
+  function signOut() {
+    sessionStorage.removeItem(KEY_STORAGE);
+    if (state.streamAbort) state.streamAbort.abort();
+    state.key = "";
+    state.operator = null;
+    $("operator").textContent = "not signed in";
+    $("signout").hidden = true;
+    $("app").hidden = true;
+    $("login").hidden = false;
+    setConn("offline");
+    $("key").focus();
+  }
+
+  // --- list --------------------------------------------------------------
+
+  async function refresh() {
+    const selectedID = currentID();
+    state.requests = await api("GET", "/v1/requests?status=pending");
+    const idx = state.requests.findIndex((r) => r.id === selectedID);
+    state.selected = idx >= 0 ? idx : Math.min(state.selected, Math.max(state.requests.length - 1, 0));
+    renderList();
+    await loadDetail();
+  }
+
+  function currentID() {
+    const r = state.requests[state.selected];
+    return r ? r.id : "";
+  }
+
+  function renderList() {
+    const ul = $("requests");
+    ul.replaceChildren();
+    $("count").textContent = "(" + state.requests.length + ")";
+    $("empty").hidden = state.requests.length > 0;
+
+    state.requests.forEach((r, i) => {
+      const li = document.createElement("li");
+      li.setAttribute("role", "option");
+      if (i === state.selected) li.className = "selected";
+      li.append(tierBadge(r.risk_tier));
+      const cmd = document.createElement("span");
+      cmd.className = "cmd";
+      cmd.textContent = displayCommand(r);
+      li.append(cmd);
+      const sub = document.createElement("span");
+      sub.className = "sub";
+      sub.textContent = r.requestor_agent + " · " + age(r.created_at);
+      li.append(sub);
+      li.addEventListener("click", () => select(i));
+      ul.append(li);
+    });
+    const sel = ul.children[state.selected];
+    if (sel) sel.scrollIntoView({ block: "nearest" });
+  }
+
+  async function select(i) {
+    if (i < 0 || i >= state.requests.length) return;
+    state.selected = i;
+    renderList();
+    await loadDetail();
+  }
+
+  // --- detail ------------------------------------------------------------
+
+  async function loadDetail() {
+    const id = currentID();
+    if (!id) {
+      state.detail = null;
+      $("detail").hidden = true;
+      $("detail-empty").hidden = false;
+      return;
+    }
+    state.detail = await api("GET", "/v1/requests/" + encodeURIComponent(id));
+    renderDetail();
+  }
+
+  function renderDetail() {
+    const d = state.detail;
+    const r = d.request;
+    $("detail-empty").hidden = true;
+    $("detail").hidden = false;
+
+    const tier = $("d-tier");
+    tier.className = "tier " + r.risk_tier;
+    tier.textContent = r.risk_tier;
+    $("d-command").textContent = displayCommand(r);
+    $("d-requestor").textContent = r.requestor_agent + " (" + r.requestor_model + ")";
+    $("d-created").textContent = new Date(r.created_at).toLocaleString() + " · " + age(r.created_at);
+    $("d-approvals").textContent = d.approvals + " / " + r.min_approvals + (d.rejections ? " · " + d.rejections + " rejected" : "");
+    $("d-cwd").textContent = (r.command && r.command.cwd) || "";
+
+    const just = $("d-justification");
+    just.replaceChildren();
+    const j = r.justification || {};
+    [["Reason", j.reason], ["Expected effect", j.expected_effect], ["Goal", j.goal], ["Safety", j.safety_argument]]
+      .filter(([, v]) => v)
+      .forEach(([k, v]) => {
+        const dt = document.createElement("dt");
+        dt.textContent = k;
+        const dd = document.createElement("dd");
+        dd.textContent = v;
+        just.append(dt, dd);
+      });
+
+    $("d-dryrun-block").hidden = !r.dry_run;
+    if (r.dry_run) {
+      $("d-dryrun-cmd").textContent = r.dry_run.command || "";
+      $("d-dryrun").textContent = r.dry_run.output || "(no output)";
+    }
+
+    const atts = r.attachments || [];
+    $("d-attachments-block").hidden = atts.length === 0;
+    const attBox = $("d-attachments");
+    attBox.replaceChildren();
+    atts.forEach((a) => {
+      const h = document.createElement("div");
+      h.className = "muted";
+      const name = a.metadata && (a.metadata.path || a.metadata.name);
+      h.textContent = a.type + (name ? " · " + name : "");
+      const pre = document.createElement("pre");
+      pre.textContent = a.content;
+      attBox.append(h, pre);
+    });
+
+    const reviews = $("d-reviews");
+    reviews.replaceChildren();
+    if (!d.reviews || d.reviews.length === 0) {
+      const li = document.createElement("li");
+      li.className = "muted";
+      li.textContent = "No reviews yet.";
+      reviews.append(li);
+    } else {
+      d.reviews.forEach((rv) => {
+        const li = document.createElement("li");
+        li.className = rv.decision;
+        li.textContent = rv.decision + " by " + rv.reviewer_agent + " (" + rv.reviewer_model + ")" +
+          (rv.comments ? ": " + rv.comments : "");
+        reviews.append(li);
+      });
+    }
+
+    const own = r.requestor_session_id === state.operator.id;
+    $("approve").disabled = own;
+    $("reject").disabled = own;
+    setStatus(own ? "You cannot review your own request." : "", "muted");
+  }
+
+  // --- actions -----------------------------------------------------------
+
+  async function review(decision) {
+    const d = state.detail;
+    if (!d || state.busy) return;
+    const comments = $("comments").value.trim();
+    if (decision === "reject" && !comments) {
+      setStatus("Add a comment explaining the rejection.", "error");
+      $("comments").focus();
+      return;
+    }
+    if (d.request.risk_tier === "critical" && decision === "approve" &&
+        !window.confirm("Approve CRITICAL command?\n\n" + displayCommand(d.request))) {
+      return;
+    }
+
+    state.busy = true;
+    try {
+      const res = await api("POST", "/v1/requests/" + encodeURIComponent(d.request.id) + "/reviews", {
+        decision,
+        comments,
+      });
+      $("comments").value = "";
+      setStatus(decision + "d" + (res.new_request_status ? " · request is now " + res.new_request_status : ""), "ok");
+      await refresh();
+    } catch (err) {
+      setStatus(err.message, "error");
+    } finally {
+      state.busy = false;
+    }
+  }
+
+  function setStatus(msg, cls) {
+    const el = $("action-status");
+    el.className = cls || "";
+    el.textContent = msg;
+  }
+
+  // --- live events -------------------------------------------------------
+
+  // EventSource cannot send an Authorization header, so the SSE stream is read
+  // with fetch and parsed by hand.
+  async function connectEvents() {
+    let backoff = 1000;
+    while (state.key) {
+      const ctrl = new AbortController();
+      state.streamAbort = ctrl;
+      setConn("connecting");
+      try {
+        const resp = await fetch("/v1/events", {
+          headers: { Authorization: "Bearer " + state.key },
+          signal: ctrl.signal,
+        });
+        if (resp.status === 401) {
+          signOut();
+          return;
+        }
+        if (!resp.ok || !resp.body) throw new Error(resp.statusText);
+        setConn("live");
+        backoff = 1000;
+        await refresh();
+        await readStream(resp.body.getReader());
+      } catch (err) {
+        if (ctrl.signal.aborted) return;
+      }
+      setConn("offline");
+      await new Promise((r) => setTimeout(r, backoff));
+      backoff = Math.min(backoff * 2, 30000);
+    }
+  }
+
+  async function readStream(reader) {
+    const decoder = new TextDecoder();
+    let buf = "";
+    for (;;) {
+      const { value, done } = await reader.read();
+      if (done) return;
+      buf += decoder.decode(value, { stream: true });
+      let idx;
+      while ((idx = buf.indexOf("\n\n")) >= 0) {
+        const chunk = buf.slice(0, idx);
+        buf = buf.slice(idx + 2);
+        if (chunk.split("\n").some((l) => l.startsWith("event:"))) {
+          refresh().catch(() => {});
+        }
+      }
+    }
+  }
+
+  function setConn(s) {
+    const el = $("conn");
+    el.className = "conn " + s;
+    el.textContent = s;
+  }
+
+  // --- keyboard ----------------------------------------------------------
+
+  document.addEventListener("keydown", (e) => {
+    if ($("app").hidden) return;
+    const typing = document.activeElement === $("comments");
+    if (typing) {
+      if (e.key === "Escape") $("comments").blur();
+      return;
+    }
+    if (e.ctrlKey || e.metaKey || e.altKey) return;
+
+    switch (e.key) {
+      case "ArrowUp":
+      case "k":
+        select(state.selected - 1);
+        break;
+      case "ArrowDown":
+      case "j":
+        select(state.selected + 1);
+        break;
+      case "Enter":
+      case "d":
+        $("comments").focus();
+        break;
+      case "a":
+        review("approve");
+        break;
+      case "x":
+        review("reject");
+        break;
+      case "r":
+        refresh().catch((err) => setStatus(err.message, "error"));
+        break;
+      case "?":
+      case "h":
+        $("help").hidden = !$("help").hidden;
+        break;
+      case "Escape":
+        $("help").hidden = true;
+        break;
+      default:
+        return;
+    }
+    e.preventDefault();
+  });
+
+  // --- helpers -----------------------------------------------------------
+
+  function tierBadge(tier) {
+    const span = document.createElement("span");
+    span.className = "tier " + tier;
+    span.textContent = tier;
+    return span;
+  }
+
+  function displayCommand(r) {
+    return (r.command && (r.command.display_redacted || r.command.raw)) || "";
+  }
+
+  function age(ts) {
+    const secs = Math.max(0, Math.floor((Date.now() - new Date(ts).getTime()) / 1000));
+    if (secs < 60) return secs + "s ago";
+    if (secs < 3600) return Math.floor(secs / 60) + "m ago";
+    if (secs < 86400) return Math.floor(secs / 3600) + "h ago";
+    return Math.floor(secs / 86400) + "d ago";
+  }
+
+  // --- boot --------------------------------------------------------------
+
+  $("login-form").addEventListener("submit", (e) => {
+    e.preventDefault();
+    $("login-error").textContent = "";
+    signIn($("key").value.trim()).catch((err) => {
+      state.key = "";
+      $("login-error").textContent = err.message;
+    });
+  });
+  $("signout").addEventListener("click", signOut);
+  $("approve").addEventListener("click", () => review("approve"));
+  $("reject").addEventListener("click", () => review("reject"));
+
+  if (state.key) {
+    signIn(state.key).catch(signOut);
+  } else {
+    $("login").hidden = false;
+    $("key").focus();
+  }
+})();
@@ -0,0 +1,496 @@
+package daemon
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"html"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Dicklesworthstone/slb/internal/config"
+	"github.com/Dicklesworthstone/slb/internal/db"
+	"github.com/charmbracelet/log"
+)
+
+// Webhook signing headers. The signature is HMAC-SHA256 over
+// "<timestamp>.<body>" with the endpoint secret, hex encoded and prefixed
+// with "sha256=".
+const (
+	WebhookHeaderEvent     = "X-SLB-Event"
+	WebhookHeaderDelivery  = "X-SLB-Delivery"
+	WebhookHeaderTimestamp = "X-SLB-Timestamp"
+	WebhookHeaderSignature = "X-SLB-Signature"
+)
+
+// webhookMaxRetryDelay caps the exponential backoff between attempts.
+const webhookMaxRetryDelay = time.Hour
+
+// Events for named webhook endpoints. request_pending covers all tiers; use
+// an endpoint's tier filter to narrow it.
+const (
+	WebhookEventRequestPending   WebhookEvent = "request_pending"
+	WebhookEventRequestApproved  WebhookEvent = "request_approved"
+	WebhookEventRequestRejected  WebhookEvent = "request_rejected"
+	WebhookEventRequestExecuted  WebhookEvent = "request_executed"
+	WebhookEventRequestCancelled WebhookEvent = "request_cancelled"
+)
+
+// WebhookReview is a review as reported in webhook payloads (no signature).
+type WebhookReview struct {
+	Reviewer  string `json:"reviewer"`
+	Model     string `json:"model"`
+	Decision  string `json:"decision"`
+	Comments  string `json:"comments,omitempty"`
+	CreatedAt string `json:"created_at"`
+}
+
+// WebhookExecution summarizes how an approved command ran.
+type WebhookExecution struct {
+	ExecutedBy string `json:"executed_by,omitempty"`
+	ExecutedAt string `json:"executed_at,omitempty"`
+	ExitCode   *int   `json:"exit_code,omitempty"`
+	DurationMs *int64 `json:"duration_ms,omitempty"`
+}
+
+// webhookEventForStatus maps a request status to the event announcing it.
+func webhookEventForStatus(status db.RequestStatus) (WebhookEvent, bool) {
+	switch status {
+	case db.StatusPending:
+		return WebhookEventRequestPending, true
+	case db.StatusApproved:
+		return WebhookEventRequestApproved, true
+	case db.StatusRejected:
+		return WebhookEventRequestRejected, true
+	case db.StatusExecuted, db.StatusExecutionFailed, db.StatusTimedOut:
+		return WebhookEventRequestExecuted, true
+	case db.StatusCancelled:
+		return WebhookEventRequestCancelled, true
+	case db.StatusTimeout:
+		return WebhookEventRequestTimeout, true
+	case db.StatusEscalated:
+		return WebhookEventRequestEscalated, true
+	default:
+		return "", false
+	}
+}
+
+// buildWebhookPayload fills a payload from a request and, for later-stage
+// events, its reviews and execution outcome.
+func buildWebhookPayload(event WebhookEvent, req *db.Request, reviews []*db.Review, outcome *db.ExecutionOutcome, project string, now time.Time) WebhookPayload {
+	just := req.Justification
+	p := WebhookPayload{
+		Event:         event,
+		RequestID:     req.ID,
+		Command:       truncateRunes(displayCommand(req), 140),
+		Tier:          string(req.RiskTier),
+		Requestor:     req.RequestorAgent,
+		Timestamp:     now.UTC().Format(time.RFC3339),
+		Project:       project,
+		Status:        string(req.Status),
+		Justification: &just,
+		Outcome:       outcome,
+	}
+	for _, rv := range reviews {
+		p.Reviews = append(p.Reviews, WebhookReview{
+			Reviewer:  rv.ReviewerAgent,
+			Model:     rv.ReviewerModel,
+			Decision:  string(rv.Decision),
+			Comments:  rv.Comments,
+			CreatedAt: rv.CreatedAt.UTC().Format(time.RFC3339),
+		})
+	}
+	if ex := req.Execution; ex != nil && (ex.ExecutedAt != nil || ex.ExitCode != nil) {
+		we := &WebhookExecution{ExecutedBy: ex.ExecutedByAgent, ExitCode: ex.ExitCode, DurationMs: ex.DurationMs}
+		if ex.ExecutedAt != nil {
+			we.ExecutedAt = ex.ExecutedAt.UTC().Format(time.RFC3339)
+		}
+		p.Execution = we
+	}
+	return p
+}
+
+// SignWebhook returns the X-SLB-Signature value for body sent at timestamp.
+func SignWebhook(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// VerifyWebhookSignature checks the signature headers of a received webhook.
+// Timestamps further than tolerance from now are rejected to limit replays.
+func VerifyWebhookSignature(secret, timestampHeader, signatureHeader string, body []byte, tolerance time.Duration, now time.Time) error {
+	ts, err := strconv.ParseInt(strings.TrimSpace(timestampHeader), 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid %s header", WebhookHeaderTimestamp)
+	}
+	if tolerance > 0 {
+		skew := now.Sub(time.Unix(ts, 0))
+		if skew < 0 {
+			skew = -skew
+		}
+		if skew > tolerance {
+			return fmt.Errorf("webhook timestamp outside tolerance")
+		}
+	}
+	want := SignWebhook(secret, ts, body)
+	if !hmac.Equal([]byte(want), []byte(strings.TrimSpace(signatureHeader))) {
+		return fmt.Errorf("webhook signature mismatch")
+	}
+	return nil
+}
+
+// RenderWebhookBody renders a payload in an endpoint format: json (the raw
+// payload), slack, discord, teams (MessageCard) or matrix (hookshot
+// text/html).
+func RenderWebhookBody(format string, p WebhookPayload) ([]byte, error) {
+	title := webhookTitle(p)
+	lines := webhookSummaryLines(p)
+
+	var v any
+	switch format {
+	case "", "json":
+		v = p
+	case "slack":
+		v = map[string]any{
+			"text": title,
+			"blocks": []any{
+				map[string]any{"type": "header", "text": map[string]any{"type": "plain_text", "text": title}},
+				map[string]any{"type": "section", "text": map[string]any{"type": "mrkdwn", "text": "```" + p.Command + "```\n" + strings.Join(lines, "\n")}},
+			},
+		}
+	case "discord":
+		v = map[string]any{
+			"content": title,
+			"embeds": []any{map[string]any{
+				"title":       truncateRunes(p.Command, 250),
+				"description": strings.Join(lines, "\n"),
+				"color":       webhookTierColor(p.Tier),
+				"timestamp":   p.Timestamp,
+			}},
+		}
+	case "teams":
+		v = map[string]any{
+			"@type":      "MessageCard",
+			"@context":   "https://schema.org/extensions",
+			"summary":    title,
+			"themeColor": fmt.Sprintf("%06X", webhookTierColor(p.Tier)),
+			"title":      title,
+			"text":       "`" + p.Command + "`<br>" + strings.Join(lines, "<br>"),
+		}
+	case "matrix":
+		var h strings.Builder
+		h.WriteString("<p><strong>" + html.EscapeString(title) + "</strong></p><pre><code>" + html.EscapeString(p.Command) + "</code></pre><ul>")
+		for _, l := range lines {
+			h.WriteString("<li>" + html.EscapeString(l) + "</li>")
+		}
+		h.WriteString("</ul>")
+		v = map[string]any{
+			"text": title + "\n" + p.Command + "\n" + strings.Join(lines, "\n"),
+			"html": h.String(),
+		}
+	default:
+		return nil, fmt.Errorf("unknown webhook format %q", format)
+	}
+	return json.Marshal(v)
+}
+
+func webhookTitle(p WebhookPayload) string {
+	tier := strings.ToUpper(p.Tier)
+	switch p.Event {
+	case WebhookEventRequestPending, WebhookEventCriticalPending, WebhookEventDangerousPending:
+		return fmt.Sprintf("SLB: %s request pending", tier)
+	case WebhookEventRequestApproved:
+		return fmt.Sprintf("SLB: %s request approved", tier)
+	case WebhookEventRequestRejected:
+		return fmt.Sprintf("SLB: %s request rejected", tier)
+	case WebhookEventRequestExecuted:
+		if p.Execution != nil && p.Execution.ExitCode != nil && *p.Execution.ExitCode != 0 {
+			return fmt.Sprintf("SLB: %s request executed (exit %d)", tier, *p.Execution.ExitCode)
+		}
+		return fmt.Sprintf("SLB: %s request executed", tier)
+	case WebhookEventRequestCancelled:
+		return fmt.Sprintf("SLB: %s request cancelled", tier)
+	case WebhookEventRequestTimeout:
+		return fmt.Sprintf("SLB: %s request timed out", tier)
+	case WebhookEventRequestEscalated:
+		return fmt.Sprintf("SLB: %s request escalated", tier)
+	default:
+		return "SLB: " + string(p.Event)
+	}
+}
+
+func webhookSummaryLines(p WebhookPayload) []string {
+	lines := []string{"Requestor: " + p.Requestor, "Request: " + p.RequestID}
+	if p.Justification != nil && p.Justification.Reason != "" {
+		lines = append(lines, "Reason: "+p.Justification.Reason)
+	}
+	for _, rv := range p.Reviews {
+		line := fmt.Sprintf("%s by %s", rv.Decision, rv.Reviewer)
+		if rv.Comments != "" {
+			line += ": " + rv.Comments
+		}
+		lines = append(lines, line)
+	}
+	if p.Execution != nil && p.Execution.ExitCode != nil {
+		lines = append(lines, fmt.Sprintf("Exit code: %d", *p.Execution.ExitCode))
+	}
+	if p.Event == WebhookEventRequestPending || p.Event == WebhookEventCriticalPending || p.Event == WebhookEventDangerousPending {
+		lines = append(lines, "Review: slb review "+p.RequestID)
+	}
+	return lines
+}
+
+func webhookTierColor(tier string) int {
+	switch tier {
+	case string(db.RiskTierCritical):
+		return 0xE01E5A
+	case string(db.RiskTierDangerous):
+		return 0xF2994A
+	case string(db.RiskTierCaution):
+		return 0xF2C94C
+	default:
+		return 0x2EB67D
+	}
+}
+
+type webhookEndpoint struct {
+	config.WebhookEndpointConfig
+	secret string
+	events map[string]bool
+	tiers  map[string]bool
+}
+
+func (e *webhookEndpoint) matches(event WebhookEvent, tier string) bool {
+	if len(e.events) > 0 && !e.events[string(event)] {
+		return false
+	}
+	if len(e.tiers) > 0 && !e.tiers[strings.ToLower(tier)] {
+		return false
+	}
+	return true
+}
+
+// WebhookDispatcher delivers events to the named webhook endpoints. Every
+// delivery is recorded in the project database; failed deliveries are retried
+// with exponential backoff and end in the dead-letter queue (status "dead")
+// once their attempts are exhausted.
+type WebhookDispatcher struct {
+	endpoints   map[string]*webhookEndpoint
+	order       []string
+	maxAttempts int
+	baseDelay   time.Duration
+	openDB      func() (*db.DB, error)
+	client      *http.Client
+	logger      *log.Logger
+	now         func() time.Time
+}
+
+// NewWebhookDispatcher builds a dispatcher for cfg.Webhooks. It returns nil when
+// no endpoints are configured.
+func NewWebhookDispatcher(cfg config.NotificationsConfig, openDB func() (*db.DB, error), logger *log.Logger) *WebhookDispatcher {
+	if len(cfg.Webhooks) == 0 {
+		return nil
+	}
+	if logger == nil {
+		logger = log.Default()
+	}
+	d := &WebhookDispatcher{
+		endpoints:   make(map[string]*webhookEndpoint, len(cfg.Webhooks)),
+		maxAttempts: cfg.WebhookMaxAttempts,
+		baseDelay:   time.Duration(cfg.WebhookRetryBaseSecs) * time.Second,
+		openDB:      openDB,
+		client:      &http.Client{Timeout: WebhookTimeout},
+		logger:      logger,
+		now:         time.Now,
+	}
+	if d.maxAttempts < 1 {
+		d.maxAttempts = 1
+	}
+	for _, wc := range cfg.Webhooks {
+		ep := &webhookEndpoint{
+			WebhookEndpointConfig: wc,
+			secret:                wc.Secret,
+			events:                make(map[string]bool),
+			tiers:                 make(map[string]bool),
+		}
+		if wc.SecretEnv != "" {
+			ep.secret = os.Getenv(wc.SecretEnv)
+		}
+		for _, ev := range wc.Events {
+			ep.events[ev] = true
+		}
+		for _, tier := range wc.Tiers {
+			ep.tiers[strings.ToLower(tier)] = true
+		}
+		d.endpoints[wc.Name] = ep
+		d.order = append(d.order, wc.Name)
+	}
+	return d
+}
+
+// Dispatch records a delivery for every endpoint whose filters match and makes
+// the first attempt immediately.
+func (d *WebhookDispatcher) Dispatch(ctx context.Context, payload WebhookPayload) error {
+	if d == nil {
+		return nil
+	}
+	dbConn, err := d.openDB()
+	if err != nil {
+		return fmt.Errorf("opening webhook store: %w", err)
+	}
+	defer dbConn.Close()
+
+	for _, name := range d.order {
+		ep := d.endpoints[name]
+		if !ep.matches(payload.Event, payload.Tier) {
+			continue
+		}
+		body, err := RenderWebhookBody(ep.Format, payload)
+		if err != nil {
+			d.logger.Warn("rendering webhook failed", "endpoint", name, "error", err)
+			continue
+		}
+		delivery := &db.WebhookDelivery{
+			Endpoint:  name,
+			URL:       ep.URL,
+			Event:     string(payload.Event),
+			RequestID: payload.RequestID,
+			Body:      string(body),
+			CreatedAt: d.now().UTC(),
+		}
+		if err := dbConn.CreateWebhookDelivery(delivery); err != nil {
+			return err
+		}
+		d.attempt(ctx, dbConn, delivery)
+	}
+	return nil
+}
+
+// ProcessDue retries deliveries whose backoff has elapsed.
+func (d *WebhookDispatcher) ProcessDue(ctx context.Context) error {
+	if d == nil {
+		return nil
+	}
+	dbConn, err := d.openDB()
+	if err != nil {
+		return fmt.Errorf("opening webhook store: %w", err)
+	}
+	defer dbConn.Close()
+
+	due, err := dbConn.ListDueWebhookDeliveries(d.now(), 100)
+	if err != nil {
+		return err
+	}
+	for _, delivery := range due {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		d.attempt(ctx, dbConn, delivery)
+	}
+	return nil
+}
+
+func (d *WebhookDispatcher) attempt(ctx context.Context, dbConn *db.DB, delivery *db.WebhookDelivery) {
+	delivery.Attempts++
+	now := d.now().UTC()
+
+	ep, ok := d.endpoints[delivery.Endpoint]
+	var (
+		code int
+		err  error
+	)
+	if !ok {
+		err = fmt.Errorf("endpoint %q is no longer configured", delivery.Endpoint)
+		delivery.Attempts = d.maxAttempts
+	} else {
+		code, err = d.post(ctx, ep, delivery, now)
+	}
+
+	delivery.LastError = ""
+	delivery.LastStatusCode = nil
+	if code != 0 {
+		delivery.LastStatusCode = &code
+	}
+
+	switch {
+	case err == nil:
+		delivery.Status = db.WebhookDelivered
+		delivery.DeliveredAt = &now
+		delivery.NextAttemptAt = nil
+	case delivery.Attempts >= d.maxAttempts:
+		delivery.Status = db.WebhookDead
+		delivery.LastError = err.Error()
+		delivery.NextAttemptAt = nil
+		d.logger.Warn("webhook moved to dead-letter queue",
+			"endpoint", delivery.Endpoint, "delivery", delivery.ID, "attempts", delivery.Attempts, "error", err)
+	default:
+		delivery.Status = db.WebhookRetrying
+		delivery.LastError = err.Error()
+		next := now.Add(webhookBackoff(d.baseDelay, delivery.Attempts))
+		delivery.NextAttemptAt = &next
+		d.logger.Debug("webhook delivery failed; will retry",
+			"endpoint", delivery.Endpoint, "delivery", delivery.ID, "attempt", delivery.Attempts, "next", next, "error", err)
+	}
+
+	if err := dbConn.UpdateWebhookDelivery(delivery); err != nil {
+		d.logger.Warn("recording webhook delivery failed", "delivery", delivery.ID, "error", err)
+	}
+}
+
+func (d *WebhookDispatcher) post(ctx context.Context, ep *webhookEndpoint, delivery *db.WebhookDelivery, now time.Time) (int, error) {
+	reqCtx, cancel := context.WithTimeout(ctx, WebhookTimeout)
+	defer cancel()
+
+	body := []byte(delivery.Body)
+	req, err := http.NewRequestWithContext(reqCtx, http.MethodPost, ep.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, fmt.Errorf("creating webhook request: %w", err)
+	}
+	ts := now.Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "SLB-Webhook/1.0")
+	req.Header.Set(WebhookHeaderEvent, delivery.Event)
+	req.Header.Set(WebhookHeaderDelivery, strconv.FormatInt(delivery.ID, 10))
+	req.Header.Set(WebhookHeaderTimestamp, strconv.FormatInt(ts, 10))
+	if ep.secret != "" {
+		req.Header.Set(WebhookHeaderSignature, SignWebhook(ep.secret, ts, body))
+	}
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("sending webhook: %w", err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, fmt.Errorf("webhook returned status %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
+
+// webhookBackoff returns base * 2^(attempt-1), capped at webhookMaxRetryDelay.
+func webhookBackoff(base time.Duration, attempt int) time.Duration {
+	if base <= 0 {
+		return 0
+	}
+	delay := base
+	for i := 1; i < attempt; i++ {
+		delay *= 2
+		if delay >= webhookMaxRetryDelay {
+			return webhookMaxRetryDelay
+		}
+	}
+	return delay
+}
@@ -0,0 +1,299 @@
+package daemon
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Dicklesworthstone/slb/internal/config"
+	"github.com/Dicklesworthstone/slb/internal/db"
+)
+
+type webhookHit struct {
+	Header http.Header
+	Body   []byte
+}
+
+type webhookSink struct {
+	*httptest.Server
+	mu     sync.Mutex
+	hits   []webhookHit
+	status int
+}
+
+func startWebhookSink(t *testing.T) *webhookSink {
+	t.Helper()
+	sink := &webhookSink{status: http.StatusOK}
+	sink.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		sink.mu.Lock()
+		sink.hits = append(sink.hits, webhookHit{Header: r.Header.Clone(), Body: body})
+		status := sink.status
+		sink.mu.Unlock()
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(sink.Close)
+	return sink
+}
+
+func (s *webhookSink) received() []webhookHit {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]webhookHit(nil), s.hits...)
+}
+
+func (s *webhookSink) setStatus(code int) {
+	s.mu.Lock()
+	s.status = code
+	s.mu.Unlock()
+}
+
+func openWebhookTestDB(t *testing.T) (string, *db.DB, func() (*db.DB, error)) {
+	t.Helper()
+	project := t.TempDir()
+	dbConn, err := db.OpenProjectDB(project)
+	if err != nil {
+		t.Fatalf("open project db: %v", err)
+	}
+	t.Cleanup(func() { _ = dbConn.Close() })
+	return project, dbConn, func() (*db.DB, error) { return openProjectDB(project, false) }
+}
+
+func TestSignWebhook_RoundTrip(t *testing.T) {
+	body := []byte(`{"event":"request_pending"}`)
+	now := time.Unix(1700000000, 0)
+	sig := SignWebhook("s3cret", now.Unix(), body)
+	if !strings.HasPrefix(sig, "sha256=") {
+		t.Fatalf("unexpected signature %q", sig)
+	}
+	if err := VerifyWebhookSignature("s3cret", "1700000000", sig, body, 5*time.Minute, now); err != nil {
+		t.Fatalf("verify: %v", err)
+	}
+	if err := VerifyWebhookSignature("other", "1700000000", sig, body, 5*time.Minute, now); err == nil {
+		t.Fatalf("expected mismatch with wrong secret")
+	}
+	if err := VerifyWebhookSignature("s3cret", "1700000000", sig, body, 5*time.Minute, now.Add(time.Hour)); err == nil {
+		t.Fatalf("expected stale timestamp to be rejected")
+	}
+}
+
+func TestRenderWebhookBody_Formats(t *testing.T) {
+	exit := 1
+	p := WebhookPayload{
+		Event:     WebhookEventRequestExecuted,
+		RequestID: "req-1",
+		Command:   "rm -rf <build>",
+		Tier:      "critical",
+		Requestor: "BlueLake",
+		Reviews:   []WebhookReview{{Reviewer: "GreenHill", Decision: "approve", Comments: "ok"}},
+		Execution: &WebhookExecution{ExitCode: &exit},
+	}
+
+	cases := map[string]func(map[string]any) bool{
+		"json": func(m map[string]any) bool {
+			return m["event"] == "request_executed" && m["execution"] != nil && m["reviews"] != nil
+		},
+		"slack": func(m map[string]any) bool {
+			return strings.Contains(m["text"].(string), "exit 1") && m["blocks"] != nil
+		},
+		"discord": func(m map[string]any) bool { return m["content"] != nil && len(m["embeds"].([]any)) == 1 },
+		"teams":   func(m map[string]any) bool { return m["@type"] == "MessageCard" && m["themeColor"] == "E01E5A" },
+		"matrix": func(m map[string]any) bool {
+			return strings.Contains(m["html"].(string), "rm -rf &lt;build&gt;") && strings.Contains(m["text"].(string), "approve by GreenHill")
+		},
+	}
+	for format, check := range cases {
+		body, err := RenderWebhookBody(format, p)
+		if err != nil {
+			t.Fatalf("%s: %v", format, err)
+		}
+		var m map[string]any
+		if err := json.Unmarshal(body, &m); err != nil {
+			t.Fatalf("%s: invalid JSON: %v", format, err)
+		}
+		if !check(m) {
+			t.Errorf("%s: unexpected body %s", format, body)
+		}
+	}
+	if _, err := RenderWebhookBody("irc", p); err == nil {
+		t.Fatalf("expected error for unknown format")
+	}
+}
+
+func TestWebhookBackoff(t *testing.T) {
+	base := 5 * time.Second
+	for attempt, want := range map[int]time.Duration{1: 5 * time.Second, 2: 10 * time.Second, 4: 40 * time.Second, 20: time.Hour} {
+		if got := webhookBackoff(base, attempt); got != want {
+			t.Errorf("attempt %d: got %v want %v", attempt, got, want)
+		}
+	}
+}
+
+func TestWebhookDispatcher_SignsAndFilters(t *testing.T) {
+	_, dbConn, openDB := openWebhookTestDB(t)
+	all := startWebhookSink(t)
+	critical := startWebhookSink(t)
+	t.Setenv("SLB_TEST_WEBHOOK_SECRET", "from-env")
+
+	d := NewWebhookDispatcher(config.NotificationsConfig{
+		WebhookMaxAttempts: 3,
+		Webhooks: []config.WebhookEndpointConfig{
+			{Name: "all", URL: all.URL, Secret: "s3cret"},
+			{Name: "critical", URL: critical.URL, Format: "slack", Tiers: []string{"critical"},
+				Events: []string{"request_pending"}, SecretEnv: "SLB_TEST_WEBHOOK_SECRET"},
+		},
+	}, openDB, nil)
+
+	ctx := context.Background()
+	for _, p := range []WebhookPayload{
+		{Event: WebhookEventRequestPending, RequestID: "r1", Tier: "caution"},
+		{Event: WebhookEventRequestPending, RequestID: "r2", Tier: "critical"},
+		{Event: WebhookEventRequestApproved, RequestID: "r2", Tier: "critical"},
+	} {
+		if err := d.Dispatch(ctx, p); err != nil {
+			t.Fatalf("dispatch: %v", err)
+		}
+	}
+
+	if got := len(all.received()); got != 3 {
+		t.Fatalf("all endpoint got %d hits, want 3", got)
+	}
+	hits := critical.received()
+	if len(hits) != 1 {
+		t.Fatalf("critical endpoint got %d hits, want 1", len(hits))
+	}
+	h := hits[0]
+	if h.Header.Get(WebhookHeaderEvent) != "request_pending" || h.Header.Get(WebhookHeaderDelivery) == "" {
+		t.Fatalf("missing event headers: %v", h.Header)
+	}
+	if err := VerifyWebhookSignature("from-env", h.Header.Get(WebhookHeaderTimestamp), h.Header.Get(WebhookHeaderSignature), h.Body, time.Minute, time.Now()); err != nil {
+		t.Fatalf("signature: %v", err)
+	}
+
+	delivered, err := dbConn.ListWebhookDeliveries(db.WebhookDeliveryFilter{Status: db.WebhookDelivered})
+	if err != nil || len(delivered) != 4 {
+		t.Fatalf("expected 4 delivered rows, got %d (%v)", len(delivered), err)
+	}
+}
+
+func TestWebhookDispatcher_RetryThenDeadLetter(t *testing.T) {
+	_, dbConn, openDB := openWebhookTestDB(t)
+	sink := startWebhookSink(t)
+	sink.setStatus(http.StatusBadGateway)
+
+	d := NewWebhookDispatcher(config.NotificationsConfig{
+		WebhookMaxAttempts:   2,
+		WebhookRetryBaseSecs: 10,
+		Webhooks:             []config.WebhookEndpointConfig{{Name: "ops", URL: sink.URL}},
+	}, openDB, nil)
+	clock := time.Now()
+	d.now = func() time.Time { return clock }
+
+	ctx := context.Background()
+	if err := d.Dispatch(ctx, WebhookPayload{Event: WebhookEventRequestPending, RequestID: "r1", Tier: "critical"}); err != nil {
+		t.Fatalf("dispatch: %v", err)
+	}
+	list, _ := dbConn.ListWebhookDeliveries(db.WebhookDeliveryFilter{})
+	if len(list) != 1 || list[0].Status != db.WebhookRetrying || list[0].LastStatusCode == nil || *list[0].LastStatusCode != 502 {
+		t.Fatalf("expected retrying delivery, got %+v", list)
+	}
+
+	// Not yet due.
+	if err := d.ProcessDue(ctx); err != nil {
+		t.Fatalf("process due: %v", err)
+	}
+	if len(sink.received()) != 1 {
+		t.Fatalf("retried before backoff elapsed")
+	}
+
+	clock = clock.Add(11 * time.Second)
+	if err := d.ProcessDue(ctx); err != nil {
+		t.Fatalf("process due: %v", err)
+	}
+	got, _ := dbConn.GetWebhookDelivery(list[0].ID)
+	if got.Status != db.WebhookDead || got.Attempts != 2 {
+		t.Fatalf("expected dead delivery after 2 attempts, got %+v", got)
+	}
+
+	// Requeue from the dead-letter queue once the endpoint recovers.
+	sink.setStatus(http.StatusNoContent)
+	if err := dbConn.RequeueWebhookDelivery(got.ID); err != nil {
+		t.Fatalf("requeue: %v", err)
+	}
+	clock = time.Now().Add(time.Second)
+	if err := d.ProcessDue(ctx); err != nil {
+		t.Fatalf("process due: %v", err)
+	}
+	got, _ = dbConn.GetWebhookDelivery(got.ID)
+	if got.Status != db.WebhookDelivered || got.DeliveredAt == nil {
+		t.Fatalf("expected delivered after requeue, got %+v", got)
+	}
+}
+
+func TestNotificationManager_WebhookTransitions(t *testing.T) {
+	project, dbConn, openDB := openWebhookTestDB(t)
+	sink := startWebhookSink(t)
+
+	if err := dbConn.CreateSession(&db.Session{ID: "s1", AgentName: "AgentA", Program: "test", Model: "model", ProjectPath: project}); err != nil {
+		t.Fatalf("create session: %v", err)
+	}
+	req := &db.Request{
+		ProjectPath:        project,
+		Command:            db.CommandSpec{Raw: "git push --force", Cwd: project},
+		RiskTier:           db.RiskTierDangerous,
+		RequestorSessionID: "s1",
+		RequestorAgent:     "AgentA",
+		RequestorModel:     "model",
+		Justification:      db.Justification{Reason: "rewrite history"},
+		MinApprovals:       1,
+	}
+	if err := dbConn.CreateRequest(req); err != nil {
+		t.Fatalf("create request: %v", err)
+	}
+
+	cfg := config.NotificationsConfig{
+		WebhookMaxAttempts: 1,
+		Webhooks:           []config.WebhookEndpointConfig{{Name: "ops", URL: sink.URL}},
+	}
+	manager := NewNotificationManager(project, cfg, nil, DesktopNotifierFunc(func(string, string) error { return nil })).
+		WithWebhookDispatcher(NewWebhookDispatcher(cfg, openDB, nil))
+
+	events := func() []string {
+		var out []string
+		for _, h := range sink.received() {
+			out = append(out, h.Header.Get(WebhookHeaderEvent))
+		}
+		return out
+	}
+
+	ctx := context.Background()
+	if err := manager.Check(ctx); err != nil {
+		t.Fatalf("check: %v", err)
+	}
+	if err := dbConn.UpdateRequestStatus(req.ID, db.StatusRejected); err != nil {
+		t.Fatalf("update status: %v", err)
+	}
+	for i := 0; i < 2; i++ {
+		if err := manager.Check(ctx); err != nil {
+			t.Fatalf("check: %v", err)
+		}
+	}
+
+	got := events()
+	if strings.Join(got, ",") != "request_pending,request_rejected" {
+		t.Fatalf("unexpected events %v", got)
+	}
+	var p WebhookPayload
+	if err := json.Unmarshal(sink.received()[1].Body, &p); err != nil {
+		t.Fatalf("decode payload: %v", err)
+	}
+	if p.Status != "rejected" || p.Justification == nil || p.Justification.Reason != "rewrite history" {
+		t.Fatalf("unexpected payload %+v", p)
+	}
+}
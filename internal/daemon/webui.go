@@ -0,0 +1,30 @@
+package daemon
+
+import (
+	"embed"
+	"io/fs"
+	"net/http"
+)
+
+// webAssets is the browser review dashboard served by the HTTP gateway at /.
+// It is a static single page that talks to the /v1 API with the operator's
+// session key, so it needs no server-side state of its own.
+//
+//go:embed web
+var webAssets embed.FS
+
+// webHandler serves the embedded dashboard. Assets are public; every data and
+// review call the page makes goes through the authenticated API.
+func webHandler() http.Handler {
+	sub, err := fs.Sub(webAssets, "web")
+	if err != nil {
+		panic(err) // embedded path is fixed at build time
+	}
+	files := http.FileServer(http.FS(sub))
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Content-Type-Options", "nosniff")
+		w.Header().Set("X-Frame-Options", "DENY")
+		w.Header().Set("Content-Security-Policy", "default-src 'self'; style-src 'self'; script-src 'self'; connect-src 'self'")
+		files.ServeHTTP(w, r)
+	})
+}
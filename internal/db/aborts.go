@@ -0,0 +1,63 @@
+package db
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrAbortNotFound is returned when no abort was requested for a request.
+var ErrAbortNotFound = errors.New("execution abort not found")
+
+// ErrAbortExists is returned when an abort was already requested.
+var ErrAbortExists = errors.New("execution abort already requested")
+
+// ExecutionAbort records an approver asking for an executing command to be
+// stopped. The executing process polls for it and kills the command.
+type ExecutionAbort struct {
+	RequestID string    `json:"request_id"`
+	SessionID string    `json:"session_id"`
+	Agent     string    `json:"agent"`
+	Reason    string    `json:"reason,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// CreateExecutionAbort records an abort for a request. Only the first abort
+// is kept; later ones return ErrAbortExists.
+func (db *DB) CreateExecutionAbort(a *ExecutionAbort) error {
+	if a.CreatedAt.IsZero() {
+		a.CreatedAt = time.Now().UTC()
+	}
+	_, err := db.Exec(`
+		INSERT INTO execution_aborts (request_id, session_id, agent, reason, created_at)
+		VALUES (?, ?, ?, ?, ?)
+	`, a.RequestID, a.SessionID, a.Agent, nullString(a.Reason), a.CreatedAt.Format(time.RFC3339))
+	if err != nil {
+		if isUniqueConstraintError(err) {
+			return ErrAbortExists
+		}
+		return fmt.Errorf("creating execution abort: %w", err)
+	}
+	return nil
+}
+
+// GetExecutionAbort returns the abort requested for a request.
+func (db *DB) GetExecutionAbort(requestID string) (*ExecutionAbort, error) {
+	var a ExecutionAbort
+	var reason sql.NullString
+	var createdAt string
+	err := db.QueryRow(`
+		SELECT request_id, session_id, agent, reason, created_at
+		FROM execution_aborts WHERE request_id = ?
+	`, requestID).Scan(&a.RequestID, &a.SessionID, &a.Agent, &reason, &createdAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrAbortNotFound
+		}
+		return nil, fmt.Errorf("getting execution abort: %w", err)
+	}
+	a.Reason = reason.String
+	a.CreatedAt, _ = time.Parse(time.RFC3339, createdAt)
+	return &a, nil
+}
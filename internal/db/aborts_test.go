@@ -0,0 +1,31 @@
+package db
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestExecutionAbort(t *testing.T) {
+	db := setupTestDB(t)
+	sess, req := createTestRequest(t, db)
+
+	if _, err := db.GetExecutionAbort(req.ID); !errors.Is(err, ErrAbortNotFound) {
+		t.Fatalf("GetExecutionAbort before abort: got %v, want ErrAbortNotFound", err)
+	}
+
+	abort := &ExecutionAbort{RequestID: req.ID, SessionID: sess.ID, Agent: sess.AgentName, Reason: "deleting the wrong tree"}
+	if err := db.CreateExecutionAbort(abort); err != nil {
+		t.Fatalf("CreateExecutionAbort: %v", err)
+	}
+	if err := db.CreateExecutionAbort(&ExecutionAbort{RequestID: req.ID, SessionID: sess.ID, Agent: sess.AgentName}); !errors.Is(err, ErrAbortExists) {
+		t.Errorf("second abort: got %v, want ErrAbortExists", err)
+	}
+
+	got, err := db.GetExecutionAbort(req.ID)
+	if err != nil {
+		t.Fatalf("GetExecutionAbort: %v", err)
+	}
+	if got.Agent != sess.AgentName || got.Reason != abort.Reason || got.CreatedAt.IsZero() {
+		t.Errorf("GetExecutionAbort = %+v", got)
+	}
+}
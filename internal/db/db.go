@@ -47,7 +47,7 @@ func Open(path string) (*DB, error) {
 // OpenWithOptions opens a database connection with the given options.
 func OpenWithOptions(path string, opts OpenOptions) (*DB, error) {
 	// Ensure parent directory exists if creating
-	if opts.CreateIfNotExists {
+	if opts.CreateIfNotExists && path != ":memory:" {
 		dir := filepath.Dir(path)
 		if err := os.MkdirAll(dir, 0700); err != nil {
 			return nil, fmt.Errorf("creating database directory: %w", err)
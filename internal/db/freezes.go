@@ -0,0 +1,177 @@
+package db
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrFreezeNotFound is returned when a project has no active freeze.
+var ErrFreezeNotFound = errors.New("no active freeze")
+
+// ErrFreezeActive is returned when starting a freeze while one is active.
+var ErrFreezeActive = errors.New("a freeze is already active")
+
+// Freeze is a change freeze started by a human for a project: requests at or
+// above Tier are neither created nor executed until it ends or Until passes.
+type Freeze struct {
+	ID          int64  `json:"id"`
+	ProjectPath string `json:"project_path"`
+	Reason      string `json:"reason"`
+	// Tier is the lowest frozen tier; higher tiers are frozen too.
+	Tier      RiskTier `json:"tier"`
+	StartedBy string   `json:"started_by"`
+	// StartedBySession is the session that started the freeze.
+	StartedBySession string     `json:"started_by_session,omitempty"`
+	StartedAt        time.Time  `json:"started_at"`
+	Until            *time.Time `json:"until,omitempty"`
+	EndedBy          string     `json:"ended_by,omitempty"`
+	EndedAt          *time.Time `json:"ended_at,omitempty"`
+}
+
+// Active reports whether the freeze is in effect at t.
+func (f *Freeze) Active(t time.Time) bool {
+	return f.EndedAt == nil && (f.Until == nil || t.Before(*f.Until))
+}
+
+// Covers reports whether the freeze applies to requests of tier. Safe
+// requests are never frozen.
+func (f *Freeze) Covers(tier RiskTier) bool {
+	rank := map[RiskTier]int{RiskTierCaution: 1, RiskTierDangerous: 2, RiskTierCritical: 3}
+	lowest := rank[f.Tier]
+	if lowest == 0 {
+		lowest = 1
+	}
+	return rank[tier] >= lowest
+}
+
+// CreateFreeze starts a freeze for f.ProjectPath. It returns ErrFreezeActive
+// when the project already has an active freeze.
+func (db *DB) CreateFreeze(f *Freeze) error {
+	if f.StartedAt.IsZero() {
+		f.StartedAt = time.Now().UTC()
+	}
+	if f.Tier == "" {
+		f.Tier = RiskTierCaution
+	}
+	return db.Transaction(func(tx *sql.Tx) error {
+		active, err := activeFreeze(tx, f.ProjectPath, f.StartedAt)
+		if err != nil {
+			return err
+		}
+		if active != nil {
+			return ErrFreezeActive
+		}
+
+		result, err := tx.Exec(`
+			INSERT INTO freezes (project_path, reason, tier, started_by, started_by_session, started_at, until)
+			VALUES (?, ?, ?, ?, ?, ?, ?)
+		`, f.ProjectPath, f.Reason, string(f.Tier), f.StartedBy, nullString(f.StartedBySession),
+			f.StartedAt.Format(time.RFC3339), formatTimePtr(f.Until))
+		if err != nil {
+			return fmt.Errorf("creating freeze: %w", err)
+		}
+		if f.ID, err = result.LastInsertId(); err != nil {
+			return fmt.Errorf("getting freeze id: %w", err)
+		}
+		return nil
+	})
+}
+
+// EndFreeze ends the project's active freeze and returns it. It returns
+// ErrFreezeNotFound when there is none.
+func (db *DB) EndFreeze(projectPath, endedBy string) (*Freeze, error) {
+	now := time.Now().UTC()
+	f, err := db.GetActiveFreeze(projectPath, now)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := db.Exec(`UPDATE freezes SET ended_by = ?, ended_at = ? WHERE id = ?`,
+		endedBy, now.Format(time.RFC3339), f.ID); err != nil {
+		return nil, fmt.Errorf("ending freeze: %w", err)
+	}
+	f.EndedBy = endedBy
+	f.EndedAt = &now
+	return f, nil
+}
+
+// GetActiveFreeze returns the project's freeze in effect at t, or
+// ErrFreezeNotFound.
+func (db *DB) GetActiveFreeze(projectPath string, t time.Time) (*Freeze, error) {
+	f, err := activeFreeze(db, projectPath, t)
+	if err != nil {
+		return nil, err
+	}
+	if f == nil {
+		return nil, ErrFreezeNotFound
+	}
+	return f, nil
+}
+
+// ListFreezes returns the project's freezes, most recent first.
+func (db *DB) ListFreezes(projectPath string, limit int) ([]*Freeze, error) {
+	if limit <= 0 {
+		limit = 50
+	}
+	rows, err := db.Query(`
+		SELECT id, project_path, reason, tier, started_by, started_by_session, started_at, until, ended_by, ended_at
+		FROM freezes WHERE project_path = ?
+		ORDER BY started_at DESC, id DESC LIMIT ?
+	`, projectPath, limit)
+	if err != nil {
+		return nil, fmt.Errorf("listing freezes: %w", err)
+	}
+	defer rows.Close()
+	return scanFreezes(rows)
+}
+
+type queryer interface {
+	Query(query string, args ...any) (*sql.Rows, error)
+}
+
+// activeFreeze returns the project's freeze in effect at t, or nil.
+func activeFreeze(q queryer, projectPath string, t time.Time) (*Freeze, error) {
+	rows, err := q.Query(`
+		SELECT id, project_path, reason, tier, started_by, started_by_session, started_at, until, ended_by, ended_at
+		FROM freezes WHERE project_path = ? AND ended_at IS NULL
+		ORDER BY started_at DESC, id DESC
+	`, projectPath)
+	if err != nil {
+		return nil, fmt.Errorf("querying freezes: %w", err)
+	}
+	defer rows.Close()
+	freezes, err := scanFreezes(rows)
+	if err != nil {
+		return nil, err
+	}
+	for _, f := range freezes {
+		if f.Active(t) {
+			return f, nil
+		}
+	}
+	return nil, nil
+}
+
+func scanFreezes(rows *sql.Rows) ([]*Freeze, error) {
+	var freezes []*Freeze
+	for rows.Next() {
+		var f Freeze
+		var tier, startedAt string
+		var startedBySession, until, endedBy, endedAt sql.NullString
+		if err := rows.Scan(&f.ID, &f.ProjectPath, &f.Reason, &tier, &f.StartedBy, &startedBySession, &startedAt, &until, &endedBy, &endedAt); err != nil {
+			return nil, fmt.Errorf("scanning freeze: %w", err)
+		}
+		f.Tier = RiskTier(tier)
+		f.StartedBySession = startedBySession.String
+		f.StartedAt, _ = time.Parse(time.RFC3339, startedAt)
+		f.Until = parseTimePtr(until)
+		f.EndedBy = endedBy.String
+		f.EndedAt = parseTimePtr(endedAt)
+		freezes = append(freezes, &f)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterating freezes: %w", err)
+	}
+	return freezes, nil
+}
@@ -0,0 +1,61 @@
+package db
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestFreezes(t *testing.T) {
+	db := setupTestDB(t)
+	const project = "/test/project"
+
+	if _, err := db.GetActiveFreeze(project, time.Now()); !errors.Is(err, ErrFreezeNotFound) {
+		t.Fatalf("GetActiveFreeze before a freeze: got %v, want ErrFreezeNotFound", err)
+	}
+
+	until := time.Now().UTC().Add(time.Hour).Truncate(time.Second)
+	f := &Freeze{ProjectPath: project, Reason: "release", Tier: RiskTierDangerous, StartedBy: "ops", Until: &until}
+	if err := db.CreateFreeze(f); err != nil {
+		t.Fatalf("CreateFreeze: %v", err)
+	}
+	if err := db.CreateFreeze(&Freeze{ProjectPath: project, Reason: "again", StartedBy: "ops"}); !errors.Is(err, ErrFreezeActive) {
+		t.Errorf("second freeze: got %v, want ErrFreezeActive", err)
+	}
+	if err := db.CreateFreeze(&Freeze{ProjectPath: "/other", Reason: "other", StartedBy: "ops"}); err != nil {
+		t.Errorf("freeze of another project: %v", err)
+	}
+
+	got, err := db.GetActiveFreeze(project, time.Now())
+	if err != nil {
+		t.Fatalf("GetActiveFreeze: %v", err)
+	}
+	if got.ID != f.ID || got.Reason != "release" || got.Until == nil || !got.Until.Equal(until) {
+		t.Errorf("GetActiveFreeze = %+v", got)
+	}
+	if !got.Covers(RiskTierCritical) || !got.Covers(RiskTierDangerous) || got.Covers(RiskTierCaution) || got.Covers(RiskTier("safe")) {
+		t.Error("a dangerous freeze should cover dangerous and critical requests only")
+	}
+	if _, err := db.GetActiveFreeze(project, until); !errors.Is(err, ErrFreezeNotFound) {
+		t.Errorf("GetActiveFreeze at until: got %v, want ErrFreezeNotFound", err)
+	}
+
+	ended, err := db.EndFreeze(project, "lead")
+	if err != nil {
+		t.Fatalf("EndFreeze: %v", err)
+	}
+	if ended.EndedBy != "lead" || ended.EndedAt == nil {
+		t.Errorf("EndFreeze = %+v", ended)
+	}
+	if _, err := db.EndFreeze(project, "lead"); !errors.Is(err, ErrFreezeNotFound) {
+		t.Errorf("second EndFreeze: got %v, want ErrFreezeNotFound", err)
+	}
+
+	freezes, err := db.ListFreezes(project, 0)
+	if err != nil {
+		t.Fatalf("ListFreezes: %v", err)
+	}
+	if len(freezes) != 1 || freezes[0].EndedBy != "lead" {
+		t.Errorf("ListFreezes = %+v", freezes)
+	}
+}
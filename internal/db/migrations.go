@@ -186,10 +186,195 @@ ALTER TABLE execution_outcomes ADD COLUMN caused_problems INTEGER NOT NULL DEFAU
 ALTER TABLE execution_outcomes ADD COLUMN problem_description TEXT;
 ALTER TABLE execution_outcomes ADD COLUMN human_rating INTEGER;
 ALTER TABLE execution_outcomes ADD COLUMN human_notes TEXT;
+`,
+	},
+	{
+		Version: 4,
+		Name:    "webhook_deliveries",
+		Up: `
+-- Webhook delivery log; status 'dead' rows form the dead-letter queue.
+CREATE TABLE IF NOT EXISTS webhook_deliveries (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  endpoint TEXT NOT NULL,
+  url TEXT NOT NULL,
+  event TEXT NOT NULL,
+  request_id TEXT,
+  body TEXT NOT NULL,
+  status TEXT NOT NULL,
+  attempts INTEGER NOT NULL DEFAULT 0,
+  last_status_code INTEGER,
+  last_error TEXT,
+  next_attempt_at TEXT,
+  created_at TEXT NOT NULL,
+  delivered_at TEXT
+);
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_due ON webhook_deliveries(status, next_attempt_at);
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_created ON webhook_deliveries(created_at);
+`,
+	},
+	{
+		Version: 5,
+		Name:    "session_attestation_and_keys",
+		Up: `
+-- Process context recorded when a session is started or resumed.
+ALTER TABLE sessions ADD COLUMN attestation_json TEXT;
+` + sessionKeysDDL,
+	},
+	{
+		Version: 6,
+		Name:    "request_environment",
+		Up: `
+-- Environment fingerprint captured when a request is created.
+ALTER TABLE requests ADD COLUMN environment_json TEXT;
+`,
+	},
+	{
+		Version: 7,
+		Name:    "sandboxed_execution",
+		Up: `
+-- Sandbox requested for execution, and reviewers requiring one.
+ALTER TABLE requests ADD COLUMN sandbox_json TEXT;
+ALTER TABLE reviews ADD COLUMN require_sandbox INTEGER NOT NULL DEFAULT 0;
+`,
+	},
+	{
+		Version: 8,
+		Name:    "execution_aborts",
+		Up: `
+-- Aborts requested by approvers for commands that are executing.
+CREATE TABLE IF NOT EXISTS execution_aborts (
+  request_id TEXT PRIMARY KEY REFERENCES requests(id) ON DELETE CASCADE,
+  session_id TEXT NOT NULL,
+  agent TEXT NOT NULL,
+  reason TEXT,
+  created_at TEXT NOT NULL
+);
+`,
+	},
+	{
+		Version: 9,
+		Name:    "redactions",
+		Up: `
+-- Secrets scrubbed from each artifact of a request (execution log,
+-- transcript, dry run, attachments).
+CREATE TABLE IF NOT EXISTS redactions (
+  request_id TEXT NOT NULL REFERENCES requests(id) ON DELETE CASCADE,
+  artifact TEXT NOT NULL,
+  count INTEGER NOT NULL,
+  created_at TEXT NOT NULL,
+  PRIMARY KEY (request_id, artifact)
+);
+`,
+	},
+	{
+		Version: 10,
+		Name:    "execution_plans",
+		Up: `
+-- Multi-step plans, and the result of each step once executed.
+ALTER TABLE requests ADD COLUMN plan_json TEXT;
+` + planStepResultsDDL,
+	},
+	{
+		Version: 11,
+		Name:    "execution_windows",
+		Up: `
+-- When an approved request may execute.
+ALTER TABLE requests ADD COLUMN window_json TEXT;
+`,
+	},
+	{
+		Version: 12,
+		Name:    "freezes",
+		Up: `
+-- Change freezes started with "slb freeze start".
+CREATE TABLE IF NOT EXISTS freezes (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  project_path TEXT NOT NULL,
+  reason TEXT NOT NULL,
+  tier TEXT NOT NULL,
+  started_by TEXT NOT NULL,
+  started_at TEXT NOT NULL,
+  until TEXT,
+  ended_by TEXT,
+  ended_at TEXT
+);
+CREATE INDEX IF NOT EXISTS idx_freezes_project ON freezes(project_path, ended_at);
+`,
+	},
+	{
+		Version: 13,
+		Name:    "policy_decisions",
+		Up: `
+-- Policy rules that fired for a request and the reviewers and executors they allow.
+ALTER TABLE requests ADD COLUMN policy_json TEXT;
+`,
+	},
+	{
+		Version: 14,
+		Name:    "review_signing_keys",
+		Up: `
+-- The retired session key a review was signed with; NULL while it is the current key.
+ALTER TABLE reviews ADD COLUMN signing_key_id INTEGER REFERENCES session_keys(id);
+` + reviewSigningKeysBackfill,
+	},
+	{
+		Version: 15,
+		Name:    "freeze_sessions",
+		Up: `
+-- The session that started a freeze; only it (or a freeze operator) may end it.
+ALTER TABLE freezes ADD COLUMN started_by_session TEXT;
+`,
+	},
+	{
+		Version: 16,
+		Name:    "request_auto_answers",
+		Up: `
+-- PTY auto-answers recorded at request creation, shown to reviewers and used at execution.
+ALTER TABLE requests ADD COLUMN auto_answers_json TEXT;
 `,
 	},
 }
 
+// reviewSigningKeysBackfill links reviews signed before an earlier rotation
+// to the first key retired at or after their signature timestamp.
+const reviewSigningKeysBackfill = `
+UPDATE reviews SET signing_key_id = (
+  SELECT k.id FROM session_keys k
+  WHERE k.session_id = reviews.reviewer_session_id AND k.retired_at >= reviews.signature_timestamp
+  ORDER BY k.id ASC LIMIT 1
+) WHERE signing_key_id IS NULL;
+`
+
+// planStepResultsDDL creates the table of per-step plan execution results.
+const planStepResultsDDL = `
+CREATE TABLE IF NOT EXISTS plan_step_results (
+  request_id TEXT NOT NULL REFERENCES requests(id) ON DELETE CASCADE,
+  step INTEGER NOT NULL,
+  command TEXT NOT NULL,
+  status TEXT NOT NULL,
+  exit_code INTEGER,
+  duration_ms INTEGER,
+  log_path TEXT,
+  rollback_path TEXT,
+  started_at TEXT,
+  finished_at TEXT,
+  PRIMARY KEY (request_id, step)
+);
+`
+
+// sessionKeysDDL creates the table of retired session keys, kept so that
+// reviews signed before a key rotation still verify.
+const sessionKeysDDL = `
+CREATE TABLE IF NOT EXISTS session_keys (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  session_id TEXT NOT NULL REFERENCES sessions(id) ON DELETE CASCADE,
+  session_key TEXT NOT NULL,
+  created_at TEXT NOT NULL,
+  retired_at TEXT NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_session_keys_session ON session_keys(session_id, retired_at);
+`
+
 // ApplyMigrations applies any pending migrations in order.
 func (db *DB) ApplyMigrations(ctx context.Context) error {
 	db.mu.Lock()
@@ -238,6 +423,67 @@ func (db *DB) ApplyMigrations(ctx context.Context) error {
 					return fmt.Errorf("migration %d (%s) failed: %w", m.Version, m.Name, err)
 				}
 			}
+		case 5:
+			if err := addColumnIfMissing(ctx, tx, "sessions", "attestation_json", "TEXT"); err != nil {
+				tx.Rollback()
+				return fmt.Errorf("migration %d (%s) failed: %w", m.Version, m.Name, err)
+			}
+			if _, err := tx.ExecContext(ctx, sessionKeysDDL); err != nil {
+				tx.Rollback()
+				return fmt.Errorf("migration %d (%s) failed: %w", m.Version, m.Name, err)
+			}
+		case 6:
+			if err := addColumnIfMissing(ctx, tx, "requests", "environment_json", "TEXT"); err != nil {
+				tx.Rollback()
+				return fmt.Errorf("migration %d (%s) failed: %w", m.Version, m.Name, err)
+			}
+		case 7:
+			if err := addColumnIfMissing(ctx, tx, "requests", "sandbox_json", "TEXT"); err != nil {
+				tx.Rollback()
+				return fmt.Errorf("migration %d (%s) failed: %w", m.Version, m.Name, err)
+			}
+			if err := addColumnIfMissing(ctx, tx, "reviews", "require_sandbox", "INTEGER NOT NULL DEFAULT 0"); err != nil {
+				tx.Rollback()
+				return fmt.Errorf("migration %d (%s) failed: %w", m.Version, m.Name, err)
+			}
+		case 10:
+			if err := addColumnIfMissing(ctx, tx, "requests", "plan_json", "TEXT"); err != nil {
+				tx.Rollback()
+				return fmt.Errorf("migration %d (%s) failed: %w", m.Version, m.Name, err)
+			}
+			if _, err := tx.ExecContext(ctx, planStepResultsDDL); err != nil {
+				tx.Rollback()
+				return fmt.Errorf("migration %d (%s) failed: %w", m.Version, m.Name, err)
+			}
+		case 11:
+			if err := addColumnIfMissing(ctx, tx, "requests", "window_json", "TEXT"); err != nil {
+				tx.Rollback()
+				return fmt.Errorf("migration %d (%s) failed: %w", m.Version, m.Name, err)
+			}
+		case 13:
+			if err := addColumnIfMissing(ctx, tx, "requests", "policy_json", "TEXT"); err != nil {
+				tx.Rollback()
+				return fmt.Errorf("migration %d (%s) failed: %w", m.Version, m.Name, err)
+			}
+		case 14:
+			if err := addColumnIfMissing(ctx, tx, "reviews", "signing_key_id", "INTEGER REFERENCES session_keys(id)"); err != nil {
+				tx.Rollback()
+				return fmt.Errorf("migration %d (%s) failed: %w", m.Version, m.Name, err)
+			}
+			if _, err := tx.ExecContext(ctx, reviewSigningKeysBackfill); err != nil {
+				tx.Rollback()
+				return fmt.Errorf("migration %d (%s) failed: %w", m.Version, m.Name, err)
+			}
+		case 15:
+			if err := addColumnIfMissing(ctx, tx, "freezes", "started_by_session", "TEXT"); err != nil {
+				tx.Rollback()
+				return fmt.Errorf("migration %d (%s) failed: %w", m.Version, m.Name, err)
+			}
+		case 16:
+			if err := addColumnIfMissing(ctx, tx, "requests", "auto_answers_json", "TEXT"); err != nil {
+				tx.Rollback()
+				return fmt.Errorf("migration %d (%s) failed: %w", m.Version, m.Name, err)
+			}
 		default:
 			if _, err := tx.ExecContext(ctx, m.Up); err != nil {
 				tx.Rollback()
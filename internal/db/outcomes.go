@@ -237,12 +237,24 @@ type RequestStats struct {
 
 // GetRequestStatsByAgent returns request statistics for a specific agent.
 func (db *DB) GetRequestStatsByAgent(agentName string) (*RequestStats, error) {
+	return db.GetRequestStatsByAgentMatching(agentName, RequestQuery{}, QueryOptions{})
+}
+
+// GetRequestStatsByAgentMatching returns request statistics for an agent,
+// limited to the requests matching a history query.
+func (db *DB) GetRequestStatsByAgentMatching(agentName string, q RequestQuery, opts QueryOptions) (*RequestStats, error) {
 	stats := &RequestStats{}
 
+	where, args, err := q.where(opts)
+	if err != nil {
+		return nil, err
+	}
+	where = "r.requestor_agent = ? AND " + where
+	args = append([]any{agentName}, args...)
+
 	// Total requests by agent
 	if err := db.QueryRow(`
-		SELECT COUNT(*) FROM requests WHERE requestor_agent = ?
-	`, agentName).Scan(&stats.TotalRequests); err != nil {
+		SELECT COUNT(*) FROM requests r WHERE `+where, args...).Scan(&stats.TotalRequests); err != nil {
 		return nil, fmt.Errorf("counting requests: %w", err)
 	}
 
@@ -253,11 +265,10 @@ func (db *DB) GetRequestStatsByAgent(agentName string) (*RequestStats, error) {
 	// Approved/Rejected/Executed counts
 	if err := db.QueryRow(`
 		SELECT
-			SUM(CASE WHEN status IN ('approved', 'executing', 'executed', 'execution_failed') THEN 1 ELSE 0 END),
-			SUM(CASE WHEN status = 'rejected' THEN 1 ELSE 0 END),
-			SUM(CASE WHEN status = 'executed' THEN 1 ELSE 0 END)
-		FROM requests WHERE requestor_agent = ?
-	`, agentName).Scan(&stats.ApprovedCount, &stats.RejectedCount, &stats.ExecutedCount); err != nil {
+			SUM(CASE WHEN r.status IN ('approved', 'executing', 'executed', 'execution_failed') THEN 1 ELSE 0 END),
+			SUM(CASE WHEN r.status = 'rejected' THEN 1 ELSE 0 END),
+			SUM(CASE WHEN r.status = 'executed' THEN 1 ELSE 0 END)
+		FROM requests r WHERE `+where, args...).Scan(&stats.ApprovedCount, &stats.RejectedCount, &stats.ExecutedCount); err != nil {
 		return nil, fmt.Errorf("counting by status: %w", err)
 	}
 
@@ -267,8 +278,7 @@ func (db *DB) GetRequestStatsByAgent(agentName string) (*RequestStats, error) {
 		if err := db.QueryRow(`
 			SELECT COUNT(*) FROM execution_outcomes o
 			JOIN requests r ON o.request_id = r.id
-			WHERE r.requestor_agent = ? AND o.caused_problems = 1
-		`, agentName).Scan(&problematic); err != nil {
+			WHERE o.caused_problems = 1 AND `+where, args...).Scan(&problematic); err != nil {
 			return nil, fmt.Errorf("counting problematic: %w", err)
 		}
 		stats.ProblematicPct = float64(problematic) / float64(stats.ExecutedCount) * 100
@@ -289,19 +299,30 @@ type TimeToApprovalStats struct {
 // GetTimeToApprovalStats returns statistics about how long it takes for requests to get approved.
 // Uses the first approval review's timestamp as the "approved at" time.
 func (db *DB) GetTimeToApprovalStats() (*TimeToApprovalStats, error) {
+	return db.GetTimeToApprovalStatsMatching(RequestQuery{}, QueryOptions{})
+}
+
+// GetTimeToApprovalStatsMatching is GetTimeToApprovalStats limited to the
+// requests matching a history query.
+func (db *DB) GetTimeToApprovalStatsMatching(q RequestQuery, opts QueryOptions) (*TimeToApprovalStats, error) {
 	stats := &TimeToApprovalStats{}
 
+	where, args, err := q.where(opts)
+	if err != nil {
+		return nil, err
+	}
+
 	// Get all approval times by looking at when the first approval review was created
 	rows, err := db.Query(`
 		SELECT
 			(julianday(MIN(rv.created_at)) - julianday(r.created_at)) * 24 * 60 as minutes
 		FROM requests r
 		JOIN reviews rv ON rv.request_id = r.id AND rv.decision = 'approve'
-		WHERE r.status IN ('approved', 'executing', 'executed', 'execution_failed')
+		WHERE r.status IN ('approved', 'executing', 'executed', 'execution_failed') AND `+where+`
 		GROUP BY r.id
 		HAVING minutes IS NOT NULL
 		ORDER BY minutes
-	`)
+	`, args...)
 	if err != nil {
 		return nil, fmt.Errorf("querying approval times: %w", err)
 	}
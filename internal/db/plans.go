@@ -0,0 +1,162 @@
+package db
+
+import (
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// PlanStepStatus is the outcome of one step of an executed plan.
+type PlanStepStatus string
+
+const (
+	// PlanStepSucceeded means the step exited with code 0.
+	PlanStepSucceeded PlanStepStatus = "succeeded"
+	// PlanStepFailed means the step exited non-zero or could not start.
+	PlanStepFailed PlanStepStatus = "failed"
+	// PlanStepTimedOut means the step ran into the execution timeout.
+	PlanStepTimedOut PlanStepStatus = "timed_out"
+	// PlanStepAborted means an approver aborted the plan during the step.
+	PlanStepAborted PlanStepStatus = "aborted"
+	// PlanStepSkipped means the plan stopped before reaching the step.
+	PlanStepSkipped PlanStepStatus = "skipped"
+)
+
+// PlanStepResult records how one step of a plan executed.
+type PlanStepResult struct {
+	RequestID string `json:"request_id"`
+	// Step is the 1-based position of the step in the plan.
+	Step         int            `json:"step"`
+	Command      string         `json:"command"`
+	Status       PlanStepStatus `json:"status"`
+	ExitCode     *int           `json:"exit_code,omitempty"`
+	DurationMs   int64          `json:"duration_ms,omitempty"`
+	LogPath      string         `json:"log_path,omitempty"`
+	RollbackPath string         `json:"rollback_path,omitempty"`
+	StartedAt    *time.Time     `json:"started_at,omitempty"`
+	FinishedAt   *time.Time     `json:"finished_at,omitempty"`
+}
+
+// ComputePlanHash computes the hash binding an approval to a plan's steps.
+// Hash = sha256 of each step's command hash and continue_on_failure flag,
+// one step per line.
+func ComputePlanHash(steps []PlanStep) string {
+	h := sha256.New()
+	for _, s := range steps {
+		fmt.Fprintf(h, "%s %s\n", ComputeCommandHash(s.Command), strconv.FormatBool(s.ContinueOnFailure))
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// RecordPlanStepResult stores the result of a plan step, replacing any
+// earlier result for the same step.
+func (db *DB) RecordPlanStepResult(r *PlanStepResult) error {
+	var exitCode sql.NullInt64
+	if r.ExitCode != nil {
+		exitCode = sql.NullInt64{Int64: int64(*r.ExitCode), Valid: true}
+	}
+	_, err := db.Exec(`
+		INSERT INTO plan_step_results (
+			request_id, step, command, status, exit_code, duration_ms,
+			log_path, rollback_path, started_at, finished_at
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(request_id, step) DO UPDATE SET
+			command = excluded.command,
+			status = excluded.status,
+			exit_code = excluded.exit_code,
+			duration_ms = excluded.duration_ms,
+			log_path = excluded.log_path,
+			rollback_path = excluded.rollback_path,
+			started_at = excluded.started_at,
+			finished_at = excluded.finished_at
+	`, r.RequestID, r.Step, r.Command, string(r.Status), exitCode, r.DurationMs,
+		nullString(r.LogPath), nullString(r.RollbackPath), formatTimePtr(r.StartedAt), formatTimePtr(r.FinishedAt))
+	if err != nil {
+		return fmt.Errorf("recording plan step result: %w", err)
+	}
+	return nil
+}
+
+// ListPlanStepResults returns the recorded step results of a plan request,
+// in step order.
+func (db *DB) ListPlanStepResults(requestID string) ([]PlanStepResult, error) {
+	rows, err := db.Query(`
+		SELECT request_id, step, command, status, exit_code, duration_ms,
+			log_path, rollback_path, started_at, finished_at
+		FROM plan_step_results WHERE request_id = ? ORDER BY step
+	`, requestID)
+	if err != nil {
+		return nil, fmt.Errorf("listing plan step results: %w", err)
+	}
+	defer rows.Close()
+
+	var results []PlanStepResult
+	for rows.Next() {
+		var (
+			r                     PlanStepResult
+			status                string
+			exitCode              sql.NullInt64
+			durationMs            sql.NullInt64
+			logPath, rollbackPath sql.NullString
+			startedAt, finishedAt sql.NullString
+		)
+		if err := rows.Scan(&r.RequestID, &r.Step, &r.Command, &status, &exitCode, &durationMs,
+			&logPath, &rollbackPath, &startedAt, &finishedAt); err != nil {
+			return nil, fmt.Errorf("scanning plan step result: %w", err)
+		}
+		r.Status = PlanStepStatus(status)
+		if exitCode.Valid {
+			code := int(exitCode.Int64)
+			r.ExitCode = &code
+		}
+		r.DurationMs = durationMs.Int64
+		r.LogPath = logPath.String
+		r.RollbackPath = rollbackPath.String
+		r.StartedAt = parseTimePtr(startedAt)
+		r.FinishedAt = parseTimePtr(finishedAt)
+		results = append(results, r)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterating plan step results: %w", err)
+	}
+	return results, nil
+}
+
+func encodePlan(p *Plan) (sql.NullString, error) {
+	if p == nil {
+		return sql.NullString{}, nil
+	}
+	data, err := json.Marshal(p)
+	if err != nil {
+		return sql.NullString{}, fmt.Errorf("encoding plan: %w", err)
+	}
+	return sql.NullString{String: string(data), Valid: true}, nil
+}
+
+func decodePlan(v sql.NullString) *Plan {
+	if !v.Valid || v.String == "" || v.String == "null" {
+		return nil
+	}
+	var p Plan
+	if err := json.Unmarshal([]byte(v.String), &p); err != nil {
+		// An unreadable plan is still a plan; its hash no longer matches
+		// so it cannot be executed.
+		return &Plan{}
+	}
+	return &p
+}
+
+func parseTimePtr(v sql.NullString) *time.Time {
+	if !v.Valid {
+		return nil
+	}
+	t, err := time.Parse(time.RFC3339, v.String)
+	if err != nil {
+		return nil
+	}
+	return &t
+}
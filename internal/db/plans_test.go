@@ -0,0 +1,114 @@
+package db
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRequestPlan_RoundTrip(t *testing.T) {
+	db := setupTestDB(t)
+	sess, _ := createTestRequest(t, db)
+
+	plan := &Plan{
+		Name: "drop-db",
+		Steps: []PlanStep{
+			{Command: CommandSpec{Raw: "kubectl scale deploy/db --replicas=0", Cwd: "/srv"}, RiskTier: RiskTierCaution},
+			{Command: CommandSpec{Raw: "kubectl delete pvc data", Cwd: "/srv/k8s"}, RiskTier: RiskTierCritical, ExpectedEffect: "data volume deleted"},
+		},
+	}
+	req := &Request{
+		ProjectPath:        sess.ProjectPath,
+		Command:            CommandSpec{Raw: "plan drop-db", Cwd: "/srv"},
+		RiskTier:           RiskTierCritical,
+		RequestorSessionID: sess.ID,
+		RequestorAgent:     sess.AgentName,
+		Justification:      Justification{Reason: "decommission"},
+		MinApprovals:       2,
+		Plan:               plan,
+	}
+	if err := db.CreateRequest(req); err != nil {
+		t.Fatalf("CreateRequest: %v", err)
+	}
+	if plan.Hash != ComputePlanHash(plan.Steps) {
+		t.Errorf("Plan.Hash = %q, want ComputePlanHash of the steps", plan.Hash)
+	}
+
+	got, err := db.GetRequest(req.ID)
+	if err != nil {
+		t.Fatalf("GetRequest: %v", err)
+	}
+	if got.Plan == nil || got.Plan.Name != "drop-db" || len(got.Plan.Steps) != 2 || got.Plan.Hash != plan.Hash {
+		t.Fatalf("Plan = %+v", got.Plan)
+	}
+	if s := got.Plan.Steps[1]; s.Command.Cwd != "/srv/k8s" || s.RiskTier != RiskTierCritical || s.ExpectedEffect != "data volume deleted" {
+		t.Errorf("step 2 = %+v", s)
+	}
+
+	listed, err := db.ListPendingRequests(sess.ProjectPath)
+	if err != nil {
+		t.Fatalf("ListPendingRequests: %v", err)
+	}
+	for _, r := range listed {
+		if r.ID == req.ID && r.Plan == nil {
+			t.Error("ListPendingRequests dropped the plan")
+		}
+	}
+}
+
+func TestComputePlanHash(t *testing.T) {
+	steps := []PlanStep{
+		{Command: CommandSpec{Raw: "helm uninstall db", Cwd: "/srv"}},
+		{Command: CommandSpec{Raw: "kubectl delete pvc data", Cwd: "/srv"}},
+	}
+	base := ComputePlanHash(steps)
+
+	moved := append([]PlanStep(nil), steps...)
+	moved[1].Command.Cwd = "/tmp"
+	reordered := []PlanStep{steps[1], steps[0]}
+	lenient := append([]PlanStep(nil), steps...)
+	lenient[0].ContinueOnFailure = true
+
+	for name, s := range map[string][]PlanStep{"cwd": moved, "order": reordered, "continue_on_failure": lenient, "fewer steps": steps[:1]} {
+		if ComputePlanHash(s) == base {
+			t.Errorf("changing %s did not change the hash", name)
+		}
+	}
+	if ComputePlanHash(steps) != base {
+		t.Error("hash is not deterministic")
+	}
+}
+
+func TestPlanStepResults(t *testing.T) {
+	db := setupTestDB(t)
+	_, req := createTestRequest(t, db)
+
+	started := time.Now().UTC().Truncate(time.Second)
+	code := 0
+	for _, r := range []*PlanStepResult{
+		{RequestID: req.ID, Step: 2, Command: "helm uninstall db", Status: PlanStepSkipped},
+		{RequestID: req.ID, Step: 1, Command: "kubectl delete pvc data", Status: PlanStepFailed},
+		{RequestID: req.ID, Step: 1, Command: "kubectl delete pvc data", Status: PlanStepSucceeded, ExitCode: &code,
+			DurationMs: 42, LogPath: "/logs/step1.log", RollbackPath: "/rollback/step1", StartedAt: &started, FinishedAt: &started},
+	} {
+		if err := db.RecordPlanStepResult(r); err != nil {
+			t.Fatalf("RecordPlanStepResult: %v", err)
+		}
+	}
+
+	got, err := db.ListPlanStepResults(req.ID)
+	if err != nil {
+		t.Fatalf("ListPlanStepResults: %v", err)
+	}
+	if len(got) != 2 || got[0].Step != 1 || got[1].Step != 2 {
+		t.Fatalf("ListPlanStepResults = %+v", got)
+	}
+	first := got[0]
+	if first.Status != PlanStepSucceeded || first.ExitCode == nil || *first.ExitCode != 0 || first.DurationMs != 42 ||
+		first.LogPath != "/logs/step1.log" || first.RollbackPath != "/rollback/step1" ||
+		first.StartedAt == nil || !first.StartedAt.Equal(started) {
+		t.Errorf("step 1 = %+v", first)
+	}
+	if got[1].Status != PlanStepSkipped || got[1].ExitCode != nil || got[1].StartedAt != nil {
+		t.Errorf("step 2 = %+v", got[1])
+	}
+}
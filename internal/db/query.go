@@ -0,0 +1,688 @@
+// Package db provides the request history query language.
+package db
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// DefaultQueryLimit is the page size used when a query gives no limit.
+const DefaultQueryLimit = 50
+
+// ErrInvalidCursor is returned when a pagination cursor cannot be decoded.
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// queryFields lists the fields a history query can filter on.
+var queryFields = map[string]bool{
+	"id": true, "tier": true, "agent": true, "model": true, "status": true,
+	"project": true, "since": true, "until": true, "cmd": true, "reason": true,
+	"exit": true, "problems": true, "reviewer": true,
+}
+
+// tierRank orders risk tiers for tier:>=dangerous style comparisons.
+var tierRank = map[RiskTier]int{
+	RiskTierCaution:   1,
+	RiskTierDangerous: 2,
+	RiskTierCritical:  3,
+}
+
+// QueryTerm is one field:value filter. Op is one of = != < <= > >=; Negate
+// is set for terms written with a leading "-".
+type QueryTerm struct {
+	Field  string `json:"field"`
+	Op     string `json:"op"`
+	Value  string `json:"value"`
+	Negate bool   `json:"negate,omitempty"`
+}
+
+// RequestQuery is a parsed history query: field filters plus free-text words,
+// which are matched with the full-text index. All parts must match.
+type RequestQuery struct {
+	Terms []QueryTerm `json:"terms,omitempty"`
+	Text  []string    `json:"text,omitempty"`
+}
+
+// Empty reports whether the query has no filters.
+func (q RequestQuery) Empty() bool {
+	return len(q.Terms) == 0 && len(q.Text) == 0
+}
+
+// HasField reports whether the query filters on field.
+func (q RequestQuery) HasField(field string) bool {
+	for _, t := range q.Terms {
+		if t.Field == field {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseRequestQuery parses a history query such as
+//
+//	tier:critical agent:BlueLake status:executed since:7d cmd:"kubectl delete" exit:!=0 problems:true
+//
+// Values may be quoted, comma-separated lists (tier:critical,dangerous) or
+// prefixed with a comparison operator (exit:!=0, tier:>=dangerous). A leading
+// "-" negates a term. Words without a field are full-text search terms.
+// "@name" expands to the saved query of that name.
+func ParseRequestQuery(input string, saved map[string]string) (RequestQuery, error) {
+	var q RequestQuery
+	if err := parseQueryInto(&q, input, saved, map[string]bool{}); err != nil {
+		return RequestQuery{}, err
+	}
+	return q, nil
+}
+
+func parseQueryInto(q *RequestQuery, input string, saved map[string]string, expanding map[string]bool) error {
+	tokens, err := tokenizeQuery(input)
+	if err != nil {
+		return err
+	}
+	for _, tok := range tokens {
+		if name, ok := strings.CutPrefix(tok, "@"); ok && name != "" {
+			name = strings.ToLower(name)
+			body, found := lookupSaved(saved, name)
+			if !found {
+				return fmt.Errorf("unknown saved query %q", name)
+			}
+			if expanding[name] {
+				return fmt.Errorf("saved query %q refers to itself", name)
+			}
+			expanding[name] = true
+			if err := parseQueryInto(q, body, saved, expanding); err != nil {
+				return fmt.Errorf("saved query %q: %w", name, err)
+			}
+			delete(expanding, name)
+			continue
+		}
+
+		if tok[0] == '"' {
+			q.Text = append(q.Text, unquote(tok))
+			continue
+		}
+		key, value, hasColon := strings.Cut(tok, ":")
+		negate := false
+		if rest, ok := strings.CutPrefix(key, "-"); ok && hasColon {
+			key, negate = rest, true
+		}
+		key = strings.ToLower(key)
+		if !hasColon || !isFieldName(key) {
+			q.Text = append(q.Text, unquote(tok))
+			continue
+		}
+		if !queryFields[key] {
+			return fmt.Errorf("unknown query field %q", key)
+		}
+		term, err := parseTerm(key, value, negate)
+		if err != nil {
+			return err
+		}
+		q.Terms = append(q.Terms, term)
+	}
+	return nil
+}
+
+func lookupSaved(saved map[string]string, name string) (string, bool) {
+	for k, v := range saved {
+		if strings.ToLower(k) == name {
+			return v, true
+		}
+	}
+	return "", false
+}
+
+func parseTerm(field, raw string, negate bool) (QueryTerm, error) {
+	op := "="
+	for _, candidate := range []string{">=", "<=", "!=", ">", "<", "="} {
+		if rest, ok := strings.CutPrefix(raw, candidate); ok {
+			op, raw = candidate, rest
+			break
+		}
+	}
+	term := QueryTerm{Field: field, Op: op, Value: unquote(raw), Negate: negate}
+	if field == "tier" || field == "status" {
+		term.Value = strings.ToLower(term.Value)
+	}
+	if term.Value == "" {
+		return term, fmt.Errorf("%s: missing value", field)
+	}
+
+	switch field {
+	case "tier":
+		for _, v := range splitList(term.Value) {
+			if _, ok := tierRank[RiskTier(v)]; !ok {
+				return term, fmt.Errorf("tier: unknown risk tier %q", v)
+			}
+		}
+		if op != "=" && op != "!=" && strings.Contains(term.Value, ",") {
+			return term, fmt.Errorf("tier: %s takes a single tier", op)
+		}
+		return term, nil
+	case "status":
+		for _, v := range splitList(term.Value) {
+			if !RequestStatus(v).Valid() {
+				return term, fmt.Errorf("status: unknown status %q", v)
+			}
+		}
+	case "exit":
+		if _, err := strconv.Atoi(term.Value); err != nil {
+			return term, fmt.Errorf("exit: %q is not an exit code", term.Value)
+		}
+		return term, nil
+	case "problems":
+		if _, err := parseQueryBool(term.Value); err != nil {
+			return term, err
+		}
+	case "since", "until":
+		if _, err := ParseQueryTime(term.Value, time.Now()); err != nil {
+			return term, fmt.Errorf("%s: %w", field, err)
+		}
+	}
+	if op != "=" && op != "!=" {
+		return term, fmt.Errorf("%s: operator %s is only supported for tier and exit", field, op)
+	}
+	return term, nil
+}
+
+// Compile turns the query into a SQL condition over the requests table
+// (aliased r) and its positional arguments. now anchors relative times such
+// as since:7d. An empty query compiles to "1=1".
+func (q RequestQuery) Compile(now time.Time) (string, []any, error) {
+	return q.compile(now, false)
+}
+
+// redactedCommand is the command text searched for callers that may only
+// see the redacted display form.
+const redactedCommand = "COALESCE(r.command_display_redacted, '')"
+
+// compile is Compile; with redacted, cmd: terms and free text match the
+// redacted display command instead of the raw one.
+func (q RequestQuery) compile(now time.Time, redacted bool) (string, []any, error) {
+	var (
+		clauses []string
+		args    []any
+	)
+	column := "r.command_raw"
+	if redacted {
+		column = redactedCommand
+	}
+	for _, t := range q.Terms {
+		clause, termArgs, err := compileTerm(t, column, now)
+		if err != nil {
+			return "", nil, err
+		}
+		if t.Negate {
+			clause = "NOT (" + clause + ")"
+		}
+		clauses = append(clauses, clause)
+		args = append(args, termArgs...)
+	}
+	if redacted {
+		// The full-text index holds the raw command: search its other
+		// columns and the redacted command instead.
+		for _, w := range q.Text {
+			match := ftsMatch([]string{w})
+			if match == "" {
+				continue
+			}
+			clauses = append(clauses, "(r.rowid IN (SELECT rowid FROM requests_fts WHERE requests_fts MATCH ?) OR "+
+				redactedCommand+" LIKE ? ESCAPE '\\')")
+			args = append(args, "{justification requestor_agent status} : "+match, "%"+escapeLike(w)+"%")
+		}
+	} else if match := ftsMatch(q.Text); match != "" {
+		clauses = append(clauses, "r.rowid IN (SELECT rowid FROM requests_fts WHERE requests_fts MATCH ?)")
+		args = append(args, match)
+	}
+	if len(clauses) == 0 {
+		return "1=1", nil, nil
+	}
+	return strings.Join(clauses, " AND "), args, nil
+}
+
+// compileTerm compiles one field term; cmd: terms match commandColumn.
+func compileTerm(t QueryTerm, commandColumn string, now time.Time) (string, []any, error) {
+	switch t.Field {
+	case "id":
+		return "r.id LIKE ? ESCAPE '\\'", []any{escapeLike(t.Value) + "%"}, nil
+	case "tier":
+		return compileTier(t)
+	case "status":
+		return compileIn("r.status", t, false)
+	case "agent":
+		return compileIn("r.requestor_agent", t, true)
+	case "model":
+		return compileIn("r.requestor_model", t, true)
+	case "project":
+		return compileIn("r.project_path", t, false)
+	case "since", "until":
+		at, err := ParseQueryTime(t.Value, now)
+		if err != nil {
+			return "", nil, fmt.Errorf("%s: %w", t.Field, err)
+		}
+		op := ">="
+		if t.Field == "until" {
+			op = "<"
+		}
+		return "r.created_at " + op + " ?", []any{at.UTC().Format(time.RFC3339)}, nil
+	case "cmd":
+		return compileLike(commandColumn, t)
+	case "reason":
+		return compileLike("r.justification_reason", t)
+	case "exit":
+		code, err := strconv.Atoi(t.Value)
+		if err != nil {
+			return "", nil, fmt.Errorf("exit: %q is not an exit code", t.Value)
+		}
+		return "r.execution_exit_code " + t.Op + " ?", []any{code}, nil
+	case "problems":
+		want, err := parseQueryBool(t.Value)
+		if err != nil {
+			return "", nil, err
+		}
+		if t.Op == "!=" {
+			want = !want
+		}
+		clause := "EXISTS (SELECT 1 FROM execution_outcomes o WHERE o.request_id = r.id AND o.caused_problems = 1)"
+		if !want {
+			clause = "NOT " + clause
+		}
+		return clause, nil, nil
+	case "reviewer":
+		values := splitList(t.Value)
+		clause := "EXISTS (SELECT 1 FROM reviews v WHERE v.request_id = r.id AND v.reviewer_agent COLLATE NOCASE IN (" + placeholders(len(values)) + "))"
+		if t.Op == "!=" {
+			clause = "NOT " + clause
+		}
+		return clause, stringArgs(values), nil
+	}
+	return "", nil, fmt.Errorf("unknown query field %q", t.Field)
+}
+
+func compileTier(t QueryTerm) (string, []any, error) {
+	values := splitList(t.Value)
+	if t.Op != "=" && t.Op != "!=" {
+		pivot := tierRank[RiskTier(values[0])]
+		values = values[:0]
+		for tier, rank := range tierRank {
+			if compareInts(rank, t.Op, pivot) {
+				values = append(values, string(tier))
+			}
+		}
+		if len(values) == 0 {
+			return "0", nil, nil
+		}
+		return "r.risk_tier IN (" + placeholders(len(values)) + ")", stringArgs(values), nil
+	}
+	return compileIn("r.risk_tier", QueryTerm{Field: t.Field, Op: t.Op, Value: strings.Join(values, ",")}, false)
+}
+
+func compileIn(column string, t QueryTerm, nocase bool) (string, []any, error) {
+	values := splitList(t.Value)
+	if nocase {
+		column += " COLLATE NOCASE"
+	}
+	op := "IN"
+	if t.Op == "!=" {
+		op = "NOT IN"
+	}
+	return column + " " + op + " (" + placeholders(len(values)) + ")", stringArgs(values), nil
+}
+
+func compileLike(column string, t QueryTerm) (string, []any, error) {
+	op := "LIKE"
+	if t.Op == "!=" {
+		op = "NOT LIKE"
+	}
+	return column + " " + op + " ? ESCAPE '\\'", []any{"%" + escapeLike(t.Value) + "%"}, nil
+}
+
+// QueryOptions controls scoping and paging for QueryRequests.
+type QueryOptions struct {
+	// ProjectPath scopes results to one project unless the query has its own
+	// project: term. Empty means all projects in the database.
+	ProjectPath string
+	// Limit is the page size; <= 0 uses DefaultQueryLimit.
+	Limit int
+	// Cursor continues after the last request of a previous page.
+	Cursor string
+	// Now anchors relative times; zero means time.Now().
+	Now time.Time
+	// RedactedCommands matches cmd: terms and free text against the
+	// redacted display command, for callers that may not see raw commands.
+	RedactedCommands bool
+}
+
+// RequestPage is one page of query results, newest first.
+type RequestPage struct {
+	Requests []*Request `json:"requests"`
+	// Total counts all matches, ignoring the cursor and limit.
+	Total int `json:"total"`
+	// NextCursor fetches the following page; empty on the last page.
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+// where compiles the query and adds the project scope from opts.
+func (q RequestQuery) where(opts QueryOptions) (string, []any, error) {
+	now := opts.Now
+	if now.IsZero() {
+		now = time.Now()
+	}
+	where, args, err := q.compile(now, opts.RedactedCommands)
+	if err != nil {
+		return "", nil, err
+	}
+	if opts.ProjectPath != "" && !q.HasField("project") {
+		where += " AND r.project_path = ?"
+		args = append(args, opts.ProjectPath)
+	}
+	return where, args, nil
+}
+
+// QueryRequests runs a history query in the database, returning one page of
+// results ordered by creation time (newest first) with a keyset cursor for
+// the next page.
+func (db *DB) QueryRequests(q RequestQuery, opts QueryOptions) (*RequestPage, error) {
+	limit := opts.Limit
+	if limit <= 0 {
+		limit = DefaultQueryLimit
+	}
+
+	where, args, err := q.where(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &RequestPage{Requests: []*Request{}}
+	if err := db.QueryRow(`SELECT COUNT(*) FROM requests r WHERE `+where, args...).Scan(&page.Total); err != nil {
+		return nil, fmt.Errorf("counting requests: %w", err)
+	}
+
+	if opts.Cursor != "" {
+		createdAt, id, err := decodeCursor(opts.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		where += " AND (r.created_at < ? OR (r.created_at = ? AND r.id < ?))"
+		args = append(args, createdAt, createdAt, id)
+	}
+	args = append(args, limit+1)
+
+	rows, err := db.Query(`
+		SELECT r.id, r.project_path,
+			r.command_raw, r.command_argv_json, r.command_cwd, r.command_shell, r.command_hash,
+			r.command_display_redacted, r.command_contains_sensitive,
+			r.risk_tier, r.requestor_session_id, r.requestor_agent, r.requestor_model,
+			r.justification_reason, r.justification_expected_effect, r.justification_goal, r.justification_safety_argument,
+			r.dry_run_command, r.dry_run_output, r.attachments_json, r.environment_json, r.sandbox_json, r.plan_json, r.window_json, r.policy_json, r.auto_answers_json,
+			r.status, r.min_approvals, r.require_different_model,
+			r.execution_log_path, r.execution_exit_code, r.execution_duration_ms,
+			r.execution_executed_at, r.execution_executed_by_session_id, r.execution_executed_by_agent, r.execution_executed_by_model,
+			r.rollback_path, r.rollback_rolled_back_at,
+			r.created_at, r.resolved_at, r.expires_at, r.approval_expires_at
+		FROM requests r
+		WHERE `+where+`
+		ORDER BY r.created_at DESC, r.id DESC
+		LIMIT ?
+	`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("querying requests: %w", err)
+	}
+	defer rows.Close()
+
+	requests, err := scanRequests(rows)
+	if err != nil {
+		return nil, err
+	}
+	if len(requests) > limit {
+		requests = requests[:limit]
+		last := requests[len(requests)-1]
+		page.NextCursor = encodeCursor(last.CreatedAt.UTC().Format(time.RFC3339), last.ID)
+	}
+	if requests != nil {
+		page.Requests = requests
+	}
+	return page, nil
+}
+
+// RequestCount is the number of requests created on one day with a given
+// tier and status.
+type RequestCount struct {
+	Day      string        `json:"day"` // YYYY-MM-DD (UTC)
+	RiskTier RiskTier      `json:"risk_tier"`
+	Status   RequestStatus `json:"status"`
+	Count    int           `json:"count"`
+}
+
+// CountRequestsMatching groups the requests matching q by creation day, tier
+// and status. opts.Limit and opts.Cursor are ignored.
+func (db *DB) CountRequestsMatching(q RequestQuery, opts QueryOptions) ([]RequestCount, error) {
+	where, args, err := q.where(opts)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := db.Query(`
+		SELECT substr(r.created_at, 1, 10) AS day, r.risk_tier, r.status, COUNT(*)
+		FROM requests r
+		WHERE `+where+`
+		GROUP BY day, r.risk_tier, r.status
+		ORDER BY day, r.risk_tier, r.status
+	`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("counting requests: %w", err)
+	}
+	defer rows.Close()
+
+	var counts []RequestCount
+	for rows.Next() {
+		var c RequestCount
+		var tier, status string
+		if err := rows.Scan(&c.Day, &tier, &status, &c.Count); err != nil {
+			return nil, fmt.Errorf("scanning request count: %w", err)
+		}
+		c.RiskTier, c.Status = RiskTier(tier), RequestStatus(status)
+		counts = append(counts, c)
+	}
+	return counts, rows.Err()
+}
+
+// ListAgentsMatching returns the distinct requestor agents of the requests
+// matching q, sorted by name.
+func (db *DB) ListAgentsMatching(q RequestQuery, opts QueryOptions) ([]string, error) {
+	where, args, err := q.where(opts)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := db.Query(`SELECT DISTINCT r.requestor_agent FROM requests r WHERE `+where+` ORDER BY r.requestor_agent`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("listing agents: %w", err)
+	}
+	defer rows.Close()
+
+	var agents []string
+	for rows.Next() {
+		var agent string
+		if err := rows.Scan(&agent); err != nil {
+			return nil, fmt.Errorf("scanning agent: %w", err)
+		}
+		agents = append(agents, agent)
+	}
+	return agents, rows.Err()
+}
+
+func encodeCursor(createdAt, id string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(createdAt + "|" + id))
+}
+
+func decodeCursor(cursor string) (createdAt, id string, err error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return "", "", ErrInvalidCursor
+	}
+	createdAt, id, ok := strings.Cut(string(raw), "|")
+	if !ok || id == "" {
+		return "", "", ErrInvalidCursor
+	}
+	if _, err := time.Parse(time.RFC3339, createdAt); err != nil {
+		return "", "", ErrInvalidCursor
+	}
+	return createdAt, id, nil
+}
+
+// ParseQueryTime accepts a relative age (30m, 12h, 7d, 2w), a date
+// (2006-01-02) or an RFC3339 timestamp.
+func ParseQueryTime(value string, now time.Time) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	if t, err := time.ParseInLocation("2006-01-02", value, time.Local); err == nil {
+		return t, nil
+	}
+	if len(value) >= 2 {
+		n, err := strconv.Atoi(value[:len(value)-1])
+		if err == nil && n >= 0 {
+			unit := map[byte]time.Duration{
+				'm': time.Minute, 'h': time.Hour, 'd': 24 * time.Hour, 'w': 7 * 24 * time.Hour,
+			}[value[len(value)-1]]
+			if unit != 0 {
+				return now.Add(-time.Duration(n) * unit), nil
+			}
+		}
+	}
+	return time.Time{}, fmt.Errorf("%q is not a time (use 7d, 12h, 2006-01-02 or RFC3339)", value)
+}
+
+func parseQueryBool(value string) (bool, error) {
+	switch strings.ToLower(value) {
+	case "true", "yes", "1":
+		return true, nil
+	case "false", "no", "0":
+		return false, nil
+	}
+	return false, fmt.Errorf("problems: %q is not true or false", value)
+}
+
+// tokenizeQuery splits on whitespace outside double quotes, keeping the
+// quotes. Single quotes are literal so commands like echo 'x' can be searched.
+func tokenizeQuery(input string) ([]string, error) {
+	var (
+		tokens []string
+		cur    strings.Builder
+		quote  rune
+	)
+	for _, r := range input {
+		switch {
+		case quote != 0:
+			cur.WriteRune(r)
+			if r == quote {
+				quote = 0
+			}
+		case r == '"':
+			quote = r
+			cur.WriteRune(r)
+		case unicode.IsSpace(r):
+			if cur.Len() > 0 {
+				tokens = append(tokens, cur.String())
+				cur.Reset()
+			}
+		default:
+			cur.WriteRune(r)
+		}
+	}
+	if quote != 0 {
+		return nil, fmt.Errorf("unterminated quote in query")
+	}
+	if cur.Len() > 0 {
+		tokens = append(tokens, cur.String())
+	}
+	return tokens, nil
+}
+
+// unquote removes quotes from a token such as "kubectl delete" or
+// cmd:"a b" values.
+func unquote(s string) string {
+	var b strings.Builder
+	var quote rune
+	for _, r := range s {
+		switch {
+		case quote == 0 && r == '"':
+			quote = r
+		case r == quote:
+			quote = 0
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+func isFieldName(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if r < 'a' || r > 'z' {
+			return false
+		}
+	}
+	return true
+}
+
+func splitList(value string) []string {
+	var out []string
+	for _, v := range strings.Split(value, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+// ftsMatch quotes each free-text word as an FTS5 phrase; the phrases are
+// ANDed together.
+func ftsMatch(words []string) string {
+	var phrases []string
+	for _, w := range words {
+		w = strings.ReplaceAll(w, `"`, `""`)
+		if strings.TrimSpace(w) != "" {
+			phrases = append(phrases, `"`+w+`"`)
+		}
+	}
+	return strings.Join(phrases, " ")
+}
+
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
+
+func placeholders(n int) string {
+	return strings.TrimSuffix(strings.Repeat("?,", n), ",")
+}
+
+func stringArgs(values []string) []any {
+	args := make([]any, len(values))
+	for i, v := range values {
+		args[i] = v
+	}
+	return args
+}
+
+func compareInts(a int, op string, b int) bool {
+	switch op {
+	case "<":
+		return a < b
+	case "<=":
+		return a <= b
+	case ">":
+		return a > b
+	case ">=":
+		return a >= b
+	case "!=":
+		return a != b
+	}
+	return a == b
+}
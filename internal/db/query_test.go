@@ -0,0 +1,284 @@
+package db
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestParseRequestQuery(t *testing.T) {
+	saved := map[string]string{"Failures": "status:execution_failed,executed exit:!=0", "loop": "@loop"}
+
+	q, err := ParseRequestQuery(`tier:critical agent:BlueLake since:7d cmd:"kubectl delete" exit:!=0 -problems:true rm`, nil)
+	if err != nil {
+		t.Fatalf("ParseRequestQuery: %v", err)
+	}
+	want := []QueryTerm{
+		{Field: "tier", Op: "=", Value: "critical"},
+		{Field: "agent", Op: "=", Value: "BlueLake"},
+		{Field: "since", Op: "=", Value: "7d"},
+		{Field: "cmd", Op: "=", Value: "kubectl delete"},
+		{Field: "exit", Op: "!=", Value: "0"},
+		{Field: "problems", Op: "=", Value: "true", Negate: true},
+	}
+	if len(q.Terms) != len(want) {
+		t.Fatalf("terms = %+v", q.Terms)
+	}
+	for i := range want {
+		if q.Terms[i] != want[i] {
+			t.Errorf("term %d = %+v, want %+v", i, q.Terms[i], want[i])
+		}
+	}
+	if len(q.Text) != 1 || q.Text[0] != "rm" {
+		t.Errorf("text = %v", q.Text)
+	}
+
+	q, err = ParseRequestQuery("@failures agent:x", saved)
+	if err != nil || len(q.Terms) != 3 || q.Terms[0].Field != "status" {
+		t.Errorf("saved query expansion = %+v, %v", q, err)
+	}
+
+	for _, bad := range []string{
+		"teir:critical", "tier:extreme", "status:done", "exit:abc", "since:soon",
+		"agent:>x", "problems:maybe", `cmd:"open`, "@missing", "@loop", "tier:",
+	} {
+		if _, err := ParseRequestQuery(bad, saved); err == nil {
+			t.Errorf("ParseRequestQuery(%q) succeeded, want error", bad)
+		}
+	}
+}
+
+func TestRequestQueryCompile(t *testing.T) {
+	now := time.Date(2025, 6, 10, 12, 0, 0, 0, time.UTC)
+	q, err := ParseRequestQuery("tier:>=dangerous since:2d exit:!=0 cmd:50%", nil)
+	if err != nil {
+		t.Fatalf("ParseRequestQuery: %v", err)
+	}
+	where, args, err := q.Compile(now)
+	if err != nil {
+		t.Fatalf("Compile: %v", err)
+	}
+	wantWhere := `r.risk_tier IN (?,?) AND r.created_at >= ? AND r.execution_exit_code != ? AND r.command_raw LIKE ? ESCAPE '\'`
+	if where != wantWhere {
+		t.Errorf("where = %s", where)
+	}
+	if len(args) != 5 || args[2] != "2025-06-08T12:00:00Z" || args[3] != 0 || args[4] != `%50\%%` {
+		t.Errorf("args = %v", args)
+	}
+
+	empty, _, _ := RequestQuery{}.Compile(now)
+	if empty != "1=1" {
+		t.Errorf("empty query compiled to %q", empty)
+	}
+}
+
+func TestQueryRequests(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	sess := &Session{AgentName: "BlueLake", Program: "claude-code", Model: "opus", ProjectPath: "/test/project"}
+	if err := db.CreateSession(sess); err != nil {
+		t.Fatalf("CreateSession failed: %v", err)
+	}
+	reviewer := &Session{AgentName: "GreenHill", Program: "codex", Model: "gpt", ProjectPath: "/test/project"}
+	if err := db.CreateSession(reviewer); err != nil {
+		t.Fatalf("CreateSession failed: %v", err)
+	}
+
+	base := time.Now().UTC().Add(-10 * 24 * time.Hour).Truncate(time.Second)
+	create := func(i int, cmd string, tier RiskTier, project string) *Request {
+		r := &Request{
+			ProjectPath:        project,
+			RequestorSessionID: sess.ID,
+			RequestorAgent:     sess.AgentName,
+			RequestorModel:     sess.Model,
+			RiskTier:           tier,
+			MinApprovals:       1,
+			Command:            CommandSpec{Raw: cmd, Cwd: project},
+			Justification:      Justification{Reason: "maintenance"},
+		}
+		if err := db.CreateRequest(r); err != nil {
+			t.Fatalf("CreateRequest failed: %v", err)
+		}
+		r.CreatedAt = base.Add(time.Duration(i) * 24 * time.Hour)
+		if _, err := db.Exec(`UPDATE requests SET created_at = ? WHERE id = ?`, r.CreatedAt.Format(time.RFC3339), r.ID); err != nil {
+			t.Fatalf("update created_at: %v", err)
+		}
+		return r
+	}
+
+	old := create(0, "kubectl delete pod web-1", RiskTierCritical, "/test/project")
+	failed := create(8, "kubectl delete ns staging", RiskTierCritical, "/test/project")
+	create(8, "git push --force", RiskTierDangerous, "/test/project")
+	create(9, "make clean", RiskTierCaution, "/test/project")
+	create(9, "kubectl delete pod api-1", RiskTierCritical, "/other/project")
+
+	exit := 1
+	if err := db.UpdateRequestExecution(failed.ID, &Execution{ExitCode: &exit}); err != nil {
+		t.Fatalf("UpdateRequestExecution: %v", err)
+	}
+	if _, err := db.RecordOutcome(failed.ID, true, "deleted the wrong namespace", nil, ""); err != nil {
+		t.Fatalf("RecordOutcome: %v", err)
+	}
+	if err := db.CreateReview(&Review{
+		RequestID: failed.ID, ReviewerSessionID: reviewer.ID, ReviewerAgent: reviewer.AgentName,
+		ReviewerModel: reviewer.Model, Decision: DecisionApprove, Signature: "sig",
+	}); err != nil {
+		t.Fatalf("CreateReview: %v", err)
+	}
+
+	run := func(query string, opts QueryOptions) *RequestPage {
+		t.Helper()
+		q, err := ParseRequestQuery(query, nil)
+		if err != nil {
+			t.Fatalf("ParseRequestQuery(%q): %v", query, err)
+		}
+		page, err := db.QueryRequests(q, opts)
+		if err != nil {
+			t.Fatalf("QueryRequests(%q): %v", query, err)
+		}
+		return page
+	}
+	scoped := QueryOptions{ProjectPath: "/test/project"}
+
+	cases := []struct {
+		query string
+		want  int
+	}{
+		{"", 4},
+		{"tier:critical", 2},
+		{"tier:>=dangerous", 3},
+		{"-tier:critical", 2},
+		{`cmd:"kubectl delete" since:7d`, 1},
+		{"exit:!=0 problems:true", 1},
+		{"problems:false", 3},
+		{"reviewer:greenhill", 1},
+		{"agent:bluelake status:pending", 4},
+		{"kubectl", 2},
+		{"project:/other/project", 1},
+		{"project:/test/project,/other/project tier:critical", 3},
+	}
+	for _, tc := range cases {
+		if page := run(tc.query, scoped); page.Total != tc.want || len(page.Requests) != tc.want {
+			t.Errorf("%q: total %d, got %d requests, want %d", tc.query, page.Total, len(page.Requests), tc.want)
+		}
+	}
+
+	// Pages follow the keyset cursor, newest first, with no overlap.
+	var seen []string
+	cursor := ""
+	for i := 0; i < 3; i++ {
+		page := run("", QueryOptions{ProjectPath: "/test/project", Limit: 3, Cursor: cursor})
+		if page.Total != 4 {
+			t.Errorf("page %d total = %d", i, page.Total)
+		}
+		for _, r := range page.Requests {
+			seen = append(seen, r.ID)
+		}
+		cursor = page.NextCursor
+		if cursor == "" {
+			break
+		}
+	}
+	if len(seen) != 4 || seen[3] != old.ID {
+		t.Errorf("paged results = %v", seen)
+	}
+
+	if _, err := db.QueryRequests(RequestQuery{}, QueryOptions{Cursor: "not-a-cursor"}); !errors.Is(err, ErrInvalidCursor) {
+		t.Errorf("expected ErrInvalidCursor, got %v", err)
+	}
+}
+
+func TestQueryAggregates(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	var sessions []*Session
+	for _, name := range []string{"BlueLake", "RedRiver"} {
+		s := &Session{AgentName: name, Program: "claude-code", Model: "opus", ProjectPath: "/test/project"}
+		if err := db.CreateSession(s); err != nil {
+			t.Fatalf("CreateSession failed: %v", err)
+		}
+		sessions = append(sessions, s)
+	}
+	create := func(sess *Session, tier RiskTier, createdAt time.Time) *Request {
+		r := &Request{
+			ProjectPath: "/test/project", RequestorSessionID: sess.ID, RequestorAgent: sess.AgentName,
+			RequestorModel: sess.Model, RiskTier: tier, MinApprovals: 1,
+			Command: CommandSpec{Raw: "make deploy", Cwd: "/test/project"}, Justification: Justification{Reason: "ship"},
+		}
+		if err := db.CreateRequest(r); err != nil {
+			t.Fatalf("CreateRequest failed: %v", err)
+		}
+		if _, err := db.Exec(`UPDATE requests SET created_at = ? WHERE id = ?`, createdAt.Format(time.RFC3339), r.ID); err != nil {
+			t.Fatalf("update created_at: %v", err)
+		}
+		return r
+	}
+
+	day1 := time.Date(2025, 6, 1, 9, 0, 0, 0, time.UTC)
+	day2 := day1.Add(24 * time.Hour)
+	approved := create(sessions[0], RiskTierCritical, day1)
+	create(sessions[0], RiskTierCritical, day1.Add(time.Hour))
+	create(sessions[1], RiskTierCaution, day2)
+	create(sessions[1], RiskTierCaution, day1.Add(-30*24*time.Hour)) // outside the window
+
+	if _, err := db.Exec(`UPDATE requests SET status = 'executed' WHERE id = ?`, approved.ID); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.CreateReview(&Review{
+		RequestID: approved.ID, ReviewerSessionID: sessions[1].ID, ReviewerAgent: "RedRiver",
+		ReviewerModel: "opus", Decision: DecisionApprove, Signature: "sig",
+	}); err != nil {
+		t.Fatalf("CreateReview: %v", err)
+	}
+	if _, err := db.Exec(`UPDATE reviews SET created_at = ? WHERE request_id = ?`, day1.Add(30*time.Minute).Format(time.RFC3339), approved.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	q, err := ParseRequestQuery("since:2025-05-25", nil)
+	if err != nil {
+		t.Fatalf("ParseRequestQuery: %v", err)
+	}
+	opts := QueryOptions{ProjectPath: "/test/project"}
+
+	counts, err := db.CountRequestsMatching(q, opts)
+	if err != nil {
+		t.Fatalf("CountRequestsMatching: %v", err)
+	}
+	want := []RequestCount{
+		{Day: "2025-06-01", RiskTier: RiskTierCritical, Status: StatusExecuted, Count: 1},
+		{Day: "2025-06-01", RiskTier: RiskTierCritical, Status: StatusPending, Count: 1},
+		{Day: "2025-06-02", RiskTier: RiskTierCaution, Status: StatusPending, Count: 1},
+	}
+	if len(counts) != len(want) {
+		t.Fatalf("counts = %+v", counts)
+	}
+	for i := range want {
+		if counts[i] != want[i] {
+			t.Errorf("counts[%d] = %+v, want %+v", i, counts[i], want[i])
+		}
+	}
+
+	agents, err := db.ListAgentsMatching(q, opts)
+	if err != nil || len(agents) != 2 || agents[0] != "BlueLake" {
+		t.Errorf("ListAgentsMatching = %v, %v", agents, err)
+	}
+
+	stats, err := db.GetRequestStatsByAgentMatching("RedRiver", q, opts)
+	if err != nil || stats.TotalRequests != 1 {
+		t.Errorf("GetRequestStatsByAgentMatching = %+v, %v", stats, err)
+	}
+	if all, _ := db.GetRequestStatsByAgent("RedRiver"); all.TotalRequests != 2 {
+		t.Errorf("GetRequestStatsByAgent total = %d, want 2", all.TotalRequests)
+	}
+
+	latency, err := db.GetTimeToApprovalStatsMatching(q, opts)
+	if err != nil || latency.SampleSize != 1 || latency.AvgMinutes < 29.9 || latency.AvgMinutes > 30.1 {
+		t.Errorf("GetTimeToApprovalStatsMatching = %+v, %v", latency, err)
+	}
+	caution, _ := ParseRequestQuery("tier:caution", nil)
+	if latency, _ := db.GetTimeToApprovalStatsMatching(caution, opts); latency.SampleSize != 0 {
+		t.Errorf("expected no approvals for caution, got %+v", latency)
+	}
+}
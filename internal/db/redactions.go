@@ -0,0 +1,53 @@
+package db
+
+import (
+	"fmt"
+	"time"
+)
+
+// Redaction counts the secrets scrubbed from one artifact of a request,
+// e.g. "execution_log", "transcript", "dry_run" or "attachment[0]".
+type Redaction struct {
+	RequestID string    `json:"request_id"`
+	Artifact  string    `json:"artifact"`
+	Count     int       `json:"count"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// RecordRedactions records how many secrets were scrubbed from an artifact,
+// replacing an earlier count for the same artifact.
+func (db *DB) RecordRedactions(requestID, artifact string, count int) error {
+	_, err := db.Exec(`
+		INSERT INTO redactions (request_id, artifact, count, created_at)
+		VALUES (?, ?, ?, ?)
+		ON CONFLICT (request_id, artifact) DO UPDATE SET count = excluded.count, created_at = excluded.created_at
+	`, requestID, artifact, count, time.Now().UTC().Format(time.RFC3339))
+	if err != nil {
+		return fmt.Errorf("recording redactions: %w", err)
+	}
+	return nil
+}
+
+// ListRedactions returns the redaction counts recorded for a request.
+func (db *DB) ListRedactions(requestID string) ([]Redaction, error) {
+	rows, err := db.Query(`
+		SELECT request_id, artifact, count, created_at
+		FROM redactions WHERE request_id = ? ORDER BY artifact
+	`, requestID)
+	if err != nil {
+		return nil, fmt.Errorf("listing redactions: %w", err)
+	}
+	defer rows.Close()
+
+	var out []Redaction
+	for rows.Next() {
+		var r Redaction
+		var createdAt string
+		if err := rows.Scan(&r.RequestID, &r.Artifact, &r.Count, &createdAt); err != nil {
+			return nil, fmt.Errorf("scanning redaction: %w", err)
+		}
+		r.CreatedAt, _ = time.Parse(time.RFC3339, createdAt)
+		out = append(out, r)
+	}
+	return out, rows.Err()
+}
@@ -0,0 +1,28 @@
+package db
+
+import "testing"
+
+func TestRedactions(t *testing.T) {
+	db := setupTestDB(t)
+	_, req := createTestRequest(t, db)
+
+	if got, err := db.ListRedactions(req.ID); err != nil || len(got) != 0 {
+		t.Fatalf("ListRedactions before recording = %v, %v", got, err)
+	}
+	for _, r := range []struct {
+		artifact string
+		count    int
+	}{{"execution_log", 1}, {"attachment[0]", 2}, {"execution_log", 3}} {
+		if err := db.RecordRedactions(req.ID, r.artifact, r.count); err != nil {
+			t.Fatalf("RecordRedactions: %v", err)
+		}
+	}
+
+	got, err := db.ListRedactions(req.ID)
+	if err != nil {
+		t.Fatalf("ListRedactions: %v", err)
+	}
+	if len(got) != 2 || got[0].Artifact != "attachment[0]" || got[0].Count != 2 || got[1].Artifact != "execution_log" || got[1].Count != 3 {
+		t.Errorf("ListRedactions = %+v", got)
+	}
+}
@@ -0,0 +1,94 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ReputationSample is one resolved request with the signals reputation
+// scoring uses: how it was decided, how it ran and whether it caused
+// problems.
+type ReputationSample struct {
+	RequestID string        `json:"request_id"`
+	Agent     string        `json:"agent"`
+	Model     string        `json:"model"`
+	Status    RequestStatus `json:"status"`
+	CreatedAt time.Time     `json:"created_at"`
+	ExitCode  *int          `json:"exit_code,omitempty"`
+	// CausedProblems is set when any recorded outcome reported problems.
+	CausedProblems bool `json:"caused_problems"`
+	// RejectionComments are the comments of rejecting reviews.
+	RejectionComments []string `json:"rejection_comments,omitempty"`
+}
+
+// reputationStatuses are the request states that say something about the
+// requestor. Pending, cancelled and unreviewed (timeout) requests do not.
+var reputationStatuses = []string{
+	string(StatusApproved), string(StatusRejected), string(StatusExecuting), string(StatusExecuted),
+	string(StatusExecutionFailed),
+}
+
+// ListReputationSamples returns the resolved requests created since the
+// given time by an agent (matched case-insensitively) or, when agent is
+// empty, by a requestor model. Newest first.
+func (db *DB) ListReputationSamples(agent, model string, since time.Time) ([]ReputationSample, error) {
+	var subject string
+	args := []any{}
+	switch {
+	case agent != "":
+		subject = "r.requestor_agent = ? COLLATE NOCASE"
+		args = append(args, agent)
+	case model != "":
+		subject = "r.requestor_model = ? COLLATE NOCASE"
+		args = append(args, model)
+	default:
+		return nil, fmt.Errorf("agent or model is required")
+	}
+	args = append(args, since.UTC().Format(time.RFC3339))
+	for _, s := range reputationStatuses {
+		args = append(args, s)
+	}
+
+	rows, err := db.Query(`
+		SELECT r.id, r.requestor_agent, r.requestor_model, r.status, r.created_at, r.execution_exit_code,
+			EXISTS (SELECT 1 FROM execution_outcomes o WHERE o.request_id = r.id AND o.caused_problems = 1),
+			(SELECT group_concat(v.comments, char(31)) FROM reviews v
+			 WHERE v.request_id = r.id AND v.decision = 'reject' AND COALESCE(v.comments, '') != '')
+		FROM requests r
+		WHERE `+subject+` AND r.created_at >= ? AND r.status IN (`+placeholders(len(reputationStatuses))+`)
+		ORDER BY r.created_at DESC, r.id DESC
+	`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("listing reputation samples: %w", err)
+	}
+	defer rows.Close()
+
+	var samples []ReputationSample
+	for rows.Next() {
+		var (
+			s         ReputationSample
+			status    string
+			createdAt string
+			exitCode  sql.NullInt64
+			problems  int
+			comments  sql.NullString
+		)
+		if err := rows.Scan(&s.RequestID, &s.Agent, &s.Model, &status, &createdAt, &exitCode, &problems, &comments); err != nil {
+			return nil, fmt.Errorf("scanning reputation sample: %w", err)
+		}
+		s.Status = RequestStatus(status)
+		s.CreatedAt, _ = time.Parse(time.RFC3339, createdAt)
+		if exitCode.Valid {
+			code := int(exitCode.Int64)
+			s.ExitCode = &code
+		}
+		s.CausedProblems = problems == 1
+		if comments.Valid {
+			s.RejectionComments = strings.Split(comments.String, "\x1f")
+		}
+		samples = append(samples, s)
+	}
+	return samples, rows.Err()
+}
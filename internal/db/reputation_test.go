@@ -0,0 +1,82 @@
+package db
+
+import (
+	"testing"
+	"time"
+)
+
+func TestListReputationSamples(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	requestor := &Session{AgentName: "BlueLake", Program: "claude-code", Model: "opus", ProjectPath: "/test/project"}
+	reviewer := &Session{AgentName: "RedRiver", Program: "codex", Model: "gpt", ProjectPath: "/test/project"}
+	for _, s := range []*Session{requestor, reviewer} {
+		if err := db.CreateSession(s); err != nil {
+			t.Fatalf("CreateSession failed: %v", err)
+		}
+	}
+	create := func(status RequestStatus) *Request {
+		r := &Request{
+			ProjectPath: "/test/project", RequestorSessionID: requestor.ID, RequestorAgent: "BlueLake",
+			RequestorModel: "opus", RiskTier: RiskTierDangerous, MinApprovals: 1, Status: status,
+			Command: CommandSpec{Raw: "make deploy", Cwd: "/test/project"}, Justification: Justification{Reason: "ship"},
+		}
+		if err := db.CreateRequest(r); err != nil {
+			t.Fatalf("CreateRequest failed: %v", err)
+		}
+		return r
+	}
+
+	executed := create(StatusExecuted)
+	exit := 2
+	if err := db.UpdateRequestExecution(executed.ID, &Execution{ExitCode: &exit}); err != nil {
+		t.Fatalf("UpdateRequestExecution: %v", err)
+	}
+	if err := db.CreateOutcome(&ExecutionOutcome{RequestID: executed.ID, CausedProblems: true}); err != nil {
+		t.Fatalf("CreateOutcome: %v", err)
+	}
+	rejected := create(StatusRejected)
+	if err := db.CreateReview(&Review{
+		RequestID: rejected.ID, ReviewerSessionID: reviewer.ID, ReviewerAgent: "RedRiver",
+		ReviewerModel: "gpt", Decision: DecisionReject, Signature: "sig", Comments: "no rollback plan",
+	}); err != nil {
+		t.Fatalf("CreateReview: %v", err)
+	}
+	create(StatusPending)
+	create(StatusCancelled)
+	create(StatusTimedOut)
+
+	samples, err := db.ListReputationSamples("bluelake", "", time.Now().Add(-time.Hour))
+	if err != nil {
+		t.Fatalf("ListReputationSamples: %v", err)
+	}
+	if len(samples) != 2 {
+		t.Fatalf("expected 2 resolved samples, got %d: %+v", len(samples), samples)
+	}
+	byID := map[string]ReputationSample{}
+	for _, s := range samples {
+		byID[s.RequestID] = s
+	}
+	if s := byID[executed.ID]; !s.CausedProblems || s.ExitCode == nil || *s.ExitCode != 2 {
+		t.Errorf("executed sample = %+v", s)
+	}
+	if s := byID[rejected.ID]; len(s.RejectionComments) != 1 || s.RejectionComments[0] != "no rollback plan" || s.CausedProblems {
+		t.Errorf("rejected sample = %+v", s)
+	}
+
+	byModel, err := db.ListReputationSamples("", "OPUS", time.Now().Add(-time.Hour))
+	if err != nil {
+		t.Fatalf("ListReputationSamples by model: %v", err)
+	}
+	if len(byModel) != 2 {
+		t.Errorf("expected 2 samples by model, got %d", len(byModel))
+	}
+
+	if samples, err := db.ListReputationSamples("BlueLake", "", time.Now().Add(time.Hour)); err != nil || len(samples) != 0 {
+		t.Errorf("expected no samples after since, got %d (%v)", len(samples), err)
+	}
+	if _, err := db.ListReputationSamples("", "", time.Time{}); err == nil {
+		t.Error("expected error without agent or model")
+	}
+}
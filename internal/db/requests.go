@@ -35,6 +35,9 @@ func (db *DB) CreateRequest(r *Request) error {
 	if r.Command.Hash == "" {
 		r.Command.Hash = ComputeCommandHash(r.Command)
 	}
+	if r.Plan != nil && r.Plan.Hash == "" {
+		r.Plan.Hash = ComputePlanHash(r.Plan.Steps)
+	}
 
 	// Set timestamps
 	now := time.Now().UTC()
@@ -50,25 +53,49 @@ func (db *DB) CreateRequest(r *Request) error {
 	// Serialize complex fields
 	argvJSON, _ := json.Marshal(r.Command.Argv)
 	attachmentsJSON, _ := json.Marshal(r.Attachments)
+	environmentJSON, err := encodeEnvironment(r.Environment)
+	if err != nil {
+		return err
+	}
+	sandboxJSON, err := encodeSandbox(r.Sandbox)
+	if err != nil {
+		return err
+	}
+	planJSON, err := encodePlan(r.Plan)
+	if err != nil {
+		return err
+	}
+	windowJSON, err := encodeWindow(r.Window)
+	if err != nil {
+		return err
+	}
+	policyJSON, err := encodePolicy(r.Policy)
+	if err != nil {
+		return err
+	}
+	autoAnswersJSON, err := encodeAutoAnswers(r.AutoAnswers)
+	if err != nil {
+		return err
+	}
 
-	_, err := db.Exec(`
+	_, err = db.Exec(`
 		INSERT INTO requests (
 			id, project_path,
 			command_raw, command_argv_json, command_cwd, command_shell, command_hash,
 			command_display_redacted, command_contains_sensitive,
 			risk_tier, requestor_session_id, requestor_agent, requestor_model,
 			justification_reason, justification_expected_effect, justification_goal, justification_safety_argument,
-			dry_run_command, dry_run_output, attachments_json,
+			dry_run_command, dry_run_output, attachments_json, environment_json, sandbox_json, plan_json, window_json, policy_json, auto_answers_json,
 			status, min_approvals, require_different_model,
 			created_at, expires_at, approval_expires_at
-		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 	`,
 		r.ID, r.ProjectPath,
 		r.Command.Raw, string(argvJSON), r.Command.Cwd, boolToInt(r.Command.Shell), r.Command.Hash,
 		nullString(r.Command.DisplayRedacted), boolToInt(r.Command.ContainsSensitive),
 		string(r.RiskTier), r.RequestorSessionID, r.RequestorAgent, r.RequestorModel,
 		r.Justification.Reason, nullString(r.Justification.ExpectedEffect), nullString(r.Justification.Goal), nullString(r.Justification.SafetyArgument),
-		nullDryRunCommand(r.DryRun), nullDryRunOutput(r.DryRun), string(attachmentsJSON),
+		nullDryRunCommand(r.DryRun), nullDryRunOutput(r.DryRun), string(attachmentsJSON), environmentJSON, sandboxJSON, planJSON, windowJSON, policyJSON, autoAnswersJSON,
 		string(r.Status), r.MinApprovals, boolToInt(r.RequireDifferentModel),
 		r.CreatedAt.Format(time.RFC3339), formatTimePtr(r.ExpiresAt), formatTimePtr(r.ApprovalExpiresAt),
 	)
@@ -88,7 +115,7 @@ func (db *DB) GetRequestTx(tx *sql.Tx, id string) (*Request, error) {
 			command_display_redacted, command_contains_sensitive,
 			risk_tier, requestor_session_id, requestor_agent, requestor_model,
 			justification_reason, justification_expected_effect, justification_goal, justification_safety_argument,
-			dry_run_command, dry_run_output, attachments_json,
+			dry_run_command, dry_run_output, attachments_json, environment_json, sandbox_json, plan_json, window_json, policy_json, auto_answers_json,
 			status, min_approvals, require_different_model,
 			execution_log_path, execution_exit_code, execution_duration_ms,
 			execution_executed_at, execution_executed_by_session_id, execution_executed_by_agent, execution_executed_by_model,
@@ -108,7 +135,7 @@ func (db *DB) GetRequest(id string) (*Request, error) {
 			command_display_redacted, command_contains_sensitive,
 			risk_tier, requestor_session_id, requestor_agent, requestor_model,
 			justification_reason, justification_expected_effect, justification_goal, justification_safety_argument,
-			dry_run_command, dry_run_output, attachments_json,
+			dry_run_command, dry_run_output, attachments_json, environment_json, sandbox_json, plan_json, window_json, policy_json, auto_answers_json,
 			status, min_approvals, require_different_model,
 			execution_log_path, execution_exit_code, execution_duration_ms,
 			execution_executed_at, execution_executed_by_session_id, execution_executed_by_agent, execution_executed_by_model,
@@ -129,7 +156,7 @@ func (db *DB) GetRequestWithReviews(id string) (*Request, []*Review, error) {
 
 	rows, err := db.Query(`
 		SELECT id, request_id, reviewer_session_id, reviewer_agent, reviewer_model,
-			decision, signature, signature_timestamp, responses_json, comments, created_at
+			decision, signature, signature_timestamp, responses_json, comments, require_sandbox, created_at
 		FROM reviews WHERE request_id = ?
 		ORDER BY created_at ASC
 	`, id)
@@ -170,7 +197,7 @@ func (db *DB) ListPendingRequestsByProjects(projectPaths []string) ([]*Request,
 			command_display_redacted, command_contains_sensitive,
 			risk_tier, requestor_session_id, requestor_agent, requestor_model,
 			justification_reason, justification_expected_effect, justification_goal, justification_safety_argument,
-			dry_run_command, dry_run_output, attachments_json,
+			dry_run_command, dry_run_output, attachments_json, environment_json, sandbox_json, plan_json, window_json, policy_json, auto_answers_json,
 			status, min_approvals, require_different_model,
 			execution_log_path, execution_exit_code, execution_duration_ms,
 			execution_executed_at, execution_executed_by_session_id, execution_executed_by_agent, execution_executed_by_model,
@@ -198,7 +225,7 @@ func (db *DB) ListPendingRequestsAllProjects() ([]*Request, error) {
 			command_display_redacted, command_contains_sensitive,
 			risk_tier, requestor_session_id, requestor_agent, requestor_model,
 			justification_reason, justification_expected_effect, justification_goal, justification_safety_argument,
-			dry_run_command, dry_run_output, attachments_json,
+			dry_run_command, dry_run_output, attachments_json, environment_json, sandbox_json, plan_json, window_json, policy_json, auto_answers_json,
 			status, min_approvals, require_different_model,
 			execution_log_path, execution_exit_code, execution_duration_ms,
 			execution_executed_at, execution_executed_by_session_id, execution_executed_by_agent, execution_executed_by_model,
@@ -223,7 +250,7 @@ func (db *DB) ListRequestsByStatus(status RequestStatus, projectPath string) ([]
 			command_display_redacted, command_contains_sensitive,
 			risk_tier, requestor_session_id, requestor_agent, requestor_model,
 			justification_reason, justification_expected_effect, justification_goal, justification_safety_argument,
-			dry_run_command, dry_run_output, attachments_json,
+			dry_run_command, dry_run_output, attachments_json, environment_json, sandbox_json, plan_json, window_json, policy_json, auto_answers_json,
 			status, min_approvals, require_different_model,
 			execution_log_path, execution_exit_code, execution_duration_ms,
 			execution_executed_at, execution_executed_by_session_id, execution_executed_by_agent, execution_executed_by_model,
@@ -248,7 +275,7 @@ func (db *DB) ListAllRequests(projectPath string) ([]*Request, error) {
 			command_display_redacted, command_contains_sensitive,
 			risk_tier, requestor_session_id, requestor_agent, requestor_model,
 			justification_reason, justification_expected_effect, justification_goal, justification_safety_argument,
-			dry_run_command, dry_run_output, attachments_json,
+			dry_run_command, dry_run_output, attachments_json, environment_json, sandbox_json, plan_json, window_json, policy_json, auto_answers_json,
 			status, min_approvals, require_different_model,
 			execution_log_path, execution_exit_code, execution_duration_ms,
 			execution_executed_at, execution_executed_by_session_id, execution_executed_by_agent, execution_executed_by_model,
@@ -265,6 +292,37 @@ func (db *DB) ListAllRequests(projectPath string) ([]*Request, error) {
 	return scanRequests(rows)
 }
 
+// ListRequestsByCommandHash returns earlier requests for the same command
+// (same raw text, cwd, argv and shell flag), newest first. excludeID is left
+// out; limit <= 0 means no limit.
+func (db *DB) ListRequestsByCommandHash(hash, excludeID string, limit int) ([]*Request, error) {
+	if limit <= 0 {
+		limit = -1
+	}
+	rows, err := db.Query(`
+		SELECT id, project_path,
+			command_raw, command_argv_json, command_cwd, command_shell, command_hash,
+			command_display_redacted, command_contains_sensitive,
+			risk_tier, requestor_session_id, requestor_agent, requestor_model,
+			justification_reason, justification_expected_effect, justification_goal, justification_safety_argument,
+			dry_run_command, dry_run_output, attachments_json, environment_json, sandbox_json, plan_json, window_json, policy_json, auto_answers_json,
+			status, min_approvals, require_different_model,
+			execution_log_path, execution_exit_code, execution_duration_ms,
+			execution_executed_at, execution_executed_by_session_id, execution_executed_by_agent, execution_executed_by_model,
+			rollback_path, rollback_rolled_back_at,
+			created_at, resolved_at, expires_at, approval_expires_at
+		FROM requests WHERE command_hash = ? AND id != ?
+		ORDER BY created_at DESC
+		LIMIT ?
+	`, hash, excludeID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("querying requests by command hash: %w", err)
+	}
+	defer rows.Close()
+
+	return scanRequests(rows)
+}
+
 // UpdateRequestStatusTx updates a request's status within a transaction.
 func (db *DB) UpdateRequestStatusTx(tx *sql.Tx, id string, status RequestStatus, currentStatus RequestStatus) error {
 	// Validate transition using state machine
@@ -489,7 +547,7 @@ func (db *DB) SearchRequests(query string) ([]*Request, error) {
 			r.command_display_redacted, r.command_contains_sensitive,
 			r.risk_tier, r.requestor_session_id, r.requestor_agent, r.requestor_model,
 			r.justification_reason, r.justification_expected_effect, r.justification_goal, r.justification_safety_argument,
-			r.dry_run_command, r.dry_run_output, r.attachments_json,
+			r.dry_run_command, r.dry_run_output, r.attachments_json, r.environment_json, r.sandbox_json, r.plan_json, r.window_json, r.policy_json, r.auto_answers_json,
 			r.status, r.min_approvals, r.require_different_model,
 			r.execution_log_path, r.execution_exit_code, r.execution_duration_ms,
 			r.execution_executed_at, r.execution_executed_by_session_id, r.execution_executed_by_agent, r.execution_executed_by_model,
@@ -518,7 +576,7 @@ func (db *DB) FindExpiredRequests() ([]*Request, error) {
 			command_display_redacted, command_contains_sensitive,
 			risk_tier, requestor_session_id, requestor_agent, requestor_model,
 			justification_reason, justification_expected_effect, justification_goal, justification_safety_argument,
-			dry_run_command, dry_run_output, attachments_json,
+			dry_run_command, dry_run_output, attachments_json, environment_json, sandbox_json, plan_json, window_json, policy_json, auto_answers_json,
 			status, min_approvals, require_different_model,
 			execution_log_path, execution_exit_code, execution_duration_ms,
 			execution_executed_at, execution_executed_by_session_id, execution_executed_by_agent, execution_executed_by_model,
@@ -553,7 +611,9 @@ func ComputeCommandHash(cmd CommandSpec) string {
 func scanRequest(row *sql.Row) (*Request, error) {
 	r := &Request{}
 	var (
-		argvJSON, attachmentsJSON                           sql.NullString
+		argvJSON, attachmentsJSON, environmentJSON          sql.NullString
+		sandboxJSON, planJSON, windowJSON, policyJSON       sql.NullString
+		autoAnswersJSON                                     sql.NullString
 		cmdDisplayRedacted                                  sql.NullString
 		justExpEffect, justGoal, justSafety                 sql.NullString
 		dryRunCmd, dryRunOutput                             sql.NullString
@@ -572,7 +632,7 @@ func scanRequest(row *sql.Row) (*Request, error) {
 		&cmdDisplayRedacted, &containsSensitive,
 		&riskTier, &r.RequestorSessionID, &r.RequestorAgent, &r.RequestorModel,
 		&r.Justification.Reason, &justExpEffect, &justGoal, &justSafety,
-		&dryRunCmd, &dryRunOutput, &attachmentsJSON,
+		&dryRunCmd, &dryRunOutput, &attachmentsJSON, &environmentJSON, &sandboxJSON, &planJSON, &windowJSON, &policyJSON, &autoAnswersJSON,
 		&status, &minApprovals, &requireDiffModel,
 		&execLogPath, &execExitCode, &execDurationMs,
 		&execAt, &execBySessionID, &execByAgent, &execByModel,
@@ -603,6 +663,12 @@ func scanRequest(row *sql.Row) (*Request, error) {
 	if attachmentsJSON.Valid && attachmentsJSON.String != "null" {
 		json.Unmarshal([]byte(attachmentsJSON.String), &r.Attachments)
 	}
+	r.Environment = decodeEnvironment(environmentJSON)
+	r.Sandbox = decodeSandbox(sandboxJSON)
+	r.Plan = decodePlan(planJSON)
+	r.Window = decodeWindow(windowJSON)
+	r.Policy = decodePolicy(policyJSON)
+	r.AutoAnswers = decodeAutoAnswers(autoAnswersJSON)
 	if justExpEffect.Valid {
 		r.Justification.ExpectedEffect = justExpEffect.String
 	}
@@ -686,7 +752,9 @@ func scanRequests(rows *sql.Rows) ([]*Request, error) {
 	for rows.Next() {
 		r := &Request{}
 		var (
-			argvJSON, attachmentsJSON                           sql.NullString
+			argvJSON, attachmentsJSON, environmentJSON          sql.NullString
+			sandboxJSON, planJSON, windowJSON, policyJSON       sql.NullString
+			autoAnswersJSON                                     sql.NullString
 			cmdDisplayRedacted                                  sql.NullString
 			justExpEffect, justGoal, justSafety                 sql.NullString
 			dryRunCmd, dryRunOutput                             sql.NullString
@@ -705,7 +773,7 @@ func scanRequests(rows *sql.Rows) ([]*Request, error) {
 			&cmdDisplayRedacted, &containsSensitive,
 			&riskTier, &r.RequestorSessionID, &r.RequestorAgent, &r.RequestorModel,
 			&r.Justification.Reason, &justExpEffect, &justGoal, &justSafety,
-			&dryRunCmd, &dryRunOutput, &attachmentsJSON,
+			&dryRunCmd, &dryRunOutput, &attachmentsJSON, &environmentJSON, &sandboxJSON, &planJSON, &windowJSON, &policyJSON, &autoAnswersJSON,
 			&status, &minApprovals, &requireDiffModel,
 			&execLogPath, &execExitCode, &execDurationMs,
 			&execAt, &execBySessionID, &execByAgent, &execByModel,
@@ -733,6 +801,12 @@ func scanRequests(rows *sql.Rows) ([]*Request, error) {
 		if attachmentsJSON.Valid && attachmentsJSON.String != "null" {
 			json.Unmarshal([]byte(attachmentsJSON.String), &r.Attachments)
 		}
+		r.Environment = decodeEnvironment(environmentJSON)
+		r.Sandbox = decodeSandbox(sandboxJSON)
+		r.Plan = decodePlan(planJSON)
+		r.Window = decodeWindow(windowJSON)
+		r.Policy = decodePolicy(policyJSON)
+		r.AutoAnswers = decodeAutoAnswers(autoAnswersJSON)
 		if justExpEffect.Valid {
 			r.Justification.ExpectedEffect = justExpEffect.String
 		}
@@ -846,3 +920,120 @@ func nullDryRunOutput(dr *DryRunResult) sql.NullString {
 	}
 	return nullString(dr.Output)
 }
+
+// encodeEnvironment stores an empty fingerprint as {}: it differs from none,
+// recording that the environment was bound while nothing was set.
+func encodeEnvironment(f EnvironmentFingerprint) (sql.NullString, error) {
+	if f == nil {
+		return sql.NullString{}, nil
+	}
+	data, err := json.Marshal(f)
+	if err != nil {
+		return sql.NullString{}, fmt.Errorf("encoding environment fingerprint: %w", err)
+	}
+	return sql.NullString{String: string(data), Valid: true}, nil
+}
+
+func decodeEnvironment(v sql.NullString) EnvironmentFingerprint {
+	if !v.Valid || v.String == "" || v.String == "null" {
+		return nil
+	}
+	var f EnvironmentFingerprint
+	if err := json.Unmarshal([]byte(v.String), &f); err != nil {
+		return nil
+	}
+	return f
+}
+
+func encodeSandbox(s *SandboxSpec) (sql.NullString, error) {
+	if s == nil {
+		return sql.NullString{}, nil
+	}
+	data, err := json.Marshal(s)
+	if err != nil {
+		return sql.NullString{}, fmt.Errorf("encoding sandbox spec: %w", err)
+	}
+	return sql.NullString{String: string(data), Valid: true}, nil
+}
+
+func encodeWindow(w *ExecutionWindow) (sql.NullString, error) {
+	if w == nil {
+		return sql.NullString{}, nil
+	}
+	data, err := json.Marshal(w)
+	if err != nil {
+		return sql.NullString{}, fmt.Errorf("encoding execution window: %w", err)
+	}
+	return sql.NullString{String: string(data), Valid: true}, nil
+}
+
+func decodeWindow(v sql.NullString) *ExecutionWindow {
+	if !v.Valid || v.String == "" || v.String == "null" {
+		return nil
+	}
+	var w ExecutionWindow
+	if err := json.Unmarshal([]byte(v.String), &w); err != nil {
+		// An unreadable window never opens.
+		past := time.Unix(0, 0).UTC()
+		return &ExecutionWindow{NotAfter: &past}
+	}
+	return &w
+}
+
+func encodePolicy(d *PolicyDecision) (sql.NullString, error) {
+	if d == nil {
+		return sql.NullString{}, nil
+	}
+	data, err := json.Marshal(d)
+	if err != nil {
+		return sql.NullString{}, fmt.Errorf("encoding policy decision: %w", err)
+	}
+	return sql.NullString{String: string(data), Valid: true}, nil
+}
+
+func decodePolicy(v sql.NullString) *PolicyDecision {
+	if !v.Valid || v.String == "" || v.String == "null" {
+		return nil
+	}
+	var d PolicyDecision
+	if err := json.Unmarshal([]byte(v.String), &d); err != nil {
+		// An unreadable decision allows no reviewer and no executor.
+		return &PolicyDecision{unreadable: true}
+	}
+	return &d
+}
+
+func encodeAutoAnswers(answers []AutoAnswer) (sql.NullString, error) {
+	if len(answers) == 0 {
+		return sql.NullString{}, nil
+	}
+	data, err := json.Marshal(answers)
+	if err != nil {
+		return sql.NullString{}, fmt.Errorf("encoding auto-answers: %w", err)
+	}
+	return sql.NullString{String: string(data), Valid: true}, nil
+}
+
+func decodeAutoAnswers(v sql.NullString) []AutoAnswer {
+	if !v.Valid || v.String == "" || v.String == "null" {
+		return nil
+	}
+	var answers []AutoAnswer
+	if err := json.Unmarshal([]byte(v.String), &answers); err != nil {
+		// Unreadable answers are not typed into anything.
+		return nil
+	}
+	return answers
+}
+
+func decodeSandbox(v sql.NullString) *SandboxSpec {
+	if !v.Valid || v.String == "" || v.String == "null" {
+		return nil
+	}
+	var s SandboxSpec
+	if err := json.Unmarshal([]byte(v.String), &s); err != nil {
+		// An unreadable spec still demands a sandbox.
+		return &SandboxSpec{}
+	}
+	return &s
+}
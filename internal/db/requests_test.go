@@ -90,6 +90,86 @@ func TestGetRequest(t *testing.T) {
 	}
 }
 
+func TestRequestEnvironmentRoundTrip(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	sess, _ := createTestRequest(t, db)
+	r := &Request{
+		ProjectPath:        "/test/project",
+		RequestorSessionID: sess.ID,
+		RequestorAgent:     sess.AgentName,
+		RequestorModel:     sess.Model,
+		RiskTier:           RiskTierDangerous,
+		MinApprovals:       1,
+		Command:            CommandSpec{Raw: "kubectl delete deployment api", Cwd: "/tmp"},
+		Justification:      Justification{Reason: "cleanup"},
+		Environment:        EnvironmentFingerprint{"kube.context": "staging", "aws.profile": "dev"},
+	}
+	if err := db.CreateRequest(r); err != nil {
+		t.Fatalf("CreateRequest failed: %v", err)
+	}
+
+	got, err := db.GetRequest(r.ID)
+	if err != nil {
+		t.Fatalf("GetRequest failed: %v", err)
+	}
+	if diff := r.Environment.Diff(got.Environment); len(diff) != 0 {
+		t.Errorf("environment differs after round trip in %v", diff)
+	}
+	pending, err := db.ListPendingRequests("/test/project")
+	if err != nil {
+		t.Fatalf("ListPendingRequests failed: %v", err)
+	}
+	for _, p := range pending {
+		if p.ID == r.ID && p.Environment["kube.context"] != "staging" {
+			t.Errorf("listed environment = %v", p.Environment)
+		}
+	}
+
+	diff := got.Environment.Diff(EnvironmentFingerprint{"kube.context": "prod", "git.branch": "main"})
+	if want := []string{"aws.profile", "git.branch", "kube.context"}; len(diff) != 3 || diff[0] != want[0] || diff[1] != want[1] || diff[2] != want[2] {
+		t.Errorf("Diff = %v, want %v", diff, want)
+	}
+}
+
+func TestRequestWindowRoundTrip(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	sess, _ := createTestRequest(t, db)
+	notBefore := time.Now().UTC().Add(time.Hour).Truncate(time.Second)
+	notAfter := notBefore.Add(2 * time.Hour)
+	r := &Request{
+		ProjectPath:        "/test/project",
+		RequestorSessionID: sess.ID,
+		RequestorAgent:     sess.AgentName,
+		RequestorModel:     sess.Model,
+		RiskTier:           RiskTierCritical,
+		MinApprovals:       2,
+		Command:            CommandSpec{Raw: "helm uninstall db", Cwd: "/tmp"},
+		Justification:      Justification{Reason: "maintenance"},
+		Window:             &ExecutionWindow{NotBefore: &notBefore, NotAfter: &notAfter, Recurring: "nightly"},
+	}
+	if err := db.CreateRequest(r); err != nil {
+		t.Fatalf("CreateRequest failed: %v", err)
+	}
+
+	got, err := db.GetRequest(r.ID)
+	if err != nil {
+		t.Fatalf("GetRequest failed: %v", err)
+	}
+	w := got.Window
+	if w == nil || w.Recurring != "nightly" || w.NotBefore == nil || !w.NotBefore.Equal(notBefore) || w.NotAfter == nil || !w.NotAfter.Equal(notAfter) {
+		t.Errorf("Window = %+v", w)
+	}
+
+	_, plain := createTestRequest(t, db)
+	if got, _ := db.GetRequest(plain.ID); got.Window != nil {
+		t.Errorf("request without a window has %+v", got.Window)
+	}
+}
+
 func TestGetRequestNotFound(t *testing.T) {
 	db := setupTestDB(t)
 	defer db.Close()
@@ -402,6 +482,58 @@ func TestListAllRequests(t *testing.T) {
 	}
 }
 
+func TestListRequestsByCommandHash(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	sess := &Session{AgentName: "Hash1", Program: "codex-cli", Model: "gpt-5", ProjectPath: "/test/project"}
+	if err := db.CreateSession(sess); err != nil {
+		t.Fatalf("CreateSession failed: %v", err)
+	}
+	makeReq := func(raw string) *Request {
+		r := &Request{
+			ProjectPath:        sess.ProjectPath,
+			RequestorSessionID: sess.ID,
+			RequestorAgent:     sess.AgentName,
+			RequestorModel:     sess.Model,
+			RiskTier:           RiskTierDangerous,
+			MinApprovals:       1,
+			Command:            CommandSpec{Raw: raw, Cwd: sess.ProjectPath},
+			Justification:      Justification{Reason: "hash"},
+		}
+		if err := db.CreateRequest(r); err != nil {
+			t.Fatalf("CreateRequest failed: %v", err)
+		}
+		return r
+	}
+
+	r1 := makeReq("rm -rf ./build")
+	r2 := makeReq("rm -rf ./build")
+	r3 := makeReq("rm -rf ./build")
+	_ = makeReq("rm -rf ./dist")
+
+	similar, err := db.ListRequestsByCommandHash(r3.Command.Hash, r3.ID, 0)
+	if err != nil {
+		t.Fatalf("ListRequestsByCommandHash failed: %v", err)
+	}
+	if len(similar) != 2 {
+		t.Fatalf("expected 2 requests, got %d", len(similar))
+	}
+	for _, r := range similar {
+		if r.ID != r1.ID && r.ID != r2.ID {
+			t.Errorf("unexpected request %s", r.ID)
+		}
+	}
+
+	limited, err := db.ListRequestsByCommandHash(r3.Command.Hash, r3.ID, 1)
+	if err != nil {
+		t.Fatalf("ListRequestsByCommandHash with limit failed: %v", err)
+	}
+	if len(limited) != 1 {
+		t.Errorf("expected 1 request with limit, got %d", len(limited))
+	}
+}
+
 func TestListPendingRequestsAllProjects(t *testing.T) {
 	db := setupTestDB(t)
 	defer db.Close()
@@ -41,12 +41,12 @@ func (db *DB) CreateReviewTx(tx *sql.Tx, r *Review) error {
 		INSERT INTO reviews (
 			id, request_id, reviewer_session_id, reviewer_agent, reviewer_model,
 			decision, signature, signature_timestamp,
-			responses_json, comments, created_at
-		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+			responses_json, comments, require_sandbox, created_at
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 	`,
 		r.ID, r.RequestID, r.ReviewerSessionID, r.ReviewerAgent, r.ReviewerModel,
 		string(r.Decision), r.Signature, r.SignatureTimestamp.Format(time.RFC3339),
-		nullString(string(respJSON)), nullString(r.Comments), r.CreatedAt.Format(time.RFC3339),
+		nullString(string(respJSON)), nullString(r.Comments), boolToInt(r.RequireSandbox), r.CreatedAt.Format(time.RFC3339),
 	)
 	if err != nil {
 		if isUniqueConstraintError(err) {
@@ -83,12 +83,12 @@ func (db *DB) CreateReview(r *Review) error {
 		INSERT INTO reviews (
 			id, request_id, reviewer_session_id, reviewer_agent, reviewer_model,
 			decision, signature, signature_timestamp,
-			responses_json, comments, created_at
-		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+			responses_json, comments, require_sandbox, created_at
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 	`,
 		r.ID, r.RequestID, r.ReviewerSessionID, r.ReviewerAgent, r.ReviewerModel,
 		string(r.Decision), r.Signature, r.SignatureTimestamp.Format(time.RFC3339),
-		nullString(string(respJSON)), nullString(r.Comments), r.CreatedAt.Format(time.RFC3339),
+		nullString(string(respJSON)), nullString(r.Comments), boolToInt(r.RequireSandbox), r.CreatedAt.Format(time.RFC3339),
 	)
 	if err != nil {
 		if isUniqueConstraintError(err) {
@@ -103,7 +103,7 @@ func (db *DB) CreateReview(r *Review) error {
 func (db *DB) GetReview(id string) (*Review, error) {
 	row := db.QueryRow(`
 		SELECT id, request_id, reviewer_session_id, reviewer_agent, reviewer_model,
-		       decision, signature, signature_timestamp, responses_json, comments, created_at
+		       decision, signature, signature_timestamp, responses_json, comments, require_sandbox, created_at
 		FROM reviews WHERE id = ?
 	`, id)
 	return scanReviewRow(row)
@@ -113,7 +113,7 @@ func (db *DB) GetReview(id string) (*Review, error) {
 func (db *DB) ListReviewsForRequest(requestID string) ([]*Review, error) {
 	rows, err := db.Query(`
 		SELECT id, request_id, reviewer_session_id, reviewer_agent, reviewer_model,
-		       decision, signature, signature_timestamp, responses_json, comments, created_at
+		       decision, signature, signature_timestamp, responses_json, comments, require_sandbox, created_at
 		FROM reviews WHERE request_id = ?
 		ORDER BY created_at ASC
 	`, requestID)
@@ -197,9 +197,10 @@ func scanReviewRow(row *sql.Row) (*Review, error) {
 	var sigTs, created string
 	var responsesJSON sql.NullString
 	var comments sql.NullString
+	var requireSandbox int
 
 	err := row.Scan(&r.ID, &r.RequestID, &r.ReviewerSessionID, &r.ReviewerAgent, &r.ReviewerModel,
-		&decision, &r.Signature, &sigTs, &responsesJSON, &comments, &created)
+		&decision, &r.Signature, &sigTs, &responsesJSON, &comments, &requireSandbox, &created)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, ErrReviewNotFound
@@ -208,6 +209,7 @@ func scanReviewRow(row *sql.Row) (*Review, error) {
 	}
 
 	r.Decision = Decision(decision)
+	r.RequireSandbox = requireSandbox == 1
 	r.SignatureTimestamp, _ = time.Parse(time.RFC3339, sigTs)
 	r.CreatedAt, _ = time.Parse(time.RFC3339, created)
 
@@ -229,13 +231,15 @@ func scanReviewList(rows *sql.Rows) ([]*Review, error) {
 		var sigTs, created string
 		var responsesJSON sql.NullString
 		var comments sql.NullString
+		var requireSandbox int
 
 		if err := rows.Scan(&r.ID, &r.RequestID, &r.ReviewerSessionID, &r.ReviewerAgent, &r.ReviewerModel,
-			&decision, &r.Signature, &sigTs, &responsesJSON, &comments, &created); err != nil {
+			&decision, &r.Signature, &sigTs, &responsesJSON, &comments, &requireSandbox, &created); err != nil {
 			return nil, fmt.Errorf("scanning reviews: %w", err)
 		}
 
 		r.Decision = Decision(decision)
+		r.RequireSandbox = requireSandbox == 1
 		r.SignatureTimestamp, _ = time.Parse(time.RFC3339, sigTs)
 		r.CreatedAt, _ = time.Parse(time.RFC3339, created)
 		if responsesJSON.Valid {
@@ -0,0 +1,199 @@
+package db
+
+import (
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// RetiredSessionKey is a session key replaced by a rotation. It stays
+// usable for verifying signatures made while it was current, never for
+// signing new reviews.
+type RetiredSessionKey struct {
+	ID         int64     `json:"id"`
+	SessionID  string    `json:"session_id"`
+	SessionKey string    `json:"-"`
+	CreatedAt  time.Time `json:"created_at"`
+	RetiredAt  time.Time `json:"retired_at"`
+}
+
+// newSessionKey generates a 32 byte (256 bit) HMAC-SHA256 key.
+func newSessionKey() (string, error) {
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		return "", fmt.Errorf("generating session key: %w", err)
+	}
+	return hex.EncodeToString(key), nil
+}
+
+// RotateSessionKey replaces the key of an active session and retires the
+// old one. The session's reviews signed with the old key are linked to its
+// retired entry. Returns the new key.
+func (db *DB) RotateSessionKey(id string) (string, error) {
+	newKey, err := newSessionKey()
+	if err != nil {
+		return "", err
+	}
+	now := time.Now().UTC().Format(time.RFC3339)
+
+	err = db.Transaction(func(tx *sql.Tx) error {
+		var oldKey, startedAt string
+		err := tx.QueryRow(`
+			SELECT session_key, started_at FROM sessions WHERE id = ? AND ended_at IS NULL
+		`, id).Scan(&oldKey, &startedAt)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return ErrSessionNotFound
+			}
+			return fmt.Errorf("reading session key: %w", err)
+		}
+
+		// The old key has been current since the previous rotation, or
+		// since the session started.
+		var lastRetired sql.NullString
+		if err := tx.QueryRow(`
+			SELECT MAX(retired_at) FROM session_keys WHERE session_id = ?
+		`, id).Scan(&lastRetired); err != nil {
+			return fmt.Errorf("reading previous rotation: %w", err)
+		}
+		createdAt := startedAt
+		if lastRetired.Valid {
+			createdAt = lastRetired.String
+		}
+
+		result, err := tx.Exec(`
+			INSERT INTO session_keys (session_id, session_key, created_at, retired_at) VALUES (?, ?, ?, ?)
+		`, id, oldKey, createdAt, now)
+		if err != nil {
+			return fmt.Errorf("retiring session key: %w", err)
+		}
+		keyID, err := result.LastInsertId()
+		if err != nil {
+			return fmt.Errorf("retiring session key: %w", err)
+		}
+		if _, err := tx.Exec(`
+			UPDATE reviews SET signing_key_id = ? WHERE reviewer_session_id = ? AND signing_key_id IS NULL
+		`, keyID, id); err != nil {
+			return fmt.Errorf("linking reviews to retired key: %w", err)
+		}
+		if _, err := tx.Exec(`UPDATE sessions SET session_key = ? WHERE id = ?`, newKey, id); err != nil {
+			return fmt.Errorf("updating session key: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	return newKey, nil
+}
+
+// ListRetiredSessionKeys returns the retired keys of a session, oldest first.
+func (db *DB) ListRetiredSessionKeys(sessionID string) ([]RetiredSessionKey, error) {
+	rows, err := db.Query(`
+		SELECT id, session_id, session_key, created_at, retired_at
+		FROM session_keys
+		WHERE session_id = ?
+		ORDER BY id ASC
+	`, sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("listing retired session keys: %w", err)
+	}
+	defer rows.Close()
+
+	var keys []RetiredSessionKey
+	for rows.Next() {
+		var k RetiredSessionKey
+		var createdAt, retiredAt string
+		if err := rows.Scan(&k.ID, &k.SessionID, &k.SessionKey, &createdAt, &retiredAt); err != nil {
+			return nil, fmt.Errorf("scanning retired session key: %w", err)
+		}
+		k.CreatedAt, _ = time.Parse(time.RFC3339, createdAt)
+		k.RetiredAt, _ = time.Parse(time.RFC3339, retiredAt)
+		keys = append(keys, k)
+	}
+	return keys, rows.Err()
+}
+
+// VerifyStoredReviewSignature checks a stored review's signature against
+// the key it was signed with: the retired key a rotation linked it to, or
+// the reviewer session's current key. Rotations therefore do not invalidate
+// earlier reviews, and a retired key never verifies later ones.
+func (db *DB) VerifyStoredReviewSignature(r *Review) (bool, error) {
+	var keyID sql.NullInt64
+	err := db.QueryRow(`SELECT signing_key_id FROM reviews WHERE id = ?`, r.ID).Scan(&keyID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return false, ErrReviewNotFound
+		}
+		return false, fmt.Errorf("reading review signing key: %w", err)
+	}
+
+	var key string
+	if keyID.Valid {
+		err = db.QueryRow(`
+			SELECT session_key FROM session_keys WHERE id = ? AND session_id = ?
+		`, keyID.Int64, r.ReviewerSessionID).Scan(&key)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return false, nil
+			}
+			return false, fmt.Errorf("reading retired session key: %w", err)
+		}
+	} else {
+		session, err := db.GetSession(r.ReviewerSessionID)
+		if err != nil {
+			return false, err
+		}
+		key = session.SessionKey
+	}
+	return VerifyReviewSignature(key, r.RequestID, r.Decision, r.SignatureTimestamp, r.Signature), nil
+}
+
+// UpdateSessionAttestation replaces the attestation of an active session.
+func (db *DB) UpdateSessionAttestation(id string, a *SessionAttestation) error {
+	attestation, err := encodeAttestation(a)
+	if err != nil {
+		return err
+	}
+	result, err := db.Exec(`
+		UPDATE sessions SET attestation_json = ? WHERE id = ? AND ended_at IS NULL
+	`, attestation, id)
+	if err != nil {
+		return fmt.Errorf("updating session attestation: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("getting rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return ErrSessionNotFound
+	}
+	return nil
+}
+
+func encodeAttestation(a *SessionAttestation) (sql.NullString, error) {
+	if a == nil {
+		return sql.NullString{}, nil
+	}
+	data, err := json.Marshal(a)
+	if err != nil {
+		return sql.NullString{}, fmt.Errorf("encoding session attestation: %w", err)
+	}
+	return sql.NullString{String: string(data), Valid: true}, nil
+}
+
+func decodeAttestation(v sql.NullString) (*SessionAttestation, error) {
+	if !v.Valid || v.String == "" {
+		return nil, nil
+	}
+	var a SessionAttestation
+	if err := json.Unmarshal([]byte(v.String), &a); err != nil {
+		return nil, fmt.Errorf("parsing session attestation: %w", err)
+	}
+	return &a, nil
+}
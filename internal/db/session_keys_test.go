@@ -0,0 +1,135 @@
+package db
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRotateSessionKey(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	s := &Session{AgentName: "BlueLake", Program: "claude-code", Model: "opus", ProjectPath: "/test/project"}
+	if err := db.CreateSession(s); err != nil {
+		t.Fatalf("CreateSession failed: %v", err)
+	}
+	signedAt := time.Now().UTC().Truncate(time.Second)
+	review := func(key string) *Review {
+		_, req := createTestRequest(t, db)
+		r := &Review{
+			RequestID:          req.ID,
+			ReviewerSessionID:  s.ID,
+			ReviewerAgent:      s.AgentName,
+			ReviewerModel:      s.Model,
+			Decision:           DecisionApprove,
+			SignatureTimestamp: signedAt,
+			Signature:          ComputeReviewSignature(key, req.ID, DecisionApprove, signedAt),
+		}
+		if err := db.CreateReview(r); err != nil {
+			t.Fatalf("CreateReview: %v", err)
+		}
+		return r
+	}
+	verify := func(r *Review) bool {
+		t.Helper()
+		ok, err := db.VerifyStoredReviewSignature(r)
+		if err != nil {
+			t.Fatalf("VerifyStoredReviewSignature: %v", err)
+		}
+		return ok
+	}
+
+	oldKey := s.SessionKey
+	before := review(oldKey)
+
+	newKey, err := db.RotateSessionKey(s.ID)
+	if err != nil {
+		t.Fatalf("RotateSessionKey: %v", err)
+	}
+	if newKey == oldKey || len(newKey) != 64 {
+		t.Fatalf("new key = %q", newKey)
+	}
+	got, err := db.GetSession(s.ID)
+	if err != nil {
+		t.Fatalf("GetSession: %v", err)
+	}
+	if got.SessionKey != newKey {
+		t.Error("session key not replaced")
+	}
+
+	retired, err := db.ListRetiredSessionKeys(s.ID)
+	if err != nil {
+		t.Fatalf("ListRetiredSessionKeys: %v", err)
+	}
+	if len(retired) != 1 || retired[0].SessionKey != oldKey {
+		t.Fatalf("retired keys = %+v", retired)
+	}
+
+	if !verify(before) {
+		t.Error("review signed before rotation should verify")
+	}
+
+	// Signed in the same second as the rotation, but stored after it: the
+	// retired key must not verify it.
+	if verify(review(oldKey)) {
+		t.Error("retired key must not verify reviews stored after rotation")
+	}
+	after := review(newKey)
+	if !verify(after) {
+		t.Error("current key should verify")
+	}
+
+	// A second rotation keeps both earlier generations verifiable.
+	if _, err := db.RotateSessionKey(s.ID); err != nil {
+		t.Fatalf("second RotateSessionKey: %v", err)
+	}
+	if !verify(before) || !verify(after) {
+		t.Error("reviews signed with retired keys should still verify")
+	}
+
+	if _, err := db.VerifyStoredReviewSignature(&Review{ID: "missing"}); err != ErrReviewNotFound {
+		t.Errorf("expected ErrReviewNotFound, got %v", err)
+	}
+	if _, err := db.RotateSessionKey("missing"); err != ErrSessionNotFound {
+		t.Errorf("expected ErrSessionNotFound, got %v", err)
+	}
+}
+
+func TestSessionAttestationRoundTrip(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	s := &Session{
+		AgentName:   "BlueLake",
+		ProjectPath: "/test/project",
+		Attestation: &SessionAttestation{
+			CollectedAt: time.Now().UTC().Truncate(time.Second),
+			ProcessTree: []ProcessInfo{{PID: 42, Name: "bash"}, {PID: 7, Name: "claude", StartTime: 99}},
+			Anchor:      &ProcessInfo{PID: 7, Name: "claude", StartTime: 99},
+			TTY:         "/dev/pts/3",
+		},
+	}
+	if err := db.CreateSession(s); err != nil {
+		t.Fatalf("CreateSession failed: %v", err)
+	}
+
+	got, err := db.GetSession(s.ID)
+	if err != nil {
+		t.Fatalf("GetSession: %v", err)
+	}
+	if got.Attestation == nil || got.Attestation.Anchor.Name != "claude" || len(got.Attestation.ProcessTree) != 2 {
+		t.Fatalf("attestation = %+v", got.Attestation)
+	}
+
+	got.Attestation.ContextChanges = []ContextChange{{At: time.Now().UTC(), Fields: []string{"tty"}}}
+	if err := db.UpdateSessionAttestation(s.ID, got.Attestation); err != nil {
+		t.Fatalf("UpdateSessionAttestation: %v", err)
+	}
+	active, err := db.ListActiveSessions("/test/project")
+	if err != nil {
+		t.Fatalf("ListActiveSessions: %v", err)
+	}
+	if len(active) != 1 || len(active[0].Attestation.ContextChanges) != 1 {
+		t.Errorf("listed attestation = %+v", active[0].Attestation)
+	}
+}
@@ -2,9 +2,7 @@
 package db
 
 import (
-	"crypto/rand"
 	"database/sql"
-	"encoding/hex"
 	"errors"
 	"fmt"
 	"time"
@@ -37,11 +35,11 @@ func (db *DB) CreateSession(s *Session) error {
 
 	// Generate session key (32 bytes = 256 bits for HMAC-SHA256)
 	if s.SessionKey == "" {
-		key := make([]byte, 32)
-		if _, err := rand.Read(key); err != nil {
-			return fmt.Errorf("generating session key: %w", err)
+		key, err := newSessionKey()
+		if err != nil {
+			return err
 		}
-		s.SessionKey = hex.EncodeToString(key)
+		s.SessionKey = key
 	}
 
 	// Set timestamps
@@ -50,11 +48,16 @@ func (db *DB) CreateSession(s *Session) error {
 	s.LastActiveAt = now
 	s.EndedAt = nil
 
+	attestation, err := encodeAttestation(s.Attestation)
+	if err != nil {
+		return err
+	}
+
 	// Insert into database
-	_, err := db.Exec(`
-		INSERT INTO sessions (id, agent_name, program, model, project_path, session_key, started_at, last_active_at, ended_at)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?, NULL)
-	`, s.ID, s.AgentName, s.Program, s.Model, s.ProjectPath, s.SessionKey, s.StartedAt.Format(time.RFC3339), s.LastActiveAt.Format(time.RFC3339))
+	_, err = db.Exec(`
+		INSERT INTO sessions (id, agent_name, program, model, project_path, session_key, started_at, last_active_at, ended_at, attestation_json)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, NULL, ?)
+	`, s.ID, s.AgentName, s.Program, s.Model, s.ProjectPath, s.SessionKey, s.StartedAt.Format(time.RFC3339), s.LastActiveAt.Format(time.RFC3339), attestation)
 
 	if err != nil {
 		// Check for unique constraint violation (active session already exists)
@@ -90,7 +93,7 @@ func (db *DB) UpdateSessionModel(id, newModel string) error {
 // GetSession retrieves a session by ID.
 func (db *DB) GetSession(id string) (*Session, error) {
 	row := db.QueryRow(`
-		SELECT id, agent_name, program, model, project_path, session_key, started_at, last_active_at, ended_at
+		SELECT id, agent_name, program, model, project_path, session_key, started_at, last_active_at, ended_at, attestation_json
 		FROM sessions WHERE id = ?
 	`, id)
 
@@ -101,7 +104,7 @@ func (db *DB) GetSession(id string) (*Session, error) {
 // Returns ErrSessionNotFound if no active session exists.
 func (db *DB) GetActiveSession(agentName, projectPath string) (*Session, error) {
 	row := db.QueryRow(`
-		SELECT id, agent_name, program, model, project_path, session_key, started_at, last_active_at, ended_at
+		SELECT id, agent_name, program, model, project_path, session_key, started_at, last_active_at, ended_at, attestation_json
 		FROM sessions
 		WHERE agent_name = ? AND project_path = ? AND ended_at IS NULL
 	`, agentName, projectPath)
@@ -109,10 +112,22 @@ func (db *DB) GetActiveSession(agentName, projectPath string) (*Session, error)
 	return scanSession(row)
 }
 
+// GetActiveSessionByKey retrieves the active session holding a session key.
+// Returns ErrSessionNotFound if no active session uses the key.
+func (db *DB) GetActiveSessionByKey(sessionKey string) (*Session, error) {
+	row := db.QueryRow(`
+		SELECT id, agent_name, program, model, project_path, session_key, started_at, last_active_at, ended_at, attestation_json
+		FROM sessions
+		WHERE session_key = ? AND ended_at IS NULL
+	`, sessionKey)
+
+	return scanSession(row)
+}
+
 // ListActiveSessions returns all active sessions for a project.
 func (db *DB) ListActiveSessions(projectPath string) ([]*Session, error) {
 	rows, err := db.Query(`
-		SELECT id, agent_name, program, model, project_path, session_key, started_at, last_active_at, ended_at
+		SELECT id, agent_name, program, model, project_path, session_key, started_at, last_active_at, ended_at, attestation_json
 		FROM sessions
 		WHERE project_path = ? AND ended_at IS NULL
 		ORDER BY last_active_at DESC
@@ -128,7 +143,7 @@ func (db *DB) ListActiveSessions(projectPath string) ([]*Session, error) {
 // ListAllActiveSessions returns all active sessions across all projects.
 func (db *DB) ListAllActiveSessions() ([]*Session, error) {
 	rows, err := db.Query(`
-		SELECT id, agent_name, program, model, project_path, session_key, started_at, last_active_at, ended_at
+		SELECT id, agent_name, program, model, project_path, session_key, started_at, last_active_at, ended_at, attestation_json
 		FROM sessions
 		WHERE ended_at IS NULL
 		ORDER BY last_active_at DESC
@@ -238,7 +253,7 @@ func (db *DB) ResetSessionRateLimits(id string, now time.Time) (time.Time, error
 func (db *DB) FindStaleSessions(threshold time.Duration) ([]*Session, error) {
 	cutoff := time.Now().UTC().Add(-threshold).Format(time.RFC3339)
 	rows, err := db.Query(`
-		SELECT id, agent_name, program, model, project_path, session_key, started_at, last_active_at, ended_at
+		SELECT id, agent_name, program, model, project_path, session_key, started_at, last_active_at, ended_at, attestation_json
 		FROM sessions
 		WHERE ended_at IS NULL AND last_active_at < ?
 		ORDER BY last_active_at ASC
@@ -255,7 +270,7 @@ func (db *DB) FindStaleSessions(threshold time.Duration) ([]*Session, error) {
 // that have a different model than the specified one.
 func (db *DB) ListActiveSessionsWithDifferentModel(projectPath, excludeModel string) ([]*Session, error) {
 	rows, err := db.Query(`
-		SELECT id, agent_name, program, model, project_path, session_key, started_at, last_active_at, ended_at
+		SELECT id, agent_name, program, model, project_path, session_key, started_at, last_active_at, ended_at, attestation_json
 		FROM sessions
 		WHERE project_path = ? AND ended_at IS NULL AND model != ?
 		ORDER BY last_active_at DESC
@@ -327,9 +342,9 @@ func (db *DB) GetDifferentModelStatus(projectPath, requestorModel string) (*Diff
 func scanSession(row *sql.Row) (*Session, error) {
 	s := &Session{}
 	var startedAt, lastActiveAt string
-	var endedAt sql.NullString
+	var endedAt, attestation sql.NullString
 
-	err := row.Scan(&s.ID, &s.AgentName, &s.Program, &s.Model, &s.ProjectPath, &s.SessionKey, &startedAt, &lastActiveAt, &endedAt)
+	err := row.Scan(&s.ID, &s.AgentName, &s.Program, &s.Model, &s.ProjectPath, &s.SessionKey, &startedAt, &lastActiveAt, &endedAt, &attestation)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, ErrSessionNotFound
@@ -356,6 +371,10 @@ func scanSession(row *sql.Row) (*Session, error) {
 		s.EndedAt = &t
 	}
 
+	if s.Attestation, err = decodeAttestation(attestation); err != nil {
+		return nil, err
+	}
+
 	return s, nil
 }
 
@@ -365,9 +384,9 @@ func scanSessions(rows *sql.Rows) ([]*Session, error) {
 	for rows.Next() {
 		s := &Session{}
 		var startedAt, lastActiveAt string
-		var endedAt sql.NullString
+		var endedAt, attestation sql.NullString
 
-		err := rows.Scan(&s.ID, &s.AgentName, &s.Program, &s.Model, &s.ProjectPath, &s.SessionKey, &startedAt, &lastActiveAt, &endedAt)
+		err := rows.Scan(&s.ID, &s.AgentName, &s.Program, &s.Model, &s.ProjectPath, &s.SessionKey, &startedAt, &lastActiveAt, &endedAt, &attestation)
 		if err != nil {
 			return nil, fmt.Errorf("scanning session row: %w", err)
 		}
@@ -391,6 +410,10 @@ func scanSessions(rows *sql.Rows) ([]*Session, error) {
 			s.EndedAt = &t
 		}
 
+		if s.Attestation, err = decodeAttestation(attestation); err != nil {
+			return nil, err
+		}
+
 		sessions = append(sessions, s)
 	}
 
@@ -171,6 +171,40 @@ func TestGetActiveSession(t *testing.T) {
 	}
 }
 
+func TestGetActiveSessionByKey(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	s := &Session{
+		AgentName:   "GreenLake",
+		Program:     "claude-code",
+		Model:       "opus-4.5",
+		ProjectPath: "/test/project",
+	}
+	if err := db.CreateSession(s); err != nil {
+		t.Fatalf("CreateSession failed: %v", err)
+	}
+
+	retrieved, err := db.GetActiveSessionByKey(s.SessionKey)
+	if err != nil {
+		t.Fatalf("GetActiveSessionByKey failed: %v", err)
+	}
+	if retrieved.ID != s.ID {
+		t.Errorf("ID mismatch: got %s, want %s", retrieved.ID, s.ID)
+	}
+
+	if _, err := db.GetActiveSessionByKey("unknown"); err != ErrSessionNotFound {
+		t.Errorf("Expected ErrSessionNotFound for unknown key, got: %v", err)
+	}
+
+	if err := db.EndSession(s.ID); err != nil {
+		t.Fatalf("EndSession failed: %v", err)
+	}
+	if _, err := db.GetActiveSessionByKey(s.SessionKey); err != ErrSessionNotFound {
+		t.Errorf("Expected ErrSessionNotFound after ending session, got: %v", err)
+	}
+}
+
 func TestListActiveSessions(t *testing.T) {
 	db := setupTestDB(t)
 	defer db.Close()
@@ -3,6 +3,9 @@ package db
 
 import (
 	"encoding/json"
+	"path"
+	"sort"
+	"strings"
 	"time"
 )
 
@@ -26,6 +29,50 @@ type Session struct {
 	LastActiveAt time.Time `json:"last_active_at"`
 	// EndedAt is when the session ended (nil if still active).
 	EndedAt *time.Time `json:"ended_at,omitempty"`
+	// Attestation is the process context the session was started from
+	// (nil when none was collected).
+	Attestation *SessionAttestation `json:"attestation,omitempty"`
+}
+
+// ProcessInfo identifies a process. StartTime disambiguates reused PIDs.
+type ProcessInfo struct {
+	PID       int    `json:"pid"`
+	Name      string `json:"name"`
+	StartTime uint64 `json:"start_time,omitempty"`
+}
+
+// SessionAttestation records facts about where a session was started that
+// the agent cannot simply declare: the process tree, terminal, container and
+// the agent detected from the environment.
+type SessionAttestation struct {
+	CollectedAt time.Time `json:"collected_at"`
+	// ProcessTree lists the ancestors of the slb process, nearest first.
+	ProcessTree []ProcessInfo `json:"process_tree,omitempty"`
+	// Anchor is the nearest long-lived ancestor (skipping shells and
+	// wrappers), normally the agent program itself.
+	Anchor      *ProcessInfo `json:"anchor,omitempty"`
+	TTY         string       `json:"tty,omitempty"`
+	ContainerID string       `json:"container_id,omitempty"`
+	Hostname    string       `json:"hostname,omitempty"`
+	User        string       `json:"user,omitempty"`
+	// DetectedProgram and DetectedModel come from environment variables,
+	// process ancestry and agent config files; DetectionSignals says which.
+	DetectedProgram  string   `json:"detected_program,omitempty"`
+	DetectedModel    string   `json:"detected_model,omitempty"`
+	DetectionSignals []string `json:"detection_signals,omitempty"`
+	// ProgramMismatch is set when the declared program is not the detected one.
+	ProgramMismatch bool `json:"program_mismatch,omitempty"`
+	// ContextChanges records resumes from a different process context.
+	ContextChanges []ContextChange `json:"context_changes,omitempty"`
+}
+
+// ContextChange is a resume whose process context differed from the one
+// the session was attested with.
+type ContextChange struct {
+	At     time.Time `json:"at"`
+	Fields []string  `json:"fields"`
+	// Anchor is the anchor process of the resuming context.
+	Anchor *ProcessInfo `json:"anchor,omitempty"`
 }
 
 // IsActive returns true if the session is still active.
@@ -77,8 +124,11 @@ type Attachment struct {
 type DryRunResult struct {
 	// Command is the dry run command that was executed.
 	Command string `json:"command"`
-	// Output is the output from the dry run.
+	// Output is the output from the dry run, with secrets scrubbed.
 	Output string `json:"output"`
+	// Redactions is the number of secrets scrubbed from Output (not
+	// stored with the request; see DB.RecordRedactions).
+	Redactions int `json:"redactions,omitempty"`
 }
 
 // Execution contains information about command execution.
@@ -134,6 +184,30 @@ type Request struct {
 	// Attachments contains additional context.
 	Attachments []Attachment `json:"attachments,omitempty"`
 
+	// Environment is the fingerprint of the execution environment captured
+	// at request time; execution is refused when it no longer matches.
+	Environment EnvironmentFingerprint `json:"environment,omitempty"`
+
+	// Sandbox requests sandboxed execution (Linux only).
+	Sandbox *SandboxSpec `json:"sandbox,omitempty"`
+
+	// Plan is set for multi-step plans, which are approved as one unit and
+	// executed step by step; Command then summarises the steps.
+	Plan *Plan `json:"plan,omitempty"`
+
+	// Window limits when the approved request may execute (nil = any time
+	// while the approval is valid).
+	Window *ExecutionWindow `json:"window,omitempty"`
+
+	// Policy records the policy rules that fired when the request was
+	// created and the reviewers and executors they allow (nil = no rule).
+	Policy *PolicyDecision `json:"policy,omitempty"`
+
+	// AutoAnswers are typed into matching prompts when the request runs in
+	// a pseudo-terminal (--pty). They are recorded at creation so reviewers
+	// see them; later config changes do not apply.
+	AutoAnswers []AutoAnswer `json:"auto_answers,omitempty"`
+
 	// Status is the current request status.
 	Status RequestStatus `json:"status"`
 	// MinApprovals is the minimum approvals required.
@@ -156,6 +230,165 @@ type Request struct {
 	ApprovalExpiresAt *time.Time `json:"approval_expires_at,omitempty"`
 }
 
+// AutoAnswer types Answer, followed by Enter, whenever a command running in
+// a pseudo-terminal prints output matching the regular expression Pattern.
+type AutoAnswer struct {
+	Pattern string `json:"pattern"`
+	Answer  string `json:"answer"`
+}
+
+// ExecutionWindow is when an approved request may execute: between
+// NotBefore and NotAfter and, when Recurring names a configured change
+// window, while that window is open.
+type ExecutionWindow struct {
+	NotBefore *time.Time `json:"not_before,omitempty"`
+	NotAfter  *time.Time `json:"not_after,omitempty"`
+	Recurring string     `json:"recurring,omitempty"`
+}
+
+// PolicyDecision is the outcome of the policy rules for a request that
+// outlives its creation: who may approve it and who may execute it.
+type PolicyDecision struct {
+	// Rules are the names of the rules that fired, in order.
+	Rules     []string           `json:"rules,omitempty"`
+	Reviewers []PolicyConstraint `json:"reviewers,omitempty"`
+	Executors []PolicyConstraint `json:"executors,omitempty"`
+
+	unreadable bool
+}
+
+// PolicyConstraint limits an action to sessions whose agent name matches one
+// of Agents and whose model matches one of Models (path.Match patterns,
+// case-insensitive; an empty list matches anything).
+type PolicyConstraint struct {
+	Rule   string   `json:"rule"`
+	Agents []string `json:"agents,omitempty"`
+	Models []string `json:"models,omitempty"`
+}
+
+// AllowsReviewer returns "" when a session of agent and model may approve
+// the request, or the name of the rule that forbids it.
+func (d *PolicyDecision) AllowsReviewer(agent, model string) string {
+	return d.allows(d.reviewers(), agent, model)
+}
+
+// AllowsExecutor returns "" when a session of agent and model may execute
+// the request, or the name of the rule that forbids it.
+func (d *PolicyDecision) AllowsExecutor(agent, model string) string {
+	return d.allows(d.executors(), agent, model)
+}
+
+func (d *PolicyDecision) reviewers() []PolicyConstraint {
+	if d == nil {
+		return nil
+	}
+	return d.Reviewers
+}
+
+func (d *PolicyDecision) executors() []PolicyConstraint {
+	if d == nil {
+		return nil
+	}
+	return d.Executors
+}
+
+func (d *PolicyDecision) allows(constraints []PolicyConstraint, agent, model string) string {
+	if d != nil && d.unreadable {
+		return "unreadable policy decision"
+	}
+	for _, c := range constraints {
+		if !matchesAny(c.Agents, agent) || !matchesAny(c.Models, model) {
+			return c.Rule
+		}
+	}
+	return ""
+}
+
+func matchesAny(patterns []string, value string) bool {
+	if len(patterns) == 0 {
+		return true
+	}
+	value = strings.ToLower(value)
+	for _, p := range patterns {
+		if ok, _ := path.Match(strings.ToLower(p), value); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// Plan is an ordered sequence of commands approved as one request.
+type Plan struct {
+	// Name identifies the plan (optional).
+	Name string `json:"name,omitempty"`
+	// Steps run in order.
+	Steps []PlanStep `json:"steps"`
+	// Hash is ComputePlanHash(Steps), checked before execution.
+	Hash string `json:"hash"`
+}
+
+// PlanStep is one command of a plan.
+type PlanStep struct {
+	// Command is the step's command; its Cwd may differ between steps.
+	Command CommandSpec `json:"command"`
+	// ExpectedEffect describes what the step will do (optional).
+	ExpectedEffect string `json:"expected_effect,omitempty"`
+	// RiskTier is the step's own classification ("safe" or empty when it
+	// needs no approval on its own).
+	RiskTier RiskTier `json:"risk_tier,omitempty"`
+	// ContinueOnFailure runs the remaining steps even if this one fails;
+	// by default a failing step stops the plan.
+	ContinueOnFailure bool `json:"continue_on_failure,omitempty"`
+}
+
+// SandboxSpec restricts a command's execution. Writes are limited to
+// WritePaths plus the paths named in the command.
+type SandboxSpec struct {
+	// WritePaths are extra paths the command may write beneath.
+	WritePaths []string `json:"write_paths,omitempty"`
+	// DisableNetwork cuts the command off from the network.
+	DisableNetwork bool `json:"disable_network,omitempty"`
+	// CPUSeconds limits CPU time (0 = unlimited).
+	CPUSeconds int `json:"cpu_seconds,omitempty"`
+	// MemoryMB limits the address space (0 = unlimited).
+	MemoryMB int `json:"memory_mb,omitempty"`
+	// TimeoutSeconds limits wall-clock time (0 = the executor's timeout).
+	TimeoutSeconds int `json:"timeout_seconds,omitempty"`
+}
+
+// EnvironmentFingerprint maps risk-relevant context (kube context, cloud
+// profile, git branch, ...) to its value, e.g. "kube.context" to "prod".
+// Keys whose value could not be determined are omitted.
+type EnvironmentFingerprint map[string]string
+
+// Keys returns the fingerprint keys in sorted order.
+func (f EnvironmentFingerprint) Keys() []string {
+	keys := make([]string, 0, len(f))
+	for k := range f {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Diff returns the sorted keys whose values differ between f and other,
+// including keys present on only one side.
+func (f EnvironmentFingerprint) Diff(other EnvironmentFingerprint) []string {
+	var keys []string
+	for k, v := range f {
+		if ov, ok := other[k]; !ok || ov != v {
+			keys = append(keys, k)
+		}
+	}
+	for k := range other {
+		if _, ok := f[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
 // IsExpired returns true if the request has expired.
 func (r *Request) IsExpired() bool {
 	if r.ExpiresAt == nil {
@@ -213,6 +446,8 @@ type Review struct {
 	Responses ReviewResponse `json:"responses,omitempty"`
 	// Comments contains additional comments.
 	Comments string `json:"comments,omitempty"`
+	// RequireSandbox makes an approval conditional on sandboxed execution.
+	RequireSandbox bool `json:"require_sandbox,omitempty"`
 
 	// CreatedAt is when the review was created.
 	CreatedAt time.Time `json:"created_at"`
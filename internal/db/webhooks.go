@@ -0,0 +1,246 @@
+package db
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ErrWebhookDeliveryNotFound indicates the requested delivery does not exist.
+var ErrWebhookDeliveryNotFound = errors.New("webhook delivery not found")
+
+// WebhookDeliveryStatus is the state of a webhook delivery.
+type WebhookDeliveryStatus string
+
+const (
+	// WebhookPending is queued for its first attempt.
+	WebhookPending WebhookDeliveryStatus = "pending"
+	// WebhookRetrying failed at least once and is waiting for its next attempt.
+	WebhookRetrying WebhookDeliveryStatus = "retrying"
+	// WebhookDelivered was accepted by the endpoint.
+	WebhookDelivered WebhookDeliveryStatus = "delivered"
+	// WebhookDead exhausted its attempts and sits in the dead-letter queue.
+	WebhookDead WebhookDeliveryStatus = "dead"
+)
+
+// WebhookDelivery records one event sent (or to be sent) to one endpoint.
+type WebhookDelivery struct {
+	ID             int64                 `json:"id"`
+	Endpoint       string                `json:"endpoint"`
+	URL            string                `json:"url"`
+	Event          string                `json:"event"`
+	RequestID      string                `json:"request_id,omitempty"`
+	Body           string                `json:"body"`
+	Status         WebhookDeliveryStatus `json:"status"`
+	Attempts       int                   `json:"attempts"`
+	LastStatusCode *int                  `json:"last_status_code,omitempty"`
+	LastError      string                `json:"last_error,omitempty"`
+	NextAttemptAt  *time.Time            `json:"next_attempt_at,omitempty"`
+	CreatedAt      time.Time             `json:"created_at"`
+	DeliveredAt    *time.Time            `json:"delivered_at,omitempty"`
+}
+
+// WebhookDeliveryFilter narrows ListWebhookDeliveries.
+type WebhookDeliveryFilter struct {
+	Endpoint  string
+	Status    WebhookDeliveryStatus
+	RequestID string
+	Limit     int
+}
+
+const webhookDeliveryColumns = `id, endpoint, url, event, request_id, body, status, attempts,
+	last_status_code, last_error, next_attempt_at, created_at, delivered_at`
+
+// CreateWebhookDelivery inserts a delivery, due immediately unless NextAttemptAt is set.
+func (db *DB) CreateWebhookDelivery(d *WebhookDelivery) error {
+	now := time.Now().UTC()
+	if d.CreatedAt.IsZero() {
+		d.CreatedAt = now
+	}
+	if d.Status == "" {
+		d.Status = WebhookPending
+	}
+	if d.NextAttemptAt == nil {
+		next := d.CreatedAt
+		d.NextAttemptAt = &next
+	}
+
+	result, err := db.Exec(`
+		INSERT INTO webhook_deliveries (
+			endpoint, url, event, request_id, body, status, attempts,
+			last_status_code, last_error, next_attempt_at, created_at, delivered_at
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`,
+		d.Endpoint, d.URL, d.Event, nullString(d.RequestID), d.Body, string(d.Status), d.Attempts,
+		nullInt(d.LastStatusCode), nullString(d.LastError), formatTimePtr(d.NextAttemptAt),
+		d.CreatedAt.UTC().Format(time.RFC3339Nano), formatTimePtr(d.DeliveredAt),
+	)
+	if err != nil {
+		return fmt.Errorf("creating webhook delivery: %w", err)
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("getting webhook delivery id: %w", err)
+	}
+	d.ID = id
+	return nil
+}
+
+// UpdateWebhookDelivery stores the outcome of a delivery attempt.
+func (db *DB) UpdateWebhookDelivery(d *WebhookDelivery) error {
+	result, err := db.Exec(`
+		UPDATE webhook_deliveries SET
+			status = ?, attempts = ?, last_status_code = ?, last_error = ?,
+			next_attempt_at = ?, delivered_at = ?
+		WHERE id = ?
+	`,
+		string(d.Status), d.Attempts, nullInt(d.LastStatusCode), nullString(d.LastError),
+		formatTimePtr(d.NextAttemptAt), formatTimePtr(d.DeliveredAt), d.ID,
+	)
+	if err != nil {
+		return fmt.Errorf("updating webhook delivery: %w", err)
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return ErrWebhookDeliveryNotFound
+	}
+	return nil
+}
+
+// GetWebhookDelivery retrieves a delivery by ID.
+func (db *DB) GetWebhookDelivery(id int64) (*WebhookDelivery, error) {
+	row := db.QueryRow(`SELECT `+webhookDeliveryColumns+` FROM webhook_deliveries WHERE id = ?`, id)
+	d, err := scanWebhookDelivery(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrWebhookDeliveryNotFound
+	}
+	return d, err
+}
+
+// ListWebhookDeliveries returns deliveries, most recent first.
+func (db *DB) ListWebhookDeliveries(f WebhookDeliveryFilter) ([]*WebhookDelivery, error) {
+	var (
+		where []string
+		args  []any
+	)
+	if f.Endpoint != "" {
+		where = append(where, "endpoint = ?")
+		args = append(args, f.Endpoint)
+	}
+	if f.Status != "" {
+		where = append(where, "status = ?")
+		args = append(args, string(f.Status))
+	}
+	if f.RequestID != "" {
+		where = append(where, "request_id = ?")
+		args = append(args, f.RequestID)
+	}
+	limit := f.Limit
+	if limit <= 0 {
+		limit = 50
+	}
+
+	query := `SELECT ` + webhookDeliveryColumns + ` FROM webhook_deliveries`
+	if len(where) > 0 {
+		query += " WHERE " + strings.Join(where, " AND ")
+	}
+	query += " ORDER BY id DESC LIMIT ?"
+	args = append(args, limit)
+
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("listing webhook deliveries: %w", err)
+	}
+	defer rows.Close()
+	return scanWebhookDeliveries(rows)
+}
+
+// ListDueWebhookDeliveries returns pending/retrying deliveries whose next attempt
+// is at or before now, oldest first.
+func (db *DB) ListDueWebhookDeliveries(now time.Time, limit int) ([]*WebhookDelivery, error) {
+	if limit <= 0 {
+		limit = 100
+	}
+	rows, err := db.Query(`
+		SELECT `+webhookDeliveryColumns+`
+		FROM webhook_deliveries
+		WHERE status IN (?, ?) AND next_attempt_at <= ?
+		ORDER BY next_attempt_at ASC, id ASC
+		LIMIT ?
+	`, string(WebhookPending), string(WebhookRetrying), now.UTC().Format(time.RFC3339), limit)
+	if err != nil {
+		return nil, fmt.Errorf("listing due webhook deliveries: %w", err)
+	}
+	defer rows.Close()
+	return scanWebhookDeliveries(rows)
+}
+
+// RequeueWebhookDelivery moves a delivery (typically from the dead-letter queue)
+// back to pending with a fresh attempt budget.
+func (db *DB) RequeueWebhookDelivery(id int64) error {
+	result, err := db.Exec(`
+		UPDATE webhook_deliveries SET status = ?, attempts = 0, next_attempt_at = ?
+		WHERE id = ? AND status != ?
+	`, string(WebhookPending), time.Now().UTC().Format(time.RFC3339), id, string(WebhookDelivered))
+	if err != nil {
+		return fmt.Errorf("requeueing webhook delivery: %w", err)
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		if _, err := db.GetWebhookDelivery(id); err != nil {
+			return err
+		}
+		return fmt.Errorf("webhook delivery %d was already delivered", id)
+	}
+	return nil
+}
+
+func scanWebhookDelivery(row interface{ Scan(...any) error }) (*WebhookDelivery, error) {
+	var (
+		d                                   WebhookDelivery
+		requestID, lastError                sql.NullString
+		statusCode                          sql.NullInt64
+		nextAttempt, createdAt, deliveredAt sql.NullString
+		status                              string
+	)
+	if err := row.Scan(&d.ID, &d.Endpoint, &d.URL, &d.Event, &requestID, &d.Body, &status, &d.Attempts,
+		&statusCode, &lastError, &nextAttempt, &createdAt, &deliveredAt); err != nil {
+		return nil, err
+	}
+	d.Status = WebhookDeliveryStatus(status)
+	d.RequestID = requestID.String
+	d.LastError = lastError.String
+	if statusCode.Valid {
+		code := int(statusCode.Int64)
+		d.LastStatusCode = &code
+	}
+	d.NextAttemptAt = parseWebhookTime(nextAttempt)
+	if t := parseWebhookTime(createdAt); t != nil {
+		d.CreatedAt = *t
+	}
+	d.DeliveredAt = parseWebhookTime(deliveredAt)
+	return &d, nil
+}
+
+func scanWebhookDeliveries(rows *sql.Rows) ([]*WebhookDelivery, error) {
+	var out []*WebhookDelivery
+	for rows.Next() {
+		d, err := scanWebhookDelivery(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scanning webhook delivery: %w", err)
+		}
+		out = append(out, d)
+	}
+	return out, rows.Err()
+}
+
+func parseWebhookTime(s sql.NullString) *time.Time {
+	if !s.Valid || s.String == "" {
+		return nil
+	}
+	t, err := time.Parse(time.RFC3339Nano, s.String)
+	if err != nil {
+		return nil
+	}
+	return &t
+}
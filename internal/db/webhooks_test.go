@@ -0,0 +1,82 @@
+package db
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestWebhookDeliveries_Lifecycle(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	d := &WebhookDelivery{
+		Endpoint:  "ops",
+		URL:       "https://hooks.example.com/ops",
+		Event:     "request_pending",
+		RequestID: "req-1",
+		Body:      `{"event":"request_pending"}`,
+	}
+	if err := db.CreateWebhookDelivery(d); err != nil {
+		t.Fatalf("CreateWebhookDelivery: %v", err)
+	}
+	if d.ID == 0 || d.Status != WebhookPending {
+		t.Fatalf("unexpected delivery after create: %+v", d)
+	}
+
+	due, err := db.ListDueWebhookDeliveries(time.Now().Add(time.Second), 10)
+	if err != nil {
+		t.Fatalf("ListDueWebhookDeliveries: %v", err)
+	}
+	if len(due) != 1 || due[0].ID != d.ID || due[0].Body != d.Body {
+		t.Fatalf("unexpected due deliveries: %+v", due)
+	}
+
+	// Fail once, schedule a retry in the future: no longer due.
+	code := 502
+	next := time.Now().Add(time.Hour)
+	d.Status = WebhookRetrying
+	d.Attempts = 1
+	d.LastStatusCode = &code
+	d.LastError = "bad gateway"
+	d.NextAttemptAt = &next
+	if err := db.UpdateWebhookDelivery(d); err != nil {
+		t.Fatalf("UpdateWebhookDelivery: %v", err)
+	}
+	if due, _ := db.ListDueWebhookDeliveries(time.Now(), 10); len(due) != 0 {
+		t.Fatalf("expected no due deliveries, got %d", len(due))
+	}
+
+	// Dead-letter, then requeue.
+	d.Status = WebhookDead
+	d.NextAttemptAt = nil
+	if err := db.UpdateWebhookDelivery(d); err != nil {
+		t.Fatalf("UpdateWebhookDelivery(dead): %v", err)
+	}
+	dead, err := db.ListWebhookDeliveries(WebhookDeliveryFilter{Status: WebhookDead})
+	if err != nil || len(dead) != 1 {
+		t.Fatalf("ListWebhookDeliveries(dead) = %d, %v", len(dead), err)
+	}
+	if dead[0].LastStatusCode == nil || *dead[0].LastStatusCode != 502 || dead[0].LastError != "bad gateway" {
+		t.Fatalf("unexpected dead delivery: %+v", dead[0])
+	}
+
+	if err := db.RequeueWebhookDelivery(d.ID); err != nil {
+		t.Fatalf("RequeueWebhookDelivery: %v", err)
+	}
+	got, err := db.GetWebhookDelivery(d.ID)
+	if err != nil {
+		t.Fatalf("GetWebhookDelivery: %v", err)
+	}
+	if got.Status != WebhookPending || got.Attempts != 0 {
+		t.Fatalf("unexpected requeued delivery: %+v", got)
+	}
+
+	if err := db.RequeueWebhookDelivery(9999); !errors.Is(err, ErrWebhookDeliveryNotFound) {
+		t.Fatalf("expected ErrWebhookDeliveryNotFound, got %v", err)
+	}
+
+	if list, _ := db.ListWebhookDeliveries(WebhookDeliveryFilter{Endpoint: "other"}); len(list) != 0 {
+		t.Fatalf("endpoint filter returned %d deliveries", len(list))
+	}
+}
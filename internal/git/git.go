@@ -35,6 +35,16 @@ func GetBranch(path string) (string, error) {
 	return strings.TrimSpace(string(out)), nil
 }
 
+// GetRemoteURL returns the URL of the named remote.
+func GetRemoteURL(path, remote string) (string, error) {
+	cmd := exec.Command("git", "-C", path, "remote", "get-url", remote)
+	out, err := cmd.Output()
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
 // InstallHook installs the SLB pre-commit hook.
 func InstallHook(repoPath string) error {
 	absPath, err := filepath.Abs(repoPath)
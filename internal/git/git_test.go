@@ -148,6 +148,16 @@ func TestRepoHelpers(t *testing.T) {
 	if strings.TrimSpace(branch) == "" || branch == "HEAD" {
 		t.Fatalf("unexpected branch: %q", branch)
 	}
+
+	if _, err := GetRemoteURL(sub, "origin"); err == nil {
+		t.Fatalf("expected GetRemoteURL error without remote")
+	}
+	if _, err := runGit(repo, "remote", "add", "origin", "https://example.com/org/repo.git"); err != nil {
+		t.Fatalf("remote add: %v", err)
+	}
+	if url, err := GetRemoteURL(sub, "origin"); err != nil || url != "https://example.com/org/repo.git" {
+		t.Fatalf("GetRemoteURL=%q, %v", url, err)
+	}
 }
 
 func TestGetRootAndBranch_NonRepoErrors(t *testing.T) {
@@ -0,0 +1,295 @@
+package integrations
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"github.com/Dicklesworthstone/slb/internal/utils"
+	"go.yaml.in/yaml/v3"
+)
+
+// agentSignature describes how to recognise an agent program.
+type agentSignature struct {
+	Type AgentType
+	Name string
+	// Env variables the agent sets in the shells it spawns.
+	Env []string
+	// Processes are executable names of the agent (lower case).
+	Processes []string
+	// Aliases are other spellings of the program name, normalized.
+	Aliases []string
+	// ModelEnv variables name the configured model.
+	ModelEnv []string
+	// ModelFile reads the configured model from the agent's config files.
+	ModelFile func(getenv func(string) string, home string) string
+}
+
+// agentSignatures are checked in order. Editors come last: agent CLIs often
+// run inside an editor's terminal and inherit its variables.
+var agentSignatures = []agentSignature{
+	{
+		Type:      AgentClaudeCode,
+		Name:      "Claude Code",
+		Env:       []string{"CLAUDECODE", "CLAUDE_CODE_ENTRYPOINT", "CLAUDE_CODE_SSE_PORT"},
+		Processes: []string{"claude"},
+		Aliases:   []string{"claude", "claudecode", "claudecli"},
+		ModelEnv:  []string{"ANTHROPIC_MODEL"},
+		ModelFile: func(_ func(string) string, home string) string {
+			return jsonModel(filepath.Join(home, ".claude", "settings.json"))
+		},
+	},
+	{
+		Type:      AgentCodexCLI,
+		Name:      "Codex CLI",
+		Env:       []string{"CODEX_SANDBOX", "CODEX_SANDBOX_NETWORK_DISABLED"},
+		Processes: []string{"codex"},
+		Aliases:   []string{"codex", "codexcli", "openaicodex"},
+		ModelFile: func(getenv func(string) string, home string) string {
+			dir := getenv("CODEX_HOME")
+			if dir == "" {
+				dir = filepath.Join(home, ".codex")
+			}
+			return tomlModel(filepath.Join(dir, "config.toml"))
+		},
+	},
+	{
+		Type:      AgentGeminiCLI,
+		Name:      "Gemini CLI",
+		Env:       []string{"GEMINI_CLI"},
+		Processes: []string{"gemini"},
+		Aliases:   []string{"gemini", "geminicli"},
+		ModelEnv:  []string{"GEMINI_MODEL"},
+		ModelFile: func(_ func(string) string, home string) string {
+			return jsonModel(filepath.Join(home, ".gemini", "settings.json"))
+		},
+	},
+	{
+		Type:      AgentAider,
+		Name:      "Aider",
+		Processes: []string{"aider"},
+		Aliases:   []string{"aider", "aiderchat"},
+		ModelEnv:  []string{"AIDER_MODEL"},
+		ModelFile: func(_ func(string) string, home string) string {
+			return yamlModel(filepath.Join(home, ".aider.conf.yml"))
+		},
+	},
+	{
+		Type:      AgentOpenCode,
+		Name:      "OpenCode",
+		Processes: []string{"opencode"},
+		Aliases:   []string{"opencode"},
+	},
+	{
+		Type:      AgentCursor,
+		Name:      "Cursor",
+		Env:       []string{"CURSOR_AGENT", "CURSOR_TRACE_ID"},
+		Processes: []string{"cursor", "cursor-agent"},
+		Aliases:   []string{"cursor", "cursoragent", "cursorcli"},
+	},
+	{
+		Type:      AgentWindsurf,
+		Name:      "Windsurf",
+		Processes: []string{"windsurf"},
+		Aliases:   []string{"windsurf", "codeium"},
+	},
+}
+
+// interpreters run agents written in scripting languages; the agent is then
+// named by the script argument.
+var interpreters = map[string]bool{
+	"node": true, "bun": true, "deno": true, "python": true, "python3": true,
+}
+
+// DetectAgent attempts to detect the current agent from environment
+// variables, the process ancestry and the agents' config files.
+func DetectAgent() *Agent {
+	home, _ := os.UserHomeDir()
+	return DetectAgentFrom(os.Getenv, utils.ProcessAncestors(os.Getppid()), home)
+}
+
+// DetectAgentFrom detects the agent from the given environment lookup,
+// ancestor processes (nearest first) and home directory. The nearest
+// ancestor that is a known agent wins; otherwise environment variables are
+// checked. Returns nil when nothing matches.
+func DetectAgentFrom(getenv func(string) string, ancestors []utils.Process, home string) *Agent {
+	var (
+		sig     *agentSignature
+		signals []string
+	)
+
+	for _, p := range ancestors {
+		for i := range agentSignatures {
+			if name := matchProcess(agentSignatures[i].Processes, p); name != "" {
+				sig = &agentSignatures[i]
+				signals = append(signals, "process:"+name)
+				break
+			}
+		}
+		if sig != nil {
+			break
+		}
+	}
+	if sig == nil {
+		for i := range agentSignatures {
+			if envSignals(getenv, agentSignatures[i].Env) != nil {
+				sig = &agentSignatures[i]
+				break
+			}
+		}
+	}
+	if sig == nil {
+		return nil
+	}
+	signals = append(signals, envSignals(getenv, sig.Env)...)
+
+	agent := &Agent{Name: sig.Name, Type: sig.Type, Signals: signals}
+	for _, key := range sig.ModelEnv {
+		if v := strings.TrimSpace(getenv(key)); v != "" {
+			agent.Model = v
+			agent.Signals = append(agent.Signals, "model:env:"+key)
+			break
+		}
+	}
+	if agent.Model == "" && sig.ModelFile != nil && home != "" {
+		if m := sig.ModelFile(getenv, home); m != "" {
+			agent.Model = m
+			agent.Signals = append(agent.Signals, "model:config")
+		}
+	}
+	return agent
+}
+
+// ProgramMatches reports whether a declared program name refers to the given
+// agent type, accepting common spellings such as "claude" for claude-code.
+// Unknown types only match their own name.
+func ProgramMatches(declared string, t AgentType) bool {
+	d := normalizeProgram(declared)
+	if d == normalizeProgram(string(t)) {
+		return true
+	}
+	for _, sig := range agentSignatures {
+		if sig.Type != t {
+			continue
+		}
+		for _, alias := range sig.Aliases {
+			if d == alias {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func normalizeProgram(s string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9':
+			return r
+		case r >= 'A' && r <= 'Z':
+			return r + ('a' - 'A')
+		}
+		return -1
+	}, s)
+}
+
+// matchProcess returns the matched name if the process is one of names,
+// looking through interpreters to the script or module they run.
+func matchProcess(names []string, p utils.Process) string {
+	candidates := []string{strings.ToLower(p.Name)}
+	if len(p.Args) > 0 {
+		exe := strings.ToLower(filepath.Base(p.Args[0]))
+		candidates = append(candidates, exe)
+		if interpreters[exe] && len(p.Args) > 1 {
+			script := p.Args[1]
+			if script == "-m" && len(p.Args) > 2 {
+				script = p.Args[2]
+			}
+			candidates = append(candidates, scriptNames(script)...)
+		}
+	}
+	for _, c := range candidates {
+		for _, n := range names {
+			if c == n {
+				return n
+			}
+		}
+	}
+	return ""
+}
+
+// scriptNames returns the path components of a script without extensions
+// and "-cli"/"-code" suffixes, so ".../@google/gemini-cli/dist/index.js"
+// yields "gemini".
+func scriptNames(path string) []string {
+	var names []string
+	for _, part := range strings.Split(strings.ToLower(filepath.ToSlash(path)), "/") {
+		part = strings.TrimSuffix(part, filepath.Ext(part))
+		part = strings.TrimSuffix(strings.TrimSuffix(part, "-cli"), "-code")
+		if part != "" {
+			names = append(names, part)
+		}
+	}
+	return names
+}
+
+func envSignals(getenv func(string) string, keys []string) []string {
+	var signals []string
+	for _, key := range keys {
+		if getenv(key) != "" {
+			signals = append(signals, "env:"+key)
+		}
+	}
+	return signals
+}
+
+func jsonModel(path string) string {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+	var settings struct {
+		Model json.RawMessage `json:"model"`
+	}
+	if json.Unmarshal(data, &settings) != nil || len(settings.Model) == 0 {
+		return ""
+	}
+	// "model" is either a name or an object with a name.
+	var name string
+	if json.Unmarshal(settings.Model, &name) == nil {
+		return strings.TrimSpace(name)
+	}
+	var obj struct {
+		Name string `json:"name"`
+	}
+	if json.Unmarshal(settings.Model, &obj) == nil {
+		return strings.TrimSpace(obj.Name)
+	}
+	return ""
+}
+
+func tomlModel(path string) string {
+	var cfg struct {
+		Model string `toml:"model"`
+	}
+	if _, err := toml.DecodeFile(path, &cfg); err != nil {
+		return ""
+	}
+	return strings.TrimSpace(cfg.Model)
+}
+
+func yamlModel(path string) string {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+	var cfg struct {
+		Model string `yaml:"model"`
+	}
+	if yaml.Unmarshal(data, &cfg) != nil {
+		return ""
+	}
+	return strings.TrimSpace(cfg.Model)
+}
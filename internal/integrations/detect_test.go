@@ -0,0 +1,119 @@
+package integrations
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Dicklesworthstone/slb/internal/utils"
+)
+
+func envOf(vars map[string]string) func(string) string {
+	return func(key string) string { return vars[key] }
+}
+
+func TestDetectAgentFrom(t *testing.T) {
+	home := t.TempDir()
+	writeFile := func(rel, content string) {
+		t.Helper()
+		path := filepath.Join(home, rel)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	writeFile(".codex/config.toml", "model = \"gpt-5-codex\"\n\n[tui]\nmodel = \"ignored\"\n")
+	writeFile(".gemini/settings.json", `{"model": {"name": "gemini-2.5-pro"}}`)
+	writeFile(".aider.conf.yml", "model: sonnet\n")
+
+	bash := utils.Process{PID: 30, Name: "bash", Args: []string{"/bin/bash", "-c", "slb run"}}
+
+	tests := []struct {
+		name      string
+		env       map[string]string
+		ancestors []utils.Process
+		wantType  AgentType
+		wantModel string
+		wantSig   string
+	}{
+		{
+			name:      "claude env with model env",
+			env:       map[string]string{"CLAUDECODE": "1", "ANTHROPIC_MODEL": "opus"},
+			wantType:  AgentClaudeCode,
+			wantModel: "opus",
+			wantSig:   "env:CLAUDECODE",
+		},
+		{
+			name:      "codex process with config file",
+			ancestors: []utils.Process{bash, {PID: 20, Name: "codex"}},
+			wantType:  AgentCodexCLI,
+			wantModel: "gpt-5-codex",
+			wantSig:   "process:codex",
+		},
+		{
+			name:      "gemini behind node",
+			ancestors: []utils.Process{bash, {PID: 20, Name: "node", Args: []string{"node", "/usr/lib/node_modules/@google/gemini-cli/dist/index.js"}}},
+			wantType:  AgentGeminiCLI,
+			wantModel: "gemini-2.5-pro",
+			wantSig:   "process:gemini",
+		},
+		{
+			name:      "aider as python module",
+			ancestors: []utils.Process{{PID: 20, Name: "python3", Args: []string{"python3", "-m", "aider"}}},
+			wantType:  AgentAider,
+			wantModel: "sonnet",
+		},
+		{
+			name:      "nearest agent process beats editor env",
+			env:       map[string]string{"CURSOR_TRACE_ID": "abc"},
+			ancestors: []utils.Process{bash, {PID: 20, Name: "claude"}, {PID: 10, Name: "cursor"}},
+			wantType:  AgentClaudeCode,
+		},
+		{
+			name:     "editor env alone",
+			env:      map[string]string{"CURSOR_TRACE_ID": "abc"},
+			wantType: AgentCursor,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			agent := DetectAgentFrom(envOf(tc.env), tc.ancestors, home)
+			if agent == nil {
+				t.Fatal("expected an agent")
+			}
+			if agent.Type != tc.wantType || agent.Model != tc.wantModel {
+				t.Errorf("got type=%s model=%q, want %s %q", agent.Type, agent.Model, tc.wantType, tc.wantModel)
+			}
+			if tc.wantSig != "" && !strings.Contains(strings.Join(agent.Signals, " "), tc.wantSig) {
+				t.Errorf("signals %v missing %q", agent.Signals, tc.wantSig)
+			}
+		})
+	}
+
+	if agent := DetectAgentFrom(envOf(nil), []utils.Process{bash}, home); agent != nil {
+		t.Errorf("expected no agent, got %+v", agent)
+	}
+}
+
+func TestProgramMatches(t *testing.T) {
+	for _, tc := range []struct {
+		declared string
+		agent    AgentType
+		want     bool
+	}{
+		{"claude-code", AgentClaudeCode, true},
+		{"Claude Code", AgentClaudeCode, true},
+		{"claude", AgentClaudeCode, true},
+		{"codex", AgentCodexCLI, true},
+		{"codex-cli", AgentClaudeCode, false},
+		{"my-wrapper", AgentType("my-wrapper"), true},
+		{"", AgentGeminiCLI, false},
+	} {
+		if got := ProgramMatches(tc.declared, tc.agent); got != tc.want {
+			t.Errorf("ProgramMatches(%q, %s) = %v, want %v", tc.declared, tc.agent, got, tc.want)
+		}
+	}
+}
@@ -1,5 +1,5 @@
 // Package integrations implements external service integrations for SLB.
-// Supports Claude Code, Codex CLI, Cursor, and other agent frameworks.
+// Supports Claude Code, Codex CLI, Gemini CLI, Cursor, and other agent frameworks.
 package integrations
 
 // AgentType represents a supported agent type.
@@ -8,8 +8,11 @@ type AgentType string
 const (
 	AgentClaudeCode AgentType = "claude-code"
 	AgentCodexCLI   AgentType = "codex-cli"
+	AgentGeminiCLI  AgentType = "gemini-cli"
 	AgentCursor     AgentType = "cursor"
 	AgentAider      AgentType = "aider"
+	AgentOpenCode   AgentType = "opencode"
+	AgentWindsurf   AgentType = "windsurf"
 	AgentCustom     AgentType = "custom"
 )
 
@@ -19,11 +22,7 @@ type Agent struct {
 	Type    AgentType
 	Model   string
 	Session string
-}
-
-// DetectAgent attempts to detect the current agent from environment.
-func DetectAgent() *Agent {
-	// TODO: Implement agent detection from env vars
-	// Check for CLAUDE_CODE_*, CODEX_*, CURSOR_* etc.
-	return nil
+	// Signals lists the evidence for a detection, e.g. "env:CLAUDECODE" or
+	// "process:codex".
+	Signals []string
 }
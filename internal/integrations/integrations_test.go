@@ -2,6 +2,7 @@ package integrations
 
 import (
 	"encoding/json"
+	"errors"
 	"os"
 	"path/filepath"
 	"strings"
@@ -250,10 +251,6 @@ func TestAgentMailClient_MissingCLIIsNonFatal(t *testing.T) {
 }
 
 func TestAgentMailHelpers(t *testing.T) {
-	if DetectAgent() != nil {
-		t.Fatalf("DetectAgent currently expected to return nil")
-	}
-
 	if got := importanceForTier(db.RiskTierCritical); got != ImportanceUrgent {
 		t.Fatalf("critical tier importance=%q", got)
 	}
@@ -353,3 +350,44 @@ func TestAgentMailClient_SendSucceedsWhenCommandSucceeds(t *testing.T) {
 		t.Fatalf("expected nil, got: %v", err)
 	}
 }
+
+type countingNotifier struct {
+	calls int
+	err   error
+}
+
+func (c *countingNotifier) NotifyNewRequest(*db.Request) error { c.calls++; return c.err }
+func (c *countingNotifier) NotifyRequestApproved(*db.Request, *db.Review) error {
+	c.calls++
+	return c.err
+}
+func (c *countingNotifier) NotifyRequestRejected(*db.Request, *db.Review) error {
+	c.calls++
+	return c.err
+}
+func (c *countingNotifier) NotifyRequestExecuted(*db.Request, *db.Execution, int) error {
+	c.calls++
+	return c.err
+}
+
+func TestMultiNotifier_CallsAllAndJoinsErrors(t *testing.T) {
+	boom := errors.New("boom")
+	first := &countingNotifier{err: boom}
+	second := &countingNotifier{}
+	m := MultiNotifier{first, nil, second}
+
+	req := &db.Request{ID: "r1"}
+	if err := m.NotifyNewRequest(req); !errors.Is(err, boom) {
+		t.Fatalf("expected joined error, got %v", err)
+	}
+	_ = m.NotifyRequestApproved(req, nil)
+	_ = m.NotifyRequestRejected(req, nil)
+	_ = m.NotifyRequestExecuted(req, nil, 0)
+
+	if first.calls != 4 || second.calls != 4 {
+		t.Fatalf("expected every notifier called 4 times, got %d and %d", first.calls, second.calls)
+	}
+	if err := (MultiNotifier{second}).NotifyNewRequest(req); err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+}
@@ -0,0 +1,40 @@
+package integrations
+
+import (
+	"errors"
+
+	"github.com/Dicklesworthstone/slb/internal/db"
+)
+
+// MultiNotifier fans request events out to several notifiers. Every notifier is
+// called; the errors are joined.
+type MultiNotifier []RequestNotifier
+
+func (m MultiNotifier) NotifyNewRequest(req *db.Request) error {
+	return m.each(func(n RequestNotifier) error { return n.NotifyNewRequest(req) })
+}
+
+func (m MultiNotifier) NotifyRequestApproved(req *db.Request, review *db.Review) error {
+	return m.each(func(n RequestNotifier) error { return n.NotifyRequestApproved(req, review) })
+}
+
+func (m MultiNotifier) NotifyRequestRejected(req *db.Request, review *db.Review) error {
+	return m.each(func(n RequestNotifier) error { return n.NotifyRequestRejected(req, review) })
+}
+
+func (m MultiNotifier) NotifyRequestExecuted(req *db.Request, exec *db.Execution, exitCode int) error {
+	return m.each(func(n RequestNotifier) error { return n.NotifyRequestExecuted(req, exec, exitCode) })
+}
+
+func (m MultiNotifier) each(fn func(RequestNotifier) error) error {
+	var errs []error
+	for _, n := range m {
+		if n == nil {
+			continue
+		}
+		if err := fn(n); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,751 @@
+package policy
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Expr is a compiled rule condition. The language is a small subset of CEL:
+//
+//   - literals: "double" or 'single' quoted strings (single-quoted strings
+//     keep backslashes, which suits regular expressions), numbers, true,
+//     false, null and lists [a, b]
+//   - variables and fields: request.command, env["kube.context"]
+//   - operators: ! - == != < <= > >= in && || and parentheses
+//   - methods: s.matches(re), s.startsWith(p), s.endsWith(p),
+//     x.contains(v), s.lower(), x.size() and size(x)
+//
+// Missing fields and map keys evaluate to null; methods on null return
+// false or null. "in" tests list membership, map keys and substrings.
+type Expr struct {
+	src  string
+	root node
+}
+
+// Compile parses src.
+func Compile(src string) (*Expr, error) {
+	toks, err := lex(src)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{toks: toks}
+	root, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if t := p.peek(); t.kind != tokEOF {
+		return nil, fmt.Errorf("unexpected %q at offset %d", t.text, t.pos)
+	}
+	return &Expr{src: src, root: root}, nil
+}
+
+// String returns the source of the expression.
+func (e *Expr) String() string { return e.src }
+
+// Eval evaluates the expression with the given variables.
+func (e *Expr) Eval(vars map[string]any) (any, error) {
+	return e.root.eval(vars)
+}
+
+// EvalBool evaluates the expression, which must produce a boolean.
+func (e *Expr) EvalBool(vars map[string]any) (bool, error) {
+	v, err := e.Eval(vars)
+	if err != nil {
+		return false, err
+	}
+	b, ok := v.(bool)
+	if !ok {
+		return false, fmt.Errorf("expression %q produced %s, not a boolean", e.src, typeName(v))
+	}
+	return b, nil
+}
+
+// Lexer
+
+type tokKind int
+
+const (
+	tokEOF tokKind = iota
+	tokIdent
+	tokString
+	tokNumber
+	tokOp
+)
+
+type token struct {
+	kind tokKind
+	text string
+	str  string
+	num  float64
+	pos  int
+}
+
+func lex(src string) ([]token, error) {
+	var toks []token
+	for i := 0; i < len(src); {
+		c := src[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case isIdentStart(c):
+			j := i + 1
+			for j < len(src) && (isIdentStart(src[j]) || isDigit(src[j])) {
+				j++
+			}
+			toks = append(toks, token{kind: tokIdent, text: src[i:j], pos: i})
+			i = j
+		case isDigit(c):
+			j := i + 1
+			for j < len(src) && (isDigit(src[j]) || src[j] == '.') {
+				j++
+			}
+			n, err := strconv.ParseFloat(src[i:j], 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid number %q at offset %d", src[i:j], i)
+			}
+			toks = append(toks, token{kind: tokNumber, text: src[i:j], num: n, pos: i})
+			i = j
+		case c == '"' || c == '\'':
+			s, n, err := lexString(src[i:])
+			if err != nil {
+				return nil, fmt.Errorf("%v at offset %d", err, i)
+			}
+			toks = append(toks, token{kind: tokString, text: src[i : i+n], str: s, pos: i})
+			i += n
+		default:
+			op := ""
+			for _, candidate := range []string{"&&", "||", "==", "!=", "<=", ">=", "!", "<", ">", "(", ")", "[", "]", ",", ".", "-"} {
+				if strings.HasPrefix(src[i:], candidate) {
+					op = candidate
+					break
+				}
+			}
+			if op == "" {
+				return nil, fmt.Errorf("unexpected character %q at offset %d", c, i)
+			}
+			toks = append(toks, token{kind: tokOp, text: op, pos: i})
+			i += len(op)
+		}
+	}
+	return append(toks, token{kind: tokEOF, pos: len(src)}), nil
+}
+
+// lexString reads the string literal at the start of s and returns its value
+// and length.
+func lexString(s string) (string, int, error) {
+	quote := s[0]
+	var b strings.Builder
+	for i := 1; i < len(s); i++ {
+		switch c := s[i]; {
+		case c == quote:
+			if quote == '"' {
+				v, err := strconv.Unquote(s[:i+1])
+				return v, i + 1, err
+			}
+			return b.String(), i + 1, nil
+		case c == '\\' && i+1 < len(s):
+			if quote == '\'' && (s[i+1] == '\'' || s[i+1] == '\\') {
+				i++
+				b.WriteByte(s[i])
+				continue
+			}
+			b.WriteByte(c)
+			if quote == '"' {
+				i++
+				b.WriteByte(s[i])
+			}
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return "", 0, fmt.Errorf("unterminated string")
+}
+
+func isIdentStart(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func isDigit(c byte) bool { return c >= '0' && c <= '9' }
+
+// Parser
+
+type parser struct {
+	toks []token
+	pos  int
+}
+
+func (p *parser) peek() token { return p.toks[p.pos] }
+
+func (p *parser) next() token {
+	t := p.toks[p.pos]
+	if t.kind != tokEOF {
+		p.pos++
+	}
+	return t
+}
+
+func (p *parser) accept(op string) bool {
+	if t := p.peek(); t.kind == tokOp && t.text == op {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *parser) expect(op string) error {
+	if !p.accept(op) {
+		t := p.peek()
+		if t.kind == tokEOF {
+			return fmt.Errorf("expected %q at end of expression", op)
+		}
+		return fmt.Errorf("expected %q at offset %d, found %q", op, t.pos, t.text)
+	}
+	return nil
+}
+
+func (p *parser) parseOr() (node, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.accept("||") {
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = &logicNode{and: false, left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseAnd() (node, error) {
+	left, err := p.parseComparison()
+	if err != nil {
+		return nil, err
+	}
+	for p.accept("&&") {
+		right, err := p.parseComparison()
+		if err != nil {
+			return nil, err
+		}
+		left = &logicNode{and: true, left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseComparison() (node, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	t := p.peek()
+	op := t.text
+	switch {
+	case t.kind == tokOp && (op == "==" || op == "!=" || op == "<" || op == "<=" || op == ">" || op == ">="):
+	case t.kind == tokIdent && op == "in":
+	default:
+		return left, nil
+	}
+	p.next()
+	right, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	return &compareNode{op: op, left: left, right: right}, nil
+}
+
+func (p *parser) parseUnary() (node, error) {
+	if p.accept("!") {
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &notNode{operand: operand}, nil
+	}
+	if p.accept("-") {
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &negNode{operand: operand}, nil
+	}
+	return p.parsePostfix()
+}
+
+func (p *parser) parsePostfix() (node, error) {
+	n, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		switch {
+		case p.accept("."):
+			t := p.next()
+			if t.kind != tokIdent {
+				return nil, fmt.Errorf("expected a field or method name at offset %d", t.pos)
+			}
+			if p.peek().kind == tokOp && p.peek().text == "(" {
+				args, err := p.parseArgs()
+				if err != nil {
+					return nil, err
+				}
+				if err := checkMethod(t.text, len(args)); err != nil {
+					return nil, err
+				}
+				n = &callNode{name: t.text, target: n, args: args}
+			} else {
+				n = &fieldNode{target: n, name: t.text}
+			}
+		case p.accept("["):
+			index, err := p.parseOr()
+			if err != nil {
+				return nil, err
+			}
+			if err := p.expect("]"); err != nil {
+				return nil, err
+			}
+			n = &indexNode{target: n, index: index}
+		default:
+			return n, nil
+		}
+	}
+}
+
+func (p *parser) parsePrimary() (node, error) {
+	t := p.next()
+	switch t.kind {
+	case tokString:
+		return &literalNode{value: t.str}, nil
+	case tokNumber:
+		return &literalNode{value: t.num}, nil
+	case tokIdent:
+		switch t.text {
+		case "true":
+			return &literalNode{value: true}, nil
+		case "false":
+			return &literalNode{value: false}, nil
+		case "null":
+			return &literalNode{value: nil}, nil
+		case "size":
+			args, err := p.parseArgs()
+			if err != nil {
+				return nil, err
+			}
+			if len(args) != 1 {
+				return nil, fmt.Errorf("size() takes one argument")
+			}
+			return &callNode{name: "size", target: args[0]}, nil
+		}
+		return &varNode{name: t.text}, nil
+	case tokOp:
+		switch t.text {
+		case "(":
+			n, err := p.parseOr()
+			if err != nil {
+				return nil, err
+			}
+			return n, p.expect(")")
+		case "[":
+			var items []node
+			if !p.accept("]") {
+				for {
+					item, err := p.parseOr()
+					if err != nil {
+						return nil, err
+					}
+					items = append(items, item)
+					if p.accept("]") {
+						break
+					}
+					if err := p.expect(","); err != nil {
+						return nil, err
+					}
+				}
+			}
+			return &listNode{items: items}, nil
+		}
+	case tokEOF:
+		return nil, fmt.Errorf("unexpected end of expression")
+	}
+	return nil, fmt.Errorf("unexpected %q at offset %d", t.text, t.pos)
+}
+
+func (p *parser) parseArgs() ([]node, error) {
+	if err := p.expect("("); err != nil {
+		return nil, err
+	}
+	var args []node
+	if p.accept(")") {
+		return args, nil
+	}
+	for {
+		arg, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, arg)
+		if p.accept(")") {
+			return args, nil
+		}
+		if err := p.expect(","); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// methods maps method names to their number of arguments.
+var methods = map[string]int{
+	"matches":    1,
+	"startsWith": 1,
+	"endsWith":   1,
+	"contains":   1,
+	"lower":      0,
+	"size":       0,
+}
+
+func checkMethod(name string, args int) error {
+	want, ok := methods[name]
+	if !ok {
+		return fmt.Errorf("unknown method %s()", name)
+	}
+	if args != want {
+		return fmt.Errorf("%s() takes %d argument(s), got %d", name, want, args)
+	}
+	return nil
+}
+
+// Evaluation
+
+type node interface {
+	eval(vars map[string]any) (any, error)
+}
+
+type literalNode struct{ value any }
+
+func (n *literalNode) eval(map[string]any) (any, error) { return n.value, nil }
+
+type varNode struct{ name string }
+
+func (n *varNode) eval(vars map[string]any) (any, error) {
+	v, ok := vars[n.name]
+	if !ok {
+		return nil, fmt.Errorf("unknown variable %q", n.name)
+	}
+	return v, nil
+}
+
+type listNode struct{ items []node }
+
+func (n *listNode) eval(vars map[string]any) (any, error) {
+	list := make([]any, 0, len(n.items))
+	for _, item := range n.items {
+		v, err := item.eval(vars)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, v)
+	}
+	return list, nil
+}
+
+type fieldNode struct {
+	target node
+	name   string
+}
+
+func (n *fieldNode) eval(vars map[string]any) (any, error) {
+	v, err := n.target.eval(vars)
+	if err != nil {
+		return nil, err
+	}
+	return lookup(v, n.name)
+}
+
+type indexNode struct {
+	target, index node
+}
+
+func (n *indexNode) eval(vars map[string]any) (any, error) {
+	v, err := n.target.eval(vars)
+	if err != nil {
+		return nil, err
+	}
+	index, err := n.index.eval(vars)
+	if err != nil {
+		return nil, err
+	}
+	switch key := index.(type) {
+	case string:
+		return lookup(v, key)
+	case float64:
+		list, ok := v.([]any)
+		if !ok {
+			return nil, fmt.Errorf("cannot index %s with a number", typeName(v))
+		}
+		if i := int(key); float64(i) == key && i >= 0 && i < len(list) {
+			return list[i], nil
+		}
+		return nil, nil
+	}
+	return nil, fmt.Errorf("invalid index %s", typeName(index))
+}
+
+func lookup(v any, key string) (any, error) {
+	switch m := v.(type) {
+	case nil:
+		return nil, nil
+	case map[string]any:
+		return m[key], nil
+	}
+	return nil, fmt.Errorf("cannot select %q from %s", key, typeName(v))
+}
+
+type logicNode struct {
+	and         bool
+	left, right node
+}
+
+func (n *logicNode) eval(vars map[string]any) (any, error) {
+	left, err := evalBool(n.left, vars)
+	if err != nil {
+		return nil, err
+	}
+	if left != n.and {
+		return left, nil
+	}
+	return evalBool(n.right, vars)
+}
+
+type notNode struct{ operand node }
+
+func (n *notNode) eval(vars map[string]any) (any, error) {
+	b, err := evalBool(n.operand, vars)
+	return !b, err
+}
+
+type negNode struct{ operand node }
+
+func (n *negNode) eval(vars map[string]any) (any, error) {
+	v, err := n.operand.eval(vars)
+	if err != nil {
+		return nil, err
+	}
+	f, ok := v.(float64)
+	if !ok {
+		return nil, fmt.Errorf("cannot negate %s", typeName(v))
+	}
+	return -f, nil
+}
+
+func evalBool(n node, vars map[string]any) (bool, error) {
+	v, err := n.eval(vars)
+	if err != nil {
+		return false, err
+	}
+	b, ok := v.(bool)
+	if !ok {
+		return false, fmt.Errorf("expected a boolean, got %s", typeName(v))
+	}
+	return b, nil
+}
+
+type compareNode struct {
+	op          string
+	left, right node
+}
+
+func (n *compareNode) eval(vars map[string]any) (any, error) {
+	left, err := n.left.eval(vars)
+	if err != nil {
+		return nil, err
+	}
+	right, err := n.right.eval(vars)
+	if err != nil {
+		return nil, err
+	}
+	switch n.op {
+	case "==":
+		return equal(left, right), nil
+	case "!=":
+		return !equal(left, right), nil
+	case "in":
+		return contains(right, left)
+	}
+	var cmp int
+	switch l := left.(type) {
+	case float64:
+		r, ok := right.(float64)
+		if !ok {
+			return nil, fmt.Errorf("cannot compare number with %s", typeName(right))
+		}
+		switch {
+		case l < r:
+			cmp = -1
+		case l > r:
+			cmp = 1
+		}
+	case string:
+		r, ok := right.(string)
+		if !ok {
+			return nil, fmt.Errorf("cannot compare string with %s", typeName(right))
+		}
+		cmp = strings.Compare(l, r)
+	default:
+		return nil, fmt.Errorf("cannot order %s", typeName(left))
+	}
+	switch n.op {
+	case "<":
+		return cmp < 0, nil
+	case "<=":
+		return cmp <= 0, nil
+	case ">":
+		return cmp > 0, nil
+	default:
+		return cmp >= 0, nil
+	}
+}
+
+func equal(a, b any) bool {
+	la, aList := a.([]any)
+	lb, bList := b.([]any)
+	if aList || bList {
+		if !aList || !bList || len(la) != len(lb) {
+			return false
+		}
+		for i := range la {
+			if !equal(la[i], lb[i]) {
+				return false
+			}
+		}
+		return true
+	}
+	if _, ok := a.(map[string]any); ok {
+		return false
+	}
+	if _, ok := b.(map[string]any); ok {
+		return false
+	}
+	return a == b
+}
+
+// contains reports whether container (a list, map or string) holds v.
+func contains(container, v any) (bool, error) {
+	switch c := container.(type) {
+	case nil:
+		return false, nil
+	case []any:
+		for _, item := range c {
+			if equal(item, v) {
+				return true, nil
+			}
+		}
+		return false, nil
+	case map[string]any:
+		key, ok := v.(string)
+		if !ok {
+			return false, nil
+		}
+		_, found := c[key]
+		return found, nil
+	case string:
+		s, ok := v.(string)
+		if !ok {
+			return false, fmt.Errorf("cannot look for %s in a string", typeName(v))
+		}
+		return strings.Contains(c, s), nil
+	}
+	return false, fmt.Errorf("cannot look inside %s", typeName(container))
+}
+
+type callNode struct {
+	name   string
+	target node
+	args   []node
+}
+
+func (n *callNode) eval(vars map[string]any) (any, error) {
+	target, err := n.target.eval(vars)
+	if err != nil {
+		return nil, err
+	}
+	args := make([]any, len(n.args))
+	for i, a := range n.args {
+		if args[i], err = a.eval(vars); err != nil {
+			return nil, err
+		}
+	}
+
+	switch n.name {
+	case "size":
+		switch t := target.(type) {
+		case nil:
+			return float64(0), nil
+		case string:
+			return float64(len(t)), nil
+		case []any:
+			return float64(len(t)), nil
+		case map[string]any:
+			return float64(len(t)), nil
+		}
+		return nil, fmt.Errorf("size() of %s", typeName(target))
+	case "contains":
+		if s, ok := target.(string); ok {
+			arg, ok := args[0].(string)
+			if !ok {
+				return nil, fmt.Errorf("contains() of a string needs a string argument")
+			}
+			return strings.Contains(s, arg), nil
+		}
+		return contains(target, args[0])
+	}
+
+	if target == nil {
+		if n.name == "lower" {
+			return nil, nil
+		}
+		return false, nil
+	}
+	s, ok := target.(string)
+	if !ok {
+		return nil, fmt.Errorf("%s() of %s", n.name, typeName(target))
+	}
+	if n.name == "lower" {
+		return strings.ToLower(s), nil
+	}
+	arg, ok := args[0].(string)
+	if !ok {
+		return nil, fmt.Errorf("%s() needs a string argument", n.name)
+	}
+	switch n.name {
+	case "matches":
+		re, err := regexp.Compile(arg)
+		if err != nil {
+			return nil, fmt.Errorf("matches(): %w", err)
+		}
+		return re.MatchString(s), nil
+	case "startsWith":
+		return strings.HasPrefix(s, arg), nil
+	default:
+		return strings.HasSuffix(s, arg), nil
+	}
+}
+
+func typeName(v any) string {
+	switch v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "a boolean"
+	case float64:
+		return "a number"
+	case string:
+		return "a string"
+	case []any:
+		return "a list"
+	case map[string]any:
+		return "a map"
+	}
+	return fmt.Sprintf("%T", v)
+}
@@ -0,0 +1,86 @@
+package policy
+
+import "testing"
+
+func TestExpr_Eval(t *testing.T) {
+	vars := map[string]any{
+		"request": map[string]any{"command": "kubectl delete ns staging", "steps": float64(0)},
+		"session": map[string]any{"agent": "BlueDog", "model": "gpt-5"},
+		"env":     map[string]any{"kube.context": "prod-eu"},
+		"time":    map[string]any{"hour": float64(23), "weekday": "fri"},
+		"reviewers": map[string]any{
+			"count":  float64(2),
+			"models": []any{"opus-4.5", "gpt-5"},
+		},
+	}
+	for _, tc := range []struct {
+		expr string
+		want any
+	}{
+		{`request.command.startsWith("kubectl")`, true},
+		{`request.command.matches('\bdelete\b')`, true},
+		{`request.command.matches("^rm")`, false},
+		{`env["kube.context"].startsWith("prod") && time.hour >= 22`, true},
+		{`env["aws.profile"] == "prod"`, false},
+		{`env["aws.profile"].startsWith("prod")`, false},
+		{`"kube.context" in env`, true},
+		{`session.model in reviewers.models`, true},
+		{`session.agent.lower() in ["bluedog", "reddog"]`, true},
+		{`time.weekday in ["sat", "sun"]`, false},
+		{`!(time.hour < 7 || time.hour >= 22)`, false},
+		{`size(reviewers.models) == 2 && reviewers.models.size() > 1`, true},
+		{`-reviewers.count < 0`, true},
+		{`reviewers.models.contains("opus-4.5")`, true},
+		{`"delete" in request.command`, true},
+		{`request.steps == 0`, true},
+		{`[1, "a"] == [1, "a"]`, true},
+		{`request.missing == null`, true},
+		{`"a\tb" != 'a\tb'`, true},
+		{`'it\'s' == "it's"`, true},
+		{`false && request.missing.nested.startsWith("x")`, false},
+	} {
+		expr, err := Compile(tc.expr)
+		if err != nil {
+			t.Errorf("Compile(%q): %v", tc.expr, err)
+			continue
+		}
+		got, err := expr.Eval(vars)
+		if err != nil {
+			t.Errorf("Eval(%q): %v", tc.expr, err)
+			continue
+		}
+		if got != tc.want {
+			t.Errorf("Eval(%q) = %v, want %v", tc.expr, got, tc.want)
+		}
+	}
+}
+
+func TestExpr_Errors(t *testing.T) {
+	for _, src := range []string{"", "a ==", "(a", `"unterminated`, "a.foo()", "a.matches()", "a # b", "[1, 2", "a b"} {
+		if _, err := Compile(src); err == nil {
+			t.Errorf("Compile(%q): expected an error", src)
+		}
+	}
+
+	vars := map[string]any{"n": float64(1), "s": "x"}
+	for _, src := range []string{"unknown == 1", "n < s", "n && true", "s.matches('(')", "-s", "n.startsWith('x')"} {
+		expr, err := Compile(src)
+		if err != nil {
+			t.Errorf("Compile(%q): %v", src, err)
+			continue
+		}
+		if _, err := expr.Eval(vars); err == nil {
+			t.Errorf("Eval(%q): expected an error", src)
+		}
+	}
+	if _, err := Compile("n - 1 == 0"); err == nil {
+		t.Error("expected an error for an unsupported operator")
+	}
+	expr, err := Compile("s")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := expr.EvalBool(vars); err == nil {
+		t.Error("EvalBool of a string: expected an error")
+	}
+}
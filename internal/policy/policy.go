@@ -0,0 +1,477 @@
+// Package policy evaluates declarative approval policies: ordered rules whose
+// conditions are small CEL-like expressions over a request, its
+// classification, the requesting session, the environment fingerprint, the
+// time of day and the available reviewers, and whose effects set required
+// approvals, constrain reviewers and executors, or deny the request.
+package policy
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"github.com/Dicklesworthstone/slb/internal/config"
+	"github.com/Dicklesworthstone/slb/internal/db"
+)
+
+// ErrDenied is returned when a rule denies a request.
+var ErrDenied = errors.New("denied by policy")
+
+// Policy is an ordered list of rules. Every rule whose condition holds
+// fires; the strictest effects win.
+type Policy struct {
+	// Timezone is the IANA zone of the time variables (empty = local time).
+	Timezone string `toml:"timezone"`
+	Rules    []Rule `toml:"rule"`
+
+	loc *time.Location
+}
+
+// Rule is a condition and the effects applied when it holds.
+type Rule struct {
+	Name string `toml:"name"`
+	// When is the condition; empty always holds.
+	When string `toml:"when"`
+	// Reason explains the rule; it is the message of a denial.
+	Reason string `toml:"reason"`
+
+	Deny bool `toml:"deny"`
+	// MinApprovals sets the approvals required; the highest of the rules
+	// that fired applies.
+	MinApprovals          *int `toml:"min_approvals"`
+	RequireDifferentModel bool `toml:"require_different_model"`
+	// Reviewers and ReviewerModels limit who may approve, and Executors who
+	// may execute, to matching agent names and models (glob patterns).
+	Reviewers      []string `toml:"reviewers"`
+	ReviewerModels []string `toml:"reviewer_models"`
+	Executors      []string `toml:"executors"`
+
+	expr *Expr
+	// base marks the rules compiled from the configuration by Default.
+	base bool
+}
+
+// Parse reads a TOML policy and compiles its rules.
+func Parse(data []byte) (*Policy, error) {
+	var p Policy
+	md, err := toml.Decode(string(data), &p)
+	if err != nil {
+		return nil, err
+	}
+	if undecoded := md.Undecoded(); len(undecoded) > 0 {
+		keys := make([]string, len(undecoded))
+		for i, k := range undecoded {
+			keys[i] = k.String()
+		}
+		return nil, fmt.Errorf("unknown keys: %s", strings.Join(keys, ", "))
+	}
+	if err := p.Compile(); err != nil {
+		return nil, err
+	}
+	for _, r := range p.Rules {
+		if r.MinApprovals != nil && *r.MinApprovals < 1 {
+			return nil, fmt.Errorf("rule %q: min_approvals must be >= 1", r.Name)
+		}
+	}
+	return &p, nil
+}
+
+// LoadFile parses the policy file at path.
+func LoadFile(path string) (*Policy, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	p, err := Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("policy %s: %w", path, err)
+	}
+	return p, nil
+}
+
+// Compile validates the rules and compiles their conditions.
+func (p *Policy) Compile() error {
+	loc := time.Local
+	if p.Timezone != "" {
+		var err error
+		if loc, err = time.LoadLocation(p.Timezone); err != nil {
+			return fmt.Errorf("timezone: %w", err)
+		}
+	}
+	p.loc = loc
+
+	seen := make(map[string]bool, len(p.Rules))
+	for i := range p.Rules {
+		r := &p.Rules[i]
+		if r.Name == "" {
+			return fmt.Errorf("rule %d: name is required", i+1)
+		}
+		if seen[r.Name] {
+			return fmt.Errorf("rule %q: duplicate name", r.Name)
+		}
+		seen[r.Name] = true
+		if r.MinApprovals != nil && *r.MinApprovals < 0 {
+			return fmt.Errorf("rule %q: min_approvals must be >= 0", r.Name)
+		}
+		if len(r.effects()) == 0 {
+			return fmt.Errorf("rule %q: no effect (deny, min_approvals, require_different_model, reviewers, reviewer_models or executors)", r.Name)
+		}
+		r.expr = nil
+		if strings.TrimSpace(r.When) != "" {
+			expr, err := Compile(r.When)
+			if err != nil {
+				return fmt.Errorf("rule %q: when: %w", r.Name, err)
+			}
+			r.expr = expr
+		}
+	}
+	return nil
+}
+
+// Extend returns a policy with the rules of p followed by those of other.
+// The rules of other can only add to p: reusing the name of one of its rules
+// is an error.
+func (p *Policy) Extend(other *Policy) (*Policy, error) {
+	if other == nil {
+		return p, nil
+	}
+	merged := &Policy{Timezone: p.Timezone, loc: p.loc}
+	if other.Timezone != "" {
+		merged.Timezone, merged.loc = other.Timezone, other.loc
+	}
+	existing := make(map[string]bool, len(p.Rules))
+	for _, r := range p.Rules {
+		existing[r.Name] = true
+	}
+	for _, r := range other.Rules {
+		if existing[r.Name] {
+			return nil, fmt.Errorf("rule %q: name is taken by a default rule", r.Name)
+		}
+	}
+	merged.Rules = append(merged.Rules, p.Rules...)
+	for _, r := range other.Rules {
+		r.base = false
+		merged.Rules = append(merged.Rules, r)
+	}
+	return merged, nil
+}
+
+// effects describes what the rule does when it fires.
+func (r *Rule) effects() []string {
+	var effects []string
+	if r.Deny {
+		effects = append(effects, "deny")
+	}
+	if r.MinApprovals != nil {
+		effects = append(effects, "min_approvals="+strconv.Itoa(*r.MinApprovals))
+	}
+	if r.RequireDifferentModel {
+		effects = append(effects, "require_different_model")
+	}
+	if len(r.Reviewers) > 0 {
+		effects = append(effects, "reviewers="+strings.Join(r.Reviewers, ","))
+	}
+	if len(r.ReviewerModels) > 0 {
+		effects = append(effects, "reviewer_models="+strings.Join(r.ReviewerModels, ","))
+	}
+	if len(r.Executors) > 0 {
+		effects = append(effects, "executors="+strings.Join(r.Executors, ","))
+	}
+	return effects
+}
+
+// Input is what rule conditions can see.
+type Input struct {
+	Request        Request
+	Classification Classification
+	Session        Session
+	// Environment is the request's environment fingerprint.
+	Environment map[string]string
+	// Time is when the request is evaluated.
+	Time time.Time
+	// Reviewers are the other active sessions of the project.
+	Reviewers []Session
+}
+
+// Request describes the request being created.
+type Request struct {
+	Command     string
+	Cwd         string
+	ProjectPath string
+	Shell       bool
+	Reason      string
+	// Steps is the number of steps of a plan (0 for a single command).
+	Steps int
+	// Window is the named change window the request is limited to.
+	Window string
+}
+
+// Classification is the request's risk classification.
+type Classification struct {
+	Tier           string
+	MatchedPattern string
+	// MinApprovals is the tier's approvals before any rule applies.
+	MinApprovals int
+}
+
+// Session is an agent session.
+type Session struct {
+	Agent   string
+	Model   string
+	Program string
+}
+
+// Variables returns the variables rule conditions are evaluated with.
+func (p *Policy) Variables(in Input) map[string]any {
+	env := make(map[string]any, len(in.Environment))
+	for k, v := range in.Environment {
+		env[k] = v
+	}
+	t := in.Time
+	if t.IsZero() {
+		t = time.Now()
+	}
+	if p.loc != nil {
+		t = t.In(p.loc)
+	}
+	var agents, models, programs []any
+	seenModels := map[string]bool{}
+	for _, s := range in.Reviewers {
+		agents = append(agents, s.Agent)
+		programs = append(programs, s.Program)
+		if s.Model != "" && !seenModels[s.Model] {
+			seenModels[s.Model] = true
+			models = append(models, s.Model)
+		}
+	}
+	return map[string]any{
+		"request": map[string]any{
+			"command": in.Request.Command,
+			"cwd":     in.Request.Cwd,
+			"project": in.Request.ProjectPath,
+			"shell":   in.Request.Shell,
+			"reason":  in.Request.Reason,
+			"steps":   float64(in.Request.Steps),
+			"window":  in.Request.Window,
+		},
+		"classification": map[string]any{
+			"tier":          in.Classification.Tier,
+			"pattern":       in.Classification.MatchedPattern,
+			"min_approvals": float64(in.Classification.MinApprovals),
+		},
+		"session": map[string]any{
+			"agent":   in.Session.Agent,
+			"model":   in.Session.Model,
+			"program": in.Session.Program,
+		},
+		"env": env,
+		"time": map[string]any{
+			"hour":    float64(t.Hour()),
+			"minute":  float64(t.Minute()),
+			"weekday": strings.ToLower(t.Weekday().String()[:3]),
+			"date":    t.Format("2006-01-02"),
+		},
+		"reviewers": map[string]any{
+			"count":    float64(len(in.Reviewers)),
+			"agents":   list(agents),
+			"models":   list(models),
+			"programs": list(programs),
+		},
+	}
+}
+
+func list(items []any) []any {
+	if items == nil {
+		return []any{}
+	}
+	return items
+}
+
+// Fired is a rule whose condition held.
+type Fired struct {
+	Rule    string   `json:"rule"`
+	Reason  string   `json:"reason,omitempty"`
+	Effects []string `json:"effects"`
+}
+
+// Decision is the combined outcome of the rules that fired.
+type Decision struct {
+	Fired []Fired
+	// Denial is the first deny rule that fired (nil when allowed).
+	Denial *Fired
+	// MinApprovals is the approvals set by the default rules that fired (or
+	// the classification's when none did), raised by any higher
+	// min_approvals of the other rules. Those rules can only tighten.
+	MinApprovals          int
+	RequireDifferentModel bool
+	Reviewers             []db.PolicyConstraint
+	Executors             []db.PolicyConstraint
+}
+
+// Evaluate runs every rule against in. A condition that cannot be evaluated
+// is an error: a policy never silently skips a rule.
+func (p *Policy) Evaluate(in Input) (*Decision, error) {
+	d := &Decision{MinApprovals: in.Classification.MinApprovals}
+	vars := p.Variables(in)
+	baseSet := false
+	raised := 0
+	for i := range p.Rules {
+		r := &p.Rules[i]
+		if r.expr != nil {
+			ok, err := r.expr.EvalBool(vars)
+			if err != nil {
+				return nil, fmt.Errorf("policy rule %q: %w", r.Name, err)
+			}
+			if !ok {
+				continue
+			}
+		}
+
+		fired := Fired{Rule: r.Name, Reason: r.Reason, Effects: r.effects()}
+		d.Fired = append(d.Fired, fired)
+		if r.Deny && d.Denial == nil {
+			d.Denial = &fired
+		}
+		switch {
+		case r.MinApprovals == nil:
+		case r.base && (!baseSet || *r.MinApprovals > d.MinApprovals):
+			d.MinApprovals = *r.MinApprovals
+			baseSet = true
+		case !r.base && *r.MinApprovals > raised:
+			raised = *r.MinApprovals
+		}
+		if r.RequireDifferentModel {
+			d.RequireDifferentModel = true
+		}
+		if len(r.Reviewers) > 0 || len(r.ReviewerModels) > 0 {
+			d.Reviewers = append(d.Reviewers, db.PolicyConstraint{Rule: r.Name, Agents: r.Reviewers, Models: r.ReviewerModels})
+		}
+		if len(r.Executors) > 0 {
+			d.Executors = append(d.Executors, db.PolicyConstraint{Rule: r.Name, Agents: r.Executors})
+		}
+	}
+	if raised > d.MinApprovals {
+		d.MinApprovals = raised
+	}
+	return d, nil
+}
+
+// Err returns an error wrapping ErrDenied when a rule denied the request.
+func (d *Decision) Err() error {
+	if d.Denial == nil {
+		return nil
+	}
+	reason := d.Denial.Reason
+	if reason == "" {
+		reason = "no reason given"
+	}
+	return fmt.Errorf("%w: rule %q: %s", ErrDenied, d.Denial.Rule, reason)
+}
+
+// Record returns what the request keeps of the decision, or nil when no
+// rule fired.
+func (d *Decision) Record() *db.PolicyDecision {
+	if len(d.Fired) == 0 {
+		return nil
+	}
+	rec := &db.PolicyDecision{Reviewers: d.Reviewers, Executors: d.Executors}
+	for _, f := range d.Fired {
+		rec.Rules = append(rec.Rules, f.Rule)
+	}
+	return rec
+}
+
+// Defaults are the configuration keys the default policy is compiled from.
+type Defaults struct {
+	// TierApprovals maps a tier to its patterns.<tier>.min_approvals.
+	TierApprovals map[string]int
+	// RequireDifferentModel is general.require_different_model.
+	RequireDifferentModel bool
+	// Blocked is agents.blocked.
+	Blocked []string
+}
+
+// DefaultsFromConfig returns the keys of cfg the default policy is compiled
+// from.
+func DefaultsFromConfig(cfg config.Config) Defaults {
+	return Defaults{
+		TierApprovals: map[string]int{
+			string(db.RiskTierCritical):  cfg.Patterns.Critical.MinApprovals,
+			string(db.RiskTierDangerous): cfg.Patterns.Dangerous.MinApprovals,
+			string(db.RiskTierCaution):   cfg.Patterns.Caution.MinApprovals,
+		},
+		RequireDifferentModel: cfg.General.RequireDifferentModel,
+		Blocked:               cfg.Agents.Blocked,
+	}
+}
+
+// Default compiles configuration keys into the equivalent policy. Its rules
+// are named after the keys they come from.
+func Default(d Defaults) *Policy {
+	p := &Policy{}
+	if len(d.Blocked) > 0 {
+		quoted := make([]string, len(d.Blocked))
+		for i, agent := range d.Blocked {
+			quoted[i] = strconv.Quote(strings.ToLower(agent))
+		}
+		p.Rules = append(p.Rules, Rule{
+			Name:   "agents.blocked",
+			When:   "session.agent.lower() in [" + strings.Join(quoted, ", ") + "]",
+			Reason: "agent is blocked from creating requests",
+			Deny:   true,
+		})
+	}
+
+	tiers := make([]string, 0, len(d.TierApprovals))
+	for tier := range d.TierApprovals {
+		tiers = append(tiers, tier)
+	}
+	sort.Slice(tiers, func(i, j int) bool { return tierRank(tiers[i]) > tierRank(tiers[j]) })
+	for _, tier := range tiers {
+		n := d.TierApprovals[tier]
+		p.Rules = append(p.Rules, Rule{
+			Name:         "patterns." + tier + ".min_approvals",
+			When:         "classification.tier == " + strconv.Quote(tier),
+			MinApprovals: &n,
+		})
+	}
+
+	p.Rules = append(p.Rules, Rule{
+		Name:                  "critical.require_different_model",
+		When:                  `classification.tier == "critical"`,
+		Reason:                "critical requests are approved by a different model",
+		RequireDifferentModel: true,
+	})
+	if d.RequireDifferentModel {
+		p.Rules = append(p.Rules, Rule{
+			Name:                  "general.require_different_model",
+			RequireDifferentModel: true,
+		})
+	}
+
+	if err := p.Compile(); err != nil {
+		// The rules above are well formed by construction.
+		panic(err)
+	}
+	for i := range p.Rules {
+		p.Rules[i].base = true
+	}
+	return p
+}
+
+func tierRank(tier string) int {
+	switch tier {
+	case "critical":
+		return 3
+	case "dangerous":
+		return 2
+	case "caution":
+		return 1
+	}
+	return 0
+}
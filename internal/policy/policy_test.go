@@ -0,0 +1,146 @@
+package policy
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+const testPolicy = `
+timezone = "UTC"
+
+[[rule]]
+name = "prod"
+when = 'env["kube.context"].startsWith("prod")'
+min_approvals = 3
+reviewer_models = ["opus*"]
+executors = ["deploy-*"]
+
+[[rule]]
+name = "night"
+when = 'time.hour >= 22 && classification.tier == "critical"'
+deny = true
+reason = "no critical changes at night"
+`
+
+func TestParse(t *testing.T) {
+	p, err := Parse([]byte(testPolicy))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if len(p.Rules) != 2 || p.Rules[0].MinApprovals == nil || *p.Rules[0].MinApprovals != 3 {
+		t.Fatalf("rules = %+v", p.Rules)
+	}
+
+	for name, src := range map[string]string{
+		"unknown key":   "[[rule]]\nname = \"a\"\ndeny = true\nmin_approval = 2",
+		"no name":       "[[rule]]\ndeny = true",
+		"duplicate":     "[[rule]]\nname = \"a\"\ndeny = true\n[[rule]]\nname = \"a\"\ndeny = true",
+		"no effect":     "[[rule]]\nname = \"a\"\nwhen = \"true\"",
+		"bad condition": "[[rule]]\nname = \"a\"\ndeny = true\nwhen = \"session.agent ==\"",
+		"negative":      "[[rule]]\nname = \"a\"\nmin_approvals = -1",
+		"zero":          "[[rule]]\nname = \"a\"\nmin_approvals = 0",
+		"timezone":      "timezone = \"Mars/Olympus\"",
+	} {
+		if _, err := Parse([]byte(src)); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
+
+func TestEvaluate(t *testing.T) {
+	p, err := Parse([]byte(testPolicy))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	in := Input{
+		Classification: Classification{Tier: "dangerous", MinApprovals: 1},
+		Session:        Session{Agent: "BlueDog", Model: "gpt-5"},
+		Environment:    map[string]string{"kube.context": "prod-eu"},
+		Time:           time.Date(2026, 10, 16, 23, 0, 0, 0, time.UTC),
+	}
+
+	d, err := p.Evaluate(in)
+	if err != nil {
+		t.Fatalf("Evaluate: %v", err)
+	}
+	if d.Err() != nil || d.MinApprovals != 3 || len(d.Fired) != 1 || d.Fired[0].Rule != "prod" {
+		t.Fatalf("decision = %+v", d)
+	}
+	rec := d.Record()
+	if rule := rec.AllowsReviewer("RedCat", "gpt-5"); rule != "prod" {
+		t.Errorf("AllowsReviewer(gpt-5) = %q, want prod", rule)
+	}
+	if rule := rec.AllowsReviewer("RedCat", "Opus-4.5"); rule != "" {
+		t.Errorf("AllowsReviewer(opus) = %q", rule)
+	}
+	if rule := rec.AllowsExecutor("BlueDog", "gpt-5"); rule != "prod" {
+		t.Errorf("AllowsExecutor(BlueDog) = %q, want prod", rule)
+	}
+
+	in.Classification.Tier = "critical"
+	d, err = p.Evaluate(in)
+	if err != nil {
+		t.Fatalf("Evaluate: %v", err)
+	}
+	if err := d.Err(); !errors.Is(err, ErrDenied) || !strings.Contains(err.Error(), "no critical changes at night") {
+		t.Errorf("Err() = %v, want a denial", err)
+	}
+
+	in.Environment = nil
+	in.Time = time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	if d, err = p.Evaluate(in); err != nil || len(d.Fired) != 0 || d.MinApprovals != 1 || d.Record() != nil {
+		t.Errorf("no rule fired: decision = %+v, %v", d, err)
+	}
+}
+
+func TestDefault(t *testing.T) {
+	p := Default(Defaults{
+		TierApprovals: map[string]int{"critical": 2, "dangerous": 1, "caution": 0},
+		Blocked:       []string{"BadBot"},
+	})
+	for _, tc := range []struct {
+		tier, agent    string
+		approvals      int
+		differentModel bool
+		denied         bool
+	}{
+		{"critical", "a", 2, true, false},
+		{"dangerous", "a", 1, false, false},
+		{"caution", "a", 0, false, false},
+		{"dangerous", "badbot", 1, false, true},
+	} {
+		d, err := p.Evaluate(Input{Classification: Classification{Tier: tc.tier, MinApprovals: 9}, Session: Session{Agent: tc.agent}})
+		if err != nil {
+			t.Fatalf("Evaluate: %v", err)
+		}
+		if d.MinApprovals != tc.approvals || d.RequireDifferentModel != tc.differentModel || (d.Denial != nil) != tc.denied {
+			t.Errorf("%s/%s: decision = %+v", tc.tier, tc.agent, d)
+		}
+	}
+
+	// A policy file cannot replace a default rule.
+	file, err := Parse([]byte("[[rule]]\nname = \"patterns.critical.min_approvals\"\nmin_approvals = 1"))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if _, err := p.Extend(file); err == nil {
+		t.Error("Extend: expected an error for a default rule name")
+	}
+
+	// Its rules can raise the approvals of the default rules but not lower them.
+	file, err = Parse([]byte("[[rule]]\nname = \"more\"\nwhen = 'classification.tier == \"dangerous\"'\nmin_approvals = 3\n[[rule]]\nname = \"fewer\"\nmin_approvals = 1"))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	merged, err := p.Extend(file)
+	if err != nil {
+		t.Fatalf("Extend: %v", err)
+	}
+	for tier, want := range map[string]int{"critical": 2, "dangerous": 3, "caution": 1} {
+		if d, _ := merged.Evaluate(Input{Classification: Classification{Tier: tier}}); d.MinApprovals != want {
+			t.Errorf("extended %s approvals = %d, want %d", tier, d.MinApprovals, want)
+		}
+	}
+}
@@ -0,0 +1,18 @@
+// Package pty allocates pseudo-terminals for commands that behave
+// differently, or wait for input, when they are not attached to a terminal.
+package pty
+
+import "errors"
+
+// ErrUnsupported is returned when pseudo-terminals are not available on
+// this system.
+var ErrUnsupported = errors.New("pseudo-terminals are not supported on this system")
+
+// Winsize is a terminal size in character cells.
+type Winsize struct {
+	Rows uint16
+	Cols uint16
+}
+
+// DefaultSize is used when the size of slb's own terminal is unknown.
+var DefaultSize = Winsize{Rows: 24, Cols: 80}
@@ -0,0 +1,120 @@
+//go:build linux
+
+package pty
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"os/signal"
+	"strconv"
+	"syscall"
+
+	"golang.org/x/sys/unix"
+)
+
+// Start runs cmd attached to a new pseudo-terminal of the given size and
+// returns the master side. The command becomes the leader of a new session
+// with the terminal as its controlling terminal; its stdin, stdout and
+// stderr are the terminal unless already set. The caller closes the master
+// after the command has exited.
+func Start(cmd *exec.Cmd, size Winsize) (*os.File, error) {
+	master, slave, err := open()
+	if err != nil {
+		return nil, err
+	}
+	defer slave.Close()
+	if err := SetSize(master, size); err != nil {
+		master.Close()
+		return nil, err
+	}
+
+	if cmd.Stdin == nil {
+		cmd.Stdin = slave
+	}
+	if cmd.Stdout == nil {
+		cmd.Stdout = slave
+	}
+	if cmd.Stderr == nil {
+		cmd.Stderr = slave
+	}
+	if cmd.SysProcAttr == nil {
+		cmd.SysProcAttr = &syscall.SysProcAttr{}
+	}
+	cmd.SysProcAttr.Setsid = true
+	cmd.SysProcAttr.Setctty = true
+	cmd.SysProcAttr.Ctty = 0 // the child's stdin
+	if err := cmd.Start(); err != nil {
+		master.Close()
+		return nil, err
+	}
+	return master, nil
+}
+
+// Available reports whether a pseudo-terminal can be allocated.
+func Available() error {
+	master, slave, err := open()
+	if err != nil {
+		return err
+	}
+	slave.Close()
+	master.Close()
+	return nil
+}
+
+// open allocates a pseudo-terminal pair from /dev/ptmx.
+func open() (master, slave *os.File, err error) {
+	// A non-blocking master is registered with the runtime poller, so
+	// closing it interrupts a pending Read.
+	fd, err := unix.Open("/dev/ptmx", unix.O_RDWR|unix.O_NOCTTY|unix.O_CLOEXEC|unix.O_NONBLOCK, 0)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: opening /dev/ptmx: %v", ErrUnsupported, err)
+	}
+	master = os.NewFile(uintptr(fd), "/dev/ptmx")
+	if err := unix.IoctlSetPointerInt(fd, unix.TIOCSPTLCK, 0); err != nil {
+		master.Close()
+		return nil, nil, fmt.Errorf("unlocking pty: %w", err)
+	}
+	n, err := unix.IoctlGetInt(fd, unix.TIOCGPTN)
+	if err != nil {
+		master.Close()
+		return nil, nil, fmt.Errorf("getting pty number: %w", err)
+	}
+	name := "/dev/pts/" + strconv.Itoa(n)
+	sfd, err := unix.Open(name, unix.O_RDWR|unix.O_NOCTTY|unix.O_CLOEXEC, 0)
+	if err != nil {
+		master.Close()
+		return nil, nil, fmt.Errorf("opening %s: %w", name, err)
+	}
+	return master, os.NewFile(uintptr(sfd), name), nil
+}
+
+// GetSize returns the size of the terminal open as f.
+func GetSize(f *os.File) (Winsize, error) {
+	ws, err := unix.IoctlGetWinsize(int(f.Fd()), unix.TIOCGWINSZ)
+	if err != nil {
+		return Winsize{}, err
+	}
+	return Winsize{Rows: ws.Row, Cols: ws.Col}, nil
+}
+
+// SetSize resizes the terminal open as f.
+func SetSize(f *os.File, size Winsize) error {
+	return unix.IoctlSetWinsize(int(f.Fd()), unix.TIOCSWINSZ, &unix.Winsize{Row: size.Rows, Col: size.Cols})
+}
+
+// NotifyResize calls fn whenever slb's terminal is resized, until the
+// returned function is called.
+func NotifyResize(fn func()) (stop func()) {
+	c := make(chan os.Signal, 1)
+	signal.Notify(c, unix.SIGWINCH)
+	go func() {
+		for range c {
+			fn()
+		}
+	}()
+	return func() {
+		signal.Stop(c)
+		close(c)
+	}
+}
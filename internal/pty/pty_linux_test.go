@@ -0,0 +1,35 @@
+//go:build linux
+
+package pty
+
+import (
+	"io"
+	"os/exec"
+	"strings"
+	"testing"
+)
+
+func TestStart(t *testing.T) {
+	if err := Available(); err != nil {
+		t.Skipf("pty unavailable: %v", err)
+	}
+	cmd := exec.Command("/bin/sh", "-c", "stty size; tty")
+	master, err := Start(cmd, Winsize{Rows: 33, Cols: 101})
+	if err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	defer master.Close()
+
+	size, err := GetSize(master)
+	if err != nil || size != (Winsize{Rows: 33, Cols: 101}) {
+		t.Errorf("GetSize = %+v, %v", size, err)
+	}
+	if err := cmd.Wait(); err != nil {
+		t.Fatalf("Wait: %v", err)
+	}
+	// The slave is gone once the command exited: reads end with EIO.
+	out, _ := io.ReadAll(master)
+	if !strings.Contains(string(out), "33 101") || !strings.Contains(string(out), "/dev/pts/") {
+		t.Errorf("output %q", out)
+	}
+}
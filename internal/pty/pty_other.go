@@ -0,0 +1,33 @@
+//go:build !linux
+
+package pty
+
+import (
+	"os"
+	"os/exec"
+)
+
+// Available reports whether a pseudo-terminal can be allocated.
+func Available() error {
+	return ErrUnsupported
+}
+
+// Start runs cmd attached to a new pseudo-terminal; unsupported here.
+func Start(*exec.Cmd, Winsize) (*os.File, error) {
+	return nil, ErrUnsupported
+}
+
+// GetSize returns the size of the terminal open as f.
+func GetSize(*os.File) (Winsize, error) {
+	return Winsize{}, ErrUnsupported
+}
+
+// SetSize resizes the terminal open as f.
+func SetSize(*os.File, Winsize) error {
+	return ErrUnsupported
+}
+
+// NotifyResize calls fn whenever slb's terminal is resized; unsupported here.
+func NotifyResize(func()) (stop func()) {
+	return func() {}
+}
@@ -0,0 +1,100 @@
+package report
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+)
+
+// WriteCSV writes the report in long format: one section,key,metric,value
+// row per figure, so every section fits a single sheet.
+func WriteCSV(w io.Writer, r *Report) error {
+	cw := csv.NewWriter(w)
+	row := func(section, key, metric string, value any) {
+		_ = cw.Write([]string{section, key, metric, csvValue(value)})
+	}
+
+	row("section", "key", "metric", "value")
+	row("summary", "", "generated_at", r.GeneratedAt)
+	row("summary", "", "project", r.Project)
+	row("summary", "", "agent", r.Agent)
+	if r.Since != nil {
+		row("summary", "", "since", *r.Since)
+	}
+	if r.Until != nil {
+		row("summary", "", "until", *r.Until)
+	}
+	row("summary", "", "query", r.Query)
+	row("summary", "", "total_requests", r.TotalRequests)
+	row("summary", "", "problem_outcomes", len(r.Problems))
+	row("summary", "", "emergency_executions", len(r.Emergencies))
+
+	for _, c := range r.ByTier {
+		row("tier", c.Key, "requests", c.Count)
+	}
+	for _, c := range r.ByStatus {
+		row("status", c.Key, "requests", c.Count)
+	}
+	for _, d := range r.Daily {
+		row("day", d.Day, "requests", d.Total)
+		for _, tier := range tierOrder {
+			if n := d.ByTier[tier]; n > 0 {
+				row("day", d.Day, tier, n)
+			}
+		}
+	}
+
+	l := r.ApprovalLatency
+	row("approval_latency", "", "sample_size", l.SampleSize)
+	row("approval_latency", "", "avg_minutes", l.AvgMinutes)
+	row("approval_latency", "", "median_minutes", l.MedianMinutes)
+	row("approval_latency", "", "min_minutes", l.MinMinutes)
+	row("approval_latency", "", "max_minutes", l.MaxMinutes)
+
+	for _, a := range r.Agents {
+		row("agent", a.Agent, "total_requests", a.TotalRequests)
+		row("agent", a.Agent, "approved", a.ApprovedCount)
+		row("agent", a.Agent, "rejected", a.RejectedCount)
+		row("agent", a.Agent, "executed", a.ExecutedCount)
+		row("agent", a.Agent, "problematic_pct", a.ProblematicPct)
+	}
+
+	for _, p := range r.Problems {
+		row("problem", p.RequestID, "command", p.Command)
+		row("problem", p.RequestID, "agent", p.Agent)
+		row("problem", p.RequestID, "risk_tier", p.RiskTier)
+		if p.ExitCode != nil {
+			row("problem", p.RequestID, "exit_code", *p.ExitCode)
+		}
+		row("problem", p.RequestID, "description", p.Description)
+		row("problem", p.RequestID, "reported_at", p.ReportedAt)
+	}
+
+	for _, e := range r.Emergencies {
+		key := e.ExecutedAt.Format(time.RFC3339)
+		row("emergency", key, "actor", e.Actor)
+		row("emergency", key, "command", e.Command)
+		row("emergency", key, "reason", e.Reason)
+		row("emergency", key, "cwd", e.Cwd)
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+func csvValue(v any) string {
+	switch v := v.(type) {
+	case string:
+		return v
+	case int:
+		return strconv.Itoa(v)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case time.Time:
+		return v.UTC().Format(time.RFC3339)
+	default:
+		return fmt.Sprint(v)
+	}
+}
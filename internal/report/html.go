@@ -0,0 +1,112 @@
+package report
+
+import (
+	_ "embed"
+	"html/template"
+	"io"
+	"time"
+)
+
+// reportTemplate is a single self-contained page: inline CSS, charts drawn
+// as plain HTML bars and inline SVG, no scripts or external assets, so the
+// file can be archived or mailed as-is.
+//
+//go:embed report.html.tmpl
+var reportTemplate string
+
+var htmlTemplate = template.Must(template.New("report").Funcs(template.FuncMap{
+	"pct": func(n, max int) float64 {
+		if max <= 0 {
+			return 0
+		}
+		return float64(n) * 100 / float64(max)
+	},
+	"last": func(bars []chartBar) int { return len(bars) - 1 },
+	"when": func(t time.Time) string { return t.UTC().Format("2006-01-02 15:04 UTC") },
+	"minutes": func(m float64) string {
+		return (time.Duration(m * float64(time.Minute))).Round(time.Second).String()
+	},
+}).Parse(reportTemplate))
+
+// chartBar is one stacked column of the daily chart, in SVG units.
+type chartBar struct {
+	Day      string
+	Total    int
+	X, Width float64
+	Segments []chartSegment
+}
+
+type chartSegment struct {
+	Tier      string
+	Count     int
+	Y, Height float64
+}
+
+const (
+	chartWidth  = 720.0
+	chartHeight = 160.0
+)
+
+// dailyChart lays out the daily series as stacked columns, one per day.
+func dailyChart(days []DayCount) []chartBar {
+	if len(days) == 0 {
+		return nil
+	}
+	peak := 0
+	for _, d := range days {
+		peak = max(peak, d.Total)
+	}
+	if peak == 0 {
+		peak = 1
+	}
+	slot := chartWidth / float64(len(days))
+	bars := make([]chartBar, 0, len(days))
+	for i, d := range days {
+		bar := chartBar{Day: d.Day, Total: d.Total, X: float64(i)*slot + slot*0.1, Width: slot * 0.8}
+		y := chartHeight
+		for _, tier := range tierOrder {
+			n := d.ByTier[tier]
+			if n == 0 {
+				continue
+			}
+			h := float64(n) / float64(peak) * chartHeight
+			y -= h
+			bar.Segments = append(bar.Segments, chartSegment{Tier: tier, Count: n, Y: y, Height: h})
+		}
+		bars = append(bars, bar)
+	}
+	return bars
+}
+
+func maxCount(counts []Count) int {
+	m := 0
+	for _, c := range counts {
+		m = max(m, c.Count)
+	}
+	return m
+}
+
+// WriteHTML writes the report as a self-contained HTML page with charts.
+func WriteHTML(w io.Writer, r *Report) error {
+	agentPeak := 0
+	for _, a := range r.Agents {
+		agentPeak = max(agentPeak, a.TotalRequests)
+	}
+	return htmlTemplate.Execute(w, struct {
+		*Report
+		TierPeak    int
+		StatusPeak  int
+		AgentPeak   int
+		Chart       []chartBar
+		ChartWidth  float64
+		ChartHeight float64
+	}{
+		Report:      r,
+		TierPeak:    maxCount(r.ByTier),
+		StatusPeak:  maxCount(r.ByStatus),
+		AgentPeak:   agentPeak,
+		Chart:       dailyChart(r.Daily),
+		ChartWidth:  chartWidth,
+		ChartHeight: chartHeight,
+	})
+}
@@ -0,0 +1,82 @@
+package report
+
+import (
+	"encoding/json"
+	"io"
+	"time"
+
+	"github.com/Dicklesworthstone/slb/internal/db"
+)
+
+// WriteJSONL writes the report as JSON lines. Each line carries a "type" of
+// summary, day, agent, problem or emergency; the summary line comes first.
+func WriteJSONL(w io.Writer, r *Report) error {
+	enc := json.NewEncoder(w)
+
+	summary := struct {
+		Type            string                 `json:"type"`
+		GeneratedAt     time.Time              `json:"generated_at"`
+		Project         string                 `json:"project,omitempty"`
+		Agent           string                 `json:"agent,omitempty"`
+		Since           *time.Time             `json:"since,omitempty"`
+		Until           *time.Time             `json:"until,omitempty"`
+		Query           string                 `json:"query,omitempty"`
+		TotalRequests   int                    `json:"total_requests"`
+		ByTier          []Count                `json:"by_tier"`
+		ByStatus        []Count                `json:"by_status"`
+		ApprovalLatency db.TimeToApprovalStats `json:"approval_latency"`
+		Problems        int                    `json:"problem_outcomes"`
+		Emergencies     int                    `json:"emergency_executions"`
+	}{
+		Type:            "summary",
+		GeneratedAt:     r.GeneratedAt,
+		Project:         r.Project,
+		Agent:           r.Agent,
+		Since:           r.Since,
+		Until:           r.Until,
+		Query:           r.Query,
+		TotalRequests:   r.TotalRequests,
+		ByTier:          r.ByTier,
+		ByStatus:        r.ByStatus,
+		ApprovalLatency: r.ApprovalLatency,
+		Problems:        len(r.Problems),
+		Emergencies:     len(r.Emergencies),
+	}
+	if err := enc.Encode(summary); err != nil {
+		return err
+	}
+
+	for _, d := range r.Daily {
+		if err := enc.Encode(struct {
+			Type string `json:"type"`
+			DayCount
+		}{"day", d}); err != nil {
+			return err
+		}
+	}
+	for _, a := range r.Agents {
+		if err := enc.Encode(struct {
+			Type string `json:"type"`
+			AgentStats
+		}{"agent", a}); err != nil {
+			return err
+		}
+	}
+	for _, p := range r.Problems {
+		if err := enc.Encode(struct {
+			Type string `json:"type"`
+			ProblemOutcome
+		}{"problem", p}); err != nil {
+			return err
+		}
+	}
+	for _, e := range r.Emergencies {
+		if err := enc.Encode(struct {
+			Type string `json:"type"`
+			EmergencyExecution
+		}{"emergency", e}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
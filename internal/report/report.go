@@ -0,0 +1,386 @@
+// Package report builds period reports over request history and renders them
+// as CSV, JSONL or a self-contained HTML page.
+package report
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Dicklesworthstone/slb/internal/db"
+)
+
+// Options selects the requests a report covers.
+type Options struct {
+	// ProjectPath limits the report to one project; empty means every project
+	// in the database.
+	ProjectPath string
+	// Agent limits the report to one requestor agent (and emergency
+	// executions by that actor).
+	Agent string
+	// Since and Until bound the period; zero values leave it open.
+	Since time.Time
+	Until time.Time
+	// Query is an additional history query, e.g. "tier:critical".
+	Query        string
+	SavedQueries map[string]string
+	// EmergencyLogDir holds the emergency_*.log files written by
+	// slb emergency-execute. Empty skips emergency executions.
+	EmergencyLogDir string
+	// Now is the report time; zero means time.Now().
+	Now time.Time
+}
+
+// Count is a labelled count.
+type Count struct {
+	Key   string `json:"key"`
+	Count int    `json:"count"`
+}
+
+// DayCount is the number of requests created on one day, by tier.
+type DayCount struct {
+	Day    string         `json:"day"`
+	Total  int            `json:"total"`
+	ByTier map[string]int `json:"by_tier"`
+}
+
+// AgentStats is the request summary for one requestor agent.
+type AgentStats struct {
+	Agent string `json:"agent"`
+	db.RequestStats
+}
+
+// ProblemOutcome is an executed request whose outcome reported problems.
+type ProblemOutcome struct {
+	RequestID   string           `json:"request_id"`
+	Command     string           `json:"command"`
+	Agent       string           `json:"agent"`
+	ProjectPath string           `json:"project_path"`
+	RiskTier    db.RiskTier      `json:"risk_tier"`
+	Status      db.RequestStatus `json:"status"`
+	ExitCode    *int             `json:"exit_code,omitempty"`
+	Description string           `json:"description,omitempty"`
+	CreatedAt   time.Time        `json:"created_at"`
+	ReportedAt  time.Time        `json:"reported_at"`
+}
+
+// EmergencyExecution is one slb emergency-execute run, read from its log.
+type EmergencyExecution struct {
+	ExecutedAt time.Time `json:"executed_at"`
+	Actor      string    `json:"actor"`
+	Command    string    `json:"command"`
+	Hash       string    `json:"hash"`
+	Reason     string    `json:"reason"`
+	Cwd        string    `json:"cwd"`
+	LogPath    string    `json:"log_path"`
+}
+
+// Report is a period report over request history.
+type Report struct {
+	GeneratedAt time.Time  `json:"generated_at"`
+	Project     string     `json:"project,omitempty"`
+	Agent       string     `json:"agent,omitempty"`
+	Since       *time.Time `json:"since,omitempty"`
+	Until       *time.Time `json:"until,omitempty"`
+	Query       string     `json:"query,omitempty"`
+
+	TotalRequests   int                    `json:"total_requests"`
+	ByTier          []Count                `json:"by_tier"`
+	ByStatus        []Count                `json:"by_status"`
+	Daily           []DayCount             `json:"daily"`
+	ApprovalLatency db.TimeToApprovalStats `json:"approval_latency"`
+	Agents          []AgentStats           `json:"agents"`
+	Problems        []ProblemOutcome       `json:"problems"`
+	Emergencies     []EmergencyExecution   `json:"emergency_executions"`
+}
+
+// tierOrder is the display order of risk tiers.
+var tierOrder = []string{
+	string(db.RiskTierCritical), string(db.RiskTierDangerous), string(db.RiskTierCaution),
+}
+
+// Build queries the database and emergency logs for the report.
+func Build(database *db.DB, opts Options) (*Report, error) {
+	now := opts.Now
+	if now.IsZero() {
+		now = time.Now()
+	}
+	r := &Report{
+		GeneratedAt: now.UTC(),
+		Project:     opts.ProjectPath,
+		Agent:       opts.Agent,
+		Query:       opts.Query,
+		ByTier:      []Count{},
+		ByStatus:    []Count{},
+		Daily:       []DayCount{},
+		Agents:      []AgentStats{},
+		Problems:    []ProblemOutcome{},
+		Emergencies: []EmergencyExecution{},
+	}
+	if !opts.Since.IsZero() {
+		since := opts.Since.UTC()
+		r.Since = &since
+	}
+	if !opts.Until.IsZero() {
+		until := opts.Until.UTC()
+		r.Until = &until
+	}
+
+	q, err := db.ParseRequestQuery(queryString(opts), opts.SavedQueries)
+	if err != nil {
+		return nil, fmt.Errorf("parsing query: %w", err)
+	}
+	qopts := db.QueryOptions{ProjectPath: opts.ProjectPath, Now: now}
+
+	counts, err := database.CountRequestsMatching(q, qopts)
+	if err != nil {
+		return nil, err
+	}
+	r.tally(counts)
+
+	latency, err := database.GetTimeToApprovalStatsMatching(q, qopts)
+	if err != nil {
+		return nil, fmt.Errorf("approval latency: %w", err)
+	}
+	r.ApprovalLatency = *latency
+
+	agents, err := database.ListAgentsMatching(q, qopts)
+	if err != nil {
+		return nil, err
+	}
+	for _, agent := range agents {
+		stats, err := database.GetRequestStatsByAgentMatching(agent, q, qopts)
+		if err != nil {
+			return nil, fmt.Errorf("stats for %s: %w", agent, err)
+		}
+		r.Agents = append(r.Agents, AgentStats{Agent: agent, RequestStats: *stats})
+	}
+
+	if r.Problems, err = loadProblems(database, q, qopts); err != nil {
+		return nil, err
+	}
+
+	if opts.EmergencyLogDir != "" {
+		emergencies, err := ReadEmergencyLogs(opts.EmergencyLogDir)
+		if err != nil {
+			return nil, err
+		}
+		for _, e := range emergencies {
+			if (r.Since != nil && e.ExecutedAt.Before(*r.Since)) || (r.Until != nil && !e.ExecutedAt.Before(*r.Until)) {
+				continue
+			}
+			if opts.Agent != "" && !strings.EqualFold(e.Actor, opts.Agent) {
+				continue
+			}
+			r.Emergencies = append(r.Emergencies, e)
+		}
+	}
+	return r, nil
+}
+
+// queryString turns the options into a history query.
+func queryString(opts Options) string {
+	parts := []string{opts.Query}
+	if opts.Agent != "" {
+		parts = append(parts, `agent:"`+opts.Agent+`"`)
+	}
+	if !opts.Since.IsZero() {
+		parts = append(parts, "since:"+opts.Since.UTC().Format(time.RFC3339))
+	}
+	if !opts.Until.IsZero() {
+		parts = append(parts, "until:"+opts.Until.UTC().Format(time.RFC3339))
+	}
+	return strings.TrimSpace(strings.Join(parts, " "))
+}
+
+// tally fills the totals, tier/status breakdowns and daily series.
+func (r *Report) tally(counts []db.RequestCount) {
+	tiers := map[string]int{}
+	statuses := map[string]int{}
+	days := map[string]*DayCount{}
+	for _, c := range counts {
+		r.TotalRequests += c.Count
+		tiers[string(c.RiskTier)] += c.Count
+		statuses[string(c.Status)] += c.Count
+		d := days[c.Day]
+		if d == nil {
+			d = &DayCount{Day: c.Day, ByTier: map[string]int{}}
+			days[c.Day] = d
+		}
+		d.Total += c.Count
+		d.ByTier[string(c.RiskTier)] += c.Count
+	}
+
+	for _, tier := range tierOrder {
+		r.ByTier = append(r.ByTier, Count{Key: tier, Count: tiers[tier]})
+		delete(tiers, tier)
+	}
+	r.ByTier = append(r.ByTier, sortedCounts(tiers)...)
+	r.ByStatus = append(r.ByStatus, sortedCounts(statuses)...)
+
+	if len(days) == 0 {
+		return
+	}
+	keys := make([]string, 0, len(days))
+	for k := range days {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	// Fill the days without requests so the series has no gaps.
+	first, err1 := time.Parse("2006-01-02", keys[0])
+	last, err2 := time.Parse("2006-01-02", keys[len(keys)-1])
+	if err1 != nil || err2 != nil {
+		for _, k := range keys {
+			r.Daily = append(r.Daily, *days[k])
+		}
+		return
+	}
+	for day := first; !day.After(last); day = day.AddDate(0, 0, 1) {
+		k := day.Format("2006-01-02")
+		if d := days[k]; d != nil {
+			r.Daily = append(r.Daily, *d)
+		} else {
+			r.Daily = append(r.Daily, DayCount{Day: k, ByTier: map[string]int{}})
+		}
+	}
+}
+
+// sortedCounts orders counts by size, then key.
+func sortedCounts(m map[string]int) []Count {
+	out := make([]Count, 0, len(m))
+	for k, v := range m {
+		out = append(out, Count{Key: k, Count: v})
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Count != out[j].Count {
+			return out[i].Count > out[j].Count
+		}
+		return out[i].Key < out[j].Key
+	})
+	return out
+}
+
+// loadProblems lists the matching requests whose outcome reported problems.
+func loadProblems(database *db.DB, q db.RequestQuery, opts db.QueryOptions) ([]ProblemOutcome, error) {
+	q.Terms = append(append([]db.QueryTerm(nil), q.Terms...), db.QueryTerm{Field: "problems", Op: "=", Value: "true"})
+	problems := []ProblemOutcome{}
+	for {
+		page, err := database.QueryRequests(q, opts)
+		if err != nil {
+			return nil, fmt.Errorf("listing problem outcomes: %w", err)
+		}
+		for _, req := range page.Requests {
+			outcome, err := database.GetOutcomeForRequest(req.ID)
+			if errors.Is(err, db.ErrOutcomeNotFound) {
+				continue
+			}
+			if err != nil {
+				return nil, fmt.Errorf("loading outcome for %s: %w", req.ID, err)
+			}
+			p := ProblemOutcome{
+				RequestID:   req.ID,
+				Command:     req.Command.Raw,
+				Agent:       req.RequestorAgent,
+				ProjectPath: req.ProjectPath,
+				RiskTier:    req.RiskTier,
+				Status:      req.Status,
+				Description: outcome.ProblemDescription,
+				CreatedAt:   req.CreatedAt,
+				ReportedAt:  outcome.CreatedAt,
+			}
+			if req.Command.DisplayRedacted != "" {
+				p.Command = req.Command.DisplayRedacted
+			}
+			if req.Execution != nil {
+				p.ExitCode = req.Execution.ExitCode
+			}
+			problems = append(problems, p)
+		}
+		if page.NextCursor == "" {
+			return problems, nil
+		}
+		opts.Cursor = page.NextCursor
+	}
+}
+
+// ReadEmergencyLogs parses the headers of the emergency_*.log files in dir,
+// oldest first. A missing directory yields no executions.
+func ReadEmergencyLogs(dir string) ([]EmergencyExecution, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "emergency_*.log"))
+	if err != nil {
+		return nil, err
+	}
+	var out []EmergencyExecution
+	for _, path := range paths {
+		e, err := readEmergencyLog(path)
+		if err != nil {
+			return nil, err
+		}
+		if e != nil {
+			out = append(out, *e)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].ExecutedAt.Before(out[j].ExecutedAt) })
+	return out, nil
+}
+
+// readEmergencyLog reads the header block written by slb emergency-execute:
+//
+//	=== EMERGENCY EXECUTION ===
+//	Time:    <RFC3339>
+//	Actor:   ...
+//	Command: ...
+//	Hash:    ...
+//	Reason:  ...
+//	CWD:     ...
+//	============================
+//
+// It returns nil for files without a valid header.
+func readEmergencyLog(path string) (*EmergencyExecution, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading emergency log: %w", err)
+	}
+	defer f.Close()
+
+	e := &EmergencyExecution{LogPath: path}
+	sc := bufio.NewScanner(f)
+	sc.Buffer(make([]byte, 64*1024), 1024*1024)
+	for line := 0; sc.Scan() && line < 10; line++ {
+		key, value, ok := strings.Cut(sc.Text(), ":")
+		if !ok {
+			if line > 0 && strings.HasPrefix(sc.Text(), "====") {
+				break
+			}
+			continue
+		}
+		value = strings.TrimSpace(value)
+		switch key {
+		case "Time":
+			e.ExecutedAt, _ = time.Parse(time.RFC3339, value)
+		case "Actor":
+			e.Actor = value
+		case "Command":
+			e.Command = value
+		case "Hash":
+			e.Hash = value
+		case "Reason":
+			e.Reason = value
+		case "CWD":
+			e.Cwd = value
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return nil, fmt.Errorf("reading emergency log: %w", err)
+	}
+	if e.ExecutedAt.IsZero() || e.Command == "" {
+		return nil, nil
+	}
+	e.ExecutedAt = e.ExecutedAt.UTC()
+	return e, nil
+}
@@ -0,0 +1,244 @@
+package report
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Dicklesworthstone/slb/internal/db"
+	"github.com/Dicklesworthstone/slb/internal/testutil"
+)
+
+var day1 = time.Date(2025, 6, 1, 9, 0, 0, 0, time.UTC)
+
+// buildFixture creates two agents' requests across three days (one outside
+// the window), one problem outcome and two emergency logs.
+func buildFixture(t *testing.T) (*db.DB, Options) {
+	t.Helper()
+	database := testutil.NewTestDB(t)
+	project := "/work/project"
+
+	blue := testutil.MakeSession(t, database, testutil.WithProject(project), testutil.WithAgent("BlueLake"))
+	red := testutil.MakeSession(t, database, testutil.WithProject(project), testutil.WithAgent("RedRiver"))
+	create := func(s *db.Session, tier db.RiskTier, status db.RequestStatus, at time.Time) *db.Request {
+		r := testutil.MakeRequest(t, database, s,
+			testutil.WithRisk(tier), testutil.WithStatus(status),
+			testutil.WithCommand("rm -rf ./build", project, false))
+		if _, err := database.Exec(`UPDATE requests SET created_at = ? WHERE id = ?`, at.Format(time.RFC3339), r.ID); err != nil {
+			t.Fatalf("update created_at: %v", err)
+		}
+		return r
+	}
+
+	broken := create(blue, db.RiskTierCritical, db.StatusExecuted, day1)
+	create(blue, db.RiskTierDangerous, db.StatusRejected, day1.Add(time.Hour))
+	create(red, db.RiskTierCaution, db.StatusPending, day1.Add(48*time.Hour))
+	create(red, db.RiskTierCritical, db.StatusPending, day1.AddDate(0, -2, 0))
+
+	if err := database.CreateOutcome(&db.ExecutionOutcome{
+		RequestID: broken.ID, CausedProblems: true, ProblemDescription: "deleted the cache too",
+	}); err != nil {
+		t.Fatalf("CreateOutcome: %v", err)
+	}
+
+	logDir := t.TempDir()
+	writeLog := func(name string, at time.Time, actor string) {
+		body := "=== EMERGENCY EXECUTION ===\n" +
+			"Time:    " + at.Format(time.RFC3339) + "\n" +
+			"Actor:   " + actor + "\n" +
+			"Command: git push --force\n" +
+			"Hash:    abc123\n" +
+			"Reason:  prod is down\n" +
+			"CWD:     " + project + "\n" +
+			"============================\n\n" +
+			"Command: not part of the header\n"
+		if err := os.WriteFile(filepath.Join(logDir, name), []byte(body), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	writeLog("emergency_20250601-120000.log", day1.Add(3*time.Hour), "BlueLake")
+	writeLog("emergency_20250301-120000.log", day1.AddDate(0, -3, 0), "BlueLake")
+	if err := os.WriteFile(filepath.Join(logDir, "emergency_garbage.log"), []byte("no header"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	return database, Options{
+		ProjectPath:     project,
+		Since:           day1.AddDate(0, 0, -7),
+		Until:           day1.AddDate(0, 0, 7),
+		EmergencyLogDir: logDir,
+		Now:             day1.AddDate(0, 0, 7),
+	}
+}
+
+func TestBuild(t *testing.T) {
+	database, opts := buildFixture(t)
+
+	r, err := Build(database, opts)
+	if err != nil {
+		t.Fatalf("Build: %v", err)
+	}
+	if r.TotalRequests != 3 {
+		t.Errorf("TotalRequests = %d, want 3", r.TotalRequests)
+	}
+	wantTiers := []Count{{"critical", 1}, {"dangerous", 1}, {"caution", 1}}
+	for i, c := range wantTiers {
+		if r.ByTier[i] != c {
+			t.Errorf("ByTier[%d] = %+v, want %+v", i, r.ByTier[i], c)
+		}
+	}
+	if len(r.ByStatus) != 3 || r.ByStatus[0].Key != "executed" {
+		t.Errorf("ByStatus = %+v", r.ByStatus)
+	}
+	// Three days from the first to the last request, with the gap filled.
+	if len(r.Daily) != 3 || r.Daily[0].Total != 2 || r.Daily[1].Total != 0 || r.Daily[2].Total != 1 {
+		t.Errorf("Daily = %+v", r.Daily)
+	}
+	if len(r.Agents) != 2 || r.Agents[0].Agent != "BlueLake" || r.Agents[0].TotalRequests != 2 || r.Agents[1].TotalRequests != 1 {
+		t.Errorf("Agents = %+v", r.Agents)
+	}
+	if len(r.Problems) != 1 || r.Problems[0].Description != "deleted the cache too" || r.Problems[0].Agent != "BlueLake" {
+		t.Errorf("Problems = %+v", r.Problems)
+	}
+	if len(r.Emergencies) != 1 || r.Emergencies[0].Command != "git push --force" || r.Emergencies[0].Reason != "prod is down" {
+		t.Errorf("Emergencies = %+v", r.Emergencies)
+	}
+
+	opts.Agent = "redriver"
+	r, err = Build(database, opts)
+	if err != nil {
+		t.Fatalf("Build with agent: %v", err)
+	}
+	if r.TotalRequests != 1 || len(r.Problems) != 0 || len(r.Emergencies) != 0 {
+		t.Errorf("agent filter: total=%d problems=%d emergencies=%d", r.TotalRequests, len(r.Problems), len(r.Emergencies))
+	}
+
+	opts.Agent = ""
+	opts.Query = "tier:>=dangerous"
+	r, err = Build(database, opts)
+	if err != nil {
+		t.Fatalf("Build with query: %v", err)
+	}
+	if r.TotalRequests != 2 {
+		t.Errorf("query filter: total=%d, want 2", r.TotalRequests)
+	}
+
+	opts.Query = "bogus:1"
+	if _, err := Build(database, opts); err == nil {
+		t.Error("expected error for invalid query")
+	}
+}
+
+func TestBuild_AllProjects(t *testing.T) {
+	database, opts := buildFixture(t)
+	other := testutil.MakeSession(t, database, testutil.WithProject("/work/other"), testutil.WithAgent("GreenHill"))
+	r := testutil.MakeRequest(t, database, other)
+	if _, err := database.Exec(`UPDATE requests SET created_at = ? WHERE id = ?`, day1.Format(time.RFC3339), r.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	opts.ProjectPath = ""
+	rep, err := Build(database, opts)
+	if err != nil {
+		t.Fatalf("Build: %v", err)
+	}
+	if rep.TotalRequests != 4 || len(rep.Agents) != 3 {
+		t.Errorf("all projects: total=%d agents=%d", rep.TotalRequests, len(rep.Agents))
+	}
+}
+
+func TestWriters(t *testing.T) {
+	database, opts := buildFixture(t)
+	r, err := Build(database, opts)
+	if err != nil {
+		t.Fatalf("Build: %v", err)
+	}
+
+	t.Run("csv", func(t *testing.T) {
+		var buf bytes.Buffer
+		if err := WriteCSV(&buf, r); err != nil {
+			t.Fatalf("WriteCSV: %v", err)
+		}
+		rows, err := csv.NewReader(&buf).ReadAll()
+		if err != nil {
+			t.Fatalf("parsing CSV: %v", err)
+		}
+		found := map[string]string{}
+		for _, row := range rows {
+			if len(row) != 4 {
+				t.Fatalf("row %v has %d columns", row, len(row))
+			}
+			found[row[0]+"/"+row[1]+"/"+row[2]] = row[3]
+		}
+		for key, want := range map[string]string{
+			"summary//total_requests": "3",
+			"tier/critical/requests":  "1",
+			"day/2025-06-01/requests": "2",
+			"agent/BlueLake/rejected": "1",
+			"summary//since":          "2025-05-25T09:00:00Z",
+			"emergency/" + r.Emergencies[0].ExecutedAt.Format(time.RFC3339) + "/actor": "BlueLake",
+		} {
+			if found[key] != want {
+				t.Errorf("%s = %q, want %q", key, found[key], want)
+			}
+		}
+	})
+
+	t.Run("jsonl", func(t *testing.T) {
+		var buf bytes.Buffer
+		if err := WriteJSONL(&buf, r); err != nil {
+			t.Fatalf("WriteJSONL: %v", err)
+		}
+		types := map[string]int{}
+		sc := bufio.NewScanner(&buf)
+		first := ""
+		for sc.Scan() {
+			var line map[string]any
+			if err := json.Unmarshal(sc.Bytes(), &line); err != nil {
+				t.Fatalf("line %q: %v", sc.Text(), err)
+			}
+			typ, _ := line["type"].(string)
+			if first == "" {
+				first = typ
+			}
+			types[typ]++
+			if typ == "agent" && line["agent"] == nil {
+				t.Errorf("agent line without agent: %v", line)
+			}
+		}
+		if first != "summary" {
+			t.Errorf("first line type = %q, want summary", first)
+		}
+		want := map[string]int{"summary": 1, "day": 3, "agent": 2, "problem": 1, "emergency": 1}
+		for k, v := range want {
+			if types[k] != v {
+				t.Errorf("%s lines = %d, want %d", k, types[k], v)
+			}
+		}
+	})
+
+	t.Run("html", func(t *testing.T) {
+		r.Problems[0].Description = "<script>alert(1)</script>"
+		var buf bytes.Buffer
+		if err := WriteHTML(&buf, r); err != nil {
+			t.Fatalf("WriteHTML: %v", err)
+		}
+		out := buf.String()
+		for _, want := range []string{"<svg", `class="tier-critical"`, "BlueLake", "git push --force", "&lt;script&gt;"} {
+			if !strings.Contains(out, want) {
+				t.Errorf("HTML missing %q", want)
+			}
+		}
+		for _, bad := range []string{"<script>", "src=", "href="} {
+			if strings.Contains(out, bad) {
+				t.Errorf("HTML is not self-contained or unescaped: contains %q", bad)
+			}
+		}
+	})
+}
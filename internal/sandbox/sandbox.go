@@ -0,0 +1,153 @@
+// Package sandbox runs commands with restricted writes, network and
+// resources. On Linux the restrictions are applied by re-executing the slb
+// binary as a small helper that confines itself with Landlock and rlimits
+// before exec'ing the command, so the command and all its children inherit
+// them.
+package sandbox
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// ErrUnsupported is returned when sandboxing is not available on this
+// system.
+var ErrUnsupported = errors.New("sandboxed execution is not supported on this system")
+
+// ErrSetup is returned when the helper could not confine itself; the
+// command was not run.
+var ErrSetup = errors.New("sandbox setup failed")
+
+const (
+	// helperArg marks a re-exec of the binary as the sandbox helper.
+	helperArg = "__slb-sandbox-exec"
+	// probeArg asks the helper to exit immediately; used to test whether a
+	// network namespace can be created.
+	probeArg = "--probe"
+	// policyEnv carries the JSON policy to the helper.
+	policyEnv = "SLB_SANDBOX_POLICY"
+	// setupFailedPrefix starts the helper's error message; the helper then
+	// exits with setupFailedCode.
+	setupFailedPrefix = "slb sandbox: "
+	setupFailedCode   = 126
+)
+
+// Policy is what a sandboxed command may do.
+type Policy struct {
+	// WritePaths are the only paths the command may write beneath.
+	WritePaths []string `json:"write_paths"`
+	// DisableNetwork cuts the command off from the network.
+	DisableNetwork bool `json:"disable_network,omitempty"`
+	// CPUSeconds limits CPU time (0 = unlimited).
+	CPUSeconds int `json:"cpu_seconds,omitempty"`
+	// MemoryMB limits the address space (0 = unlimited).
+	MemoryMB int `json:"memory_mb,omitempty"`
+
+	// LandlockNetwork is set by Command when the network is disabled through
+	// Landlock's TCP rules because no network namespace could be created.
+	LandlockNetwork bool `json:"landlock_network,omitempty"`
+}
+
+// defaultWritePaths are device files commands routinely write to.
+var defaultWritePaths = []string{"/dev/null", "/dev/zero", "/dev/full", "/dev/tty", "/dev/pts"}
+
+// Describe summarizes the policy for execution logs.
+func (p Policy) Describe() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Writable: %s\n", strings.Join(p.WritePaths, ", "))
+	network := "allowed"
+	switch {
+	case p.DisableNetwork && p.LandlockNetwork:
+		network = "disabled (TCP only)"
+	case p.DisableNetwork:
+		network = "disabled"
+	}
+	fmt.Fprintf(&b, "Network: %s\n", network)
+	if p.CPUSeconds > 0 {
+		fmt.Fprintf(&b, "CPU limit: %ds\n", p.CPUSeconds)
+	}
+	if p.MemoryMB > 0 {
+		fmt.Fprintf(&b, "Memory limit: %d MB\n", p.MemoryMB)
+	}
+	return b.String()
+}
+
+// Command returns a command that runs name with args under the policy. The
+// returned command's Env already carries the policy; append to it rather
+// than replacing it.
+func Command(ctx context.Context, p Policy, name string, args ...string) (*exec.Cmd, error) {
+	if err := Available(); err != nil {
+		return nil, err
+	}
+	exe, err := os.Executable()
+	if err != nil {
+		return nil, fmt.Errorf("locating slb binary: %w", err)
+	}
+
+	attr, landlockNetwork, err := isolateNetwork(p.DisableNetwork)
+	if err != nil {
+		return nil, err
+	}
+	p.LandlockNetwork = landlockNetwork
+	data, err := json.Marshal(p)
+	if err != nil {
+		return nil, fmt.Errorf("encoding sandbox policy: %w", err)
+	}
+
+	cmd := exec.CommandContext(ctx, exe, append([]string{helperArg, "--", name}, args...)...)
+	cmd.Env = append(os.Environ(), policyEnv+"="+string(data))
+	cmd.SysProcAttr = attr
+	return cmd, nil
+}
+
+// MaybeRunHelper runs the sandbox helper and does not return when the
+// process was started as one by Command. It must be called first thing in
+// main (and in TestMain of packages that run sandboxed commands).
+func MaybeRunHelper() {
+	if len(os.Args) < 2 || os.Args[1] != helperArg {
+		return
+	}
+	if len(os.Args) > 2 && os.Args[2] == probeArg {
+		os.Exit(0)
+	}
+	if len(os.Args) < 4 || os.Args[2] != "--" {
+		helperFailed(errors.New("missing command"))
+	}
+
+	var p Policy
+	if err := json.Unmarshal([]byte(os.Getenv(policyEnv)), &p); err != nil {
+		helperFailed(fmt.Errorf("reading policy: %w", err))
+	}
+	os.Unsetenv(policyEnv)
+
+	path, err := exec.LookPath(os.Args[3])
+	if err != nil {
+		helperFailed(err)
+	}
+	// Only returns on failure.
+	helperFailed(confineAndExec(p, path, os.Args[3:]))
+}
+
+func helperFailed(err error) {
+	fmt.Fprintf(os.Stderr, "%s%v\n", setupFailedPrefix, err)
+	os.Exit(setupFailedCode)
+}
+
+// SetupError returns ErrSetup with the helper's message when a sandboxed
+// command exited because the helper could not confine itself.
+func SetupError(exitCode int, output string) error {
+	if exitCode != setupFailedCode {
+		return nil
+	}
+	for _, line := range strings.Split(output, "\n") {
+		if msg, ok := strings.CutPrefix(line, setupFailedPrefix); ok {
+			return fmt.Errorf("%w: %s", ErrSetup, msg)
+		}
+	}
+	return nil
+}
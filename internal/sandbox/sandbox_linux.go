@@ -0,0 +1,221 @@
+package sandbox
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"sync"
+	"syscall"
+	"unsafe"
+
+	"golang.org/x/sys/unix"
+)
+
+// fsWriteAccess are the Landlock rights covering changes to the file system,
+// by the ABI version that introduced them. Reads and execution stay
+// unrestricted.
+var fsWriteAccess = []uint64{
+	1: unix.LANDLOCK_ACCESS_FS_WRITE_FILE | unix.LANDLOCK_ACCESS_FS_REMOVE_DIR |
+		unix.LANDLOCK_ACCESS_FS_REMOVE_FILE | unix.LANDLOCK_ACCESS_FS_MAKE_CHAR |
+		unix.LANDLOCK_ACCESS_FS_MAKE_DIR | unix.LANDLOCK_ACCESS_FS_MAKE_REG |
+		unix.LANDLOCK_ACCESS_FS_MAKE_SOCK | unix.LANDLOCK_ACCESS_FS_MAKE_FIFO |
+		unix.LANDLOCK_ACCESS_FS_MAKE_BLOCK | unix.LANDLOCK_ACCESS_FS_MAKE_SYM,
+	2: unix.LANDLOCK_ACCESS_FS_REFER,
+	3: unix.LANDLOCK_ACCESS_FS_TRUNCATE,
+}
+
+// fileAccess are the rights that apply to a regular file or device rather
+// than a directory.
+const fileAccess = unix.LANDLOCK_ACCESS_FS_WRITE_FILE | unix.LANDLOCK_ACCESS_FS_TRUNCATE
+
+// landlockNetworkABI is the first ABI version able to restrict TCP.
+const landlockNetworkABI = 4
+
+var (
+	abiOnce sync.Once
+	abi     int
+	abiErr  error
+
+	netnsOnce sync.Once
+	netnsOK   bool
+)
+
+// landlockABI returns the Landlock ABI version supported by the kernel.
+func landlockABI() (int, error) {
+	abiOnce.Do(func() {
+		v, _, errno := unix.Syscall(unix.SYS_LANDLOCK_CREATE_RULESET, 0, 0, unix.LANDLOCK_CREATE_RULESET_VERSION)
+		if errno != 0 {
+			abiErr = fmt.Errorf("%w: Landlock unavailable (%v)", ErrUnsupported, errno)
+			return
+		}
+		abi = int(v)
+	})
+	return abi, abiErr
+}
+
+// Available reports whether commands can be sandboxed here.
+func Available() error {
+	_, err := landlockABI()
+	return err
+}
+
+// isolateNetwork returns the process attributes that disable networking.
+// A new user and network namespace is preferred; where unprivileged user
+// namespaces are not allowed, Landlock's TCP rules are used instead.
+func isolateNetwork(disable bool) (*syscall.SysProcAttr, bool, error) {
+	if !disable {
+		return nil, false, nil
+	}
+	if networkNamespaceAvailable() {
+		return netnsAttr(), false, nil
+	}
+	if v, _ := landlockABI(); v >= landlockNetworkABI {
+		return nil, true, nil
+	}
+	return nil, false, fmt.Errorf("%w: cannot disable networking (no user namespaces and Landlock ABI < %d)", ErrUnsupported, landlockNetworkABI)
+}
+
+func netnsAttr() *syscall.SysProcAttr {
+	uid, gid := os.Getuid(), os.Getgid()
+	return &syscall.SysProcAttr{
+		Cloneflags:  syscall.CLONE_NEWUSER | syscall.CLONE_NEWNET,
+		UidMappings: []syscall.SysProcIDMap{{ContainerID: uid, HostID: uid, Size: 1}},
+		GidMappings: []syscall.SysProcIDMap{{ContainerID: gid, HostID: gid, Size: 1}},
+	}
+}
+
+// networkNamespaceAvailable starts the helper in a new network namespace
+// once to learn whether that is permitted.
+func networkNamespaceAvailable() bool {
+	netnsOnce.Do(func() {
+		exe, err := os.Executable()
+		if err != nil {
+			return
+		}
+		cmd := exec.Command(exe, helperArg, probeArg)
+		cmd.SysProcAttr = netnsAttr()
+		netnsOK = cmd.Run() == nil
+	})
+	return netnsOK
+}
+
+// confineAndExec restricts the calling thread and replaces the process with
+// path. Landlock domains and no_new_privs are per thread, so the thread is
+// locked and exec'd from directly.
+func confineAndExec(p Policy, path string, argv []string) error {
+	runtime.LockOSThread()
+
+	if err := setRlimits(p); err != nil {
+		return err
+	}
+	if err := restrictWrites(p); err != nil {
+		return err
+	}
+	if err := unix.Exec(path, argv, os.Environ()); err != nil {
+		return fmt.Errorf("exec %s: %w", path, err)
+	}
+	return nil
+}
+
+func setRlimits(p Policy) error {
+	if p.CPUSeconds > 0 {
+		// SIGXCPU at the soft limit, SIGKILL a second later.
+		lim := &unix.Rlimit{Cur: uint64(p.CPUSeconds), Max: uint64(p.CPUSeconds) + 1}
+		if err := unix.Setrlimit(unix.RLIMIT_CPU, lim); err != nil {
+			return fmt.Errorf("setting CPU limit: %w", err)
+		}
+	}
+	if p.MemoryMB > 0 {
+		bytes := uint64(p.MemoryMB) << 20
+		if err := unix.Setrlimit(unix.RLIMIT_AS, &unix.Rlimit{Cur: bytes, Max: bytes}); err != nil {
+			return fmt.Errorf("setting memory limit: %w", err)
+		}
+	}
+	return nil
+}
+
+func restrictWrites(p Policy) error {
+	version, err := landlockABI()
+	if err != nil {
+		return err
+	}
+	var handled uint64
+	for v := 1; v < len(fsWriteAccess) && v <= version; v++ {
+		handled |= fsWriteAccess[v]
+	}
+	attr := unix.LandlockRulesetAttr{Access_fs: handled}
+	if p.DisableNetwork && p.LandlockNetwork {
+		attr.Access_net = unix.LANDLOCK_ACCESS_NET_BIND_TCP | unix.LANDLOCK_ACCESS_NET_CONNECT_TCP
+	}
+
+	fd, _, errno := unix.Syscall(unix.SYS_LANDLOCK_CREATE_RULESET, uintptr(unsafe.Pointer(&attr)), unsafe.Sizeof(attr), 0)
+	if errno != 0 {
+		return fmt.Errorf("creating Landlock ruleset: %w", errno)
+	}
+	ruleset := int(fd)
+	defer unix.Close(ruleset)
+
+	for _, path := range append(append([]string{}, defaultWritePaths...), p.WritePaths...) {
+		if err := allowWrites(ruleset, path, handled); err != nil {
+			return err
+		}
+	}
+
+	if err := unix.Prctl(unix.PR_SET_NO_NEW_PRIVS, 1, 0, 0, 0); err != nil {
+		return fmt.Errorf("setting no_new_privs: %w", err)
+	}
+	if _, _, errno := unix.Syscall(unix.SYS_LANDLOCK_RESTRICT_SELF, uintptr(ruleset), 0, 0); errno != 0 {
+		return fmt.Errorf("enforcing Landlock ruleset: %w", errno)
+	}
+	return nil
+}
+
+// allowWrites permits writes beneath path. Missing paths are skipped: the
+// caller grants their parent when the command may create them.
+func allowWrites(ruleset int, path string, handled uint64) error {
+	fd, err := unix.Open(path, unix.O_PATH|unix.O_CLOEXEC, 0)
+	if err != nil {
+		if errors.Is(err, unix.ENOENT) || errors.Is(err, unix.ENXIO) || errors.Is(err, unix.EACCES) {
+			return nil
+		}
+		return fmt.Errorf("opening %s: %w", path, err)
+	}
+	defer unix.Close(fd)
+
+	var st unix.Stat_t
+	if err := unix.Fstat(fd, &st); err != nil {
+		return fmt.Errorf("stat %s: %w", path, err)
+	}
+	access := handled
+	if st.Mode&unix.S_IFMT != unix.S_IFDIR {
+		access &= fileAccess
+	}
+	rule := unix.LandlockPathBeneathAttr{Allowed_access: access, Parent_fd: int32(fd)}
+	if _, _, errno := unix.Syscall6(unix.SYS_LANDLOCK_ADD_RULE, uintptr(ruleset), unix.LANDLOCK_RULE_PATH_BENEATH,
+		uintptr(unsafe.Pointer(&rule)), 0, 0, 0); errno != 0 {
+		return fmt.Errorf("allowing writes to %s: %w", path, errno)
+	}
+	return nil
+}
+
+// signalViolation describes a resource limit that killed the command.
+func signalViolation(p Policy, state *os.ProcessState) string {
+	if state == nil {
+		return ""
+	}
+	ws, ok := state.Sys().(syscall.WaitStatus)
+	if !ok || !ws.Signaled() {
+		return ""
+	}
+	switch sig := ws.Signal(); {
+	case sig == syscall.SIGXCPU, sig == syscall.SIGKILL && p.CPUSeconds > 0 && state.UserTime()+state.SystemTime() >= cpuSeconds(p):
+		return fmt.Sprintf("cpu: exceeded the %ds CPU limit", p.CPUSeconds)
+	case sig == syscall.SIGKILL && p.MemoryMB > 0:
+		return fmt.Sprintf("memory: killed, possibly for exceeding the %d MB limit", p.MemoryMB)
+	case sig == syscall.SIGSEGV && p.MemoryMB > 0:
+		return fmt.Sprintf("memory: crashed, possibly for exceeding the %d MB limit", p.MemoryMB)
+	}
+	return ""
+}
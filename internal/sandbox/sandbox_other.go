@@ -0,0 +1,25 @@
+//go:build !linux
+
+package sandbox
+
+import (
+	"os"
+	"syscall"
+)
+
+// Available reports whether commands can be sandboxed here.
+func Available() error {
+	return ErrUnsupported
+}
+
+func isolateNetwork(bool) (*syscall.SysProcAttr, bool, error) {
+	return nil, false, ErrUnsupported
+}
+
+func confineAndExec(Policy, string, []string) error {
+	return ErrUnsupported
+}
+
+func signalViolation(Policy, *os.ProcessState) string {
+	return ""
+}
@@ -0,0 +1,134 @@
+package sandbox
+
+import (
+	"context"
+	"errors"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestMain(m *testing.M) {
+	MaybeRunHelper()
+	os.Exit(m.Run())
+}
+
+func requireSandbox(t *testing.T) {
+	t.Helper()
+	if err := Available(); err != nil {
+		t.Skipf("sandbox unavailable: %v", err)
+	}
+}
+
+func run(t *testing.T, p Policy, name string, args ...string) (string, *exec.Cmd, error) {
+	t.Helper()
+	cmd, err := Command(context.Background(), p, name, args...)
+	if err != nil {
+		t.Fatalf("Command: %v", err)
+	}
+	out, err := cmd.CombinedOutput()
+	return string(out), cmd, err
+}
+
+func TestCommand_RestrictsWrites(t *testing.T) {
+	requireSandbox(t)
+	allowed := t.TempDir()
+	denied := t.TempDir()
+
+	out, _, err := run(t, Policy{WritePaths: []string{allowed}}, "sh", "-c",
+		"echo ok > "+filepath.Join(allowed, "a")+" && echo no > "+filepath.Join(denied, "b"))
+	if err == nil {
+		t.Fatalf("expected write outside the sandbox to fail, output: %s", out)
+	}
+	if _, err := os.Stat(filepath.Join(allowed, "a")); err != nil {
+		t.Errorf("allowed write missing: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(denied, "b")); err == nil {
+		t.Error("denied write happened")
+	}
+	if v := Violations(Policy{}, out, nil); len(v) != 1 || !strings.HasPrefix(v[0], "write: ") {
+		t.Errorf("Violations = %v (output %q)", v, out)
+	}
+
+	// Device files stay writable and the policy does not leak into the command.
+	out, _, err = run(t, Policy{}, "sh", "-c", "echo x > /dev/null && echo ${"+policyEnv+":-unset}")
+	if err != nil || strings.TrimSpace(out) != "unset" {
+		t.Errorf("got %q, %v", out, err)
+	}
+}
+
+func TestCommand_CPULimit(t *testing.T) {
+	requireSandbox(t)
+	p := Policy{CPUSeconds: 1}
+	out, cmd, err := run(t, p, "sh", "-c", "while :; do :; done")
+	if err == nil {
+		t.Fatalf("expected CPU limit to stop the loop, output: %s", out)
+	}
+	if v := Violations(p, out, cmd.ProcessState); len(v) == 0 || !strings.HasPrefix(v[0], "cpu: ") {
+		t.Errorf("Violations = %v", v)
+	}
+}
+
+func TestCommand_DisableNetwork(t *testing.T) {
+	requireSandbox(t)
+	if _, _, err := isolateNetwork(true); err != nil {
+		t.Skipf("network isolation unavailable: %v", err)
+	}
+	bash, err := exec.LookPath("bash")
+	if err != nil {
+		t.Skip("bash not available")
+	}
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Skipf("listen: %v", err)
+	}
+	defer ln.Close()
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			conn.Close()
+		}
+	}()
+	connect := "exec 3<>/dev/tcp/" + strings.Replace(ln.Addr().String(), ":", "/", 1)
+
+	if out, _, err := run(t, Policy{}, bash, "-c", connect); err != nil {
+		t.Fatalf("connect with network allowed: %v (%s)", err, out)
+	}
+	if out, _, err := run(t, Policy{DisableNetwork: true}, bash, "-c", connect); err == nil {
+		t.Errorf("connect succeeded with network disabled: %s", out)
+	}
+}
+
+func TestSetupError(t *testing.T) {
+	if err := SetupError(1, setupFailedPrefix+"boom"); err != nil {
+		t.Errorf("non-setup exit code: %v", err)
+	}
+	err := SetupError(setupFailedCode, "noise\n"+setupFailedPrefix+"boom\n")
+	if !errors.Is(err, ErrSetup) || !strings.Contains(err.Error(), "boom") {
+		t.Errorf("SetupError = %v", err)
+	}
+	if runtime.GOOS != "linux" {
+		if err := Available(); !errors.Is(err, ErrUnsupported) {
+			t.Errorf("Available = %v", err)
+		}
+	}
+}
+
+func TestViolations(t *testing.T) {
+	output := "rm: cannot remove '/etc/x': Read-only file system\ncurl: (6) Could not resolve host: example.com\n" +
+		"rm: cannot remove '/etc/x': Read-only file system\nall good\n"
+	if v := Violations(Policy{}, output, nil); len(v) != 1 {
+		t.Errorf("network markers apply only with the network disabled: %v", v)
+	}
+	v := Violations(Policy{DisableNetwork: true}, output, nil)
+	if len(v) != 2 || !strings.HasPrefix(v[0], "write: ") || !strings.HasPrefix(v[1], "network: ") {
+		t.Errorf("Violations = %v", v)
+	}
+}
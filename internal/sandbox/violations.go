@@ -0,0 +1,70 @@
+package sandbox
+
+import (
+	"os"
+	"strings"
+	"time"
+)
+
+// maxViolations bounds the violations reported for one execution.
+const maxViolations = 20
+
+// violationMarkers map output fragments to the kind of restriction they
+// suggest the command ran into.
+var violationMarkers = []struct {
+	kind, marker string
+	applies      func(Policy) bool
+}{
+	{"write", "Read-only file system", always},
+	{"write", "Permission denied", always},
+	{"write", "Operation not permitted", always},
+	{"network", "Network is unreachable", noNetwork},
+	{"network", "Could not resolve host", noNetwork},
+	{"network", "Temporary failure in name resolution", noNetwork},
+	{"network", "Name or service not known", noNetwork},
+	{"memory", "Cannot allocate memory", limitsMemory},
+	{"memory", "out of memory", limitsMemory},
+	{"memory", "MemoryError", limitsMemory},
+}
+
+func always(Policy) bool                { return true }
+func noNetwork(p Policy) bool           { return p.DisableNetwork }
+func limitsMemory(p Policy) bool        { return p.MemoryMB > 0 }
+func cpuSeconds(p Policy) time.Duration { return time.Duration(p.CPUSeconds) * time.Second }
+
+// Violations lists signs that a sandboxed command ran into its policy:
+// output lines reporting denied writes, unreachable networks or failed
+// allocations, and resource limits that killed it. Denied writes cannot be
+// told apart from ordinary permission errors, so they are reported as
+// likely rather than certain violations.
+func Violations(p Policy, output string, state *os.ProcessState) []string {
+	var violations []string
+	if v := signalViolation(p, state); v != "" {
+		violations = append(violations, v)
+	}
+	seen := map[string]bool{}
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || seen[line] || strings.HasPrefix(line, setupFailedPrefix) {
+			continue
+		}
+		for _, m := range violationMarkers {
+			if m.applies(p) && strings.Contains(line, m.marker) {
+				seen[line] = true
+				violations = append(violations, m.kind+": "+truncate(line, 200))
+				break
+			}
+		}
+		if len(violations) >= maxViolations {
+			break
+		}
+	}
+	return violations
+}
+
+func truncate(s string, max int) string {
+	if len(s) <= max {
+		return s
+	}
+	return s[:max-3] + "..."
+}
@@ -0,0 +1,198 @@
+// Package schedule evaluates recurring time windows defined by a cron-like
+// expression and a duration, such as change windows and freeze periods.
+package schedule
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// MaxDuration is the longest a recurring window may last.
+const MaxDuration = 7 * 24 * time.Hour
+
+// searchLimit bounds the search for the next start of a window.
+const searchLimit = 366 * 24 * time.Hour
+
+// Cron is a parsed five-field cron expression: minute, hour, day of month,
+// month and day of week. Fields accept *, numbers, ranges (1-5), lists
+// (1,15), steps (*/15, 9-17/2) and, for months and days of the week,
+// three-letter names (JAN, MON-FRI). Day of week 0 and 7 are Sunday.
+type Cron struct {
+	expr                          string
+	minute, hour, dom, month, dow uint64
+	domRestricted, dowRestricted  bool
+}
+
+type field struct {
+	name     string
+	min, max int
+	names    []string
+}
+
+var fields = []field{
+	{name: "minute", min: 0, max: 59},
+	{name: "hour", min: 0, max: 23},
+	{name: "day of month", min: 1, max: 31},
+	{name: "month", min: 1, max: 12, names: []string{"jan", "feb", "mar", "apr", "may", "jun", "jul", "aug", "sep", "oct", "nov", "dec"}},
+	{name: "day of week", min: 0, max: 7, names: []string{"sun", "mon", "tue", "wed", "thu", "fri", "sat"}},
+}
+
+// Parse parses a cron expression.
+func Parse(expr string) (*Cron, error) {
+	parts := strings.Fields(expr)
+	if len(parts) != len(fields) {
+		return nil, fmt.Errorf("cron expression %q: expected 5 fields, got %d", expr, len(parts))
+	}
+	c := &Cron{expr: strings.Join(parts, " ")}
+	sets := []*uint64{&c.minute, &c.hour, &c.dom, &c.month, &c.dow}
+	for i, part := range parts {
+		set, err := parseField(part, fields[i])
+		if err != nil {
+			return nil, fmt.Errorf("cron expression %q: %w", expr, err)
+		}
+		*sets[i] = set
+	}
+	// Sunday may be written as 7.
+	if c.dow&(1<<7) != 0 {
+		c.dow |= 1
+	}
+	c.domRestricted = parts[2] != "*"
+	c.dowRestricted = parts[4] != "*"
+	return c, nil
+}
+
+func parseField(s string, f field) (uint64, error) {
+	var set uint64
+	for _, item := range strings.Split(s, ",") {
+		rangePart, step := item, 1
+		if i := strings.Index(item, "/"); i >= 0 {
+			n, err := strconv.Atoi(item[i+1:])
+			if err != nil || n < 1 {
+				return 0, fmt.Errorf("%s: invalid step in %q", f.name, item)
+			}
+			rangePart, step = item[:i], n
+		}
+
+		lo, hi := f.min, f.max
+		if rangePart != "*" {
+			var err error
+			bounds := strings.SplitN(rangePart, "-", 2)
+			if lo, err = f.value(bounds[0]); err != nil {
+				return 0, err
+			}
+			hi = lo
+			if len(bounds) == 2 {
+				if hi, err = f.value(bounds[1]); err != nil {
+					return 0, err
+				}
+			} else if step > 1 {
+				hi = f.max
+			}
+			if hi < lo {
+				return 0, fmt.Errorf("%s: invalid range %q", f.name, rangePart)
+			}
+		}
+		for v := lo; v <= hi; v += step {
+			set |= 1 << uint(v)
+		}
+	}
+	return set, nil
+}
+
+func (f field) value(s string) (int, error) {
+	for i, name := range f.names {
+		if strings.EqualFold(s, name) {
+			return i + f.min, nil
+		}
+	}
+	n, err := strconv.Atoi(s)
+	if err != nil || n < f.min || n > f.max {
+		return 0, fmt.Errorf("%s: %q is not between %d and %d", f.name, s, f.min, f.max)
+	}
+	return n, nil
+}
+
+// String returns the expression as parsed.
+func (c *Cron) String() string {
+	return c.expr
+}
+
+// Matches reports whether the minute containing t matches the expression.
+// As in cron, when both day of month and day of week are restricted, a day
+// matching either one matches.
+func (c *Cron) Matches(t time.Time) bool {
+	if c.minute&(1<<uint(t.Minute())) == 0 || c.hour&(1<<uint(t.Hour())) == 0 || c.month&(1<<uint(t.Month())) == 0 {
+		return false
+	}
+	dom := c.dom&(1<<uint(t.Day())) != 0
+	dow := c.dow&(1<<uint(t.Weekday())) != 0
+	if c.domRestricted && c.dowRestricted {
+		return dom || dow
+	}
+	return dom && dow
+}
+
+// Window is a recurring window: it opens whenever Cron matches and stays
+// open for Duration. Cron is evaluated in Location (time.Local when nil).
+type Window struct {
+	Cron     *Cron
+	Duration time.Duration
+	Location *time.Location
+}
+
+// NewWindow parses a window from a cron expression, a duration such as
+// "2h30m" and an optional IANA time zone name.
+func NewWindow(expr, duration, timezone string) (Window, error) {
+	c, err := Parse(expr)
+	if err != nil {
+		return Window{}, err
+	}
+	d, err := time.ParseDuration(duration)
+	if err != nil {
+		return Window{}, fmt.Errorf("duration %q: %w", duration, err)
+	}
+	if d < time.Minute || d > MaxDuration {
+		return Window{}, fmt.Errorf("duration %q must be between 1m and %s", duration, MaxDuration)
+	}
+	w := Window{Cron: c, Duration: d}
+	if timezone != "" {
+		if w.Location, err = time.LoadLocation(timezone); err != nil {
+			return Window{}, fmt.Errorf("timezone %q: %w", timezone, err)
+		}
+	}
+	return w, nil
+}
+
+func (w Window) in(t time.Time) time.Time {
+	if w.Location != nil {
+		return t.In(w.Location)
+	}
+	return t.In(time.Local)
+}
+
+// Active reports whether the window is open at t and, if so, when the
+// latest opening covering t closes.
+func (w Window) Active(t time.Time) (end time.Time, ok bool) {
+	t = w.in(t)
+	for start := t.Truncate(time.Minute); t.Sub(start) < w.Duration; start = start.Add(-time.Minute) {
+		if w.Cron.Matches(start) {
+			return start.Add(w.Duration), true
+		}
+	}
+	return time.Time{}, false
+}
+
+// Next returns the first opening of the window after t, searching up to a
+// year ahead.
+func (w Window) Next(t time.Time) (time.Time, bool) {
+	t = w.in(t)
+	start := t.Truncate(time.Minute).Add(time.Minute)
+	for limit := t.Add(searchLimit); start.Before(limit); start = start.Add(time.Minute) {
+		if w.Cron.Matches(start) {
+			return start, true
+		}
+	}
+	return time.Time{}, false
+}
@@ -0,0 +1,82 @@
+package schedule
+
+import (
+	"testing"
+	"time"
+)
+
+func at(s string) time.Time {
+	t, err := time.ParseInLocation("2006-01-02 15:04", s, time.UTC)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+func TestParse(t *testing.T) {
+	for _, expr := range []string{"* * * * *", "0 2 * * *", "*/15 9-17 * * MON-FRI", "0 15 * * 5", "0 0 1,15 * *", "30 1 * jan-mar 7"} {
+		if _, err := Parse(expr); err != nil {
+			t.Errorf("Parse(%q): %v", expr, err)
+		}
+	}
+	for _, expr := range []string{"", "* * * *", "60 * * * *", "* 24 * * *", "* * 0 * *", "* * * 13 *", "* * * * 8", "5-1 * * * *", "*/0 * * * *", "* * * * funday"} {
+		if _, err := Parse(expr); err == nil {
+			t.Errorf("Parse(%q): expected an error", expr)
+		}
+	}
+}
+
+func TestCron_Matches(t *testing.T) {
+	tests := []struct {
+		expr string
+		time string
+		want bool
+	}{
+		{"0 15 * * fri", "2026-10-16 15:00", true}, // a Friday
+		{"0 15 * * fri", "2026-10-16 15:01", false},
+		{"0 15 * * fri", "2026-10-15 15:00", false},
+		{"*/15 9-17 * * 1-5", "2026-10-15 17:45", true},
+		{"*/15 9-17 * * 1-5", "2026-10-18 10:00", false}, // a Sunday
+		{"0 0 * * 7", "2026-10-18 00:00", true},
+		{"0 0 1 * mon", "2026-10-19 00:00", true}, // day of week alone matches
+		{"0 0 1 * mon", "2026-11-01 00:00", true}, // day of month alone matches
+		{"0 0 1 * mon", "2026-10-20 00:00", false},
+		{"0 0 1 * *", "2026-10-20 00:00", false},
+	}
+	for _, tt := range tests {
+		c, err := Parse(tt.expr)
+		if err != nil {
+			t.Fatalf("Parse(%q): %v", tt.expr, err)
+		}
+		if got := c.Matches(at(tt.time)); got != tt.want {
+			t.Errorf("%q at %s = %v, want %v", tt.expr, tt.time, got, tt.want)
+		}
+	}
+}
+
+func TestWindow(t *testing.T) {
+	// Fridays from 15:00 until the end of the day.
+	w, err := NewWindow("0 15 * * fri", "9h", "UTC")
+	if err != nil {
+		t.Fatalf("NewWindow: %v", err)
+	}
+
+	if end, ok := w.Active(at("2026-10-16 18:30")); !ok || !end.Equal(at("2026-10-17 00:00")) {
+		t.Errorf("Active(Fri 18:30) = %v, %v", end, ok)
+	}
+	if _, ok := w.Active(at("2026-10-16 14:59")); ok {
+		t.Error("Active(Fri 14:59) = true")
+	}
+	if _, ok := w.Active(at("2026-10-17 00:00")); ok {
+		t.Error("Active(Sat 00:00) = true, the window closes at midnight")
+	}
+	if next, ok := w.Next(at("2026-10-17 09:00")); !ok || !next.Equal(at("2026-10-23 15:00")) {
+		t.Errorf("Next(Sat) = %v, %v", next, ok)
+	}
+
+	for _, tt := range [][3]string{{"0 2 * * *", "0s", ""}, {"0 2 * * *", "200h", ""}, {"0 2 * * *", "1h", "Nowhere/City"}, {"0 2 * *", "1h", ""}} {
+		if _, err := NewWindow(tt[0], tt[1], tt[2]); err == nil {
+			t.Errorf("NewWindow(%q, %q, %q): expected an error", tt[0], tt[1], tt[2])
+		}
+	}
+}
@@ -0,0 +1,418 @@
+// Package scrub removes secrets from command output before it is persisted
+// or sent to reviewers: execution logs, transcripts, dry-run output and
+// attachments.
+package scrub
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"regexp"
+	"sync"
+)
+
+// Placeholder replaces every secret found.
+const Placeholder = "[REDACTED]"
+
+// maxLine is how much output without a newline is held back before it is
+// scrubbed anyway, so a secret split there may be missed.
+const maxLine = 64 * 1024
+
+// Detector finds one kind of secret. When Pattern has a group named
+// "secret", only that group is replaced; otherwise the whole match is.
+type Detector struct {
+	Name    string
+	Pattern *regexp.Regexp
+}
+
+// builtinDetectors are always applied.
+var builtinDetectors = []Detector{
+	{"aws_access_key_id", regexp.MustCompile(`\b(?:AKIA|ASIA|AGPA|AIDA|AROA|ANPA|ANVA|AIPA)[0-9A-Z]{16}\b`)},
+	{"aws_secret_access_key", regexp.MustCompile(`(?i)aws_?secret_?(?:access_?)?key["']?\s*[=:]\s*["']?(?P<secret>[A-Za-z0-9/+=]{40})`)},
+	{"github_token", regexp.MustCompile(`\b(?:gh[pousr]_[A-Za-z0-9]{36,}|github_pat_[A-Za-z0-9_]{22,})\b`)},
+	{"gitlab_token", regexp.MustCompile(`\bgl(?:pat|dt|rt|ptt|cbt)-[A-Za-z0-9_-]{20,}\b`)},
+	{"jwt", regexp.MustCompile(`\beyJ[A-Za-z0-9_-]{5,}\.eyJ[A-Za-z0-9_-]{5,}\.[A-Za-z0-9_-]{10,}`)},
+	{"private_key", regexp.MustCompile(`-----BEGIN [A-Z ]*PRIVATE KEY-----(?P<secret>.+?)-----END [A-Z ]*PRIVATE KEY-----`)},
+	{"connection_string", regexp.MustCompile(`\b[a-zA-Z][a-zA-Z0-9+.-]*://[^:/\s@]+:(?P<secret>[^@\s]+)@`)},
+	{"bearer_token", regexp.MustCompile(`(?i)\bbearer\s+(?P<secret>[A-Za-z0-9._~+/-]{8,}=*)`)},
+	{"secret_assignment", regexp.MustCompile(`(?i)\b(?:api[_-]?key|access[_-]?token|auth[_-]?token|secret|password|passwd)["']?\s*[=:]\s*["']?(?P<secret>[^\s"',]{4,})`)},
+}
+
+// PEM blocks span lines; their bodies are dropped while streaming.
+var (
+	privateKeyBegin = regexp.MustCompile(`-----BEGIN [A-Z ]*PRIVATE KEY-----`)
+	privateKeyEnd   = regexp.MustCompile(`-----END [A-Z ]*PRIVATE KEY-----`)
+)
+
+// Kubernetes Secrets printed by kubectl (-o yaml or -o json) hold their
+// values in entries under "data" (base64) or "stringData" (plain). The
+// entries are recognised by their indentation below that key, and only in
+// an object whose "kind" at the same indentation is Secret. kubectl sorts
+// keys, so "kind" usually follows "data"; the block is held back until it
+// is known.
+var (
+	secretDataBegin = regexp.MustCompile(`^(\s*(?:- )?)"?(data|stringData)"?:\s*\{?\s*$`)
+	kindLine        = regexp.MustCompile(`^(\s*(?:- )?)"?kind"?:\s*["']?([A-Za-z0-9]+)["']?,?\s*$`)
+	secretDataEntry = regexp.MustCompile(`^\s*["']?[A-Za-z0-9._-]+["']?:[ \t]+(?P<secret>\S.*?),?\s*$`)
+	base64Value     = regexp.MustCompile(`^["']?(?:[A-Za-z0-9+/]{4})+(?:[A-Za-z0-9+/]{2}==|[A-Za-z0-9+/]{3}=)?["']?$`)
+)
+
+// secretData tracks a "data:" or "stringData:" block being streamed.
+type secretData struct {
+	indent    int  // indentation of the data key
+	keyIndent int  // indentation of its entries; -1 until the first one
+	plain     bool // stringData: every value is a secret
+	inValue   bool // inside a multi-line ("|" or ">") value
+}
+
+// objectKind is the kind of an object whose keys are at indent.
+type objectKind struct {
+	indent int
+	kind   string
+}
+
+// Scrubber replaces secrets found by its detectors.
+type Scrubber struct {
+	detectors []Detector
+}
+
+var defaultScrubber = &Scrubber{detectors: builtinDetectors}
+
+// Default returns a scrubber with only the built-in detectors.
+func Default() *Scrubber {
+	return defaultScrubber
+}
+
+// New returns a scrubber with the built-in detectors plus one for each
+// custom regular expression.
+func New(custom []string) (*Scrubber, error) {
+	if len(custom) == 0 {
+		return defaultScrubber, nil
+	}
+	detectors := append([]Detector(nil), builtinDetectors...)
+	for i, p := range custom {
+		re, err := regexp.Compile(p)
+		if err != nil {
+			return nil, fmt.Errorf("invalid scrub pattern %q: %w", p, err)
+		}
+		detectors = append(detectors, Detector{Name: fmt.Sprintf("custom_%d", i+1), Pattern: re})
+	}
+	return &Scrubber{detectors: detectors}, nil
+}
+
+// String returns s with secrets replaced and the number replaced.
+func (s *Scrubber) String(in string) (string, int) {
+	var out bytes.Buffer
+	w := s.NewWriter(&out)
+	_, _ = io.WriteString(w, in)
+	_ = w.Close()
+	return out.String(), w.Count()
+}
+
+// Writer scrubs output line by line before passing it on. Output is held
+// back until a newline (or maxLine bytes) arrives, and a data block until
+// the kind of its object is known; Flush writes the former, Close both.
+// It is safe for concurrent use.
+type Writer struct {
+	s *Scrubber
+	w io.Writer
+
+	mu     sync.Mutex
+	buf    []byte
+	inKey  bool
+	secret *secretData
+	count  int
+
+	kinds      []objectKind // kinds of the enclosing objects, outermost first
+	held       [][]byte     // a data block of an object of unknown kind
+	heldIndent int          // indentation of its data key
+	decided    *bool        // whether the held block, being replayed, is a Secret's
+}
+
+// NewWriter returns a Writer scrubbing everything written to w.
+func (s *Scrubber) NewWriter(w io.Writer) *Writer {
+	return &Writer{s: s, w: w}
+}
+
+// Write scrubs and writes the complete lines in p.
+func (w *Writer) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.buf = append(w.buf, p...)
+	n := bytes.LastIndexByte(w.buf, '\n') + 1
+	if n == 0 && len(w.buf) >= maxLine {
+		n = len(w.buf)
+	}
+	if n == 0 {
+		return len(p), nil
+	}
+	err := w.emit(w.buf[:n])
+	w.buf = append(w.buf[:0], w.buf[n:]...)
+	if err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// Flush scrubs and writes output held back waiting for a newline.
+func (w *Writer) Flush() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if len(w.buf) == 0 {
+		return nil
+	}
+	err := w.emit(w.buf)
+	w.buf = w.buf[:0]
+	return err
+}
+
+// Close scrubs and writes all output held back, treating a data block whose
+// object has no kind as not a Secret's. The underlying writer is not closed.
+func (w *Writer) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	var out []byte
+	if len(w.buf) > 0 {
+		out = w.scrubLines(w.buf)
+		w.buf = w.buf[:0]
+	}
+	if w.held != nil {
+		out = append(out, w.release(false)...)
+	}
+	if len(out) == 0 {
+		return nil
+	}
+	_, err := w.w.Write(out)
+	return err
+}
+
+// Count returns the number of secrets replaced so far.
+func (w *Writer) Count() int {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.count
+}
+
+func (w *Writer) emit(data []byte) error {
+	out := w.scrubLines(data)
+	if len(out) == 0 {
+		return nil
+	}
+	_, err := w.w.Write(out)
+	return err
+}
+
+func (w *Writer) scrubLines(data []byte) []byte {
+	var out bytes.Buffer
+	for len(data) > 0 {
+		line := data
+		if i := bytes.IndexByte(data, '\n'); i >= 0 {
+			line = data[:i+1]
+		}
+		data = data[len(line):]
+		out.Write(w.scrubLine(line))
+	}
+	return out.Bytes()
+}
+
+// scrubLine scrubs one line, dropping the body of a multi-line private key.
+func (w *Writer) scrubLine(line []byte) []byte {
+	if w.inKey {
+		if loc := privateKeyEnd.FindIndex(line); loc != nil {
+			w.inKey = false
+			return w.replace(line[loc[0]:])
+		}
+		return nil
+	}
+	if w.held != nil {
+		return w.hold(line)
+	}
+	if w.secret != nil {
+		if out, ok := w.scrubSecretData(line); ok {
+			return out
+		}
+	}
+	w.trackKind(line)
+	if m := secretDataBegin.FindSubmatch(line); m != nil {
+		indent := len(m[1])
+		secret, known := w.kindAt(indent)
+		if w.decided != nil {
+			secret, known = *w.decided, true
+			w.decided = nil
+		}
+		switch {
+		case !known:
+			w.held = [][]byte{append([]byte(nil), line...)}
+			w.heldIndent = indent
+			return nil
+		case secret:
+			w.secret = &secretData{indent: indent, keyIndent: -1, plain: string(m[2]) == "stringData"}
+			return line
+		}
+	}
+	line = w.replace(line)
+	if loc := privateKeyBegin.FindIndex(line); loc != nil && !privateKeyEnd.Match(line[loc[1]:]) {
+		w.inKey = true
+		w.count++
+		nl := ""
+		if bytes.HasSuffix(line, []byte("\r\n")) {
+			nl = "\r\n"
+		} else if bytes.HasSuffix(line, []byte("\n")) {
+			nl = "\n"
+		}
+		return append(append([]byte(nil), line[:loc[1]]...), []byte(nl+Placeholder+nl)...)
+	}
+	return line
+}
+
+// trackKind records the kind of the object a line belongs to, forgetting
+// the objects that ended before it.
+func (w *Writer) trackKind(line []byte) {
+	content := bytes.TrimRight(line, "\r\n")
+	trimmed := bytes.TrimSpace(content)
+	if len(trimmed) == 0 {
+		return
+	}
+	if string(trimmed) == "---" {
+		w.kinds = nil
+		return
+	}
+	indent := len(content) - len(bytes.TrimLeft(content, " "))
+	for len(w.kinds) > 0 && w.kinds[len(w.kinds)-1].indent > indent {
+		w.kinds = w.kinds[:len(w.kinds)-1]
+	}
+	m := kindLine.FindSubmatch(content)
+	if m == nil {
+		return
+	}
+	if n := len(w.kinds); n > 0 && w.kinds[n-1].indent == len(m[1]) {
+		w.kinds = w.kinds[:n-1]
+	}
+	w.kinds = append(w.kinds, objectKind{indent: len(m[1]), kind: string(m[2])})
+}
+
+// kindAt reports whether the object with keys at indent is a Secret, and
+// whether its kind is known yet.
+func (w *Writer) kindAt(indent int) (secret, known bool) {
+	for _, k := range w.kinds {
+		if k.indent == indent {
+			return k.kind == "Secret", true
+		}
+	}
+	return false, false
+}
+
+// hold keeps a line of a data block whose object's kind is not yet known,
+// releasing the block once the line settles it: a "kind" key of the same
+// object, or the end of the object.
+func (w *Writer) hold(line []byte) []byte {
+	w.held = append(w.held, append([]byte(nil), line...))
+	content := bytes.TrimRight(line, "\r\n")
+	trimmed := bytes.TrimSpace(content)
+	if len(trimmed) == 0 {
+		return nil
+	}
+	indent := len(content) - len(bytes.TrimLeft(content, " "))
+	if indent < w.heldIndent || string(trimmed) == "---" {
+		return w.release(false)
+	}
+	if m := kindLine.FindSubmatch(content); m != nil && len(m[1]) == w.heldIndent {
+		return w.release(string(m[2]) == "Secret")
+	}
+	return nil
+}
+
+// release scrubs the held lines now that it is known whether they are a
+// Secret's data.
+func (w *Writer) release(secret bool) []byte {
+	held := w.held
+	w.held = nil
+	w.decided = &secret
+	var out []byte
+	for _, line := range held {
+		out = append(out, w.scrubLine(line)...)
+	}
+	return out
+}
+
+// scrubSecretData scrubs a line following a Kubernetes Secret's data key.
+// It reports false once the line is no longer part of the block.
+func (w *Writer) scrubSecretData(line []byte) ([]byte, bool) {
+	content := bytes.TrimRight(line, "\r\n")
+	if len(bytes.TrimSpace(content)) == 0 {
+		return line, true
+	}
+	s := w.secret
+	indent := len(content) - len(bytes.TrimLeft(content, " "))
+	if indent <= s.indent {
+		w.secret = nil
+		return nil, false
+	}
+	if s.keyIndent < 0 {
+		s.keyIndent = indent
+	}
+	if indent > s.keyIndent {
+		if !s.inValue {
+			return w.replace(line), true
+		}
+		return append(append(content[:indent:indent], Placeholder...), line[len(content):]...), true
+	}
+
+	s.inValue = false
+	m := secretDataEntry.FindSubmatchIndex(line)
+	if m == nil {
+		return w.replace(line), true
+	}
+	start, end := m[2], m[3]
+	value := line[start:end]
+	switch {
+	case value[0] == '|' || value[0] == '>':
+		s.inValue = true
+		w.count++
+		return line, true
+	case s.plain || base64Value.Match(value):
+		// Quotes stay, so JSON output remains valid.
+		if q := value[0]; len(value) > 1 && (q == '"' || q == '\'') && value[len(value)-1] == q {
+			start, end = start+1, end-1
+		}
+		w.count++
+		return append(append(append([]byte(nil), line[:start]...), Placeholder...), line[end:]...), true
+	}
+	return w.replace(line), true
+}
+
+func (w *Writer) replace(line []byte) []byte {
+	for _, d := range w.s.detectors {
+		group := d.Pattern.SubexpIndex("secret")
+		line = replaceAll(d.Pattern, group, line, &w.count)
+	}
+	return line
+}
+
+// replaceAll replaces every match of re in b, or only its group when group
+// is positive, counting replacements.
+func replaceAll(re *regexp.Regexp, group int, b []byte, count *int) []byte {
+	matches := re.FindAllSubmatchIndex(b, -1)
+	if len(matches) == 0 {
+		return b
+	}
+	var out []byte
+	last := 0
+	for _, m := range matches {
+		start, end := m[0], m[1]
+		if group > 0 {
+			start, end = m[2*group], m[2*group+1]
+			if start < 0 {
+				continue
+			}
+		}
+		if start == end || string(b[start:end]) == Placeholder {
+			continue
+		}
+		out = append(out, b[last:start]...)
+		out = append(out, Placeholder...)
+		last = end
+		*count++
+	}
+	return append(out, b[last:]...)
+}
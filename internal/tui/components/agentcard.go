@@ -30,6 +30,10 @@ type AgentInfo struct {
 	LastActive  time.Time
 	SessionID   string
 	ProjectPath string
+	// Reputation is the agent's 0-100 reputation score and ReputationLevel
+	// its bucket (unknown, low, normal, high); an empty level hides it.
+	Reputation      float64
+	ReputationLevel string
 }
 
 // AgentCard renders an agent as a styled card.
@@ -118,6 +122,10 @@ func (a *AgentCard) Render() string {
 	statusLine := fmt.Sprintf("%s  •  %s", statusBadge, dimStyle.Render(timeAgo))
 	lines = append(lines, statusLine)
 
+	if badge := a.reputationBadge(); badge != "" {
+		lines = append(lines, fmt.Sprintf("%s %s", dimStyle.Render("reputation"), badge))
+	}
+
 	content := strings.Join(lines, "\n")
 
 	// Card style
@@ -166,6 +174,9 @@ func (a *AgentCard) renderCompact() string {
 		nameStyle.Render(a.Agent.Name),
 		dimStyle.Render(a.Agent.Program),
 	)
+	if badge := a.reputationBadge(); badge != "" {
+		compact += "  " + badge
+	}
 
 	if a.Selected {
 		return lipgloss.NewStyle().Background(t.Surface).Render(compact)
@@ -173,6 +184,26 @@ func (a *AgentCard) renderCompact() string {
 	return compact
 }
 
+// reputationBadge renders the reputation score colored by level, or "" when
+// the agent has no reputation.
+func (a *AgentCard) reputationBadge() string {
+	t := theme.Current
+	var color lipgloss.Color
+	switch a.Agent.ReputationLevel {
+	case "":
+		return ""
+	case "unknown":
+		return lipgloss.NewStyle().Foreground(t.Subtext).Render("rep ?")
+	case "low":
+		color = t.Red
+	case "high":
+		color = t.Green
+	default:
+		color = t.Yellow
+	}
+	return lipgloss.NewStyle().Foreground(color).Render(fmt.Sprintf("rep %.0f", a.Agent.Reputation))
+}
+
 // formatTimeAgo formats a time as a human-readable "ago" string.
 func formatTimeAgo(t time.Time) string {
 	if t.IsZero() {
@@ -147,6 +147,26 @@ func TestRenderAgentCardCompact(t *testing.T) {
 	}
 }
 
+func TestAgentCardReputation(t *testing.T) {
+	agent := AgentInfo{Name: "Test", Program: "test", Model: "test", Status: AgentStatusActive}
+	if got := RenderAgentCardCompact(agent); strings.Contains(got, "rep") {
+		t.Errorf("card without reputation shows one: %q", got)
+	}
+
+	agent.Reputation = 42
+	agent.ReputationLevel = "low"
+	for _, got := range []string{RenderAgentCard(agent), RenderAgentCardCompact(agent)} {
+		if !strings.Contains(got, "rep 42") {
+			t.Errorf("expected reputation score in %q", got)
+		}
+	}
+
+	agent.ReputationLevel = "unknown"
+	if got := RenderAgentCardCompact(agent); !strings.Contains(got, "rep ?") {
+		t.Errorf("expected unknown reputation marker in %q", got)
+	}
+}
+
 func TestAgentStatusConstants(t *testing.T) {
 	if AgentStatusActive != "active" {
 		t.Errorf("AgentStatusActive: expected 'active', got %q", AgentStatusActive)
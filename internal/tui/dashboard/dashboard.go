@@ -2,6 +2,7 @@ package dashboard
 
 import (
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"strings"
@@ -10,13 +11,27 @@ import (
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 
+	"github.com/Dicklesworthstone/slb/internal/core"
 	"github.com/Dicklesworthstone/slb/internal/db"
 	"github.com/Dicklesworthstone/slb/internal/tui/components"
+	"github.com/Dicklesworthstone/slb/internal/tui/live"
 	"github.com/Dicklesworthstone/slb/internal/tui/theme"
 )
 
 const refreshInterval = 2 * time.Second
 
+// A new CRITICAL request flashes for flashDuration, toggling every flashBlink.
+const (
+	flashDuration = 3 * time.Second
+	flashBlink    = 400 * time.Millisecond
+)
+
+// maxActivity bounds the activity panel when fed by live events.
+const maxActivity = 50
+
+// bellOut receives the terminal bell for new CRITICAL requests.
+var bellOut io.Writer = os.Stderr
+
 type focusPanel int
 
 const (
@@ -27,6 +42,7 @@ const (
 
 type requestRow struct {
 	ID        string
+	Project   string
 	Tier      string
 	Command   string
 	Requestor string
@@ -35,10 +51,13 @@ type requestRow struct {
 
 type refreshMsg struct{}
 
+type flashMsg struct{}
+
 type dataMsg struct {
 	agents      []components.AgentInfo
 	pending     []requestRow
 	activity    []string
+	freezes     []*db.Freeze
 	err         error
 	refreshedAt time.Time
 }
@@ -47,6 +66,14 @@ type dataMsg struct {
 type Model struct {
 	projectPath string
 
+	// projects lists every project shown (primary first); projectFilter
+	// narrows the panels to one of them.
+	projects      []string
+	projectFilter string
+
+	// marked holds the IDs of pending requests selected for a batch review.
+	marked map[string]bool
+
 	ready  bool
 	width  int
 	height int
@@ -69,6 +96,24 @@ type Model struct {
 	lastErr     error
 	lastRefresh time.Time
 
+	// Live updates: while conn is StateLive the dashboard is driven by daemon
+	// events and only polls when degraded.
+	conn         live.State
+	refreshEvery time.Duration
+	loaded       bool
+
+	// New CRITICAL request highlight and optional terminal bell.
+	bell       bool
+	flashID    string
+	flashOn    bool
+	flashUntil time.Time
+
+	// reputation scores the agents shown on the agent cards.
+	reputation core.ReputationConfig
+
+	// freezes are the active change freezes of the shown projects.
+	freezes []*db.Freeze
+
 	// Callbacks
 	OnPatterns func() // Navigate to pattern management view
 	OnHistory  func() // Navigate to history view
@@ -82,13 +127,41 @@ func New(projectPath string) Model {
 		}
 	}
 	return Model{
-		projectPath: projectPath,
-		focus:       focusPending,
+		projectPath:  projectPath,
+		focus:        focusPending,
+		refreshEvery: refreshInterval,
+		reputation:   core.DefaultReputationConfig(),
 	}
 }
 
+// WithReputation sets how agent reputation shown on the agent cards is scored.
+func (m Model) WithReputation(cfg core.ReputationConfig) Model {
+	m.reputation = cfg
+	return m
+}
+
+// WithRefreshInterval sets the polling interval used while the daemon is unreachable.
+func (m Model) WithRefreshInterval(d time.Duration) Model {
+	if d > 0 {
+		m.refreshEvery = d
+	}
+	return m
+}
+
+// WithBell rings the terminal bell when a new CRITICAL request arrives.
+func (m Model) WithBell(enabled bool) Model {
+	m.bell = enabled
+	return m
+}
+
+// WithConnection sets the current event stream state.
+func (m Model) WithConnection(state live.State) Model {
+	m.conn = state
+	return m
+}
+
 func (m Model) Init() tea.Cmd {
-	return tea.Batch(loadCmd(m.projectPath), tickCmd())
+	return tea.Batch(m.Reload(), tickCmd(m.refreshEvery))
 }
 
 func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
@@ -99,19 +172,61 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.ready = true
 		return m, nil
 	case refreshMsg:
-		return m, tea.Batch(loadCmd(m.projectPath), tickCmd())
+		// Events keep the view current while live; only poll when degraded.
+		if m.conn == live.StateLive {
+			return m, tickCmd(m.refreshEvery)
+		}
+		return m, tea.Batch(m.Reload(), tickCmd(m.refreshEvery))
+	case live.StatusMsg:
+		m.conn = msg.State
+		if msg.State == live.StateLive {
+			// Resync: events may have been missed while disconnected.
+			return m, m.Reload()
+		}
+		return m, nil
+	case live.EventMsg:
+		return m.applyEvent(msg)
+	case flashMsg:
+		if m.flashID == "" {
+			return m, nil
+		}
+		if !time.Now().Before(m.flashUntil) {
+			m.flashID = ""
+			m.flashOn = false
+			return m, nil
+		}
+		m.flashOn = !m.flashOn
+		return m, flashCmd()
 	case dataMsg:
+		var cmd tea.Cmd
+		if m.loaded && msg.err == nil {
+			known := make(map[string]bool, len(m.pending))
+			for _, r := range m.pending {
+				known[r.ID] = true
+			}
+			for _, r := range msg.pending {
+				if !known[r.ID] && r.Tier == string(db.RiskTierCritical) {
+					cmd = m.startFlash(r.ID)
+				}
+			}
+		}
+		if msg.err == nil {
+			m.loaded = true
+		}
+
 		m.agents = msg.agents
 		m.pending = msg.pending
+		m.pruneMarks()
 		m.activity = msg.activity
+		m.freezes = msg.freezes
 		m.lastErr = msg.err
 		m.lastRefresh = msg.refreshedAt
 
-		m.agentSel, m.agentOff = clampSelection(m.agentSel, m.agentOff, len(m.agents), m.visibleRows())
-		m.pendingSel, m.pendingOff = clampSelection(m.pendingSel, m.pendingOff, len(m.pending), m.visibleRows())
+		m.agentSel, m.agentOff = clampSelection(m.agentSel, m.agentOff, len(m.visibleAgents()), m.visibleRows())
+		m.pendingSel, m.pendingOff = clampSelection(m.pendingSel, m.pendingOff, len(m.visiblePending()), m.visibleRows())
 		m.activitySel, m.activityOff = clampSelection(m.activitySel, m.activityOff, len(m.activity), m.visibleRows())
 
-		return m, nil
+		return m, cmd
 	case tea.KeyMsg:
 		switch msg.String() {
 		case "q", "ctrl+c":
@@ -134,6 +249,23 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		case "down", "j":
 			m.moveSelection(1)
 			return m, nil
+		case "p":
+			m.cycleProjectFilter()
+			return m, nil
+		case " ":
+			if m.focus == focusPending {
+				m.toggleMark()
+			}
+			return m, nil
+		case "*":
+			if m.focus == focusPending {
+				m.toggleMarkAll()
+			}
+			return m, nil
+		case "a":
+			return m, m.reviewSelectionCmd(db.DecisionApprove)
+		case "x":
+			return m, m.reviewSelectionCmd(db.DecisionReject)
 		case "m":
 			if m.OnPatterns != nil {
 				m.OnPatterns()
@@ -153,6 +285,148 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
+// applyEvent updates the model incrementally from a daemon event. Events for
+// other projects are ignored; events it cannot apply trigger a reload.
+func (m Model) applyEvent(msg live.EventMsg) (tea.Model, tea.Cmd) {
+	ev := msg.RequestEvent()
+	project := m.projectPath
+	if payload, ok := msg.Event.Payload.(map[string]any); ok {
+		if p, ok := payload["project_path"].(string); ok && p != "" {
+			if !m.hasProject(p) {
+				return m, nil
+			}
+			project = p
+		}
+	}
+
+	at := time.Unix(msg.Event.Time, 0)
+	if msg.Event.Time == 0 {
+		at = time.Now()
+	}
+
+	var activity string
+	var cmd tea.Cmd
+	switch ev.Event {
+	case "request_pending":
+		if ev.RequestID == "" || ev.Command == "" {
+			return m, m.Reload()
+		}
+		if m.pendingIndex(ev.RequestID) >= 0 {
+			return m, nil
+		}
+		m.pending = append(m.pending, requestRow{
+			ID:        ev.RequestID,
+			Project:   project,
+			Tier:      ev.RiskTier,
+			Command:   ev.Command,
+			Requestor: ev.Requestor,
+			CreatedAt: at,
+		})
+		activity = fmt.Sprintf("Pending %s by %s", shortID(ev.RequestID), ev.Requestor)
+		if ev.RiskTier == string(db.RiskTierCritical) {
+			cmd = m.startFlash(ev.RequestID)
+		}
+	case "request_approved":
+		m.removePending(ev.RequestID)
+		activity = fmt.Sprintf("Approved %s by %s", shortID(ev.RequestID), ev.ApprovedBy)
+	case "request_rejected":
+		m.removePending(ev.RequestID)
+		activity = fmt.Sprintf("Rejected %s by %s", shortID(ev.RequestID), ev.RejectedBy)
+	case "request_executed":
+		m.removePending(ev.RequestID)
+		if ev.ExitCode != nil {
+			activity = fmt.Sprintf("Executed %s (exit %d)", shortID(ev.RequestID), *ev.ExitCode)
+		} else {
+			activity = fmt.Sprintf("Executed %s", shortID(ev.RequestID))
+		}
+	case "request_cancelled":
+		m.removePending(ev.RequestID)
+		activity = fmt.Sprintf("Cancelled %s", shortID(ev.RequestID))
+	case "request_timeout":
+		m.removePending(ev.RequestID)
+		activity = fmt.Sprintf("Timed out %s", shortID(ev.RequestID))
+	case "request_escalated":
+		m.removePending(ev.RequestID)
+		activity = fmt.Sprintf("Escalated %s", shortID(ev.RequestID))
+	case "review_submitted":
+		activity = fmt.Sprintf("Review on %s", shortID(ev.RequestID))
+	case "execution_aborted":
+		activity = fmt.Sprintf("Aborted %s by %s", shortID(ev.RequestID), ev.AbortedBy)
+	case "freeze_started":
+		activity = fmt.Sprintf("Freeze started by %s: %s", ev.FrozenBy, ev.Reason)
+		cmd = m.Reload()
+	case "freeze_ended":
+		activity = fmt.Sprintf("Freeze ended by %s", ev.EndedBy)
+		cmd = m.Reload()
+	case "session_started", "session_ended", "session_resumed":
+		return m, m.Reload()
+	default:
+		return m, nil
+	}
+
+	if activity != "" {
+		if m.multiProject() {
+			activity = "[" + projectLabel(project) + "] " + activity
+		}
+		m.activity = append([]string{activity + " (" + at.Local().Format("15:04:05") + ")"}, m.activity...)
+		if len(m.activity) > maxActivity {
+			m.activity = m.activity[:maxActivity]
+		}
+	}
+	m.lastRefresh = time.Now().UTC()
+	m.pendingSel, m.pendingOff = clampSelection(m.pendingSel, m.pendingOff, len(m.visiblePending()), m.visibleRows())
+	m.activitySel, m.activityOff = clampSelection(m.activitySel, m.activityOff, len(m.activity), m.visibleRows())
+	return m, cmd
+}
+
+func (m *Model) pendingIndex(id string) int {
+	for i, r := range m.pending {
+		if r.ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+func (m *Model) removePending(id string) {
+	if i := m.pendingIndex(id); i >= 0 {
+		m.pending = append(m.pending[:i:i], m.pending[i+1:]...)
+	}
+	delete(m.marked, id)
+	if m.flashID == id {
+		m.flashID = ""
+		m.flashOn = false
+	}
+}
+
+// startFlash highlights a new CRITICAL request and rings the bell if enabled.
+func (m *Model) startFlash(id string) tea.Cmd {
+	alreadyFlashing := m.flashID != ""
+	m.flashID = id
+	m.flashOn = true
+	m.flashUntil = time.Now().Add(flashDuration)
+
+	var cmds []tea.Cmd
+	if !alreadyFlashing {
+		cmds = append(cmds, flashCmd())
+	}
+	if m.bell {
+		cmds = append(cmds, bellCmd())
+	}
+	return tea.Batch(cmds...)
+}
+
+func flashCmd() tea.Cmd {
+	return tea.Tick(flashBlink, func(time.Time) tea.Msg { return flashMsg{} })
+}
+
+func bellCmd() tea.Cmd {
+	return func() tea.Msg {
+		_, _ = io.WriteString(bellOut, "\a")
+		return nil
+	}
+}
+
 func (m Model) View() string {
 	if !m.ready {
 		return "Loading..."
@@ -199,8 +473,29 @@ func (m Model) renderHeader() string {
 	th := theme.Current
 
 	title := lipgloss.NewStyle().Foreground(th.Mauve).Bold(true).Render("SLB Dashboard")
-	statusDot := lipgloss.NewStyle().Foreground(th.Yellow).Render("●")
-	daemon := lipgloss.NewStyle().Foreground(th.Subtext).Render(fmt.Sprintf("%s Daemon: unknown", statusDot))
+	if m.flashID != "" && m.flashOn {
+		title += "  " + lipgloss.NewStyle().Foreground(th.Base).Background(th.Red).Bold(true).Padding(0, 1).Render("NEW CRITICAL REQUEST")
+	}
+
+	dotColor, label := th.Yellow, "Daemon: connecting"
+	switch m.conn {
+	case live.StateLive:
+		dotColor, label = th.Green, "Daemon: live"
+	case live.StateDegraded:
+		dotColor, label = th.Peach, fmt.Sprintf("Daemon: offline (polling every %s)", m.refreshEvery)
+	}
+	statusDot := lipgloss.NewStyle().Foreground(dotColor).Render("●")
+	daemon := lipgloss.NewStyle().Foreground(th.Subtext).Render(fmt.Sprintf("%s %s", statusDot, label))
+	if m.multiProject() {
+		scope := fmt.Sprintf("Projects: all (%d)", len(m.projects))
+		if m.projectFilter != "" {
+			scope = "Project: " + projectLabel(m.projectFilter)
+		}
+		title += "  " + lipgloss.NewStyle().Foreground(th.Subtext).Render(scope)
+	}
+	if banner := m.freezeBanner(); banner != "" {
+		title += "  " + lipgloss.NewStyle().Foreground(th.Base).Background(th.Blue).Bold(true).Padding(0, 1).Render(banner)
+	}
 
 	row := lipgloss.JoinHorizontal(lipgloss.Top,
 		title,
@@ -216,10 +511,39 @@ func (m Model) renderHeader() string {
 		Render(row)
 }
 
+// freezeBanner describes the active change freezes for the header.
+func (m Model) freezeBanner() string {
+	var shown []*db.Freeze
+	for _, f := range m.freezes {
+		if m.projectFilter == "" || f.ProjectPath == m.projectFilter {
+			shown = append(shown, f)
+		}
+	}
+	if len(shown) == 0 {
+		return ""
+	}
+	f := shown[0]
+	banner := fmt.Sprintf("FROZEN: %s+", strings.ToUpper(string(f.Tier)))
+	if f.Until != nil {
+		banner += " until " + f.Until.Local().Format("Jan 2 15:04")
+	}
+	if m.multiProject() {
+		banner += " in " + projectLabel(f.ProjectPath)
+	}
+	if len(shown) > 1 {
+		banner += fmt.Sprintf(" (+%d)", len(shown)-1)
+	}
+	return banner
+}
+
 func (m Model) renderFooter() string {
 	th := theme.Current
 
-	hint := lipgloss.NewStyle().Foreground(th.Subtext).Render("[tab] focus  [↑/↓] navigate  [m] patterns  [h] history  [q] quit")
+	keys := "[tab] focus  [↑/↓] navigate  [space] select  [a/x] approve/reject  [m] patterns  [h] history  [q] quit"
+	if m.multiProject() {
+		keys = "[tab] focus  [↑/↓] navigate  [space] select  [a/x] approve/reject  [p] project  [m] patterns  [h] history  [q] quit"
+	}
+	hint := lipgloss.NewStyle().Foreground(th.Subtext).Render(keys)
 
 	right := ""
 	if !m.lastRefresh.IsZero() {
@@ -246,20 +570,26 @@ func (m Model) renderFooter() string {
 func (m Model) renderAgentsPanel(width, height int) string {
 	th := theme.Current
 
-	title := lipgloss.NewStyle().Foreground(th.Blue).Bold(true).Render(fmt.Sprintf("Agents (%d)", len(m.agents)))
+	agents := m.visibleAgents()
+	title := lipgloss.NewStyle().Foreground(th.Blue).Bold(true).Render(fmt.Sprintf("Agents (%d)", len(agents)))
 
 	lines := []string{title}
 	visible := maxInt(1, height-4) // title + border padding
-	start, end := window(m.agentOff, len(m.agents), visible)
+	start, end := window(m.agentOff, len(agents), visible)
 
+	groupStyle := lipgloss.NewStyle().Foreground(th.Subtext).Bold(true)
 	for i := start; i < end; i++ {
-		card := components.NewAgentCard(m.agents[i]).
+		// Group sessions under a project heading when showing several projects.
+		if m.multiProject() && (i == start || agents[i].ProjectPath != agents[i-1].ProjectPath) {
+			lines = append(lines, groupStyle.Render(truncateRunes(projectLabel(agents[i].ProjectPath), width-4)))
+		}
+		card := components.NewAgentCard(agents[i]).
 			AsCompact().
 			AsSelected(i == m.agentSel && m.focus == focusAgents).
 			WithWidth(width - 4)
 		lines = append(lines, card.Render())
 	}
-	if len(m.agents) == 0 {
+	if len(agents) == 0 {
 		lines = append(lines, lipgloss.NewStyle().Foreground(th.Subtext).Render("No active sessions"))
 	}
 
@@ -280,30 +610,51 @@ func (m Model) renderAgentsPanel(width, height int) string {
 func (m Model) renderPendingPanel(width, height int) string {
 	th := theme.Current
 
-	title := lipgloss.NewStyle().Foreground(th.Blue).Bold(true).Render(fmt.Sprintf("Pending Requests (%d)", len(m.pending)))
+	rows := m.visiblePending()
+	heading := fmt.Sprintf("Pending Requests (%d)", len(rows))
+	marked := m.markedCount()
+	if marked > 0 {
+		heading += fmt.Sprintf(" • %d selected", marked)
+	}
+	title := lipgloss.NewStyle().Foreground(th.Blue).Bold(true).Render(heading)
 	lines := []string{title}
 
 	visible := maxInt(1, height-4)
-	start, end := window(m.pendingOff, len(m.pending), visible)
+	start, end := window(m.pendingOff, len(rows), visible)
 
 	lineStyle := lipgloss.NewStyle().Foreground(th.Text)
 	selectedStyle := lipgloss.NewStyle().Foreground(th.Text).Background(th.Surface1).Bold(true)
+	flashStyle := lipgloss.NewStyle().Foreground(th.Base).Background(th.Red).Bold(true)
 
 	for i := start; i < end; i++ {
-		r := m.pending[i]
+		r := rows[i]
 		emoji := theme.TierEmoji(r.Tier)
 		age := formatTimeAgo(r.CreatedAt)
 		label := fmt.Sprintf("%s %s  •  %s  •  %s", emoji, r.Command, r.Requestor, age)
+		if m.multiProject() {
+			project := truncateRunes(projectLabel(r.Project), projectColumnWidth)
+			label = fmt.Sprintf("%-*s %s", projectColumnWidth, project, label)
+		}
+		if marked > 0 {
+			box := "[ ] "
+			if m.marked[r.ID] {
+				box = "[x] "
+			}
+			label = box + label
+		}
 		label = truncateRunes(label, width-4)
 
 		style := lineStyle
 		if i == m.pendingSel && m.focus == focusPending {
 			style = selectedStyle
 		}
+		if r.ID == m.flashID && m.flashOn {
+			style = flashStyle
+		}
 		lines = append(lines, style.Render(label))
 	}
 
-	if len(m.pending) == 0 {
+	if len(rows) == 0 {
 		lines = append(lines, lipgloss.NewStyle().Foreground(th.Subtext).Render("No pending requests"))
 	}
 
@@ -372,10 +723,10 @@ func (m *Model) moveSelection(delta int) {
 	switch m.focus {
 	case focusAgents:
 		m.agentSel += delta
-		m.agentSel, m.agentOff = clampSelection(m.agentSel, m.agentOff, len(m.agents), m.visibleRows())
+		m.agentSel, m.agentOff = clampSelection(m.agentSel, m.agentOff, len(m.visibleAgents()), m.visibleRows())
 	case focusPending:
 		m.pendingSel += delta
-		m.pendingSel, m.pendingOff = clampSelection(m.pendingSel, m.pendingOff, len(m.pending), m.visibleRows())
+		m.pendingSel, m.pendingOff = clampSelection(m.pendingSel, m.pendingOff, len(m.visiblePending()), m.visibleRows())
 	case focusActivity:
 		m.activitySel += delta
 		m.activitySel, m.activityOff = clampSelection(m.activitySel, m.activityOff, len(m.activity), m.visibleRows())
@@ -388,10 +739,26 @@ func (m *Model) SelectedRequestID() string {
 	if m.focus != focusPending {
 		return ""
 	}
-	if m.pendingSel < 0 || m.pendingSel >= len(m.pending) {
+	rows := m.visiblePending()
+	if m.pendingSel < 0 || m.pendingSel >= len(rows) {
 		return ""
 	}
-	return m.pending[m.pendingSel].ID
+	return rows[m.pendingSel].ID
+}
+
+// SelectedRequestProject returns the project of the selected pending request.
+func (m *Model) SelectedRequestProject() string {
+	if m.focus != focusPending {
+		return ""
+	}
+	rows := m.visiblePending()
+	if m.pendingSel < 0 || m.pendingSel >= len(rows) {
+		return ""
+	}
+	if rows[m.pendingSel].Project == "" {
+		return m.projectPath
+	}
+	return rows[m.pendingSel].Project
 }
 
 // IsPendingFocused returns true if the pending requests panel is focused.
@@ -399,24 +766,25 @@ func (m *Model) IsPendingFocused() bool {
 	return m.focus == focusPending
 }
 
-func tickCmd() tea.Cmd {
-	return tea.Tick(refreshInterval, func(time.Time) tea.Msg { return refreshMsg{} })
+func tickCmd(every time.Duration) tea.Cmd {
+	return tea.Tick(every, func(time.Time) tea.Msg { return refreshMsg{} })
 }
 
-func loadCmd(projectPath string) tea.Cmd {
+func loadCmd(rep core.ReputationConfig, projects ...string) tea.Cmd {
 	return func() tea.Msg {
-		agents, pending, activity, err := loadData(projectPath)
+		agents, pending, activity, err := loadProjects(projects, rep)
 		return dataMsg{
 			agents:      agents,
 			pending:     pending,
 			activity:    activity,
+			freezes:     loadFreezes(projects),
 			err:         err,
 			refreshedAt: time.Now().UTC(),
 		}
 	}
 }
 
-func loadData(projectPath string) ([]components.AgentInfo, []requestRow, []string, error) {
+func loadData(projectPath string, rep core.ReputationConfig) ([]components.AgentInfo, []requestRow, []string, error) {
 	dbPath := filepath.Join(projectPath, ".slb", "state.db")
 	dbConn, err := db.OpenWithOptions(dbPath, db.OpenOptions{
 		CreateIfNotExists: false,
@@ -434,8 +802,9 @@ func loadData(projectPath string) ([]components.AgentInfo, []requestRow, []strin
 		return []components.AgentInfo{}, []requestRow{}, []string{}, err
 	}
 	agents := make([]components.AgentInfo, 0, len(sessions))
+	scores := map[string]*core.Reputation{}
 	for _, s := range sessions {
-		agents = append(agents, components.AgentInfo{
+		info := components.AgentInfo{
 			Name:        s.AgentName,
 			Program:     s.Program,
 			Model:       s.Model,
@@ -443,7 +812,18 @@ func loadData(projectPath string) ([]components.AgentInfo, []requestRow, []strin
 			LastActive:  s.LastActiveAt,
 			SessionID:   s.ID,
 			ProjectPath: s.ProjectPath,
-		})
+		}
+		// Reputation is decoration; a scoring error just leaves it off the card.
+		r, ok := scores[s.AgentName]
+		if !ok {
+			r, _ = core.AgentReputation(dbConn, s.AgentName, rep, time.Now())
+			scores[s.AgentName] = r
+		}
+		if r != nil {
+			info.Reputation = r.Score
+			info.ReputationLevel = string(r.Level)
+		}
+		agents = append(agents, info)
 	}
 
 	reqs, err := dbConn.ListPendingRequests(projectPath)
@@ -458,6 +838,7 @@ func loadData(projectPath string) ([]components.AgentInfo, []requestRow, []strin
 		}
 		pending = append(pending, requestRow{
 			ID:        r.ID,
+			Project:   r.ProjectPath,
 			Tier:      string(r.RiskTier),
 			Command:   cmd,
 			Requestor: r.RequestorAgent,
@@ -475,6 +856,27 @@ func loadData(projectPath string) ([]components.AgentInfo, []requestRow, []strin
 	return agents, pending, activity, nil
 }
 
+// loadFreezes returns the active change freezes of projects. Projects
+// without a database or freeze are skipped.
+func loadFreezes(projects []string) []*db.Freeze {
+	var freezes []*db.Freeze
+	for _, p := range projects {
+		dbConn, err := db.OpenWithOptions(filepath.Join(p, ".slb", "state.db"), db.OpenOptions{
+			CreateIfNotExists: false,
+			InitSchema:        false,
+			ReadOnly:          true,
+		})
+		if err != nil {
+			continue
+		}
+		if f, err := dbConn.GetActiveFreeze(p, time.Now()); err == nil {
+			freezes = append(freezes, f)
+		}
+		dbConn.Close()
+	}
+	return freezes
+}
+
 func classifyAgentStatus(lastActive time.Time) components.AgentStatus {
 	if lastActive.IsZero() {
 		return components.AgentStatusStale
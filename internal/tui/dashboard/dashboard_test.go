@@ -1,6 +1,7 @@
 package dashboard
 
 import (
+	"bytes"
 	"crypto/rand"
 	"encoding/hex"
 	"os"
@@ -10,8 +11,11 @@ import (
 
 	tea "github.com/charmbracelet/bubbletea"
 
+	"github.com/Dicklesworthstone/slb/internal/core"
+	"github.com/Dicklesworthstone/slb/internal/daemon"
 	"github.com/Dicklesworthstone/slb/internal/db"
 	"github.com/Dicklesworthstone/slb/internal/tui/components"
+	"github.com/Dicklesworthstone/slb/internal/tui/live"
 )
 
 func TestNew(t *testing.T) {
@@ -370,7 +374,7 @@ func TestLoadData(t *testing.T) {
 	sess := createTestSession(t, h.db, h.projectPath)
 	createTestRequest(t, h.db, sess, "rm -rf /tmp", "critical")
 
-	agents, pending, activity, err := loadData(h.projectPath)
+	agents, pending, activity, err := loadData(h.projectPath, core.DefaultReputationConfig())
 	if err != nil {
 		t.Fatalf("loadData failed: %v", err)
 	}
@@ -390,7 +394,7 @@ func TestLoadData(t *testing.T) {
 func TestLoadDataEmptyDB(t *testing.T) {
 	h := newTestHarness(t)
 
-	agents, pending, activity, err := loadData(h.projectPath)
+	agents, pending, activity, err := loadData(h.projectPath, core.DefaultReputationConfig())
 	if err != nil {
 		t.Fatalf("loadData on empty DB failed: %v", err)
 	}
@@ -407,7 +411,7 @@ func TestLoadDataEmptyDB(t *testing.T) {
 }
 
 func TestLoadDataNonexistentDB(t *testing.T) {
-	agents, pending, activity, err := loadData("/nonexistent/path")
+	agents, pending, activity, err := loadData("/nonexistent/path", core.DefaultReputationConfig())
 	// Should return error but empty data, not panic
 	if err == nil {
 		t.Error("expected error for nonexistent database")
@@ -431,7 +435,7 @@ func TestLoadDataWithMultipleRequests(t *testing.T) {
 		createTestRequest(t, h.db, sess, "test cmd", "caution")
 	}
 
-	_, pending, activity, err := loadData(h.projectPath)
+	_, pending, activity, err := loadData(h.projectPath, core.DefaultReputationConfig())
 	if err != nil {
 		t.Fatalf("loadData failed: %v", err)
 	}
@@ -450,7 +454,7 @@ func TestLoadCmd(t *testing.T) {
 
 	createTestSession(t, h.db, h.projectPath)
 
-	cmd := loadCmd(h.projectPath)
+	cmd := loadCmd(core.DefaultReputationConfig(), h.projectPath)
 	if cmd == nil {
 		t.Fatal("loadCmd should return non-nil command")
 	}
@@ -468,7 +472,7 @@ func TestLoadCmd(t *testing.T) {
 }
 
 func TestTickCmd(t *testing.T) {
-	cmd := tickCmd()
+	cmd := tickCmd(refreshInterval)
 	if cmd == nil {
 		t.Error("tickCmd should return non-nil command")
 	}
@@ -1013,7 +1017,7 @@ func TestLoadDataWithDisplayRedacted(t *testing.T) {
 		t.Fatalf("failed to create request: %v", err)
 	}
 
-	_, pending, _, err := loadData(h.projectPath)
+	_, pending, _, err := loadData(h.projectPath, core.DefaultReputationConfig())
 	if err != nil {
 		t.Fatalf("loadData failed: %v", err)
 	}
@@ -1027,3 +1031,320 @@ func TestLoadDataWithDisplayRedacted(t *testing.T) {
 		t.Errorf("expected command to be 'redacted cmd', got %q", pending[0].Command)
 	}
 }
+
+func eventMsg(eventType string, payload map[string]any) live.EventMsg {
+	return live.EventMsg{Event: daemon.Event{Type: eventType, Payload: payload, Time: time.Now().Unix()}}
+}
+
+// runCmd executes cmd and any batched commands it returns.
+func runCmd(cmd tea.Cmd) {
+	if cmd == nil {
+		return
+	}
+	if batch, ok := cmd().(tea.BatchMsg); ok {
+		for _, c := range batch {
+			runCmd(c)
+		}
+	}
+}
+
+func TestModelRefreshSkipsPollingWhenLive(t *testing.T) {
+	m := New("").WithConnection(live.StateLive)
+	_, cmd := m.Update(refreshMsg{})
+	if cmd == nil {
+		t.Fatal("expected the tick to be rescheduled")
+	}
+	if _, ok := cmd().(refreshMsg); !ok {
+		t.Error("expected only the refresh tick while live, not a reload")
+	}
+}
+
+func TestModelApplyEventAddsAndRemovesPending(t *testing.T) {
+	m := New("/proj")
+
+	updated, _ := m.Update(eventMsg(daemon.EventRequestPending, map[string]any{
+		"request_id":   "req-1",
+		"risk_tier":    "dangerous",
+		"command":      "git push --force",
+		"requestor":    "BlueLake",
+		"project_path": "/proj",
+	}))
+	model := updated.(Model)
+	if len(model.pending) != 1 || model.pending[0].Command != "git push --force" {
+		t.Fatalf("expected one pending row, got %+v", model.pending)
+	}
+	if len(model.activity) != 1 || !strings.Contains(model.activity[0], "Pending req-1") {
+		t.Errorf("unexpected activity: %v", model.activity)
+	}
+
+	// Duplicate events are idempotent.
+	updated, _ = model.Update(eventMsg(daemon.EventRequestPending, map[string]any{
+		"request_id": "req-1", "command": "git push --force", "project_path": "/proj",
+	}))
+	model = updated.(Model)
+	if len(model.pending) != 1 {
+		t.Fatalf("expected duplicate pending event to be ignored, got %d rows", len(model.pending))
+	}
+
+	updated, _ = model.Update(eventMsg(daemon.EventRequestApproved, map[string]any{
+		"request_id": "req-1", "approved_by": "GreenHill", "project_path": "/proj",
+	}))
+	model = updated.(Model)
+	if len(model.pending) != 0 {
+		t.Fatalf("expected pending row removed, got %+v", model.pending)
+	}
+	if !strings.Contains(model.activity[0], "Approved req-1 by GreenHill") {
+		t.Errorf("unexpected activity: %v", model.activity)
+	}
+}
+
+func TestModelApplyEventIgnoresOtherProjects(t *testing.T) {
+	m := New("/proj")
+	updated, cmd := m.Update(eventMsg(daemon.EventRequestPending, map[string]any{
+		"request_id": "req-1", "command": "rm -rf build", "project_path": "/other",
+	}))
+	model := updated.(Model)
+	if cmd != nil || len(model.pending) != 0 || len(model.activity) != 0 {
+		t.Fatal("expected event for another project to be ignored")
+	}
+}
+
+func TestModelApplyEventIncompleteReloads(t *testing.T) {
+	m := New("/proj")
+	_, cmd := m.Update(eventMsg(daemon.EventRequestPending, map[string]any{"request_id": "req-1"}))
+	if cmd == nil {
+		t.Fatal("expected a reload for an event missing fields")
+	}
+}
+
+func TestModelCriticalFlashAndBell(t *testing.T) {
+	var buf bytes.Buffer
+	prev := bellOut
+	bellOut = &buf
+	defer func() { bellOut = prev }()
+
+	m := New("/proj").WithBell(true)
+	updated, cmd := m.Update(eventMsg(daemon.EventRequestPending, map[string]any{
+		"request_id":   "req-crit",
+		"risk_tier":    "critical",
+		"command":      "DROP DATABASE prod",
+		"requestor":    "BlueLake",
+		"project_path": "/proj",
+	}))
+	model := updated.(Model)
+	if model.flashID != "req-crit" || !model.flashOn {
+		t.Fatalf("expected critical request to flash, got flashID=%q", model.flashID)
+	}
+	runCmd(cmd)
+	if buf.String() != "\a" {
+		t.Errorf("expected terminal bell, got %q", buf.String())
+	}
+	if !strings.Contains(model.renderHeader(), "NEW CRITICAL REQUEST") {
+		t.Error("expected header badge while flashing")
+	}
+
+	model.flashUntil = time.Now().Add(-time.Second)
+	updated, _ = model.Update(flashMsg{})
+	model = updated.(Model)
+	if model.flashID != "" {
+		t.Error("expected flash to end after its duration")
+	}
+}
+
+func TestModelDataMsgFlashesNewCritical(t *testing.T) {
+	m := New("")
+	updated, cmd := m.Update(dataMsg{pending: []requestRow{{ID: "1", Tier: "critical"}}})
+	model := updated.(Model)
+	if cmd != nil || model.flashID != "" {
+		t.Fatal("initial load should not flash")
+	}
+
+	updated, cmd = model.Update(dataMsg{pending: []requestRow{{ID: "1", Tier: "critical"}, {ID: "2", Tier: "critical"}}})
+	model = updated.(Model)
+	if cmd == nil || model.flashID != "2" {
+		t.Fatalf("expected new critical request to flash, got %q", model.flashID)
+	}
+}
+
+func TestModelStatusLiveResyncs(t *testing.T) {
+	m := New("")
+	updated, cmd := m.Update(live.StatusMsg{State: live.StateLive})
+	if updated.(Model).conn != live.StateLive {
+		t.Error("expected connection state to be recorded")
+	}
+	if cmd == nil {
+		t.Error("expected a resync load on reconnect")
+	}
+	if !strings.Contains(updated.(Model).renderHeader(), "live") {
+		t.Error("expected live indicator in header")
+	}
+}
+
+func TestLoadProjectsMergesProjects(t *testing.T) {
+	a := newTestHarness(t)
+	b := newTestHarness(t)
+	sa := createTestSession(t, a.db, a.projectPath)
+	sb := createTestSession(t, b.db, b.projectPath)
+	createTestRequest(t, a.db, sa, "rm -rf build", "dangerous")
+	createTestRequest(t, b.db, sb, "git push --force", "critical")
+
+	agents, pending, activity, err := loadProjects([]string{a.projectPath, b.projectPath, "/nonexistent/path"}, core.DefaultReputationConfig())
+	if err == nil {
+		t.Error("expected an error for the missing project")
+	}
+	if len(agents) != 2 || agents[0].ProjectPath != a.projectPath || agents[1].ProjectPath != b.projectPath {
+		t.Fatalf("expected agents grouped by project, got %+v", agents)
+	}
+	if len(pending) != 2 {
+		t.Fatalf("expected 2 pending across projects, got %d", len(pending))
+	}
+	projects := map[string]bool{}
+	for _, r := range pending {
+		projects[r.Project] = true
+	}
+	if !projects[a.projectPath] || !projects[b.projectPath] {
+		t.Errorf("expected rows tagged with their project, got %+v", pending)
+	}
+	if len(activity) != 2 || !strings.HasPrefix(activity[0], "[") {
+		t.Errorf("expected project-prefixed activity, got %v", activity)
+	}
+}
+
+func TestModelProjectFilter(t *testing.T) {
+	m := New("/a").WithProjects([]string{"/b", "/a"})
+	if got := m.Projects(); len(got) != 2 || got[0] != "/a" || got[1] != "/b" {
+		t.Fatalf("unexpected projects: %v", got)
+	}
+	m.agents = []components.AgentInfo{{Name: "A1", ProjectPath: "/a"}, {Name: "B1", ProjectPath: "/b"}}
+	m.pending = []requestRow{{ID: "r-a", Project: "/a"}, {ID: "r-b", Project: "/b"}}
+
+	if len(m.visiblePending()) != 2 {
+		t.Fatal("expected all rows without a filter")
+	}
+
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'p'}})
+	m = updated.(Model)
+	if m.ProjectFilter() != "/a" || len(m.visiblePending()) != 1 || len(m.visibleAgents()) != 1 {
+		t.Fatalf("expected filter /a, got %q", m.ProjectFilter())
+	}
+
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'p'}})
+	m = updated.(Model)
+	if m.ProjectFilter() != "/b" || m.SelectedRequestID() != "r-b" || m.SelectedRequestProject() != "/b" {
+		t.Fatalf("expected selection in /b, got %q in %q", m.SelectedRequestID(), m.SelectedRequestProject())
+	}
+
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'p'}})
+	m = updated.(Model)
+	if m.ProjectFilter() != "" {
+		t.Fatalf("expected filter to cycle back to all, got %q", m.ProjectFilter())
+	}
+}
+
+func TestModelMultiProjectEventsAndRendering(t *testing.T) {
+	m := New("/alpha").WithProjects([]string{"/beta"})
+	m.width, m.height, m.ready = 160, 30, true
+
+	updated, _ := m.Update(eventMsg(daemon.EventRequestPending, map[string]any{
+		"request_id": "req-b", "command": "make deploy", "requestor": "BlueLake", "project_path": "/beta",
+	}))
+	m = updated.(Model)
+	if len(m.pending) != 1 || m.pending[0].Project != "/beta" {
+		t.Fatalf("expected event from a member project to be applied, got %+v", m.pending)
+	}
+	if !strings.HasPrefix(m.activity[0], "[beta] ") {
+		t.Errorf("expected project-prefixed activity, got %q", m.activity[0])
+	}
+
+	updated, _ = m.Update(eventMsg(daemon.EventRequestPending, map[string]any{
+		"request_id": "req-c", "command": "ls", "project_path": "/gamma",
+	}))
+	m = updated.(Model)
+	if len(m.pending) != 1 {
+		t.Fatal("expected event from an unknown project to be ignored")
+	}
+
+	m.agents = []components.AgentInfo{{Name: "A1", ProjectPath: "/alpha"}, {Name: "B1", ProjectPath: "/beta"}}
+	view := m.View()
+	if !strings.Contains(view, "Projects: all (2)") {
+		t.Error("expected project scope in header")
+	}
+	if !strings.Contains(m.renderPendingPanel(100, 10), "beta ") {
+		t.Error("expected project column in pending panel")
+	}
+	agents := m.renderAgentsPanel(40, 12)
+	if !strings.Contains(agents, "alpha") || !strings.Contains(agents, "beta") {
+		t.Error("expected project headings in agents panel")
+	}
+}
+
+func TestModelBatchSelection(t *testing.T) {
+	m := New("/proj")
+	m.pending = []requestRow{{ID: "r1", Command: "rm -rf ./dist-a"}, {ID: "r2", Command: "rm -rf ./dist-b"}, {ID: "r3", Command: "ls"}}
+
+	space := tea.KeyMsg{Type: tea.KeySpace, Runes: []rune{' '}}
+	updated, _ := m.Update(space)
+	m = updated.(Model)
+	updated, _ = m.Update(space)
+	m = updated.(Model)
+	if m.markedCount() != 2 || !m.marked["r1"] || !m.marked["r2"] {
+		t.Fatalf("expected r1 and r2 marked, got %v", m.marked)
+	}
+	if !strings.Contains(m.renderPendingPanel(80, 10), "2 selected") {
+		t.Error("expected selection count in pending panel")
+	}
+
+	updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'a'}})
+	m = updated.(Model)
+	if cmd == nil {
+		t.Fatal("expected a review selection command")
+	}
+	msg, ok := cmd().(ReviewSelectionMsg)
+	if !ok || msg.Decision != db.DecisionApprove || len(msg.Requests) != 2 || msg.Requests[0].Project != "/proj" {
+		t.Fatalf("unexpected selection msg: %+v", msg)
+	}
+
+	// Removing a request drops its mark.
+	m.removePending("r1")
+	if m.marked["r1"] {
+		t.Error("expected mark removed with the request")
+	}
+
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'*'}})
+	m = updated.(Model)
+	if m.markedCount() != 2 {
+		t.Fatalf("expected all visible marked, got %d", m.markedCount())
+	}
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'*'}})
+	m = updated.(Model)
+	if m.markedCount() != 0 {
+		t.Fatalf("expected marks cleared, got %d", m.markedCount())
+	}
+
+	// Without marks the highlighted request is used.
+	m.pendingSel = 1
+	_, cmd = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'x'}})
+	msg = cmd().(ReviewSelectionMsg)
+	if msg.Decision != db.DecisionReject || len(msg.Requests) != 1 || msg.Requests[0].ID != "r3" {
+		t.Fatalf("expected highlighted request, got %+v", msg)
+	}
+}
+
+func TestFreezeBanner(t *testing.T) {
+	h := newTestHarness(t)
+	if err := h.db.CreateFreeze(&db.Freeze{ProjectPath: h.projectPath, Reason: "release", Tier: db.RiskTierDangerous, StartedBy: "ops"}); err != nil {
+		t.Fatalf("CreateFreeze: %v", err)
+	}
+
+	freezes := loadFreezes([]string{h.projectPath, t.TempDir()})
+	if len(freezes) != 1 || freezes[0].Reason != "release" {
+		t.Fatalf("loadFreezes = %+v", freezes)
+	}
+
+	m := New(h.projectPath)
+	m.width = 120
+	updated, _ := m.Update(dataMsg{freezes: freezes, refreshedAt: time.Now()})
+	if header := updated.(Model).renderHeader(); !strings.Contains(header, "FROZEN: DANGEROUS+") {
+		t.Errorf("expected freeze badge in header, got %q", header)
+	}
+}
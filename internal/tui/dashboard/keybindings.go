@@ -28,6 +28,11 @@ type KeyMap struct {
 	Approve key.Binding
 	Reject  key.Binding
 	Details key.Binding
+
+	// Batch selection and project filter
+	Mark    key.Binding
+	MarkAll key.Binding
+	Project key.Binding
 }
 
 // DefaultKeyMap returns the default keybindings.
@@ -97,6 +102,18 @@ func DefaultKeyMap() KeyMap {
 			key.WithKeys("d"),
 			key.WithHelp("d", "details"),
 		),
+		Mark: key.NewBinding(
+			key.WithKeys(" "),
+			key.WithHelp("space", "select request"),
+		),
+		MarkAll: key.NewBinding(
+			key.WithKeys("*"),
+			key.WithHelp("*", "select all"),
+		),
+		Project: key.NewBinding(
+			key.WithKeys("p"),
+			key.WithHelp("p", "project filter"),
+		),
 	}
 }
 
@@ -119,6 +136,7 @@ func (k KeyMap) FullHelp() [][]key.Binding {
 		{k.Up, k.Down, k.Tab, k.ShiftTab},
 		{k.FocusAgents, k.FocusRequests, k.FocusActivity},
 		{k.Select, k.Approve, k.Reject, k.Details},
+		{k.Mark, k.MarkAll, k.Project},
 		{k.Refresh, k.Help, k.Quit},
 	}
 }
@@ -0,0 +1,172 @@
+package dashboard
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sort"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/Dicklesworthstone/slb/internal/core"
+	"github.com/Dicklesworthstone/slb/internal/tui/components"
+)
+
+// projectColumnWidth is the width of the project column in the pending panel.
+const projectColumnWidth = 14
+
+// WithProjects sets the projects shown by the dashboard. The primary project
+// passed to New is always included and listed first.
+func (m Model) WithProjects(paths []string) Model {
+	m.projects = dedupeProjects(append([]string{m.projectPath}, paths...))
+	if m.projectFilter != "" && !m.hasProject(m.projectFilter) {
+		m.projectFilter = ""
+	}
+	return m
+}
+
+// Projects returns the projects shown by the dashboard.
+func (m Model) Projects() []string {
+	if len(m.projects) == 0 {
+		return []string{m.projectPath}
+	}
+	return append([]string(nil), m.projects...)
+}
+
+// ProjectFilter returns the project the view is narrowed to ("" for all).
+func (m Model) ProjectFilter() string {
+	return m.projectFilter
+}
+
+// Reload returns a command that reloads data for every project.
+func (m Model) Reload() tea.Cmd {
+	return loadCmd(m.reputation, m.Projects()...)
+}
+
+func (m Model) multiProject() bool {
+	return len(m.projects) > 1
+}
+
+func (m Model) hasProject(path string) bool {
+	for _, p := range m.Projects() {
+		if p == path {
+			return true
+		}
+	}
+	return false
+}
+
+// cycleProjectFilter steps through all projects, then back to "all".
+func (m *Model) cycleProjectFilter() {
+	projects := m.Projects()
+	if len(projects) < 2 {
+		m.projectFilter = ""
+		return
+	}
+	next := ""
+	if m.projectFilter == "" {
+		next = projects[0]
+	} else {
+		for i, p := range projects {
+			if p == m.projectFilter && i+1 < len(projects) {
+				next = projects[i+1]
+			}
+		}
+	}
+	m.projectFilter = next
+	m.agentSel, m.agentOff = 0, 0
+	m.pendingSel, m.pendingOff = 0, 0
+}
+
+// visiblePending returns the pending rows that pass the project filter.
+func (m Model) visiblePending() []requestRow {
+	if m.projectFilter == "" {
+		return m.pending
+	}
+	out := make([]requestRow, 0, len(m.pending))
+	for _, r := range m.pending {
+		if r.Project == m.projectFilter {
+			out = append(out, r)
+		}
+	}
+	return out
+}
+
+// visibleAgents returns the agents that pass the project filter.
+func (m Model) visibleAgents() []components.AgentInfo {
+	if m.projectFilter == "" {
+		return m.agents
+	}
+	out := make([]components.AgentInfo, 0, len(m.agents))
+	for _, a := range m.agents {
+		if a.ProjectPath == m.projectFilter {
+			out = append(out, a)
+		}
+	}
+	return out
+}
+
+// loadProjects loads and merges data for several projects. Agents are grouped
+// by project (in project order); pending requests are newest first. A project
+// that fails to load is skipped and its error reported alongside the others.
+func loadProjects(projects []string, rep core.ReputationConfig) ([]components.AgentInfo, []requestRow, []string, error) {
+	if len(projects) == 1 {
+		return loadData(projects[0], rep)
+	}
+
+	var (
+		agents   []components.AgentInfo
+		pending  []requestRow
+		activity []string
+		errs     []error
+	)
+	for _, p := range projects {
+		a, rows, _, err := loadData(p, rep)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", projectLabel(p), err))
+			continue
+		}
+		agents = append(agents, a...)
+		pending = append(pending, rows...)
+	}
+
+	sort.SliceStable(pending, func(i, j int) bool {
+		return pending[i].CreatedAt.After(pending[j].CreatedAt)
+	})
+	for i := 0; i < len(pending) && i < 10; i++ {
+		p := pending[i]
+		activity = append(activity, fmt.Sprintf("[%s] Pending %s by %s (%s)", projectLabel(p.Project), shortID(p.ID), p.Requestor, formatTimeAgo(p.CreatedAt)))
+	}
+
+	if agents == nil {
+		agents = []components.AgentInfo{}
+	}
+	if pending == nil {
+		pending = []requestRow{}
+	}
+	if activity == nil {
+		activity = []string{}
+	}
+	return agents, pending, activity, errors.Join(errs...)
+}
+
+// projectLabel is the short name shown for a project.
+func projectLabel(path string) string {
+	if path == "" {
+		return "?"
+	}
+	return filepath.Base(path)
+}
+
+func dedupeProjects(paths []string) []string {
+	seen := make(map[string]bool, len(paths))
+	out := make([]string, 0, len(paths))
+	for _, p := range paths {
+		if p == "" || seen[p] {
+			continue
+		}
+		seen[p] = true
+		out = append(out, p)
+	}
+	return out
+}
@@ -0,0 +1,121 @@
+package dashboard
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/Dicklesworthstone/slb/internal/db"
+)
+
+// SelectedRequest identifies a pending request and its project.
+type SelectedRequest struct {
+	ID      string
+	Project string
+}
+
+// ReviewSelectionMsg asks the parent to confirm a review of the selected
+// pending requests.
+type ReviewSelectionMsg struct {
+	Decision db.Decision
+	Requests []SelectedRequest
+}
+
+// toggleMark marks or unmarks the highlighted pending request.
+func (m *Model) toggleMark() {
+	rows := m.visiblePending()
+	if m.pendingSel < 0 || m.pendingSel >= len(rows) {
+		return
+	}
+	id := rows[m.pendingSel].ID
+	if m.marked[id] {
+		delete(m.marked, id)
+	} else {
+		if m.marked == nil {
+			m.marked = make(map[string]bool)
+		}
+		m.marked[id] = true
+	}
+	m.moveSelection(1)
+}
+
+// toggleMarkAll marks every visible pending request, or clears the marks when
+// they are all marked already.
+func (m *Model) toggleMarkAll() {
+	rows := m.visiblePending()
+	all := len(rows) > 0
+	for _, r := range rows {
+		if !m.marked[r.ID] {
+			all = false
+			break
+		}
+	}
+	if all {
+		for _, r := range rows {
+			delete(m.marked, r.ID)
+		}
+		return
+	}
+	if m.marked == nil {
+		m.marked = make(map[string]bool)
+	}
+	for _, r := range rows {
+		m.marked[r.ID] = true
+	}
+}
+
+// pruneMarks drops marks for requests that are no longer pending.
+func (m *Model) pruneMarks() {
+	for id := range m.marked {
+		if m.pendingIndex(id) < 0 {
+			delete(m.marked, id)
+		}
+	}
+}
+
+// markedCount returns how many visible pending requests are marked.
+func (m Model) markedCount() int {
+	n := 0
+	for _, r := range m.visiblePending() {
+		if m.marked[r.ID] {
+			n++
+		}
+	}
+	return n
+}
+
+// Selection returns the marked visible pending requests, or the highlighted
+// one when nothing is marked.
+func (m Model) Selection() []SelectedRequest {
+	rows := m.visiblePending()
+	var out []SelectedRequest
+	for _, r := range rows {
+		if m.marked[r.ID] {
+			out = append(out, SelectedRequest{ID: r.ID, Project: m.rowProject(r)})
+		}
+	}
+	if len(out) == 0 && m.pendingSel >= 0 && m.pendingSel < len(rows) {
+		r := rows[m.pendingSel]
+		out = append(out, SelectedRequest{ID: r.ID, Project: m.rowProject(r)})
+	}
+	return out
+}
+
+// reviewSelectionCmd requests a review of the current selection.
+func (m Model) reviewSelectionCmd(decision db.Decision) tea.Cmd {
+	if m.focus != focusPending {
+		return nil
+	}
+	selection := m.Selection()
+	if len(selection) == 0 {
+		return nil
+	}
+	return func() tea.Msg {
+		return ReviewSelectionMsg{Decision: decision, Requests: selection}
+	}
+}
+
+func (m Model) rowProject(r requestRow) string {
+	if r.Project == "" {
+		return m.projectPath
+	}
+	return r.Project
+}
@@ -15,6 +15,7 @@ import (
 
 	"github.com/Dicklesworthstone/slb/internal/db"
 	"github.com/Dicklesworthstone/slb/internal/tui/components"
+	"github.com/Dicklesworthstone/slb/internal/tui/live"
 	"github.com/Dicklesworthstone/slb/internal/tui/theme"
 )
 
@@ -118,9 +119,11 @@ type Model struct {
 	rows       []HistoryRow
 	totalCount int
 
-	// Pagination
+	// Pagination. cursors[i] is the keyset cursor that starts page i; page 0
+	// starts at "".
 	page      int
 	pageCount int
+	cursors   []string
 
 	// Selection
 	selectedIdx int
@@ -132,6 +135,8 @@ type Model struct {
 
 	// Filters
 	filters Filters
+	// savedQueries expands @name in the search query.
+	savedQueries map[string]string
 
 	// Callbacks
 	OnBack   func()
@@ -140,6 +145,9 @@ type Model struct {
 	// Error state
 	lastErr     error
 	lastRefresh time.Time
+
+	// Event stream state; polling is skipped while live.
+	conn live.State
 }
 
 // refreshMsg triggers a data refresh.
@@ -147,8 +155,10 @@ type refreshMsg struct{}
 
 // dataMsg contains loaded data.
 type dataMsg struct {
+	page        int
 	rows        []HistoryRow
 	totalCount  int
+	nextCursor  string
 	err         error
 	refreshedAt time.Time
 }
@@ -162,8 +172,8 @@ func New(projectPath string) Model {
 	}
 
 	ti := textinput.New()
-	ti.Placeholder = "Search commands, agents, reasons..."
-	ti.CharLimit = 100
+	ti.Placeholder = "Search or filter: tier:critical since:7d exit:!=0 @saved"
+	ti.CharLimit = 256
 	ti.Width = 40
 
 	return Model{
@@ -175,9 +185,21 @@ func New(projectPath string) Model {
 	}
 }
 
+// WithConnection sets the current event stream state.
+func (m Model) WithConnection(state live.State) Model {
+	m.conn = state
+	return m
+}
+
+// WithSavedQueries sets the saved queries available as @name.
+func (m Model) WithSavedQueries(saved map[string]string) Model {
+	m.savedQueries = saved
+	return m
+}
+
 // Init initializes the model.
 func (m Model) Init() tea.Cmd {
-	return tea.Batch(loadDataCmd(m.projectPath, m.searchQuery, m.filters, m.page), tickCmd())
+	return tea.Batch(m.loadData(), tickCmd())
 }
 
 // Update handles messages.
@@ -193,9 +215,27 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, nil
 
 	case refreshMsg:
-		return m, tea.Batch(loadDataCmd(m.projectPath, m.searchQuery, m.filters, m.page), tickCmd())
+		if m.conn == live.StateLive {
+			return m, tickCmd()
+		}
+		return m, tea.Batch(m.loadData(), tickCmd())
+
+	case live.StatusMsg:
+		m.conn = msg.State
+		if msg.State == live.StateLive {
+			return m, m.loadData()
+		}
+		return m, nil
+
+	case live.EventMsg:
+		// Any request event may change a visible row; reload the current page.
+		if strings.HasPrefix(msg.Event.Type, "request_") || msg.Event.Type == "review_submitted" {
+			return m, m.loadData()
+		}
+		return m, nil
 
 	case dataMsg:
+		m.setCursor(msg.page+1, msg.nextCursor)
 		m.rows = msg.rows
 		m.totalCount = msg.totalCount
 		m.lastErr = msg.err
@@ -219,7 +259,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.searching = false
 				m.page = 0
 				m.selectedIdx = 0
-				return m, loadDataCmd(m.projectPath, m.searchQuery, m.filters, m.page)
+				return m, m.loadData()
 			case "esc":
 				m.searching = false
 				m.searchInput.SetValue(m.searchQuery)
@@ -245,7 +285,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.searchInput.SetValue("")
 				m.page = 0
 				m.selectedIdx = 0
-				return m, loadDataCmd(m.projectPath, m.searchQuery, m.filters, m.page)
+				return m, m.loadData()
 			}
 			if m.OnBack != nil {
 				m.OnBack()
@@ -271,7 +311,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			if m.page < m.pageCount-1 {
 				m.page++
 				m.selectedIdx = 0
-				return m, loadDataCmd(m.projectPath, m.searchQuery, m.filters, m.page)
+				return m, m.loadData()
 			}
 			return m, nil
 
@@ -279,7 +319,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			if m.page > 0 {
 				m.page--
 				m.selectedIdx = 0
-				return m, loadDataCmd(m.projectPath, m.searchQuery, m.filters, m.page)
+				return m, m.loadData()
 			}
 			return m, nil
 
@@ -295,13 +335,13 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.filters.CycleTier()
 			m.page = 0
 			m.selectedIdx = 0
-			return m, loadDataCmd(m.projectPath, m.searchQuery, m.filters, m.page)
+			return m, m.loadData()
 
 		case key.Matches(msg, m.keyMap.FilterStatus):
 			m.filters.CycleStatus()
 			m.page = 0
 			m.selectedIdx = 0
-			return m, loadDataCmd(m.projectPath, m.searchQuery, m.filters, m.page)
+			return m, m.loadData()
 		}
 	}
 
@@ -345,7 +385,7 @@ func (m Model) renderHeader() string {
 
 	pageInfo := lipgloss.NewStyle().
 		Foreground(th.Subtext).
-		Render(fmt.Sprintf("Page %d/%d", m.page+1, m.pageCount))
+		Render(fmt.Sprintf("Page %d/%d  %s", m.page+1, m.pageCount, live.Indicator(m.conn)))
 
 	spacer := lipgloss.NewStyle().
 		Width(max(0, m.width-lipgloss.Width(title)-lipgloss.Width(pageInfo)-4)).
@@ -495,19 +535,57 @@ func tickCmd() tea.Cmd {
 	})
 }
 
-func loadDataCmd(projectPath, query string, filters Filters, page int) tea.Cmd {
+// query combines the search text with the tier and status filters.
+func (m Model) query() string {
+	parts := []string{strings.TrimSpace(m.searchQuery)}
+	if m.filters.TierFilter != "" {
+		parts = append(parts, "tier:"+m.filters.TierFilter)
+	}
+	if m.filters.StatusFilter != "" {
+		parts = append(parts, "status:"+m.filters.StatusFilter)
+	}
+	return strings.TrimSpace(strings.Join(parts, " "))
+}
+
+// loadData loads the current page.
+func (m Model) loadData() tea.Cmd {
+	cursor := ""
+	if m.page < len(m.cursors) {
+		cursor = m.cursors[m.page]
+	}
+	return loadDataCmd(m.projectPath, m.query(), m.savedQueries, m.page, cursor)
+}
+
+// setCursor records the cursor that starts page.
+func (m *Model) setCursor(page int, cursor string) {
+	for len(m.cursors) <= page {
+		m.cursors = append(m.cursors, "")
+	}
+	m.cursors[page] = cursor
+}
+
+func loadDataCmd(projectPath, query string, saved map[string]string, page int, cursor string) tea.Cmd {
 	return func() tea.Msg {
-		rows, total, err := loadHistoryData(projectPath, query, filters, page)
+		rows, total, next, err := loadHistoryData(projectPath, query, saved, cursor)
 		return dataMsg{
+			page:        page,
 			rows:        rows,
 			totalCount:  total,
+			nextCursor:  next,
 			err:         err,
 			refreshedAt: time.Now().UTC(),
 		}
 	}
 }
 
-func loadHistoryData(projectPath, query string, filters Filters, page int) ([]HistoryRow, int, error) {
+// loadHistoryData runs the history query in the database and returns one
+// page of rows, the total match count and the cursor for the next page.
+func loadHistoryData(projectPath, query string, saved map[string]string, cursor string) ([]HistoryRow, int, string, error) {
+	q, err := db.ParseRequestQuery(query, saved)
+	if err != nil {
+		return nil, 0, "", err
+	}
+
 	dbPath := filepath.Join(projectPath, ".slb", "state.db")
 	dbConn, err := db.OpenWithOptions(dbPath, db.OpenOptions{
 		CreateIfNotExists: false,
@@ -515,48 +593,21 @@ func loadHistoryData(projectPath, query string, filters Filters, page int) ([]Hi
 		ReadOnly:          true,
 	})
 	if err != nil {
-		return nil, 0, err
+		return nil, 0, "", err
 	}
 	defer dbConn.Close()
 
-	// Build search query
-	var requests []*db.Request
-	if query != "" {
-		requests, err = dbConn.SearchRequests(query)
-	} else {
-		requests, err = dbConn.ListAllRequests(projectPath)
-	}
+	page, err := dbConn.QueryRequests(q, db.QueryOptions{
+		ProjectPath: projectPath,
+		Limit:       pageSize,
+		Cursor:      cursor,
+	})
 	if err != nil {
-		return nil, 0, err
-	}
-
-	// Apply filters
-	filtered := make([]*db.Request, 0, len(requests))
-	for _, r := range requests {
-		if filters.TierFilter != "" && string(r.RiskTier) != filters.TierFilter {
-			continue
-		}
-		if filters.StatusFilter != "" && string(r.Status) != filters.StatusFilter {
-			continue
-		}
-		filtered = append(filtered, r)
+		return nil, 0, "", err
 	}
 
-	// Paginate
-	total := len(filtered)
-	start := page * pageSize
-	end := start + pageSize
-	if start > total {
-		start = total
-	}
-	if end > total {
-		end = total
-	}
-
-	page_requests := filtered[start:end]
-
-	rows := make([]HistoryRow, 0, len(page_requests))
-	for _, r := range page_requests {
+	rows := make([]HistoryRow, 0, len(page.Requests))
+	for _, r := range page.Requests {
 		cmd := r.Command.DisplayRedacted
 		if cmd == "" {
 			cmd = r.Command.Raw
@@ -572,7 +623,7 @@ func loadHistoryData(projectPath, query string, filters Filters, page int) ([]Hi
 		})
 	}
 
-	return rows, total, nil
+	return rows, page.Total, page.NextCursor, nil
 }
 
 func shortID(id string) string {
@@ -856,7 +856,7 @@ func TestLoadHistoryData(t *testing.T) {
 	createTestRequest(t, h.db, sess, "git push --force", db.RiskTierDangerous, db.StatusApproved)
 
 	// Load data
-	rows, total, err := loadHistoryData(h.projectPath, "", Filters{}, 0)
+	rows, total, _, err := loadHistoryData(h.projectPath, "", nil, "")
 	if err != nil {
 		t.Fatalf("loadHistoryData failed: %v", err)
 	}
@@ -877,7 +877,7 @@ func TestLoadHistoryDataWithSearch(t *testing.T) {
 	createTestRequest(t, h.db, sess, "npm install", db.RiskTierCaution, db.StatusApproved)
 
 	// Search for docker
-	rows, _, err := loadHistoryData(h.projectPath, "docker", Filters{}, 0)
+	rows, _, _, err := loadHistoryData(h.projectPath, "docker", nil, "")
 	if err != nil {
 		t.Fatalf("loadHistoryData with search failed: %v", err)
 	}
@@ -894,8 +894,7 @@ func TestLoadHistoryDataWithTierFilter(t *testing.T) {
 	createTestRequest(t, h.db, sess, "git status", db.RiskTierCaution, db.StatusApproved)
 
 	// Filter by critical tier
-	filters := Filters{TierFilter: string(db.RiskTierCritical)}
-	rows, total, err := loadHistoryData(h.projectPath, "", filters, 0)
+	rows, total, _, err := loadHistoryData(h.projectPath, "tier:critical", nil, "")
 	if err != nil {
 		t.Fatalf("loadHistoryData with tier filter failed: %v", err)
 	}
@@ -919,8 +918,7 @@ func TestLoadHistoryDataWithStatusFilter(t *testing.T) {
 	createTestRequest(t, h.db, sess, "ls -la", db.RiskTierCaution, db.StatusApproved)
 
 	// Filter by approved status
-	filters := Filters{StatusFilter: string(db.StatusApproved)}
-	rows, total, err := loadHistoryData(h.projectPath, "", filters, 0)
+	rows, total, _, err := loadHistoryData(h.projectPath, "status:approved", nil, "")
 	if err != nil {
 		t.Fatalf("loadHistoryData with status filter failed: %v", err)
 	}
@@ -943,7 +941,7 @@ func TestLoadHistoryDataPagination(t *testing.T) {
 	}
 
 	// First page
-	rows, total, err := loadHistoryData(h.projectPath, "", Filters{}, 0)
+	rows, total, next, err := loadHistoryData(h.projectPath, "", nil, "")
 	if err != nil {
 		t.Fatalf("loadHistoryData page 0 failed: %v", err)
 	}
@@ -956,7 +954,7 @@ func TestLoadHistoryDataPagination(t *testing.T) {
 	}
 
 	// Second page
-	rows, _, err = loadHistoryData(h.projectPath, "", Filters{}, 1)
+	rows, _, next, err = loadHistoryData(h.projectPath, "", nil, next)
 	if err != nil {
 		t.Fatalf("loadHistoryData page 1 failed: %v", err)
 	}
@@ -964,10 +962,13 @@ func TestLoadHistoryDataPagination(t *testing.T) {
 	if len(rows) != 5 {
 		t.Errorf("expected 5 rows on second page, got %d", len(rows))
 	}
+	if next != "" {
+		t.Errorf("expected no cursor after the last page, got %q", next)
+	}
 }
 
 func TestLoadHistoryDataNonexistentDB(t *testing.T) {
-	_, _, err := loadHistoryData("/nonexistent/path", "", Filters{}, 0)
+	_, _, _, err := loadHistoryData("/nonexistent/path", "", nil, "")
 	if err == nil {
 		t.Error("expected error for nonexistent database")
 	}
@@ -976,7 +977,7 @@ func TestLoadHistoryDataNonexistentDB(t *testing.T) {
 func TestLoadHistoryDataEmptyDB(t *testing.T) {
 	h := newTestHarness(t)
 
-	rows, total, err := loadHistoryData(h.projectPath, "", Filters{}, 0)
+	rows, total, _, err := loadHistoryData(h.projectPath, "", nil, "")
 	if err != nil {
 		t.Fatalf("loadHistoryData on empty DB failed: %v", err)
 	}
@@ -995,7 +996,7 @@ func TestLoadDataCmd(t *testing.T) {
 	sess := createTestSession(t, h.db, h.projectPath)
 	createTestRequest(t, h.db, sess, "test cmd", db.RiskTierCaution, db.StatusPending)
 
-	cmd := loadDataCmd(h.projectPath, "", Filters{}, 0)
+	cmd := loadDataCmd(h.projectPath, "", nil, 0, "")
 	if cmd == nil {
 		t.Fatal("loadDataCmd should return non-nil command")
 	}
@@ -1107,3 +1108,50 @@ func randHex(n int) string {
 	}
 	return hex.EncodeToString(b)[:n]
 }
+
+func TestBrowserQueryCombinesFilters(t *testing.T) {
+	m := New("")
+	m.searchQuery = " cmd:rm "
+	m.filters.SetTier(string(db.RiskTierCritical))
+	m.filters.SetStatus(string(db.StatusExecuted))
+
+	if got := m.query(); got != "cmd:rm tier:critical status:executed" {
+		t.Errorf("query() = %q", got)
+	}
+}
+
+func TestBrowserModelRecordsPageCursors(t *testing.T) {
+	m := New("")
+	updated, _ := m.Update(dataMsg{page: 0, rows: []HistoryRow{{ID: "1"}}, totalCount: pageSize + 1, nextCursor: "c1"})
+	model := updated.(Model)
+	if len(model.cursors) != 2 || model.cursors[1] != "c1" {
+		t.Fatalf("cursors = %v", model.cursors)
+	}
+
+	updated, _ = model.Update(tea.KeyMsg{Type: tea.KeyRight})
+	model = updated.(Model)
+	if model.page != 1 || model.cursors[model.page] != "c1" {
+		t.Errorf("page %d starts at %q, want c1", model.page, model.cursors[model.page])
+	}
+}
+
+func TestLoadHistoryDataQueryLanguage(t *testing.T) {
+	h := newTestHarness(t)
+
+	sess := createTestSession(t, h.db, h.projectPath)
+	createTestRequest(t, h.db, sess, "kubectl delete pod web", db.RiskTierCritical, db.StatusPending)
+	createTestRequest(t, h.db, sess, "kubectl get pods", db.RiskTierCaution, db.StatusApproved)
+
+	saved := map[string]string{"deletes": `cmd:"kubectl delete"`}
+	rows, total, _, err := loadHistoryData(h.projectPath, "@deletes -status:approved", saved, "")
+	if err != nil {
+		t.Fatalf("loadHistoryData: %v", err)
+	}
+	if total != 1 || len(rows) != 1 || rows[0].Command != "kubectl delete pod web" {
+		t.Errorf("got %d/%d rows: %+v", len(rows), total, rows)
+	}
+
+	if _, _, _, err := loadHistoryData(h.projectPath, "tier:extreme", nil, ""); err == nil {
+		t.Error("expected an error for an invalid query")
+	}
+}
@@ -0,0 +1,206 @@
+// Package live streams daemon events into the TUI.
+//
+// A Stream keeps one subscription to the daemon open, reconnecting with
+// backoff when it drops. Views receive StatusMsg when the connection changes
+// (resyncing from the database on StateLive, polling while StateDegraded) and
+// EventMsg for each daemon event.
+package live
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/Dicklesworthstone/slb/internal/daemon"
+	"github.com/Dicklesworthstone/slb/internal/tui/theme"
+)
+
+// State is the connection state of the event stream.
+type State int
+
+const (
+	// StateConnecting means no connection attempt has finished yet.
+	StateConnecting State = iota
+	// StateLive means events are streaming from the daemon.
+	StateLive
+	// StateDegraded means the daemon is unreachable; views poll instead.
+	StateDegraded
+)
+
+func (s State) String() string {
+	switch s {
+	case StateLive:
+		return "live"
+	case StateDegraded:
+		return "degraded"
+	default:
+		return "connecting"
+	}
+}
+
+// Indicator renders a compact connection indicator: a colored dot and label.
+func Indicator(s State) string {
+	th := theme.Current
+	color := th.Yellow
+	switch s {
+	case StateLive:
+		color = th.Green
+	case StateDegraded:
+		color = th.Peach
+	}
+	return lipgloss.NewStyle().Foreground(color).Render("●") + " " +
+		lipgloss.NewStyle().Foreground(th.Subtext).Render(s.String())
+}
+
+// StatusMsg reports a connection state change.
+type StatusMsg struct {
+	State State
+	Err   error
+}
+
+// EventMsg carries one daemon event.
+type EventMsg struct {
+	Event daemon.Event
+}
+
+// RequestEvent decodes the common request fields of the event payload.
+func (m EventMsg) RequestEvent() *daemon.RequestStreamEvent {
+	return daemon.ToRequestStreamEvent(m.Event)
+}
+
+// SubscribeFunc opens a subscription. The returned channel is closed when the
+// subscription ends.
+type SubscribeFunc func(ctx context.Context) (<-chan daemon.Event, error)
+
+// DaemonSubscriber subscribes through the daemon IPC socket.
+func DaemonSubscriber(socketPath string) SubscribeFunc {
+	return func(ctx context.Context) (<-chan daemon.Event, error) {
+		client := daemon.NewIPCClient(socketPath)
+		events, err := client.Subscribe(ctx)
+		if err != nil {
+			_ = client.Close()
+			return nil, err
+		}
+		return events, nil
+	}
+}
+
+// Reconnect backoff bounds.
+const (
+	minBackoff = time.Second
+	maxBackoff = 30 * time.Second
+)
+
+// Stream delivers StatusMsg and EventMsg values to a Bubble Tea program.
+type Stream struct {
+	subscribe SubscribeFunc
+	msgs      chan tea.Msg
+
+	mu     sync.Mutex
+	cancel context.CancelFunc
+}
+
+// NewStream creates a stream; call Start to begin connecting.
+func NewStream(subscribe SubscribeFunc) *Stream {
+	return &Stream{
+		subscribe: subscribe,
+		msgs:      make(chan tea.Msg, 256),
+	}
+}
+
+// Start connects in the background until ctx is cancelled or Stop is called.
+func (s *Stream) Start(ctx context.Context) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.cancel != nil {
+		return
+	}
+	ctx, s.cancel = context.WithCancel(ctx)
+	go s.run(ctx)
+}
+
+// Stop ends the stream.
+func (s *Stream) Stop() {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.cancel != nil {
+		s.cancel()
+	}
+}
+
+// Next waits for the next stream message. Re-issue it after every StatusMsg or
+// EventMsg to keep receiving.
+func (s *Stream) Next() tea.Cmd {
+	if s == nil {
+		return nil
+	}
+	return func() tea.Msg {
+		return <-s.msgs
+	}
+}
+
+func (s *Stream) run(ctx context.Context) {
+	backoff := minBackoff
+	degraded := false
+
+	for ctx.Err() == nil {
+		events, err := s.subscribe(ctx)
+		if err != nil {
+			if !degraded {
+				degraded = true
+				s.send(ctx, StatusMsg{State: StateDegraded, Err: err})
+			}
+			if !sleep(ctx, backoff) {
+				return
+			}
+			backoff = minDuration(backoff*2, maxBackoff)
+			continue
+		}
+
+		degraded = false
+		backoff = minBackoff
+		s.send(ctx, StatusMsg{State: StateLive})
+		for ev := range events {
+			s.send(ctx, EventMsg{Event: ev})
+		}
+		if ctx.Err() != nil {
+			return
+		}
+		degraded = true
+		s.send(ctx, StatusMsg{State: StateDegraded})
+	}
+}
+
+func (s *Stream) send(ctx context.Context, msg tea.Msg) {
+	select {
+	case s.msgs <- msg:
+	case <-ctx.Done():
+	}
+}
+
+func sleep(ctx context.Context, d time.Duration) bool {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+func minDuration(a, b time.Duration) time.Duration {
+	if a < b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,86 @@
+package live
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/Dicklesworthstone/slb/internal/daemon"
+)
+
+func nextMsg(t *testing.T, s *Stream) tea.Msg {
+	t.Helper()
+	ch := make(chan tea.Msg, 1)
+	go func() { ch <- s.Next()() }()
+	select {
+	case msg := <-ch:
+		return msg
+	case <-time.After(3 * time.Second):
+		t.Fatal("timed out waiting for stream message")
+		return nil
+	}
+}
+
+func TestStream_DegradedThenLive(t *testing.T) {
+	var mu sync.Mutex
+	attempts := 0
+	events := make(chan daemon.Event, 1)
+
+	s := NewStream(func(ctx context.Context) (<-chan daemon.Event, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		attempts++
+		if attempts == 1 {
+			return nil, errors.New("no daemon")
+		}
+		return events, nil
+	})
+	s.Start(context.Background())
+	defer s.Stop()
+
+	if msg, ok := nextMsg(t, s).(StatusMsg); !ok || msg.State != StateDegraded || msg.Err == nil {
+		t.Fatalf("expected degraded status first, got %#v", msg)
+	}
+	if msg, ok := nextMsg(t, s).(StatusMsg); !ok || msg.State != StateLive {
+		t.Fatalf("expected live status after reconnect, got %#v", msg)
+	}
+
+	events <- daemon.Event{Type: daemon.EventRequestPending, Payload: map[string]any{"request_id": "r1"}}
+	msg, ok := nextMsg(t, s).(EventMsg)
+	if !ok {
+		t.Fatalf("expected EventMsg")
+	}
+	if ev := msg.RequestEvent(); ev.RequestID != "r1" || ev.Event != daemon.EventRequestPending {
+		t.Fatalf("unexpected event: %+v", ev)
+	}
+
+	close(events)
+	if msg, ok := nextMsg(t, s).(StatusMsg); !ok || msg.State != StateDegraded {
+		t.Fatalf("expected degraded after the subscription closed, got %#v", msg)
+	}
+}
+
+func TestStream_NilSafe(t *testing.T) {
+	var s *Stream
+	s.Start(context.Background())
+	s.Stop()
+	if s.Next() != nil {
+		t.Fatal("expected nil cmd from nil stream")
+	}
+}
+
+func TestStateString(t *testing.T) {
+	cases := map[State]string{StateConnecting: "connecting", StateLive: "live", StateDegraded: "degraded"}
+	for s, want := range cases {
+		if s.String() != want {
+			t.Errorf("%d: got %q, want %q", s, s.String(), want)
+		}
+		if Indicator(s) == "" {
+			t.Errorf("%d: empty indicator", s)
+		}
+	}
+}
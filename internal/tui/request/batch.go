@@ -0,0 +1,286 @@
+package request
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textarea"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/Dicklesworthstone/slb/internal/db"
+	"github.com/Dicklesworthstone/slb/internal/tui/components"
+	"github.com/Dicklesworthstone/slb/internal/tui/theme"
+)
+
+// BatchKeyMap defines keybindings for the batch review screen.
+type BatchKeyMap struct {
+	Submit key.Binding
+	Cancel key.Binding
+}
+
+// DefaultBatchKeyMap returns the default keybindings.
+func DefaultBatchKeyMap() BatchKeyMap {
+	return BatchKeyMap{
+		Submit: key.NewBinding(
+			key.WithKeys("ctrl+s", "ctrl+enter"),
+			key.WithHelp("ctrl+s", "confirm"),
+		),
+		Cancel: key.NewBinding(
+			key.WithKeys("esc"),
+			key.WithHelp("esc", "cancel"),
+		),
+	}
+}
+
+// BatchResult is the outcome of one review in a batch.
+type BatchResult struct {
+	RequestID string
+	// NewStatus is set when the review changed the request status.
+	NewStatus db.RequestStatus
+	Err       error
+}
+
+// BatchResultMsg reports the outcome of a submitted batch.
+type BatchResultMsg struct {
+	Results []BatchResult
+}
+
+// BatchModel is the confirmation screen for approving or rejecting several
+// requests at once. It lists every covered command; each request is then
+// reviewed individually by OnConfirm.
+type BatchModel struct {
+	Requests []*db.Request
+	Decision db.Decision
+	Width    int
+	Height   int
+	KeyMap   BatchKeyMap
+
+	// Submitted is set once confirmed; Results holds the outcome.
+	Submitted bool
+	Cancelled bool
+	Comments  string
+	Results   []BatchResult
+
+	input     textarea.Model
+	showError bool
+	errorMsg  string
+
+	// Callbacks
+	OnConfirm func(decision db.Decision, requests []*db.Request, comments string) tea.Cmd
+	OnDone    func() tea.Cmd
+}
+
+// NewBatchModel creates a batch review screen for the given requests.
+func NewBatchModel(requests []*db.Request, decision db.Decision) *BatchModel {
+	ti := textarea.New()
+	if decision == db.DecisionReject {
+		ti.Placeholder = "Explain why you are rejecting these requests..."
+	} else {
+		ti.Placeholder = "Optional comments for every approval..."
+	}
+	ti.ShowLineNumbers = false
+	ti.SetHeight(3)
+	ti.Focus()
+
+	return &BatchModel{
+		Requests: requests,
+		Decision: decision,
+		KeyMap:   DefaultBatchKeyMap(),
+		input:    ti,
+	}
+}
+
+// Init initializes the model.
+func (m *BatchModel) Init() tea.Cmd {
+	return textarea.Blink
+}
+
+// Update handles messages.
+func (m *BatchModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.Width = msg.Width
+		m.Height = msg.Height
+		m.input.SetWidth(maxInt(20, m.Width-12))
+		return m, nil
+
+	case BatchResultMsg:
+		m.Results = msg.Results
+		return m, nil
+
+	case tea.KeyMsg:
+		// After the batch ran, any key returns.
+		if m.Submitted {
+			if m.Results != nil && m.OnDone != nil {
+				return m, m.OnDone()
+			}
+			return m, nil
+		}
+
+		switch {
+		case key.Matches(msg, m.KeyMap.Submit):
+			comments := strings.TrimSpace(m.input.Value())
+			if m.Decision == db.DecisionReject && comments == "" {
+				m.showError = true
+				m.errorMsg = "A reason is required when rejecting requests"
+				return m, nil
+			}
+			m.Comments = comments
+			m.Submitted = true
+			if m.OnConfirm != nil {
+				return m, m.OnConfirm(m.Decision, m.Requests, comments)
+			}
+			return m, nil
+
+		case key.Matches(msg, m.KeyMap.Cancel):
+			m.Cancelled = true
+			if m.OnDone != nil {
+				return m, m.OnDone()
+			}
+			return m, nil
+		}
+
+		if m.showError {
+			m.showError = false
+		}
+	}
+
+	var cmd tea.Cmd
+	m.input, cmd = m.input.Update(msg)
+	return m, cmd
+}
+
+// View renders the model.
+func (m *BatchModel) View() string {
+	th := theme.Current
+	var b strings.Builder
+
+	verb, color := "Approve", th.Green
+	if m.Decision == db.DecisionReject {
+		verb, color = "Reject", th.Red
+	}
+
+	titleStyle := lipgloss.NewStyle().Foreground(color).Bold(true).Padding(1, 0)
+	b.WriteString(titleStyle.Render(fmt.Sprintf("%s %d request(s)", verb, len(m.Requests))))
+	b.WriteString("\n\n")
+
+	// Every covered command, exactly as it will be reviewed.
+	width := maxInt(30, m.Width-12)
+	results := make(map[string]BatchResult, len(m.Results))
+	for _, r := range m.Results {
+		results[r.RequestID] = r
+	}
+	rowStyle := lipgloss.NewStyle().Foreground(th.Text).Padding(0, 2)
+	subStyle := lipgloss.NewStyle().Foreground(th.Subtext)
+	for _, r := range m.Requests {
+		mark := " "
+		if res, ok := results[r.ID]; ok {
+			if res.Err != nil {
+				mark = lipgloss.NewStyle().Foreground(th.Red).Render("✗")
+			} else {
+				mark = lipgloss.NewStyle().Foreground(th.Green).Render("✓")
+			}
+		}
+		cmd := r.Command.DisplayRedacted
+		if cmd == "" {
+			cmd = r.Command.Raw
+		}
+		header := fmt.Sprintf("%s %s %s  %s  %s", mark, components.RenderRiskIndicatorCompact(string(r.RiskTier)),
+			r.ID, subStyle.Render(filepath.Base(r.ProjectPath)), subStyle.Render(r.RequestorAgent))
+		b.WriteString(rowStyle.Render(header))
+		b.WriteString("\n")
+		b.WriteString(rowStyle.Render("    $ " + truncate(cmd, width)))
+		b.WriteString("\n")
+		if res, ok := results[r.ID]; ok {
+			note := ""
+			switch {
+			case res.Err != nil:
+				note = lipgloss.NewStyle().Foreground(th.Red).Render("    " + res.Err.Error())
+			case res.NewStatus != "":
+				note = subStyle.Render("    → " + string(res.NewStatus))
+			}
+			if note != "" {
+				b.WriteString(rowStyle.Render(note))
+				b.WriteString("\n")
+			}
+		}
+	}
+	b.WriteString("\n")
+
+	keyStyle := lipgloss.NewStyle().Foreground(th.Mauve).Bold(true)
+	descStyle := lipgloss.NewStyle().Foreground(th.Subtext)
+	footerStyle := lipgloss.NewStyle().Foreground(th.Subtext).Padding(0, 2)
+
+	switch {
+	case m.Results != nil:
+		failed := 0
+		for _, r := range m.Results {
+			if r.Err != nil {
+				failed++
+			}
+		}
+		summary := fmt.Sprintf("%d of %d reviewed", len(m.Results)-failed, len(m.Results))
+		if failed > 0 {
+			summary += fmt.Sprintf(", %d failed", failed)
+		}
+		b.WriteString(footerStyle.Render(summary + "  •  " + descStyle.Render("press any key to return")))
+	case m.Submitted:
+		b.WriteString(footerStyle.Render("Submitting reviews..."))
+	default:
+		labelStyle := lipgloss.NewStyle().Foreground(th.Blue).Bold(true).Padding(0, 2)
+		label := "Comments:"
+		if m.Decision == db.DecisionReject {
+			label = "Reason (required):"
+		}
+		b.WriteString(labelStyle.Render(label))
+		b.WriteString("\n")
+
+		borderColor := th.Overlay0
+		if m.showError {
+			borderColor = th.Red
+		}
+		inputStyle := lipgloss.NewStyle().
+			Border(lipgloss.RoundedBorder()).
+			BorderForeground(borderColor).
+			Padding(0, 1).
+			Margin(0, 2)
+		b.WriteString(inputStyle.Render(m.input.View()))
+		if m.showError {
+			b.WriteString("\n" + lipgloss.NewStyle().Foreground(th.Red).Padding(0, 2).Render(m.errorMsg))
+		}
+		b.WriteString("\n\n")
+
+		footer := keyStyle.Render("[ctrl+s]") + descStyle.Render(fmt.Sprintf(" %s all (each signed separately)", strings.ToLower(verb))) + "  " +
+			keyStyle.Render("[esc]") + descStyle.Render(" cancel")
+		b.WriteString(footerStyle.Render(footer))
+	}
+
+	panelStyle := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(color).
+		Padding(1, 2).
+		Width(maxInt(40, m.Width-4))
+
+	return panelStyle.Render(b.String())
+}
+
+func truncate(s string, max int) string {
+	r := []rune(s)
+	if max <= 0 || len(r) <= max {
+		return s
+	}
+	if max <= 1 {
+		return string(r[:max])
+	}
+	return string(r[:max-1]) + "…"
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
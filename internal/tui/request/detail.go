@@ -3,6 +3,7 @@ package request
 
 import (
 	"fmt"
+	"path/filepath"
 	"strings"
 	"time"
 
@@ -11,9 +12,12 @@ import (
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 
+	"github.com/Dicklesworthstone/slb/internal/core"
+	"github.com/Dicklesworthstone/slb/internal/daemon"
 	"github.com/Dicklesworthstone/slb/internal/db"
 	"github.com/Dicklesworthstone/slb/internal/tui/components"
 	"github.com/Dicklesworthstone/slb/internal/tui/icons"
+	"github.com/Dicklesworthstone/slb/internal/tui/live"
 	"github.com/Dicklesworthstone/slb/internal/tui/theme"
 )
 
@@ -29,6 +33,8 @@ type DetailKeyMap struct {
 	ScrollDn key.Binding
 	PageUp   key.Binding
 	PageDown key.Binding
+	NextTab  key.Binding
+	PrevTab  key.Binding
 	Quit     key.Binding
 }
 
@@ -75,6 +81,14 @@ func DefaultDetailKeyMap() DetailKeyMap {
 			key.WithKeys("pgdown", "ctrl+d"),
 			key.WithHelp("pgdown", "page down"),
 		),
+		NextTab: key.NewBinding(
+			key.WithKeys("tab", "]"),
+			key.WithHelp("tab", "next pane"),
+		),
+		PrevTab: key.NewBinding(
+			key.WithKeys("shift+tab", "["),
+			key.WithHelp("shift+tab", "previous pane"),
+		),
 		Quit: key.NewBinding(
 			key.WithKeys("ctrl+c"),
 			key.WithHelp("ctrl+c", "quit"),
@@ -94,18 +108,29 @@ const (
 	DetailModeReject
 )
 
-// DetailModel is the Bubble Tea model for request detail view.
+// DetailModel is the Bubble Tea model for request detail view. The view is
+// split into tabs (overview, dry run, attachments, reviews, similar requests);
+// on wide terminals the overview stays on the left and the selected tab is
+// shown beside it.
 type DetailModel struct {
 	Request  *db.Request
 	Reviews  []db.Review
-	Session  *db.Session // Current session for approval eligibility
+	Similar  []core.SimilarRequest // Earlier requests with the same command shape
+	Session  *db.Session           // Current session for approval eligibility
 	Width    int
 	Height   int
 	KeyMap   DetailKeyMap
 	Mode     DetailMode
 	viewport viewport.Model
+	side     viewport.Model // Context pane in split mode
 	ready    bool
 
+	// tab is the active tab; sideTab is the context tab shown beside the
+	// overview in split mode.
+	tab     int
+	sideTab int
+	images  imageProtocol
+
 	// Sub-models for forms
 	approveForm *ApproveModel
 	rejectForm  *RejectModel
@@ -119,6 +144,11 @@ type DetailModel struct {
 
 	// Copied flag for feedback
 	copied bool
+
+	// Event stream state; the request is reloaded when an event names it.
+	conn live.State
+	// output is the live execution output shown in the Output tab.
+	output liveOutput
 }
 
 // NewDetailModel creates a new request detail model.
@@ -128,15 +158,30 @@ func NewDetailModel(request *db.Request, reviews []db.Review) *DetailModel {
 		Reviews: reviews,
 		KeyMap:  DefaultDetailKeyMap(),
 		Mode:    DetailModeView,
+		sideTab: 1,
+		images:  defaultImageProtocol(),
 	}
 }
 
+// WithSimilar sets the earlier requests with the same command shape.
+func (m *DetailModel) WithSimilar(similar []core.SimilarRequest) *DetailModel {
+	m.Similar = similar
+	m.refresh()
+	return m
+}
+
 // WithSession sets the current session.
 func (m *DetailModel) WithSession(s *db.Session) *DetailModel {
 	m.Session = s
 	return m
 }
 
+// WithConnection sets the current event stream state.
+func (m *DetailModel) WithConnection(state live.State) *DetailModel {
+	m.conn = state
+	return m
+}
+
 // Init initializes the model.
 func (m *DetailModel) Init() tea.Cmd {
 	return nil
@@ -150,15 +195,7 @@ func (m *DetailModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case tea.WindowSizeMsg:
 		m.Width = msg.Width
 		m.Height = msg.Height
-		if !m.ready {
-			m.viewport = viewport.New(msg.Width, msg.Height-4) // Reserve space for header/footer
-			m.viewport.SetContent(m.renderContent())
-			m.ready = true
-		} else {
-			m.viewport.Width = msg.Width
-			m.viewport.Height = msg.Height - 4
-			m.viewport.SetContent(m.renderContent())
-		}
+		m.layout()
 		return m, nil
 
 	case tea.KeyMsg:
@@ -238,26 +275,76 @@ func (m *DetailModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, tea.Quit
 
 		case key.Matches(msg, m.KeyMap.ScrollUp):
-			m.viewport.LineUp(1)
+			m.scrollTarget().LineUp(1)
 
 		case key.Matches(msg, m.KeyMap.ScrollDn):
-			m.viewport.LineDown(1)
+			m.scrollTarget().LineDown(1)
 
 		case key.Matches(msg, m.KeyMap.PageUp):
-			m.viewport.HalfViewUp()
+			m.scrollTarget().HalfViewUp()
 
 		case key.Matches(msg, m.KeyMap.PageDown):
-			m.viewport.HalfViewDown()
+			m.scrollTarget().HalfViewDown()
+
+		case key.Matches(msg, m.KeyMap.NextTab):
+			m.cycleTab(1)
+
+		case key.Matches(msg, m.KeyMap.PrevTab):
+			m.cycleTab(-1)
+
+		default:
+			// 1-9 jump straight to a tab.
+			if s := msg.String(); len(s) == 1 && s[0] >= '1' && s[0] <= '9' {
+				m.selectTab(int(s[0] - '1'))
+			}
 		}
 
 	case clearCopiedMsg:
 		m.copied = false
+
+	case live.StatusMsg:
+		m.conn = msg.State
+		if msg.State == live.StateLive {
+			return m, m.reloadCmd()
+		}
+		return m, nil
+
+	case live.EventMsg:
+		// Output chunks are appended as they come rather than reloading
+		// the request for each one.
+		if chunk := daemon.ToExecutionOutput(msg.Event); chunk != nil {
+			if m.Request != nil && chunk.RequestID == m.Request.ID {
+				m.appendOutput(chunk)
+			}
+			return m, nil
+		}
+		if ev := msg.RequestEvent(); m.Request != nil && ev.RequestID == m.Request.ID {
+			if ev.Event == daemon.EventExecutionAborted {
+				note := "aborted by " + ev.AbortedBy
+				if ev.Reason != "" {
+					note += ": " + ev.Reason
+				}
+				m.noteOutput(note)
+			}
+			return m, m.reloadCmd()
+		}
+		return m, nil
+
+	case detailReloadedMsg:
+		if msg.err == nil && m.Request != nil && msg.request.ID == m.Request.ID {
+			m.Request = msg.request
+			m.Reviews = msg.reviews
+			m.Similar = msg.similar
+			m.refresh()
+		}
+		return m, nil
 	}
 
-	// Update viewport
+	// Update the focused viewport
 	if m.ready {
+		target := m.scrollTarget()
 		var vpCmd tea.Cmd
-		m.viewport, vpCmd = m.viewport.Update(msg)
+		*target, vpCmd = target.Update(msg)
 		cmds = append(cmds, vpCmd)
 	}
 
@@ -266,6 +353,46 @@ func (m *DetailModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 type clearCopiedMsg struct{}
 
+type detailReloadedMsg struct {
+	request *db.Request
+	reviews []db.Review
+	similar []core.SimilarRequest
+	err     error
+}
+
+// SimilarLimit caps the earlier requests shown in the Similar tab.
+const SimilarLimit = 20
+
+// reloadCmd re-reads the request, its reviews and similar requests from the
+// project database.
+func (m *DetailModel) reloadCmd() tea.Cmd {
+	if m.Request == nil || m.Request.ProjectPath == "" {
+		return nil
+	}
+	dbPath := filepath.Join(m.Request.ProjectPath, ".slb", "state.db")
+	id := m.Request.ID
+	return func() tea.Msg {
+		dbConn, err := db.OpenWithOptions(dbPath, db.OpenOptions{ReadOnly: true})
+		if err != nil {
+			return detailReloadedMsg{err: err}
+		}
+		defer dbConn.Close()
+
+		req, reviewPtrs, err := dbConn.GetRequestWithReviews(id)
+		if err != nil {
+			return detailReloadedMsg{err: err}
+		}
+		reviews := make([]db.Review, 0, len(reviewPtrs))
+		for _, r := range reviewPtrs {
+			if r != nil {
+				reviews = append(reviews, *r)
+			}
+		}
+		similar, _ := core.FindSimilarRequests(dbConn, req, SimilarLimit)
+		return detailReloadedMsg{request: req, reviews: reviews, similar: similar}
+	}
+}
+
 // View renders the model.
 func (m *DetailModel) View() string {
 	if !m.ready {
@@ -287,9 +414,11 @@ func (m *DetailModel) View() string {
 	header := m.renderHeader()
 	b.WriteString(header)
 	b.WriteString("\n")
+	b.WriteString(m.renderTabBar())
+	b.WriteString("\n")
 
 	// Scrollable content
-	b.WriteString(m.viewport.View())
+	b.WriteString(m.renderPanes())
 	b.WriteString("\n")
 
 	// Footer with keybindings
@@ -319,10 +448,11 @@ func (m *DetailModel) renderHeader() string {
 	// Tier indicator
 	tierIndicator := components.RenderRiskIndicator(string(m.Request.RiskTier))
 
-	header := fmt.Sprintf("%s  %s  %s",
+	header := fmt.Sprintf("%s  %s  %s  %s",
 		idStyle.Render(m.Request.ID),
 		statusBadge,
 		tierIndicator,
+		live.Indicator(m.conn),
 	)
 
 	headerStyle := lipgloss.NewStyle().
@@ -333,10 +463,12 @@ func (m *DetailModel) renderHeader() string {
 	return headerStyle.Render(header)
 }
 
-// renderContent renders the main scrollable content.
+// renderContent renders the overview: command, requestor, justification,
+// summaries of the dry run and attachments, timeline and reviews.
 func (m *DetailModel) renderContent() string {
 	th := theme.Current
 	var sections []string
+	width, _ := m.paneWidths()
 
 	// Command box
 	cmdBox := components.NewCommandBox(m.Request.Command.Raw).
@@ -344,11 +476,16 @@ func (m *DetailModel) renderContent() string {
 	if m.Request.Command.DisplayRedacted != "" {
 		cmdBox = cmdBox.WithRedacted(m.Request.Command.DisplayRedacted)
 	}
-	if m.Width > 0 {
-		cmdBox = cmdBox.WithMaxWidth(m.Width - 4)
+	if width > 0 {
+		cmdBox = cmdBox.WithMaxWidth(width - 4)
 	}
 	sections = append(sections, cmdBox.Render())
 
+	// Precedent from similar requests
+	if precedent := m.renderPrecedent(); precedent != "" {
+		sections = append(sections, precedent)
+	}
+
 	// Requestor info
 	requestorInfo := m.renderRequestorInfo()
 	sections = append(sections, requestorInfo)
@@ -359,6 +496,21 @@ func (m *DetailModel) renderContent() string {
 		sections = append(sections, justification)
 	}
 
+	// Execution environment fingerprint
+	if len(m.Request.Environment) > 0 {
+		sections = append(sections, m.renderEnvironment())
+	}
+
+	// Sandbox restrictions
+	if m.Request.Sandbox != nil {
+		sections = append(sections, m.renderSandbox())
+	}
+
+	// Prompts answered automatically under --pty
+	if len(m.Request.AutoAnswers) > 0 {
+		sections = append(sections, m.renderAutoAnswers())
+	}
+
 	// Dry run output
 	if m.Request.DryRun != nil && m.Request.DryRun.Output != "" {
 		dryRun := m.renderDryRun()
@@ -384,11 +536,28 @@ func (m *DetailModel) renderContent() string {
 	// Join sections with dividers
 	divider := lipgloss.NewStyle().
 		Foreground(th.Overlay0).
-		Render(strings.Repeat("─", m.Width-4))
+		Render(strings.Repeat("─", maxInt(0, width-4)))
 
 	return strings.Join(sections, "\n"+divider+"\n\n")
 }
 
+// renderPrecedent summarizes similar past requests, pointing at the
+// Similar tab. It is highlighted when any of them failed or caused problems.
+func (m *DetailModel) renderPrecedent() string {
+	if len(m.Similar) == 0 {
+		return ""
+	}
+	th := theme.Current
+	summary := core.SummarizeSimilar(m.Similar)
+	style := lipgloss.NewStyle().Foreground(th.Subtext)
+	prefix := ""
+	if summary.Problems > 0 || summary.Failed > 0 {
+		style = lipgloss.NewStyle().Foreground(th.Red).Bold(true)
+		prefix = "⚠ "
+	}
+	return style.Render(fmt.Sprintf("%s%s (press %d)", prefix, summary, m.tabIndex(tabSimilar, 0)+1))
+}
+
 // renderRequestorInfo renders requestor information.
 func (m *DetailModel) renderRequestorInfo() string {
 	th := theme.Current
@@ -460,6 +629,85 @@ func (m *DetailModel) renderJustification() string {
 	return sectionTitle + "\n" + strings.Join(lines, "\n")
 }
 
+// renderEnvironment renders the execution environment fingerprint the
+// approval is bound to.
+func (m *DetailModel) renderEnvironment() string {
+	th := theme.Current
+
+	sectionTitle := lipgloss.NewStyle().
+		Foreground(th.Blue).
+		Bold(true).
+		Render("Environment")
+
+	labelStyle := lipgloss.NewStyle().Foreground(th.Subtext).Width(22)
+	valueStyle := lipgloss.NewStyle().Foreground(th.Text)
+
+	var lines []string
+	for _, key := range m.Request.Environment.Keys() {
+		lines = append(lines, labelStyle.Render(key+":")+" "+valueStyle.Render(m.Request.Environment[key]))
+	}
+
+	return sectionTitle + "\n" + strings.Join(lines, "\n")
+}
+
+// renderSandbox renders the restrictions the command is to be executed
+// under.
+func (m *DetailModel) renderSandbox() string {
+	th := theme.Current
+	s := m.Request.Sandbox
+
+	sectionTitle := lipgloss.NewStyle().
+		Foreground(th.Blue).
+		Bold(true).
+		Render("Sandbox")
+
+	labelStyle := lipgloss.NewStyle().Foreground(th.Subtext).Width(22)
+	valueStyle := lipgloss.NewStyle().Foreground(th.Text)
+	line := func(label, value string) string {
+		return labelStyle.Render(label+":") + " " + valueStyle.Render(value)
+	}
+
+	writable := "paths named in the command"
+	if len(s.WritePaths) > 0 {
+		writable += ", " + strings.Join(s.WritePaths, ", ")
+	}
+	network := "allowed"
+	if s.DisableNetwork {
+		network = "disabled"
+	}
+	lines := []string{line("Writable", writable), line("Network", network)}
+	if s.CPUSeconds > 0 {
+		lines = append(lines, line("CPU limit", fmt.Sprintf("%ds", s.CPUSeconds)))
+	}
+	if s.MemoryMB > 0 {
+		lines = append(lines, line("Memory limit", fmt.Sprintf("%d MB", s.MemoryMB)))
+	}
+	if s.TimeoutSeconds > 0 {
+		lines = append(lines, line("Time limit", fmt.Sprintf("%ds", s.TimeoutSeconds)))
+	}
+
+	return sectionTitle + "\n" + strings.Join(lines, "\n")
+}
+
+// renderAutoAnswers renders what is typed into which prompts when the
+// command runs in a pseudo-terminal.
+func (m *DetailModel) renderAutoAnswers() string {
+	th := theme.Current
+
+	sectionTitle := lipgloss.NewStyle().
+		Foreground(th.Blue).
+		Bold(true).
+		Render("Auto-answers (--pty)")
+
+	patternStyle := lipgloss.NewStyle().Foreground(th.Subtext)
+	answerStyle := lipgloss.NewStyle().Foreground(th.Text)
+	var lines []string
+	for _, a := range m.Request.AutoAnswers {
+		lines = append(lines, patternStyle.Render(a.Pattern)+" → "+answerStyle.Render(fmt.Sprintf("%q", a.Answer)))
+	}
+	return sectionTitle + "\n" + strings.Join(lines, "\n")
+}
+
 // renderDryRun renders the dry run output section.
 func (m *DetailModel) renderDryRun() string {
 	th := theme.Current
@@ -480,7 +728,7 @@ func (m *DetailModel) renderDryRun() string {
 
 	output := m.Request.DryRun.Output
 	if len(output) > 500 {
-		output = output[:500] + "\n... (truncated)"
+		output = output[:500] + fmt.Sprintf("\n... (truncated, press %d for the full output)", m.tabIndex(tabDryRun, 0)+1)
 	}
 
 	return sectionTitle + "\n" +
@@ -510,8 +758,8 @@ func (m *DetailModel) renderAttachments() string {
 		}
 		preview = strings.ReplaceAll(preview, "\n", " ")
 
-		line := fmt.Sprintf("%d. %s %s: %s",
-			i+1, typeIcon, typeBadge,
+		line := fmt.Sprintf("[%d] %s %s: %s",
+			m.tabIndex(tabAttachment, i)+1, typeIcon, typeBadge,
 			lipgloss.NewStyle().Foreground(th.Subtext).Render(preview),
 		)
 		lines = append(lines, line)
@@ -629,10 +877,11 @@ func (m *DetailModel) renderFooter() string {
 		keys = append(keys, keyStyle.Render("[c]")+descStyle.Render("opy"))
 	}
 
+	keys = append(keys, keyStyle.Render("[tab]")+descStyle.Render(" pane"))
 	keys = append(keys, keyStyle.Render("[esc]")+descStyle.Render(" back"))
 
 	// Scroll indicator
-	scrollInfo := fmt.Sprintf(" %d%%", int(m.viewport.ScrollPercent()*100))
+	scrollInfo := fmt.Sprintf(" %d%%", int(m.scrollTarget().ScrollPercent()*100))
 	keys = append(keys, descStyle.Render(scrollInfo))
 
 	return strings.Join(keys, "  ")
@@ -0,0 +1,125 @@
+package request
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/alecthomas/chroma/v2"
+	"github.com/alecthomas/chroma/v2/lexers"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/Dicklesworthstone/slb/internal/tui/theme"
+)
+
+// tokenStyles maps chroma token types to the current theme. A token without
+// an entry of its own takes the style of its subcategory, then category.
+func tokenStyles() map[chroma.TokenType]lipgloss.Style {
+	th := theme.Current
+	fg := func(c lipgloss.TerminalColor) lipgloss.Style { return lipgloss.NewStyle().Foreground(c) }
+	return map[chroma.TokenType]lipgloss.Style{
+		chroma.Keyword:           fg(th.Mauve),
+		chroma.KeywordConstant:   fg(th.Peach),
+		chroma.KeywordType:       fg(th.Yellow),
+		chroma.NameBuiltin:       fg(th.Red),
+		chroma.NameFunction:      fg(th.Blue),
+		chroma.NameClass:         fg(th.Yellow),
+		chroma.NameTag:           fg(th.Blue),
+		chroma.NameAttribute:     fg(th.Teal),
+		chroma.NameVariable:      fg(th.Flamingo),
+		chroma.LiteralString:     fg(th.Green),
+		chroma.LiteralNumber:     fg(th.Peach),
+		chroma.Operator:          fg(th.Teal),
+		chroma.Comment:           fg(th.Overlay1).Italic(true),
+		chroma.CommentPreproc:    fg(th.Pink),
+		chroma.GenericHeading:    fg(th.Text).Bold(true),
+		chroma.GenericSubheading: fg(th.Mauve),
+		chroma.GenericInserted:   fg(th.Green),
+		chroma.GenericDeleted:    fg(th.Red),
+		chroma.GenericEmph:       lipgloss.NewStyle().Italic(true),
+		chroma.GenericStrong:     lipgloss.NewStyle().Bold(true),
+	}
+}
+
+// languageFor picks a chroma lexer name from a filename ("" when unknown).
+func languageFor(filename string) string {
+	base := filepath.Base(filename)
+	lexer := lexers.Match(base)
+	if lexer == nil {
+		lexer = lexers.Match(strings.ToLower(base))
+	}
+	if lexer == nil {
+		return ""
+	}
+	return strings.ToLower(lexer.Config().Name)
+}
+
+// highlightDiff colors a unified diff: file headers, hunk headers, additions
+// and removals.
+func highlightDiff(diff string) string {
+	return strings.Join(highlightLines(diff, "diff"), "\n")
+}
+
+// highlightCode renders source with a line-number gutter starting at
+// firstLine, colored by the chroma lexer named lang when there is one.
+func highlightCode(content, lang string, firstLine int) string {
+	th := theme.Current
+	gutter := lipgloss.NewStyle().Foreground(th.Overlay0)
+	if firstLine < 1 {
+		firstLine = 1
+	}
+
+	lines := highlightLines(content, lang)
+	width := len(fmt.Sprint(firstLine + len(lines) - 1))
+	for i, line := range lines {
+		lines[i] = gutter.Render(fmt.Sprintf("%*d │ ", width, firstLine+i)) + line
+	}
+	return strings.Join(lines, "\n")
+}
+
+// highlightLines splits content into lines colored by the lexer named lang.
+// Content of an unknown language, or one the lexer fails on, stays plain.
+func highlightLines(content, lang string) []string {
+	content = strings.TrimRight(content, "\n")
+	plain := strings.Split(content, "\n")
+	lexer := lexers.Get(lang)
+	if lang == "" || lexer == nil {
+		return plain
+	}
+	tokens, err := chroma.Coalesce(lexer).Tokenise(nil, content)
+	if err != nil {
+		return plain
+	}
+
+	styles := tokenStyles()
+	var lines []string
+	for _, line := range chroma.SplitTokensIntoLines(tokens.Tokens()) {
+		var b strings.Builder
+		for _, tok := range line {
+			value := strings.TrimRight(tok.Value, "\n")
+			if value == "" {
+				continue
+			}
+			if style, ok := styleFor(styles, tok.Type); ok {
+				value = style.Render(value)
+			}
+			b.WriteString(value)
+		}
+		lines = append(lines, b.String())
+	}
+	// Lexers may drop or add a final empty line; keep the line count of
+	// the content so the gutter matches the source.
+	for len(lines) < len(plain) {
+		lines = append(lines, "")
+	}
+	return lines[:len(plain)]
+}
+
+func styleFor(styles map[chroma.TokenType]lipgloss.Style, t chroma.TokenType) (lipgloss.Style, bool) {
+	for _, tt := range []chroma.TokenType{t, t.SubCategory(), t.Category()} {
+		if style, ok := styles[tt]; ok {
+			return style, true
+		}
+	}
+	return lipgloss.Style{}, false
+}
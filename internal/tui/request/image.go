@@ -0,0 +1,286 @@
+package request
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"image"
+	"image/color"
+	"image/color/palette"
+	"image/draw"
+	_ "image/gif"  // Register GIF format
+	_ "image/jpeg" // Register JPEG format
+	"image/png"
+	"os"
+	"strings"
+)
+
+// imageProtocol is the terminal graphics protocol used for inline images.
+type imageProtocol int
+
+const (
+	imageProtocolNone imageProtocol = iota
+	imageProtocolKitty
+	imageProtocolSixel
+)
+
+// Approximate cell size in pixels, used to size sixel output and to reserve
+// rows below an inline image.
+const (
+	cellWidthPx  = 10
+	cellHeightPx = 20
+	// maxInlineRows caps how much of the pane an inline image may take.
+	maxInlineRows = 24
+)
+
+// detectImageProtocol picks an inline image protocol from the environment.
+// SLB_TUI_IMAGES=kitty|sixel|none overrides detection. Kitty is assumed for
+// kitty, WezTerm and Ghostty; sixel for terminals that advertise it in TERM
+// or are known to support it. Everything else gets metadata only.
+func detectImageProtocol(getenv func(string) string) imageProtocol {
+	switch strings.ToLower(getenv("SLB_TUI_IMAGES")) {
+	case "kitty":
+		return imageProtocolKitty
+	case "sixel":
+		return imageProtocolSixel
+	case "none", "off":
+		return imageProtocolNone
+	}
+
+	term := strings.ToLower(getenv("TERM"))
+	program := strings.ToLower(getenv("TERM_PROGRAM"))
+	switch {
+	case getenv("KITTY_WINDOW_ID") != "", term == "xterm-kitty", term == "xterm-ghostty",
+		program == "wezterm", program == "ghostty":
+		return imageProtocolKitty
+	case strings.Contains(term, "sixel"), term == "foot", strings.HasPrefix(term, "mlterm"),
+		program == "mlterm", program == "contour":
+		return imageProtocolSixel
+	}
+	return imageProtocolNone
+}
+
+func defaultImageProtocol() imageProtocol {
+	return detectImageProtocol(os.Getenv)
+}
+
+// decodeDataURI splits a "data:<mime>;base64,<payload>" URI as produced by
+// core.LoadScreenshot.
+func decodeDataURI(uri string) (mime string, data []byte, err error) {
+	rest, ok := strings.CutPrefix(uri, "data:")
+	if !ok {
+		return "", nil, fmt.Errorf("not a data URI")
+	}
+	header, payload, ok := strings.Cut(rest, ",")
+	if !ok {
+		return "", nil, fmt.Errorf("malformed data URI")
+	}
+	mime, encoding, _ := strings.Cut(header, ";")
+	if encoding != "base64" {
+		return mime, []byte(payload), nil
+	}
+	data, err = base64.StdEncoding.DecodeString(payload)
+	if err != nil {
+		return mime, nil, fmt.Errorf("decoding image: %w", err)
+	}
+	return mime, data, nil
+}
+
+// imageInfo is the metadata shown for an image attachment.
+type imageInfo struct {
+	Mime   string
+	Format string
+	Width  int
+	Height int
+	Bytes  int
+}
+
+func inspectImage(uri string) (imageInfo, []byte, error) {
+	mime, data, err := decodeDataURI(uri)
+	if err != nil {
+		return imageInfo{}, nil, err
+	}
+	info := imageInfo{Mime: mime, Bytes: len(data)}
+	cfg, format, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return info, data, fmt.Errorf("reading image header: %w", err)
+	}
+	info.Format, info.Width, info.Height = format, cfg.Width, cfg.Height
+	return info, data, nil
+}
+
+// inlineRows is how many terminal rows an image occupies when scaled to at
+// most cols columns.
+func inlineRows(info imageInfo, cols int) int {
+	if info.Width == 0 || info.Height == 0 || cols <= 0 {
+		return 0
+	}
+	widthPx := minInt(info.Width, cols*cellWidthPx)
+	heightPx := info.Height * widthPx / info.Width
+	rows := (heightPx + cellHeightPx - 1) / cellHeightPx
+	return minInt(maxInt(rows, 1), maxInlineRows)
+}
+
+// renderInlineImage returns the escape sequence that draws the image with the
+// given protocol, followed by blank lines reserving the rows it covers. It
+// returns "" when the protocol is none or the image cannot be encoded.
+func renderInlineImage(proto imageProtocol, info imageInfo, data []byte, cols int) string {
+	rows := inlineRows(info, cols)
+	if rows == 0 {
+		return ""
+	}
+	var seq string
+	switch proto {
+	case imageProtocolKitty:
+		seq = kittyImage(info, data, cols, rows)
+	case imageProtocolSixel:
+		seq = sixelImage(data, cols, rows)
+	}
+	if seq == "" {
+		return ""
+	}
+	return seq + strings.Repeat("\n", rows)
+}
+
+// kittyImage encodes the image with the kitty graphics protocol. PNG data is
+// sent as-is; other formats are re-encoded as PNG. The cursor is not moved
+// (C=1) so the caller reserves the rows itself.
+func kittyImage(info imageInfo, data []byte, cols, rows int) string {
+	if info.Format != "png" {
+		img, _, err := image.Decode(bytes.NewReader(data))
+		if err != nil {
+			return ""
+		}
+		var buf bytes.Buffer
+		if err := png.Encode(&buf, img); err != nil {
+			return ""
+		}
+		data = buf.Bytes()
+	}
+	cols = minInt(cols, (info.Width+cellWidthPx-1)/cellWidthPx)
+
+	payload := base64.StdEncoding.EncodeToString(data)
+	const chunk = 4096
+	var b strings.Builder
+	for i := 0; i < len(payload); i += chunk {
+		end := minInt(i+chunk, len(payload))
+		more := 0
+		if end < len(payload) {
+			more = 1
+		}
+		if i == 0 {
+			fmt.Fprintf(&b, "\x1b_Ga=T,f=100,q=2,C=1,c=%d,r=%d,m=%d;%s\x1b\\", cols, rows, more, payload[i:end])
+		} else {
+			fmt.Fprintf(&b, "\x1b_Gm=%d;%s\x1b\\", more, payload[i:end])
+		}
+	}
+	return b.String()
+}
+
+// sixelImage scales the image to fit cols x rows cells, quantizes it to the
+// web-safe palette and encodes it as DEC sixel graphics.
+func sixelImage(data []byte, cols, rows int) string {
+	src, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return ""
+	}
+	scaled := scaleToFit(src, cols*cellWidthPx, rows*cellHeightPx)
+	bounds := scaled.Bounds()
+	pal := color.Palette(palette.WebSafe)
+	img := image.NewPaletted(bounds, pal)
+	draw.FloydSteinberg.Draw(img, bounds, scaled, bounds.Min)
+
+	var b strings.Builder
+	b.WriteString("\x1bPq")
+	fmt.Fprintf(&b, "\"1;1;%d;%d", bounds.Dx(), bounds.Dy())
+	for i, c := range pal {
+		r, g, bl, _ := c.RGBA()
+		fmt.Fprintf(&b, "#%d;2;%d;%d;%d", i, r*100/0xffff, g*100/0xffff, bl*100/0xffff)
+	}
+
+	w, h := bounds.Dx(), bounds.Dy()
+	for band := 0; band < h; band += 6 {
+		// Collect which colors appear in this band.
+		used := make(map[uint8]bool)
+		for y := band; y < band+6 && y < h; y++ {
+			for x := 0; x < w; x++ {
+				used[img.ColorIndexAt(x, y)] = true
+			}
+		}
+		first := true
+		for idx := 0; idx < len(pal); idx++ {
+			if !used[uint8(idx)] {
+				continue
+			}
+			if !first {
+				b.WriteByte('$')
+			}
+			first = false
+			fmt.Fprintf(&b, "#%d", idx)
+			writeSixelRow(&b, img, uint8(idx), band, w, h)
+		}
+		b.WriteByte('-')
+	}
+	b.WriteString("\x1b\\")
+	return b.String()
+}
+
+// writeSixelRow emits one color plane of a six-pixel band, run-length encoded.
+func writeSixelRow(b *strings.Builder, img *image.Paletted, idx uint8, band, w, h int) {
+	var last byte
+	run := 0
+	flush := func() {
+		switch {
+		case run == 0:
+		case run > 3:
+			fmt.Fprintf(b, "!%d%c", run, last)
+		default:
+			b.WriteString(strings.Repeat(string(last), run))
+		}
+	}
+	for x := 0; x < w; x++ {
+		var bits byte
+		for dy := 0; dy < 6 && band+dy < h; dy++ {
+			if img.ColorIndexAt(x, band+dy) == idx {
+				bits |= 1 << dy
+			}
+		}
+		ch := 63 + bits
+		if ch == last && run > 0 {
+			run++
+			continue
+		}
+		flush()
+		last, run = ch, 1
+	}
+	flush()
+}
+
+// scaleToFit downsamples (nearest neighbour) so the image fits maxW x maxH,
+// preserving the aspect ratio. Images that already fit are returned as-is.
+func scaleToFit(src image.Image, maxW, maxH int) image.Image {
+	b := src.Bounds()
+	w, h := b.Dx(), b.Dy()
+	if w <= maxW && h <= maxH {
+		return src
+	}
+	scale := float64(maxW) / float64(w)
+	if s := float64(maxH) / float64(h); s < scale {
+		scale = s
+	}
+	nw, nh := maxInt(1, int(float64(w)*scale)), maxInt(1, int(float64(h)*scale))
+	dst := image.NewRGBA(image.Rect(0, 0, nw, nh))
+	for y := 0; y < nh; y++ {
+		for x := 0; x < nw; x++ {
+			dst.Set(x, y, src.At(b.Min.X+x*w/nw, b.Min.Y+y*h/nh))
+		}
+	}
+	return dst
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
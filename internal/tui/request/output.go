@@ -0,0 +1,122 @@
+package request
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/Dicklesworthstone/slb/internal/daemon"
+	"github.com/Dicklesworthstone/slb/internal/db"
+	"github.com/Dicklesworthstone/slb/internal/tui/theme"
+)
+
+// maxLiveOutput bounds the execution output kept for the Output tab; older
+// output is trimmed (the execution log has all of it).
+const maxLiveOutput = 256 * 1024
+
+// liveOutput is the execution output received from the daemon for the
+// request being viewed.
+type liveOutput struct {
+	text    []byte
+	seq     int64
+	trimmed bool
+	// notes are gaps, drops and aborts reported alongside the output.
+	notes []string
+}
+
+// hasOutput reports whether the Output tab applies: the request is
+// executing or output was received.
+func (m *DetailModel) hasOutput() bool {
+	return m.output.seq > 0 || len(m.output.notes) > 0 ||
+		(m.Request != nil && m.Request.Status == db.StatusExecuting)
+}
+
+// appendOutput adds an execution_output chunk and keeps the Output tab
+// scrolled to the end when it was already there.
+func (m *DetailModel) appendOutput(chunk *daemon.ExecutionOutput) {
+	o := &m.output
+	if o.seq > 0 && chunk.Seq > o.seq+1 {
+		o.notes = append(o.notes, fmt.Sprintf("%d output chunk(s) missed", chunk.Seq-o.seq-1))
+	}
+	if chunk.Dropped > 0 {
+		o.notes = append(o.notes, fmt.Sprintf("%d bytes of output dropped", chunk.Dropped))
+	}
+	o.seq = chunk.Seq
+	o.text = append(o.text, chunk.Data...)
+	if len(o.text) > maxLiveOutput {
+		rest := o.text[len(o.text)-maxLiveOutput:]
+		if i := bytes.IndexByte(rest, '\n'); i >= 0 {
+			rest = rest[i+1:]
+		}
+		o.text = append([]byte(nil), rest...)
+		o.trimmed = true
+	}
+	m.refreshOutput()
+}
+
+// noteOutput records an event shown below the output, e.g. an abort.
+func (m *DetailModel) noteOutput(note string) {
+	m.output.notes = append(m.output.notes, note)
+	m.refreshOutput()
+}
+
+// refreshOutput re-renders after new output, following the end of the
+// Output tab when it is visible and was scrolled to the bottom.
+func (m *DetailModel) refreshOutput() {
+	if !m.ready {
+		return
+	}
+	i := m.tabIndex(tabOutput, 0)
+	visible := i >= 0 && (i == m.tab || (m.split() && m.contextTab() == i))
+	if !visible {
+		m.refresh() // the tab may have just appeared
+		return
+	}
+	vp := &m.viewport
+	if m.split() {
+		vp = &m.side
+	}
+	follow := vp.AtBottom()
+	m.refresh()
+	if follow {
+		vp.GotoBottom()
+	}
+}
+
+// renderOutputPane shows the live execution output.
+func (m *DetailModel) renderOutputPane(width int) string {
+	th := theme.Current
+	subStyle := lipgloss.NewStyle().Foreground(th.Subtext)
+	title := lipgloss.NewStyle().Foreground(th.Blue).Bold(true).Render("Execution Output")
+
+	status := "waiting for output…"
+	if m.Request != nil {
+		switch m.Request.Status {
+		case db.StatusExecuting:
+			status = "executing — abort with: slb abort " + m.Request.ID
+		case db.StatusApproved:
+			status = "approved — waiting for execution"
+		default:
+			status = string(m.Request.Status)
+			if e := m.Request.Execution; e != nil && e.ExitCode != nil {
+				status += fmt.Sprintf(" (exit %d)", *e.ExitCode)
+			}
+		}
+	}
+	out := title + "\n" + subStyle.Render(status)
+	if m.output.trimmed {
+		out += "\n" + subStyle.Italic(true).Render("earlier output trimmed; see the execution log")
+	}
+
+	if text := strings.TrimRight(string(m.output.text), "\n"); text != "" {
+		out += "\n\n" + lipgloss.NewStyle().Foreground(th.Text).Width(maxInt(20, width-2)).Render(text)
+	} else if m.output.seq == 0 {
+		out += "\n\n" + subStyle.Render("Output appears here while the command runs (needs the daemon).")
+	}
+	for _, note := range m.output.notes {
+		out += "\n" + lipgloss.NewStyle().Foreground(th.Peach).Render("[slb] "+note)
+	}
+	return out
+}
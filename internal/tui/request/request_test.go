@@ -1,13 +1,22 @@
 package request
 
 import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"image"
+	"image/color"
+	"image/png"
 	"strings"
 	"testing"
 	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 
+	"github.com/Dicklesworthstone/slb/internal/core"
+	"github.com/Dicklesworthstone/slb/internal/daemon"
 	"github.com/Dicklesworthstone/slb/internal/db"
+	"github.com/Dicklesworthstone/slb/internal/tui/live"
 )
 
 // Helper to create a test request
@@ -1156,3 +1165,307 @@ func TestRenderFunctionsEdgeCases(t *testing.T) {
 		t.Error("Long dry run output should be truncated")
 	}
 }
+
+func TestBatchModel(t *testing.T) {
+	reqs := []*db.Request{
+		{ID: "r1", ProjectPath: "/p/api", RiskTier: db.RiskTierCaution, RequestorAgent: "BlueLake", Command: db.CommandSpec{Raw: "rm -rf ./dist-a"}},
+		{ID: "r2", ProjectPath: "/p/web", RiskTier: db.RiskTierDangerous, RequestorAgent: "BlueLake", Command: db.CommandSpec{Raw: "rm -rf ./dist-b", DisplayRedacted: "rm -rf ./dist-*"}},
+	}
+
+	m := NewBatchModel(reqs, db.DecisionReject)
+	m.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+	view := m.View()
+	for _, want := range []string{"Reject 2 request(s)", "rm -rf ./dist-a", "rm -rf ./dist-*", "api", "web"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("expected %q in view", want)
+		}
+	}
+
+	var confirmed []*db.Request
+	var reason string
+	done := false
+	m.OnConfirm = func(_ db.Decision, r []*db.Request, comments string) tea.Cmd {
+		confirmed, reason = r, comments
+		return nil
+	}
+	m.OnDone = func() tea.Cmd {
+		done = true
+		return nil
+	}
+
+	// A reason is required for rejections.
+	m.Update(tea.KeyMsg{Type: tea.KeyCtrlS})
+	if m.Submitted || !m.showError {
+		t.Fatal("expected validation error without a reason")
+	}
+
+	m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("too risky")})
+	m.Update(tea.KeyMsg{Type: tea.KeyCtrlS})
+	if !m.Submitted || len(confirmed) != 2 || reason != "too risky" {
+		t.Fatalf("expected confirmation with reason, got %v %q", m.Submitted, reason)
+	}
+
+	m.Update(BatchResultMsg{Results: []BatchResult{{RequestID: "r1", NewStatus: db.StatusRejected}, {RequestID: "r2", Err: errors.New("already reviewed")}}})
+	view = m.View()
+	if !strings.Contains(view, "1 of 2 reviewed, 1 failed") || !strings.Contains(view, "already reviewed") {
+		t.Errorf("expected result summary, got:\n%s", view)
+	}
+
+	m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'q'}})
+	if !done {
+		t.Error("expected any key to return after results")
+	}
+}
+
+func tabTitles(m *DetailModel) []string {
+	var titles []string
+	for _, t := range m.tabs() {
+		titles = append(titles, t.title)
+	}
+	return titles
+}
+
+func TestDetailModelTabs(t *testing.T) {
+	req := testRequest()
+	req.DryRun = &db.DryRunResult{Command: "rm -rf /tmp/test --dry-run", Output: strings.Repeat("Would remove: file\n", 60)}
+	req.Attachments = []db.Attachment{
+		{Type: db.AttachmentTypeGitDiff, Content: "--- a/x\n+++ b/x\n@@ -1 +1 @@\n-old\n+new", Metadata: map[string]any{"ref": "HEAD"}},
+		{Type: db.AttachmentTypeFile, Content: "package main\n", Metadata: map[string]any{"filename": "main.go"}},
+	}
+	exit := 1
+	similar := []core.SimilarRequest{{
+		RequestID: "REQ-OLD", Status: db.StatusExecutionFailed, Match: core.MatchExact, RequestorAgent: "OldAgent",
+		Command: "rm -rf /tmp/test", CreatedAt: time.Now().Add(-48 * time.Hour), ExitCode: &exit,
+		Decisions:      []core.SimilarDecision{{ReviewerAgent: "Approver1", Decision: db.DecisionApprove, Comments: "fine"}},
+		CausedProblems: true, ProblemDescription: "deleted live data",
+	}}
+
+	m := NewDetailModel(req, nil).WithSimilar(similar)
+	m.images = imageProtocolNone
+	m.Update(tea.WindowSizeMsg{Width: 100, Height: 30})
+
+	want := []string{"Overview", "Dry run", "diff HEAD", "main.go", "Reviews (0)", "Similar (1)"}
+	if got := tabTitles(m); strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Fatalf("tabs = %v, want %v", got, want)
+	}
+	if view := m.View(); !strings.Contains(view, "1 Overview") || !strings.Contains(view, "6 Similar (1)") {
+		t.Errorf("expected tab bar, got:\n%s", view)
+	}
+	if overview := m.renderContent(); !strings.Contains(overview, "press 2 for the full output") || !strings.Contains(overview, "[4]") ||
+		!strings.Contains(overview, "caused problems (press 6)") {
+		t.Errorf("expected overview to point at the dry run and attachment tabs, got:\n%s", overview)
+	}
+
+	// Number keys jump to a tab; the dry run pane is not truncated.
+	m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'2'}})
+	if m.tab != 1 {
+		t.Fatalf("expected dry run tab, got %d", m.tab)
+	}
+	if strings.Contains(m.viewport.View(), "truncated") {
+		t.Error("dry run pane should show the full output")
+	}
+
+	m.Update(tea.KeyMsg{Type: tea.KeyTab})
+	if !strings.Contains(m.viewport.View(), "+new") {
+		t.Errorf("expected diff pane, got:\n%s", m.viewport.View())
+	}
+
+	// shift+tab from the first tab wraps to the last.
+	m.selectTab(0)
+	m.Update(tea.KeyMsg{Type: tea.KeyShiftTab})
+	view := m.viewport.View()
+	for _, want := range []string{"REQ-OLD", "exact", "exit 1", "approve by Approver1", "deleted live data", "1 similar: 1 approved"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("expected %q in similar pane, got:\n%s", want, view)
+		}
+	}
+
+	// Out-of-range tab numbers are ignored.
+	m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'9'}})
+	if m.tab != 5 {
+		t.Errorf("expected tab to stay at 5, got %d", m.tab)
+	}
+}
+
+func TestDetailModelSplitView(t *testing.T) {
+	req := testRequest()
+	req.Attachments = []db.Attachment{{Type: db.AttachmentTypeContext, Content: "context output here", Metadata: map[string]any{"source": "git status"}}}
+
+	m := NewDetailModel(req, nil)
+	m.Update(tea.WindowSizeMsg{Width: 160, Height: 30})
+	if !m.split() {
+		t.Fatal("expected split view on a wide terminal")
+	}
+
+	// The overview stays on the left; the first context tab is beside it.
+	view := m.View()
+	if !strings.Contains(view, "rm -rf /tmp/test") || !strings.Contains(view, "context output here") {
+		t.Errorf("expected overview and context pane side by side, got:\n%s", view)
+	}
+	if m.scrollTarget() != &m.viewport {
+		t.Error("overview should be scrolled while it is the active tab")
+	}
+
+	m.Update(tea.KeyMsg{Type: tea.KeyTab})
+	if m.scrollTarget() != &m.side {
+		t.Error("context pane should be scrolled once selected")
+	}
+	m.Update(tea.KeyMsg{Type: tea.KeyTab})
+	if !strings.Contains(m.side.View(), "No reviews yet") {
+		t.Errorf("expected reviews pane beside the overview, got:\n%s", m.side.View())
+	}
+
+	// Narrowing the terminal falls back to a single pane.
+	m.Update(tea.WindowSizeMsg{Width: 80, Height: 30})
+	if m.split() || !strings.Contains(m.viewport.View(), "No reviews yet") {
+		t.Error("expected single-pane reviews tab after resize")
+	}
+}
+
+func TestHighlight(t *testing.T) {
+	diff := highlightDiff("--- a/x\n+++ b/x\n@@ -1 +1 @@\n-old\n+new\n context")
+	for _, want := range []string{"--- a/x", "@@ -1 +1 @@", "-old", "+new", " context"} {
+		if !strings.Contains(diff, want) {
+			t.Errorf("expected %q in highlighted diff", want)
+		}
+	}
+
+	code := highlightCode("func main() {\n\treturn \"x\" // done\n}", "go", 10)
+	lines := strings.Split(code, "\n")
+	if len(lines) != 3 || !strings.Contains(lines[0], "10 │") || !strings.Contains(lines[2], "12 │") {
+		t.Errorf("expected numbered lines starting at 10, got:\n%s", code)
+	}
+	for _, want := range []string{"func", "main", "\"x\"", "// done"} {
+		if !strings.Contains(code, want) {
+			t.Errorf("expected %q in highlighted code", want)
+		}
+	}
+
+	if languageFor("dir/script.PY") != "python" || languageFor("README") != "" {
+		t.Error("unexpected language detection")
+	}
+	for _, name := range []string{"main.go", "app.js", "app.ts", "lib.rs", "run.sh", "query.sql", "deploy.yaml", "config.toml", "Dockerfile"} {
+		if lang := languageFor(name); lang == "" || len(highlightLines("x\ny", lang)) != 2 {
+			t.Errorf("%s: language %q", name, lang)
+		}
+	}
+	if attachmentFirstLine(db.Attachment{Metadata: map[string]any{"lines": "42-50"}}) != 42 {
+		t.Error("expected first line from log excerpt range")
+	}
+}
+
+func testPNG(t *testing.T, w, h int) string {
+	t.Helper()
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for x := 0; x < w; x++ {
+		img.Set(x, 0, color.RGBA{R: 255, A: 255})
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatalf("encoding png: %v", err)
+	}
+	return "data:image/png;base64," + base64.StdEncoding.EncodeToString(buf.Bytes())
+}
+
+func TestDetectImageProtocol(t *testing.T) {
+	tests := []struct {
+		env  map[string]string
+		want imageProtocol
+	}{
+		{map[string]string{}, imageProtocolNone},
+		{map[string]string{"TERM": "xterm-kitty"}, imageProtocolKitty},
+		{map[string]string{"KITTY_WINDOW_ID": "1"}, imageProtocolKitty},
+		{map[string]string{"TERM_PROGRAM": "WezTerm"}, imageProtocolKitty},
+		{map[string]string{"TERM": "foot"}, imageProtocolSixel},
+		{map[string]string{"TERM": "xterm-sixel"}, imageProtocolSixel},
+		{map[string]string{"TERM": "xterm-kitty", "SLB_TUI_IMAGES": "none"}, imageProtocolNone},
+		{map[string]string{"SLB_TUI_IMAGES": "sixel"}, imageProtocolSixel},
+	}
+	for _, tt := range tests {
+		got := detectImageProtocol(func(k string) string { return tt.env[k] })
+		if got != tt.want {
+			t.Errorf("detectImageProtocol(%v) = %v, want %v", tt.env, got, tt.want)
+		}
+	}
+}
+
+func TestRenderImage(t *testing.T) {
+	uri := testPNG(t, 40, 30)
+	info, data, err := inspectImage(uri)
+	if err != nil {
+		t.Fatalf("inspectImage: %v", err)
+	}
+	if info.Format != "png" || info.Width != 40 || info.Height != 30 || info.Bytes != len(data) {
+		t.Errorf("unexpected image info %+v", info)
+	}
+
+	kitty := renderInlineImage(imageProtocolKitty, info, data, 80)
+	if !strings.HasPrefix(kitty, "\x1b_Ga=T,f=100") || !strings.Contains(kitty, "\x1b\\") {
+		t.Errorf("expected kitty graphics sequence, got %q", kitty[:min(len(kitty), 40)])
+	}
+	sixel := renderInlineImage(imageProtocolSixel, info, data, 80)
+	if !strings.HasPrefix(sixel, "\x1bPq") || !strings.Contains(sixel, "\x1b\\") {
+		t.Errorf("expected sixel sequence, got %q", sixel[:min(len(sixel), 40)])
+	}
+	if renderInlineImage(imageProtocolNone, info, data, 80) != "" {
+		t.Error("expected no inline image without a protocol")
+	}
+
+	if _, _, err := decodeDataURI("not a uri"); err == nil {
+		t.Error("expected error for non data URI")
+	}
+
+	req := testRequest()
+	req.Attachments = []db.Attachment{{Type: db.AttachmentTypeScreenshot, Content: uri, Metadata: map[string]any{"filename": "shot.png"}}}
+	m := NewDetailModel(req, nil)
+	m.images = imageProtocolNone
+	m.Update(tea.WindowSizeMsg{Width: 100, Height: 30})
+	m.selectTab(1)
+	view := m.viewport.View()
+	for _, want := range []string{"shot.png", "40×30 px", "SLB_TUI_IMAGES"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("expected %q in image pane, got:\n%s", want, view)
+		}
+	}
+}
+
+func TestDetailModelLiveOutput(t *testing.T) {
+	req := testRequest()
+	req.Status = db.StatusExecuting
+	m := NewDetailModel(req, nil)
+	m.Update(tea.WindowSizeMsg{Width: 100, Height: 30})
+
+	titles := tabTitles(m)
+	if titles[len(titles)-1] != "Output" {
+		t.Fatalf("expected an Output tab while executing, got %v", titles)
+	}
+	m.selectTab(len(titles) - 1)
+
+	chunk := func(requestID string, seq int, data string) live.EventMsg {
+		return live.EventMsg{Event: daemon.Event{Type: daemon.EventExecutionOutput, Payload: map[string]any{
+			"request_id": requestID, "seq": float64(seq), "data": data,
+		}}}
+	}
+	if _, cmd := m.Update(chunk(req.ID, 1, "step 1 done\n")); cmd != nil {
+		t.Error("output chunks should not reload the request")
+	}
+	m.Update(chunk("REQ-OTHER", 1, "someone else's output\n"))
+	m.Update(chunk(req.ID, 3, "step 3 done\n"))
+
+	view := m.viewport.View()
+	for _, want := range []string{"step 1 done", "step 3 done", "1 output chunk(s) missed", "slb abort REQ-001"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("expected %q in output pane, got:\n%s", want, view)
+		}
+	}
+	if strings.Contains(view, "someone else's output") {
+		t.Error("output of another request was shown")
+	}
+
+	m.Update(live.EventMsg{Event: daemon.Event{Type: daemon.EventExecutionAborted, Payload: map[string]any{
+		"request_id": req.ID, "aborted_by": "Reviewer", "reason": "wrong cluster",
+	}}})
+	if !strings.Contains(m.viewport.View(), "aborted by Reviewer: wrong cluster") {
+		t.Errorf("expected abort note, got:\n%s", m.viewport.View())
+	}
+}
@@ -0,0 +1,463 @@
+package request
+
+import (
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/viewport"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/Dicklesworthstone/slb/internal/core"
+	"github.com/Dicklesworthstone/slb/internal/db"
+	"github.com/Dicklesworthstone/slb/internal/tui/components"
+	"github.com/Dicklesworthstone/slb/internal/tui/theme"
+)
+
+// splitMinWidth is the terminal width from which the overview and the
+// selected context pane are shown side by side.
+const splitMinWidth = 140
+
+// tabKind identifies what a detail tab shows.
+type tabKind int
+
+const (
+	tabOverview tabKind = iota
+	tabDryRun
+	tabAttachment
+	tabReviews
+	tabSimilar
+	tabOutput
+)
+
+// detailTab is one pane of the detail view.
+type detailTab struct {
+	kind tabKind
+	// attachment indexes Request.Attachments for tabAttachment.
+	attachment int
+	title      string
+}
+
+// tabs lists the panes for the current request: the overview, the dry run
+// (when present), one per attachment, reviews, similar requests and the
+// live output (once executing).
+func (m *DetailModel) tabs() []detailTab {
+	tabs := []detailTab{{kind: tabOverview, title: "Overview"}}
+	if m.Request.DryRun != nil && m.Request.DryRun.Output != "" {
+		tabs = append(tabs, detailTab{kind: tabDryRun, title: "Dry run"})
+	}
+	for i, att := range m.Request.Attachments {
+		tabs = append(tabs, detailTab{kind: tabAttachment, attachment: i, title: attachmentTitle(att)})
+	}
+	tabs = append(tabs,
+		detailTab{kind: tabReviews, title: fmt.Sprintf("Reviews (%d)", len(m.Reviews))},
+		detailTab{kind: tabSimilar, title: fmt.Sprintf("Similar (%d)", len(m.Similar))},
+	)
+	if m.hasOutput() {
+		tabs = append(tabs, detailTab{kind: tabOutput, title: "Output"})
+	}
+	return tabs
+}
+
+// tabIndex returns the position of a tab (-1 when absent).
+func (m *DetailModel) tabIndex(kind tabKind, attachment int) int {
+	for i, t := range m.tabs() {
+		if t.kind == kind && (kind != tabAttachment || t.attachment == attachment) {
+			return i
+		}
+	}
+	return -1
+}
+
+// split reports whether the overview and context panes are side by side.
+func (m *DetailModel) split() bool {
+	return m.Width >= splitMinWidth
+}
+
+// contextTab is the tab shown in the right pane in split mode: the active
+// tab, or the last context tab viewed while the overview is focused.
+func (m *DetailModel) contextTab() int {
+	if m.tab > 0 {
+		return m.tab
+	}
+	return m.sideTab
+}
+
+// selectTab activates tab i (clamped to the available tabs).
+func (m *DetailModel) selectTab(i int) {
+	n := len(m.tabs())
+	if i < 0 || i >= n {
+		return
+	}
+	if i == m.tab {
+		return
+	}
+	m.tab = i
+	if i > 0 {
+		m.sideTab = i
+	}
+	m.refresh()
+	m.scrollTarget().GotoTop()
+}
+
+// cycleTab moves delta tabs forward or backward, wrapping around.
+func (m *DetailModel) cycleTab(delta int) {
+	n := len(m.tabs())
+	m.selectTab(((m.tab+delta)%n + n) % n)
+}
+
+// scrollTarget is the viewport that scrolling keys apply to.
+func (m *DetailModel) scrollTarget() *viewport.Model {
+	if m.split() && m.tab > 0 {
+		return &m.side
+	}
+	return &m.viewport
+}
+
+// paneWidths returns the widths of the main and side panes. The side pane is
+// zero when the view is not split.
+func (m *DetailModel) paneWidths() (main, side int) {
+	if !m.split() {
+		return m.Width, 0
+	}
+	main = (m.Width - 1) / 2
+	return main, m.Width - main - 1
+}
+
+// layout sizes the viewports and refreshes their content.
+func (m *DetailModel) layout() {
+	height := m.Height - 5 // header, tab bar, footer
+	if height < 1 {
+		height = 1
+	}
+	mainW, sideW := m.paneWidths()
+	if !m.ready {
+		m.viewport = viewport.New(mainW, height)
+		m.side = viewport.New(sideW, height)
+		m.ready = true
+	} else {
+		m.viewport.Width, m.viewport.Height = mainW, height
+		m.side.Width, m.side.Height = sideW, height
+	}
+	m.refresh()
+}
+
+// refresh re-renders the content of the visible panes.
+func (m *DetailModel) refresh() {
+	if !m.ready {
+		return
+	}
+	tabs := m.tabs()
+	if m.tab >= len(tabs) {
+		m.tab = 0
+	}
+	if m.sideTab <= 0 || m.sideTab >= len(tabs) {
+		m.sideTab = 1
+	}
+	mainW, sideW := m.paneWidths()
+	if m.split() {
+		m.viewport.SetContent(m.renderContent())
+		m.side.SetContent(m.renderTab(tabs[m.contextTab()], sideW))
+		return
+	}
+	m.viewport.SetContent(m.renderTab(tabs[m.tab], mainW))
+}
+
+// renderPanes renders the visible pane(s).
+func (m *DetailModel) renderPanes() string {
+	if !m.split() {
+		return m.viewport.View()
+	}
+	th := theme.Current
+	sep := lipgloss.NewStyle().Foreground(th.Overlay0).
+		Render(strings.TrimSuffix(strings.Repeat("│\n", m.viewport.Height), "\n"))
+	return lipgloss.JoinHorizontal(lipgloss.Top, m.viewport.View(), sep, m.side.View())
+}
+
+// renderTabBar renders the numbered tab strip.
+func (m *DetailModel) renderTabBar() string {
+	th := theme.Current
+	active := lipgloss.NewStyle().Foreground(th.Base).Background(th.Mauve).Bold(true).Padding(0, 1)
+	shown := lipgloss.NewStyle().Foreground(th.Mauve).Bold(true).Padding(0, 1)
+	inactive := lipgloss.NewStyle().Foreground(th.Subtext).Padding(0, 1)
+
+	var parts []string
+	for i, t := range m.tabs() {
+		label := t.title
+		if i < 9 {
+			label = strconv.Itoa(i+1) + " " + label
+		}
+		switch {
+		case i == m.tab:
+			parts = append(parts, active.Render(label))
+		case m.split() && m.tab == 0 && i == m.sideTab:
+			parts = append(parts, shown.Render(label))
+		default:
+			parts = append(parts, inactive.Render(label))
+		}
+	}
+	return lipgloss.NewStyle().MaxWidth(m.Width).Render(strings.Join(parts, " "))
+}
+
+// renderTab renders the content of one tab at the given width.
+func (m *DetailModel) renderTab(t detailTab, width int) string {
+	switch t.kind {
+	case tabDryRun:
+		return m.renderDryRunPane(width)
+	case tabAttachment:
+		return m.renderAttachmentPane(t.attachment, width)
+	case tabReviews:
+		return m.renderReviewsPane()
+	case tabSimilar:
+		return m.renderSimilarPane()
+	case tabOutput:
+		return m.renderOutputPane(width)
+	default:
+		return m.renderContent()
+	}
+}
+
+// renderDryRunPane shows the complete dry run output.
+func (m *DetailModel) renderDryRunPane(width int) string {
+	th := theme.Current
+	title := lipgloss.NewStyle().Foreground(th.Blue).Bold(true).Render("Dry Run Output")
+	if m.Request.DryRun == nil {
+		return title + "\n" + lipgloss.NewStyle().Foreground(th.Subtext).Render("No dry run was recorded.")
+	}
+	cmdStyle := lipgloss.NewStyle().Foreground(th.Subtext).Italic(true)
+	outputStyle := lipgloss.NewStyle().Foreground(th.Text).Width(maxInt(20, width-2))
+	return title + "\n" +
+		cmdStyle.Render("$ "+m.Request.DryRun.Command) + "\n\n" +
+		outputStyle.Render(strings.TrimRight(m.Request.DryRun.Output, "\n"))
+}
+
+// renderAttachmentPane shows one attachment in full: highlighted diffs and
+// files, command output, or image metadata with an inline preview.
+func (m *DetailModel) renderAttachmentPane(i, width int) string {
+	th := theme.Current
+	att := m.Request.Attachments[i]
+	subStyle := lipgloss.NewStyle().Foreground(th.Subtext)
+
+	title := lipgloss.NewStyle().Foreground(th.Blue).Bold(true).
+		Render(fmt.Sprintf("%s Attachment %d: %s", attachmentIcon(string(att.Type)), i+1, attachmentTitle(att)))
+	badge := lipgloss.NewStyle().Foreground(th.Peach).Render(string(att.Type))
+
+	var meta []string
+	for _, k := range []string{"source", "file", "ref", "lines", "size", "exit_code", "duration_ms"} {
+		if v, ok := att.Metadata[k]; ok && fmt.Sprint(v) != "" {
+			meta = append(meta, fmt.Sprintf("%s: %v", strings.ReplaceAll(k, "_", " "), v))
+		}
+	}
+	if truncated, _ := att.Metadata["truncated"].(bool); truncated {
+		meta = append(meta, "truncated")
+	}
+	header := title + "\n" + badge
+	if len(meta) > 0 {
+		header += "  " + subStyle.Render(strings.Join(meta, " • "))
+	}
+
+	var body string
+	switch att.Type {
+	case db.AttachmentTypeGitDiff:
+		body = highlightDiff(att.Content)
+	case db.AttachmentTypeFile:
+		body = highlightCode(att.Content, languageFor(attachmentFilename(att)), attachmentFirstLine(att))
+	case db.AttachmentTypeScreenshot:
+		body = m.renderImage(att, width)
+	default:
+		body = lipgloss.NewStyle().Foreground(th.Text).Render(strings.TrimRight(att.Content, "\n"))
+	}
+	return header + "\n\n" + body
+}
+
+// renderImage shows image metadata and, where the terminal supports it, the
+// image itself.
+func (m *DetailModel) renderImage(att db.Attachment, width int) string {
+	th := theme.Current
+	labelStyle := lipgloss.NewStyle().Foreground(th.Subtext).Width(12)
+	valueStyle := lipgloss.NewStyle().Foreground(th.Text)
+
+	info, data, err := inspectImage(att.Content)
+	if err != nil && data == nil {
+		return lipgloss.NewStyle().Foreground(th.Red).Render("Cannot display image: " + err.Error())
+	}
+
+	lines := []string{
+		labelStyle.Render("Format:") + valueStyle.Render(nonEmpty(info.Format, info.Mime)),
+		labelStyle.Render("Size:") + valueStyle.Render(formatBytes(info.Bytes)),
+	}
+	if info.Width > 0 {
+		lines = append(lines, labelStyle.Render("Dimensions:")+valueStyle.Render(fmt.Sprintf("%d×%d px", info.Width, info.Height)))
+	}
+	if desc, _ := att.Metadata["description"].(string); desc != "" {
+		lines = append(lines, labelStyle.Render("Description:")+valueStyle.Render(desc))
+	}
+	out := strings.Join(lines, "\n")
+	if err != nil {
+		return out + "\n\n" + lipgloss.NewStyle().Foreground(th.Red).Render(err.Error())
+	}
+
+	if inline := renderInlineImage(m.images, info, data, width-2); inline != "" {
+		return out + "\n\n" + inline
+	}
+	return out + "\n\n" + lipgloss.NewStyle().Foreground(th.Overlay1).Italic(true).
+		Render("Inline preview needs a kitty or sixel capable terminal (override with SLB_TUI_IMAGES=kitty|sixel).")
+}
+
+// renderReviewsPane shows every review with its structured responses.
+func (m *DetailModel) renderReviewsPane() string {
+	th := theme.Current
+	if len(m.Reviews) == 0 {
+		title := lipgloss.NewStyle().Foreground(th.Blue).Bold(true).
+			Render(fmt.Sprintf("Reviews (0/%d required)", m.Request.MinApprovals))
+		return title + "\n" + lipgloss.NewStyle().Foreground(th.Subtext).Render("No reviews yet.")
+	}
+
+	out := m.renderReviews()
+	labelStyle := lipgloss.NewStyle().Foreground(th.Subtext)
+	var details []string
+	for _, rev := range m.Reviews {
+		var lines []string
+		if rev.ReviewerModel != "" {
+			lines = append(lines, labelStyle.Render("model: ")+rev.ReviewerModel)
+		}
+		for _, r := range []struct{ label, value string }{
+			{"reason", rev.Responses.ReasonResponse},
+			{"effect", rev.Responses.EffectResponse},
+			{"goal", rev.Responses.GoalResponse},
+			{"safety", rev.Responses.SafetyResponse},
+		} {
+			if r.value != "" {
+				lines = append(lines, labelStyle.Render(r.label+": ")+r.value)
+			}
+		}
+		if len(lines) > 0 {
+			details = append(details, lipgloss.NewStyle().Bold(true).Render(rev.ReviewerAgent)+"\n  "+strings.Join(lines, "\n  "))
+		}
+	}
+	if len(details) > 0 {
+		out += "\n\n" + strings.Join(details, "\n")
+	}
+	return out
+}
+
+// renderSimilarPane lists earlier requests with the same command shape,
+// their decisions and how they turned out.
+func (m *DetailModel) renderSimilarPane() string {
+	th := theme.Current
+	title := lipgloss.NewStyle().Foreground(th.Blue).Bold(true).
+		Render(fmt.Sprintf("Similar Requests (%d)", len(m.Similar)))
+	subStyle := lipgloss.NewStyle().Foreground(th.Subtext)
+	if len(m.Similar) == 0 {
+		return title + "\n" + subStyle.Render("No earlier requests with the same program, subcommand or target.")
+	}
+
+	summary := core.SummarizeSimilar(m.Similar)
+	summaryStyle := subStyle
+	if summary.Problems > 0 || summary.Failed > 0 {
+		summaryStyle = lipgloss.NewStyle().Foreground(th.Red).Bold(true)
+	}
+
+	var blocks []string
+	for _, s := range m.Similar {
+		line := fmt.Sprintf("%s %s  %s  %s  %s",
+			components.RenderStatusBadge(string(s.Status)),
+			lipgloss.NewStyle().Foreground(th.Mauve).Render(s.RequestID),
+			lipgloss.NewStyle().Foreground(th.Peach).Render(string(s.Match)),
+			lipgloss.NewStyle().Foreground(th.Text).Render(s.RequestorAgent),
+			subStyle.Render(formatTimeAgo(s.CreatedAt)),
+		)
+		if s.ExitCode != nil {
+			color := th.Green
+			if *s.ExitCode != 0 {
+				color = th.Red
+			}
+			line += "  " + lipgloss.NewStyle().Foreground(color).Render(fmt.Sprintf("exit %d", *s.ExitCode))
+		}
+		line += "\n   " + subStyle.Render("$ "+s.Command)
+		for _, d := range s.Decisions {
+			decision := lipgloss.NewStyle().Foreground(th.Green).Render(string(d.Decision))
+			if d.Decision == db.DecisionReject {
+				decision = lipgloss.NewStyle().Foreground(th.Red).Render(string(d.Decision))
+			}
+			entry := "\n   " + decision + " by " + d.ReviewerAgent
+			if d.Comments != "" {
+				entry += subStyle.Italic(true).Render(": " + d.Comments)
+			}
+			line += entry
+		}
+		if s.CausedProblems {
+			problem := "caused problems"
+			if s.ProblemDescription != "" {
+				problem += ": " + s.ProblemDescription
+			}
+			line += "\n   " + lipgloss.NewStyle().Foreground(th.Red).Bold(true).Render("⚠ "+problem)
+		}
+		blocks = append(blocks, line)
+	}
+	return title + "\n" + summaryStyle.Render(summary.String()) + "\n\n" + strings.Join(blocks, "\n\n")
+}
+
+// attachmentTitle is the short label for an attachment tab.
+func attachmentTitle(att db.Attachment) string {
+	if name := attachmentFilename(att); name != "" {
+		return name
+	}
+	switch att.Type {
+	case db.AttachmentTypeGitDiff:
+		if ref, _ := att.Metadata["ref"].(string); ref != "" {
+			return "diff " + ref
+		}
+		return "diff"
+	case db.AttachmentTypeContext:
+		return "context"
+	case db.AttachmentTypeScreenshot:
+		return "image"
+	default:
+		return string(att.Type)
+	}
+}
+
+// attachmentFilename is the base name of the attached file, if any.
+func attachmentFilename(att db.Attachment) string {
+	if name, _ := att.Metadata["filename"].(string); name != "" {
+		return name
+	}
+	if path, _ := att.Metadata["file"].(string); path != "" {
+		return filepath.Base(path)
+	}
+	return ""
+}
+
+// attachmentFirstLine is the line number of the first line of a file
+// attachment (log excerpts record their range as "start-end").
+func attachmentFirstLine(att db.Attachment) int {
+	lines, _ := att.Metadata["lines"].(string)
+	start, _, _ := strings.Cut(lines, "-")
+	n, err := strconv.Atoi(start)
+	if err != nil || n < 1 {
+		return 1
+	}
+	return n
+}
+
+func formatBytes(n int) string {
+	switch {
+	case n >= 1<<20:
+		return fmt.Sprintf("%.1f MB", float64(n)/(1<<20))
+	case n >= 1<<10:
+		return fmt.Sprintf("%.1f KB", float64(n)/(1<<10))
+	default:
+		return fmt.Sprintf("%d B", n)
+	}
+}
+
+func nonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
@@ -3,15 +3,20 @@
 package tui
 
 import (
+	"context"
+	"fmt"
 	"os"
 	"path/filepath"
 	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 
+	"github.com/Dicklesworthstone/slb/internal/core"
+	"github.com/Dicklesworthstone/slb/internal/daemon"
 	"github.com/Dicklesworthstone/slb/internal/db"
 	"github.com/Dicklesworthstone/slb/internal/tui/dashboard"
 	"github.com/Dicklesworthstone/slb/internal/tui/history"
+	"github.com/Dicklesworthstone/slb/internal/tui/live"
 	"github.com/Dicklesworthstone/slb/internal/tui/patterns"
 	"github.com/Dicklesworthstone/slb/internal/tui/request"
 	"github.com/Dicklesworthstone/slb/internal/tui/theme"
@@ -25,6 +30,7 @@ const (
 	ViewRequestDetail
 	ViewHistory
 	ViewPatterns
+	ViewBatchReview
 )
 
 // Options configures the TUI behavior.
@@ -35,6 +41,16 @@ type Options struct {
 	RefreshInterval int
 	SessionID       string
 	SessionKey      string
+	// Bell rings the terminal bell when a new CRITICAL request arrives.
+	Bell bool
+	// Projects lists additional projects shown alongside ProjectPath.
+	Projects []string
+	// Discover adds projects seen in daemon events to the view.
+	Discover bool
+	// SavedQueries are the history queries available as @name.
+	SavedQueries map[string]string
+	// Reputation scores agents on the dashboard; nil uses the defaults.
+	Reputation *core.ReputationConfig
 }
 
 // DefaultOptions returns the default TUI options.
@@ -62,9 +78,19 @@ type Model struct {
 	detail    *request.DetailModel
 	history   history.Model
 	patterns  patterns.Model
+	batch     *request.BatchModel
 
 	// Navigation state
 	selectedRequestID string
+	selectedProject   string
+
+	// projects shown in the dashboard: ProjectPath, Options.Projects and
+	// any discovered from daemon events.
+	projects []string
+
+	// Daemon event stream shared by all views (nil when not streaming).
+	stream *live.Stream
+	conn   live.State
 }
 
 // New creates a new TUI model with options.
@@ -79,21 +105,43 @@ func NewWithOptions(opts Options) Model {
 		theme.SetTheme(theme.FlavorName(opts.Theme))
 	}
 
-	// Create dashboard model
-	dash := dashboard.New(opts.ProjectPath)
+	m := Model{
+		options:  opts,
+		view:     ViewDashboard,
+		history:  history.New(opts.ProjectPath).WithSavedQueries(opts.SavedQueries),
+		patterns: patterns.New(opts.ProjectPath),
+		projects: dedupePaths(append([]string{opts.ProjectPath}, opts.Projects...)),
+	}
+	m.dashboard = m.newDashboard()
+	return m
+}
 
-	return Model{
-		options:   opts,
-		view:      ViewDashboard,
-		dashboard: &dash,
-		history:   history.New(opts.ProjectPath),
-		patterns:  patterns.New(opts.ProjectPath),
+// WithStream attaches a daemon event stream. The caller starts and stops it.
+func (m Model) WithStream(s *live.Stream) Model {
+	m.stream = s
+	return m
+}
+
+// newDashboard creates a dashboard configured from the options and current
+// stream state.
+func (m Model) newDashboard() *dashboard.Model {
+	dash := dashboard.New(m.options.ProjectPath).
+		WithRefreshInterval(time.Duration(m.options.RefreshInterval) * time.Second).
+		WithBell(m.options.Bell).
+		WithConnection(m.conn).
+		WithProjects(m.projects)
+	if m.options.Reputation != nil {
+		dash = dash.WithReputation(*m.options.Reputation)
 	}
+	return &dash
 }
 
 // Init implements tea.Model.
 func (m Model) Init() tea.Cmd {
-	// Initialize the current view
+	return tea.Batch(m.initView(), m.stream.Next())
+}
+
+func (m Model) initView() tea.Cmd {
 	switch m.view {
 	case ViewDashboard:
 		if m.dashboard != nil {
@@ -107,6 +155,10 @@ func (m Model) Init() tea.Cmd {
 		if m.detail != nil {
 			return m.detail.Init()
 		}
+	case ViewBatchReview:
+		if m.batch != nil {
+			return m.batch.Init()
+		}
 	}
 	return nil
 }
@@ -115,6 +167,8 @@ func (m Model) Init() tea.Cmd {
 type navigateMsg struct {
 	view      View
 	requestID string
+	// project holds the request's project; empty means Options.ProjectPath.
+	project string
 }
 
 // Update implements tea.Model.
@@ -129,6 +183,19 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case navigateMsg:
 		return m.handleNavigation(msg)
 
+	case dashboard.ReviewSelectionMsg:
+		return m.openBatchReview(msg)
+
+	case live.StatusMsg:
+		m.conn = msg.State
+		next, cmd := m.forwardUpdate(msg)
+		return next, tea.Batch(cmd, m.stream.Next())
+
+	case live.EventMsg:
+		discovered := m.discoverProject(msg)
+		next, cmd := m.forwardUpdate(msg)
+		return next, tea.Batch(cmd, discovered, m.stream.Next())
+
 	case tea.KeyMsg:
 		// Handle global navigation keys based on current view
 		if m.view == ViewDashboard {
@@ -145,6 +212,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					return m.handleNavigation(navigateMsg{
 						view:      ViewRequestDetail,
 						requestID: m.dashboard.SelectedRequestID(),
+						project:   m.dashboard.SelectedRequestProject(),
 					})
 				}
 			}
@@ -218,6 +286,15 @@ func (m Model) forwardUpdate(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.patterns = pm
 		}
 		cmd = c
+
+	case ViewBatchReview:
+		if m.batch != nil {
+			next, c := m.batch.Update(msg)
+			if bm, ok := next.(*request.BatchModel); ok {
+				m.batch = bm
+			}
+			cmd = c
+		}
 	}
 
 	return m, cmd
@@ -229,18 +306,18 @@ func (m Model) handleNavigation(nav navigateMsg) (tea.Model, tea.Cmd) {
 
 	switch nav.view {
 	case ViewDashboard:
-		dash := dashboard.New(m.options.ProjectPath)
-		m.dashboard = &dash
+		m.dashboard = m.newDashboard()
 		m.setupDashboardCallbacks()
 		return m, m.dashboard.Init()
 
 	case ViewRequestDetail:
 		if nav.requestID != "" {
 			m.selectedRequestID = nav.requestID
+			m.selectedProject = nav.project
 			// Load the request and create detail view
 			detail := m.loadRequestDetail(nav.requestID)
 			if detail != nil {
-				m.detail = detail
+				m.detail = detail.WithConnection(m.conn)
 				m.setupDetailCallbacks()
 				return m, m.detail.Init()
 			}
@@ -250,7 +327,7 @@ func (m Model) handleNavigation(nav navigateMsg) (tea.Model, tea.Cmd) {
 		return m, nil
 
 	case ViewHistory:
-		m.history = history.New(m.options.ProjectPath)
+		m.history = history.New(m.options.ProjectPath).WithSavedQueries(m.options.SavedQueries).WithConnection(m.conn)
 		m.setupHistoryCallbacks()
 		return m, m.history.Init()
 
@@ -311,9 +388,18 @@ func (m *Model) setupPatternsCallbacks() {
 	}
 }
 
+// requestProject returns the project of the selected request.
+func (m *Model) requestProject() string {
+	if m.selectedProject != "" {
+		return m.selectedProject
+	}
+	return m.options.ProjectPath
+}
+
 // loadRequestDetail loads a request and creates a detail model.
 func (m *Model) loadRequestDetail(requestID string) *request.DetailModel {
-	dbPath := filepath.Join(m.options.ProjectPath, ".slb", "state.db")
+	project := m.requestProject()
+	dbPath := filepath.Join(project, ".slb", "state.db")
 	dbConn, err := db.OpenWithOptions(dbPath, db.OpenOptions{
 		CreateIfNotExists: false,
 		InitSchema:        false,
@@ -324,13 +410,7 @@ func (m *Model) loadRequestDetail(requestID string) *request.DetailModel {
 	}
 	defer dbConn.Close()
 
-	var currentSession *db.Session
-	if m.options.SessionID != "" {
-		s, err := dbConn.GetSession(m.options.SessionID)
-		if err == nil {
-			currentSession = s
-		}
-	}
+	currentSession, _, _ := m.sessionFor(dbConn, project)
 
 	req, err := dbConn.GetRequest(requestID)
 	if err != nil {
@@ -347,7 +427,9 @@ func (m *Model) loadRequestDetail(requestID string) *request.DetailModel {
 		}
 	}
 
-	detail := request.NewDetailModel(req, reviews)
+	similar, _ := core.FindSimilarRequests(dbConn, req, request.SimilarLimit)
+
+	detail := request.NewDetailModel(req, reviews).WithSimilar(similar)
 	if currentSession != nil {
 		detail.WithSession(currentSession)
 	}
@@ -356,12 +438,23 @@ func (m *Model) loadRequestDetail(requestID string) *request.DetailModel {
 
 // approveRequest creates a command to approve a request.
 func (m *Model) approveRequest(requestID string, comments string) tea.Cmd {
+	return m.submitReview(m.requestProject(), requestID, db.DecisionApprove, comments)
+}
+
+// rejectRequest creates a command to reject a request.
+func (m *Model) rejectRequest(requestID string, reason string) tea.Cmd {
+	return m.submitReview(m.requestProject(), requestID, db.DecisionReject, reason)
+}
+
+// submitReview creates a command that records a review in the request's
+// project, signed with the reviewer's session for that project.
+func (m *Model) submitReview(project, requestID string, decision db.Decision, comments string) tea.Cmd {
 	return func() tea.Msg {
 		if m.options.SessionID == "" || m.options.SessionKey == "" {
-			return nil // Cannot approve without session
+			return nil // Cannot review without session
 		}
 
-		dbPath := filepath.Join(m.options.ProjectPath, ".slb", "state.db")
+		dbPath := filepath.Join(project, ".slb", "state.db")
 		dbConn, err := db.OpenWithOptions(dbPath, db.OpenOptions{
 			CreateIfNotExists: false,
 			InitSchema:        false, // Schema should exist
@@ -372,75 +465,221 @@ func (m *Model) approveRequest(requestID string, comments string) tea.Cmd {
 		}
 		defer dbConn.Close()
 
-		// Get session to populate reviewer info
-		session, err := dbConn.GetSession(m.options.SessionID)
+		session, key, err := m.sessionFor(dbConn, project)
 		if err != nil {
 			return nil
 		}
 
-		now := time.Now().UTC()
-		review := &db.Review{
-			RequestID:          requestID,
-			ReviewerSessionID:  session.ID,
-			ReviewerAgent:      session.AgentName,
-			ReviewerModel:      session.Model,
-			Decision:           db.DecisionApprove,
-			Comments:           comments,
-			SignatureTimestamp: now,
-		}
+		reviewSvc := core.NewReviewService(dbConn, core.DefaultReviewConfig())
+		reviewSvc.SetNotifier(daemon.NewEventNotifier(daemon.DefaultSocketPath()))
+		// Errors surface through the request's state on the next refresh.
+		_, _ = reviewSvc.SubmitReview(core.ReviewOptions{
+			SessionID:  session.ID,
+			SessionKey: key,
+			RequestID:  requestID,
+			Decision:   decision,
+			Comments:   comments,
+		})
 
-		// Compute signature
-		review.Signature = db.ComputeReviewSignature(m.options.SessionKey, requestID, db.DecisionApprove, now)
+		return navigateMsg{view: ViewDashboard}
+	}
+}
 
-		if err := dbConn.CreateReviewWithValidation(review, m.options.SessionKey); err != nil {
-			// In a real app we'd send an error msg, but for now just log/ignore or return to dash
-			// Ideally we return an error message tea.Msg
-		}
+// sessionFor resolves the reviewer session used in project. Sessions belong to
+// one project, so for other projects the active session of the same agent is
+// used, with the key stored in that project's database.
+func (m *Model) sessionFor(dbConn *db.DB, project string) (*db.Session, string, error) {
+	if m.options.SessionID == "" {
+		return nil, "", fmt.Errorf("no session configured")
+	}
+	if s, err := dbConn.GetSession(m.options.SessionID); err == nil {
+		return s, m.options.SessionKey, nil
+	}
+	agent, err := m.sessionAgent()
+	if err != nil {
+		return nil, "", err
+	}
+	s, err := dbConn.GetActiveSession(agent, project)
+	if err != nil {
+		return nil, "", fmt.Errorf("no active session for %s in %s: %w", agent, project, err)
+	}
+	return s, s.SessionKey, nil
+}
 
-		return navigateMsg{view: ViewDashboard}
+// sessionAgent returns the agent name of the configured session, looked up in
+// the primary project.
+func (m *Model) sessionAgent() (string, error) {
+	dbConn, err := db.OpenWithOptions(filepath.Join(m.options.ProjectPath, ".slb", "state.db"), db.OpenOptions{
+		ReadOnly: true,
+	})
+	if err != nil {
+		return "", err
 	}
+	defer dbConn.Close()
+	s, err := dbConn.GetSession(m.options.SessionID)
+	if err != nil {
+		return "", err
+	}
+	if s.SessionKey != m.options.SessionKey {
+		return "", fmt.Errorf("session key mismatch")
+	}
+	return s.AgentName, nil
 }
 
-// rejectRequest creates a command to reject a request.
-func (m *Model) rejectRequest(requestID string, reason string) tea.Cmd {
-	return func() tea.Msg {
-		if m.options.SessionID == "" || m.options.SessionKey == "" {
-			return nil
+// openBatchReview shows the confirmation screen for a dashboard selection.
+// Requests that are no longer pending are left out.
+func (m Model) openBatchReview(msg dashboard.ReviewSelectionMsg) (tea.Model, tea.Cmd) {
+	var requests []*db.Request
+	byProject := make(map[string][]string)
+	var order []string
+	for _, sel := range msg.Requests {
+		if _, ok := byProject[sel.Project]; !ok {
+			order = append(order, sel.Project)
 		}
-
-		dbPath := filepath.Join(m.options.ProjectPath, ".slb", "state.db")
-		dbConn, err := db.OpenWithOptions(dbPath, db.OpenOptions{
-			CreateIfNotExists: false,
-			InitSchema:        false,
-			ReadOnly:          false,
-		})
+		byProject[sel.Project] = append(byProject[sel.Project], sel.ID)
+	}
+	for _, project := range order {
+		dbConn, err := db.OpenWithOptions(filepath.Join(project, ".slb", "state.db"), db.OpenOptions{ReadOnly: true})
 		if err != nil {
-			return nil
+			continue
 		}
-		defer dbConn.Close()
+		for _, id := range byProject[project] {
+			if r, err := dbConn.GetRequest(id); err == nil && r.Status == db.StatusPending {
+				requests = append(requests, r)
+			}
+		}
+		dbConn.Close()
+	}
+	if len(requests) == 0 {
+		return m, nil
+	}
 
-		session, err := dbConn.GetSession(m.options.SessionID)
-		if err != nil {
-			return nil
+	m.batch = request.NewBatchModel(requests, msg.Decision)
+	m.batch.OnConfirm = func(decision db.Decision, reqs []*db.Request, comments string) tea.Cmd {
+		return m.submitBatch(decision, reqs, comments)
+	}
+	m.batch.OnDone = func() tea.Cmd {
+		return func() tea.Msg { return navigateMsg{view: ViewDashboard} }
+	}
+	m.view = ViewBatchReview
+	if m.width > 0 {
+		m.batch.Update(tea.WindowSizeMsg{Width: m.width, Height: m.height})
+	}
+	return m, m.batch.Init()
+}
+
+// submitBatch reviews each request individually through ReviewService, in its
+// own project and signed with the reviewer's session for that project.
+func (m *Model) submitBatch(decision db.Decision, requests []*db.Request, comments string) tea.Cmd {
+	mm := *m
+	return func() tea.Msg {
+		results := make([]request.BatchResult, 0, len(requests))
+		fail := func(r *db.Request, err error) {
+			results = append(results, request.BatchResult{RequestID: r.ID, Err: err})
+		}
+		if mm.options.SessionID == "" || mm.options.SessionKey == "" {
+			for _, r := range requests {
+				fail(r, fmt.Errorf("no session configured (use --session-id and --session-key)"))
+			}
+			return request.BatchResultMsg{Results: results}
 		}
 
-		now := time.Now().UTC()
-		review := &db.Review{
-			RequestID:          requestID,
-			ReviewerSessionID:  session.ID,
-			ReviewerAgent:      session.AgentName,
-			ReviewerModel:      session.Model,
-			Decision:           db.DecisionReject,
-			Comments:           reason,
-			SignatureTimestamp: now,
+		byProject := make(map[string][]*db.Request)
+		var order []string
+		for _, r := range requests {
+			if _, ok := byProject[r.ProjectPath]; !ok {
+				order = append(order, r.ProjectPath)
+			}
+			byProject[r.ProjectPath] = append(byProject[r.ProjectPath], r)
 		}
 
-		review.Signature = db.ComputeReviewSignature(m.options.SessionKey, requestID, db.DecisionReject, now)
+		for _, project := range order {
+			reqs := byProject[project]
+			dbConn, err := db.OpenWithOptions(filepath.Join(project, ".slb", "state.db"), db.OpenOptions{})
+			if err != nil {
+				for _, r := range reqs {
+					fail(r, err)
+				}
+				continue
+			}
+			session, key, err := mm.sessionFor(dbConn, project)
+			if err != nil {
+				for _, r := range reqs {
+					fail(r, err)
+				}
+				dbConn.Close()
+				continue
+			}
+
+			ids := make([]string, len(reqs))
+			for i, r := range reqs {
+				ids[i] = r.ID
+			}
+			reviewSvc := core.NewReviewService(dbConn, core.DefaultReviewConfig())
+			reviewSvc.SetNotifier(daemon.NewEventNotifier(daemon.DefaultSocketPath()))
+			for _, res := range reviewSvc.SubmitBatch(core.ReviewOptions{
+				SessionID:  session.ID,
+				SessionKey: key,
+				Decision:   decision,
+				Comments:   comments,
+			}, ids) {
+				br := request.BatchResult{RequestID: res.RequestID, Err: res.Err}
+				if res.Err == nil && res.Result.RequestStatusChanged {
+					br.NewStatus = res.Result.NewRequestStatus
+				}
+				results = append(results, br)
+			}
+			dbConn.Close()
+		}
+		return request.BatchResultMsg{Results: results}
+	}
+}
 
-		_ = dbConn.CreateReviewWithValidation(review, m.options.SessionKey)
+// discoverProject adds the project of a daemon event to the view when
+// discovery is enabled and the project has an SLB database.
+func (m *Model) discoverProject(msg live.EventMsg) tea.Cmd {
+	if !m.options.Discover {
+		return nil
+	}
+	payload, ok := msg.Event.Payload.(map[string]any)
+	if !ok {
+		return nil
+	}
+	project, _ := payload["project_path"].(string)
+	if project == "" {
+		return nil
+	}
+	for _, p := range m.projects {
+		if p == project {
+			return nil
+		}
+	}
+	if _, err := os.Stat(filepath.Join(project, ".slb", "state.db")); err != nil {
+		return nil
+	}
+	m.projects = append(m.projects, project)
+	if m.dashboard == nil {
+		return nil
+	}
+	dash := m.dashboard.WithProjects(m.projects)
+	m.dashboard = &dash
+	if m.view != ViewDashboard {
+		return nil
+	}
+	return m.dashboard.Reload()
+}
 
-		return navigateMsg{view: ViewDashboard}
+func dedupePaths(paths []string) []string {
+	seen := make(map[string]bool, len(paths))
+	out := make([]string, 0, len(paths))
+	for _, p := range paths {
+		if p == "" || seen[p] {
+			continue
+		}
+		seen[p] = true
+		out = append(out, p)
 	}
+	return out
 }
 
 // View implements tea.Model.
@@ -458,6 +697,10 @@ func (m Model) View() string {
 		return m.history.View()
 	case ViewPatterns:
 		return m.patterns.View()
+	case ViewBatchReview:
+		if m.batch != nil {
+			return m.batch.View()
+		}
 	}
 	return "Loading..."
 }
@@ -474,7 +717,13 @@ func RunWithOptions(opts Options) error {
 		theme.SetTheme(theme.FlavorName(opts.Theme))
 	}
 
-	m := NewWithOptions(opts)
+	// Stream daemon events when the daemon is up; views poll while it is not.
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	stream := live.NewStream(live.DaemonSubscriber(daemon.DefaultSocketPath()))
+	stream.Start(ctx)
+
+	m := NewWithOptions(opts).WithStream(stream)
 
 	// Build program options
 	teaOpts := []tea.ProgramOption{tea.WithAltScreen()}
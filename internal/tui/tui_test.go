@@ -1,12 +1,17 @@
 package tui
 
 import (
+	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 
+	"github.com/Dicklesworthstone/slb/internal/daemon"
 	"github.com/Dicklesworthstone/slb/internal/db"
+	"github.com/Dicklesworthstone/slb/internal/tui/dashboard"
+	"github.com/Dicklesworthstone/slb/internal/tui/live"
 	"github.com/Dicklesworthstone/slb/internal/tui/request"
 )
 
@@ -609,3 +614,174 @@ func TestUpdateKeyPressOnDashboardNonNavigation(t *testing.T) {
 		t.Errorf("non-navigation key should keep dashboard view, got %d", um.view)
 	}
 }
+
+// ============== Multi-project Tests ==============
+
+func newProjectDB(t *testing.T) (string, *db.DB) {
+	t.Helper()
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, ".slb"), 0755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	database, err := db.OpenAndMigrate(filepath.Join(dir, ".slb", "state.db"))
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	t.Cleanup(func() { database.Close() })
+	return dir, database
+}
+
+func createProjectSession(t *testing.T, database *db.DB, project, agent, model string) *db.Session {
+	t.Helper()
+	s := &db.Session{AgentName: agent, Program: "test", Model: model, ProjectPath: project}
+	if err := database.CreateSession(s); err != nil {
+		t.Fatalf("create session: %v", err)
+	}
+	return s
+}
+
+func TestSubmitReviewUsesProjectSession(t *testing.T) {
+	t.Setenv("SLB_HOST", "")
+	primary, primaryDB := newProjectDB(t)
+	other, otherDB := newProjectDB(t)
+
+	reviewer := createProjectSession(t, primaryDB, primary, "Reviewer", "model-a")
+	otherReviewer := createProjectSession(t, otherDB, other, "Reviewer", "model-a")
+	requestor := createProjectSession(t, otherDB, other, "Requestor", "model-b")
+
+	req := &db.Request{
+		ProjectPath:        other,
+		Command:            db.CommandSpec{Raw: "make deploy", Cwd: other},
+		RiskTier:           db.RiskTierDangerous,
+		RequestorSessionID: requestor.ID,
+		RequestorAgent:     requestor.AgentName,
+		RequestorModel:     requestor.Model,
+		Justification:      db.Justification{Reason: "ship it"},
+		Status:             db.StatusPending,
+		MinApprovals:       1,
+	}
+	if err := otherDB.CreateRequest(req); err != nil {
+		t.Fatalf("create request: %v", err)
+	}
+
+	m := NewWithOptions(Options{
+		ProjectPath: primary,
+		Projects:    []string{other},
+		SessionID:   reviewer.ID,
+		SessionKey:  reviewer.SessionKey,
+	})
+	m.selectedProject = other
+
+	detail := m.loadRequestDetail(req.ID)
+	if detail == nil || detail.Session == nil || detail.Session.ID != otherReviewer.ID {
+		t.Fatalf("expected detail bound to the reviewer's session in %s", other)
+	}
+
+	if msg := m.approveRequest(req.ID, "lgtm")(); msg == nil {
+		t.Fatal("expected navigation after review")
+	}
+	reviews, err := otherDB.ListReviewsForRequest(req.ID)
+	if err != nil || len(reviews) != 1 {
+		t.Fatalf("expected one review in the request's project, got %d (%v)", len(reviews), err)
+	}
+	if reviews[0].ReviewerSessionID != otherReviewer.ID {
+		t.Errorf("review signed by %s, want %s", reviews[0].ReviewerSessionID, otherReviewer.ID)
+	}
+
+	// A wrong key for the primary session must not unlock other projects.
+	m.options.SessionKey = "wrong"
+	if _, _, err := m.sessionFor(otherDB, other); err == nil {
+		t.Error("expected session key mismatch to be rejected")
+	}
+}
+
+func TestDiscoverProjectFromEvents(t *testing.T) {
+	primary, _ := newProjectDB(t)
+	other, _ := newProjectDB(t)
+
+	event := func(project string) live.EventMsg {
+		return live.EventMsg{Event: daemon.Event{
+			Type:    daemon.EventRequestPending,
+			Payload: map[string]any{"request_id": "r1", "project_path": project},
+		}}
+	}
+
+	m := NewWithOptions(Options{ProjectPath: primary})
+	updated, _ := m.Update(event(other))
+	if got := updated.(Model).projects; len(got) != 1 {
+		t.Fatalf("expected no discovery when disabled, got %v", got)
+	}
+
+	m = NewWithOptions(Options{ProjectPath: primary, Discover: true})
+	updated, _ = m.Update(event(other))
+	m = updated.(Model)
+	if len(m.projects) != 2 || m.projects[1] != other {
+		t.Fatalf("expected %s discovered, got %v", other, m.projects)
+	}
+	if got := m.dashboard.Projects(); len(got) != 2 {
+		t.Errorf("expected dashboard to show discovered project, got %v", got)
+	}
+
+	updated, _ = m.Update(event(filepath.Join(t.TempDir(), "no-slb")))
+	if got := updated.(Model).projects; len(got) != 2 {
+		t.Errorf("expected projects without a database to be ignored, got %v", got)
+	}
+}
+
+func TestBatchReviewFromDashboardSelection(t *testing.T) {
+	t.Setenv("SLB_HOST", "")
+	project, database := newProjectDB(t)
+	reviewer := createProjectSession(t, database, project, "Reviewer", "model-a")
+	requestor := createProjectSession(t, database, project, "BlueLake", "model-b")
+
+	var ids []string
+	for _, cmd := range []string{"rm -rf ./dist-a", "rm -rf ./dist-b"} {
+		req := &db.Request{
+			ProjectPath:        project,
+			Command:            db.CommandSpec{Raw: cmd, Cwd: project},
+			RiskTier:           db.RiskTierCaution,
+			RequestorSessionID: requestor.ID,
+			RequestorAgent:     requestor.AgentName,
+			RequestorModel:     requestor.Model,
+			Justification:      db.Justification{Reason: "cleanup"},
+			Status:             db.StatusPending,
+			MinApprovals:       1,
+		}
+		if err := database.CreateRequest(req); err != nil {
+			t.Fatalf("create request: %v", err)
+		}
+		ids = append(ids, req.ID)
+	}
+
+	m := NewWithOptions(Options{ProjectPath: project, SessionID: reviewer.ID, SessionKey: reviewer.SessionKey})
+	updated, _ := m.Update(dashboard.ReviewSelectionMsg{
+		Decision: db.DecisionApprove,
+		Requests: []dashboard.SelectedRequest{{ID: ids[0], Project: project}, {ID: ids[1], Project: project}, {ID: "gone", Project: project}},
+	})
+	m = updated.(Model)
+	if m.view != ViewBatchReview || m.batch == nil || len(m.batch.Requests) != 2 {
+		t.Fatalf("expected batch view with 2 pending requests, got view %d", m.view)
+	}
+
+	msg := m.submitBatch(db.DecisionApprove, m.batch.Requests, "lgtm")()
+	res, ok := msg.(request.BatchResultMsg)
+	if !ok || len(res.Results) != 2 {
+		t.Fatalf("unexpected batch result: %#v", msg)
+	}
+	for _, r := range res.Results {
+		if r.Err != nil || r.NewStatus != db.StatusApproved {
+			t.Errorf("%s: unexpected result %+v", r.RequestID, r)
+		}
+		reviews, _ := database.ListReviewsForRequest(r.RequestID)
+		if len(reviews) != 1 || reviews[0].ReviewerSessionID != reviewer.ID || reviews[0].Signature == "" {
+			t.Errorf("%s: expected one signed review, got %+v", r.RequestID, reviews)
+		}
+	}
+
+	// Without a session every review fails with an explanation.
+	m.options.SessionID = ""
+	res = m.submitBatch(db.DecisionApprove, m.batch.Requests, "")().(request.BatchResultMsg)
+	if len(res.Results) != 2 || res.Results[0].Err == nil {
+		t.Errorf("expected session errors, got %+v", res.Results)
+	}
+}
@@ -0,0 +1,67 @@
+package utils
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// maxProcessDepth bounds the ancestor walk.
+const maxProcessDepth = 16
+
+// ProcRoot is the procfs mount; a variable so tests can fake it.
+var ProcRoot = "/proc"
+
+// Process identifies a process. StartTime (clock ticks after boot)
+// disambiguates reused PIDs.
+type Process struct {
+	PID       int
+	Name      string
+	StartTime uint64
+	// Args is the command line. It may hold secrets, so it is only used for
+	// matching and never stored.
+	Args []string
+}
+
+// ProcessAncestors returns pid and its ancestors up to init, nearest first.
+// It reads procfs and returns nothing where procfs is unavailable.
+func ProcessAncestors(pid int) []Process {
+	var tree []Process
+	for depth := 0; pid > 1 && depth < maxProcessDepth; depth++ {
+		p, ppid, ok := readProcStat(pid)
+		if !ok {
+			break
+		}
+		if data, err := os.ReadFile(filepath.Join(ProcRoot, strconv.Itoa(pid), "cmdline")); err == nil {
+			p.Args = strings.Split(strings.TrimRight(string(data), "\x00"), "\x00")
+		}
+		tree = append(tree, p)
+		pid = ppid
+	}
+	return tree
+}
+
+// readProcStat parses /proc/<pid>/stat: "pid (comm) state ppid ..." with the
+// start time as the 22nd field. comm may itself contain parentheses.
+func readProcStat(pid int) (Process, int, bool) {
+	data, err := os.ReadFile(filepath.Join(ProcRoot, strconv.Itoa(pid), "stat"))
+	if err != nil {
+		return Process{}, 0, false
+	}
+	stat := string(data)
+	open, end := strings.IndexByte(stat, '('), strings.LastIndexByte(stat, ')')
+	if open < 0 || end < open {
+		return Process{}, 0, false
+	}
+	rest := strings.Fields(stat[end+1:])
+	if len(rest) < 20 {
+		return Process{}, 0, false
+	}
+	ppid, err := strconv.Atoi(rest[1])
+	if err != nil {
+		return Process{}, 0, false
+	}
+	start, _ := strconv.ParseUint(rest[19], 10, 64)
+	return Process{PID: pid, Name: stat[open+1 : end], StartTime: start}, ppid, true
+}
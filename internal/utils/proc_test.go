@@ -0,0 +1,45 @@
+package utils
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestProcessAncestors(t *testing.T) {
+	root := t.TempDir()
+	write := func(pid, file, content string) {
+		t.Helper()
+		if err := os.MkdirAll(filepath.Join(root, pid), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(root, pid, file), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// Fields after the name: state ppid pgrp session tty tpgid flags minflt
+	// cminflt majflt cmajflt utime stime cutime cstime priority nice
+	// threads itrealvalue starttime.
+	write("300", "stat", "300 (bash) S 200 0 0 0 0 0 0 0 0 0 0 0 0 0 20 0 1 0 5000 0")
+	write("200", "stat", "200 (claude (node)) S 100 0 0 0 0 0 0 0 0 0 0 0 0 0 20 0 1 0 4000 0")
+	write("200", "cmdline", "node\x00/usr/lib/claude/cli.js\x00")
+	write("100", "stat", "100 (tmux: server) S 1 0 0 0 0 0 0 0 0 0 0 0 0 0 20 0 1 0 10 0")
+
+	old := ProcRoot
+	ProcRoot = root
+	defer func() { ProcRoot = old }()
+
+	tree := ProcessAncestors(300)
+	if len(tree) != 3 {
+		t.Fatalf("tree = %+v", tree)
+	}
+	if tree[1].Name != "claude (node)" || tree[1].StartTime != 4000 || tree[2].PID != 100 {
+		t.Errorf("tree = %+v", tree)
+	}
+	if len(tree[1].Args) != 2 || tree[1].Args[1] != "/usr/lib/claude/cli.js" {
+		t.Errorf("args = %q", tree[1].Args)
+	}
+	if missing := ProcessAncestors(999); len(missing) != 0 {
+		t.Errorf("expected empty tree for unknown pid, got %+v", missing)
+	}
+}